@@ -0,0 +1,108 @@
+package pdf
+
+import "strings"
+
+// windows1252 maps the characters of the Windows-1252 (WinAnsi) code page that differ from Latin-1
+var windows1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A,
+	'‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// substitutes spell out the currency symbols the standard fonts have no glyph for
+var substitutes = map[rune]string{
+	'₹': "Rs.",
+	'₽': "RUB ",
+	'₩': "KRW ",
+	'₺': "TRY ",
+}
+
+// encode converts text to Windows-1252, the encoding of the standard fonts. Currency symbols outside it are
+// spelled out and any other character is replaced by a question mark.
+func encode(text string) string {
+	var builder strings.Builder
+	for _, r := range text {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			builder.WriteByte(byte(r))
+		case windows1252[r] != 0:
+			builder.WriteByte(windows1252[r])
+		case substitutes[r] != "":
+			builder.WriteString(substitutes[r])
+		default:
+			builder.WriteByte('?')
+		}
+	}
+	return builder.String()
+}
+
+// helveticaWidths and helveticaBoldWidths are the advance widths, in thousandths of the font size, of the
+// printable ASCII characters from the space (32) to the tilde (126)
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// TextWidth returns the width in points of a line of text, as it will be written
+func TextWidth(font Font, size float64, text string) float64 {
+	widths := helveticaWidths
+	if font == HelveticaBold {
+		widths = helveticaBoldWidths
+	}
+
+	total := 0
+	for _, b := range []byte(encode(text)) {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			// Accented letters and symbols are close to the width of a digit
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Truncate shortens text with an ellipsis so that it fits a width
+func Truncate(font Font, size float64, text string, width float64) string {
+	if TextWidth(font, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(font, size, string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// Wrap breaks text into lines that fit a width, at spaces where it can
+func Wrap(font Font, size float64, text string, width float64) (lines []string) {
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && TextWidth(font, size, candidate) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return
+}
@@ -0,0 +1,200 @@
+// Package pdf writes simple PDF documents, text, lines and filled rectangles on A4 pages, using the standard
+// Helvetica fonts every PDF reader ships with, so no font has to be embedded and no external library is needed.
+// Positions are in points from the top left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// PageWidth is the width of an A4 page in points
+	PageWidth = 595.28
+	// PageHeight is the height of an A4 page in points
+	PageHeight = 841.89
+)
+
+// Font is one of the standard fonts
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+// resourceName returns the name the font is referenced by in page content
+func (f Font) resourceName() string {
+	if f == HelveticaBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// Color is an RGB colour, each component from 0 to 1
+type Color struct {
+	R, G, B float64
+}
+
+var (
+	Black = Color{0, 0, 0}
+	White = Color{1, 1, 1}
+	Gray  = Color{0.45, 0.45, 0.45}
+)
+
+// Document is a PDF document being built page by page
+type Document struct {
+	Title   string
+	Author  string
+	pages   []*Page
+	created time.Time
+}
+
+// Page is a page of a document, drawing on it appends to its content stream
+type Page struct {
+	content bytes.Buffer
+}
+
+// NewDocument returns an empty document
+func NewDocument(title string, author string) *Document {
+	return &Document{
+		Title:   title,
+		Author:  author,
+		created: time.Now(),
+	}
+}
+
+// AddPage appends a blank page to the document and returns it
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Pages returns the pages added so far, in order
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Text writes a single line of text with its baseline at y
+func (p *Page) Text(x float64, y float64, font Font, size float64, color Color, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT %v rg /%v %v Tf %v %v Td (%v) Tj ET\n", color.operands(), font.resourceName(),
+		number(size), number(x), number(PageHeight-y), escape(encode(text)))
+}
+
+// TextRight writes a single line of text ending at x
+func (p *Page) TextRight(x float64, y float64, font Font, size float64, color Color, text string) {
+	p.Text(x-TextWidth(font, size, text), y, font, size, color, text)
+}
+
+// Line draws a straight line
+func (p *Page) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%v RG %v w %v %v m %v %v l S\n", color.operands(), number(width),
+		number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// FillRect fills a rectangle whose top left corner is at x, y
+func (p *Page) FillRect(x float64, y float64, width float64, height float64, color Color) {
+	fmt.Fprintf(&p.content, "%v rg %v %v %v %v re f\n", color.operands(), number(x), number(PageHeight-y-height),
+		number(width), number(height))
+}
+
+// Write serialises the document
+// Parameters:
+// - writer: where the PDF is written to
+// Returns:
+// - error when the document could not be written
+func (d *Document) Write(writer io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buffer bytes.Buffer
+	var offsets []int
+	beginObject := func() int {
+		offsets = append(offsets, buffer.Len())
+		id := len(offsets)
+		fmt.Fprintf(&buffer, "%d 0 obj\n", id)
+		return id
+	}
+	endObject := func() {
+		buffer.WriteString("endobj\n")
+	}
+
+	// Objects 1 to 5 are the catalog, the page tree, both fonts and the document information, pages follow
+	const catalogId, pagesId, regularId, boldId, infoId = 1, 2, 3, 4, 5
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	beginObject()
+	fmt.Fprintf(&buffer, "<< /Type /Catalog /Pages %d 0 R >>\n", pagesId)
+	endObject()
+
+	beginObject()
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", infoId+1+i*2))
+	}
+	fmt.Fprintf(&buffer, "<< /Type /Pages /Kids [%v] /Count %d /MediaBox [0 0 %v %v] >>\n",
+		strings.Join(kids, " "), len(d.pages), number(PageWidth), number(PageHeight))
+	endObject()
+
+	for _, name := range []string{"Helvetica", "Helvetica-Bold"} {
+		beginObject()
+		fmt.Fprintf(&buffer, "<< /Type /Font /Subtype /Type1 /BaseFont /%v /Encoding /WinAnsiEncoding >>\n", name)
+		endObject()
+	}
+
+	beginObject()
+	fmt.Fprintf(&buffer, "<< /Title (%v) /Author (%v) /Producer (aspire-lms) /CreationDate (D:%v) >>\n",
+		escape(encode(d.Title)), escape(encode(d.Author)), d.created.UTC().Format("20060102150405Z"))
+	endObject()
+
+	for _, page := range d.pages {
+		pageId := beginObject()
+		fmt.Fprintf(&buffer, "<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> "+
+			"/Contents %d 0 R >>\n", pagesId, regularId, boldId, pageId+1)
+		endObject()
+
+		beginObject()
+		fmt.Fprintf(&buffer, "<< /Length %d >>\nstream\n", page.content.Len())
+		buffer.Write(page.content.Bytes())
+		buffer.WriteString("endstream\n")
+		endObject()
+	}
+
+	xref := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, catalogId, infoId, xref)
+
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+// operands returns the colour as the operands of a colour operator
+func (c Color) operands() string {
+	return fmt.Sprintf("%v %v %v", number(c.R), number(c.G), number(c.B))
+}
+
+// number formats a coordinate or size with at most two decimals
+func number(value float64) string {
+	formatted := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", value), "0"), ".")
+	if formatted == "" || formatted == "-0" {
+		return "0"
+	}
+	return formatted
+}
+
+// escape escapes the characters with a meaning inside a PDF string
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", "", "\n", " ").Replace(text)
+}
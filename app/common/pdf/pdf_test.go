@@ -0,0 +1,73 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite_CrossReferenceMatchesObjects(t *testing.T) {
+	document := NewDocument("Statement (March)", "ASPIRE")
+	page := document.AddPage()
+	page.FillRect(0, 0, PageWidth, 60, Color{0.1, 0.2, 0.4})
+	page.Text(40, 40, HelveticaBold, 20, White, "ASPIRE")
+	page.Line(40, 80, 555, 80, 0.5, Gray)
+	document.AddPage().TextRight(555, 100, Helvetica, 10, Black, "Page 2 of 2")
+
+	var buffer bytes.Buffer
+	assert.NoError(t, document.Write(&buffer))
+	output := buffer.String()
+
+	assert.True(t, strings.HasPrefix(output, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(output, "%%EOF\n"))
+	assert.Contains(t, output, "/Count 2")
+	assert.Contains(t, output, `/Title (Statement \(March\))`)
+
+	// Every offset in the cross-reference table points at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(output)
+	assert.Len(t, startxref, 2)
+	xref, _ := strconv.Atoi(startxref[1])
+	assert.True(t, strings.HasPrefix(output[xref:], "xref\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(output[xref:], -1)
+	assert.Len(t, entries, 9)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(output[offset:], strconv.Itoa(i+1)+" 0 obj\n"), "object %d", i+1)
+	}
+
+	// Stream lengths match their content
+	for _, match := range regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindAllStringSubmatch(output, -1) {
+		length, _ := strconv.Atoi(match[1])
+		assert.Equal(t, length, len(match[2]))
+	}
+}
+
+func TestEncode_WinAnsi(t *testing.T) {
+	assert.Equal(t, "\x80100 \xa3 5", encode("€100 £ 5"))
+	assert.Equal(t, "Rs.1,000.00", encode("₹1,000.00"))
+	assert.Equal(t, "caf\xe9 ?", encode("café 你"))
+	assert.Equal(t, `a\(b\)\\`, escape("a(b)\\"))
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 5.56, TextWidth(Helvetica, 10, "0"), 0.001)
+	assert.InDelta(t, 33.36, TextWidth(Helvetica, 10, "100.00")+TextWidth(Helvetica, 10, ""), 5.6)
+	assert.Greater(t, TextWidth(HelveticaBold, 10, "Balance"), TextWidth(Helvetica, 10, "Balance"))
+	assert.Equal(t, "Repayment...", Truncate(Helvetica, 10, "Repayment received through gateway", 60))
+	assert.Equal(t, "Short", Truncate(Helvetica, 10, "Short", 60))
+}
+
+func TestWrap(t *testing.T) {
+	lines := Wrap(Helvetica, 10, "This is to certify that the loan has been repaid in full", 120)
+
+	assert.Equal(t, []string{"This is to certify that the", "loan has been repaid in full"}, lines)
+	for _, line := range lines {
+		assert.LessOrEqual(t, TextWidth(Helvetica, 10, line), 120.0)
+	}
+	assert.Empty(t, Wrap(Helvetica, 10, "  ", 120))
+}
@@ -0,0 +1,61 @@
+package utility
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordAlgorithmBcrypt prefixes password hashes made with bcrypt, so that the algorithm can be changed later
+// without losing the hashes already stored
+const PasswordAlgorithmBcrypt = "bcrypt"
+
+// PasswordHashCost is the bcrypt cost of new password hashes, hashes with a lower cost are rehashed on login
+const PasswordHashCost = 12
+
+// PasswordMaxLength is the longest password bcrypt can hash, in bytes
+const PasswordMaxLength = 72
+
+// HashPassword returns a salted bcrypt hash of the password, prefixed with its algorithm as "bcrypt$<hash>"
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordHashCost)
+	if err != nil {
+		return "", err
+	}
+	return PasswordAlgorithmBcrypt + "$" + string(hash), nil
+}
+
+// VerifyPassword checks a password against its stored hash. Hashes without an algorithm prefix are the unsalted
+// SHA-512 hex digests stored before bcrypt was introduced.
+// Parameters:
+// - encoded: the stored password hash
+// - password: the password to check
+// Returns:
+// - bool reporting whether the password matches
+// - bool reporting whether the hash should be replaced by HashPassword, because it is a legacy or weaker hash
+func VerifyPassword(encoded string, password string) (match bool, rehash bool) {
+	if encoded == "" {
+		return false, false
+	}
+
+	algorithm, hash, found := strings.Cut(encoded, "$")
+	if !found || algorithm != PasswordAlgorithmBcrypt {
+		legacy := legacyHashPassword(password)
+		return subtle.ConstantTimeCompare([]byte(encoded), []byte(legacy)) == 1, true
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, err != nil || cost < PasswordHashCost
+}
+
+// legacyHashPassword returns the unsalted SHA-512 hex digest passwords were stored as before bcrypt
+func legacyHashPassword(password string) string {
+	hash := sha512.Sum512([]byte(password))
+	return hex.EncodeToString(hash[:])
+}
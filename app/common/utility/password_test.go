@@ -0,0 +1,55 @@
+package utility
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "bcrypt$$2a$12$"))
+
+	// Every hash has its own salt
+	other, err := HashPassword("correct horse")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	match, rehash := VerifyPassword(hash, "correct horse")
+	assert.True(t, match)
+	assert.False(t, rehash)
+
+	match, _ = VerifyPassword(hash, "wrong horse")
+	assert.False(t, match)
+}
+
+func TestVerifyPassword_Legacy(t *testing.T) {
+	// SHA-512 of "12345678", the hash the seeded employee was stored with
+	legacy := "fa585d89c851dd338a70dcf535aa2a92fee7836dd6aff1226583e88e0996293f16bc009c652826e0fc5c706695a03cdd" +
+		"ce372f139eff4d13959da6f1f5d3eabe"
+
+	match, rehash := VerifyPassword(legacy, "12345678")
+	assert.True(t, match)
+	assert.True(t, rehash)
+
+	match, _ = VerifyPassword(legacy, "87654321")
+	assert.False(t, match)
+}
+
+func TestVerifyPassword_WeakerCost(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	match, rehash := VerifyPassword(PasswordAlgorithmBcrypt+"$"+string(hash), "correct horse")
+	assert.True(t, match)
+	assert.True(t, rehash)
+}
+
+func TestVerifyPassword_NoPassword(t *testing.T) {
+	match, rehash := VerifyPassword("", "")
+	assert.False(t, match)
+	assert.False(t, rehash)
+}
@@ -0,0 +1,60 @@
+package utility
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// EncryptSecret encrypts a secret kept in the database, such as a TOTP secret, with AES-256-GCM under a key derived
+// from the configured passphrase. The result is the base64 of the nonce followed by the ciphertext.
+func EncryptSecret(passphrase string, secret string) (string, error) {
+	aead, err := secretCipher(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret decrypts a secret encrypted by EncryptSecret with the same passphrase
+func DecryptSecret(passphrase string, encrypted string) (string, error) {
+	aead, err := secretCipher(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("encrypted secret is too short")
+	}
+	secret, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// secretCipher returns the AES-256-GCM cipher of a passphrase
+func secretCipher(passphrase string) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("no key is configured for encrypting secrets")
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
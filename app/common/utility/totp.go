@@ -0,0 +1,101 @@
+package utility
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TOTPPeriod is the number of seconds a time-based one-time password is valid for, as in RFC 6238
+	TOTPPeriod = 30
+
+	// TOTPDigits is the length of a time-based one-time password
+	TOTPDigits = 6
+
+	// totpSecretSize is the length in bytes of a TOTP secret, the size of an HMAC-SHA1 key recommended by RFC 4226
+	totpSecretSize = 20
+)
+
+// totpEncoding is the unpadded base32 authenticator apps expect TOTP secrets in
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random TOTP secret, base32 encoded
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPStep returns the time step of a point in time, the counter TOTP codes are computed from
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / TOTPPeriod
+}
+
+// TOTPCode returns the code of a base32 encoded secret for a time step, as in RFC 6238 with HMAC-SHA1
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation of RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, value%modulo), nil
+}
+
+// ValidateTOTP checks a code against a base32 encoded secret, accepting the codes of skew steps before and after
+// the current one to allow for clock drift
+// Parameters:
+// - secret: the base32 encoded secret
+// - code: the code entered by the user
+// - t: the time the code was entered
+// - skew: how many steps of drift are accepted
+// Returns:
+// - int64 with the step the code belongs to, for refusing a code that was already used
+// - bool reporting whether the code is valid
+// - error when the secret cannot be decoded
+func ValidateTOTP(secret string, code string, t time.Time, skew int64) (int64, bool, error) {
+	current := TOTPStep(t)
+	for step := current - skew; step <= current+skew; step++ {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI authenticator apps enrol a secret from, usually shown as a QR code
+func TOTPProvisioningURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(TOTPPeriod))
+	return fmt.Sprintf("otpauth://totp/%v:%v?%v", url.PathEscape(issuer), url.PathEscape(account), query.Encode())
+}
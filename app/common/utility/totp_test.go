@@ -0,0 +1,72 @@
+package utility
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 test vectors, "12345678901234567890" base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	// The last 6 digits of the 8 digit SHA1 test vectors of RFC 6238 appendix B
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	step, ok, err := ValidateTOTP(rfc6238Secret, "005924", now, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now), step)
+
+	// The code of the previous step is accepted for clock drift, older ones are not
+	step, ok, _ = ValidateTOTP(rfc6238Secret, "005924", now.Add(TOTPPeriod*time.Second), 1)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now), step)
+	_, ok, _ = ValidateTOTP(rfc6238Secret, "005924", now.Add(2*TOTPPeriod*time.Second), 1)
+	assert.False(t, ok)
+
+	_, _, err = ValidateTOTP("not base32!", "005924", now, 1)
+	assert.Error(t, err)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri := TOTPProvisioningURI("ASPIRE", "john.doe@example.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/ASPIRE:john.doe@example.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+}
+
+func TestEncryptSecret(t *testing.T) {
+	encrypted, err := EncryptSecret("passphrase", rfc6238Secret)
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, rfc6238Secret)
+
+	secret, err := DecryptSecret("passphrase", encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, rfc6238Secret, secret)
+
+	_, err = DecryptSecret("other passphrase", encrypted)
+	assert.Error(t, err)
+	_, err = EncryptSecret("", rfc6238Secret)
+	assert.Error(t, err)
+}
@@ -3,10 +3,13 @@ package configs
 import (
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -19,20 +22,48 @@ const (
 
 // Config object
 type Config struct {
-	Tenant           string      `env:"TENANT" required:"true"`
-	Env              string      `env:"ENV" required:"true"`
-	Mysql            MysqlConfig `json:"mysql"`
-	JWTAccessSecret  string      `env:"JWT_ACCESS_SIGN_KEY" required:"true"`
-	JWTRefreshSecret string      `env:"JWT_REFRESH_SIGN_KEY" required:"true"`
-	Host             string      `env:"APP_HOST" required:"true"`
-	Port             string      `env:"APP_PORT" required:"true"`
-	DbHost           string      `env:"DB_HOST" required:"true"`
-	DbPort           string      `env:"DB_PORT" required:"true"`
-	DbDriver         string      `env:"DB_DRIVER" required:"true"`
-	DbUser           string      `env:"DB_USER" required:"true"`
-	DbPassword       string      `env:"DB_PASSWORD" required:"true"`
-	DbName           string      `env:"DB_NAME" required:"true"`
-	NewRelicLicense  string      `env:"NEW_RELIC_LICENSE"`
+	Tenant            string      `env:"TENANT" required:"true"`
+	Env               string      `env:"ENV" required:"true"`
+	Mysql             MysqlConfig `json:"mysql"`
+	JWTAccessSecret   string      `env:"JWT_ACCESS_SIGN_KEY"`
+	JWTRefreshSecret  string      `env:"JWT_REFRESH_SIGN_KEY" required:"true"`
+	JWTKeySecret      string      `env:"JWT_KEY_ENCRYPTION_KEY" required:"true"`
+	JWTAlgorithm      string      `env:"JWT_SIGNING_ALGORITHM"`
+	JWTKeyRotation    string      `env:"JWT_KEY_ROTATION_INTERVAL"`
+	JWTKeySchedule    string      `env:"JWT_KEY_SCHEDULER_INTERVAL"`
+	Host              string      `env:"APP_HOST" required:"true"`
+	Port              string      `env:"APP_PORT" required:"true"`
+	DbHost            string      `env:"DB_HOST" required:"true"`
+	DbPort            string      `env:"DB_PORT" required:"true"`
+	DbDriver          string      `env:"DB_DRIVER" required:"true"`
+	DbUser            string      `env:"DB_USER" required:"true"`
+	DbPassword        string      `env:"DB_PASSWORD" required:"true"`
+	DbName            string      `env:"DB_NAME" required:"true"`
+	NewRelicLicense   string      `env:"NEW_RELIC_LICENSE"`
+	IdempotencyTTL    string      `env:"IDEMPOTENCY_KEY_TTL"`
+	PaymentGateway    string      `env:"PAYMENT_GATEWAY"`
+	GatewaySecret     string      `env:"PAYMENT_GATEWAY_WEBHOOK_SECRET"`
+	DebitProvider     string      `env:"DEBIT_PROVIDER"`
+	DebitDirectory    string      `env:"DEBIT_PROVIDER_DIRECTORY"`
+	DebitSecret       string      `env:"DEBIT_PROVIDER_WEBHOOK_SECRET"`
+	DebitMaxAttempts  string      `env:"DEBIT_RETRY_MAX_ATTEMPTS"`
+	DebitRetryDelay   string      `env:"DEBIT_RETRY_INTERVAL"`
+	DebitSchedule     string      `env:"DEBIT_SCHEDULER_INTERVAL"`
+	NonAccrualDays    string      `env:"ACCRUAL_NON_ACCRUAL_DPD"`
+	AccrualSchedule   string      `env:"ACCRUAL_SCHEDULER_INTERVAL"`
+	LateFeeAmount     string      `env:"LATE_FEE_AMOUNT"`
+	LateFeeGraceDays  string      `env:"LATE_FEE_GRACE_DAYS"`
+	Stage2Days        string      `env:"PROVISION_STAGE2_DPD"`
+	Stage3Days        string      `env:"PROVISION_STAGE3_DPD"`
+	ProvisionSchedule string      `env:"PROVISION_SCHEDULER_INTERVAL"`
+	Notifier          string      `env:"NOTIFICATION_PROVIDER"`
+	NotifierDirectory string      `env:"NOTIFICATION_DIRECTORY"`
+	MfaSecretKey      string      `env:"MFA_SECRET_KEY"`
+	MfaRequired       string      `env:"MFA_REQUIRED_USER_TYPES"`
+	LoginMaxFailures  string      `env:"LOGIN_MAX_FAILURES"`
+	LoginIPFailures   string      `env:"LOGIN_IP_MAX_FAILURES"`
+	LoginLockout      string      `env:"LOGIN_LOCKOUT_DURATION"`
+	Allocation        string      `env:"ALLOCATION_STRATEGY"`
 }
 
 // IsProd Checks if env is production
@@ -52,6 +83,165 @@ func (c Config) GetTenantName() string {
 	return strings.ToUpper(fmt.Sprintf("%v_%v", c.Tenant, c.Env))
 }
 
+// GetIdempotencyTTL returns how long an Idempotency-Key is remembered, defaulting to 24 hours
+func (c Config) GetIdempotencyTTL() time.Duration {
+	return parseDuration(c.IdempotencyTTL, 24*time.Hour)
+}
+
+// GetDebitDirectory returns the folder the file based debit provider exchanges files in
+func (c Config) GetDebitDirectory() string {
+	if c.DebitDirectory == "" {
+		return filepath.Join("storage", "debit")
+	}
+	return c.DebitDirectory
+}
+
+// GetDebitMaxAttempts returns how many times an installment is presented for debit, defaulting to 3
+func (c Config) GetDebitMaxAttempts() int {
+	attempts, err := strconv.Atoi(c.DebitMaxAttempts)
+	if err != nil || attempts <= 0 {
+		return 3
+	}
+	return attempts
+}
+
+// GetDebitRetryInterval returns the wait before the first retry of a failed debit, defaulting to 24 hours
+func (c Config) GetDebitRetryInterval() time.Duration {
+	return parseDuration(c.DebitRetryDelay, 24*time.Hour)
+}
+
+// GetDebitSchedulerInterval returns how often the debit collection cycle runs, defaulting to 1 hour
+func (c Config) GetDebitSchedulerInterval() time.Duration {
+	return parseDuration(c.DebitSchedule, time.Hour)
+}
+
+// GetNonAccrualDays returns the days past due from which a loan stops accruing interest, defaulting to 90
+func (c Config) GetNonAccrualDays() int {
+	days, err := strconv.Atoi(c.NonAccrualDays)
+	if err != nil || days <= 0 {
+		return 90
+	}
+	return days
+}
+
+// GetAccrualSchedulerInterval returns how often the interest accrual run starts, defaulting to 1 hour
+func (c Config) GetAccrualSchedulerInterval() time.Duration {
+	return parseDuration(c.AccrualSchedule, time.Hour)
+}
+
+// GetLateFeeAmount returns the fee charged on an installment left unpaid past its grace period, no fee is
+// charged when it is not set
+func (c Config) GetLateFeeAmount() float64 {
+	amount, err := strconv.ParseFloat(c.LateFeeAmount, 64)
+	if err != nil || amount <= 0 {
+		return 0
+	}
+	return math.Round(amount*100) / 100
+}
+
+// GetLateFeeGraceDays returns the days an installment may be overdue before it is charged the late fee,
+// defaulting to 5
+func (c Config) GetLateFeeGraceDays() int {
+	days, err := strconv.Atoi(c.LateFeeGraceDays)
+	if err != nil || days < 0 {
+		return 5
+	}
+	return days
+}
+
+// GetStage2Days returns the days past due from which a loan is in provisioning stage 2, defaulting to 30
+func (c Config) GetStage2Days() int {
+	days, err := strconv.Atoi(c.Stage2Days)
+	if err != nil || days <= 0 {
+		return 30
+	}
+	return days
+}
+
+// GetStage3Days returns the days past due from which a loan is in provisioning stage 3, defaulting to 90
+func (c Config) GetStage3Days() int {
+	days, err := strconv.Atoi(c.Stage3Days)
+	if err != nil || days <= 0 {
+		return 90
+	}
+	return days
+}
+
+// GetProvisionSchedulerInterval returns how often the scheduler checks for a month end to provision, defaulting
+// to 1 hour
+func (c Config) GetProvisionSchedulerInterval() time.Duration {
+	return parseDuration(c.ProvisionSchedule, time.Hour)
+}
+
+// GetNotifierDirectory returns the folder the file based notifier writes messages to
+func (c Config) GetNotifierDirectory() string {
+	if c.NotifierDirectory == "" {
+		return filepath.Join("storage", "notifications")
+	}
+	return c.NotifierDirectory
+}
+
+// GetMfaRequiredUserTypes returns the user types that have to sign in with a second factor, defaulting to employees.
+// NONE makes it optional for everyone.
+func (c Config) GetMfaRequiredUserTypes() []string {
+	if c.MfaRequired == "" {
+		return []string{"EMPLOYEE"}
+	}
+
+	var userTypes []string
+	for _, userType := range strings.Split(c.MfaRequired, ",") {
+		userType = strings.ToUpper(strings.TrimSpace(userType))
+		if userType != "" && userType != "NONE" {
+			userTypes = append(userTypes, userType)
+		}
+	}
+	return userTypes
+}
+
+// GetLoginMaxFailures returns how many failed sign ins lock an identifier out, defaulting to 10
+func (c Config) GetLoginMaxFailures() int {
+	failures, err := strconv.Atoi(c.LoginMaxFailures)
+	if err != nil || failures <= 0 {
+		return 10
+	}
+	return failures
+}
+
+// GetLoginIPMaxFailures returns how many failed sign ins lock an IP address out, defaulting to 50
+func (c Config) GetLoginIPMaxFailures() int {
+	failures, err := strconv.Atoi(c.LoginIPFailures)
+	if err != nil || failures <= 0 {
+		return 50
+	}
+	return failures
+}
+
+// GetLoginLockoutDuration returns how long the first lockout lasts, defaulting to 15 minutes
+func (c Config) GetLoginLockoutDuration() time.Duration {
+	return parseDuration(c.LoginLockout, 15*time.Minute)
+}
+
+// GetJWTSigningAlgorithm returns the algorithm new access token signing keys are generated for, ES256 or RS256,
+// defaulting to ES256
+func (c Config) GetJWTSigningAlgorithm() string {
+	if strings.ToUpper(strings.TrimSpace(c.JWTAlgorithm)) == "RS256" {
+		return "RS256"
+	}
+	return "ES256"
+}
+
+// GetJWTKeyRotationInterval returns how long an access token signing key signs before its successor takes over,
+// defaulting to 30 days
+func (c Config) GetJWTKeyRotationInterval() time.Duration {
+	return parseDuration(c.JWTKeyRotation, 30*24*time.Hour)
+}
+
+// GetJWTKeySchedulerInterval returns how often the scheduler checks whether a signing key is due for rotation,
+// defaulting to 1 hour
+func (c Config) GetJWTKeySchedulerInterval() time.Duration {
+	return parseDuration(c.JWTKeySchedule, time.Hour)
+}
+
 // LoadLocalConfig gets config from .env
 func LoadLocalConfig() {
 	requiredEnvVars := getRequiredEnvVars(Config{})
@@ -108,23 +298,63 @@ type ConfigLoad struct {
 // GetConfig gets all config for the application
 func GetConfig() Config {
 	return Config{
-		Tenant:           getEnv("TENANT"),
-		Env:              getEnv("ENV"),
-		Mysql:            GetMysqlConfig(),
-		JWTAccessSecret:  getEnv("JWT_ACCESS_SIGN_KEY"),
-		JWTRefreshSecret: getEnv("JWT_REFRESH_SIGN_KEY"),
-		Host:             getEnv("APP_HOST"),
-		Port:             getEnv("APP_PORT"),
-		DbHost:           getEnv("DB_HOST"),
-		DbPort:           getEnv("DB_PORT"),
-		DbDriver:         getEnv("DB_DRIVER"),
-		DbUser:           getEnv("DB_USER"),
-		DbPassword:       getEnv("DB_PASSWORD"),
-		DbName:           getEnv("DB_NAME"),
-		NewRelicLicense:  getEnv("NEW_RELIC_LICENSE"),
+		Tenant:            getEnv("TENANT"),
+		Env:               getEnv("ENV"),
+		Mysql:             GetMysqlConfig(),
+		JWTAccessSecret:   getEnv("JWT_ACCESS_SIGN_KEY"),
+		JWTRefreshSecret:  getEnv("JWT_REFRESH_SIGN_KEY"),
+		JWTKeySecret:      getEnv("JWT_KEY_ENCRYPTION_KEY"),
+		JWTAlgorithm:      getEnv("JWT_SIGNING_ALGORITHM"),
+		JWTKeyRotation:    getEnv("JWT_KEY_ROTATION_INTERVAL"),
+		JWTKeySchedule:    getEnv("JWT_KEY_SCHEDULER_INTERVAL"),
+		Host:              getEnv("APP_HOST"),
+		Port:              getEnv("APP_PORT"),
+		DbHost:            getEnv("DB_HOST"),
+		DbPort:            getEnv("DB_PORT"),
+		DbDriver:          getEnv("DB_DRIVER"),
+		DbUser:            getEnv("DB_USER"),
+		DbPassword:        getEnv("DB_PASSWORD"),
+		DbName:            getEnv("DB_NAME"),
+		NewRelicLicense:   getEnv("NEW_RELIC_LICENSE"),
+		IdempotencyTTL:    getEnv("IDEMPOTENCY_KEY_TTL"),
+		PaymentGateway:    getEnv("PAYMENT_GATEWAY"),
+		GatewaySecret:     getEnv("PAYMENT_GATEWAY_WEBHOOK_SECRET"),
+		DebitProvider:     getEnv("DEBIT_PROVIDER"),
+		DebitDirectory:    getEnv("DEBIT_PROVIDER_DIRECTORY"),
+		DebitSecret:       getEnv("DEBIT_PROVIDER_WEBHOOK_SECRET"),
+		DebitMaxAttempts:  getEnv("DEBIT_RETRY_MAX_ATTEMPTS"),
+		DebitRetryDelay:   getEnv("DEBIT_RETRY_INTERVAL"),
+		DebitSchedule:     getEnv("DEBIT_SCHEDULER_INTERVAL"),
+		NonAccrualDays:    getEnv("ACCRUAL_NON_ACCRUAL_DPD"),
+		AccrualSchedule:   getEnv("ACCRUAL_SCHEDULER_INTERVAL"),
+		LateFeeAmount:     getEnv("LATE_FEE_AMOUNT"),
+		LateFeeGraceDays:  getEnv("LATE_FEE_GRACE_DAYS"),
+		Stage2Days:        getEnv("PROVISION_STAGE2_DPD"),
+		Stage3Days:        getEnv("PROVISION_STAGE3_DPD"),
+		ProvisionSchedule: getEnv("PROVISION_SCHEDULER_INTERVAL"),
+		Notifier:          getEnv("NOTIFICATION_PROVIDER"),
+		NotifierDirectory: getEnv("NOTIFICATION_DIRECTORY"),
+		MfaSecretKey:      getEnv("MFA_SECRET_KEY"),
+		MfaRequired:       getEnv("MFA_REQUIRED_USER_TYPES"),
+		LoginMaxFailures:  getEnv("LOGIN_MAX_FAILURES"),
+		LoginIPFailures:   getEnv("LOGIN_IP_MAX_FAILURES"),
+		LoginLockout:      getEnv("LOGIN_LOCKOUT_DURATION"),
+		Allocation:        getEnv("ALLOCATION_STRATEGY"),
 	}
 }
 
 func getEnv(key string) string {
 	return os.Getenv(key)
 }
+
+// parseDuration parses a Go duration string and falls back to the given default when it is empty or invalid
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
@@ -0,0 +1,123 @@
+package action_controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	actionService "github.com/nishanthrk/aspire-lms/app/services/action"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"net/http"
+	"strings"
+)
+
+// GetActions handles listing the actions proposed for a checker
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?status=" and "?type=" narrow the list
+// - actionService: actionService.ActionService for handling maker-checker operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the actions
+func GetActions(c *fiber.Ctx, actionService actionService.ActionService) error {
+	// Initialize a PendingActionListRequest DTO from the query string
+	params := dto.PendingActionListRequest{
+		Status:     strings.ToUpper(c.Query("status")),
+		ActionType: strings.ToUpper(c.Query("type")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the actionService to list the actions
+	response, handle := actionService.GetActions(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the actions
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// ApproveAction handles a checker approving a pending action, which applies it
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - actionService: actionService.ActionService for handling maker-checker operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the applied action
+func ApproveAction(c *fiber.Ctx, actionService actionService.ActionService, userService userService.UserService) error {
+	// Initialize an ActionDecisionRequest DTO and set the ActionID from the URL parameters
+	params := dto.ActionDecisionRequest{}
+	params.ActionID = c.Params("actionId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the actionService to approve and apply the action
+	response, handle := actionService.ApproveAction(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the applied action
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// RejectAction handles a checker rejecting a pending action, which discards it
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - actionService: actionService.ActionService for handling maker-checker operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the rejected action
+func RejectAction(c *fiber.Ctx, actionService actionService.ActionService, userService userService.UserService) error {
+	// Initialize an ActionDecisionRequest DTO and set the ActionID from the URL parameters
+	params := dto.ActionDecisionRequest{}
+	params.ActionID = c.Params("actionId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the actionService to reject the action
+	response, handle := actionService.RejectAction(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the rejected action
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,156 @@
+package action_controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	actionSvc "github.com/nishanthrk/aspire-lms/app/services/action"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetActions_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActionService := actionSvc.NewMockActionService(ctrl)
+
+	response := dto.PendingActionListResponse{Status: 1, Message: "Total 1 action(s) found"}
+	response.Data.Actions = []dto.PendingActionObject{{ActionID: "action_id", Status: models.PendingActionStatusPending}}
+
+	mockActionService.EXPECT().GetActions(dto.PendingActionListRequest{
+		Status: models.PendingActionStatusPending,
+	}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/actions", func(c *fiber.Ctx) error {
+		return GetActions(c, mockActionService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/actions?status=pending", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	actions := responseBody["data"].(map[string]interface{})["actions"].([]interface{})
+	assert.Len(t, actions, 1)
+}
+
+func TestGetActions_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActionService := actionSvc.NewMockActionService(ctrl)
+
+	app := fiber.New()
+	app.Get("/actions", func(c *fiber.Ctx) error {
+		return GetActions(c, mockActionService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/actions?status=unknown", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestApproveAction_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActionService := actionSvc.NewMockActionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.PendingActionResponse{Status: 1, Message: "Action approved and applied"}
+	response.Data = dto.PendingActionObject{ActionID: "action_id", Status: models.PendingActionStatusApplied}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "checker_id"})
+	mockActionService.EXPECT().ApproveAction(dto.ActionDecisionRequest{
+		ActionID: "action_id",
+		Reason:   "Verified",
+	}, models.User{UserID: "checker_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/actions/:actionId/approve", func(c *fiber.Ctx) error {
+		return ApproveAction(c, mockActionService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"reason": "Verified"})
+	req := httptest.NewRequest(http.MethodPost, "/actions/action_id/approve", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "APPLIED", data["status"])
+}
+
+func TestApproveAction_SameUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActionService := actionSvc.NewMockActionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "maker_id"})
+	mockActionService.EXPECT().ApproveAction(gomock.Any(), gomock.Any()).Return(dto.PendingActionResponse{},
+		dto.HandleError{
+			Status: -3,
+			Errors: fmt.Errorf("an action has to be checked by someone other than who proposed it"),
+		})
+
+	app := fiber.New()
+	app.Post("/actions/:actionId/approve", func(c *fiber.Ctx) error {
+		return ApproveAction(c, mockActionService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/actions/action_id/approve", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-3), responseBody["status"])
+}
+
+func TestRejectAction_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActionService := actionSvc.NewMockActionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.PendingActionResponse{Status: 1, Message: "Action rejected"}
+	response.Data = dto.PendingActionObject{ActionID: "action_id", Status: models.PendingActionStatusRejected}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "checker_id"})
+	mockActionService.EXPECT().RejectAction(dto.ActionDecisionRequest{
+		ActionID: "action_id",
+		Reason:   "Not justified",
+	}, models.User{UserID: "checker_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/actions/:actionId/reject", func(c *fiber.Ctx) error {
+		return RejectAction(c, mockActionService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"reason": "Not justified"})
+	req := httptest.NewRequest(http.MethodPost, "/actions/action_id/reject", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,220 @@
+package assignment_controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	assignmentService "github.com/nishanthrk/aspire-lms/app/services/assignment"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"net/http"
+)
+
+// ReassignApplication handles handing a pending loan application over to another employee
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - assignmentService: assignmentService.AssignmentService for handling assignment operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the handover
+func ReassignApplication(c *fiber.Ctx, assignmentService assignmentService.AssignmentService,
+	userService userService.UserService) error {
+	// Initialize a ReassignRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.ReassignRequest{}
+	params.ApplicationID = c.Params("applicationId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the assignmentService to reassign the application
+	response, handle := assignmentService.ReassignApplication(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the handover
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// BulkReassign handles handing the pending loan applications of an employee over to another employee
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - assignmentService: assignmentService.AssignmentService for handling assignment operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the reassigned and
+// skipped applications
+func BulkReassign(c *fiber.Ctx, assignmentService assignmentService.AssignmentService,
+	userService userService.UserService) error {
+	// Initialize a BulkReassignRequest DTO
+	params := dto.BulkReassignRequest{}
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the assignmentService to reassign the applications
+	response, handle := assignmentService.BulkReassign(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the reassigned and skipped applications
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetAssignmentHistory handles listing who held a loan application when
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - assignmentService: assignmentService.AssignmentService for handling assignment operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the assignments
+func GetAssignmentHistory(c *fiber.Ctx, assignmentService assignmentService.AssignmentService) error {
+	// Retrieve the application ID from the URL parameters
+	applicationId := c.Params("applicationId")
+
+	// Call the assignmentService to list the assignments of the application
+	response, handle := assignmentService.GetAssignmentHistory(applicationId)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the assignments
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// CreateDelegation handles setting an out-of-office delegation for a date range
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - assignmentService: assignmentService.AssignmentService for handling assignment operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the delegation
+func CreateDelegation(c *fiber.Ctx, assignmentService assignmentService.AssignmentService,
+	userService userService.UserService) error {
+	// Initialize a DelegationRequest DTO
+	params := dto.DelegationRequest{}
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the assignmentService to create the delegation
+	response, handle := assignmentService.CreateDelegation(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the delegation
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetDelegations handles listing the delegations an employee gave or received
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?user_id=" lists the delegations of another employee
+// - assignmentService: assignmentService.AssignmentService for handling assignment operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the delegations
+func GetDelegations(c *fiber.Ctx, assignmentService assignmentService.AssignmentService,
+	userService userService.UserService) error {
+	// Initialize a DelegationListRequest DTO from the query string
+	params := dto.DelegationListRequest{
+		UserID: c.Query("user_id"),
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the assignmentService to list the delegations
+	response, handle := assignmentService.GetDelegations(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the delegations
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// RevokeDelegation handles ending a delegation before its end date
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - assignmentService: assignmentService.AssignmentService for handling assignment operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the delegation
+func RevokeDelegation(c *fiber.Ctx, assignmentService assignmentService.AssignmentService,
+	userService userService.UserService) error {
+	// Initialize a DelegationRevokeRequest DTO and set the DelegationID from the URL parameters
+	params := dto.DelegationRevokeRequest{}
+	params.DelegationID = c.Params("delegationId")
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the assignmentService to revoke the delegation
+	response, handle := assignmentService.RevokeDelegation(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the revoked delegation
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,274 @@
+package assignment_controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	assignmentSvc "github.com/nishanthrk/aspire-lms/app/services/assignment"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReassignApplication_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.ReassignResponse{Status: 1, Message: "Loan application reassigned"}
+	response.Data = dto.ReassignObject{ApplicationID: "app_id", FromUserID: "employee_id", ToUserID: "other_id"}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "admin_id"})
+	mockAssignmentService.EXPECT().ReassignApplication(dto.ReassignRequest{
+		ApplicationID: "app_id",
+		ToUserID:      "other_id",
+		Reason:        "On leave",
+	}, models.User{UserID: "admin_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/applications/:applicationId/reassign", func(c *fiber.Ctx) error {
+		return ReassignApplication(c, mockAssignmentService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"to_user_id": "other_id", "reason": "On leave"})
+	req := httptest.NewRequest(http.MethodPost, "/applications/app_id/reassign", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "other_id", data["to_user_id"])
+}
+
+func TestReassignApplication_MissingReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/applications/:applicationId/reassign", func(c *fiber.Ctx) error {
+		return ReassignApplication(c, mockAssignmentService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"to_user_id": "other_id"})
+	req := httptest.NewRequest(http.MethodPost, "/applications/app_id/reassign", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestBulkReassign_SameEmployee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/applications/reassign", func(c *fiber.Ctx) error {
+		return BulkReassign(c, mockAssignmentService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"from_user_id": "employee_id",
+		"to_user_id":   "employee_id",
+		"reason":       "On leave",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/applications/reassign", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestBulkReassign_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.BulkReassignResponse{Status: 1, Message: "1 application(s) reassigned, 1 skipped"}
+	response.Data.Reassigned = []dto.ReassignObject{{ApplicationID: "app_1", FromUserID: "employee_id",
+		ToUserID: "other_id"}}
+	response.Data.Skipped = []dto.ReassignSkipObject{{ApplicationID: "app_2", Error: "application is not pending"}}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "admin_id"})
+	mockAssignmentService.EXPECT().BulkReassign(dto.BulkReassignRequest{
+		FromUserID: "employee_id",
+		ToUserID:   "other_id",
+		Reason:     "On leave",
+	}, models.User{UserID: "admin_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/applications/reassign", func(c *fiber.Ctx) error {
+		return BulkReassign(c, mockAssignmentService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"from_user_id": "employee_id",
+		"to_user_id":   "other_id",
+		"reason":       "On leave",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/applications/reassign", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Len(t, data["reassigned"], 1)
+	assert.Len(t, data["skipped"], 1)
+}
+
+func TestGetAssignmentHistory_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+
+	response := dto.AssignmentHistoryResponse{Status: 1, Message: "Total 2 assignment(s) found"}
+	response.Data.ApplicationID = "app_id"
+	response.Data.Assignments = []dto.AssignmentObject{
+		{AssignmentID: "assignment_1", UserID: "employee_id", EndedAt: "2024-01-02T00:00:00Z"},
+		{AssignmentID: "assignment_2", UserID: "other_id"},
+	}
+
+	mockAssignmentService.EXPECT().GetAssignmentHistory("app_id").Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/applications/:applicationId/assignments", func(c *fiber.Ctx) error {
+		return GetAssignmentHistory(c, mockAssignmentService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/applications/app_id/assignments", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestCreateDelegation_InvalidDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/delegations", func(c *fiber.Ctx) error {
+		return CreateDelegation(c, mockAssignmentService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"delegate_id": "other_id",
+		"start_date":  "01/02/2024",
+		"end_date":    "2024-01-10",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/delegations", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestCreateDelegation_Overlapping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "employee_id"})
+	mockAssignmentService.EXPECT().CreateDelegation(gomock.Any(), gomock.Any()).Return(dto.DelegationResponse{},
+		dto.HandleError{
+			Status: -4,
+			Errors: fmt.Errorf("overlaps delegation delegation_id from 2024-01-01 to 2024-01-05"),
+		})
+
+	app := fiber.New()
+	app.Post("/delegations", func(c *fiber.Ctx) error {
+		return CreateDelegation(c, mockAssignmentService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"delegate_id": "other_id",
+		"start_date":  "2024-01-02",
+		"end_date":    "2024-01-10",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/delegations", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-4), responseBody["status"])
+}
+
+func TestGetDelegations_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.DelegationListResponse{Status: 1, Message: "Total 1 delegation(s) found"}
+	response.Data.Delegations = []dto.DelegationObject{{DelegationID: "delegation_id", UserID: "employee_id",
+		DelegateID: "other_id"}}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "admin_id"})
+	mockAssignmentService.EXPECT().GetDelegations(dto.DelegationListRequest{UserID: "employee_id"},
+		models.User{UserID: "admin_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/delegations", func(c *fiber.Ctx) error {
+		return GetDelegations(c, mockAssignmentService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/delegations?user_id=employee_id", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRevokeDelegation_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssignmentService := assignmentSvc.NewMockAssignmentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.DelegationResponse{Status: 1, Message: "Delegation revoked"}
+	response.Data = dto.DelegationObject{DelegationID: "delegation_id", RevokedBy: "employee_id"}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "employee_id"})
+	mockAssignmentService.EXPECT().RevokeDelegation(dto.DelegationRevokeRequest{DelegationID: "delegation_id"},
+		models.User{UserID: "employee_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/delegations/:delegationId/revoke", func(c *fiber.Ctx) error {
+		return RevokeDelegation(c, mockAssignmentService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/delegations/delegation_id/revoke", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
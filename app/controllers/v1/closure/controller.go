@@ -0,0 +1,93 @@
+package closure_controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	closure "github.com/nishanthrk/aspire-lms/app/services/closure"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"net/http"
+)
+
+// CloseLoan handles the closure of a repaid loan
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - closureService: closure.ClosureService for closing the loan
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the closure date
+func CloseLoan(c *fiber.Ctx, closureService closure.ClosureService, userService userService.UserService) error {
+	// Initialize a LoanClosureRequest DTO from the URL parameters
+	params := dto.LoanClosureRequest{
+		ApplicationID: c.Params("applicationId"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the closureService to close the loan
+	response, handle := closureService.CloseLoan(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the closure
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetClosureLetter handles the download of the no-objection letter of a closed loan
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - closureService: closure.ClosureService for building the letter
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns the letter as a PDF
+func GetClosureLetter(c *fiber.Ctx, closureService closure.ClosureService, userService userService.UserService) error {
+	// Initialize a ClosureLetterRequest DTO from the URL parameters
+	params := dto.ClosureLetterRequest{
+		ApplicationID: c.Params("applicationId"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the closureService to build the letter
+	response, handle := closureService.GetClosureLetter(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return the letter as a PDF download
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=\"closure-letter-%v.pdf\"", response.Data.ApplicationId))
+	c.Status(http.StatusOK)
+	return closure.WriteClosureLetterPDF(c, response.Data)
+}
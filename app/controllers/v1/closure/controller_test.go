@@ -0,0 +1,136 @@
+package closure_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	closureSvc "github.com/nishanthrk/aspire-lms/app/services/closure"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCloseLoan_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClosureService := closureSvc.NewMockClosureService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.LoanClosureResponse{Status: 1, Message: "Loan closed successfully"}
+	response.Data.ApplicationId = "application_id"
+	response.Data.Status = models.LoanApplicationStatusClosed
+	response.Data.ClosedDate = "2024-03-02"
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockClosureService.EXPECT().CloseLoan(dto.LoanClosureRequest{ApplicationID: "application_id"},
+		models.User{UserID: "user_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/close", func(c *fiber.Ctx) error {
+		return CloseLoan(c, mockClosureService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/close", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "CLOSED", data["status"])
+	assert.Equal(t, "2024-03-02", data["closed_date"])
+}
+
+func TestCloseLoan_AmountsOutstanding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClosureService := closureSvc.NewMockClosureService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockClosureService.EXPECT().CloseLoan(gomock.Any(), gomock.Any()).Return(dto.LoanClosureResponse{},
+		dto.HandleError{Status: -5, Errors: fmt.Errorf("loan cannot be closed: excess credit of ₹10.00 has to be refunded")})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/close", func(c *fiber.Ctx) error {
+		return CloseLoan(c, mockClosureService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/close", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-5), responseBody["status"])
+	assert.Equal(t, "loan cannot be closed: excess credit of ₹10.00 has to be refunded", responseBody["error"])
+}
+
+func TestGetClosureLetter_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClosureService := closureSvc.NewMockClosureService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.ClosureLetterResponse{Status: 1}
+	response.Data = dto.ClosureLetter{Tenant: "ASPIRE", ApplicationId: "application_id", CustomerName: "Jane Doe",
+		LoanAmount: "$1,000.00", ClosedDate: "2024-03-02"}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockClosureService.EXPECT().GetClosureLetter(dto.ClosureLetterRequest{ApplicationID: "application_id"},
+		models.User{UserID: "user_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/application/:applicationId/closure-letter", func(c *fiber.Ctx) error {
+		return GetClosureLetter(c, mockClosureService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/application/application_id/closure-letter", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/pdf", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, `attachment; filename="closure-letter-application_id.pdf"`,
+		resp.Header.Get(fiber.HeaderContentDisposition))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.True(t, strings.HasPrefix(string(body), "%PDF-"))
+}
+
+func TestGetClosureLetter_NotClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClosureService := closureSvc.NewMockClosureService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockClosureService.EXPECT().GetClosureLetter(gomock.Any(), gomock.Any()).Return(dto.ClosureLetterResponse{},
+		dto.HandleError{Status: -3, Errors: fmt.Errorf("loan is PAID, a closure letter is only issued once it is closed")})
+
+	app := fiber.New()
+	app.Get("/application/:applicationId/closure-letter", func(c *fiber.Ctx) error {
+		return GetClosureLetter(c, mockClosureService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/application/application_id/closure-letter", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-3), responseBody["status"])
+}
@@ -0,0 +1,134 @@
+package ledger_controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	accrual "github.com/nishanthrk/aspire-lms/app/services/accrual"
+	ledger "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GetTrialBalance handles the trial balance of the general ledger
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?as_of=" and "?currency=" narrow the report
+// - ledgerService: ledger.LedgerService for handling ledger operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the trial balance
+func GetTrialBalance(c *fiber.Ctx, ledgerService ledger.LedgerService) error {
+	// Initialize a TrialBalanceRequest DTO from the query string
+	params := dto.TrialBalanceRequest{
+		AsOf:         c.Query("as_of"),
+		CurrencyCode: strings.ToUpper(c.Query("currency")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the ledgerService to total the accounts
+	response, handle := ledgerService.GetTrialBalance(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the trial balance
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetJournal handles the listing of journal entries
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?application_id=", "?from=" and "?to=" narrow the listing
+// - ledgerService: ledger.LedgerService for handling ledger operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the entries
+func GetJournal(c *fiber.Ctx, ledgerService ledger.LedgerService) error {
+	// Initialize a JournalRequest DTO from the query string
+	params := dto.JournalRequest{
+		ApplicationID: c.Query("application_id"),
+		From:          c.Query("from"),
+		To:            c.Query("to"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the ledgerService to load the entries
+	response, handle := ledgerService.GetJournal(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the entries
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// RunAccruals handles an interest accrual run without waiting for the scheduler
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?as_of=" is the last day to accrue, yesterday by default
+// - accrualService: accrual.AccrualService for running the accrual
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the run summary
+func RunAccruals(c *fiber.Ctx, accrualService accrual.AccrualService) error {
+	// Initialize an AccrualRunRequest DTO from the query string
+	params := dto.AccrualRunRequest{
+		AsOf: c.Query("as_of"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	asOf := time.Now().AddDate(0, 0, -1)
+	if params.AsOf != "" {
+		asOf, _ = time.ParseInLocation("2006-01-02", params.AsOf, time.Local)
+	}
+
+	// Accruing days that have not ended yet would recognise interest not earned
+	now := time.Now()
+	if !asOf.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  "interest can only be accrued for days that have ended",
+		})
+	}
+
+	// Call the accrualService to run the accrual
+	response, handle := accrualService.RunAccruals(asOf)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the run summary
+	return c.Status(http.StatusOK).JSON(response)
+}
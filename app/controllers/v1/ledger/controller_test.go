@@ -0,0 +1,188 @@
+package ledger_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	accrualSvc "github.com/nishanthrk/aspire-lms/app/services/accrual"
+	ledgerSvc "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetTrialBalance_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLedgerService := ledgerSvc.NewMockLedgerService(ctrl)
+
+	response := dto.TrialBalanceResponse{Status: 1}
+	response.Data.AsOf = "2024-03-31"
+	response.Data.Currencies = []dto.TrialBalanceCurrency{{
+		CurrencyCode: "INR",
+		TotalDebit:   "₹1,000.00",
+		TotalCredit:  "₹1,000.00",
+		Balanced:     true,
+	}}
+
+	mockLedgerService.EXPECT().GetTrialBalance(dto.TrialBalanceRequest{
+		AsOf:         "2024-03-31",
+		CurrencyCode: "INR",
+	}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/ledger/trial-balance", func(c *fiber.Ctx) error {
+		return GetTrialBalance(c, mockLedgerService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ledger/trial-balance?as_of=2024-03-31&currency=inr", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	currencies := data["currencies"].([]interface{})
+	assert.Equal(t, true, currencies[0].(map[string]interface{})["balanced"])
+}
+
+func TestGetTrialBalance_InvalidDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLedgerService := ledgerSvc.NewMockLedgerService(ctrl)
+
+	app := fiber.New()
+	app.Get("/ledger/trial-balance", func(c *fiber.Ctx) error {
+		return GetTrialBalance(c, mockLedgerService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ledger/trial-balance?as_of=31-03-2024", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestGetJournal_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLedgerService := ledgerSvc.NewMockLedgerService(ctrl)
+
+	applicationId := "6f1c2b1e-2f7a-4c55-9c1e-2b9f3f7d8a10"
+	response := dto.JournalResponse{Status: 1, Data: []dto.JournalEntryObject{{
+		EntryId:         "entry_id",
+		EntryType:       "DISBURSEMENT",
+		SourceReference: "DISBURSEMENT:" + applicationId,
+		ApplicationId:   applicationId,
+		CurrencyCode:    "INR",
+		EffectiveDate:   "2024-03-01",
+		Lines: []dto.JournalLineObject{
+			{AccountCode: "1100", Debit: "₹1,000.00"},
+			{AccountCode: "1000", Credit: "₹1,000.00"},
+		},
+	}}}
+
+	mockLedgerService.EXPECT().GetJournal(dto.JournalRequest{
+		ApplicationID: applicationId,
+		From:          "2024-03-01",
+	}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/ledger/entries", func(c *fiber.Ctx) error {
+		return GetJournal(c, mockLedgerService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ledger/entries?application_id="+applicationId+"&from=2024-03-01", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	entries := responseBody["data"].([]interface{})
+	assert.Equal(t, "entry_id", entries[0].(map[string]interface{})["entry_id"])
+}
+
+func TestGetJournal_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLedgerService := ledgerSvc.NewMockLedgerService(ctrl)
+	mockLedgerService.EXPECT().GetJournal(gomock.Any()).Return(dto.JournalResponse{}, dto.HandleError{
+		Status: -1,
+		Errors: fmt.Errorf("from date 2024-03-31 is after to date 2024-03-01"),
+	})
+
+	app := fiber.New()
+	app.Get("/ledger/entries", func(c *fiber.Ctx) error {
+		return GetJournal(c, mockLedgerService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ledger/entries?from=2024-03-31&to=2024-03-01", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, "from date 2024-03-31 is after to date 2024-03-01", responseBody["error"])
+}
+
+func TestRunAccruals_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccrualService := accrualSvc.NewMockAccrualService(ctrl)
+
+	response := dto.AccrualRunResponse{Status: 1}
+	response.Data.AsOf = "2024-03-31"
+	response.Data.LoansAccrued = 2
+	response.Data.DaysAccrued = 2
+
+	asOf, _ := time.ParseInLocation("2006-01-02", "2024-03-31", time.Local)
+	mockAccrualService.EXPECT().RunAccruals(asOf).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/ledger/accruals/run", func(c *fiber.Ctx) error {
+		return RunAccruals(c, mockAccrualService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ledger/accruals/run?as_of=2024-03-31", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["loans_accrued"])
+}
+
+func TestRunAccruals_DayNotEnded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccrualService := accrualSvc.NewMockAccrualService(ctrl)
+
+	app := fiber.New()
+	app.Post("/ledger/accruals/run", func(c *fiber.Ctx) error {
+		return RunAccruals(c, mockAccrualService)
+	})
+
+	today := time.Now().Format("2006-01-02")
+	req := httptest.NewRequest(http.MethodPost, "/ledger/accruals/run?as_of="+today, nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, "interest can only be accrued for days that have ended", responseBody["error"])
+}
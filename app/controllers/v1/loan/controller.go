@@ -8,9 +8,10 @@ import (
 	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
 	userService "github.com/nishanthrk/aspire-lms/app/services/user"
 	"net/http"
+	"strconv"
 )
 
-// CreateLoanApplication handles the creation of a loan application
+// CreateLoanApplication handles the creation of a loan application by the signed in customer
 // Parameters:
 // - c: *fiber.Ctx representing the request context
 // - loanService: loanService.LoanService for handling loan-related operations
@@ -29,8 +30,11 @@ func CreateLoanApplication(c *fiber.Ctx, loanService loanService.LoanService, us
 		})
 	}
 
+	// Get the signed in customer applying for the loan
+	user := userService.GetUserObject(c)
+
 	// Call the loanService to create the loan application
-	response, handle := loanService.CreateLoanApplication(params, userService, repaymentService)
+	response, handle := loanService.CreateLoanApplication(params, user, userService, repaymentService)
 	if handle.Status < 0 {
 		// Return a 422 Unprocessable Entity status with the service error
 		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
@@ -125,3 +129,62 @@ func GetLoanApplication(c *fiber.Ctx, loanService loanService.LoanService, userS
 	// Return a 200 OK status with the loan application details
 	return c.Status(http.StatusOK).JSON(response)
 }
+
+// GetEligibilityConfigs retrieves the eligibility configs the eligible loan amount is calculated with
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - loanService: loanService.LoanService for handling loan-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the configs
+func GetEligibilityConfigs(c *fiber.Ctx, loanService loanService.LoanService) error {
+	// Call the loanService to get the eligibility configs
+	response, handle := loanService.GetEligibilityConfigs()
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the eligibility configs
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// ProposeEligibilityConfig handles proposing an edit of an eligibility config, applied once a checker approves it
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - loanService: loanService.LoanService for handling loan-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the pending action
+func ProposeEligibilityConfig(c *fiber.Ctx, loanService loanService.LoanService, userService userService.UserService) error {
+	// Initialize the params object and set the ConfigID from the URL parameter
+	params := dto.EligibilityConfigRequest{}
+	params.ConfigID, _ = strconv.ParseInt(c.Params("configId"), 10, 64)
+
+	// Parse and validate the request body into params
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the loanService to propose the edit
+	response, handle := loanService.ProposeEligibilityConfig(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the pending action
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -25,7 +25,12 @@ func TestCreateLoanApplication_Success(t *testing.T) {
 	mockUserService := userSvc.NewMockUserService(ctrl)
 	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
 
-	mockLoanService.EXPECT().CreateLoanApplication(gomock.Any(), mockUserService, mockRepaymentService).Return(dto.ApplicationCreateResponse{
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{
+		UserID:   "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d",
+		UserName: "John Doe",
+		UserType: "CUSTOMER",
+	})
+	mockLoanService.EXPECT().CreateLoanApplication(gomock.Any(), gomock.Any(), mockUserService, mockRepaymentService).Return(dto.ApplicationCreateResponse{
 		Status: 1,
 		Data: struct {
 			ApplicationID string `json:"application_id"`
@@ -46,10 +51,7 @@ func TestCreateLoanApplication_Success(t *testing.T) {
 
 	request := dto.ApplicationCreateRequest{
 		User: dto.UserObject{
-			UserName:     "John Doe",
-			UserEmail:    "john.doe@example.com",
-			MobileNumber: "1234567890",
-			CountryCode:  "IND",
+			CountryCode: "IND",
 			Kyc: dto.KycObject{
 				KycType:   "PAN",
 				KycNumber: "ABCDE1234F",
@@ -168,6 +170,101 @@ func TestApproveLoanApplication_Success(t *testing.T) {
 	assert.Equal(t, "2e560272-45eb-442e-9a67-a2f8c423e063", data["application_id"])
 }
 
+func TestApproveLoanApplication_RoutedToNextLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoanService := loanSvc.NewMockLoanService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{
+		UserID:   "53297921-01d9-4311-94f3-54cbb971c5a0",
+		UserName: "John Doe",
+		UserType: "EMPLOYEE",
+	})
+	mockLoanService.EXPECT().ApproveLoanApplication(gomock.Any(), gomock.Any(), mockRepaymentService).Return(dto.ApplicationApproveResponse{
+		Status: 2,
+		Data: dto.ApproveObject{
+			ApplicationID:   "2e560272-45eb-442e-9a67-a2f8c423e063",
+			PendingApprover: "7a1d0a2e-5b8e-4f7c-9d0a-0f3e8a6b1c22",
+			ApprovalStep:    1,
+		},
+		Message: "Loan application sent to the next-level approver",
+	}, dto.HandleError{})
+
+	app := fiber.New()
+	app.Post("/v1/application/:applicationId/approve", func(c *fiber.Ctx) error {
+		return ApproveLoanApplication(c, mockLoanService, mockUserService, mockRepaymentService)
+	})
+
+	requestBody, _ := json.Marshal(dto.ApplicationApproveRequest{ApprovedAmount: 100000})
+	req := httptest.NewRequest(http.MethodPost, "/v1/application/2e560272-45eb-442e-9a67-a2f8c423e063/approve", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "EMPLOYEE_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+
+	// The application stays pending until the next-level approver signs it off
+	assert.Equal(t, float64(2), response["status"].(float64))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "7a1d0a2e-5b8e-4f7c-9d0a-0f3e8a6b1c22", data["pending_approver"])
+	assert.Equal(t, float64(1), data["approval_step"])
+}
+
+func TestApproveLoanApplication_OverrideAwaitingChecker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoanService := loanSvc.NewMockLoanService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{
+		UserID:   "53297921-01d9-4311-94f3-54cbb971c5a0",
+		UserName: "John Doe",
+		UserType: "EMPLOYEE",
+	})
+	mockLoanService.EXPECT().ApproveLoanApplication(gomock.Any(), gomock.Any(), mockRepaymentService).Return(dto.ApplicationApproveResponse{
+		Status: 3,
+		Data: dto.ApproveObject{
+			ApplicationID: "2e560272-45eb-442e-9a67-a2f8c423e063",
+			ApprovalStep:  1,
+			ActionID:      "c6f1b7c4-3d4e-4b8a-9f51-6a3f0d2e9b10",
+		},
+		Message: "Loan application override sent to a checker",
+	}, dto.HandleError{})
+
+	app := fiber.New()
+	app.Post("/v1/application/:applicationId/approve", func(c *fiber.Ctx) error {
+		return ApproveLoanApplication(c, mockLoanService, mockUserService, mockRepaymentService)
+	})
+
+	requestBody, _ := json.Marshal(dto.ApplicationApproveRequest{ApprovedAmount: 100000, Override: true})
+	req := httptest.NewRequest(http.MethodPost, "/v1/application/2e560272-45eb-442e-9a67-a2f8c423e063/approve", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "EMPLOYEE_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+
+	// The override is only applied once a checker approves the pending action
+	assert.Equal(t, float64(3), response["status"].(float64))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "c6f1b7c4-3d4e-4b8a-9f51-6a3f0d2e9b10", data["action_id"])
+}
+
 func TestApproveLoanApplication_InvalidRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -197,3 +294,100 @@ func TestApproveLoanApplication_InvalidRequest(t *testing.T) {
 	assert.Equal(t, float64(-1), response["status"].(float64))
 	assert.NotEmpty(t, response["error"])
 }
+
+func TestGetEligibilityConfigs_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoanService := loanSvc.NewMockLoanService(ctrl)
+
+	response := dto.EligibilityConfigListResponse{Status: 1, Message: "Total 1 config(s) found"}
+	response.Data.Configs = []dto.EligibilityConfigObject{{ConfigID: 1, CountryCode: "IN"}}
+	mockLoanService.EXPECT().GetEligibilityConfigs().Return(response, dto.HandleError{})
+
+	app := fiber.New()
+	app.Get("/v1/eligibility-configs", func(c *fiber.Ctx) error {
+		return GetEligibilityConfigs(c, mockLoanService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/eligibility-configs", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+	configs := body["data"].(map[string]interface{})["configs"].([]interface{})
+	assert.Len(t, configs, 1)
+}
+
+func TestProposeEligibilityConfig_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoanService := loanSvc.NewMockLoanService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	request := dto.EligibilityConfigRequest{
+		ConfigID:                1,
+		MinCreditScore:          700,
+		MaxCreditScore:          900,
+		MaxFoir:                 0.5,
+		BaseLoanAmount:          500000,
+		CreditScoreFactorHigh:   1.5,
+		CreditScoreFactorMedium: 1.2,
+		CreditScoreFactorLow:    1,
+	}
+	response := dto.PendingActionResponse{Status: 1, Message: "Eligibility config change sent to a checker"}
+	response.Data = dto.PendingActionObject{ActionID: "action_id", Status: models.PendingActionStatusPending}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "maker_id"})
+	mockLoanService.EXPECT().ProposeEligibilityConfig(request, models.User{UserID: "maker_id"}).
+		Return(response, dto.HandleError{})
+
+	app := fiber.New()
+	app.Put("/v1/eligibility-configs/:configId", func(c *fiber.Ctx) error {
+		return ProposeEligibilityConfig(c, mockLoanService, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPut, "/v1/eligibility-configs/1", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+	assert.Equal(t, "PENDING", body["data"].(map[string]interface{})["status"])
+}
+
+func TestProposeEligibilityConfig_InvalidFoir(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoanService := loanSvc.NewMockLoanService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Put("/v1/eligibility-configs/:configId", func(c *fiber.Ctx) error {
+		return ProposeEligibilityConfig(c, mockLoanService, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(map[string]interface{}{
+		"min_credit_score":           700,
+		"max_credit_score":           900,
+		"max_foir":                   1.5,
+		"base_loan_amount":           500000,
+		"credit_score_factor_high":   1.5,
+		"credit_score_factor_medium": 1.2,
+		"credit_score_factor_low":    1,
+	})
+	req := httptest.NewRequest(http.MethodPut, "/v1/eligibility-configs/1", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -0,0 +1,190 @@
+package mandate_controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	debitService "github.com/nishanthrk/aspire-lms/app/services/debit"
+	mandateService "github.com/nishanthrk/aspire-lms/app/services/mandate"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+)
+
+// RegisterMandate handles registering a recurring debit mandate for a loan application
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - mandateService: mandateService.MandateService for handling mandate-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the mandate details
+func RegisterMandate(c *fiber.Ctx, mandateService mandateService.MandateService, userService userService.UserService) error {
+	// Initialize a MandateCreateRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.MandateCreateRequest{}
+	params.ApplicationID = c.Params("applicationId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the mandateService to register the mandate
+	response, handle := mandateService.RegisterMandate(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the mandate details
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetMandate handles fetching the debit mandate of a loan application with its collection attempts
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - mandateService: mandateService.MandateService for handling mandate-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the mandate details
+func GetMandate(c *fiber.Ctx, mandateService mandateService.MandateService, userService userService.UserService) error {
+	// Initialize a MandateDetailsRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.MandateDetailsRequest{
+		ApplicationID: c.Params("applicationId"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the mandateService to fetch the mandate
+	response, handle := mandateService.GetMandate(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the mandate details
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// CancelMandate handles cancelling the active debit mandate of a loan application
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - mandateService: mandateService.MandateService for handling mandate-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the mandate details
+func CancelMandate(c *fiber.Ctx, mandateService mandateService.MandateService, userService userService.UserService) error {
+	// Initialize a MandateCancelRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.MandateCancelRequest{
+		ApplicationID: c.Params("applicationId"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the mandateService to cancel the mandate
+	response, handle := mandateService.CancelMandate(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the mandate details
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// DebitCallback handles collection outcome callbacks from the debit provider
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - mandateService: mandateService.MandateService for handling mandate-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response acknowledging the callback
+func DebitCallback(c *fiber.Ctx, mandateService mandateService.MandateService) error {
+	// Initialize a DebitCallbackRequest DTO to hold the callback payload
+	params := dto.DebitCallbackRequest{}
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the mandateService to verify the signature and apply the outcome
+	response, handle := mandateService.HandleDebitCallback(params, c.Body(), c.Get(debitService.SignatureHeader))
+	if handle.Status == -1 {
+		// Return a 401 Unauthorized status when the signature does not verify
+		return c.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status acknowledging the callback
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// RunCollections handles running a debit collection cycle on demand, outside the scheduler
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - mandateService: mandateService.MandateService for handling mandate-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the cycle summary
+func RunCollections(c *fiber.Ctx, mandateService mandateService.MandateService) error {
+	// Call the mandateService to run the collection cycle
+	response, handle := mandateService.RunCollections(time.Now())
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the cycle summary
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,240 @@
+package mandate_controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	debitSvc "github.com/nishanthrk/aspire-lms/app/services/debit"
+	mandateSvc "github.com/nishanthrk/aspire-lms/app/services/mandate"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMandate_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	request := dto.MandateCreateRequest{
+		AccountHolderName: "Jane Doe",
+		BankAccountNumber: "123456789012",
+		BankCode:          "HDFC0001234",
+		MaxAmount:         5000,
+		Frequency:         models.MandateFrequencyMonthly,
+		StartDate:         "2024-05-01",
+		EndDate:           "2025-05-01",
+	}
+	requestBody, _ := json.Marshal(request)
+	request.ApplicationID = "application_id"
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockMandateService.EXPECT().RegisterMandate(request, models.User{UserID: "user_id"}).Return(dto.MandateResponse{
+		Status:  1,
+		Message: "Debit mandate registered successfully",
+		Data: dto.MandateObject{
+			MandateId:         "mandate_id",
+			BankAccountNumber: "XXXX9012",
+			Status:            models.MandateStatusActive,
+		},
+	}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/mandate", func(c *fiber.Ctx) error {
+		return RegisterMandate(c, mockMandateService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/mandate", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "XXXX9012", data["bank_account_number"])
+}
+
+func TestRegisterMandate_InvalidFrequency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	requestBody, _ := json.Marshal(dto.MandateCreateRequest{
+		AccountHolderName: "Jane Doe",
+		BankAccountNumber: "123456789012",
+		BankCode:          "HDFC0001234",
+		MaxAmount:         5000,
+		Frequency:         "DAILY",
+		StartDate:         "2024-05-01",
+		EndDate:           "2025-05-01",
+	})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/mandate", func(c *fiber.Ctx) error {
+		return RegisterMandate(c, mockMandateService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/mandate", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestRegisterMandate_InvalidDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	requestBody, _ := json.Marshal(dto.MandateCreateRequest{
+		AccountHolderName: "Jane Doe",
+		BankAccountNumber: "123456789012",
+		BankCode:          "HDFC0001234",
+		MaxAmount:         5000,
+		Frequency:         models.MandateFrequencyWeekly,
+		StartDate:         "01/05/2024",
+		EndDate:           "2025-05-01",
+	})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/mandate", func(c *fiber.Ctx) error {
+		return RegisterMandate(c, mockMandateService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/mandate", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestCancelMandate_NoActiveMandate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockMandateService.EXPECT().CancelMandate(dto.MandateCancelRequest{ApplicationID: "application_id"}, gomock.Any()).
+		Return(dto.MandateResponse{}, dto.HandleError{
+			Status: -2,
+			Errors: fmt.Errorf("application application_id has no active debit mandate"),
+		})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/mandate/cancel", func(c *fiber.Ctx) error {
+		return CancelMandate(c, mockMandateService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/mandate/cancel", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, "application application_id has no active debit mandate", responseBody["error"])
+}
+
+func TestDebitCallback_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+
+	requestBody, _ := json.Marshal(dto.DebitCallbackRequest{
+		Reference:   "dd_reference",
+		Status:      models.PaymentStatusFailed,
+		FailureCode: debitSvc.FailureCodeInsufficientFunds,
+	})
+
+	mockMandateService.EXPECT().HandleDebitCallback(gomock.Any(), requestBody, "signature").Return(dto.DebitCallbackResponse{
+		Status:  1,
+		Message: "Debit marked as FAILED",
+	}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/webhooks/debit", func(c *fiber.Ctx) error {
+		return DebitCallback(c, mockMandateService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/debit", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(debitSvc.SignatureHeader, "signature")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestDebitCallback_InvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+	mockMandateService.EXPECT().HandleDebitCallback(gomock.Any(), gomock.Any(), "").Return(dto.DebitCallbackResponse{}, dto.HandleError{
+		Status: -1,
+		Errors: fmt.Errorf("invalid callback signature"),
+	})
+
+	app := fiber.New()
+	app.Post("/webhooks/debit", func(c *fiber.Ctx) error {
+		return DebitCallback(c, mockMandateService)
+	})
+
+	requestBody, _ := json.Marshal(dto.DebitCallbackRequest{
+		Reference: "dd_reference",
+		Status:    models.PaymentStatusSettled,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/debit", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRunCollections_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMandateService := mandateSvc.NewMockMandateService(ctrl)
+
+	response := dto.CollectionRunResponse{Status: 1, Message: "Debit collections processed"}
+	response.Data.CollectionsRaised = 2
+	mockMandateService.EXPECT().RunCollections(gomock.Any()).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/mandate/collections/run", func(c *fiber.Ctx) error {
+		return RunCollections(c, mockMandateService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/mandate/collections/run", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["collections_raised"])
+}
@@ -0,0 +1,235 @@
+package provision_controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	provision "github.com/nishanthrk/aspire-lms/app/services/provision"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RunProvisioning handles a provisioning run without waiting for the scheduler
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?as_of=" is the month end to measure at, the last one by default
+// - provisionService: provision.ProvisionService for running the provisioning
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the run totals
+func RunProvisioning(c *fiber.Ctx, provisionService provision.ProvisionService, userService userService.UserService) error {
+	// Initialize a ProvisionRunRequest DTO from the query string
+	params := dto.ProvisionRunRequest{
+		AsOf: c.Query("as_of"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	asOf := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	if params.AsOf != "" {
+		asOf, _ = time.ParseInLocation("2006-01-02", params.AsOf, time.Local)
+	}
+
+	// A month end still in progress has no closing balances to measure
+	if !asOf.Before(today) {
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  "provisions can only be run for month ends that have ended",
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the provisionService to run the provisioning
+	response, handle := provisionService.RunProvisioning(asOf, user.UserID)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the run totals
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetProvisionRuns handles the history of provisioning runs
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - provisionService: provision.ProvisionService for handling provisioning operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the runs
+func GetProvisionRuns(c *fiber.Ctx, provisionService provision.ProvisionService) error {
+	// Call the provisionService to list the runs
+	response, handle := provisionService.GetProvisionRuns()
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the runs
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetProvisionRun handles a provisioning run with the provision of every loan
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - provisionService: provision.ProvisionService for handling provisioning operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the run
+func GetProvisionRun(c *fiber.Ctx, provisionService provision.ProvisionService) error {
+	// Initialize a ProvisionRunDetailRequest DTO from the URL parameters
+	params := dto.ProvisionRunDetailRequest{
+		RunID: c.Params("runId"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the provisionService to load the run
+	response, handle := provisionService.GetProvisionRun(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the run
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetParameters handles the listing of the PD/LGD parameters
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?country=" and "?product=" narrow the listing
+// - provisionService: provision.ProvisionService for handling provisioning operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the parameters
+func GetParameters(c *fiber.Ctx, provisionService provision.ProvisionService) error {
+	// Initialize a ProvisionParameterListRequest DTO from the query string
+	params := dto.ProvisionParameterListRequest{
+		CountryCode: strings.ToUpper(c.Query("country")),
+		ProductCode: strings.ToUpper(c.Query("product")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the provisionService to list the parameters
+	response, handle := provisionService.GetParameters(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the parameters
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// SetParameter handles creating or replacing the PD/LGD parameters of a country, product and stage
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - provisionService: provision.ProvisionService for handling provisioning operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the parameters
+func SetParameter(c *fiber.Ctx, provisionService provision.ProvisionService, userService userService.UserService) error {
+	// Initialize a ProvisionParameterRequest DTO
+	params := dto.ProvisionParameterRequest{}
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+	params.CountryCode = strings.ToUpper(params.CountryCode)
+	params.ProductCode = strings.ToUpper(params.ProductCode)
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the provisionService to store the parameters
+	response, handle := provisionService.SetParameter(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the parameters
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// SetRestructured handles flagging a loan as restructured or clearing the flag
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - provisionService: provision.ProvisionService for handling provisioning operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the flag
+func SetRestructured(c *fiber.Ctx, provisionService provision.ProvisionService, userService userService.UserService) error {
+	// Initialize a RestructureRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.RestructureRequest{}
+	params.ApplicationID = c.Params("applicationId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the provisionService to set the flag
+	response, handle := provisionService.SetRestructured(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the flag
+	return c.Status(http.StatusOK).JSON(response)
+}
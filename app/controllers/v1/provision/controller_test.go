@@ -0,0 +1,235 @@
+package provision_controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	provisionSvc "github.com/nishanthrk/aspire-lms/app/services/provision"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRunProvisioning_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.ProvisionRunResponse{Status: 1}
+	response.Data.AsOf = "2024-03-31"
+	response.Data.Totals = []dto.ProvisionTotal{{CurrencyCode: "INR", Stage: 1, Count: 2, Ecl: "₹90.00"}}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockProvisionService.EXPECT().RunProvisioning(time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local), "user_id").
+		Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/provisions/runs", func(c *fiber.Ctx) error {
+		return RunProvisioning(c, mockProvisionService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/provisions/runs?as_of=2024-03-31", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	totals := data["totals"].([]interface{})
+	assert.Equal(t, "₹90.00", totals[0].(map[string]interface{})["ecl"])
+}
+
+func TestRunProvisioning_CurrentMonthRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/provisions/runs", func(c *fiber.Ctx) error {
+		return RunProvisioning(c, mockProvisionService, mockUserService)
+	})
+
+	now := time.Now()
+	monthEnd := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+	req := httptest.NewRequest(http.MethodPost, "/provisions/runs?as_of="+monthEnd.Format("2006-01-02"), nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, "provisions can only be run for month ends that have ended", responseBody["error"])
+}
+
+func TestRunProvisioning_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockProvisionService.EXPECT().RunProvisioning(gomock.Any(), "user_id").Return(dto.ProvisionRunResponse{},
+		dto.HandleError{Status: -7, Errors: fmt.Errorf("no PD/LGD parameters for country IND, product TERM_LOAN, stage 2")})
+
+	app := fiber.New()
+	app.Post("/provisions/runs", func(c *fiber.Ctx) error {
+		return RunProvisioning(c, mockProvisionService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/provisions/runs?as_of=2024-02-29", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-7), responseBody["status"])
+}
+
+func TestGetProvisionRun_InvalidRunId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+
+	app := fiber.New()
+	app.Get("/provisions/runs/:runId", func(c *fiber.Ctx) error {
+		return GetProvisionRun(c, mockProvisionService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/provisions/runs/not-a-run", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSetParameter_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	request := dto.ProvisionParameterRequest{
+		CountryCode:          "IND",
+		ProductCode:          "TERM_LOAN",
+		Stage:                2,
+		ProbabilityOfDefault: 0.25,
+		LossGivenDefault:     0.4,
+	}
+	response := dto.ProvisionParameterResponse{Status: 1}
+	response.Data.Parameters = []dto.ProvisionParameterObject{{CountryCode: "IND", ProductCode: "TERM_LOAN", Stage: 2,
+		ProbabilityOfDefault: 0.25, LossGivenDefault: 0.4}}
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockProvisionService.EXPECT().SetParameter(request, models.User{UserID: "user_id"}).
+		Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Put("/provisions/parameters", func(c *fiber.Ctx) error {
+		return SetParameter(c, mockProvisionService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"country_code":           "ind",
+		"product_code":           "term_loan",
+		"stage":                  2,
+		"probability_of_default": 0.25,
+		"loss_given_default":     0.4,
+	})
+	req := httptest.NewRequest(http.MethodPut, "/provisions/parameters", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSetParameter_InvalidProbability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Put("/provisions/parameters", func(c *fiber.Ctx) error {
+		return SetParameter(c, mockProvisionService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"country_code":           "IND",
+		"product_code":           "TERM_LOAN",
+		"stage":                  4,
+		"probability_of_default": 1.5,
+		"loss_given_default":     0.4,
+	})
+	req := httptest.NewRequest(http.MethodPut, "/provisions/parameters", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSetRestructured_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.RestructureResponse{Status: 1}
+	response.Data.ApplicationId = "application_id"
+	response.Data.Restructured = true
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockProvisionService.EXPECT().SetRestructured(gomock.Any(), models.User{UserID: "user_id"}).
+		DoAndReturn(func(request dto.RestructureRequest, user models.User) (dto.RestructureResponse, dto.HandleError) {
+			assert.Equal(t, "application_id", request.ApplicationID)
+			assert.True(t, *request.Restructured)
+			return response, dto.HandleError{Status: 1}
+		})
+
+	app := fiber.New()
+	app.Put("/application/:applicationId/restructured", func(c *fiber.Ctx) error {
+		return SetRestructured(c, mockProvisionService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"restructured": true})
+	req := httptest.NewRequest(http.MethodPut, "/application/application_id/restructured", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSetRestructured_MissingFlag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvisionService := provisionSvc.NewMockProvisionService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Put("/application/:applicationId/restructured", func(c *fiber.Ctx) error {
+		return SetRestructured(c, mockProvisionService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/application/application_id/restructured", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
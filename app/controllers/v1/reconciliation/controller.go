@@ -0,0 +1,126 @@
+package reconciliation_controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	reconciliation "github.com/nishanthrk/aspire-lms/app/services/reconciliation"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// ImportStatement handles the upload of a bank statement file for reconciliation
+// Parameters:
+// - c: *fiber.Ctx representing the request context, carrying the statement as the multipart "file" field
+// - reconciliationService: reconciliation.ReconciliationService for handling reconciliation operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the import summary
+func ImportStatement(c *fiber.Ctx, reconciliationService reconciliation.ReconciliationService,
+	userService userService.UserService) error {
+	// Initialize a StatementImportRequest DTO with the optional format form value
+	params := dto.StatementImportRequest{
+		Format: strings.ToUpper(c.FormValue("format")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Read the uploaded statement file
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  "statement file is required",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  fmt.Sprintf("statement file could not be read: %v", err),
+		})
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  fmt.Sprintf("statement file could not be read: %v", err),
+		})
+	}
+	params.FileName = filepath.Base(fileHeader.Filename)
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the reconciliationService to import the statement
+	response, handle := reconciliationService.ImportStatement(params, content, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the import summary
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetStatementExceptions handles the exceptions report of an imported bank statement
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?format=csv" downloads the report as CSV
+// - reconciliationService: reconciliation.ReconciliationService for handling reconciliation operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns the exceptions as JSON or CSV
+func GetStatementExceptions(c *fiber.Ctx, reconciliationService reconciliation.ReconciliationService) error {
+	// Initialize a StatementExceptionRequest DTO from the URL
+	params := dto.StatementExceptionRequest{
+		StatementID: c.Params("statementId"),
+		Format:      strings.ToLower(c.Query("format")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the reconciliationService to load the exceptions
+	response, handle := reconciliationService.GetStatementExceptions(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	if params.Format == "csv" {
+		// Return the report as a CSV download
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition,
+			fmt.Sprintf("attachment; filename=\"exceptions-%v.csv\"", response.Data.StatementId))
+		c.Status(http.StatusOK)
+		return reconciliation.WriteExceptionReport(c, response.Data.Exceptions)
+	}
+
+	// Return a 200 OK status with the exceptions
+	return c.Status(http.StatusOK).JSON(response)
+}
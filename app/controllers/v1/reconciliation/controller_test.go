@@ -0,0 +1,193 @@
+package reconciliation_controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	reconciliationSvc "github.com/nishanthrk/aspire-lms/app/services/reconciliation"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newStatementUpload builds a multipart request carrying a statement file and an optional format
+func newStatementUpload(t *testing.T, fileName string, content string, format string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if format != "" {
+		assert.NoError(t, writer.WriteField("format", format))
+	}
+	if fileName != "" {
+		part, err := writer.CreateFormFile("file", fileName)
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/reconciliation/statement", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportStatement_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationService := reconciliationSvc.NewMockReconciliationService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	content := "amount,currency\n100,INR\n"
+	response := dto.StatementImportResponse{Status: 1, Message: "Bank statement imported successfully"}
+	response.Data.StatementId = "statement_id"
+	response.Data.TotalLines = 1
+	response.Data.PostedLines = 1
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockReconciliationService.EXPECT().ImportStatement(dto.StatementImportRequest{
+		FileName: "statement.csv",
+		Format:   models.StatementFormatCSV,
+	}, []byte(content), models.User{UserID: "user_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/reconciliation/statement", func(c *fiber.Ctx) error {
+		return ImportStatement(c, mockReconciliationService, mockUserService)
+	})
+
+	resp, err := app.Test(newStatementUpload(t, "statement.csv", content, "csv"), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "statement_id", data["statement_id"])
+	assert.Equal(t, float64(1), data["posted_lines"])
+}
+
+func TestImportStatement_MissingFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationService := reconciliationSvc.NewMockReconciliationService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/reconciliation/statement", func(c *fiber.Ctx) error {
+		return ImportStatement(c, mockReconciliationService, mockUserService)
+	})
+
+	resp, err := app.Test(newStatementUpload(t, "", "", ""), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestImportStatement_InvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationService := reconciliationSvc.NewMockReconciliationService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/reconciliation/statement", func(c *fiber.Ctx) error {
+		return ImportStatement(c, mockReconciliationService, mockUserService)
+	})
+
+	resp, err := app.Test(newStatementUpload(t, "statement.txt", "amount,currency\n", "mt940"), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestImportStatement_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationService := reconciliationSvc.NewMockReconciliationService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockReconciliationService.EXPECT().ImportStatement(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		dto.StatementImportResponse{}, dto.HandleError{
+			Status: -1,
+			Errors: fmt.Errorf("CSV statement has no currency column"),
+		})
+
+	app := fiber.New()
+	app.Post("/reconciliation/statement", func(c *fiber.Ctx) error {
+		return ImportStatement(c, mockReconciliationService, mockUserService)
+	})
+
+	resp, err := app.Test(newStatementUpload(t, "statement.csv", "amount\n100\n", ""), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, "CSV statement has no currency column", responseBody["error"])
+}
+
+func TestGetStatementExceptions_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationService := reconciliationSvc.NewMockReconciliationService(ctrl)
+
+	response := dto.StatementExceptionResponse{Status: 1}
+	response.Data.StatementId = "statement_id"
+	response.Data.Exceptions = []dto.StatementException{{
+		LineNumber:   2,
+		Amount:       "100.00",
+		CurrencyCode: "INR",
+		Reason:       "no loan matches the virtual account, payment reference or amount",
+	}}
+
+	mockReconciliationService.EXPECT().GetStatementExceptions(dto.StatementExceptionRequest{
+		StatementID: "statement_id",
+		Format:      "csv",
+	}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/reconciliation/statement/:statementId/exceptions", func(c *fiber.Ctx) error {
+		return GetStatementExceptions(c, mockReconciliationService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/reconciliation/statement/statement_id/exceptions?format=CSV", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "2,,100.00,INR,,,,,,,\"no loan matches")
+}
+
+func TestGetStatementExceptions_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationService := reconciliationSvc.NewMockReconciliationService(ctrl)
+	mockReconciliationService.EXPECT().GetStatementExceptions(gomock.Any()).Return(
+		dto.StatementExceptionResponse{}, dto.HandleError{
+			Status: -1,
+			Errors: fmt.Errorf("statement unknown not found"),
+		})
+
+	app := fiber.New()
+	app.Get("/reconciliation/statement/:statementId/exceptions", func(c *fiber.Ctx) error {
+		return GetStatementExceptions(c, mockReconciliationService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/reconciliation/statement/unknown/exceptions", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/nishanthrk/aspire-lms/app/common/validator"
 	"github.com/nishanthrk/aspire-lms/app/dto"
+	gatewayService "github.com/nishanthrk/aspire-lms/app/services/gateway"
 	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
 	userService "github.com/nishanthrk/aspire-lms/app/services/user"
 	"net/http"
@@ -47,3 +48,160 @@ func PayRepayment(c *fiber.Ctx, repaymentService repaymentService.RepaymentServi
 	// Return a 200 OK status with the repayment details
 	return c.Status(http.StatusOK).JSON(response)
 }
+
+// ReversePayment handles the reversal of a received payment, e.g. a bounced cheque or a failed debit
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - repaymentService: repaymentService.RepaymentService for handling repayment-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the reversal details
+func ReversePayment(c *fiber.Ctx, repaymentService repaymentService.RepaymentService, userService userService.UserService) error {
+	// Initialize a PaymentReversalRequest DTO and set the IDs from the URL parameters
+	params := dto.PaymentReversalRequest{}
+	params.ApplicationID = c.Params("applicationId")
+	params.PaymentID = c.Params("paymentId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the repaymentService to reverse the payment
+	response, handle := repaymentService.ReversePayment(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the reversal details
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// RefundExcessCredit handles refunding the excess credit left on a loan application
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - repaymentService: repaymentService.RepaymentService for handling repayment-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the refund details
+func RefundExcessCredit(c *fiber.Ctx, repaymentService repaymentService.RepaymentService, userService userService.UserService) error {
+	// Initialize a RefundRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.RefundRequest{}
+	params.ApplicationID = c.Params("applicationId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the repaymentService to refund the excess credit
+	response, handle := repaymentService.RefundExcessCredit(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the refund details
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GatewayWebhook handles payment status callbacks from the payment gateway
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - repaymentService: repaymentService.RepaymentService for handling repayment-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response acknowledging the callback
+func GatewayWebhook(c *fiber.Ctx, repaymentService repaymentService.RepaymentService) error {
+	// Initialize a GatewayWebhookRequest DTO to hold the callback payload
+	params := dto.GatewayWebhookRequest{}
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the repaymentService to verify the signature and apply the payment status
+	response, handle := repaymentService.HandleGatewayWebhook(params, c.Body(), c.Get(gatewayService.SignatureHeader))
+	if handle.Status == -1 {
+		// Return a 401 Unauthorized status when the signature does not verify
+		return c.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status acknowledging the callback
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// SyncPaymentStatus handles polling the gateway for a payment whose callback was not received
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - repaymentService: repaymentService.RepaymentService for handling repayment-related operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the payment status
+func SyncPaymentStatus(c *fiber.Ctx, repaymentService repaymentService.RepaymentService, userService userService.UserService) error {
+	// Initialize a PaymentSyncRequest DTO and set the IDs from the URL parameters
+	params := dto.PaymentSyncRequest{
+		ApplicationID: c.Params("applicationId"),
+		PaymentID:     c.Params("paymentId"),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the repaymentService to synchronise the payment status
+	response, handle := repaymentService.SyncPaymentStatus(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the payment status
+	return c.Status(http.StatusOK).JSON(response)
+}
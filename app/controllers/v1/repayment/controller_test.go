@@ -3,10 +3,12 @@ package repayment_controller
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/nishanthrk/aspire-lms/app/dto"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	gatewaySvc "github.com/nishanthrk/aspire-lms/app/services/gateway"
 	repaymentSvc "github.com/nishanthrk/aspire-lms/app/services/repayment"
 	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
 	"github.com/stretchr/testify/assert"
@@ -26,9 +28,14 @@ func TestPayRepayment_Success(t *testing.T) {
 		Status:  1,
 		Message: "Payment process successfully",
 		Data: struct {
-			PaymentId string `json:"payment_id"`
+			PaymentId        string `json:"payment_id"`
+			PaymentStatus    string `json:"payment_status,omitempty"`
+			GatewayReference string `json:"gateway_reference,omitempty"`
+			CheckoutURL      string `json:"checkout_url,omitempty"`
 		}{
-			PaymentId: "payment_id",
+			PaymentId:        "payment_id",
+			PaymentStatus:    models.PaymentStatusPending,
+			GatewayReference: "fake_reference",
 		},
 	}, dto.HandleError{
 		Status: 1,
@@ -64,6 +71,8 @@ func TestPayRepayment_Success(t *testing.T) {
 	assert.Equal(t, "Payment process successfully", response["message"].(string))
 	data := response["data"].(map[string]interface{})
 	assert.NotEmpty(t, data["payment_id"])
+	assert.Equal(t, models.PaymentStatusPending, data["payment_status"])
+	assert.Equal(t, "fake_reference", data["gateway_reference"])
 }
 
 func TestPayRepayment_InvalidRequest(t *testing.T) {
@@ -92,3 +101,214 @@ func TestPayRepayment_InvalidRequest(t *testing.T) {
 	assert.Equal(t, float64(-1), response["status"].(float64))
 	assert.NotEmpty(t, response["error"])
 }
+
+func TestReversePayment_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.PaymentReversalResponse{
+		Status:  1,
+		Message: "Payment reversed successfully",
+	}
+	response.Data.PaymentId = "payment_id"
+	response.Data.Status = models.PaymentStatusReversed
+
+	mockRepaymentService.EXPECT().ReversePayment(dto.PaymentReversalRequest{
+		ApplicationID: "application_id",
+		PaymentID:     "payment_id",
+		Reason:        "Cheque bounced",
+	}, gomock.Any()).Return(response, dto.HandleError{
+		Status: 1,
+	})
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{
+		UserID: "user_id",
+	})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/payment/:paymentId/reverse", func(c *fiber.Ctx) error {
+		return ReversePayment(c, mockRepaymentService, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(map[string]string{"reason": "Cheque bounced"})
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/payment/payment_id/reverse", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(1), body["status"].(float64))
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "payment_id", data["payment_id"])
+	assert.Equal(t, models.PaymentStatusReversed, data["status"])
+}
+
+func TestReversePayment_MissingReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/payment/:paymentId/reverse", func(c *fiber.Ctx) error {
+		return ReversePayment(c, mockRepaymentService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/payment/payment_id/reverse", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestRefundExcessCredit_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockRepaymentService.EXPECT().RefundExcessCredit(gomock.Any(), gomock.Any()).Return(dto.RefundResponse{}, dto.HandleError{
+		Status: -4,
+		Errors: fmt.Errorf("refund exceeds the available excess credit of ₹0.00"),
+	})
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{
+		UserID: "user_id",
+	})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/refund", func(c *fiber.Ctx) error {
+		return RefundExcessCredit(c, mockRepaymentService, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.RefundRequest{Amount: 100})
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/refund", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(-4), body["status"].(float64))
+	assert.NotEmpty(t, body["error"])
+}
+
+func TestGatewayWebhook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+
+	requestBody, _ := json.Marshal(dto.GatewayWebhookRequest{
+		Reference: "fake_reference",
+		Status:    models.PaymentStatusSettled,
+		Amount:    1000,
+	})
+
+	mockRepaymentService.EXPECT().HandleGatewayWebhook(gomock.Any(), requestBody, "signature").Return(dto.GatewayWebhookResponse{
+		Status:  1,
+		Message: "Payment marked as SETTLED",
+	}, dto.HandleError{
+		Status: 1,
+	})
+
+	app := fiber.New()
+	app.Post("/webhooks/payment", func(c *fiber.Ctx) error {
+		return GatewayWebhook(c, mockRepaymentService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(gatewaySvc.SignatureHeader, "signature")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGatewayWebhook_InvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+	mockRepaymentService.EXPECT().HandleGatewayWebhook(gomock.Any(), gomock.Any(), "").Return(dto.GatewayWebhookResponse{}, dto.HandleError{
+		Status: -1,
+		Errors: fmt.Errorf("invalid webhook signature"),
+	})
+
+	app := fiber.New()
+	app.Post("/webhooks/payment", func(c *fiber.Ctx) error {
+		return GatewayWebhook(c, mockRepaymentService)
+	})
+
+	requestBody, _ := json.Marshal(dto.GatewayWebhookRequest{
+		Reference: "fake_reference",
+		Status:    models.PaymentStatusFailed,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestGatewayWebhook_UnknownStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+
+	app := fiber.New()
+	app.Post("/webhooks/payment", func(c *fiber.Ctx) error {
+		return GatewayWebhook(c, mockRepaymentService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payment",
+		bytes.NewReader([]byte(`{"reference":"fake_reference","status":"RECEIVED"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestGatewayWebhook_SettledWithoutAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepaymentService := repaymentSvc.NewMockRepaymentService(ctrl)
+
+	app := fiber.New()
+	app.Post("/webhooks/payment", func(c *fiber.Ctx) error {
+		return GatewayWebhook(c, mockRepaymentService)
+	})
+
+	// A settlement has to state the amount settled, it is never taken for granted
+	requestBody, _ := json.Marshal(dto.GatewayWebhookRequest{
+		Reference: "fake_reference",
+		Status:    models.PaymentStatusSettled,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(gatewaySvc.SignatureHeader, "signature")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -0,0 +1,32 @@
+package signing_controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	signing "github.com/nishanthrk/aspire-lms/app/services/signing"
+	"net/http"
+)
+
+// keySetMaxAge is how long other services may cache the key set, well within the overlap of two keys
+const keySetMaxAge = "public, max-age=300"
+
+// GetKeySet handles the publication of the keys access tokens are verified with
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - signingService: signing.SigningService for reading the keys
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns the JSON Web Key Set
+func GetKeySet(c *fiber.Ctx, signingService signing.SigningService) error {
+	// Call the signingService to read the published keys
+	response, handle := signingService.GetKeySet()
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the key set, it may be cached by the services verifying tokens
+	c.Set(fiber.HeaderCacheControl, keySetMaxAge)
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,72 @@
+package signing_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	signingSvc "github.com/nishanthrk/aspire-lms/app/services/signing"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetKeySet_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSigningService := signingSvc.NewMockSigningService(ctrl)
+
+	response := dto.JsonWebKeySet{Keys: []dto.JsonWebKey{
+		{Kty: "EC", Use: "sig", Alg: "ES256", Kid: "key_id", Crv: "P-256", X: "x", Y: "y"},
+	}}
+	mockSigningService.EXPECT().GetKeySet().Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/.well-known/jwks.json", func(c *fiber.Ctx) error {
+		return GetKeySet(c, mockSigningService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=300", resp.Header.Get(fiber.HeaderCacheControl))
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	keys := responseBody["keys"].([]interface{})
+	assert.Len(t, keys, 1)
+	key := keys[0].(map[string]interface{})
+	assert.Equal(t, "key_id", key["kid"])
+	assert.Equal(t, "ES256", key["alg"])
+	assert.Equal(t, "P-256", key["crv"])
+	assert.NotContains(t, key, "n")
+}
+
+func TestGetKeySet_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSigningService := signingSvc.NewMockSigningService(ctrl)
+	mockSigningService.EXPECT().GetKeySet().
+		Return(dto.JsonWebKeySet{}, dto.HandleError{Status: -1, Errors: fmt.Errorf("database is unavailable")})
+
+	app := fiber.New()
+	app.Get("/.well-known/jwks.json", func(c *fiber.Ctx) error {
+		return GetKeySet(c, mockSigningService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderCacheControl))
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-1), responseBody["status"])
+	assert.Equal(t, "database is unavailable", responseBody["error"])
+}
@@ -0,0 +1,71 @@
+package statement_controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	statement "github.com/nishanthrk/aspire-lms/app/services/statement"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"net/http"
+	"strings"
+)
+
+// GetAccountStatement handles the statement of account of a loan for a date range
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?from=" and "?to=" bound the range and "?format=" picks
+// json, csv or pdf
+// - statementService: statement.StatementService for building the statement
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns the statement in the requested format
+func GetAccountStatement(c *fiber.Ctx, statementService statement.StatementService, userService userService.UserService) error {
+	// Initialize an AccountStatementRequest DTO from the URL
+	params := dto.AccountStatementRequest{
+		ApplicationID: c.Params("applicationId"),
+		From:          c.Query("from"),
+		To:            c.Query("to"),
+		Format:        strings.ToLower(c.Query("format")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the statementService to build the statement
+	response, handle := statementService.GetAccountStatement(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	fileName := fmt.Sprintf("statement-%v-%v-%v", response.Data.ApplicationId, response.Data.From, response.Data.To)
+	switch params.Format {
+	case "csv":
+		// Return the statement as a CSV download
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%v.csv\"", fileName))
+		c.Status(http.StatusOK)
+		return statement.WriteAccountStatementCSV(c, response.Data)
+	case "pdf":
+		// Return the statement as a PDF download
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%v.pdf\"", fileName))
+		c.Status(http.StatusOK)
+		return statement.WriteAccountStatementPDF(c, response.Data)
+	}
+
+	// Return a 200 OK status with the statement
+	return c.Status(http.StatusOK).JSON(response)
+}
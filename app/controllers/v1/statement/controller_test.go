@@ -0,0 +1,158 @@
+package statement_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	statementSvc "github.com/nishanthrk/aspire-lms/app/services/statement"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// statementResponse is a statement of a single disbursement
+func statementResponse() dto.AccountStatementResponse {
+	response := dto.AccountStatementResponse{Status: 1}
+	response.Data = dto.AccountStatement{
+		Tenant:         "ASPIRE",
+		ApplicationId:  "app_id",
+		CurrencyCode:   "USD",
+		From:           "2024-01-01",
+		To:             "2024-01-31",
+		OpeningBalance: "$0.00",
+		TotalDebits:    "$1,000.00",
+		TotalCredits:   "$0.00",
+		ClosingBalance: "$1,000.00",
+		Lines: []dto.AccountStatementLine{
+			{Date: "2024-01-05", Type: "DISBURSEMENT", Description: "Loan disbursed", Debit: "$1,000.00",
+				Balance: "$1,000.00"},
+		},
+	}
+	return response
+}
+
+// newStatementApp serves the statement endpoint with the given mocks
+func newStatementApp(mockStatementService *statementSvc.MockStatementService, mockUserService *userSvc.MockUserService) *fiber.App {
+	app := fiber.New()
+	app.Get("/application/:applicationId/statement", func(c *fiber.Ctx) error {
+		return GetAccountStatement(c, mockStatementService, mockUserService)
+	})
+	return app
+}
+
+func TestGetAccountStatement_JSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatementService := statementSvc.NewMockStatementService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockStatementService.EXPECT().GetAccountStatement(dto.AccountStatementRequest{
+		ApplicationID: "app_id",
+		From:          "2024-01-01",
+		To:            "2024-01-31",
+	}, models.User{UserID: "user_id"}).Return(statementResponse(), dto.HandleError{Status: 1})
+
+	req := httptest.NewRequest(http.MethodGet, "/application/app_id/statement?from=2024-01-01&to=2024-01-31", nil)
+	resp, err := newStatementApp(mockStatementService, mockUserService).Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "$1,000.00", data["closing_balance"])
+	assert.Len(t, data["lines"], 1)
+}
+
+func TestGetAccountStatement_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatementService := statementSvc.NewMockStatementService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockStatementService.EXPECT().GetAccountStatement(gomock.Any(), gomock.Any()).
+		Return(statementResponse(), dto.HandleError{Status: 1})
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/application/app_id/statement?from=2024-01-01&to=2024-01-31&format=CSV", nil)
+	resp, err := newStatementApp(mockStatementService, mockUserService).Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, `attachment; filename="statement-app_id-2024-01-01-2024-01-31.csv"`,
+		resp.Header.Get(fiber.HeaderContentDisposition))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "2024-01-05,DISBURSEMENT,,Loan disbursed,\"$1,000.00\"")
+}
+
+func TestGetAccountStatement_PDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatementService := statementSvc.NewMockStatementService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockStatementService.EXPECT().GetAccountStatement(gomock.Any(), gomock.Any()).
+		Return(statementResponse(), dto.HandleError{Status: 1})
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/application/app_id/statement?from=2024-01-01&to=2024-01-31&format=pdf", nil)
+	resp, err := newStatementApp(mockStatementService, mockUserService).Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/pdf", resp.Header.Get(fiber.HeaderContentType))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.True(t, strings.HasPrefix(string(body), "%PDF-"))
+}
+
+func TestGetAccountStatement_InvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatementService := statementSvc.NewMockStatementService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	for _, query := range []string{"to=2024-01-31", "from=2024-01-01&to=31-01-2024", "from=2024-01-01&to=2024-01-31&format=xml"} {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/application/app_id/statement?%v", query), nil)
+		resp, err := newStatementApp(mockStatementService, mockUserService).Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode, query)
+	}
+}
+
+func TestGetAccountStatement_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatementService := statementSvc.NewMockStatementService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockStatementService.EXPECT().GetAccountStatement(gomock.Any(), gomock.Any()).
+		Return(dto.AccountStatementResponse{}, dto.HandleError{Status: -3,
+			Errors: fmt.Errorf("dont have permission to this application: app_id")})
+
+	req := httptest.NewRequest(http.MethodGet, "/application/app_id/statement?from=2024-01-01&to=2024-01-31", nil)
+	resp, err := newStatementApp(mockStatementService, mockUserService).Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-3), responseBody["status"])
+	assert.Equal(t, "dont have permission to this application: app_id", responseBody["error"])
+}
@@ -4,8 +4,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/nishanthrk/aspire-lms/app/common/validator"
 	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
 	userService "github.com/nishanthrk/aspire-lms/app/services/user"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // Login handles user authentication by validating the provided credentials
@@ -17,8 +20,9 @@ import (
 func Login(c *fiber.Ctx, userService userService.UserService) error {
 	// Initialize a LoginRequest DTO to hold the request parameters
 	var params dto.LoginRequest
-	// Set the Platform from the request headers
+	// Set the Platform from the request headers and the address failed sign ins are counted against
 	params.Platform = c.Get("X-Platform")
+	params.IPAddress = c.IP()
 
 	// Parse and validate the request body into the params object
 	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
@@ -31,6 +35,48 @@ func Login(c *fiber.Ctx, userService userService.UserService) error {
 
 	// Call the userService to validate the provided credentials
 	response, handle := userService.ValidateCredentials(params)
+	if response.RetryAfter > 0 {
+		// Return a 429 Too Many Requests status with the wait when failed sign ins are throttled
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(response.RetryAfter, 10))
+		return c.Status(http.StatusTooManyRequests).JSON(&fiber.Map{
+			"status":      handle.Status,
+			"error":       handle.Errors.Error(),
+			"retry_after": response.RetryAfter,
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the authentication details
+	return c.JSON(response)
+}
+
+// SetPassword replaces a temporary password with one chosen by the user and signs them in
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the password could not be set; otherwise, it returns a JSON response with the authentication details
+func SetPassword(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a SetPasswordRequest DTO to hold the request parameters
+	var params dto.SetPasswordRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to set the new password
+	response, handle := userService.SetPassword(params)
 	if handle.Status < 0 {
 		// Return a 422 Unprocessable Entity status with the service error
 		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
@@ -42,3 +88,774 @@ func Login(c *fiber.Ctx, userService userService.UserService) error {
 	// Return a JSON response with the authentication details
 	return c.JSON(response)
 }
+
+// RefreshToken exchanges a refresh token for new access and refresh tokens
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the refresh token was refused; otherwise, it returns a JSON response with the new authentication details
+func RefreshToken(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a RefreshTokenRequest DTO to hold the request parameters
+	var params dto.RefreshTokenRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to rotate the refresh token
+	response, handle := userService.RefreshToken(params)
+	if handle.Status < 0 {
+		// Return a 401 Unauthorized status, the client has to sign in again
+		return c.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the authentication details
+	return c.JSON(response)
+}
+
+// Logout revokes the session of the access token, or every session of the user with all_sessions
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the session could not be revoked; otherwise, it returns a JSON response with the result
+func Logout(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a LogoutRequest DTO to hold the request parameters, the body is optional
+	var params dto.LogoutRequest
+	if len(c.Body()) > 0 {
+		if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+			// Return a 422 Unprocessable Entity status with the validation error
+			return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+				"status": -1,
+				"error":  err,
+			})
+		}
+	}
+
+	// Get the session of the access token and the signed in user
+	params.SessionID = userService.GetSessionID(c)
+	user := userService.GetUserObject(c)
+
+	// Call the userService to revoke the session
+	response, handle := userService.Logout(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the result
+	return c.JSON(response)
+}
+
+// Register signs a customer up and sends a verification code to their email or mobile number
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the customer could not be signed up; otherwise, it returns a JSON response with the registration to verify
+func Register(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a RegistrationRequest DTO to hold the request parameters
+	var params dto.RegistrationRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to start the registration and send the code
+	response, handle := userService.Register(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the registration to verify
+	return c.JSON(response)
+}
+
+// VerifyRegistration checks the verification code of a registration and creates the customer
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the code was refused; otherwise, it returns a JSON response with the password setup token
+func VerifyRegistration(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a RegistrationVerifyRequest DTO to hold the request parameters
+	var params dto.RegistrationVerifyRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to verify the code
+	response, handle := userService.VerifyRegistration(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the password setup token
+	return c.JSON(response)
+}
+
+// ForgotPassword sends a password reset code to the user with the email or mobile number, if there is one
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the request was refused; otherwise, it returns a JSON response acknowledging the request
+func ForgotPassword(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a ForgotPasswordRequest DTO to hold the request parameters
+	var params dto.ForgotPasswordRequest
+	// Set the Platform from the request headers and the address the request came from for the audit log
+	params.Platform = c.Get("X-Platform")
+	params.IPAddress = c.IP()
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to send the reset code
+	response, handle := userService.ForgotPassword(params)
+	if handle.Status < 0 {
+		// Return a 429 Too Many Requests status when the identifier is rate limited, 422 otherwise
+		return c.Status(passwordResetStatus(handle)).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response acknowledging the request
+	return c.JSON(response)
+}
+
+// ResetPassword sets a new password with the reset code that was sent
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the code was refused; otherwise, it returns a JSON response confirming the reset
+func ResetPassword(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a ResetPasswordRequest DTO to hold the request parameters
+	var params dto.ResetPasswordRequest
+	// Set the Platform from the request headers and the address the request came from for the audit log
+	params.Platform = c.Get("X-Platform")
+	params.IPAddress = c.IP()
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to check the code and set the password
+	response, handle := userService.ResetPassword(params)
+	if handle.Status < 0 {
+		// Return a 429 Too Many Requests status when the identifier is rate limited, 422 otherwise
+		return c.Status(passwordResetStatus(handle)).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response confirming the reset
+	return c.JSON(response)
+}
+
+// passwordResetStatus returns the HTTP status of a refused password reset request, the service answers -1 when the
+// identifier is rate limited
+func passwordResetStatus(handle dto.HandleError) int {
+	if handle.Status == -1 {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// VerifyMfa answers the MFA challenge of a sign in with an authenticator or backup code
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the code was refused; otherwise, it returns a JSON response with the authentication tokens
+func VerifyMfa(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a MfaVerifyRequest DTO to hold the request parameters
+	var params dto.MfaVerifyRequest
+	// Set the address wrong codes are counted against as failed sign ins
+	params.IPAddress = c.IP()
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to check the code and sign the user in
+	response, handle := userService.VerifyMfa(params)
+	if response.RetryAfter > 0 {
+		// Return a 429 Too Many Requests status with the wait when failed sign ins are throttled
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(response.RetryAfter, 10))
+		return c.Status(http.StatusTooManyRequests).JSON(&fiber.Map{
+			"status":      handle.Status,
+			"error":       handle.Errors.Error(),
+			"retry_after": response.RetryAfter,
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the authentication tokens
+	return c.JSON(response)
+}
+
+// EnrollMfa starts a TOTP enrolment for the signed in user
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the enrolment could not be started; otherwise, it returns a JSON response with the secret to add to an authenticator app
+func EnrollMfa(c *fiber.Ctx, userService userService.UserService) error {
+	// Get the signed in user
+	user := userService.GetUserObject(c)
+
+	// Call the userService to generate the secret
+	response, handle := userService.EnrollMfa(dto.MfaEnrollRequest{}, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the secret to add to an authenticator app
+	return c.JSON(response)
+}
+
+// EnrollMfaChallenge starts a TOTP enrolment with the challenge of a sign in that requires one
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the enrolment could not be started; otherwise, it returns a JSON response with the secret to add to an authenticator app
+func EnrollMfaChallenge(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a MfaEnrollRequest DTO to hold the request parameters
+	var params dto.MfaEnrollRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to generate the secret, the user is the one of the challenge
+	response, handle := userService.EnrollMfa(params, models.User{})
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the secret to add to an authenticator app
+	return c.JSON(response)
+}
+
+// ConfirmMfa enables the pending TOTP enrolment of the signed in user with a code of the authenticator app
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the code was refused; otherwise, it returns a JSON response with the backup codes
+func ConfirmMfa(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a MfaCodeRequest DTO to hold the request parameters
+	var params dto.MfaCodeRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in user
+	user := userService.GetUserObject(c)
+
+	// Call the userService to check the code and enable the second factor
+	response, handle := userService.ConfirmMfa(params, user)
+	if response.RetryAfter > 0 {
+		// Return a 429 Too Many Requests status with the wait when wrong codes are throttled
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(response.RetryAfter, 10))
+		return c.Status(http.StatusTooManyRequests).JSON(&fiber.Map{
+			"status":      handle.Status,
+			"error":       handle.Errors.Error(),
+			"retry_after": response.RetryAfter,
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the backup codes
+	return c.JSON(response)
+}
+
+// RegenerateBackupCodes replaces the backup codes of the signed in user
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the code was refused; otherwise, it returns a JSON response with the new backup codes
+func RegenerateBackupCodes(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a MfaCodeRequest DTO to hold the request parameters
+	var params dto.MfaCodeRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in user
+	user := userService.GetUserObject(c)
+
+	// Call the userService to check the code and replace the backup codes
+	response, handle := userService.RegenerateBackupCodes(params, user)
+	if response.RetryAfter > 0 {
+		// Return a 429 Too Many Requests status with the wait when wrong codes are throttled
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(response.RetryAfter, 10))
+		return c.Status(http.StatusTooManyRequests).JSON(&fiber.Map{
+			"status":      handle.Status,
+			"error":       handle.Errors.Error(),
+			"retry_after": response.RetryAfter,
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the new backup codes
+	return c.JSON(response)
+}
+
+// DisableMfa removes the second factor of the signed in user
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the code was refused or the second factor is required; otherwise, it returns a JSON response with the result
+func DisableMfa(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a MfaCodeRequest DTO to hold the request parameters
+	var params dto.MfaCodeRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in user
+	user := userService.GetUserObject(c)
+
+	// Call the userService to check the code and remove the second factor
+	response, handle := userService.DisableMfa(params, user)
+	if response.RetryAfter > 0 {
+		// Return a 429 Too Many Requests status with the wait when wrong codes are throttled
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(response.RetryAfter, 10))
+		return c.Status(http.StatusTooManyRequests).JSON(&fiber.Map{
+			"status":      handle.Status,
+			"error":       handle.Errors.Error(),
+			"retry_after": response.RetryAfter,
+		})
+	}
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the result
+	return c.JSON(response)
+}
+
+// UnlockLogin clears the failed sign ins and lockout of an identifier or an IP address
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the unlock failed; otherwise, it returns a JSON response confirming the unlock
+func UnlockLogin(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UnlockLoginRequest DTO to hold the request parameters
+	var params dto.UnlockLoginRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in employee
+	user := userService.GetUserObject(c)
+
+	// Call the userService to clear the failed sign ins
+	response, handle := userService.UnlockLogin(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response confirming the unlock
+	return c.JSON(response)
+}
+
+// CreateUser handles an administrator creating a customer or an employee
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the user could not be created; otherwise, it returns a JSON response with the user
+func CreateUser(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserCreateRequest DTO to hold the request parameters
+	var params dto.UserCreateRequest
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in administrator
+	admin := userService.GetUserObject(c)
+
+	// Call the userService to create the user
+	response, handle := userService.CreateUser(params, admin)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the user
+	return c.JSON(response)
+}
+
+// GetUsers handles listing a page of the users
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?type=", "?status=" and "?search=" narrow the list and
+// "?page=" and "?limit=" page it
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the users could not be listed; otherwise, it returns a JSON response with the users
+func GetUsers(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserListRequest DTO from the query string
+	params := dto.UserListRequest{
+		UserType: strings.ToUpper(c.Query("type")),
+		Status:   strings.ToUpper(c.Query("status")),
+		Search:   c.Query("search"),
+		Page:     c.QueryInt("page", 1),
+		Limit:    c.QueryInt("limit", 50),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the userService to list the users
+	response, handle := userService.GetUsers(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the users
+	return c.JSON(response)
+}
+
+// GetUser handles reading the details of a user and their roles
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the user was not found; otherwise, it returns a JSON response with the user
+func GetUser(c *fiber.Ctx, userService userService.UserService) error {
+	// Call the userService with the user ID from the URL parameters
+	response, handle := userService.GetUser(c.Params("userId"))
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the user
+	return c.JSON(response)
+}
+
+// UpdateUser handles an administrator changing the name, email or mobile number of a user
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the user could not be updated; otherwise, it returns a JSON response with the updated user
+func UpdateUser(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserUpdateRequest DTO and set the UserID from the URL parameters
+	var params dto.UserUpdateRequest
+	params.UserID = c.Params("userId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in administrator
+	admin := userService.GetUserObject(c)
+
+	// Call the userService to update the user
+	response, handle := userService.UpdateUser(params, admin)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the updated user
+	return c.JSON(response)
+}
+
+// DeactivateUser handles an administrator disabling a user, who loses access at once
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the user could not be deactivated; otherwise, it returns a JSON response with the user
+func DeactivateUser(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserStatusRequest DTO and set the UserID from the URL parameters
+	var params dto.UserStatusRequest
+	params.UserID = c.Params("userId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in administrator
+	admin := userService.GetUserObject(c)
+
+	// Call the userService to deactivate the user
+	response, handle := userService.DeactivateUser(params, admin)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the deactivated user
+	return c.JSON(response)
+}
+
+// ReactivateUser handles an administrator enabling a deactivated user again
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the user could not be reactivated; otherwise, it returns a JSON response with the user
+func ReactivateUser(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserStatusRequest DTO and set the UserID from the URL parameters
+	var params dto.UserStatusRequest
+	params.UserID = c.Params("userId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in administrator
+	admin := userService.GetUserObject(c)
+
+	// Call the userService to reactivate the user
+	response, handle := userService.ReactivateUser(params, admin)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the reactivated user
+	return c.JSON(response)
+}
+
+// ResetUserPassword handles an administrator resetting the password of a user, who is sent a code to choose a new one
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the password could not be reset; otherwise, it returns a JSON response with the user
+func ResetUserPassword(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserPasswordResetRequest DTO and set the UserID from the URL parameters
+	var params dto.UserPasswordResetRequest
+	params.UserID = c.Params("userId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in administrator
+	admin := userService.GetUserObject(c)
+
+	// Call the userService to reset the password
+	response, handle := userService.ResetUserPassword(params, admin)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response confirming the reset
+	return c.JSON(response)
+}
+
+// AssignRoles handles an administrator replacing the roles of an employee
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if the roles could not be assigned; otherwise, it returns a JSON response with the employee
+func AssignRoles(c *fiber.Ctx, userService userService.UserService) error {
+	// Initialize a UserRolesRequest DTO and set the UserID from the URL parameters
+	var params dto.UserRolesRequest
+	params.UserID = c.Params("userId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Get the signed in administrator
+	admin := userService.GetUserObject(c)
+
+	// Call the userService to assign the roles
+	response, handle := userService.AssignRoles(params, admin)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a JSON response with the employee and their roles
+	return c.JSON(response)
+}
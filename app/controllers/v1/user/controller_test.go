@@ -3,6 +3,7 @@ package user_controller
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
 	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
 	"github.com/stretchr/testify/assert"
 )
@@ -90,3 +92,916 @@ func TestLogin_InvalidRequest(t *testing.T) {
 	assert.Equal(t, float64(-1), response["status"].(float64))
 	assert.NotEmpty(t, response["error"])
 }
+
+func TestLogin_PasswordChangeRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().ValidateCredentials(gomock.Any()).Return(dto.LoginResponse{
+		Status:  2,
+		Message: "Password has to be changed before signing in",
+		Data: dto.TokenDetail{
+			PasswordSetupToken: "setup_token",
+			UserID:             "user_id",
+			UserType:           "EMPLOYEE",
+			TokenExpires:       1718562847,
+		},
+	}, dto.HandleError{
+		Status: 1,
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/auth", func(c *fiber.Ctx) error {
+		return Login(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.LoginRequest{Identifier: "9790970381", Password: "temporary"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/auth", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "EMPLOYEE_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(2), response["status"].(float64))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "setup_token", data["password_setup_token"])
+	assert.NotContains(t, data, "access_token")
+	assert.NotContains(t, data, "refresh_token")
+}
+
+func TestSetPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().SetPassword(dto.SetPasswordRequest{
+		SetupToken: "setup_token",
+		Password:   "a-new-password",
+	}).Return(dto.LoginResponse{
+		Status:  1,
+		Message: "Password set successfully",
+		Data: dto.TokenDetail{
+			AccessToken:  "access_token",
+			RefreshToken: "refresh_token",
+			UserID:       "user_id",
+			UserType:     "EMPLOYEE",
+		},
+	}, dto.HandleError{
+		Status: 1,
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/password/setup", func(c *fiber.Ctx) error {
+		return SetPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.SetPasswordRequest{SetupToken: "setup_token", Password: "a-new-password"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password/setup", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(1), response["status"].(float64))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "access_token", data["access_token"])
+}
+
+func TestSetPassword_TooShort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/v1/user/password/setup", func(c *fiber.Ctx) error {
+		return SetPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.SetPasswordRequest{SetupToken: "setup_token", Password: "short"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password/setup", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-1), response["status"].(float64))
+}
+
+func TestSetPassword_ExpiredToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().SetPassword(gomock.Any()).Return(dto.LoginResponse{}, dto.HandleError{
+		Status: -1,
+		Errors: fmt.Errorf("password setup token is invalid or has expired"),
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/password/setup", func(c *fiber.Ctx) error {
+		return SetPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.SetPasswordRequest{SetupToken: "setup_token", Password: "a-new-password"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password/setup", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-1), response["status"].(float64))
+	assert.Equal(t, "password setup token is invalid or has expired", response["error"])
+}
+
+func TestRefreshToken_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().RefreshToken(dto.RefreshTokenRequest{RefreshToken: "refresh_token"}).Return(
+		dto.LoginResponse{
+			Status: 1,
+			Data: dto.TokenDetail{
+				AccessToken:  "new_access_token",
+				RefreshToken: "new_refresh_token",
+				UserID:       "user_id",
+				UserType:     "CUSTOMER",
+			},
+		}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/token/refresh", func(c *fiber.Ctx) error {
+		return RefreshToken(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.RefreshTokenRequest{RefreshToken: "refresh_token"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/token/refresh", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "new_access_token", data["access_token"])
+	assert.Equal(t, "new_refresh_token", data["refresh_token"])
+}
+
+func TestRefreshToken_Reused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().RefreshToken(gomock.Any()).Return(dto.LoginResponse{}, dto.HandleError{
+		Status: -4,
+		Errors: fmt.Errorf("refresh token has already been used, the session has been revoked"),
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/token/refresh", func(c *fiber.Ctx) error {
+		return RefreshToken(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.RefreshTokenRequest{RefreshToken: "refresh_token"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/token/refresh", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-4), response["status"].(float64))
+}
+
+func TestLogout_CurrentSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetSessionID(gomock.Any()).Return("session_id")
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockUserService.EXPECT().Logout(dto.LogoutRequest{SessionID: "session_id"}, models.User{UserID: "user_id"}).
+		Return(dto.LogoutResponse{Status: 1, Message: "Signed out successfully"}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/logout", func(c *fiber.Ctx) error {
+		return Logout(c, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/logout", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestLogout_AllSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetSessionID(gomock.Any()).Return("session_id")
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockUserService.EXPECT().Logout(dto.LogoutRequest{SessionID: "session_id", AllSessions: true},
+		models.User{UserID: "user_id"}).
+		Return(dto.LogoutResponse{Status: 1, Message: "Signed out successfully"}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/logout", func(c *fiber.Ctx) error {
+		return Logout(c, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/logout", bytes.NewReader([]byte(`{"all_sessions": true}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRegister_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	request := dto.RegistrationRequest{
+		UserName:     "John Doe",
+		UserEmail:    "john.doe@example.com",
+		MobileNumber: "1234567890",
+		Channel:      "EMAIL",
+	}
+	response := dto.RegistrationResponse{Status: 1, Message: "A verification code has been sent"}
+	response.Data.RegistrationID = "registration_id"
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().Register(request).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/register", func(c *fiber.Ctx) error {
+		return Register(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/register", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRegister_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/v1/user/register", func(c *fiber.Ctx) error {
+		return Register(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.RegistrationRequest{UserName: "John Doe", UserEmail: "not-an-email"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/register", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestVerifyRegistration_WrongCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	request := dto.RegistrationVerifyRequest{RegistrationID: "registration_id", Code: "123456"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().VerifyRegistration(request).Return(dto.LoginResponse{}, dto.HandleError{
+		Status: -4,
+		Errors: fmt.Errorf("verification code is incorrect"),
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/register/verify", func(c *fiber.Ctx) error {
+		return VerifyRegistration(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/register/verify", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-4), body["status"].(float64))
+}
+
+func TestForgotPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().ForgotPassword(dto.ForgotPasswordRequest{
+		Identifier: "john.doe@example.com",
+		Platform:   "CUSTOMER_API",
+		IPAddress:  "0.0.0.0",
+	}).Return(dto.PasswordResetResponse{
+		Status:  1,
+		Message: "If an account matches, a password reset code has been sent to it",
+	}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/password/forgot", func(c *fiber.Ctx) error {
+		return ForgotPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.ForgotPasswordRequest{Identifier: "john.doe@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password/forgot", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "CUSTOMER_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestForgotPassword_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().ForgotPassword(gomock.Any()).Return(dto.PasswordResetResponse{}, dto.HandleError{
+		Status: -1,
+		Errors: fmt.Errorf("too many password reset attempts, try again later"),
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/password/forgot", func(c *fiber.Ctx) error {
+		return ForgotPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.ForgotPasswordRequest{Identifier: "john.doe@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password/forgot", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "CUSTOMER_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+}
+
+func TestResetPassword_WrongCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().ResetPassword(gomock.Any()).Return(dto.PasswordResetResponse{}, dto.HandleError{
+		Status: -3,
+		Errors: fmt.Errorf("verification code is incorrect"),
+	})
+
+	app := fiber.New()
+	app.Post("/v1/user/password/reset", func(c *fiber.Ctx) error {
+		return ResetPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.ResetPasswordRequest{
+		Identifier: "john.doe@example.com",
+		Code:       "123456",
+		Password:   "a-new-password",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password/reset", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "CUSTOMER_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-3), response["status"].(float64))
+}
+
+func TestVerifyMfa_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	request := dto.MfaVerifyRequest{ChallengeToken: "challenge_token", Code: "123456", IPAddress: "0.0.0.0"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().VerifyMfa(request).Return(dto.LoginResponse{
+		Status: 1,
+		Data:   dto.TokenDetail{AccessToken: "access_token", RefreshToken: "refresh_token"},
+	}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/mfa/verify", func(c *fiber.Ctx) error {
+		return VerifyMfa(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/mfa/verify", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "access_token", response["data"].(map[string]interface{})["access_token"])
+}
+
+func TestVerifyMfa_MissingCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/v1/user/mfa/verify", func(c *fiber.Ctx) error {
+		return VerifyMfa(c, mockUserService)
+	})
+
+	// Either an authenticator code or a backup code is needed
+	requestBody, _ := json.Marshal(dto.MfaVerifyRequest{ChallengeToken: "challenge_token"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/mfa/verify", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestVerifyMfa_Throttled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().VerifyMfa(gomock.Any()).Return(dto.LoginResponse{RetryAfter: 60},
+		dto.HandleError{Status: -8, Errors: fmt.Errorf("too many failed sign ins, try again in 60 seconds")})
+
+	app := fiber.New()
+	app.Post("/v1/user/mfa/verify", func(c *fiber.Ctx) error {
+		return VerifyMfa(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.MfaVerifyRequest{ChallengeToken: "challenge_token", Code: "123456"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/mfa/verify", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-8), response["status"])
+}
+
+func TestDisableMfa_Required(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := models.User{UserID: "user_id", UserType: "EMPLOYEE"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(user)
+	mockUserService.EXPECT().DisableMfa(dto.MfaCodeRequest{Code: "123456"}, user).Return(dto.MfaResponse{},
+		dto.HandleError{
+			Status: -1,
+			Errors: fmt.Errorf("two-factor authentication is required for your account"),
+		})
+
+	app := fiber.New()
+	app.Post("/v1/user/mfa/disable", func(c *fiber.Ctx) error {
+		return DisableMfa(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.MfaCodeRequest{Code: "123456"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/mfa/disable", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestMfaCodeChecks_Throttled(t *testing.T) {
+	user := models.User{UserID: "user_id", UserType: "CUSTOMER"}
+	throttled := dto.HandleError{Status: -5, Errors: fmt.Errorf("too many wrong authentication codes, try again in 900 seconds")}
+
+	tests := []struct {
+		name    string
+		path    string
+		expect  func(mockUserService *userSvc.MockUserService)
+		handler func(c *fiber.Ctx, userService userSvc.UserService) error
+	}{
+		{
+			name: "confirm",
+			path: "/v1/user/mfa/confirm",
+			expect: func(mockUserService *userSvc.MockUserService) {
+				mockUserService.EXPECT().ConfirmMfa(dto.MfaCodeRequest{Code: "123456"}, user).
+					Return(dto.MfaBackupCodesResponse{RetryAfter: 900}, throttled)
+			},
+			handler: ConfirmMfa,
+		},
+		{
+			name: "backup codes",
+			path: "/v1/user/mfa/backup-codes",
+			expect: func(mockUserService *userSvc.MockUserService) {
+				mockUserService.EXPECT().RegenerateBackupCodes(dto.MfaCodeRequest{Code: "123456"}, user).
+					Return(dto.MfaBackupCodesResponse{RetryAfter: 900}, throttled)
+			},
+			handler: RegenerateBackupCodes,
+		},
+		{
+			name: "disable",
+			path: "/v1/user/mfa/disable",
+			expect: func(mockUserService *userSvc.MockUserService) {
+				mockUserService.EXPECT().DisableMfa(dto.MfaCodeRequest{Code: "123456"}, user).
+					Return(dto.MfaResponse{RetryAfter: 900}, throttled)
+			},
+			handler: DisableMfa,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserService := userSvc.NewMockUserService(ctrl)
+			mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(user)
+			tt.expect(mockUserService)
+
+			handler := tt.handler
+			app := fiber.New()
+			app.Post(tt.path, func(c *fiber.Ctx) error {
+				return handler(c, mockUserService)
+			})
+
+			requestBody, _ := json.Marshal(dto.MfaCodeRequest{Code: "123456"})
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+			assert.Equal(t, "900", resp.Header.Get(fiber.HeaderRetryAfter))
+
+			var response map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, float64(-5), response["status"])
+		})
+	}
+}
+
+func TestLogin_Throttled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().ValidateCredentials(gomock.Any()).Return(dto.LoginResponse{RetryAfter: 900},
+		dto.HandleError{
+			Status: -6,
+			Errors: fmt.Errorf("too many failed sign ins, try again in 900 seconds"),
+		})
+
+	app := fiber.New()
+	app.Post("/v1/user/auth", func(c *fiber.Ctx) error {
+		return Login(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(dto.LoginRequest{Identifier: "john.doe@example.com", Password: "wrong password"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/auth", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "CUSTOMER_API")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "900", resp.Header.Get("Retry-After"))
+}
+
+func TestUnlockLogin_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := models.User{UserID: "admin_id", UserType: "EMPLOYEE"}
+	request := dto.UnlockLoginRequest{Identifier: "john.doe@example.com"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(admin)
+	mockUserService.EXPECT().UnlockLogin(request, admin).
+		Return(dto.UnlockLoginResponse{Status: 1, Message: "Sign in unlocked"}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/user/login/unlock", func(c *fiber.Ctx) error {
+		return UnlockLogin(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/login/unlock", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestUnlockLogin_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/v1/user/login/unlock", func(c *fiber.Ctx) error {
+		return UnlockLogin(c, mockUserService)
+	})
+
+	// Either an identifier or an IP address is needed
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/login/unlock", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestCreateUser_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := models.User{UserID: "admin_id", UserType: "EMPLOYEE"}
+	request := dto.UserCreateRequest{
+		UserName:     "Jane Doe",
+		UserEmail:    "jane.doe@example.com",
+		MobileNumber: "6591230381",
+		UserType:     "EMPLOYEE",
+		Roles:        []string{"UNDERWRITER"},
+	}
+
+	response := dto.UserDetailResponse{Status: 1, Message: "User created"}
+	response.Data = dto.UserDetailObject{UserID: "user_id", UserType: "EMPLOYEE", Status: "ACTIVE",
+		Roles: []string{"UNDERWRITER"}}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(admin)
+	mockUserService.EXPECT().CreateUser(request, admin).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/users", func(c *fiber.Ctx) error {
+		return CreateUser(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "ACTIVE", data["status"])
+}
+
+func TestCreateUser_InvalidType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/v1/users", func(c *fiber.Ctx) error {
+		return CreateUser(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(map[string]interface{}{
+		"user_name":     "Jane Doe",
+		"user_email":    "jane.doe@example.com",
+		"mobile_number": "6591230381",
+		"user_type":     "PARTNER",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestGetUsers_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	response := dto.UserListResponse{Status: 1, Message: "Total 1 user(s) found"}
+	response.Data.Users = []dto.UserDetailObject{{UserID: "user_id", Status: "INACTIVE"}}
+	response.Data.Total = 1
+	response.Data.Page = 2
+	response.Data.Limit = 10
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUsers(dto.UserListRequest{
+		UserType: "EMPLOYEE",
+		Status:   "INACTIVE",
+		Page:     2,
+		Limit:    10,
+	}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/v1/users", func(c *fiber.Ctx) error {
+		return GetUsers(c, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users?type=employee&status=inactive&page=2&limit=10", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGetUsers_InvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Get("/v1/users", func(c *fiber.Ctx) error {
+		return GetUsers(c, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users?limit=1000", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestDeactivateUser_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := models.User{UserID: "admin_id", UserType: "EMPLOYEE"}
+	response := dto.UserDetailResponse{Status: 1, Message: "User deactivated"}
+	response.Data = dto.UserDetailObject{UserID: "user_id", Status: "INACTIVE", DeactivationReason: "Left the company"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(admin)
+	mockUserService.EXPECT().DeactivateUser(dto.UserStatusRequest{UserID: "user_id", Reason: "Left the company"},
+		admin).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/users/:userId/deactivate", func(c *fiber.Ctx) error {
+		return DeactivateUser(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(map[string]interface{}{"reason": "Left the company"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/users/user_id/deactivate", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestDeactivateUser_Self(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := models.User{UserID: "admin_id", UserType: "EMPLOYEE"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(admin)
+	mockUserService.EXPECT().DeactivateUser(gomock.Any(), admin).Return(dto.UserDetailResponse{}, dto.HandleError{
+		Status: -1,
+		Errors: fmt.Errorf("you cannot deactivate yourself"),
+	})
+
+	app := fiber.New()
+	app.Post("/v1/users/:userId/deactivate", func(c *fiber.Ctx) error {
+		return DeactivateUser(c, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/users/admin_id/deactivate", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, "you cannot deactivate yourself", responseBody["error"])
+}
+
+func TestResetUserPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := models.User{UserID: "admin_id", UserType: "EMPLOYEE"}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(admin)
+	mockUserService.EXPECT().ResetUserPassword(dto.UserPasswordResetRequest{UserID: "user_id", Channel: "SMS"},
+		admin).Return(dto.UserDetailResponse{Status: 1, Message: "Password reset"}, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/v1/users/:userId/password/reset", func(c *fiber.Ctx) error {
+		return ResetUserPassword(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(map[string]interface{}{"channel": "SMS"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/users/user_id/password/reset", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestAssignRoles_MissingRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Put("/v1/users/:userId/roles", func(c *fiber.Ctx) error {
+		return AssignRoles(c, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/users/user_id/roles", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestAssignRoles_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := models.User{UserID: "admin_id", UserType: "EMPLOYEE"}
+	response := dto.UserDetailResponse{Status: 1, Message: "Roles assigned, the user has been signed out of every session"}
+	response.Data = dto.UserDetailObject{UserID: "user_id", Roles: []string{"APPROVER", "AUDITOR"}}
+
+	mockUserService := userSvc.NewMockUserService(ctrl)
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(admin)
+	mockUserService.EXPECT().AssignRoles(dto.UserRolesRequest{
+		UserID: "user_id",
+		Roles:  []string{"APPROVER", "AUDITOR"},
+	}, admin).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Put("/v1/users/:userId/roles", func(c *fiber.Ctx) error {
+		return AssignRoles(c, mockUserService)
+	})
+
+	requestBody, _ := json.Marshal(map[string]interface{}{"roles": []string{"APPROVER", "AUDITOR"}})
+	req := httptest.NewRequest(http.MethodPut, "/v1/users/user_id/roles", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,86 @@
+package writeoff_controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/validator"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	writeoff "github.com/nishanthrk/aspire-lms/app/services/writeoff"
+	"net/http"
+	"strings"
+)
+
+// WriteOffLoan handles writing off an unrecoverable loan
+// Parameters:
+// - c: *fiber.Ctx representing the request context
+// - writeOffService: writeoff.WriteOffService for handling write-off operations
+// - userService: userService.UserService for handling user-related operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the write-off details
+func WriteOffLoan(c *fiber.Ctx, writeOffService writeoff.WriteOffService, userService userService.UserService) error {
+	// Initialize a WriteOffRequest DTO and set the ApplicationID from the URL parameters
+	params := dto.WriteOffRequest{}
+	params.ApplicationID = c.Params("applicationId")
+
+	// Parse and validate the request body into the params object
+	if err := validator.ParseBodyAndValidate(c, &params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Retrieve the user object from the request context
+	user := userService.GetUserObject(c)
+
+	// Call the writeOffService to write the loan off
+	response, handle := writeOffService.WriteOffLoan(params, user)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the write-off details
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetWriteOffReport handles the report of loans written off in a period
+// Parameters:
+// - c: *fiber.Ctx representing the request context, "?from=" and "?to=" give the period, "?currency=" narrows it
+// - writeOffService: writeoff.WriteOffService for handling write-off operations
+// Returns:
+// - An error if there was an issue during the process; otherwise, it returns a JSON response with the report
+func GetWriteOffReport(c *fiber.Ctx, writeOffService writeoff.WriteOffService) error {
+	// Initialize a WriteOffReportRequest DTO from the query string
+	params := dto.WriteOffReportRequest{
+		From:         c.Query("from"),
+		To:           c.Query("to"),
+		CurrencyCode: strings.ToUpper(c.Query("currency")),
+	}
+
+	// Validate the request parameters
+	if err := validator.Validate(&params); err != nil {
+		// Return a 422 Unprocessable Entity status with the validation error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": -1,
+			"error":  err,
+		})
+	}
+
+	// Call the writeOffService to build the report
+	response, handle := writeOffService.GetWriteOffReport(params)
+	if handle.Status < 0 {
+		// Return a 422 Unprocessable Entity status with the service error
+		return c.Status(http.StatusUnprocessableEntity).JSON(&fiber.Map{
+			"status": handle.Status,
+			"error":  handle.Errors.Error(),
+		})
+	}
+
+	// Return a 200 OK status with the report
+	return c.Status(http.StatusOK).JSON(response)
+}
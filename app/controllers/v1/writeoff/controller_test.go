@@ -0,0 +1,154 @@
+package writeoff_controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	userSvc "github.com/nishanthrk/aspire-lms/app/services/user"
+	writeOffSvc "github.com/nishanthrk/aspire-lms/app/services/writeoff"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteOffLoan_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWriteOffService := writeOffSvc.NewMockWriteOffService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	response := dto.WriteOffResponse{Status: 1, Message: "Loan written off successfully"}
+	response.Data.WriteOffId = "write_off_id"
+	response.Data.Status = models.LoanApplicationStatusWrittenOff
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockWriteOffService.EXPECT().WriteOffLoan(dto.WriteOffRequest{
+		ApplicationID: "application_id",
+		Reason:        "Borrower deceased",
+	}, models.User{UserID: "user_id"}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/write-off", func(c *fiber.Ctx) error {
+		return WriteOffLoan(c, mockWriteOffService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"reason": "Borrower deceased"})
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/write-off", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "WRITTEN_OFF", data["status"])
+}
+
+func TestWriteOffLoan_MissingReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWriteOffService := writeOffSvc.NewMockWriteOffService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/write-off", func(c *fiber.Ctx) error {
+		return WriteOffLoan(c, mockWriteOffService, mockUserService)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/write-off", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestWriteOffLoan_InsufficientAuthority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWriteOffService := writeOffSvc.NewMockWriteOffService(ctrl)
+	mockUserService := userSvc.NewMockUserService(ctrl)
+
+	mockUserService.EXPECT().GetUserObject(gomock.Any()).Return(models.User{UserID: "user_id"})
+	mockWriteOffService.EXPECT().WriteOffLoan(gomock.Any(), gomock.Any()).Return(dto.WriteOffResponse{},
+		dto.HandleError{
+			Status: -5,
+			Errors: fmt.Errorf("write-off of ₹60,000.00 exceeds your authority of ₹50,000.00"),
+		})
+
+	app := fiber.New()
+	app.Post("/application/:applicationId/write-off", func(c *fiber.Ctx) error {
+		return WriteOffLoan(c, mockWriteOffService, mockUserService)
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{"reason": "Unreachable"})
+	req := httptest.NewRequest(http.MethodPost, "/application/application_id/write-off", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	assert.Equal(t, float64(-5), responseBody["status"])
+	assert.Equal(t, "write-off of ₹60,000.00 exceeds your authority of ₹50,000.00", responseBody["error"])
+}
+
+func TestGetWriteOffReport_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWriteOffService := writeOffSvc.NewMockWriteOffService(ctrl)
+
+	response := dto.WriteOffReportResponse{Status: 1}
+	response.Data.From = "2024-01-01"
+	response.Data.To = "2024-03-31"
+	response.Data.Totals = []dto.WriteOffTotal{{CurrencyCode: "INR", Count: 1, Principal: "₹1,000.00"}}
+
+	mockWriteOffService.EXPECT().GetWriteOffReport(dto.WriteOffReportRequest{
+		From:         "2024-01-01",
+		To:           "2024-03-31",
+		CurrencyCode: "INR",
+	}).Return(response, dto.HandleError{Status: 1})
+
+	app := fiber.New()
+	app.Get("/write-offs", func(c *fiber.Ctx) error {
+		return GetWriteOffReport(c, mockWriteOffService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/write-offs?from=2024-01-01&to=2024-03-31&currency=inr", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	data := responseBody["data"].(map[string]interface{})
+	totals := data["totals"].([]interface{})
+	assert.Equal(t, float64(1), totals[0].(map[string]interface{})["count"])
+}
+
+func TestGetWriteOffReport_MissingPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWriteOffService := writeOffSvc.NewMockWriteOffService(ctrl)
+
+	app := fiber.New()
+	app.Get("/write-offs", func(c *fiber.Ctx) error {
+		return GetWriteOffReport(c, mockWriteOffService)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/write-offs?from=2024-01-01", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	gormLogger "gorm.io/gorm/logger"
 	"strings"
@@ -11,10 +12,14 @@ import (
 
 	"github.com/nishanthrk/aspire-lms/app/logger"
 	// Gorm
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// mysqlErrDuplicateKey is the MySQL error number of a row violating a unique index
+const mysqlErrDuplicateKey = 1062
+
 var (
 	// MysqlDB is the mysql connection handle
 	MysqlDB   *gorm.DB
@@ -57,6 +62,15 @@ func ConnectMysql() {
 	})
 }
 
+// IsDuplicateKey reports whether an insert or update was refused because it violates a unique index
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysqlDriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDuplicateKey
+	}
+	return false
+}
+
 type WhereCondition struct {
 	Key            string               `json:"key"`
 	Condition      string               `json:"condition"`
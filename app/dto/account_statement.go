@@ -0,0 +1,46 @@
+package dto
+
+type AccountStatementRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+	From          string `json:"-" validate:"required,datetime=2006-01-02"`
+	To            string `json:"-" validate:"required,datetime=2006-01-02"`
+	Format        string `json:"-" validate:"omitempty,oneof=json csv pdf"`
+}
+
+type AccountStatementAllocation struct {
+	InstallmentNumber int    `json:"installment_number"`
+	DueDate           string `json:"due_date"`
+	Amount            string `json:"amount"`
+}
+
+type AccountStatementLine struct {
+	Date        string                       `json:"date"`
+	Type        string                       `json:"type"`
+	Reference   string                       `json:"reference,omitempty"`
+	Description string                       `json:"description"`
+	Debit       string                       `json:"debit,omitempty"`
+	Credit      string                       `json:"credit,omitempty"`
+	Balance     string                       `json:"balance"`
+	Allocations []AccountStatementAllocation `json:"allocations,omitempty"`
+}
+
+type AccountStatement struct {
+	Tenant         string                 `json:"tenant"`
+	ApplicationId  string                 `json:"application_id"`
+	CustomerName   string                 `json:"customer_name"`
+	CurrencyCode   string                 `json:"currency"`
+	LoanStatus     string                 `json:"loan_status"`
+	From           string                 `json:"from"`
+	To             string                 `json:"to"`
+	GeneratedAt    string                 `json:"generated_at"`
+	OpeningBalance string                 `json:"opening_balance"`
+	TotalDebits    string                 `json:"total_debits"`
+	TotalCredits   string                 `json:"total_credits"`
+	ClosingBalance string                 `json:"closing_balance"`
+	Lines          []AccountStatementLine `json:"lines"`
+}
+
+type AccountStatementResponse struct {
+	Data   AccountStatement `json:"data"`
+	Status int              `json:"status"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type AccrualRunRequest struct {
+	AsOf string `json:"-" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AccrualRunResponse struct {
+	Data struct {
+		AsOf              string `json:"as_of"`
+		LoansAccrued      int    `json:"loans_accrued"`
+		DaysAccrued       int    `json:"days_accrued"`
+		MovedToNonAccrual int    `json:"moved_to_non_accrual"`
+		ResumedAccrual    int    `json:"resumed_accrual"`
+		LoansFailed       int    `json:"loans_failed"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
@@ -0,0 +1,100 @@
+package dto
+
+type ReassignRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+	ToUserID      string `json:"to_user_id" validate:"required"`
+	Reason        string `json:"reason" validate:"required,max=255"`
+}
+
+type BulkReassignRequest struct {
+	FromUserID     string   `json:"from_user_id" validate:"required"`
+	ToUserID       string   `json:"to_user_id" validate:"required,nefield=FromUserID"`
+	ApplicationIDs []string `json:"application_ids" validate:"omitempty,max=500,dive,required"`
+	Reason         string   `json:"reason" validate:"required,max=255"`
+}
+
+type ReassignObject struct {
+	ApplicationID string `json:"application_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+type ReassignResponse struct {
+	Data    ReassignObject `json:"data"`
+	Message string         `json:"message"`
+	Status  int            `json:"status"`
+}
+
+type ReassignSkipObject struct {
+	ApplicationID string `json:"application_id"`
+	Error         string `json:"error"`
+}
+
+type BulkReassignResponse struct {
+	Data struct {
+		Reassigned []ReassignObject     `json:"reassigned"`
+		Skipped    []ReassignSkipObject `json:"skipped"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type AssignmentObject struct {
+	AssignmentID string `json:"assignment_id"`
+	UserID       string `json:"user_id"`
+	AssignedBy   string `json:"assigned_by,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	StartedAt    string `json:"started_at"`
+	EndedAt      string `json:"ended_at,omitempty"`
+}
+
+type AssignmentHistoryResponse struct {
+	Data struct {
+		ApplicationID string             `json:"application_id"`
+		Assignments   []AssignmentObject `json:"assignments"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type DelegationRequest struct {
+	UserID     string `json:"user_id"`
+	DelegateID string `json:"delegate_id" validate:"required"`
+	StartDate  string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate    string `json:"end_date" validate:"required,datetime=2006-01-02"`
+	Reason     string `json:"reason" validate:"max=255"`
+}
+
+type DelegationRevokeRequest struct {
+	DelegationID string `json:"-" validate:"required"`
+}
+
+type DelegationListRequest struct {
+	UserID string `json:"-"`
+}
+
+type DelegationObject struct {
+	DelegationID string `json:"delegation_id"`
+	UserID       string `json:"user_id"`
+	DelegateID   string `json:"delegate_id"`
+	StartDate    string `json:"start_date"`
+	EndDate      string `json:"end_date"`
+	Reason       string `json:"reason,omitempty"`
+	CreatedBy    string `json:"created_by"`
+	RevokedAt    string `json:"revoked_at,omitempty"`
+	RevokedBy    string `json:"revoked_by,omitempty"`
+}
+
+type DelegationResponse struct {
+	Data    DelegationObject `json:"data"`
+	Message string           `json:"message"`
+	Status  int              `json:"status"`
+}
+
+type DelegationListResponse struct {
+	Data struct {
+		Delegations []DelegationObject `json:"delegations"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
@@ -4,17 +4,80 @@ type LoginRequest struct {
 	Identifier string `json:"identifier" validate:"required"`
 	Password   string `json:"password"  validate:"required"`
 	Platform   string `json:"-" validate:"required"`
+	IPAddress  string `json:"-"`
 }
 
 type TokenDetail struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	UserID       string `json:"user_id"`
-	UserType     string `json:"user_type"`
-	TokenExpires int64  `json:"token_expires"`
+	AccessToken           string   `json:"access_token,omitempty"`
+	RefreshToken          string   `json:"refresh_token,omitempty"`
+	PasswordSetupToken    string   `json:"password_setup_token,omitempty"`
+	MfaChallengeToken     string   `json:"mfa_challenge_token,omitempty"`
+	MfaEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"`
+	BackupCodes           []string `json:"backup_codes,omitempty"`
+	UserID                string   `json:"user_id"`
+	UserType              string   `json:"user_type"`
+	Roles                 []string `json:"roles,omitempty"`
+	TokenExpires          int64    `json:"token_expires"`
 }
 
 type LoginResponse struct {
-	Data   TokenDetail `json:"data"`
-	Status int         `json:"status"`
+	Data       TokenDetail `json:"data"`
+	Message    string      `json:"message,omitempty"`
+	Status     int         `json:"status"`
+	RetryAfter int64       `json:"retry_after,omitempty"`
+}
+
+// SetPasswordRequest replaces a temporary or legacy password with one chosen by the user
+type SetPasswordRequest struct {
+	SetupToken string `json:"setup_token" validate:"required"`
+	Password   string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// RefreshTokenRequest exchanges a refresh token for new tokens
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest signs out of the session of the access token, or of every session of the user
+type LogoutRequest struct {
+	SessionID   string `json:"-"`
+	AllSessions bool   `json:"all_sessions"`
+}
+
+type LogoutResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// ForgotPasswordRequest asks for a password reset code to be sent to the user with an email or mobile number
+type ForgotPasswordRequest struct {
+	Identifier string `json:"identifier" validate:"required"`
+	Channel    string `json:"channel" validate:"omitempty,oneof=EMAIL SMS"`
+	Platform   string `json:"-" validate:"required"`
+	IPAddress  string `json:"-"`
+}
+
+// ResetPasswordRequest sets a new password with the reset code that was sent
+type ResetPasswordRequest struct {
+	Identifier string `json:"identifier" validate:"required"`
+	Code       string `json:"code" validate:"required,numeric,len=6"`
+	Password   string `json:"password" validate:"required,min=8,max=72"`
+	Platform   string `json:"-" validate:"required"`
+	IPAddress  string `json:"-"`
+}
+
+type PasswordResetResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// UnlockLoginRequest clears the failed sign ins and lockout of an identifier, an IP address or both
+type UnlockLoginRequest struct {
+	Identifier string `json:"identifier" validate:"required_without=IPAddress"`
+	IPAddress  string `json:"ip_address" validate:"omitempty,ip"`
+}
+
+type UnlockLoginResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,62 @@
+package dto
+
+type TrialBalanceRequest struct {
+	AsOf         string `json:"-" validate:"omitempty,datetime=2006-01-02"`
+	CurrencyCode string `json:"-" validate:"omitempty,len=3"`
+}
+
+type TrialBalanceAccount struct {
+	AccountCode string `json:"account_code"`
+	Name        string `json:"name"`
+	AccountType string `json:"account_type"`
+	Debit       string `json:"debit"`
+	Credit      string `json:"credit"`
+}
+
+type TrialBalanceCurrency struct {
+	CurrencyCode string                `json:"currency"`
+	Accounts     []TrialBalanceAccount `json:"accounts"`
+	TotalDebit   string                `json:"total_debit"`
+	TotalCredit  string                `json:"total_credit"`
+	Balanced     bool                  `json:"balanced"`
+}
+
+type TrialBalanceResponse struct {
+	Data struct {
+		AsOf       string                 `json:"as_of"`
+		Currencies []TrialBalanceCurrency `json:"currencies"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type JournalRequest struct {
+	ApplicationID string `json:"-" validate:"omitempty,uuid"`
+	From          string `json:"-" validate:"omitempty,datetime=2006-01-02"`
+	To            string `json:"-" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type JournalLineObject struct {
+	AccountCode string `json:"account_code"`
+	Debit       string `json:"debit,omitempty"`
+	Credit      string `json:"credit,omitempty"`
+}
+
+type JournalEntryObject struct {
+	EntryId         string              `json:"entry_id"`
+	EntryType       string              `json:"entry_type"`
+	SourceReference string              `json:"source_reference"`
+	ApplicationId   string              `json:"application_id,omitempty"`
+	PaymentId       string              `json:"payment_id,omitempty"`
+	CurrencyCode    string              `json:"currency"`
+	EffectiveDate   string              `json:"effective_date"`
+	Description     string              `json:"description"`
+	ReversesEntryId string              `json:"reverses_entry_id,omitempty"`
+	Lines           []JournalLineObject `json:"lines"`
+}
+
+type JournalResponse struct {
+	Data    []JournalEntryObject `json:"data"`
+	Message string               `json:"message"`
+	Status  int                  `json:"status"`
+}
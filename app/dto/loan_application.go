@@ -29,7 +29,10 @@ type ApplicationApproveResponse struct {
 }
 
 type ApproveObject struct {
-	ApplicationID string `json:"application_id"`
+	ApplicationID   string `json:"application_id"`
+	PendingApprover string `json:"pending_approver,omitempty"`
+	ApprovalStep    int    `json:"approval_step,omitempty"`
+	ActionID        string `json:"action_id,omitempty"`
 }
 
 type RepaymentRequest struct {
@@ -39,7 +42,10 @@ type RepaymentRequest struct {
 
 type RepaymentResponse struct {
 	Data struct {
-		PaymentId string `json:"payment_id"`
+		PaymentId        string `json:"payment_id"`
+		PaymentStatus    string `json:"payment_status,omitempty"`
+		GatewayReference string `json:"gateway_reference,omitempty"`
+		CheckoutURL      string `json:"checkout_url,omitempty"`
 	} `json:"data"`
 	Message string `json:"message"`
 	Status  int    `json:"status"`
@@ -49,7 +55,12 @@ type ApplicationDetailsResponse struct {
 	Status int `json:"status"`
 	Data   struct {
 		LoanApplicationObject
-		Repayment []Repayment `json:"repayments"`
+		ExcessCredit    string      `json:"excess_credit,omitempty"`
+		AccruedInterest string      `json:"accrued_interest,omitempty"`
+		AccrualStatus   string      `json:"accrual_status,omitempty"`
+		ClosedDate      string      `json:"closed_date,omitempty"`
+		VirtualAccount  string      `json:"virtual_account,omitempty"`
+		Repayment       []Repayment `json:"repayments"`
 	} `json:"data"`
 }
 
@@ -59,13 +70,10 @@ type ApplicationListResponse struct {
 	Data    *[]LoanApplicationObject `json:"data"`
 }
 
+// UserObject carries the identification of the signed in customer applying for a loan
 type UserObject struct {
-	UserName     string    `json:"user_name" validate:"required"`
-	UserEmail    string    `json:"user_email" validate:"required"`
-	UserPassword string    `json:"user_password"`
-	MobileNumber string    `json:"mobile_number" validate:"required"`
-	CountryCode  string    `json:"country_code" validate:"validateCountryCode"`
-	Kyc          KycObject `json:"kyc"`
+	CountryCode string    `json:"country_code" validate:"validateCountryCode"`
+	Kyc         KycObject `json:"kyc"`
 }
 
 type KycObject struct {
@@ -97,4 +105,36 @@ type Repayment struct {
 	AmountPaid         null.String `json:"amount_paid"`
 	OutstandingBalance string      `json:"outstanding_balance,omitempty"`
 	RepaymentStatus    string      `json:"repayment_status"`
+	PaymentReference   string      `json:"payment_reference,omitempty"`
+}
+
+type PaymentReversalRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+	PaymentID     string `json:"-" validate:"required"`
+	Reason        string `json:"reason" validate:"required"`
+}
+
+type PaymentReversalResponse struct {
+	Data struct {
+		PaymentId string `json:"payment_id"`
+		Status    string `json:"status"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type RefundRequest struct {
+	ApplicationID string  `json:"-" validate:"required"`
+	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	Reason        string  `json:"reason"`
+}
+
+type RefundResponse struct {
+	Data struct {
+		RefundId     string `json:"refund_id"`
+		ExcessCredit string `json:"excess_credit"`
+		LoanStatus   string `json:"loan_status"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
 }
@@ -0,0 +1,37 @@
+package dto
+
+type LoanClosureRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+}
+
+type LoanClosureResponse struct {
+	Data struct {
+		ApplicationId string `json:"application_id"`
+		Status        string `json:"status"`
+		ClosedDate    string `json:"closed_date"`
+		ClosedBy      string `json:"closed_by,omitempty"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type ClosureLetterRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+}
+
+type ClosureLetter struct {
+	Tenant          string `json:"tenant"`
+	ReferenceNumber string `json:"reference_number"`
+	ApplicationId   string `json:"application_id"`
+	CustomerName    string `json:"customer_name"`
+	LoanAmount      string `json:"loan_amount"`
+	TotalRepaid     string `json:"total_repaid"`
+	DisbursedDate   string `json:"disbursed_date"`
+	ClosedDate      string `json:"closed_date"`
+	IssuedDate      string `json:"issued_date"`
+}
+
+type ClosureLetterResponse struct {
+	Data   ClosureLetter `json:"data"`
+	Status int           `json:"status"`
+}
@@ -0,0 +1,102 @@
+package dto
+
+type MandateCreateRequest struct {
+	ApplicationID     string  `json:"-" validate:"required"`
+	AccountHolderName string  `json:"account_holder_name" validate:"required,max=255"`
+	BankAccountNumber string  `json:"bank_account_number" validate:"required,alphanum,min=6,max=34"`
+	BankCode          string  `json:"bank_code" validate:"required,alphanum,max=20"`
+	MaxAmount         float64 `json:"max_amount" validate:"required,gt=0"`
+	Frequency         string  `json:"frequency" validate:"required,oneof=WEEKLY MONTHLY AS_PRESENTED"`
+	StartDate         string  `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate           string  `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+type MandateDetailsRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+}
+
+type MandateCancelRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+}
+
+type DebitCollectionObject struct {
+	CollectionId  string `json:"collection_id"`
+	RepaymentId   string `json:"repayment_id"`
+	PaymentId     string `json:"payment_id,omitempty"`
+	Attempt       int    `json:"attempt"`
+	Amount        string `json:"amount"`
+	Status        string `json:"status"`
+	FailureCode   string `json:"failure_code,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	NextRetryAt   string `json:"next_retry_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type MandateObject struct {
+	MandateId         string                  `json:"mandate_id"`
+	ApplicationId     string                  `json:"application_id"`
+	AccountHolderName string                  `json:"account_holder_name"`
+	BankAccountNumber string                  `json:"bank_account_number"`
+	BankCode          string                  `json:"bank_code"`
+	CurrencyCode      string                  `json:"currency"`
+	MaxAmount         string                  `json:"max_amount"`
+	Frequency         string                  `json:"frequency"`
+	StartDate         string                  `json:"start_date"`
+	EndDate           string                  `json:"end_date"`
+	Status            string                  `json:"status"`
+	Provider          string                  `json:"provider"`
+	ProviderReference string                  `json:"provider_reference"`
+	Collections       []DebitCollectionObject `json:"collections,omitempty"`
+}
+
+type MandateResponse struct {
+	Data    MandateObject `json:"data"`
+	Message string        `json:"message"`
+	Status  int           `json:"status"`
+}
+
+type MandateRegistration struct {
+	MandateID         string  `json:"mandate_id"`
+	ApplicationID     string  `json:"application_id"`
+	AccountHolderName string  `json:"account_holder_name"`
+	BankAccountNumber string  `json:"bank_account_number"`
+	BankCode          string  `json:"bank_code"`
+	CurrencyCode      string  `json:"currency_code"`
+	MaxAmount         float64 `json:"max_amount"`
+	Frequency         string  `json:"frequency"`
+	StartDate         string  `json:"start_date"`
+	EndDate           string  `json:"end_date"`
+}
+
+type DebitCollectionRequest struct {
+	CollectionID     string  `json:"collection_id"`
+	MandateReference string  `json:"mandate_reference"`
+	CurrencyCode     string  `json:"currency_code"`
+	Amount           float64 `json:"amount"`
+	DueDate          string  `json:"due_date"`
+	Attempt          int     `json:"attempt"`
+}
+
+type DebitCallbackRequest struct {
+	Reference     string `json:"reference" validate:"required"`
+	Status        string `json:"status" validate:"required,oneof=SETTLED FAILED"`
+	FailureCode   string `json:"failure_code"`
+	FailureReason string `json:"failure_reason"`
+}
+
+type DebitCallbackResponse struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type CollectionRunResponse struct {
+	Data struct {
+		ResultsApplied    int `json:"results_applied"`
+		MandatesExpired   int `json:"mandates_expired"`
+		CollectionsRaised int `json:"collections_raised"`
+		RetriesRaised     int `json:"retries_raised"`
+		CollectionsFailed int `json:"collections_failed"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+// MfaEnrollRequest starts a TOTP enrolment, for a signed in user or with the challenge of a sign in that requires one
+type MfaEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+type MfaEnrollResponse struct {
+	Status int `json:"status"`
+	Data   struct {
+		Secret     string `json:"secret"`
+		OtpauthURI string `json:"otpauth_uri"`
+		Digits     int    `json:"digits"`
+		Period     int    `json:"period"`
+	} `json:"data"`
+	Message string `json:"message"`
+}
+
+// MfaVerifyRequest answers the challenge of a sign in with an authenticator code or a backup code
+type MfaVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"omitempty,numeric,len=6"`
+	BackupCode     string `json:"backup_code" validate:"required_without=Code"`
+	IPAddress      string `json:"-"`
+}
+
+// MfaCodeRequest confirms an action on the second factor of a signed in user with an authenticator code
+type MfaCodeRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+type MfaBackupCodesResponse struct {
+	Status int `json:"status"`
+	Data   struct {
+		BackupCodes []string `json:"backup_codes"`
+	} `json:"data"`
+	Message    string `json:"message"`
+	RetryAfter int64  `json:"retry_after,omitempty"`
+}
+
+type MfaResponse struct {
+	Status     int    `json:"status"`
+	Message    string `json:"message"`
+	RetryAfter int64  `json:"retry_after,omitempty"`
+}
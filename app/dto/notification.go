@@ -0,0 +1,12 @@
+package dto
+
+// Notification is a message sent to a user through a notifier
+type Notification struct {
+	NotificationID string `json:"notification_id"`
+	Channel        string `json:"channel"`
+	Recipient      string `json:"recipient"`
+	Subject        string `json:"subject"`
+	Body           string `json:"body"`
+	Purpose        string `json:"purpose"`
+	CreatedAt      string `json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+type PaymentIntentRequest struct {
+	PaymentID     string  `json:"payment_id"`
+	ApplicationID string  `json:"application_id"`
+	CurrencyCode  string  `json:"currency_code"`
+	Amount        float64 `json:"amount"`
+}
+
+type PaymentIntent struct {
+	Gateway     string `json:"gateway"`
+	Reference   string `json:"reference"`
+	Status      string `json:"status"`
+	CheckoutURL string `json:"checkout_url,omitempty"`
+}
+
+type GatewayWebhookRequest struct {
+	Reference     string  `json:"reference" validate:"required"`
+	Status        string  `json:"status" validate:"required,oneof=SETTLED FAILED"`
+	Amount        float64 `json:"amount" validate:"required_if=Status SETTLED,gte=0"`
+	FailureReason string  `json:"failure_reason"`
+}
+
+type GatewayWebhookResponse struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type PaymentSyncRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+	PaymentID     string `json:"-" validate:"required"`
+}
+
+type PaymentSyncResponse struct {
+	Data struct {
+		PaymentId     string `json:"payment_id"`
+		PaymentStatus string `json:"payment_status"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type BankCreditRequest struct {
+	ApplicationID string  `json:"application_id" validate:"required"`
+	CurrencyCode  string  `json:"currency_code" validate:"required"`
+	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	BankReference string  `json:"bank_reference" validate:"required"`
+}
+
+type PaymentStatusRequest struct {
+	PaymentID     string `json:"payment_id" validate:"required"`
+	Status        string `json:"status" validate:"required,oneof=SETTLED FAILED"`
+	FailureReason string `json:"failure_reason"`
+}
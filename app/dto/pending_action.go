@@ -0,0 +1,74 @@
+package dto
+
+import "encoding/json"
+
+type PendingActionListRequest struct {
+	Status     string `json:"-" validate:"omitempty,oneof=PENDING APPLIED REJECTED"`
+	ActionType string `json:"-" validate:"omitempty,max=50"`
+}
+
+type ActionDecisionRequest struct {
+	ActionID string `json:"-" validate:"required"`
+	Reason   string `json:"reason" validate:"max=255"`
+}
+
+type PendingActionObject struct {
+	ActionID       string          `json:"action_id"`
+	ActionType     string          `json:"action_type"`
+	ResourceID     string          `json:"resource_id"`
+	Payload        json.RawMessage `json:"payload"`
+	PermissionCode string          `json:"permission_code"`
+	Status         string          `json:"status"`
+	MakerID        string          `json:"maker_id"`
+	CheckerID      string          `json:"checker_id,omitempty"`
+	DecisionReason string          `json:"decision_reason,omitempty"`
+	Result         string          `json:"result,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+	DecidedAt      string          `json:"decided_at,omitempty"`
+}
+
+type PendingActionResponse struct {
+	Data    PendingActionObject `json:"data"`
+	Message string              `json:"message"`
+	Status  int                 `json:"status"`
+}
+
+type PendingActionListResponse struct {
+	Data struct {
+		Actions []PendingActionObject `json:"actions"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type EligibilityConfigRequest struct {
+	ConfigID                int64   `json:"-" validate:"required"`
+	MinCreditScore          int     `json:"min_credit_score" validate:"required,gte=300,lte=900"`
+	MaxCreditScore          int     `json:"max_credit_score" validate:"required,gtefield=MinCreditScore,lte=900"`
+	MaxFoir                 float64 `json:"max_foir" validate:"gt=0,lte=1"`
+	BaseLoanAmount          float64 `json:"base_loan_amount" validate:"gt=0"`
+	CreditScoreFactorHigh   float64 `json:"credit_score_factor_high" validate:"gt=0"`
+	CreditScoreFactorMedium float64 `json:"credit_score_factor_medium" validate:"gt=0"`
+	CreditScoreFactorLow    float64 `json:"credit_score_factor_low" validate:"gt=0"`
+}
+
+type EligibilityConfigObject struct {
+	ConfigID                int64   `json:"config_id"`
+	CountryCode             string  `json:"country_code"`
+	MinCreditScore          int     `json:"min_credit_score"`
+	MaxCreditScore          int     `json:"max_credit_score"`
+	MaxFoir                 float64 `json:"max_foir"`
+	BaseLoanAmount          float64 `json:"base_loan_amount"`
+	CreditScoreFactorHigh   float64 `json:"credit_score_factor_high"`
+	CreditScoreFactorMedium float64 `json:"credit_score_factor_medium"`
+	CreditScoreFactorLow    float64 `json:"credit_score_factor_low"`
+	UpdatedAt               string  `json:"updated_at"`
+}
+
+type EligibilityConfigListResponse struct {
+	Data struct {
+		Configs []EligibilityConfigObject `json:"configs"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
@@ -0,0 +1,111 @@
+package dto
+
+type ProvisionRunRequest struct {
+	AsOf string `json:"-" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type ProvisionTotal struct {
+	CurrencyCode string `json:"currency"`
+	Stage        int    `json:"stage"`
+	Count        int    `json:"count"`
+	Exposure     string `json:"exposure"`
+	Ecl          string `json:"ecl"`
+	Movement     string `json:"movement"`
+}
+
+type ProvisionRunObject struct {
+	RunId     string           `json:"run_id"`
+	AsOf      string           `json:"as_of"`
+	LoanCount int              `json:"loan_count"`
+	RunBy     string           `json:"run_by,omitempty"`
+	CreatedAt string           `json:"created_at"`
+	Totals    []ProvisionTotal `json:"totals"`
+}
+
+type ProvisionRunResponse struct {
+	Data    ProvisionRunObject `json:"data"`
+	Message string             `json:"message"`
+	Status  int                `json:"status"`
+}
+
+type ProvisionRunListResponse struct {
+	Data struct {
+		Runs []ProvisionRunObject `json:"runs"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type ProvisionRunDetailRequest struct {
+	RunID string `json:"-" validate:"required,uuid"`
+}
+
+type LoanProvisionObject struct {
+	ApplicationId        string  `json:"application_id"`
+	CurrencyCode         string  `json:"currency"`
+	Stage                int     `json:"stage"`
+	DaysPastDue          int     `json:"days_past_due"`
+	Restructured         bool    `json:"restructured"`
+	Exposure             string  `json:"exposure"`
+	ProbabilityOfDefault float64 `json:"probability_of_default"`
+	LossGivenDefault     float64 `json:"loss_given_default"`
+	Ecl                  string  `json:"ecl"`
+	PreviousEcl          string  `json:"previous_ecl"`
+	Movement             string  `json:"movement"`
+	EntryId              string  `json:"entry_id,omitempty"`
+}
+
+type ProvisionRunDetailResponse struct {
+	Data struct {
+		ProvisionRunObject
+		Loans []LoanProvisionObject `json:"loans"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type ProvisionParameterRequest struct {
+	CountryCode          string  `json:"country_code" validate:"required,len=3"`
+	ProductCode          string  `json:"product_code" validate:"required,max=30"`
+	Stage                int     `json:"stage" validate:"required,oneof=1 2 3"`
+	ProbabilityOfDefault float64 `json:"probability_of_default" validate:"gte=0,lte=1"`
+	LossGivenDefault     float64 `json:"loss_given_default" validate:"gte=0,lte=1"`
+}
+
+type ProvisionParameterListRequest struct {
+	CountryCode string `json:"-" validate:"omitempty,len=3"`
+	ProductCode string `json:"-" validate:"omitempty,max=30"`
+}
+
+type ProvisionParameterObject struct {
+	CountryCode          string  `json:"country_code"`
+	ProductCode          string  `json:"product_code"`
+	Stage                int     `json:"stage"`
+	ProbabilityOfDefault float64 `json:"probability_of_default"`
+	LossGivenDefault     float64 `json:"loss_given_default"`
+	UpdatedBy            string  `json:"updated_by,omitempty"`
+	UpdatedAt            string  `json:"updated_at"`
+}
+
+type ProvisionParameterResponse struct {
+	Data struct {
+		Parameters []ProvisionParameterObject `json:"parameters"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type RestructureRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+	Restructured  *bool  `json:"restructured" validate:"required"`
+}
+
+type RestructureResponse struct {
+	Data struct {
+		ApplicationId    string `json:"application_id"`
+		Restructured     bool   `json:"restructured"`
+		RestructuredDate string `json:"restructured_date,omitempty"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
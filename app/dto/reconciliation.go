@@ -0,0 +1,49 @@
+package dto
+
+type StatementImportRequest struct {
+	FileName string `json:"file_name"`
+	Format   string `json:"format" validate:"omitempty,oneof=CSV CAMT053"`
+}
+
+type StatementException struct {
+	LineNumber       int    `json:"line_number"`
+	BookingDate      string `json:"booking_date,omitempty"`
+	Amount           string `json:"amount"`
+	CurrencyCode     string `json:"currency"`
+	BankReference    string `json:"bank_reference,omitempty"`
+	PaymentReference string `json:"payment_reference,omitempty"`
+	VirtualAccount   string `json:"virtual_account,omitempty"`
+	Counterparty     string `json:"counterparty,omitempty"`
+	Description      string `json:"description,omitempty"`
+	ApplicationId    string `json:"application_id,omitempty"`
+	Reason           string `json:"reason"`
+}
+
+type StatementImportResponse struct {
+	Data struct {
+		StatementId    string               `json:"statement_id"`
+		Format         string               `json:"format"`
+		TotalLines     int                  `json:"total_lines"`
+		PostedLines    int                  `json:"posted_lines"`
+		DuplicateLines int                  `json:"duplicate_lines"`
+		IgnoredLines   int                  `json:"ignored_lines"`
+		ExceptionLines int                  `json:"exception_lines"`
+		Exceptions     []StatementException `json:"exceptions"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type StatementExceptionRequest struct {
+	StatementID string `json:"-" validate:"required"`
+	Format      string `json:"-" validate:"omitempty,oneof=json csv"`
+}
+
+type StatementExceptionResponse struct {
+	Data struct {
+		StatementId string               `json:"statement_id"`
+		FileName    string               `json:"file_name"`
+		Exceptions  []StatementException `json:"exceptions"`
+	} `json:"data"`
+	Status int `json:"status"`
+}
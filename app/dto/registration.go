@@ -0,0 +1,26 @@
+package dto
+
+// RegistrationRequest signs a customer up, a verification code is sent to their email or mobile number
+type RegistrationRequest struct {
+	UserName     string `json:"user_name" validate:"required"`
+	UserEmail    string `json:"user_email" validate:"required,email"`
+	MobileNumber string `json:"mobile_number" validate:"required,numeric,min=8,max=15"`
+	Channel      string `json:"channel" validate:"omitempty,oneof=EMAIL SMS"`
+}
+
+type RegistrationResponse struct {
+	Status int `json:"status"`
+	Data   struct {
+		RegistrationID string `json:"registration_id"`
+		Channel        string `json:"channel"`
+		Recipient      string `json:"recipient"`
+		CodeExpires    string `json:"code_expires"`
+	} `json:"data"`
+	Message string `json:"message"`
+}
+
+// RegistrationVerifyRequest verifies a registration with the code that was sent
+type RegistrationVerifyRequest struct {
+	RegistrationID string `json:"registration_id" validate:"required"`
+	Code           string `json:"code" validate:"required,numeric,len=6"`
+}
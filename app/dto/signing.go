@@ -0,0 +1,31 @@
+package dto
+
+// JsonWebKey is the public half of an access token signing key, as published in a JSON Web Key Set (RFC 7517)
+type JsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JsonWebKeySet is served as is, other services read it with standard JWKS clients
+type JsonWebKeySet struct {
+	Keys []JsonWebKey `json:"keys"`
+}
+
+type KeyRotationObject struct {
+	Created     string   `json:"created,omitempty"`
+	ActivatesAt string   `json:"activates_at,omitempty"`
+	Retired     []string `json:"retired"`
+}
+
+type KeyRotationResponse struct {
+	Data    KeyRotationObject `json:"data"`
+	Message string            `json:"message"`
+	Status  int               `json:"status"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+// UserCreateRequest creates a customer or an employee, who sets their password with the code sent to them
+type UserCreateRequest struct {
+	UserName     string   `json:"user_name" validate:"required,max=255"`
+	UserEmail    string   `json:"user_email" validate:"required,email,max=255"`
+	MobileNumber string   `json:"mobile_number" validate:"required,numeric,min=8,max=15"`
+	UserType     string   `json:"user_type" validate:"required,oneof=CUSTOMER EMPLOYEE"`
+	Roles        []string `json:"roles" validate:"omitempty,max=20,dive,required"`
+	Channel      string   `json:"channel" validate:"omitempty,oneof=EMAIL SMS"`
+}
+
+// UserUpdateRequest changes the details of a user, the fields left empty are kept
+type UserUpdateRequest struct {
+	UserID       string `json:"-" validate:"required"`
+	UserName     string `json:"user_name" validate:"omitempty,max=255"`
+	UserEmail    string `json:"user_email" validate:"omitempty,email,max=255"`
+	MobileNumber string `json:"mobile_number" validate:"omitempty,numeric,min=8,max=15"`
+}
+
+// UserListRequest narrows and pages the list of users
+type UserListRequest struct {
+	UserType string `json:"-" validate:"omitempty,oneof=CUSTOMER EMPLOYEE"`
+	Status   string `json:"-" validate:"omitempty,oneof=ACTIVE INACTIVE"`
+	Search   string `json:"-" validate:"max=100"`
+	Page     int    `json:"-" validate:"min=1"`
+	Limit    int    `json:"-" validate:"min=1,max=200"`
+}
+
+// UserStatusRequest deactivates or reactivates a user
+type UserStatusRequest struct {
+	UserID string `json:"-" validate:"required"`
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// UserPasswordResetRequest clears the password of a user and sends them a code to choose a new one
+type UserPasswordResetRequest struct {
+	UserID  string `json:"-" validate:"required"`
+	Channel string `json:"channel" validate:"omitempty,oneof=EMAIL SMS"`
+}
+
+// UserRolesRequest replaces the roles of an employee, an empty list removes them all
+type UserRolesRequest struct {
+	UserID string   `json:"-" validate:"required"`
+	Roles  []string `json:"roles" validate:"required,max=20,dive,required"`
+}
+
+type UserDetailObject struct {
+	UserID             string   `json:"user_id"`
+	UserName           string   `json:"user_name"`
+	UserEmail          string   `json:"user_email"`
+	MobileNumber       string   `json:"mobile_number"`
+	UserType           string   `json:"user_type"`
+	Status             string   `json:"status"`
+	Roles              []string `json:"roles,omitempty"`
+	DeactivatedAt      string   `json:"deactivated_at,omitempty"`
+	DeactivatedBy      string   `json:"deactivated_by,omitempty"`
+	DeactivationReason string   `json:"deactivation_reason,omitempty"`
+	CreatedAt          string   `json:"created_at"`
+}
+
+type UserDetailResponse struct {
+	Data    UserDetailObject `json:"data"`
+	Message string           `json:"message"`
+	Status  int              `json:"status"`
+}
+
+type UserListResponse struct {
+	Data struct {
+		Users []UserDetailObject `json:"users"`
+		Total int64              `json:"total"`
+		Page  int                `json:"page"`
+		Limit int                `json:"limit"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
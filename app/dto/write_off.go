@@ -0,0 +1,62 @@
+package dto
+
+type WriteOffRequest struct {
+	ApplicationID string `json:"-" validate:"required"`
+	Reason        string `json:"reason" validate:"required,max=255"`
+}
+
+type WriteOffResponse struct {
+	Data struct {
+		WriteOffId    string `json:"write_off_id"`
+		ApplicationId string `json:"application_id"`
+		Status        string `json:"status"`
+		Outstanding   string `json:"outstanding"`
+		Principal     string `json:"principal"`
+		Interest      string `json:"interest"`
+		Fees          string `json:"fees"`
+		WrittenOffAt  string `json:"written_off_at"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type WriteOffReportRequest struct {
+	From         string `json:"-" validate:"required,datetime=2006-01-02"`
+	To           string `json:"-" validate:"required,datetime=2006-01-02"`
+	CurrencyCode string `json:"-" validate:"omitempty,len=3"`
+}
+
+type WriteOffObject struct {
+	WriteOffId    string `json:"write_off_id"`
+	ApplicationId string `json:"application_id"`
+	CurrencyCode  string `json:"currency"`
+	WrittenOffAt  string `json:"written_off_at"`
+	WrittenOffBy  string `json:"written_off_by"`
+	Reason        string `json:"reason"`
+	Outstanding   string `json:"outstanding"`
+	Principal     string `json:"principal"`
+	Interest      string `json:"interest"`
+	Fees          string `json:"fees"`
+	Recovered     string `json:"recovered"`
+}
+
+type WriteOffTotal struct {
+	CurrencyCode string `json:"currency"`
+	Count        int    `json:"count"`
+	Outstanding  string `json:"outstanding"`
+	Principal    string `json:"principal"`
+	Interest     string `json:"interest"`
+	Fees         string `json:"fees"`
+	Recovered    string `json:"recovered"`
+}
+
+type WriteOffReportResponse struct {
+	Data struct {
+		From      string           `json:"from"`
+		To        string           `json:"to"`
+		WriteOffs []WriteOffObject `json:"write_offs"`
+		Totals    []WriteOffTotal  `json:"totals"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
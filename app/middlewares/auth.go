@@ -1,28 +1,106 @@
 package middlewares
 
 import (
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
-	"github.com/nishanthrk/aspire-lms/app/common/constants"
-	cfg "github.com/nishanthrk/aspire-lms/app/configs"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	signingService "github.com/nishanthrk/aspire-lms/app/services/signing"
 	"net/http"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	jwtware "github.com/gofiber/jwt/v2"
 )
 
+// authScheme is the scheme of the Authorization header carrying the access token
+const authScheme = "Bearer"
+
+// errMissingToken is reported when the request carries no access token
+var errMissingToken = errors.New("Missing or malformed JWT")
+
 // RequireLoggedIn ensures access only to login users by checking for token presence and validity
+// Tokens are verified against the key set, the key is the one named by their kid header. Tokens of a session that
+// was signed out, or revoked after its refresh token was replayed, are refused, and so are the tokens of
+// deactivated users
 func RequireLoggedIn() fiber.Handler {
-	return jwtware.New(jwtware.Config{
-		SigningKey:   []byte(cfg.GetConfig().JWTAccessSecret),
-		ErrorHandler: jwtError,
-	})
+	return verifyAccessToken(jwtError, requireActiveSession)
+}
+
+// verifyAccessToken reads the bearer token of a request and verifies it against the signing key set, a valid token
+// is stored as the "user" local
+// Parameters:
+// - errorHandler: fiber.ErrorHandler answering a request without a valid token
+// - successHandler: fiber.Handler run for a valid token
+// Returns:
+// - fiber.Handler verifying the token
+func verifyAccessToken(errorHandler fiber.ErrorHandler, successHandler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := c.Get(fiber.HeaderAuthorization)
+		if len(auth) <= len(authScheme)+1 || !strings.EqualFold(auth[:len(authScheme)], authScheme) {
+			return errorHandler(c, errMissingToken)
+		}
+
+		token, err := jwt.Parse(auth[len(authScheme)+1:], signingService.VerificationKey)
+		if err != nil || !token.Valid {
+			if err == nil {
+				err = fmt.Errorf("access token is invalid")
+			}
+			return errorHandler(c, err)
+		}
+		c.Locals("user", token)
+		return successHandler(c)
+	}
+}
+
+// requireActiveSession refuses access tokens whose session has been revoked or whose user has been deactivated.
+// Tokens issued before sessions were introduced carry no session and are accepted until they expire, unless their
+// user is deactivated.
+func requireActiveSession(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	sessionId, _ := claims["sid"].(string)
+	if sessionId != "" {
+		session := models.AuthSession{}
+		session, err := session.FindByPrimaryKey(sessionId)
+		if err != nil || session.Status != models.AuthSessionStatusActive {
+			return unauthorized(c, "Session Has Been Signed Out")
+		}
+	}
+
+	// Deactivation takes effect at once, not when the token expires
+	userId, _ := claims["user_id"].(string)
+	account := models.User{}
+	account, err := account.FindByPrimaryKey(userId)
+	if err != nil || account.UserID == "" {
+		if err != nil {
+			logger.Sugar.Error("could not read status of user ", userId, ": ", err)
+		}
+		return unauthorized(c, "Invalid or Expired Authentication Token")
+	}
+	if account.Status == models.UserStatusInactive {
+		return unauthorized(c, "Account Has Been Deactivated")
+	}
+	return c.Next()
+}
+
+// unauthorized answers a request with a 401 and the reason its token was refused
+func unauthorized(c *fiber.Ctx, message string) error {
+	var errorList []*fiber.Error
+	errorList = append(
+		errorList,
+		&fiber.Error{
+			Code:    fiber.StatusUnauthorized,
+			Message: message,
+		},
+	)
+	return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"errors": errorList})
 }
 
 func OptionalAuth() fiber.Handler {
-	return jwtware.New(jwtware.Config{
-		SigningKey: []byte(cfg.GetConfig().JWTAccessSecret), // Replace with your actual secret key
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
+	return verifyAccessToken(
+		func(c *fiber.Ctx, err error) error {
 			if c.Get("X-Platform") == "" {
 				var errorList []*fiber.Error
 				errorList = append(
@@ -38,7 +116,10 @@ func OptionalAuth() fiber.Handler {
 			fmt.Println("Authentication error:", err)
 			return c.Next()
 		},
-	})
+		func(c *fiber.Ctx) error {
+			return c.Next()
+		},
+	)
 }
 
 func jwtError(c *fiber.Ctx, err error) error {
@@ -54,7 +135,7 @@ func jwtError(c *fiber.Ctx, err error) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(fiber.Map{"errors": errorList})
 	}
 
-	if err.Error() == "Missing or malformed JWT" {
+	if errors.Is(err, errMissingToken) {
 		var errorList []*fiber.Error
 		errorList = append(
 			errorList,
@@ -77,24 +158,68 @@ func jwtError(c *fiber.Ctx, err error) error {
 	return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"errors": errorList})
 }
 
-// RequireAdmin Ensures A route Can Only Be Accessed by an Admin user
-// This function can be extended to handle different roles
-func RequireAdmin(c *fiber.Ctx) error {
-	user := c.Locals("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	role := claims["user_type"].(string)
+// permissionSource looks up the permissions a route check cannot read from the access token
+type permissionSource interface {
+	// UserAccess loads the roles of a user and the permissions they grant
+	UserAccess(userId string) (authorizationService.Access, error)
 
-	var errorList []*fiber.Error
+	// HasDelegatedPermission reports whether an out-of-office employee forwarded any of the wanted permissions
+	HasDelegatedPermission(userId string, wanted ...string) bool
+}
 
-	if role != constants.UserTypeEmployee {
-		errorList = append(
-			errorList,
-			&fiber.Error{
-				Code:    fiber.StatusUnauthorized,
-				Message: "You're Not Authorized",
-			},
-		)
-		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"errors": errorList})
+// storedPermissions looks the permissions up with the authorization service
+type storedPermissions struct{}
+
+func (storedPermissions) UserAccess(userId string) (authorizationService.Access, error) {
+	return authorizationService.GetUserAccess(userId)
+}
+
+func (storedPermissions) HasDelegatedPermission(userId string, wanted ...string) bool {
+	return authorizationService.HasDelegatedPermission(userId, wanted...)
+}
+
+// RequirePermission ensures a route can only be accessed by users whose roles grant any of the given permissions.
+// The permissions are read from the access token, tokens issued before roles existed carry none and have them
+// looked up instead. Permissions forwarded by an out-of-office employee are looked up when the user's own fall short.
+func RequirePermission(permissions ...string) fiber.Handler {
+	return requirePermission(storedPermissions{}, permissions...)
+}
+
+// requirePermission is RequirePermission with the permissions missing from the token looked up in the given source
+func requirePermission(source permissionSource, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := c.Locals("user").(*jwt.Token)
+		claims := user.Claims.(jwt.MapClaims)
+		userId, _ := claims["user_id"].(string)
+
+		var held []string
+		if granted, ok := claims["permissions"].([]interface{}); ok {
+			for _, permission := range granted {
+				if code, ok := permission.(string); ok {
+					held = append(held, code)
+				}
+			}
+		} else {
+			access, err := source.UserAccess(userId)
+			if err != nil {
+				logger.Sugar.Error("could not read permissions of user ", userId, ": ", err)
+			}
+			held = access.Permissions
+		}
+
+		// The delegate of an out-of-office employee is forwarded their approval permissions
+		if !authorizationService.HasPermission(held, permissions...) &&
+			!source.HasDelegatedPermission(userId, permissions...) {
+			var errorList []*fiber.Error
+			errorList = append(
+				errorList,
+				&fiber.Error{
+					Code:    fiber.StatusForbidden,
+					Message: "You're Not Authorized",
+				},
+			)
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{"errors": errorList})
+		}
+		return c.Next()
 	}
-	return c.Next()
 }
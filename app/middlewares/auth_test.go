@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryPermissionSource holds the permissions of users and the ones delegated to them in memory, and records which
+// lookups were made
+type memoryPermissionSource struct {
+	permissions       map[string][]string
+	delegated         map[string][]string
+	accessLookups     int
+	delegationLookups int
+}
+
+func (s *memoryPermissionSource) UserAccess(userId string) (authorizationService.Access, error) {
+	s.accessLookups++
+	return authorizationService.Access{Permissions: s.permissions[userId]}, nil
+}
+
+func (s *memoryPermissionSource) HasDelegatedPermission(userId string, wanted ...string) bool {
+	s.delegationLookups++
+	return authorizationService.HasPermission(s.delegated[userId], wanted...)
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name              string
+		claims            jwt.MapClaims
+		permission        string
+		status            int
+		accessLookups     int
+		delegationLookups int
+	}{
+		{
+			name: "permission in the token",
+			claims: jwt.MapClaims{"user_id": "employee_id",
+				"permissions": []interface{}{models.PermissionReportView}},
+			permission: models.PermissionReportView,
+			status:     http.StatusOK,
+		},
+		{
+			name:          "token without permissions is looked up",
+			claims:        jwt.MapClaims{"user_id": "legacy_id"},
+			permission:    models.PermissionReportView,
+			status:        http.StatusOK,
+			accessLookups: 1,
+		},
+		{
+			name: "permission delegated by an out-of-office employee",
+			claims: jwt.MapClaims{"user_id": "delegate_id",
+				"permissions": []interface{}{models.PermissionReportView}},
+			permission:        models.PermissionApplicationApprove,
+			status:            http.StatusOK,
+			delegationLookups: 1,
+		},
+		{
+			name: "permission neither held nor delegated",
+			claims: jwt.MapClaims{"user_id": "employee_id",
+				"permissions": []interface{}{models.PermissionReportView}},
+			permission:        models.PermissionApplicationApprove,
+			status:            http.StatusForbidden,
+			delegationLookups: 1,
+		},
+		{
+			name:              "token without permissions and none looked up",
+			claims:            jwt.MapClaims{"user_id": "customer_id"},
+			permission:        models.PermissionReportView,
+			status:            http.StatusForbidden,
+			accessLookups:     1,
+			delegationLookups: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := &memoryPermissionSource{
+				permissions: map[string][]string{"legacy_id": {models.PermissionReportView}},
+				delegated:   map[string][]string{"delegate_id": {models.PermissionApplicationApprove}},
+			}
+
+			claims := test.claims
+			app := fiber.New()
+			app.Get("/v1/report", func(c *fiber.Ctx) error {
+				c.Locals("user", &jwt.Token{Claims: claims, Valid: true})
+				return c.Next()
+			}, requirePermission(source, test.permission), func(c *fiber.Ctx) error {
+				return c.JSON(fiber.Map{"status": 1})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/report", nil)
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, test.status, resp.StatusCode)
+			assert.Equal(t, test.accessLookups, source.accessLookups)
+			assert.Equal(t, test.delegationLookups, source.delegationLookups)
+
+			if test.status == http.StatusForbidden {
+				var response map[string][]map[string]interface{}
+				err = json.NewDecoder(resp.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "You're Not Authorized", response["errors"][0]["message"])
+			}
+		})
+	}
+}
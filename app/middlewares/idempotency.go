@@ -0,0 +1,228 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	cfg "github.com/nishanthrk/aspire-lms/app/configs"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client supplied idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// errIdempotencyKeyTaken is returned by a store creating a key the user already holds
+var errIdempotencyKeyTaken = fmt.Errorf("idempotency key is already held")
+
+// idempotencyStore keeps the idempotency keys and the responses remembered for them
+type idempotencyStore interface {
+	// Find returns the key sent by a user, an empty record when there is none
+	Find(key string, userId string) (models.IdempotencyKey, error)
+
+	// DeleteExpired removes the keys whose retention window has elapsed
+	DeleteExpired() error
+
+	// Create stores a new key, it fails with errIdempotencyKeyTaken when the user already holds the key
+	Create(record *models.IdempotencyKey) error
+
+	// Save stores the response remembered for a key
+	Save(record *models.IdempotencyKey) error
+
+	// Delete releases a key so that it can be used again
+	Delete(record *models.IdempotencyKey) error
+}
+
+// mysqlIdempotencyStore keeps the idempotency keys in the idempotency_key table
+type mysqlIdempotencyStore struct{}
+
+func (mysqlIdempotencyStore) Find(key string, userId string) (models.IdempotencyKey, error) {
+	var condition []db.WhereCondition
+	condition = append(condition, db.WhereCondition{
+		Key:       models.IdempotencyKeyColumns.IdempotencyKey,
+		Condition: "=",
+		Value:     key,
+	})
+	condition = append(condition, db.WhereCondition{
+		Key:       models.IdempotencyKeyColumns.UserID,
+		Condition: "=",
+		Value:     userId,
+	})
+
+	record := models.IdempotencyKey{}
+	return record.FindOneByCondition(condition)
+}
+
+func (mysqlIdempotencyStore) DeleteExpired() error {
+	record := models.IdempotencyKey{}
+	return record.DeleteExpired()
+}
+
+func (mysqlIdempotencyStore) Create(record *models.IdempotencyKey) error {
+	err := db.MysqlDB.Create(record).Error
+	if db.IsDuplicateKey(err) {
+		return errIdempotencyKeyTaken
+	}
+	return err
+}
+
+func (mysqlIdempotencyStore) Save(record *models.IdempotencyKey) error {
+	return db.MysqlDB.Save(record).Error
+}
+
+func (mysqlIdempotencyStore) Delete(record *models.IdempotencyKey) error {
+	return db.MysqlDB.Delete(record).Error
+}
+
+// Idempotency makes a route safe to retry by remembering the response of the first request sent with a
+// given Idempotency-Key. Repeated requests with the same key and payload get the stored response back,
+// while reusing the key with a different payload is rejected. Requests without the header pass through.
+func Idempotency() fiber.Handler {
+	return idempotency(mysqlIdempotencyStore{})
+}
+
+// idempotency is Idempotency with the keys kept in the given store
+func idempotency(store idempotencyStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+
+		if len(key) > 255 {
+			return idempotencyError(c, http.StatusUnprocessableEntity, "Idempotency-Key must not exceed 255 characters")
+		}
+
+		userId := getClaimUserId(c)
+		requestHash := hashRequest(c)
+
+		record, err := store.Find(key, userId)
+		if err != nil {
+			logger.Sugar.Error("failed to read idempotency key: ", err)
+			return idempotencyError(c, http.StatusServiceUnavailable,
+				"Idempotency-Key could not be checked, try again later")
+		}
+
+		// Keys past their retention window are forgotten and can be used again
+		if record.IdempotencyKeyID != "" && !record.ExpiresAt.After(time.Now()) {
+			record = models.IdempotencyKey{}
+		}
+
+		if record.IdempotencyKeyID != "" {
+			if record.RequestHash != requestHash {
+				return idempotencyError(c, http.StatusUnprocessableEntity,
+					"Idempotency-Key has already been used with a different request")
+			}
+
+			if !record.ResponseStatus.Valid {
+				return idempotencyError(c, http.StatusConflict,
+					"A request with this Idempotency-Key is still being processed")
+			}
+
+			// Replay the original response
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(int(record.ResponseStatus.Int64)).SendString(record.ResponseBody.String)
+		}
+
+		// Purge keys whose retention window has elapsed before remembering a new one
+		if err := store.DeleteExpired(); err != nil {
+			logger.Sugar.Error("failed to purge expired idempotency keys: ", err)
+		}
+
+		record = models.IdempotencyKey{
+			IdempotencyKeyID: uuid.New().String(),
+			IdempotencyKey:   key,
+			UserID:           userId,
+			RequestMethod:    c.Method(),
+			RequestPath:      c.Path(),
+			RequestHash:      requestHash,
+			ExpiresAt:        time.Now().Add(cfg.GetConfig().GetIdempotencyTTL()),
+		}
+
+		// The unique index on (idempotency_key, user_id) guards against two concurrent first attempts
+		if err = store.Create(&record); err == errIdempotencyKeyTaken {
+			return idempotencyError(c, http.StatusConflict,
+				"A request with this Idempotency-Key is still being processed")
+		} else if err != nil {
+			logger.Sugar.Error("failed to store idempotency key: ", err)
+			return idempotencyError(c, http.StatusServiceUnavailable,
+				"Idempotency-Key could not be stored, try again later")
+		}
+
+		// The key is released unless a response was remembered for it, also when the handler fails or panics, so
+		// that the request can be retried with the same key
+		remembered := false
+		defer func() {
+			if remembered {
+				return
+			}
+			if err := store.Delete(&record); err != nil {
+				logger.Sugar.Error("failed to release idempotency key: ", err)
+			}
+		}()
+
+		if err = c.Next(); err != nil {
+			return err
+		}
+
+		// Only successful responses are remembered so that failed attempts can be retried with the same key
+		status := c.Response().StatusCode()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return nil
+		}
+
+		record.ResponseStatus = null.IntFrom(int64(status))
+		record.ResponseBody = null.StringFrom(string(c.Response().Body()))
+		if err = store.Save(&record); err != nil {
+			logger.Sugar.Error("failed to store idempotent response: ", err)
+			return nil
+		}
+		remembered = true
+
+		return nil
+	}
+}
+
+// hashRequest fingerprints the method, path and body so that key reuse with another payload can be detected
+func hashRequest(c *fiber.Ctx) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(c.Method()))
+	hasher.Write([]byte(c.Path()))
+	hasher.Write(c.Body())
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// getClaimUserId returns the user_id claim of the authenticated user, or an empty string
+func getClaimUserId(c *fiber.Ctx) string {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	userId, _ := claims["user_id"].(string)
+	return userId
+}
+
+func idempotencyError(c *fiber.Ctx, status int, message string) error {
+	var errorList []*fiber.Error
+	errorList = append(
+		errorList,
+		&fiber.Error{
+			Code:    status,
+			Message: message,
+		},
+	)
+	return c.Status(status).JSON(fiber.Map{"errors": errorList})
+}
@@ -0,0 +1,290 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotencyStore keeps the idempotency keys in memory, unique per key and user like the table. findErr and
+// createErr stand in for an unavailable database.
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]models.IdempotencyKey
+	findErr   error
+	createErr error
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]models.IdempotencyKey)}
+}
+
+func (s *memoryIdempotencyStore) Find(key string, userId string) (models.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findErr != nil {
+		return models.IdempotencyKey{}, s.findErr
+	}
+	return s.records[key+"|"+userId], nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, record := range s.records {
+		if !record.ExpiresAt.After(time.Now()) {
+			delete(s.records, id)
+		}
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Create(record *models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.createErr != nil {
+		return s.createErr
+	}
+	id := record.IdempotencyKey + "|" + record.UserID
+	if _, ok := s.records[id]; ok {
+		return errIdempotencyKeyTaken
+	}
+	s.records[id] = *record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Save(record *models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.IdempotencyKey+"|"+record.UserID] = *record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Delete(record *models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, record.IdempotencyKey+"|"+record.UserID)
+	return nil
+}
+
+// expire moves the retention window of every key into the past
+func (s *memoryIdempotencyStore) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, record := range s.records {
+		record.ExpiresAt = time.Now().Add(-time.Minute)
+		s.records[id] = record
+	}
+}
+
+// setIdempotencyConfig sets the configuration the middleware reads, GetConfig refuses a non numeric DB_PORT, and the
+// logger store failures are reported to
+func setIdempotencyConfig(t *testing.T) {
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("IDEMPOTENCY_KEY_TTL", "24h")
+	logger.InitLogger()
+}
+
+// newIdempotentApp returns an app with a repayment route counting how often its handler runs
+func newIdempotentApp(store idempotencyStore, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Post("/repayment", idempotency(store), handler)
+	return app
+}
+
+func newIdempotentRequest(key string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/repayment", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	setIdempotencyConfig(t)
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	app := newIdempotentApp(store, func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1, "payment_id": fmt.Sprintf("payment_%d", calls)})
+	})
+
+	resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	first, _ := io.ReadAll(resp.Body)
+
+	resp, err = app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	replayed, _ := io.ReadAll(resp.Body)
+
+	// The handler ran once, the second request got the first response back
+	assert.Equal(t, 1, calls)
+	assert.JSONEq(t, string(first), string(replayed))
+}
+
+func TestIdempotency_DifferentBody(t *testing.T) {
+	setIdempotencyConfig(t)
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	app := newIdempotentApp(store, func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1})
+	})
+
+	resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(newIdempotentRequest("key_1", `{"amount": 200}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+
+	var responseBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&responseBody)
+	errors := responseBody["errors"].([]interface{})
+	assert.Equal(t, "Idempotency-Key has already been used with a different request",
+		errors[0].(map[string]interface{})["message"])
+}
+
+func TestIdempotency_KeyInFlight(t *testing.T) {
+	setIdempotencyConfig(t)
+	store := newMemoryIdempotencyStore()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app := newIdempotentApp(store, func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1})
+	})
+
+	// The first request holds the key until it is released
+	done := make(chan int)
+	go func() {
+		resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+		assert.NoError(t, err)
+		done <- resp.StatusCode
+	}()
+	<-started
+
+	resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestIdempotency_FailedResponseNotStored(t *testing.T) {
+	setIdempotencyConfig(t)
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	app := newIdempotentApp(store, func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			return c.Status(http.StatusUnprocessableEntity).JSON(fiber.Map{"status": -1, "error": "loan is locked"})
+		}
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1})
+	})
+
+	resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Empty(t, store.records)
+
+	// The retry with the same key runs the handler again
+	resp, err = app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ExpiredKeyReused(t *testing.T) {
+	setIdempotencyConfig(t)
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	app := newIdempotentApp(store, func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1})
+	})
+
+	resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	store.expire()
+
+	// Once expired the key is accepted again, even with another body
+	resp, err = app.Test(newIdempotentRequest("key_1", `{"amount": 200}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, 2, calls)
+	assert.Len(t, store.records, 1)
+}
+
+func TestIdempotency_StoreUnavailable(t *testing.T) {
+	setIdempotencyConfig(t)
+	handler := func(c *fiber.Ctx) error {
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1})
+	}
+
+	tests := []struct {
+		name  string
+		store *memoryIdempotencyStore
+	}{
+		{name: "key cannot be read", store: &memoryIdempotencyStore{records: map[string]models.IdempotencyKey{},
+			findErr: fmt.Errorf("connection refused")}},
+		{name: "key cannot be stored", store: &memoryIdempotencyStore{records: map[string]models.IdempotencyKey{},
+			createErr: fmt.Errorf("connection refused")}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A store failure is not mistaken for a request in flight, and the handler does not run unguarded
+			resp, err := newIdempotentApp(test.store, handler).Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		})
+	}
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	setIdempotencyConfig(t)
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	app := fiber.New()
+	app.Use(recover.New())
+	app.Post("/repayment", idempotency(store), func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": 1})
+	})
+
+	resp, err := app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Empty(t, store.records)
+
+	// The retry is processed rather than refused as still in flight
+	resp, err = app.Test(newIdempotentRequest("key_1", `{"amount": 100}`), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, calls)
+}
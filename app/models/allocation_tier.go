@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// AllocationTier [...]
+type AllocationTier struct {
+	TierID       string     `gorm:"primaryKey;column:tier_id" json:"tierId"`
+	CurrencyCode string     `gorm:"column:currency_code" json:"currencyCode"`
+	MinAmount    float64    `gorm:"column:min_amount" json:"minAmount"`
+	MaxAmount    null.Float `gorm:"column:max_amount" json:"maxAmount"`
+	RoleCode     string     `gorm:"column:role_code" json:"roleCode"`
+	CreatedAt    time.Time  `gorm:"column:created_at" json:"-"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *AllocationTier) TableName() string {
+	return "allocation_tier"
+}
+
+// AllocationTierColumns get sql column name.
+var AllocationTierColumns = struct {
+	TierID       string
+	CurrencyCode string
+	MinAmount    string
+	MaxAmount    string
+	RoleCode     string
+	CreatedAt    string
+	UpdatedAt    string
+}{
+	TierID:       "tier_id",
+	CurrencyCode: "currency_code",
+	MinAmount:    "min_amount",
+	MaxAmount:    "max_amount",
+	RoleCode:     "role_code",
+	CreatedAt:    "created_at",
+	UpdatedAt:    "updated_at",
+}
+
+// FindForAmount returns the tiers of a currency whose range covers an amount, a tier without max_amount has no upper bound
+func (m *AllocationTier) FindForAmount(currencyCode string, amount float64) (results []AllocationTier, err error) {
+	err = database.MysqlDB.Model(m).
+		Where("currency_code = ? AND min_amount <= ? AND (max_amount IS NULL OR max_amount >= ?)",
+			currencyCode, amount, amount).
+		Find(&results).Error
+	return
+}
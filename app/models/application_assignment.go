@@ -0,0 +1,78 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ApplicationAssignment [...]
+type ApplicationAssignment struct {
+	AssignmentID  string      `gorm:"primaryKey;column:assignment_id" json:"assignmentId"`
+	ApplicationID string      `gorm:"column:application_id" json:"applicationId"`
+	UserID        string      `gorm:"column:user_id" json:"userId"`
+	AssignedBy    null.String `gorm:"column:assigned_by" json:"assignedBy"`
+	Reason        null.String `gorm:"column:reason" json:"reason"`
+	StartedAt     time.Time   `gorm:"column:started_at" json:"startedAt"`
+	EndedAt       null.Time   `gorm:"column:ended_at" json:"endedAt"`
+	CreatedAt     time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt     time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *ApplicationAssignment) TableName() string {
+	return "application_assignment"
+}
+
+// ApplicationAssignmentColumns get sql column name.
+var ApplicationAssignmentColumns = struct {
+	AssignmentID  string
+	ApplicationID string
+	UserID        string
+	AssignedBy    string
+	Reason        string
+	StartedAt     string
+	EndedAt       string
+	CreatedAt     string
+	UpdatedAt     string
+}{
+	AssignmentID:  "assignment_id",
+	ApplicationID: "application_id",
+	UserID:        "user_id",
+	AssignedBy:    "assigned_by",
+	Reason:        "reason",
+	StartedAt:     "started_at",
+	EndedAt:       "ended_at",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
+}
+
+// FindByApplication returns who held an application when, the earliest first
+func (m *ApplicationAssignment) FindByApplication(applicationId string) (results []ApplicationAssignment, err error) {
+	err = database.MysqlDB.Model(m).Where("application_id = ?", applicationId).
+		Order("started_at").Order("created_at").Find(&results).Error
+	return
+}
+
+// FindOpenByApplicationForUpdate returns the employees currently holding an application, the latest first, and locks
+// them until the transaction ends
+func (m *ApplicationAssignment) FindOpenByApplicationForUpdate(tx *gorm.DB, applicationId string) (
+	results []ApplicationAssignment, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("application_id = ? AND ended_at IS NULL", applicationId).
+		Order("started_at DESC").Find(&results).Error
+	return
+}
+
+// FindPendingApplicationIDsByUser returns the pending applications an employee currently holds
+func (m *ApplicationAssignment) FindPendingApplicationIDsByUser(userId string) (result []string, err error) {
+	err = database.MysqlDB.Model(m).
+		Joins("JOIN loan_application ON loan_application.application_id = application_assignment.application_id").
+		Where("application_assignment.user_id = ? AND application_assignment.ended_at IS NULL "+
+			"AND loan_application.status = ?", userId, LoanApplicationStatusPending).
+		Distinct("application_assignment.application_id").
+		Pluck("application_assignment.application_id", &result).Error
+	return
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// ApprovalAuthority [...]
+type ApprovalAuthority struct {
+	AuthorityID  string      `gorm:"primaryKey;column:authority_id" json:"authorityId"`
+	UserID       null.String `gorm:"column:user_id" json:"userId"`
+	RoleCode     null.String `gorm:"column:role_code" json:"roleCode"`
+	CurrencyCode string      `gorm:"column:currency_code" json:"currencyCode"`
+	MaxAmount    null.Float  `gorm:"column:max_amount" json:"maxAmount"`
+	CreatedAt    time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt    time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *ApprovalAuthority) TableName() string {
+	return "approval_authority"
+}
+
+// ApprovalAuthorityColumns get sql column name.
+var ApprovalAuthorityColumns = struct {
+	AuthorityID  string
+	UserID       string
+	RoleCode     string
+	CurrencyCode string
+	MaxAmount    string
+	CreatedAt    string
+	UpdatedAt    string
+}{
+	AuthorityID:  "authority_id",
+	UserID:       "user_id",
+	RoleCode:     "role_code",
+	CurrencyCode: "currency_code",
+	MaxAmount:    "max_amount",
+	CreatedAt:    "created_at",
+	UpdatedAt:    "updated_at",
+}
+
+// FindForUser returns the approval authorities in a currency given to a user directly or through any of their roles
+func (m *ApprovalAuthority) FindForUser(userId string, roles []string, currencyCode string) (
+	results []ApprovalAuthority, err error) {
+	db := database.MysqlDB.Model(m).Where("currency_code = ?", currencyCode)
+	if len(roles) > 0 {
+		db = db.Where("user_id = ? OR role_code IN ?", userId, roles)
+	} else {
+		db = db.Where("user_id = ?", userId)
+	}
+	err = db.Find(&results).Error
+	return
+}
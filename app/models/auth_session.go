@@ -0,0 +1,69 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// AuthSession [...]
+type AuthSession struct {
+	SessionID       string      `gorm:"primaryKey;column:session_id" json:"sessionId"`
+	UserID          string      `gorm:"column:user_id" json:"userId"`
+	Status          string      `gorm:"column:status" json:"status"`
+	RevokedReason   null.String `gorm:"column:revoked_reason" json:"revokedReason"`
+	RevokedAt       null.Time   `gorm:"column:revoked_at" json:"revokedAt"`
+	LastRefreshedAt null.Time   `gorm:"column:last_refreshed_at" json:"lastRefreshedAt"`
+	CreatedAt       time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt       time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *AuthSession) TableName() string {
+	return "auth_session"
+}
+
+// AuthSessionColumns get sql column name.
+var AuthSessionColumns = struct {
+	SessionID       string
+	UserID          string
+	Status          string
+	RevokedReason   string
+	RevokedAt       string
+	LastRefreshedAt string
+	CreatedAt       string
+	UpdatedAt       string
+}{
+	SessionID:       "session_id",
+	UserID:          "user_id",
+	Status:          "status",
+	RevokedReason:   "revoked_reason",
+	RevokedAt:       "revoked_at",
+	LastRefreshedAt: "last_refreshed_at",
+	CreatedAt:       "created_at",
+	UpdatedAt:       "updated_at",
+}
+
+func (m *AuthSession) FindByPrimaryKey(sessionId string) (result AuthSession, err error) {
+	err = database.MysqlDB.Model(m).Where("session_id = ?", sessionId).Find(&result).Error
+	return
+}
+
+func (m *AuthSession) FindByPrimaryKeyForUpdate(tx *gorm.DB, sessionId string) (result AuthSession, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("session_id = ?", sessionId).Find(&result).Error
+	return
+}
+
+// RevokeByUser revokes every active session of a user, except the one given
+func (m *AuthSession) RevokeByUser(tx *gorm.DB, userId string, exceptSessionId string, reason string) error {
+	return tx.Model(m).
+		Where("user_id = ? AND status = ? AND session_id <> ?", userId, AuthSessionStatusActive, exceptSessionId).
+		Updates(map[string]interface{}{
+			AuthSessionColumns.Status:        AuthSessionStatusRevoked,
+			AuthSessionColumns.RevokedReason: reason,
+			AuthSessionColumns.RevokedAt:     time.Now(),
+		}).Error
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// BankStatement [...]
+type BankStatement struct {
+	StatementID    string    `gorm:"primaryKey;column:statement_id" json:"-"`
+	FileName       string    `gorm:"column:file_name" json:"fileName"`
+	Format         string    `gorm:"column:format" json:"format"`
+	FileHash       string    `gorm:"column:file_hash" json:"fileHash"`
+	TotalLines     int       `gorm:"column:total_lines" json:"totalLines"`
+	PostedLines    int       `gorm:"column:posted_lines" json:"postedLines"`
+	DuplicateLines int       `gorm:"column:duplicate_lines" json:"duplicateLines"`
+	IgnoredLines   int       `gorm:"column:ignored_lines" json:"ignoredLines"`
+	ExceptionLines int       `gorm:"column:exception_lines" json:"exceptionLines"`
+	ImportedBy     string    `gorm:"column:imported_by" json:"importedBy"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt      time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *BankStatement) TableName() string {
+	return "bank_statement"
+}
+
+// BankStatementColumns get sql column name.
+var BankStatementColumns = struct {
+	StatementID    string
+	FileName       string
+	Format         string
+	FileHash       string
+	TotalLines     string
+	PostedLines    string
+	DuplicateLines string
+	IgnoredLines   string
+	ExceptionLines string
+	ImportedBy     string
+	CreatedAt      string
+	UpdatedAt      string
+}{
+	StatementID:    "statement_id",
+	FileName:       "file_name",
+	Format:         "format",
+	FileHash:       "file_hash",
+	TotalLines:     "total_lines",
+	PostedLines:    "posted_lines",
+	DuplicateLines: "duplicate_lines",
+	IgnoredLines:   "ignored_lines",
+	ExceptionLines: "exception_lines",
+	ImportedBy:     "imported_by",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
+}
+
+func (m *BankStatement) FindByPrimaryKey(statementId string) (result BankStatement, err error) {
+	err = database.MysqlDB.Model(m).Where("statement_id = ?", statementId).Find(&result).Error
+	return
+}
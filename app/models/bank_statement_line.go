@@ -0,0 +1,87 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// BankStatementLine [...]
+type BankStatementLine struct {
+	LineID           string      `gorm:"primaryKey;column:line_id" json:"-"`
+	StatementID      string      `gorm:"column:statement_id" json:"statementId"`
+	LineNumber       int         `gorm:"column:line_number" json:"lineNumber"`
+	BookingDate      null.Time   `gorm:"column:booking_date" json:"bookingDate"`
+	Amount           float64     `gorm:"column:amount" json:"amount"`
+	CurrencyCode     string      `gorm:"column:currency_code" json:"currencyCode"`
+	CreditDebit      string      `gorm:"column:credit_debit" json:"creditDebit"`
+	BankReference    null.String `gorm:"column:bank_reference" json:"bankReference"`
+	PaymentReference null.String `gorm:"column:payment_reference" json:"paymentReference"`
+	VirtualAccount   null.String `gorm:"column:virtual_account" json:"virtualAccount"`
+	Counterparty     null.String `gorm:"column:counterparty" json:"counterparty"`
+	Description      null.String `gorm:"column:description" json:"description"`
+	Status           string      `gorm:"column:status" json:"status"`
+	MatchMethod      null.String `gorm:"column:match_method" json:"matchMethod"`
+	ApplicationID    null.String `gorm:"column:application_id" json:"applicationId"`
+	PaymentID        null.String `gorm:"column:payment_id" json:"paymentId"`
+	ExceptionReason  null.String `gorm:"column:exception_reason" json:"exceptionReason"`
+	CreatedAt        time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt        time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *BankStatementLine) TableName() string {
+	return "bank_statement_line"
+}
+
+// BankStatementLineColumns get sql column name.
+var BankStatementLineColumns = struct {
+	LineID           string
+	StatementID      string
+	LineNumber       string
+	BookingDate      string
+	Amount           string
+	CurrencyCode     string
+	CreditDebit      string
+	BankReference    string
+	PaymentReference string
+	VirtualAccount   string
+	Counterparty     string
+	Description      string
+	Status           string
+	MatchMethod      string
+	ApplicationID    string
+	PaymentID        string
+	ExceptionReason  string
+	CreatedAt        string
+	UpdatedAt        string
+}{
+	LineID:           "line_id",
+	StatementID:      "statement_id",
+	LineNumber:       "line_number",
+	BookingDate:      "booking_date",
+	Amount:           "amount",
+	CurrencyCode:     "currency_code",
+	CreditDebit:      "credit_debit",
+	BankReference:    "bank_reference",
+	PaymentReference: "payment_reference",
+	VirtualAccount:   "virtual_account",
+	Counterparty:     "counterparty",
+	Description:      "description",
+	Status:           "status",
+	MatchMethod:      "match_method",
+	ApplicationID:    "application_id",
+	PaymentID:        "payment_id",
+	ExceptionReason:  "exception_reason",
+	CreatedAt:        "created_at",
+	UpdatedAt:        "updated_at",
+}
+
+func (m *BankStatementLine) FindAllByCondition(whereCondition []database.WhereCondition) (
+	results []BankStatementLine, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("line_number asc")
+	err = db.Find(&results).Error
+	return
+}
@@ -1,7 +1,170 @@
 package models
 
 const (
-	LoanApplicationStatusPending  string = "PENDING"
-	LoanApplicationStatusApproved string = "APPROVED"
-	LoanApplicationStatusPaid     string = "PAID"
+	LoanApplicationStatusPending    string = "PENDING"
+	LoanApplicationStatusApproved   string = "APPROVED"
+	LoanApplicationStatusPaid       string = "PAID"
+	LoanApplicationStatusWrittenOff string = "WRITTEN_OFF"
+	LoanApplicationStatusClosed     string = "CLOSED"
+
+	PaymentStatusReceived string = "RECEIVED"
+	PaymentStatusPending  string = "PENDING"
+	PaymentStatusSettled  string = "SETTLED"
+	PaymentStatusFailed   string = "FAILED"
+	PaymentStatusReversed string = "REVERSED"
+
+	RefundStatusProcessed string = "PROCESSED"
+
+	PaymentChannelBankTransfer string = "BANK_TRANSFER"
+	PaymentChannelDirectDebit  string = "DIRECT_DEBIT"
+
+	StatementFormatCSV     string = "CSV"
+	StatementFormatCamt053 string = "CAMT053"
+
+	StatementLineCredit string = "CRDT"
+	StatementLineDebit  string = "DBIT"
+
+	StatementLineStatusPosted    string = "POSTED"
+	StatementLineStatusDuplicate string = "DUPLICATE"
+	StatementLineStatusIgnored   string = "IGNORED"
+	StatementLineStatusException string = "EXCEPTION"
+
+	MatchMethodVirtualAccount   string = "VIRTUAL_ACCOUNT"
+	MatchMethodPaymentReference string = "PAYMENT_REFERENCE"
+	MatchMethodAmount           string = "AMOUNT"
+
+	MandateStatusActive    string = "ACTIVE"
+	MandateStatusSuspended string = "SUSPENDED"
+	MandateStatusCancelled string = "CANCELLED"
+	MandateStatusExpired   string = "EXPIRED"
+
+	MandateFrequencyWeekly      string = "WEEKLY"
+	MandateFrequencyMonthly     string = "MONTHLY"
+	MandateFrequencyAsPresented string = "AS_PRESENTED"
+
+	CollectionStatusPending   string = "PENDING"
+	CollectionStatusSubmitted string = "SUBMITTED"
+	CollectionStatusSucceeded string = "SUCCEEDED"
+	CollectionStatusFailed    string = "FAILED"
+
+	LedgerAccountCash               string = "1000"
+	LedgerAccountLoanPrincipal      string = "1100"
+	LedgerAccountInterestReceivable string = "1110"
+	LedgerAccountFeesReceivable     string = "1120"
+	LedgerAccountLoanLossAllowance  string = "1190"
+	LedgerAccountExcessCredit       string = "2100"
+	LedgerAccountInterestIncome     string = "4000"
+	LedgerAccountFeeIncome          string = "4100"
+	LedgerAccountRecoveryIncome     string = "4200"
+	LedgerAccountWriteOffExpense    string = "5000"
+	LedgerAccountProvisionExpense   string = "5100"
+
+	LedgerAccountTypeAsset     string = "ASSET"
+	LedgerAccountTypeLiability string = "LIABILITY"
+	LedgerAccountTypeEquity    string = "EQUITY"
+	LedgerAccountTypeIncome    string = "INCOME"
+	LedgerAccountTypeExpense   string = "EXPENSE"
+
+	NormalBalanceDebit  string = "DEBIT"
+	NormalBalanceCredit string = "CREDIT"
+
+	JournalEntryDisbursement    string = "DISBURSEMENT"
+	JournalEntryInterestAccrual string = "INTEREST_ACCRUAL"
+	JournalEntryFeeCharge       string = "FEE_CHARGE"
+	JournalEntryRepayment       string = "REPAYMENT"
+	JournalEntryReversal        string = "REVERSAL"
+	JournalEntryRefund          string = "REFUND"
+	JournalEntryWriteOff        string = "WRITE_OFF"
+	JournalEntryAccrualReversal string = "ACCRUAL_REVERSAL"
+	JournalEntryRecovery        string = "RECOVERY"
+	JournalEntryProvision       string = "PROVISION"
+
+	AccrualStatusAccruing   string = "ACCRUING"
+	AccrualStatusNonAccrual string = "NON_ACCRUAL"
+
+	ProductTermLoan string = "TERM_LOAN"
+
+	ProvisionStage1 int = 1
+	ProvisionStage2 int = 2
+	ProvisionStage3 int = 3
+
+	AuthSessionStatusActive  string = "ACTIVE"
+	AuthSessionStatusRevoked string = "REVOKED"
+
+	SessionRevokedLogout         string = "LOGOUT"
+	SessionRevokedTokenReuse     string = "TOKEN_REUSE"
+	SessionRevokedPasswordChange string = "PASSWORD_CHANGE"
+	SessionRevokedPasswordReset  string = "PASSWORD_RESET"
+	SessionRevokedDeactivated    string = "DEACTIVATED"
+	SessionRevokedRolesChanged   string = "ROLES_CHANGED"
+
+	UserStatusActive   string = "ACTIVE"
+	UserStatusInactive string = "INACTIVE"
+
+	RefreshTokenStatusActive string = "ACTIVE"
+	RefreshTokenStatusUsed   string = "USED"
+
+	RegistrationStatusPending  string = "PENDING"
+	RegistrationStatusVerified string = "VERIFIED"
+
+	OtpPurposeRegistration  string = "REGISTRATION"
+	OtpPurposePasswordReset string = "PASSWORD_RESET"
+
+	PasswordResetRequested   string = "REQUESTED"
+	PasswordResetCodeSent    string = "CODE_SENT"
+	PasswordResetSendFailed  string = "SEND_FAILED"
+	PasswordResetRateLimited string = "RATE_LIMITED"
+	PasswordResetFailed      string = "FAILED"
+	PasswordResetCompleted   string = "COMPLETED"
+	PasswordResetByAdmin     string = "ADMIN_RESET"
+
+	MfaMethodTotp string = "TOTP"
+
+	MfaStatusPending string = "PENDING"
+	MfaStatusActive  string = "ACTIVE"
+
+	LoginThrottleIdentifier string = "IDENTIFIER"
+	LoginThrottleIP         string = "IP"
+	LoginThrottleMfa        string = "MFA"
+
+	RoleSuperAdmin       string = "SUPER_ADMIN"
+	RoleUnderwriter      string = "UNDERWRITER"
+	RoleApprover         string = "APPROVER"
+	RoleCollectionsAgent string = "COLLECTIONS_AGENT"
+	RoleFinance          string = "FINANCE"
+	RoleAuditor          string = "AUDITOR"
+
+	PermissionApplicationReadAll  string = "APPLICATION_READ_ALL"
+	PermissionApplicationApprove  string = "APPLICATION_APPROVE"
+	PermissionApplicationOverride string = "APPLICATION_OVERRIDE"
+	PermissionPaymentSync         string = "PAYMENT_SYNC"
+	PermissionPaymentReverse      string = "PAYMENT_REVERSE"
+	PermissionPaymentRefund       string = "PAYMENT_REFUND"
+	PermissionLoanClose           string = "LOAN_CLOSE"
+	PermissionLoanWriteOff        string = "LOAN_WRITE_OFF"
+	PermissionLoanRestructure     string = "LOAN_RESTRUCTURE"
+	PermissionCollectionsRun      string = "COLLECTIONS_RUN"
+	PermissionReconciliation      string = "RECONCILIATION_MANAGE"
+	PermissionProvisionManage     string = "PROVISION_MANAGE"
+	PermissionLedgerManage        string = "LEDGER_MANAGE"
+	PermissionReportView          string = "REPORT_VIEW"
+	PermissionLoginUnlock         string = "LOGIN_UNLOCK"
+	PermissionConfigManage        string = "CONFIG_MANAGE"
+	PermissionApplicationReassign string = "APPLICATION_REASSIGN"
+	PermissionUserManage          string = "USER_MANAGE"
+
+	LoanApprovalApproved  string = "APPROVED"
+	LoanApprovalEscalated string = "ESCALATED"
+	LoanApprovalChecking  string = "AWAITING_CHECK"
+	LoanApprovalRejected  string = "REJECTED"
+
+	PendingActionStatusPending  string = "PENDING"
+	PendingActionStatusApplied  string = "APPLIED"
+	PendingActionStatusRejected string = "REJECTED"
+
+	ActionLoanOverride      string = "LOAN_OVERRIDE_APPROVAL"
+	ActionEligibilityConfig string = "ELIGIBILITY_CONFIG_UPDATE"
+
+	SigningKeyStatusActive  string = "ACTIVE"
+	SigningKeyStatusRetired string = "RETIRED"
 )
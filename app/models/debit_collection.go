@@ -0,0 +1,95 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// DebitCollection [...]
+type DebitCollection struct {
+	CollectionID      string      `gorm:"primaryKey;column:collection_id" json:"-"`
+	MandateID         string      `gorm:"column:mandate_id" json:"mandateId"`
+	ApplicationID     string      `gorm:"column:application_id" json:"applicationId"`
+	RepaymentID       string      `gorm:"column:repayment_id" json:"repaymentId"`
+	PaymentID         null.String `gorm:"column:payment_id" json:"paymentId"`
+	Attempt           int         `gorm:"column:attempt" json:"attempt"`
+	Amount            float64     `gorm:"column:amount" json:"amount"`
+	CurrencyCode      string      `gorm:"column:currency_code" json:"currencyCode"`
+	Status            string      `gorm:"column:status" json:"status"`
+	ProviderReference null.String `gorm:"column:provider_reference" json:"providerReference"`
+	FailureCode       null.String `gorm:"column:failure_code" json:"failureCode"`
+	FailureReason     null.String `gorm:"column:failure_reason" json:"failureReason"`
+	NextRetryAt       null.Time   `gorm:"column:next_retry_at" json:"nextRetryAt"`
+	CreatedAt         time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt         time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *DebitCollection) TableName() string {
+	return "debit_collection"
+}
+
+// DebitCollectionColumns get sql column name.
+var DebitCollectionColumns = struct {
+	CollectionID      string
+	MandateID         string
+	ApplicationID     string
+	RepaymentID       string
+	PaymentID         string
+	Attempt           string
+	Amount            string
+	CurrencyCode      string
+	Status            string
+	ProviderReference string
+	FailureCode       string
+	FailureReason     string
+	NextRetryAt       string
+	CreatedAt         string
+	UpdatedAt         string
+}{
+	CollectionID:      "collection_id",
+	MandateID:         "mandate_id",
+	ApplicationID:     "application_id",
+	RepaymentID:       "repayment_id",
+	PaymentID:         "payment_id",
+	Attempt:           "attempt",
+	Amount:            "amount",
+	CurrencyCode:      "currency_code",
+	Status:            "status",
+	ProviderReference: "provider_reference",
+	FailureCode:       "failure_code",
+	FailureReason:     "failure_reason",
+	NextRetryAt:       "next_retry_at",
+	CreatedAt:         "created_at",
+	UpdatedAt:         "updated_at",
+}
+
+func (m *DebitCollection) FindByPrimaryKey(collectionId string) (result DebitCollection, err error) {
+	err = database.MysqlDB.Model(m).Where("collection_id = ?", collectionId).Find(&result).Error
+	return
+}
+
+// FindByPrimaryKeyForUpdate loads the collection inside the given transaction and holds a row lock on it
+func (m *DebitCollection) FindByPrimaryKeyForUpdate(tx *gorm.DB, collectionId string) (result DebitCollection, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("collection_id = ?", collectionId).Find(&result).Error
+	return
+}
+
+func (m *DebitCollection) FindOneByCondition(whereCondition []database.WhereCondition) (result DebitCollection, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+func (m *DebitCollection) FindAllByCondition(whereCondition []database.WhereCondition) (results []DebitCollection, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("created_at asc")
+	err = db.Find(&results).Error
+	return
+}
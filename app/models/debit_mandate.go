@@ -0,0 +1,111 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// DebitMandate [...]
+type DebitMandate struct {
+	MandateID         string          `gorm:"primaryKey;column:mandate_id" json:"-"`
+	ApplicationID     string          `gorm:"column:application_id" json:"applicationId"`
+	LoanApplication   LoanApplication `gorm:"joinForeignKey:application_id;foreignKey:application_id;references:ApplicationID" json:"-"`
+	UserID            string          `gorm:"column:user_id" json:"userId"`
+	AccountHolderName string          `gorm:"column:account_holder_name" json:"accountHolderName"`
+	BankAccountNumber string          `gorm:"column:bank_account_number" json:"-"`
+	BankCode          string          `gorm:"column:bank_code" json:"bankCode"`
+	CurrencyCode      string          `gorm:"column:currency_code" json:"currencyCode"`
+	MaxAmount         float64         `gorm:"column:max_amount" json:"maxAmount"`
+	Frequency         string          `gorm:"column:frequency" json:"frequency"`
+	StartDate         time.Time       `gorm:"column:start_date" json:"startDate"`
+	EndDate           time.Time       `gorm:"column:end_date" json:"endDate"`
+	Status            string          `gorm:"column:status" json:"status"`
+	Provider          string          `gorm:"column:provider" json:"provider"`
+	ProviderReference string          `gorm:"column:provider_reference" json:"providerReference"`
+	CancelledBy       null.String     `gorm:"column:cancelled_by" json:"cancelledBy"`
+	CancelledAt       null.Time       `gorm:"column:cancelled_at" json:"cancelledAt"`
+	CreatedAt         time.Time       `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt         time.Time       `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *DebitMandate) TableName() string {
+	return "debit_mandate"
+}
+
+// DebitMandateColumns get sql column name.
+var DebitMandateColumns = struct {
+	MandateID         string
+	ApplicationID     string
+	UserID            string
+	AccountHolderName string
+	BankAccountNumber string
+	BankCode          string
+	CurrencyCode      string
+	MaxAmount         string
+	Frequency         string
+	StartDate         string
+	EndDate           string
+	Status            string
+	Provider          string
+	ProviderReference string
+	CancelledBy       string
+	CancelledAt       string
+	CreatedAt         string
+	UpdatedAt         string
+}{
+	MandateID:         "mandate_id",
+	ApplicationID:     "application_id",
+	UserID:            "user_id",
+	AccountHolderName: "account_holder_name",
+	BankAccountNumber: "bank_account_number",
+	BankCode:          "bank_code",
+	CurrencyCode:      "currency_code",
+	MaxAmount:         "max_amount",
+	Frequency:         "frequency",
+	StartDate:         "start_date",
+	EndDate:           "end_date",
+	Status:            "status",
+	Provider:          "provider",
+	ProviderReference: "provider_reference",
+	CancelledBy:       "cancelled_by",
+	CancelledAt:       "cancelled_at",
+	CreatedAt:         "created_at",
+	UpdatedAt:         "updated_at",
+}
+
+func (m *DebitMandate) FindByPrimaryKey(mandateId string) (result DebitMandate, err error) {
+	err = database.MysqlDB.Model(m).Where("mandate_id = ?", mandateId).Find(&result).Error
+	return
+}
+
+func (m *DebitMandate) FindOneByCondition(whereCondition []database.WhereCondition) (result DebitMandate, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+func (m *DebitMandate) FindAllByCondition(whereCondition []database.WhereCondition) (results []DebitMandate, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("created_at asc")
+	err = db.Find(&results).Error
+	return
+}
+
+// IsValidOn reports whether the mandate authorises a debit falling due on the given date
+func (m *DebitMandate) IsValidOn(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return m.Status == MandateStatusActive &&
+		day >= m.StartDate.Format("2006-01-02") && day <= m.EndDate.Format("2006-01-02")
+}
+
+// MaskedAccountNumber returns the bank account number with everything but the last four digits hidden
+func (m *DebitMandate) MaskedAccountNumber() string {
+	if len(m.BankAccountNumber) <= 4 {
+		return m.BankAccountNumber
+	}
+	return "XXXX" + m.BankAccountNumber[len(m.BankAccountNumber)-4:]
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"time"
+)
+
+// EmployeeAvailability [...]
+type EmployeeAvailability struct {
+	UserID    string      `gorm:"primaryKey;column:user_id" json:"userId"`
+	Available bool        `gorm:"column:available" json:"available"`
+	Reason    null.String `gorm:"column:reason" json:"reason"`
+	CreatedAt time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *EmployeeAvailability) TableName() string {
+	return "employee_availability"
+}
+
+// EmployeeAvailabilityColumns get sql column name.
+var EmployeeAvailabilityColumns = struct {
+	UserID    string
+	Available string
+	Reason    string
+	CreatedAt string
+	UpdatedAt string
+}{
+	UserID:    "user_id",
+	Available: "available",
+	Reason:    "reason",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// EmployeeDelegation [...]
+type EmployeeDelegation struct {
+	DelegationID string      `gorm:"primaryKey;column:delegation_id" json:"delegationId"`
+	UserID       string      `gorm:"column:user_id" json:"userId"`
+	DelegateID   string      `gorm:"column:delegate_id" json:"delegateId"`
+	StartDate    time.Time   `gorm:"column:start_date" json:"startDate"`
+	EndDate      time.Time   `gorm:"column:end_date" json:"endDate"`
+	Reason       null.String `gorm:"column:reason" json:"reason"`
+	CreatedBy    string      `gorm:"column:created_by" json:"createdBy"`
+	RevokedAt    null.Time   `gorm:"column:revoked_at" json:"revokedAt"`
+	RevokedBy    null.String `gorm:"column:revoked_by" json:"revokedBy"`
+	CreatedAt    time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt    time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *EmployeeDelegation) TableName() string {
+	return "employee_delegation"
+}
+
+// EmployeeDelegationColumns get sql column name.
+var EmployeeDelegationColumns = struct {
+	DelegationID string
+	UserID       string
+	DelegateID   string
+	StartDate    string
+	EndDate      string
+	Reason       string
+	CreatedBy    string
+	RevokedAt    string
+	RevokedBy    string
+	CreatedAt    string
+	UpdatedAt    string
+}{
+	DelegationID: "delegation_id",
+	UserID:       "user_id",
+	DelegateID:   "delegate_id",
+	StartDate:    "start_date",
+	EndDate:      "end_date",
+	Reason:       "reason",
+	CreatedBy:    "created_by",
+	RevokedAt:    "revoked_at",
+	RevokedBy:    "revoked_by",
+	CreatedAt:    "created_at",
+	UpdatedAt:    "updated_at",
+}
+
+func (m *EmployeeDelegation) FindByPrimaryKeyForUpdate(tx *gorm.DB, delegationId string) (result EmployeeDelegation, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("delegation_id = ?", delegationId).Find(&result).Error
+	return
+}
+
+// FindActiveByUser returns the delegation an employee who is out of office on a day forwards their work with
+func (m *EmployeeDelegation) FindActiveByUser(userId string, day time.Time) (result EmployeeDelegation, err error) {
+	err = database.MysqlDB.Model(m).
+		Where("user_id = ? AND start_date <= ? AND end_date >= ? AND revoked_at IS NULL", userId,
+			day.Format("2006-01-02"), day.Format("2006-01-02")).
+		Order("created_at DESC").Limit(1).Find(&result).Error
+	return
+}
+
+// FindActiveByDelegate returns the delegations forwarding work to an employee on a day
+func (m *EmployeeDelegation) FindActiveByDelegate(delegateId string, day time.Time) (results []EmployeeDelegation, err error) {
+	err = database.MysqlDB.Model(m).
+		Where("delegate_id = ? AND start_date <= ? AND end_date >= ? AND revoked_at IS NULL", delegateId,
+			day.Format("2006-01-02"), day.Format("2006-01-02")).
+		Find(&results).Error
+	return
+}
+
+// FindOverlapping returns the delegations of an employee, not revoked, overlapping a date range
+func (m *EmployeeDelegation) FindOverlapping(tx *gorm.DB, userId string, startDate time.Time, endDate time.Time) (
+	results []EmployeeDelegation, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("user_id = ? AND start_date <= ? AND end_date >= ? AND revoked_at IS NULL", userId,
+			endDate.Format("2006-01-02"), startDate.Format("2006-01-02")).
+		Find(&results).Error
+	return
+}
+
+// FindByParty returns the delegations an employee gave or received, the latest first
+func (m *EmployeeDelegation) FindByParty(userId string) (results []EmployeeDelegation, err error) {
+	err = database.MysqlDB.Model(m).Where("user_id = ? OR delegate_id = ?", userId, userId).
+		Order("start_date DESC").Find(&results).Error
+	return
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// EmployeeSkill [...]
+type EmployeeSkill struct {
+	SkillID     string      `gorm:"primaryKey;column:skill_id" json:"skillId"`
+	UserID      string      `gorm:"column:user_id" json:"userId"`
+	CountryCode null.String `gorm:"column:country_code" json:"countryCode"`
+	ProductCode null.String `gorm:"column:product_code" json:"productCode"`
+	CreatedAt   time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt   time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *EmployeeSkill) TableName() string {
+	return "employee_skill"
+}
+
+// EmployeeSkillColumns get sql column name.
+var EmployeeSkillColumns = struct {
+	SkillID     string
+	UserID      string
+	CountryCode string
+	ProductCode string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	SkillID:     "skill_id",
+	UserID:      "user_id",
+	CountryCode: "country_code",
+	ProductCode: "product_code",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+}
+
+// FindByUsers returns the skills of a group of employees
+func (m *EmployeeSkill) FindByUsers(userIds []string) (results []EmployeeSkill, err error) {
+	if len(userIds) == 0 {
+		return
+	}
+	err = database.MysqlDB.Model(m).Where("user_id IN ?", userIds).Find(&results).Error
+	return
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// IdempotencyKey [...]
+type IdempotencyKey struct {
+	IdempotencyKeyID string      `gorm:"primaryKey;column:idempotency_key_id" json:"-"`
+	IdempotencyKey   string      `gorm:"column:idempotency_key" json:"idempotencyKey"`
+	UserID           string      `gorm:"column:user_id" json:"userId"`
+	RequestMethod    string      `gorm:"column:request_method" json:"requestMethod"`
+	RequestPath      string      `gorm:"column:request_path" json:"requestPath"`
+	RequestHash      string      `gorm:"column:request_hash" json:"requestHash"`
+	ResponseStatus   null.Int    `gorm:"column:response_status" json:"responseStatus"`
+	ResponseBody     null.String `gorm:"column:response_body" json:"responseBody"`
+	ExpiresAt        time.Time   `gorm:"column:expires_at" json:"expiresAt"`
+	CreatedAt        time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt        time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *IdempotencyKey) TableName() string {
+	return "idempotency_key"
+}
+
+// IdempotencyKeyColumns get sql column name.
+var IdempotencyKeyColumns = struct {
+	IdempotencyKeyID string
+	IdempotencyKey   string
+	UserID           string
+	RequestMethod    string
+	RequestPath      string
+	RequestHash      string
+	ResponseStatus   string
+	ResponseBody     string
+	ExpiresAt        string
+	CreatedAt        string
+	UpdatedAt        string
+}{
+	IdempotencyKeyID: "idempotency_key_id",
+	IdempotencyKey:   "idempotency_key",
+	UserID:           "user_id",
+	RequestMethod:    "request_method",
+	RequestPath:      "request_path",
+	RequestHash:      "request_hash",
+	ResponseStatus:   "response_status",
+	ResponseBody:     "response_body",
+	ExpiresAt:        "expires_at",
+	CreatedAt:        "created_at",
+	UpdatedAt:        "updated_at",
+}
+
+func (m *IdempotencyKey) FindOneByCondition(whereCondition []database.WhereCondition) (result IdempotencyKey, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+// DeleteExpired removes every idempotency key whose retention window has elapsed
+func (m *IdempotencyKey) DeleteExpired() error {
+	return database.MysqlDB.Where("expires_at <= ?", time.Now()).Delete(m).Error
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+// InterestAccrual [...]
+type InterestAccrual struct {
+	AccrualID        string      `gorm:"primaryKey;column:accrual_id" json:"-"`
+	ApplicationID    string      `gorm:"column:application_id" json:"applicationId"`
+	AccrualDate      time.Time   `gorm:"column:accrual_date" json:"accrualDate"`
+	PrincipalBalance float64     `gorm:"column:principal_balance" json:"principalBalance"`
+	InterestRate     float64     `gorm:"column:interest_rate" json:"interestRate"`
+	Amount           float64     `gorm:"column:amount" json:"amount"`
+	EntryID          null.String `gorm:"column:entry_id" json:"entryId"`
+	CreatedAt        time.Time   `gorm:"column:created_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *InterestAccrual) TableName() string {
+	return "interest_accrual"
+}
+
+// InterestAccrualColumns get sql column name.
+var InterestAccrualColumns = struct {
+	AccrualID        string
+	ApplicationID    string
+	AccrualDate      string
+	PrincipalBalance string
+	InterestRate     string
+	Amount           string
+	EntryID          string
+	CreatedAt        string
+}{
+	AccrualID:        "accrual_id",
+	ApplicationID:    "application_id",
+	AccrualDate:      "accrual_date",
+	PrincipalBalance: "principal_balance",
+	InterestRate:     "interest_rate",
+	Amount:           "amount",
+	EntryID:          "entry_id",
+	CreatedAt:        "created_at",
+}
+
+// FindLatestTx loads the last day accrued for an application inside the given transaction
+func (m *InterestAccrual) FindLatestTx(tx *gorm.DB, applicationId string) (result InterestAccrual, err error) {
+	err = tx.Model(m).Where("application_id = ?", applicationId).
+		Order("accrual_date desc").Limit(1).Find(&result).Error
+	return
+}
+
+func (m *InterestAccrual) FindAllByCondition(whereCondition []database.WhereCondition) (results []InterestAccrual, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("accrual_date asc").Find(&results).Error
+	return
+}
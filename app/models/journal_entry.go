@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ErrImmutablePosting is returned when a posted journal entry or line is about to be changed or removed
+var ErrImmutablePosting = errors.New("journal postings are immutable, post a reversing entry instead")
+
+// JournalEntry [...]
+type JournalEntry struct {
+	EntryID         string        `gorm:"primaryKey;column:entry_id" json:"-"`
+	EntryType       string        `gorm:"column:entry_type" json:"entryType"`
+	SourceReference string        `gorm:"column:source_reference" json:"sourceReference"`
+	ApplicationID   null.String   `gorm:"column:application_id" json:"applicationId"`
+	PaymentID       null.String   `gorm:"column:payment_id" json:"paymentId"`
+	CurrencyCode    string        `gorm:"column:currency_code" json:"currencyCode"`
+	EffectiveDate   time.Time     `gorm:"column:effective_date" json:"effectiveDate"`
+	Description     string        `gorm:"column:description" json:"description"`
+	ReversesEntryID null.String   `gorm:"column:reverses_entry_id" json:"reversesEntryId"`
+	CreatedBy       null.String   `gorm:"column:created_by" json:"createdBy"`
+	CreatedAt       time.Time     `gorm:"column:created_at" json:"createdAt"`
+	Lines           []JournalLine `gorm:"foreignKey:EntryID;references:EntryID" json:"lines"`
+}
+
+// TableName get sql table name.
+func (m *JournalEntry) TableName() string {
+	return "journal_entry"
+}
+
+// JournalEntryColumns get sql column name.
+var JournalEntryColumns = struct {
+	EntryID         string
+	EntryType       string
+	SourceReference string
+	ApplicationID   string
+	PaymentID       string
+	CurrencyCode    string
+	EffectiveDate   string
+	Description     string
+	ReversesEntryID string
+	CreatedBy       string
+	CreatedAt       string
+}{
+	EntryID:         "entry_id",
+	EntryType:       "entry_type",
+	SourceReference: "source_reference",
+	ApplicationID:   "application_id",
+	PaymentID:       "payment_id",
+	CurrencyCode:    "currency_code",
+	EffectiveDate:   "effective_date",
+	Description:     "description",
+	ReversesEntryID: "reverses_entry_id",
+	CreatedBy:       "created_by",
+	CreatedAt:       "created_at",
+}
+
+// BeforeUpdate refuses to change a posted entry
+func (m *JournalEntry) BeforeUpdate(tx *gorm.DB) error {
+	return ErrImmutablePosting
+}
+
+// BeforeDelete refuses to remove a posted entry
+func (m *JournalEntry) BeforeDelete(tx *gorm.DB) error {
+	return ErrImmutablePosting
+}
+
+// FindOneByConditionTx loads a single entry with its lines inside the given transaction
+func (m *JournalEntry) FindOneByConditionTx(tx *gorm.DB, whereCondition []database.WhereCondition) (result JournalEntry, err error) {
+	db := tx.Model(m).Preload("Lines")
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+func (m *JournalEntry) FindAllByCondition(whereCondition []database.WhereCondition) (results []JournalEntry, err error) {
+	db := database.MysqlDB.Model(m).Preload("Lines")
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("effective_date asc, created_at asc").Find(&results).Error
+	return
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"time"
+)
+
+// JournalLine [...]
+type JournalLine struct {
+	LineID        string      `gorm:"primaryKey;column:line_id" json:"-"`
+	EntryID       string      `gorm:"column:entry_id" json:"entryId"`
+	AccountCode   string      `gorm:"column:account_code" json:"accountCode"`
+	ApplicationID null.String `gorm:"column:application_id" json:"applicationId"`
+	Debit         float64     `gorm:"column:debit" json:"debit"`
+	Credit        float64     `gorm:"column:credit" json:"credit"`
+	CreatedAt     time.Time   `gorm:"column:created_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *JournalLine) TableName() string {
+	return "journal_line"
+}
+
+// JournalLineColumns get sql column name.
+var JournalLineColumns = struct {
+	LineID        string
+	EntryID       string
+	AccountCode   string
+	ApplicationID string
+	Debit         string
+	Credit        string
+	CreatedAt     string
+}{
+	LineID:        "line_id",
+	EntryID:       "entry_id",
+	AccountCode:   "account_code",
+	ApplicationID: "application_id",
+	Debit:         "debit",
+	Credit:        "credit",
+	CreatedAt:     "created_at",
+}
+
+// AccountTotal is the sum of the debits and credits posted to an account in one currency
+type AccountTotal struct {
+	AccountCode  string  `gorm:"column:account_code"`
+	CurrencyCode string  `gorm:"column:currency_code"`
+	Debit        float64 `gorm:"column:debit"`
+	Credit       float64 `gorm:"column:credit"`
+}
+
+// BeforeUpdate refuses to change a posted line
+func (m *JournalLine) BeforeUpdate(tx *gorm.DB) error {
+	return ErrImmutablePosting
+}
+
+// BeforeDelete refuses to remove a posted line
+func (m *JournalLine) BeforeDelete(tx *gorm.DB) error {
+	return ErrImmutablePosting
+}
+
+// SumByAccount totals the lines of every account and currency over the entries effective on or before asOf,
+// restricted to one currency unless it is empty
+func (m *JournalLine) SumByAccount(tx *gorm.DB, asOf time.Time, currencyCode string) (results []AccountTotal, err error) {
+	db := tx.Table("journal_line AS jl").
+		Select("jl.account_code, je.currency_code, SUM(jl.debit) AS debit, SUM(jl.credit) AS credit").
+		Joins("JOIN journal_entry AS je ON je.entry_id = jl.entry_id").
+		Where("je.effective_date <= ?", asOf.Format("2006-01-02"))
+	if currencyCode != "" {
+		db = db.Where("je.currency_code = ?", currencyCode)
+	}
+	err = db.Group("jl.account_code, je.currency_code").Order("je.currency_code, jl.account_code").Scan(&results).Error
+	return
+}
+
+// ApplicationBalance returns debits minus credits posted to an account for a loan application
+func (m *JournalLine) ApplicationBalance(tx *gorm.DB, accountCode string, applicationId string) (balance float64, err error) {
+	err = tx.Model(m).Select("COALESCE(SUM(debit) - SUM(credit), 0)").
+		Where("account_code = ? AND application_id = ?", accountCode, applicationId).
+		Scan(&balance).Error
+	return
+}
+
+// ApplicationBalanceAsOf returns debits minus credits posted to an account for a loan application by the entries
+// effective on or before asOf
+func (m *JournalLine) ApplicationBalanceAsOf(tx *gorm.DB, accountCode string, applicationId string, asOf time.Time) (
+	balance float64, err error) {
+	err = tx.Table("journal_line AS jl").Select("COALESCE(SUM(jl.debit) - SUM(jl.credit), 0)").
+		Joins("JOIN journal_entry AS je ON je.entry_id = jl.entry_id").
+		Where("jl.account_code = ? AND jl.application_id = ?", accountCode, applicationId).
+		Where("je.effective_date <= ?", asOf.Format("2006-01-02")).
+		Scan(&balance).Error
+	return
+}
+
+// ApplicationBalances returns debits minus credits posted to an account per loan application, leaving out the
+// applications the account is settled for
+func (m *JournalLine) ApplicationBalances(tx *gorm.DB, accountCode string) (balances map[string]float64, err error) {
+	var results []struct {
+		ApplicationID string  `gorm:"column:application_id"`
+		Balance       float64 `gorm:"column:balance"`
+	}
+	err = tx.Model(m).Select("application_id, SUM(debit) - SUM(credit) AS balance").
+		Where("account_code = ? AND application_id IS NOT NULL", accountCode).
+		Group("application_id").Having("SUM(debit) <> SUM(credit)").
+		Scan(&results).Error
+
+	balances = make(map[string]float64)
+	for _, result := range results {
+		balances[result.ApplicationID] = result.Balance
+	}
+	return
+}
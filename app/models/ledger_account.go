@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// LedgerAccount [...]
+type LedgerAccount struct {
+	AccountCode   string    `gorm:"primaryKey;column:account_code" json:"accountCode"`
+	Name          string    `gorm:"column:name" json:"name"`
+	AccountType   string    `gorm:"column:account_type" json:"accountType"`
+	NormalBalance string    `gorm:"column:normal_balance" json:"normalBalance"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"-"`
+	UpdatedAt     time.Time `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *LedgerAccount) TableName() string {
+	return "ledger_account"
+}
+
+// LedgerAccountColumns get sql column name.
+var LedgerAccountColumns = struct {
+	AccountCode   string
+	Name          string
+	AccountType   string
+	NormalBalance string
+	CreatedAt     string
+	UpdatedAt     string
+}{
+	AccountCode:   "account_code",
+	Name:          "name",
+	AccountType:   "account_type",
+	NormalBalance: "normal_balance",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
+}
+
+func (m *LedgerAccount) FindAllByCondition(whereCondition []database.WhereCondition) (results []LedgerAccount, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("account_code asc").Find(&results).Error
+	return
+}
+
+// FindByPrimaryKeyForUpdate loads an account inside the given transaction and holds a row lock on it until the
+// transaction ends, serialising the jobs that post to it as a whole
+func (m *LedgerAccount) FindByPrimaryKeyForUpdate(tx *gorm.DB, accountCode string) (result LedgerAccount, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("account_code = ?", accountCode).First(&result).Error
+	return
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/nishanthrk/aspire-lms/app/common/utility"
 	"github.com/nishanthrk/aspire-lms/app/database"
 	"github.com/nishanthrk/aspire-lms/app/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -28,7 +30,16 @@ type LoanApplication struct {
 	ExistingDebts      float64     `gorm:"column:existing_debts" json:"existingDebts"`
 	CountryCode        string      `gorm:"column:country_code" json:"countryCode"`
 	Country            Country     `gorm:"joinForeignKey:country_code;foreignKey:country_code;references:CountryCode" json:"countryList"`
+	ProductCode        string      `gorm:"column:product_code;default:TERM_LOAN" json:"productCode"`
 	EligibleLoanAmount float64     `gorm:"column:eligible_loan_amount" json:"eligibleLoanAmount"`
+	ExcessCredit       float64     `gorm:"column:excess_credit" json:"excessCredit"`
+	VirtualAccount     null.String `gorm:"column:virtual_account" json:"virtualAccount"`
+	AccrualStatus      string      `gorm:"column:accrual_status;default:ACCRUING" json:"accrualStatus"`
+	AccrualStatusDate  null.Time   `gorm:"column:accrual_status_date" json:"accrualStatusDate"`
+	Restructured       bool        `gorm:"column:restructured" json:"restructured"`
+	RestructuredDate   null.Time   `gorm:"column:restructured_date" json:"restructuredDate"`
+	ClosedDate         null.Time   `gorm:"column:closed_date" json:"closedDate"`
+	ClosedBy           null.String `gorm:"column:closed_by" json:"closedBy"`
 	CreatedAt          time.Time   `gorm:"column:created_at" json:"createdAt"`
 	UpdatedAt          time.Time   `gorm:"column:updated_at" json:"updatedAt"`
 }
@@ -56,7 +67,16 @@ var LoanApplicationColumns = struct {
 	CreditScore        string
 	ExistingDebts      string
 	CountryCode        string
+	ProductCode        string
 	EligibleLoanAmount string
+	ExcessCredit       string
+	VirtualAccount     string
+	AccrualStatus      string
+	AccrualStatusDate  string
+	Restructured       string
+	RestructuredDate   string
+	ClosedDate         string
+	ClosedBy           string
 	CreatedAt          string
 	UpdatedAt          string
 }{
@@ -76,7 +96,16 @@ var LoanApplicationColumns = struct {
 	CreditScore:        "credit_score",
 	ExistingDebts:      "existing_debts",
 	CountryCode:        "country_code",
+	ProductCode:        "product_code",
 	EligibleLoanAmount: "eligible_loan_amount",
+	ExcessCredit:       "excess_credit",
+	VirtualAccount:     "virtual_account",
+	AccrualStatus:      "accrual_status",
+	AccrualStatusDate:  "accrual_status_date",
+	Restructured:       "restructured",
+	RestructuredDate:   "restructured_date",
+	ClosedDate:         "closed_date",
+	ClosedBy:           "closed_by",
 	CreatedAt:          "created_at",
 	UpdatedAt:          "updated_at",
 }
@@ -86,6 +115,29 @@ func (m *LoanApplication) FindByPrimaryKey(applicationId string) (result LoanApp
 	return
 }
 
+func (m *LoanApplication) FindOneByCondition(whereCondition []database.WhereCondition) (result LoanApplication, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+func (m *LoanApplication) FindAllByCondition(whereCondition []database.WhereCondition) (results []LoanApplication, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("created_at asc")
+	err = db.Find(&results).Error
+	return
+}
+
+// FindByPrimaryKeyForUpdate loads the application inside the given transaction and holds a row lock on it
+// until the transaction ends, serialising concurrent money movements on the same loan
+func (m *LoanApplication) FindByPrimaryKeyForUpdate(tx *gorm.DB, applicationId string) (result LoanApplication, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("application_id = ?", applicationId).First(&result).Error
+	return
+}
+
 func (m *LoanApplication) GetLoanApplicationDTO() (object dto.LoanApplicationObject) {
 	object.ApplicationId = utility.ToString(m.ApplicationID)
 	object.ApplicationStatus = m.Status
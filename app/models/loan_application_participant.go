@@ -1,7 +1,10 @@
 package models
 
 import (
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
 	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -54,3 +57,23 @@ func (m *LoanApplicationParticipant) FindOneByCondition(whereCondition []databas
 	err = db.Find(&result).Error
 	return
 }
+
+// CountPendingByUser counts the pending applications a user takes part in as an employee
+func (m *LoanApplicationParticipant) CountPendingByUser(userId string) (count int64, err error) {
+	err = database.MysqlDB.Model(m).
+		Joins("JOIN loan_application ON loan_application.application_id = loan_application_participant.application_id").
+		Where("loan_application_participant.user_id = ? AND loan_application_participant.participant_type = ? "+
+			"AND loan_application.status = ?", userId, constants.UserTypeEmployee, LoanApplicationStatusPending).
+		Count(&count).Error
+	return
+}
+
+// FindByUserForUpdate returns the participation of a user in an application as a participant type and locks it until
+// the transaction ends
+func (m *LoanApplicationParticipant) FindByUserForUpdate(tx *gorm.DB, applicationId string, userId string,
+	participantType string) (result LoanApplicationParticipant, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("application_id = ? AND user_id = ? AND participant_type = ?", applicationId, userId, participantType).
+		Find(&result).Error
+	return
+}
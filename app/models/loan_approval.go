@@ -0,0 +1,60 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"time"
+)
+
+// LoanApproval [...]
+type LoanApproval struct {
+	ApprovalID     string      `gorm:"primaryKey;column:approval_id" json:"approvalId"`
+	ApplicationID  string      `gorm:"column:application_id" json:"applicationId"`
+	Step           int         `gorm:"column:step" json:"step"`
+	ApproverID     string      `gorm:"column:approver_id" json:"approverId"`
+	OnBehalfOf     null.String `gorm:"column:on_behalf_of" json:"onBehalfOf"`
+	ApprovedAmount float64     `gorm:"column:approved_amount" json:"approvedAmount"`
+	Override       bool        `gorm:"column:override" json:"override"`
+	Decision       string      `gorm:"column:decision" json:"decision"`
+	EscalatedTo    null.String `gorm:"column:escalated_to" json:"escalatedTo"`
+	Reason         null.String `gorm:"column:reason" json:"reason"`
+	CreatedAt      time.Time   `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName get sql table name.
+func (m *LoanApproval) TableName() string {
+	return "loan_approval"
+}
+
+// LoanApprovalColumns get sql column name.
+var LoanApprovalColumns = struct {
+	ApprovalID     string
+	ApplicationID  string
+	Step           string
+	ApproverID     string
+	OnBehalfOf     string
+	ApprovedAmount string
+	Override       string
+	Decision       string
+	EscalatedTo    string
+	Reason         string
+	CreatedAt      string
+}{
+	ApprovalID:     "approval_id",
+	ApplicationID:  "application_id",
+	Step:           "step",
+	ApproverID:     "approver_id",
+	OnBehalfOf:     "on_behalf_of",
+	ApprovedAmount: "approved_amount",
+	Override:       "override",
+	Decision:       "decision",
+	EscalatedTo:    "escalated_to",
+	Reason:         "reason",
+	CreatedAt:      "created_at",
+}
+
+// FindByApplication returns the sign-offs of an application in the order they were given
+func (m *LoanApproval) FindByApplication(tx *gorm.DB, applicationId string) (results []LoanApproval, err error) {
+	err = tx.Model(m).Where("application_id = ?", applicationId).Order("step").Find(&results).Error
+	return
+}
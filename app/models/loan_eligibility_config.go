@@ -2,6 +2,8 @@ package models
 
 import (
 	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -59,3 +61,19 @@ func (m *LoanEligibilityConfig) FindOneByCondition(whereCondition []database.Whe
 	err = db.Find(&result).Error
 	return
 }
+
+func (m *LoanEligibilityConfig) FindByPrimaryKey(id int64) (result LoanEligibilityConfig, err error) {
+	err = database.MysqlDB.Model(m).Where("id = ?", id).Find(&result).Error
+	return
+}
+
+func (m *LoanEligibilityConfig) FindByPrimaryKeyForUpdate(tx *gorm.DB, id int64) (result LoanEligibilityConfig, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("id = ?", id).Find(&result).Error
+	return
+}
+
+func (m *LoanEligibilityConfig) FindAll() (results []LoanEligibilityConfig, err error) {
+	err = database.MysqlDB.Model(m).Order("country_code").Order("min_credit_score").Find(&results).Error
+	return
+}
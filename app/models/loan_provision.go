@@ -0,0 +1,95 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// LoanProvision [...]
+type LoanProvision struct {
+	ProvisionID          string      `gorm:"primaryKey;column:provision_id" json:"-"`
+	RunID                string      `gorm:"column:run_id" json:"runId"`
+	ApplicationID        string      `gorm:"column:application_id" json:"applicationId"`
+	CurrencyCode         string      `gorm:"column:currency_code" json:"currencyCode"`
+	Stage                int         `gorm:"column:stage" json:"stage"`
+	DaysPastDue          int         `gorm:"column:days_past_due" json:"daysPastDue"`
+	Restructured         bool        `gorm:"column:restructured" json:"restructured"`
+	Exposure             float64     `gorm:"column:exposure" json:"exposure"`
+	ProbabilityOfDefault float64     `gorm:"column:probability_of_default" json:"probabilityOfDefault"`
+	LossGivenDefault     float64     `gorm:"column:loss_given_default" json:"lossGivenDefault"`
+	EclAmount            float64     `gorm:"column:ecl_amount" json:"eclAmount"`
+	PreviousAmount       float64     `gorm:"column:previous_amount" json:"previousAmount"`
+	Movement             float64     `gorm:"column:movement" json:"movement"`
+	EntryID              null.String `gorm:"column:entry_id" json:"entryId"`
+	CreatedAt            time.Time   `gorm:"column:created_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *LoanProvision) TableName() string {
+	return "loan_provision"
+}
+
+// LoanProvisionColumns get sql column name.
+var LoanProvisionColumns = struct {
+	ProvisionID          string
+	RunID                string
+	ApplicationID        string
+	CurrencyCode         string
+	Stage                string
+	DaysPastDue          string
+	Restructured         string
+	Exposure             string
+	ProbabilityOfDefault string
+	LossGivenDefault     string
+	EclAmount            string
+	PreviousAmount       string
+	Movement             string
+	EntryID              string
+	CreatedAt            string
+}{
+	ProvisionID:          "provision_id",
+	RunID:                "run_id",
+	ApplicationID:        "application_id",
+	CurrencyCode:         "currency_code",
+	Stage:                "stage",
+	DaysPastDue:          "days_past_due",
+	Restructured:         "restructured",
+	Exposure:             "exposure",
+	ProbabilityOfDefault: "probability_of_default",
+	LossGivenDefault:     "loss_given_default",
+	EclAmount:            "ecl_amount",
+	PreviousAmount:       "previous_amount",
+	Movement:             "movement",
+	EntryID:              "entry_id",
+	CreatedAt:            "created_at",
+}
+
+func (m *LoanProvision) FindAllByCondition(whereCondition []database.WhereCondition) (results []LoanProvision, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("currency_code asc, stage desc, ecl_amount desc").Find(&results).Error
+	return
+}
+
+// LoanProvisionTotal is the sum of the provisions of a run in one currency and stage
+type LoanProvisionTotal struct {
+	RunID        string  `gorm:"column:run_id"`
+	CurrencyCode string  `gorm:"column:currency_code"`
+	Stage        int     `gorm:"column:stage"`
+	Count        int     `gorm:"column:count"`
+	Exposure     float64 `gorm:"column:exposure"`
+	EclAmount    float64 `gorm:"column:ecl_amount"`
+	Movement     float64 `gorm:"column:movement"`
+}
+
+// TotalsByRun totals the provisions of the given runs per run, currency and stage
+func (m *LoanProvision) TotalsByRun(runIds []string) (results []LoanProvisionTotal, err error) {
+	err = database.MysqlDB.Model(m).
+		Select("run_id, currency_code, stage, COUNT(*) AS count, SUM(exposure) AS exposure, "+
+			"SUM(ecl_amount) AS ecl_amount, SUM(movement) AS movement").
+		Where("run_id IN ?", runIds).
+		Group("run_id, currency_code, stage").Order("run_id, currency_code, stage").
+		Scan(&results).Error
+	return
+}
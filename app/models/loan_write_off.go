@@ -0,0 +1,67 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// LoanWriteOff [...]
+type LoanWriteOff struct {
+	WriteOffID        string      `gorm:"primaryKey;column:write_off_id" json:"-"`
+	ApplicationID     string      `gorm:"column:application_id" json:"applicationId"`
+	CurrencyCode      string      `gorm:"column:currency_code" json:"currencyCode"`
+	OutstandingAmount float64     `gorm:"column:outstanding_amount" json:"outstandingAmount"`
+	PrincipalAmount   float64     `gorm:"column:principal_amount" json:"principalAmount"`
+	InterestAmount    float64     `gorm:"column:interest_amount" json:"interestAmount"`
+	FeeAmount         float64     `gorm:"column:fee_amount" json:"feeAmount"`
+	Reason            string      `gorm:"column:reason" json:"reason"`
+	WrittenOffBy      string      `gorm:"column:written_off_by" json:"writtenOffBy"`
+	WrittenOffAt      time.Time   `gorm:"column:written_off_at" json:"writtenOffAt"`
+	EntryID           null.String `gorm:"column:entry_id" json:"entryId"`
+	CreatedAt         time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt         time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *LoanWriteOff) TableName() string {
+	return "loan_write_off"
+}
+
+// LoanWriteOffColumns get sql column name.
+var LoanWriteOffColumns = struct {
+	WriteOffID        string
+	ApplicationID     string
+	CurrencyCode      string
+	OutstandingAmount string
+	PrincipalAmount   string
+	InterestAmount    string
+	FeeAmount         string
+	Reason            string
+	WrittenOffBy      string
+	WrittenOffAt      string
+	EntryID           string
+	CreatedAt         string
+	UpdatedAt         string
+}{
+	WriteOffID:        "write_off_id",
+	ApplicationID:     "application_id",
+	CurrencyCode:      "currency_code",
+	OutstandingAmount: "outstanding_amount",
+	PrincipalAmount:   "principal_amount",
+	InterestAmount:    "interest_amount",
+	FeeAmount:         "fee_amount",
+	Reason:            "reason",
+	WrittenOffBy:      "written_off_by",
+	WrittenOffAt:      "written_off_at",
+	EntryID:           "entry_id",
+	CreatedAt:         "created_at",
+	UpdatedAt:         "updated_at",
+}
+
+func (m *LoanWriteOff) FindAllByCondition(whereCondition []database.WhereCondition) (results []LoanWriteOff, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("written_off_at asc").Find(&results).Error
+	return
+}
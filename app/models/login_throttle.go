@@ -0,0 +1,68 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// LoginThrottle [...]
+type LoginThrottle struct {
+	Scope         string    `gorm:"primaryKey;column:scope" json:"scope"`
+	Subject       string    `gorm:"primaryKey;column:subject" json:"subject"`
+	FailedCount   int       `gorm:"column:failed_count" json:"failedCount"`
+	LastFailedAt  null.Time `gorm:"column:last_failed_at" json:"lastFailedAt"`
+	NextAttemptAt null.Time `gorm:"column:next_attempt_at" json:"nextAttemptAt"`
+	LockedUntil   null.Time `gorm:"column:locked_until" json:"lockedUntil"`
+	LockoutCount  int       `gorm:"column:lockout_count" json:"lockoutCount"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *LoginThrottle) TableName() string {
+	return "login_throttle"
+}
+
+// LoginThrottleColumns get sql column name.
+var LoginThrottleColumns = struct {
+	Scope         string
+	Subject       string
+	FailedCount   string
+	LastFailedAt  string
+	NextAttemptAt string
+	LockedUntil   string
+	LockoutCount  string
+	CreatedAt     string
+	UpdatedAt     string
+}{
+	Scope:         "scope",
+	Subject:       "subject",
+	FailedCount:   "failed_count",
+	LastFailedAt:  "last_failed_at",
+	NextAttemptAt: "next_attempt_at",
+	LockedUntil:   "locked_until",
+	LockoutCount:  "lockout_count",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
+}
+
+func (m *LoginThrottle) FindByPrimaryKey(scope string, subject string) (result LoginThrottle, err error) {
+	err = database.MysqlDB.Model(m).Where("scope = ? AND subject = ?", scope, subject).Find(&result).Error
+	return
+}
+
+func (m *LoginThrottle) FindByPrimaryKeyForUpdate(tx *gorm.DB, scope string, subject string) (
+	result LoginThrottle, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("scope = ? AND subject = ?", scope, subject).Find(&result).Error
+	return
+}
+
+// DeleteByPrimaryKey clears the failed attempts and lockout of a scope and subject
+func (m *LoginThrottle) DeleteByPrimaryKey(tx *gorm.DB, scope string, subject string) (deleted int64, err error) {
+	result := tx.Where("scope = ? AND subject = ?", scope, subject).Delete(m)
+	return result.RowsAffected, result.Error
+}
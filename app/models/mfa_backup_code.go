@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MfaBackupCode [...]
+type MfaBackupCode struct {
+	BackupCodeID string    `gorm:"primaryKey;column:backup_code_id" json:"backupCodeId"`
+	UserID       string    `gorm:"column:user_id" json:"userId"`
+	CodeHash     string    `gorm:"column:code_hash" json:"-"`
+	UsedAt       null.Time `gorm:"column:used_at" json:"usedAt"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName get sql table name.
+func (m *MfaBackupCode) TableName() string {
+	return "mfa_backup_code"
+}
+
+// MfaBackupCodeColumns get sql column name.
+var MfaBackupCodeColumns = struct {
+	BackupCodeID string
+	UserID       string
+	CodeHash     string
+	UsedAt       string
+	CreatedAt    string
+}{
+	BackupCodeID: "backup_code_id",
+	UserID:       "user_id",
+	CodeHash:     "code_hash",
+	UsedAt:       "used_at",
+	CreatedAt:    "created_at",
+}
+
+// FindUnusedForUpdate finds the unused backup code of a user with a hash, and locks it
+func (m *MfaBackupCode) FindUnusedForUpdate(tx *gorm.DB, userId string, codeHash string) (
+	result MfaBackupCode, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).Find(&result).Error
+	return
+}
+
+// DeleteByUser deletes every backup code of a user
+func (m *MfaBackupCode) DeleteByUser(tx *gorm.DB, userId string) error {
+	return tx.Where("user_id = ?", userId).Delete(m).Error
+}
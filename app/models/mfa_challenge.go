@@ -0,0 +1,56 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MfaChallenge [...]
+type MfaChallenge struct {
+	ChallengeID string    `gorm:"primaryKey;column:challenge_id" json:"challengeId"`
+	UserID      string    `gorm:"column:user_id" json:"userId"`
+	Identifier  string    `gorm:"column:identifier" json:"identifier"`
+	TokenHash   string    `gorm:"column:token_hash" json:"-"`
+	Attempts    int       `gorm:"column:attempts" json:"attempts"`
+	ExpiresAt   time.Time `gorm:"column:expires_at" json:"expiresAt"`
+	ConsumedAt  null.Time `gorm:"column:consumed_at" json:"consumedAt"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *MfaChallenge) TableName() string {
+	return "mfa_challenge"
+}
+
+// MfaChallengeColumns get sql column name.
+var MfaChallengeColumns = struct {
+	ChallengeID string
+	UserID      string
+	Identifier  string
+	TokenHash   string
+	Attempts    string
+	ExpiresAt   string
+	ConsumedAt  string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	ChallengeID: "challenge_id",
+	UserID:      "user_id",
+	Identifier:  "identifier",
+	TokenHash:   "token_hash",
+	Attempts:    "attempts",
+	ExpiresAt:   "expires_at",
+	ConsumedAt:  "consumed_at",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+}
+
+// FindByTokenHashForUpdate finds the challenge handed out with a token, and locks it
+func (m *MfaChallenge) FindByTokenHashForUpdate(tx *gorm.DB, tokenHash string) (result MfaChallenge, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("token_hash = ?", tokenHash).Find(&result).Error
+	return
+}
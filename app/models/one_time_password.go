@@ -0,0 +1,64 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// OneTimePassword [...]
+type OneTimePassword struct {
+	OtpID       string    `gorm:"primaryKey;column:otp_id" json:"otpId"`
+	Purpose     string    `gorm:"column:purpose" json:"purpose"`
+	ReferenceID string    `gorm:"column:reference_id" json:"referenceId"`
+	Channel     string    `gorm:"column:channel" json:"channel"`
+	Recipient   string    `gorm:"column:recipient" json:"recipient"`
+	CodeHash    string    `gorm:"column:code_hash" json:"-"`
+	Attempts    int       `gorm:"column:attempts" json:"attempts"`
+	ExpiresAt   time.Time `gorm:"column:expires_at" json:"expiresAt"`
+	ConsumedAt  null.Time `gorm:"column:consumed_at" json:"consumedAt"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *OneTimePassword) TableName() string {
+	return "one_time_password"
+}
+
+// OneTimePasswordColumns get sql column name.
+var OneTimePasswordColumns = struct {
+	OtpID       string
+	Purpose     string
+	ReferenceID string
+	Channel     string
+	Recipient   string
+	CodeHash    string
+	Attempts    string
+	ExpiresAt   string
+	ConsumedAt  string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	OtpID:       "otp_id",
+	Purpose:     "purpose",
+	ReferenceID: "reference_id",
+	Channel:     "channel",
+	Recipient:   "recipient",
+	CodeHash:    "code_hash",
+	Attempts:    "attempts",
+	ExpiresAt:   "expires_at",
+	ConsumedAt:  "consumed_at",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+}
+
+// FindLatestForUpdate finds the most recent one-time password issued for a purpose and reference, and locks it
+func (m *OneTimePassword) FindLatestForUpdate(tx *gorm.DB, purpose string, referenceId string) (
+	result OneTimePassword, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("purpose = ? AND reference_id = ?", purpose, referenceId).
+		Order("created_at desc").Limit(1).Find(&result).Error
+	return
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"time"
+)
+
+// PasswordResetEvent [...]
+type PasswordResetEvent struct {
+	EventID    string      `gorm:"primaryKey;column:event_id" json:"eventId"`
+	Identifier string      `gorm:"column:identifier" json:"identifier"`
+	UserID     null.String `gorm:"column:user_id" json:"userId"`
+	Event      string      `gorm:"column:event" json:"event"`
+	Channel    null.String `gorm:"column:channel" json:"channel"`
+	IPAddress  null.String `gorm:"column:ip_address" json:"ipAddress"`
+	Detail     null.String `gorm:"column:detail" json:"detail"`
+	CreatedAt  time.Time   `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName get sql table name.
+func (m *PasswordResetEvent) TableName() string {
+	return "password_reset_event"
+}
+
+// PasswordResetEventColumns get sql column name.
+var PasswordResetEventColumns = struct {
+	EventID    string
+	Identifier string
+	UserID     string
+	Event      string
+	Channel    string
+	IPAddress  string
+	Detail     string
+	CreatedAt  string
+}{
+	EventID:    "event_id",
+	Identifier: "identifier",
+	UserID:     "user_id",
+	Event:      "event",
+	Channel:    "channel",
+	IPAddress:  "ip_address",
+	Detail:     "detail",
+	CreatedAt:  "created_at",
+}
+
+// CountSince counts the events of a kind recorded for an identifier since a point in time
+func (m *PasswordResetEvent) CountSince(tx *gorm.DB, identifier string, event string, since time.Time) (
+	count int64, err error) {
+	err = tx.Model(m).Where("identifier = ? AND event = ? AND created_at >= ?", identifier, event, since).
+		Count(&count).Error
+	return
+}
@@ -1,18 +1,32 @@
 package models
 
-import "time"
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
 
 // Payment [...]
 type Payment struct {
-	PaymentID       string          `gorm:"primaryKey;column:payment_id" json:"-"`
-	CurrencyCode    string          `gorm:"column:currency_code" json:"currencyCode"`
-	Currency        Currency        `gorm:"joinForeignKey:currency_code;foreignKey:currency_code;references:CurrencyCode" json:"currencyList"`
-	Amount          float64         `gorm:"column:amount" json:"amount"`
-	ApplicationID   string          `gorm:"column:application_id" json:"applicationId"`
-	LoanApplication LoanApplication `gorm:"joinForeignKey:application_id;foreignKey:application_id;references:ApplicationID" json:"loanApplicationList"`
-	Status          string          `gorm:"column:status" json:"status"`
-	CreatedAt       time.Time       `gorm:"column:created_at" json:"createdAt"`
-	UpdatedAt       time.Time       `gorm:"column:updated_at" json:"updatedAt"`
+	PaymentID        string          `gorm:"primaryKey;column:payment_id" json:"-"`
+	CurrencyCode     string          `gorm:"column:currency_code" json:"currencyCode"`
+	Currency         Currency        `gorm:"joinForeignKey:currency_code;foreignKey:currency_code;references:CurrencyCode" json:"currencyList"`
+	Amount           float64         `gorm:"column:amount" json:"amount"`
+	ExcessAmount     float64         `gorm:"column:excess_amount" json:"excessAmount"`
+	ApplicationID    string          `gorm:"column:application_id" json:"applicationId"`
+	LoanApplication  LoanApplication `gorm:"joinForeignKey:application_id;foreignKey:application_id;references:ApplicationID" json:"loanApplicationList"`
+	Status           string          `gorm:"column:status" json:"status"`
+	Gateway          null.String     `gorm:"column:gateway" json:"gateway"`
+	GatewayReference null.String     `gorm:"column:gateway_reference" json:"gatewayReference"`
+	FailureReason    null.String     `gorm:"column:failure_reason" json:"failureReason"`
+	SettledAt        null.Time       `gorm:"column:settled_at" json:"settledAt"`
+	ReversalReason   null.String     `gorm:"column:reversal_reason" json:"reversalReason"`
+	ReversedBy       null.String     `gorm:"column:reversed_by" json:"reversedBy"`
+	ReversedAt       null.Time       `gorm:"column:reversed_at" json:"reversedAt"`
+	CreatedAt        time.Time       `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt        time.Time       `gorm:"column:updated_at" json:"updatedAt"`
 }
 
 // TableName get sql table name.
@@ -22,19 +36,75 @@ func (m *Payment) TableName() string {
 
 // PaymentColumns get sql column name.
 var PaymentColumns = struct {
-	PaymentID     string
-	CurrencyCode  string
-	Amount        string
-	ApplicationID string
-	Status        string
-	CreatedAt     string
-	UpdatedAt     string
+	PaymentID        string
+	CurrencyCode     string
+	Amount           string
+	ExcessAmount     string
+	ApplicationID    string
+	Status           string
+	Gateway          string
+	GatewayReference string
+	FailureReason    string
+	SettledAt        string
+	ReversalReason   string
+	ReversedBy       string
+	ReversedAt       string
+	CreatedAt        string
+	UpdatedAt        string
 }{
-	PaymentID:     "payment_id",
-	CurrencyCode:  "currency_code",
-	Amount:        "amount",
-	ApplicationID: "application_id",
-	Status:        "status",
-	CreatedAt:     "created_at",
-	UpdatedAt:     "updated_at",
+	PaymentID:        "payment_id",
+	CurrencyCode:     "currency_code",
+	Amount:           "amount",
+	ExcessAmount:     "excess_amount",
+	ApplicationID:    "application_id",
+	Status:           "status",
+	Gateway:          "gateway",
+	GatewayReference: "gateway_reference",
+	FailureReason:    "failure_reason",
+	SettledAt:        "settled_at",
+	ReversalReason:   "reversal_reason",
+	ReversedBy:       "reversed_by",
+	ReversedAt:       "reversed_at",
+	CreatedAt:        "created_at",
+	UpdatedAt:        "updated_at",
+}
+
+func (m *Payment) FindByPrimaryKey(paymentId string) (result Payment, err error) {
+	err = database.MysqlDB.Model(m).Where("payment_id = ?", paymentId).Find(&result).Error
+	return
+}
+
+// FindByPrimaryKeyForUpdate loads the payment inside the given transaction and holds a row lock on it
+func (m *Payment) FindByPrimaryKeyForUpdate(tx *gorm.DB, paymentId string) (result Payment, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("payment_id = ?", paymentId).Find(&result).Error
+	return
+}
+
+// IsAllocated reports whether the payment's funds have been allocated to the repayment schedule
+func (m *Payment) IsAllocated() bool {
+	return m.Status == PaymentStatusSettled || m.Status == PaymentStatusReceived
+}
+
+func (m *Payment) FindOneByCondition(whereCondition []database.WhereCondition) (result Payment, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+func (m *Payment) FindAllByCondition(whereCondition []database.WhereCondition) (results []Payment, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("created_at asc")
+	err = db.Find(&results).Error
+	return
+}
+
+// FindAllByConditionTx loads payments inside the given transaction, seeing its uncommitted changes
+func (m *Payment) FindAllByConditionTx(tx *gorm.DB, whereCondition []database.WhereCondition) (results []Payment, err error) {
+	db := tx.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("created_at asc").Find(&results).Error
+	return
 }
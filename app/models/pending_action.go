@@ -0,0 +1,83 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// PendingAction [...]
+type PendingAction struct {
+	ActionID       string      `gorm:"primaryKey;column:action_id" json:"actionId"`
+	ActionType     string      `gorm:"column:action_type" json:"actionType"`
+	ResourceID     string      `gorm:"column:resource_id" json:"resourceId"`
+	Payload        string      `gorm:"column:payload" json:"payload"`
+	PermissionCode string      `gorm:"column:permission_code" json:"permissionCode"`
+	Status         string      `gorm:"column:status" json:"status"`
+	MakerID        string      `gorm:"column:maker_id" json:"makerId"`
+	CheckerID      null.String `gorm:"column:checker_id" json:"checkerId"`
+	DecisionReason null.String `gorm:"column:decision_reason" json:"decisionReason"`
+	Result         null.String `gorm:"column:result" json:"result"`
+	DecidedAt      null.Time   `gorm:"column:decided_at" json:"decidedAt"`
+	CreatedAt      time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt      time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *PendingAction) TableName() string {
+	return "pending_action"
+}
+
+// PendingActionColumns get sql column name.
+var PendingActionColumns = struct {
+	ActionID       string
+	ActionType     string
+	ResourceID     string
+	Payload        string
+	PermissionCode string
+	Status         string
+	MakerID        string
+	CheckerID      string
+	DecisionReason string
+	Result         string
+	DecidedAt      string
+	CreatedAt      string
+	UpdatedAt      string
+}{
+	ActionID:       "action_id",
+	ActionType:     "action_type",
+	ResourceID:     "resource_id",
+	Payload:        "payload",
+	PermissionCode: "permission_code",
+	Status:         "status",
+	MakerID:        "maker_id",
+	CheckerID:      "checker_id",
+	DecisionReason: "decision_reason",
+	Result:         "result",
+	DecidedAt:      "decided_at",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
+}
+
+func (m *PendingAction) FindByPrimaryKeyForUpdate(tx *gorm.DB, actionId string) (result PendingAction, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("action_id = ?", actionId).Find(&result).Error
+	return
+}
+
+func (m *PendingAction) FindAllByCondition(whereCondition []database.WhereCondition) (results []PendingAction, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("created_at").Find(&results).Error
+	return
+}
+
+// FindPendingByResource returns the action of a type still awaiting a checker on a resource
+func (m *PendingAction) FindPendingByResource(tx *gorm.DB, actionType string, resourceId string) (
+	result PendingAction, err error) {
+	err = tx.Model(m).Where("action_type = ? AND resource_id = ? AND status = ?", actionType, resourceId,
+		PendingActionStatusPending).Find(&result).Error
+	return
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// Permission [...]
+type Permission struct {
+	PermissionCode string      `gorm:"primaryKey;column:permission_code" json:"permissionCode"`
+	Description    null.String `gorm:"column:description" json:"description"`
+	CreatedAt      time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt      time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *Permission) TableName() string {
+	return "permission"
+}
+
+// PermissionColumns get sql column name.
+var PermissionColumns = struct {
+	PermissionCode string
+	Description    string
+	CreatedAt      string
+	UpdatedAt      string
+}{
+	PermissionCode: "permission_code",
+	Description:    "description",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
+}
+
+// FindCodesByUser returns the permissions a user holds through any of their roles
+func (m *Permission) FindCodesByUser(userId string) (result []string, err error) {
+	err = database.MysqlDB.Model(&RolePermission{}).
+		Distinct("role_permission.permission_code").
+		Joins("JOIN user_role ON user_role.role_code = role_permission.role_code").
+		Where("user_role.user_id = ?", userId).
+		Order("role_permission.permission_code").
+		Pluck("role_permission.permission_code", &result).Error
+	return
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// ProvisionParameter [...]
+type ProvisionParameter struct {
+	CountryCode          string      `gorm:"primaryKey;column:country_code" json:"countryCode"`
+	ProductCode          string      `gorm:"primaryKey;column:product_code" json:"productCode"`
+	Stage                int         `gorm:"primaryKey;column:stage" json:"stage"`
+	ProbabilityOfDefault float64     `gorm:"column:probability_of_default" json:"probabilityOfDefault"`
+	LossGivenDefault     float64     `gorm:"column:loss_given_default" json:"lossGivenDefault"`
+	UpdatedBy            null.String `gorm:"column:updated_by" json:"updatedBy"`
+	CreatedAt            time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt            time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *ProvisionParameter) TableName() string {
+	return "provision_parameter"
+}
+
+// ProvisionParameterColumns get sql column name.
+var ProvisionParameterColumns = struct {
+	CountryCode          string
+	ProductCode          string
+	Stage                string
+	ProbabilityOfDefault string
+	LossGivenDefault     string
+	UpdatedBy            string
+	CreatedAt            string
+	UpdatedAt            string
+}{
+	CountryCode:          "country_code",
+	ProductCode:          "product_code",
+	Stage:                "stage",
+	ProbabilityOfDefault: "probability_of_default",
+	LossGivenDefault:     "loss_given_default",
+	UpdatedBy:            "updated_by",
+	CreatedAt:            "created_at",
+	UpdatedAt:            "updated_at",
+}
+
+func (m *ProvisionParameter) FindAllByCondition(whereCondition []database.WhereCondition) (results []ProvisionParameter, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("country_code asc, product_code asc, stage asc").Find(&results).Error
+	return
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ProvisionRun [...]
+type ProvisionRun struct {
+	RunID     string      `gorm:"primaryKey;column:run_id" json:"-"`
+	AsOfDate  time.Time   `gorm:"column:as_of_date" json:"asOfDate"`
+	LoanCount int         `gorm:"column:loan_count" json:"loanCount"`
+	RunBy     null.String `gorm:"column:run_by" json:"runBy"`
+	CreatedAt time.Time   `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName get sql table name.
+func (m *ProvisionRun) TableName() string {
+	return "provision_run"
+}
+
+// ProvisionRunColumns get sql column name.
+var ProvisionRunColumns = struct {
+	RunID     string
+	AsOfDate  string
+	LoanCount string
+	RunBy     string
+	CreatedAt string
+}{
+	RunID:     "run_id",
+	AsOfDate:  "as_of_date",
+	LoanCount: "loan_count",
+	RunBy:     "run_by",
+	CreatedAt: "created_at",
+}
+
+func (m *ProvisionRun) FindByPrimaryKey(runId string) (result ProvisionRun, err error) {
+	err = database.MysqlDB.Model(m).Where("run_id = ?", runId).Find(&result).Error
+	return
+}
+
+func (m *ProvisionRun) FindAllByCondition(whereCondition []database.WhereCondition) (results []ProvisionRun, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("as_of_date desc, created_at desc").Find(&results).Error
+	return
+}
+
+// FindLatestTx returns the run for the most recent month end, the latest of them when it was run again
+func (m *ProvisionRun) FindLatestTx(tx *gorm.DB) (result ProvisionRun, err error) {
+	err = tx.Model(m).Order("as_of_date desc, created_at desc").Limit(1).Find(&result).Error
+	return
+}
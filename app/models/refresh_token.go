@@ -0,0 +1,52 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// RefreshToken [...]
+type RefreshToken struct {
+	TokenID    string      `gorm:"primaryKey;column:token_id" json:"tokenId"`
+	SessionID  string      `gorm:"column:session_id" json:"sessionId"`
+	Status     string      `gorm:"column:status" json:"status"`
+	ExpiresAt  time.Time   `gorm:"column:expires_at" json:"expiresAt"`
+	UsedAt     null.Time   `gorm:"column:used_at" json:"usedAt"`
+	ReplacedBy null.String `gorm:"column:replaced_by" json:"replacedBy"`
+	CreatedAt  time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt  time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+// RefreshTokenColumns get sql column name.
+var RefreshTokenColumns = struct {
+	TokenID    string
+	SessionID  string
+	Status     string
+	ExpiresAt  string
+	UsedAt     string
+	ReplacedBy string
+	CreatedAt  string
+	UpdatedAt  string
+}{
+	TokenID:    "token_id",
+	SessionID:  "session_id",
+	Status:     "status",
+	ExpiresAt:  "expires_at",
+	UsedAt:     "used_at",
+	ReplacedBy: "replaced_by",
+	CreatedAt:  "created_at",
+	UpdatedAt:  "updated_at",
+}
+
+func (m *RefreshToken) FindByPrimaryKeyForUpdate(tx *gorm.DB, tokenId string) (result RefreshToken, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("token_id = ?", tokenId).Find(&result).Error
+	return
+}
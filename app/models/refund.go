@@ -0,0 +1,57 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// Refund [...]
+type Refund struct {
+	RefundID        string          `gorm:"primaryKey;column:refund_id" json:"-"`
+	ApplicationID   string          `gorm:"column:application_id" json:"applicationId"`
+	LoanApplication LoanApplication `gorm:"joinForeignKey:application_id;foreignKey:application_id;references:ApplicationID" json:"-"`
+	CurrencyCode    string          `gorm:"column:currency_code" json:"currencyCode"`
+	Amount          float64         `gorm:"column:amount" json:"amount"`
+	Status          string          `gorm:"column:status" json:"status"`
+	Reason          null.String     `gorm:"column:reason" json:"reason"`
+	ProcessedBy     string          `gorm:"column:processed_by" json:"processedBy"`
+	CreatedAt       time.Time       `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt       time.Time       `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *Refund) TableName() string {
+	return "refund"
+}
+
+// RefundColumns get sql column name.
+var RefundColumns = struct {
+	RefundID      string
+	ApplicationID string
+	CurrencyCode  string
+	Amount        string
+	Status        string
+	Reason        string
+	ProcessedBy   string
+	CreatedAt     string
+	UpdatedAt     string
+}{
+	RefundID:      "refund_id",
+	ApplicationID: "application_id",
+	CurrencyCode:  "currency_code",
+	Amount:        "amount",
+	Status:        "status",
+	Reason:        "reason",
+	ProcessedBy:   "processed_by",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
+}
+
+func (m *Refund) FindAllByCondition(whereCondition []database.WhereCondition) (results []Refund, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("created_at asc")
+	err = db.Find(&results).Error
+	return
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/guregu/null"
 	"github.com/nishanthrk/aspire-lms/app/database"
 	"github.com/nishanthrk/aspire-lms/app/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -40,6 +42,7 @@ var RepaymentColumns = struct {
 	PrincipleAmount    string
 	InterestAmount     string
 	DueDate            string
+	InstallmentDate    string
 	PaymentDate        string
 	AmountDue          string
 	AmountPaid         string
@@ -55,6 +58,7 @@ var RepaymentColumns = struct {
 	PrincipleAmount:    "principle_amount",
 	InterestAmount:     "interest_amount",
 	DueDate:            "due_date",
+	InstallmentDate:    "installment_date",
 	PaymentDate:        "payment_date",
 	AmountDue:          "amount_due",
 	AmountPaid:         "amount_paid",
@@ -79,6 +83,12 @@ func (m *Repayment) GetRepaymentDTO() (object dto.Repayment) {
 		object.OutstandingBalance = money.NewFromFloat(m.OutstandingBalance.Float64, object.CurrencyCode).Display()
 	}
 	object.RepaymentStatus = m.Status
+	object.PaymentReference = m.PaymentReference
+	return
+}
+
+func (m *Repayment) FindByPrimaryKey(repaymentId string) (result Repayment, err error) {
+	err = database.MysqlDB.Model(m).Where("repayment_id = ?", repaymentId).Find(&result).Error
 	return
 }
 
@@ -90,3 +100,13 @@ func (m *Repayment) FindAllByCondition(whereCondition []database.WhereCondition)
 	err = db.Find(&results).Error
 	return
 }
+
+// FindAllByConditionForUpdate loads the matching repayments inside the given transaction with a row lock
+func (m *Repayment) FindAllByConditionForUpdate(tx *gorm.DB, whereCondition []database.WhereCondition) (
+	results []Repayment, err error) {
+	db := tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("installment_date asc")
+	err = db.Find(&results).Error
+	return
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
 
 // RepaymentPaymentLog [...]
 type RepaymentPaymentLog struct {
@@ -35,3 +38,12 @@ var RepaymentPaymentLogColumns = struct {
 	CreatedAt:   "created_at",
 	UpdatedAt:   "updated_at",
 }
+
+func (m *RepaymentPaymentLog) FindAllByCondition(whereCondition []database.WhereCondition) (
+	results []RepaymentPaymentLog, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	db.Order("created_at asc")
+	err = db.Find(&results).Error
+	return
+}
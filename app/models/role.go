@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// Role [...]
+type Role struct {
+	RoleCode    string      `gorm:"primaryKey;column:role_code" json:"roleCode"`
+	Name        string      `gorm:"column:name" json:"name"`
+	Description null.String `gorm:"column:description" json:"description"`
+	CreatedAt   time.Time   `gorm:"column:created_at" json:"-"`
+	UpdatedAt   time.Time   `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *Role) TableName() string {
+	return "role"
+}
+
+// RoleColumns get sql column name.
+var RoleColumns = struct {
+	RoleCode    string
+	Name        string
+	Description string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	RoleCode:    "role_code",
+	Name:        "name",
+	Description: "description",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+}
+
+func (m *Role) FindByPrimaryKey(roleCode string) (result Role, err error) {
+	err = database.MysqlDB.Model(m).Where("role_code = ?", roleCode).Find(&result).Error
+	return
+}
+
+// FindByCodes returns the roles among a group of role codes
+func (m *Role) FindByCodes(roleCodes []string) (results []Role, err error) {
+	if len(roleCodes) == 0 {
+		return
+	}
+	err = database.MysqlDB.Model(m).Where("role_code IN ?", roleCodes).Find(&results).Error
+	return
+}
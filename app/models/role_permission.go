@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// RolePermission [...]
+type RolePermission struct {
+	RoleCode       string    `gorm:"primaryKey;column:role_code" json:"roleCode"`
+	PermissionCode string    `gorm:"primaryKey;column:permission_code" json:"permissionCode"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *RolePermission) TableName() string {
+	return "role_permission"
+}
+
+// RolePermissionColumns get sql column name.
+var RolePermissionColumns = struct {
+	RoleCode       string
+	PermissionCode string
+	CreatedAt      string
+}{
+	RoleCode:       "role_code",
+	PermissionCode: "permission_code",
+	CreatedAt:      "created_at",
+}
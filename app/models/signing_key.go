@@ -0,0 +1,68 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// SigningKey [...]
+type SigningKey struct {
+	Kid         string    `gorm:"primaryKey;column:kid" json:"kid"`
+	Algorithm   string    `gorm:"column:algorithm" json:"algorithm"`
+	PublicKey   string    `gorm:"column:public_key" json:"publicKey"`
+	PrivateKey  string    `gorm:"column:private_key" json:"-"`
+	Status      string    `gorm:"column:status" json:"status"`
+	ActivatesAt time.Time `gorm:"column:activates_at" json:"activatesAt"`
+	RotatesAt   time.Time `gorm:"column:rotates_at" json:"rotatesAt"`
+	RetiredAt   null.Time `gorm:"column:retired_at" json:"retiredAt"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *SigningKey) TableName() string {
+	return "signing_key"
+}
+
+// SigningKeyColumns get sql column name.
+var SigningKeyColumns = struct {
+	Kid         string
+	Algorithm   string
+	PublicKey   string
+	PrivateKey  string
+	Status      string
+	ActivatesAt string
+	RotatesAt   string
+	RetiredAt   string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	Kid:         "kid",
+	Algorithm:   "algorithm",
+	PublicKey:   "public_key",
+	PrivateKey:  "private_key",
+	Status:      "status",
+	ActivatesAt: "activates_at",
+	RotatesAt:   "rotates_at",
+	RetiredAt:   "retired_at",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+}
+
+// FindActive returns the keys that are not retired, the earliest to sign first
+func (m *SigningKey) FindActive() (results []SigningKey, err error) {
+	err = database.MysqlDB.Model(m).Where("status = ?", SigningKeyStatusActive).
+		Order("activates_at asc, created_at asc").Find(&results).Error
+	return
+}
+
+// FindActiveForUpdate returns the keys that are not retired and locks them, the earliest to sign first
+func (m *SigningKey) FindActiveForUpdate(tx *gorm.DB) (results []SigningKey, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("status = ?", SigningKeyStatusActive).
+		Order("activates_at asc, created_at asc").Find(&results).Error
+	return
+}
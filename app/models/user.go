@@ -1,23 +1,32 @@
 package models
 
 import (
+	"github.com/guregu/null"
 	"github.com/nishanthrk/aspire-lms/app/common/constants"
-	"github.com/nishanthrk/aspire-lms/app/common/utility"
 	"github.com/nishanthrk/aspire-lms/app/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 // User [...]
 type User struct {
-	UserID       string    `gorm:"primaryKey;column:user_id" json:"-"`
-	UserName     string    `gorm:"column:user_name" json:"userName"`
-	UserEmail    string    `gorm:"column:user_email" json:"userEmail"`
-	UserPassword string    `gorm:"column:user_password" json:"userPassword"`
-	UserType     string    `gorm:"column:user_type" json:"userType"`
-	MobileNumber string    `gorm:"column:mobile_number" json:"mobileNumber"`
-	CreatedAt    time.Time `gorm:"column:created_at" json:"createdAt"`
-	UpdatedAt    time.Time `gorm:"column:updated_at" json:"updatedAt"`
+	UserID                 string      `gorm:"primaryKey;column:user_id" json:"-"`
+	UserName               string      `gorm:"column:user_name" json:"userName"`
+	UserEmail              string      `gorm:"column:user_email" json:"userEmail"`
+	UserPassword           string      `gorm:"column:user_password" json:"-"`
+	UserType               string      `gorm:"column:user_type" json:"userType"`
+	Status                 string      `gorm:"column:status;default:ACTIVE" json:"status"`
+	MobileNumber           string      `gorm:"column:mobile_number" json:"mobileNumber"`
+	PasswordChangeRequired bool        `gorm:"column:password_change_required" json:"passwordChangeRequired"`
+	PasswordSetupToken     null.String `gorm:"column:password_setup_token" json:"-"`
+	PasswordSetupExpires   null.Time   `gorm:"column:password_setup_expires" json:"-"`
+	PasswordUpdatedAt      null.Time   `gorm:"column:password_updated_at" json:"passwordUpdatedAt"`
+	DeactivatedAt          null.Time   `gorm:"column:deactivated_at" json:"deactivatedAt"`
+	DeactivatedBy          null.String `gorm:"column:deactivated_by" json:"deactivatedBy"`
+	DeactivationReason     null.String `gorm:"column:deactivation_reason" json:"deactivationReason"`
+	CreatedAt              time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt              time.Time   `gorm:"column:updated_at" json:"updatedAt"`
 }
 
 // TableName get sql table name.
@@ -27,30 +36,39 @@ func (m *User) TableName() string {
 
 // UsersColumns get sql column name.
 var UsersColumns = struct {
-	UserID       string
-	UserName     string
-	UserEmail    string
-	UserPassword string
-	UserType     string
-	MobileNumber string
-	CreatedAt    string
-	UpdatedAt    string
+	UserID                 string
+	UserName               string
+	UserEmail              string
+	UserPassword           string
+	UserType               string
+	Status                 string
+	MobileNumber           string
+	PasswordChangeRequired string
+	PasswordSetupToken     string
+	PasswordSetupExpires   string
+	PasswordUpdatedAt      string
+	DeactivatedAt          string
+	DeactivatedBy          string
+	DeactivationReason     string
+	CreatedAt              string
+	UpdatedAt              string
 }{
-	UserID:       "user_id",
-	UserName:     "user_name",
-	UserEmail:    "user_email",
-	UserPassword: "user_password",
-	UserType:     "user_type",
-	MobileNumber: "mobile_number",
-	CreatedAt:    "created_at",
-	UpdatedAt:    "updated_at",
-}
-
-func (m *User) BeforeCreate(tx *gorm.DB) (err error) {
-	if m.UserPassword == "" {
-		m.UserPassword = utility.HashPassword("12345678")
-	}
-	return
+	UserID:                 "user_id",
+	UserName:               "user_name",
+	UserEmail:              "user_email",
+	UserPassword:           "user_password",
+	UserType:               "user_type",
+	Status:                 "status",
+	MobileNumber:           "mobile_number",
+	PasswordChangeRequired: "password_change_required",
+	PasswordSetupToken:     "password_setup_token",
+	PasswordSetupExpires:   "password_setup_expires",
+	PasswordUpdatedAt:      "password_updated_at",
+	DeactivatedAt:          "deactivated_at",
+	DeactivatedBy:          "deactivated_by",
+	DeactivationReason:     "deactivation_reason",
+	CreatedAt:              "created_at",
+	UpdatedAt:              "updated_at",
 }
 
 func (m *User) FindByPrimaryKey(userId string) (result User, err error) {
@@ -65,21 +83,83 @@ func (m *User) FindOneByCondition(whereCondition *[]database.WhereCondition, orC
 	return
 }
 
-func (m *User) FindLeastLoadedEmployee() (result string, err error) {
-	var leastLoadedUser struct {
-		UserID string
-		Count  int64
+// FindByPrimaryKeyForUpdate finds a user and locks it until the transaction ends
+func (m *User) FindByPrimaryKeyForUpdate(tx *gorm.DB, userId string) (result User, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("user_id = ?", userId).Find(&result).Error
+	return
+}
+
+// FindPage returns a page of the users matching a type, a status and a search on their name, email or mobile
+// number, empty filters match every user. The total counts the users matching the filters across every page.
+func (m *User) FindPage(userType string, status string, search string, page int, limit int) (
+	results []User, total int64, err error) {
+	db := database.MysqlDB.Model(m)
+	if userType != "" {
+		db = db.Where("user_type = ?", userType)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
 	}
+	if search != "" {
+		like := "%" + search + "%"
+		db = db.Where("(user_name LIKE ? OR user_email LIKE ? OR mobile_number LIKE ?)", like, like, like)
+	}
+	if err = db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Session(&gorm.Session{}).Order("created_at DESC").Order("user_id").Offset((page - 1) * limit).
+		Limit(limit).Find(&results).Error
+	return
+}
+
+// FindOneByConditionForUpdate finds a user and locks it until the transaction ends
+func (m *User) FindOneByConditionForUpdate(tx *gorm.DB, whereCondition []database.WhereCondition) (result User, err error) {
+	db := tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
+
+// EmployeeWorkload is an employee applications can be allocated to, with the work already allocated to them
+type EmployeeWorkload struct {
+	UserID           string
+	OpenApplications int64
+	LastAllocatedAt  null.Time
+}
+
+// FindAvailableWorkloads returns the active employees holding a permission who are not flagged unavailable, with the
+// number of pending applications they take part in and when they were last allocated one
+func (m *User) FindAvailableWorkloads(permission string) (results []EmployeeWorkload, err error) {
 	err = database.MysqlDB.Model(m).
-		Select("user.user_id, COUNT(loan_application_participant.participant_id) as count").
+		Select("user.user_id, "+
+			"COUNT(DISTINCT CASE WHEN loan_application.status = ? THEN loan_application.application_id END) "+
+			"AS open_applications, MAX(loan_application_participant.created_at) AS last_allocated_at",
+			LoanApplicationStatusPending).
+		Joins("LEFT JOIN employee_availability ON employee_availability.user_id = user.user_id").
 		Joins("LEFT JOIN loan_application_participant ON loan_application_participant.user_id = "+
 			"user.user_id AND loan_application_participant.participant_type = ?", constants.UserTypeEmployee).
-		Where("user.user_type = ?", constants.UserTypeEmployee).
+		Joins("LEFT JOIN loan_application ON loan_application.application_id = "+
+			"loan_application_participant.application_id").
+		Where("user.user_type = ? AND user.status = ?", constants.UserTypeEmployee, UserStatusActive).
+		Where("employee_availability.available IS NULL OR employee_availability.available = ?", true).
+		Where("EXISTS (SELECT 1 FROM user_role JOIN role_permission ON role_permission.role_code = "+
+			"user_role.role_code WHERE user_role.user_id = user.user_id AND role_permission.permission_code = ?)",
+			permission).
 		Group("user.user_id").
-		Order("count ASC").
-		Limit(1).
-		Scan(&leastLoadedUser).Error
+		Order("user.user_id").
+		Scan(&results).Error
+	return
+}
 
-	result = leastLoadedUser.UserID
+// FindEmployeesWithPermission returns the active employees holding a permission through any of their roles
+func (m *User) FindEmployeesWithPermission(permission string) (results []User, err error) {
+	err = database.MysqlDB.Model(m).
+		Distinct("user.*").
+		Joins("JOIN user_role ON user_role.user_id = user.user_id").
+		Joins("JOIN role_permission ON role_permission.role_code = user_role.role_code").
+		Where("user.user_type = ? AND user.status = ? AND role_permission.permission_code = ?",
+			constants.UserTypeEmployee, UserStatusActive, permission).
+		Find(&results).Error
 	return
 }
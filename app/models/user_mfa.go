@@ -0,0 +1,58 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// UserMfa [...]
+type UserMfa struct {
+	UserID       string    `gorm:"primaryKey;column:user_id" json:"userId"`
+	Method       string    `gorm:"column:method" json:"method"`
+	Secret       string    `gorm:"column:secret" json:"-"`
+	Status       string    `gorm:"column:status" json:"status"`
+	LastUsedStep int64     `gorm:"column:last_used_step" json:"-"`
+	EnabledAt    null.Time `gorm:"column:enabled_at" json:"enabledAt"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt    time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *UserMfa) TableName() string {
+	return "user_mfa"
+}
+
+// UserMfaColumns get sql column name.
+var UserMfaColumns = struct {
+	UserID       string
+	Method       string
+	Secret       string
+	Status       string
+	LastUsedStep string
+	EnabledAt    string
+	CreatedAt    string
+	UpdatedAt    string
+}{
+	UserID:       "user_id",
+	Method:       "method",
+	Secret:       "secret",
+	Status:       "status",
+	LastUsedStep: "last_used_step",
+	EnabledAt:    "enabled_at",
+	CreatedAt:    "created_at",
+	UpdatedAt:    "updated_at",
+}
+
+func (m *UserMfa) FindByPrimaryKey(userId string) (result UserMfa, err error) {
+	err = database.MysqlDB.Model(m).Where("user_id = ?", userId).Find(&result).Error
+	return
+}
+
+func (m *UserMfa) FindByPrimaryKeyForUpdate(tx *gorm.DB, userId string) (result UserMfa, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("user_id = ?", userId).Find(&result).Error
+	return
+}
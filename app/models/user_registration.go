@@ -0,0 +1,66 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// UserRegistration [...]
+type UserRegistration struct {
+	RegistrationID string      `gorm:"primaryKey;column:registration_id" json:"registrationId"`
+	UserName       string      `gorm:"column:user_name" json:"userName"`
+	UserEmail      string      `gorm:"column:user_email" json:"userEmail"`
+	MobileNumber   string      `gorm:"column:mobile_number" json:"mobileNumber"`
+	Channel        string      `gorm:"column:channel" json:"channel"`
+	Status         string      `gorm:"column:status" json:"status"`
+	UserID         null.String `gorm:"column:user_id" json:"userId"`
+	VerifiedAt     null.Time   `gorm:"column:verified_at" json:"verifiedAt"`
+	CreatedAt      time.Time   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt      time.Time   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName get sql table name.
+func (m *UserRegistration) TableName() string {
+	return "user_registration"
+}
+
+// UserRegistrationColumns get sql column name.
+var UserRegistrationColumns = struct {
+	RegistrationID string
+	UserName       string
+	UserEmail      string
+	MobileNumber   string
+	Channel        string
+	Status         string
+	UserID         string
+	VerifiedAt     string
+	CreatedAt      string
+	UpdatedAt      string
+}{
+	RegistrationID: "registration_id",
+	UserName:       "user_name",
+	UserEmail:      "user_email",
+	MobileNumber:   "mobile_number",
+	Channel:        "channel",
+	Status:         "status",
+	UserID:         "user_id",
+	VerifiedAt:     "verified_at",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
+}
+
+func (m *UserRegistration) FindByPrimaryKeyForUpdate(tx *gorm.DB, registrationId string) (result UserRegistration, err error) {
+	err = tx.Model(m).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("registration_id = ?", registrationId).Find(&result).Error
+	return
+}
+
+func (m *UserRegistration) FindOneByCondition(whereCondition []database.WhereCondition) (result UserRegistration, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Order("created_at desc").Find(&result).Error
+	return
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserRole [...]
+type UserRole struct {
+	UserID     string      `gorm:"primaryKey;column:user_id" json:"userId"`
+	RoleCode   string      `gorm:"primaryKey;column:role_code" json:"roleCode"`
+	AssignedBy null.String `gorm:"column:assigned_by" json:"assignedBy"`
+	CreatedAt  time.Time   `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName get sql table name.
+func (m *UserRole) TableName() string {
+	return "user_role"
+}
+
+// UserRoleColumns get sql column name.
+var UserRoleColumns = struct {
+	UserID     string
+	RoleCode   string
+	AssignedBy string
+	CreatedAt  string
+}{
+	UserID:     "user_id",
+	RoleCode:   "role_code",
+	AssignedBy: "assigned_by",
+	CreatedAt:  "created_at",
+}
+
+// FindRoleCodesByUser returns the roles assigned to a user
+func (m *UserRole) FindRoleCodesByUser(userId string) (result []string, err error) {
+	err = database.MysqlDB.Model(m).Where("user_id = ?", userId).Order("role_code").
+		Pluck("role_code", &result).Error
+	return
+}
+
+// FindUserIDsByRoles returns the users assigned any of a group of roles
+func (m *UserRole) FindUserIDsByRoles(roles []string) (result []string, err error) {
+	if len(roles) == 0 {
+		return
+	}
+	err = database.MysqlDB.Model(m).Distinct("user_id").Where("role_code IN ?", roles).
+		Pluck("user_id", &result).Error
+	return
+}
+
+// FindByUsers returns the roles assigned to a group of users
+func (m *UserRole) FindByUsers(userIds []string) (results []UserRole, err error) {
+	if len(userIds) == 0 {
+		return
+	}
+	err = database.MysqlDB.Model(m).Where("user_id IN ?", userIds).Order("user_id, role_code").Find(&results).Error
+	return
+}
+
+// DeleteByUser removes every role assigned to a user
+func (m *UserRole) DeleteByUser(tx *gorm.DB, userId string) error {
+	return tx.Where("user_id = ?", userId).Delete(&UserRole{}).Error
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"time"
+)
+
+// WriteOffAuthority [...]
+type WriteOffAuthority struct {
+	UserID       string    `gorm:"primaryKey;column:user_id" json:"userId"`
+	CurrencyCode string    `gorm:"primaryKey;column:currency_code" json:"currencyCode"`
+	MaxAmount    float64   `gorm:"column:max_amount" json:"maxAmount"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"-"`
+	UpdatedAt    time.Time `gorm:"column:updated_at" json:"-"`
+}
+
+// TableName get sql table name.
+func (m *WriteOffAuthority) TableName() string {
+	return "write_off_authority"
+}
+
+// WriteOffAuthorityColumns get sql column name.
+var WriteOffAuthorityColumns = struct {
+	UserID       string
+	CurrencyCode string
+	MaxAmount    string
+	CreatedAt    string
+	UpdatedAt    string
+}{
+	UserID:       "user_id",
+	CurrencyCode: "currency_code",
+	MaxAmount:    "max_amount",
+	CreatedAt:    "created_at",
+	UpdatedAt:    "updated_at",
+}
+
+func (m *WriteOffAuthority) FindOneByCondition(whereCondition []database.WhereCondition) (result WriteOffAuthority, err error) {
+	db := database.MysqlDB.Model(m)
+	db = database.ConditionBuilder(db, &whereCondition, nil, nil)
+	err = db.Find(&result).Error
+	return
+}
@@ -2,14 +2,42 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/configs"
+	actionController "github.com/nishanthrk/aspire-lms/app/controllers/v1/action"
+	assignmentController "github.com/nishanthrk/aspire-lms/app/controllers/v1/assignment"
+	closureController "github.com/nishanthrk/aspire-lms/app/controllers/v1/closure"
+	ledgerController "github.com/nishanthrk/aspire-lms/app/controllers/v1/ledger"
 	loanController "github.com/nishanthrk/aspire-lms/app/controllers/v1/loan"
+	mandateController "github.com/nishanthrk/aspire-lms/app/controllers/v1/mandate"
+	provisionController "github.com/nishanthrk/aspire-lms/app/controllers/v1/provision"
+	reconciliationController "github.com/nishanthrk/aspire-lms/app/controllers/v1/reconciliation"
 	repaymentController "github.com/nishanthrk/aspire-lms/app/controllers/v1/repayment"
+	signingController "github.com/nishanthrk/aspire-lms/app/controllers/v1/signing"
+	statementController "github.com/nishanthrk/aspire-lms/app/controllers/v1/statement"
 	userController "github.com/nishanthrk/aspire-lms/app/controllers/v1/user"
+	writeOffController "github.com/nishanthrk/aspire-lms/app/controllers/v1/writeoff"
 	"github.com/nishanthrk/aspire-lms/app/logger"
 	"github.com/nishanthrk/aspire-lms/app/middlewares"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/nishanthrk/aspire-lms/app/scheduler"
+	accrualService "github.com/nishanthrk/aspire-lms/app/services/accrual"
+	actionService "github.com/nishanthrk/aspire-lms/app/services/action"
+	allocationService "github.com/nishanthrk/aspire-lms/app/services/allocation"
+	assignmentService "github.com/nishanthrk/aspire-lms/app/services/assignment"
+	closureService "github.com/nishanthrk/aspire-lms/app/services/closure"
+	debitService "github.com/nishanthrk/aspire-lms/app/services/debit"
+	gatewayService "github.com/nishanthrk/aspire-lms/app/services/gateway"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
 	loanService "github.com/nishanthrk/aspire-lms/app/services/loan"
+	mandateService "github.com/nishanthrk/aspire-lms/app/services/mandate"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
+	provisionService "github.com/nishanthrk/aspire-lms/app/services/provision"
+	reconciliationService "github.com/nishanthrk/aspire-lms/app/services/reconciliation"
 	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
+	signingService "github.com/nishanthrk/aspire-lms/app/services/signing"
+	statementService "github.com/nishanthrk/aspire-lms/app/services/statement"
 	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	writeOffService "github.com/nishanthrk/aspire-lms/app/services/writeoff"
 )
 
 // SetupRoutesV1 sets up the version 1 routes for the aspire-lms API
@@ -30,9 +58,79 @@ func SetupRoutesV1(app *fiber.App) {
 	})
 
 	// Initialize the service instances
-	loanSvc := loanService.NewLoanService()
-	repaymentSvc := repaymentService.NewRepaymentService()
-	userSvc := userService.NewUserService()
+	ledgerSvc := ledgerService.NewLedgerService()
+	loanSvc := loanService.NewLoanService(ledgerSvc)
+	paymentGateway, err := gatewayService.NewPaymentGateway(configs.GetConfig().PaymentGateway,
+		configs.GetConfig().GatewaySecret)
+	if err != nil {
+		logger.Sugar.Fatal("PAYMENT_GATEWAY: ", err)
+	}
+	closureSvc := closureService.NewClosureService(configs.GetConfig().Tenant)
+	repaymentSvc := repaymentService.NewRepaymentService(paymentGateway, ledgerSvc, closureSvc)
+	notifier := notificationService.NewNotifier(configs.GetConfig().Notifier, configs.GetConfig().GetNotifierDirectory())
+	allocator, err := allocationService.NewAllocationStrategy(configs.GetConfig().Allocation)
+	if err != nil {
+		logger.Sugar.Fatal("ALLOCATION_STRATEGY: ", err)
+	}
+	userSvc := userService.NewUserService(notifier, configs.GetConfig().Tenant, allocator)
+	reconciliationSvc := reconciliationService.NewReconciliationService(repaymentSvc)
+	debitProvider := debitService.NewDebitProvider(configs.GetConfig().DebitProvider,
+		configs.GetConfig().GetDebitDirectory(), configs.GetConfig().DebitSecret)
+	mandateSvc := mandateService.NewMandateService(repaymentSvc, debitProvider, mandateService.RetryPolicy{
+		MaxAttempts: configs.GetConfig().GetDebitMaxAttempts(),
+		Interval:    configs.GetConfig().GetDebitRetryInterval(),
+	})
+
+	accrualSvc := accrualService.NewAccrualService(ledgerSvc, configs.GetConfig().GetNonAccrualDays(),
+		configs.GetConfig().GetLateFeeAmount(), configs.GetConfig().GetLateFeeGraceDays())
+	writeOffSvc := writeOffService.NewWriteOffService(ledgerSvc)
+	statementSvc := statementService.NewStatementService(configs.GetConfig().Tenant)
+	provisionSvc := provisionService.NewProvisionService(ledgerSvc, provisionService.StagingPolicy{
+		Stage2Days: configs.GetConfig().GetStage2Days(),
+		Stage3Days: configs.GetConfig().GetStage3Days(),
+	})
+
+	// Every maker-checker action type is applied by its handler once a checker approves it
+	actionSvc := actionService.NewActionService(map[string]actionService.Handler{
+		models.ActionLoanOverride:      loanService.NewOverrideApprovalHandler(ledgerSvc, repaymentSvc),
+		models.ActionEligibilityConfig: loanService.NewEligibilityConfigHandler(),
+	})
+
+	assignmentSvc := assignmentService.NewAssignmentService()
+	signingSvc := signingService.NewSigningService(signingService.RotationPolicy{
+		Algorithm: configs.GetConfig().GetJWTSigningAlgorithm(),
+		Interval:  configs.GetConfig().GetJWTKeyRotationInterval(),
+	})
+
+	// Raise the debits falling due under active mandates in the background
+	scheduler.StartDebitCollections(mandateSvc, configs.GetConfig().GetDebitSchedulerInterval())
+
+	// Accrue the interest earned on approved loans in the background
+	scheduler.StartInterestAccruals(accrualSvc, configs.GetConfig().GetAccrualSchedulerInterval())
+
+	// Provision the expected credit loss of the loan book once every month end has passed
+	scheduler.StartMonthEndProvisioning(provisionSvc, configs.GetConfig().GetProvisionSchedulerInterval())
+
+	// Publish the successors of the access token signing keys ahead of their rotation and retire the replaced ones
+	scheduler.StartKeyRotation(signingSvc, configs.GetConfig().GetJWTKeySchedulerInterval())
+
+	// The access token signing keys are published at the standard location outside /v1, so that other services
+	// can verify tokens without a JWT or X-Platform
+	app.Get("/.well-known/jwks.json", func(c *fiber.Ctx) error {
+		return signingController.GetKeySet(c, signingSvc)
+	})
+
+	// Payment gateway callbacks live outside /v1 because the gateway sends neither a JWT nor X-Platform,
+	// they are authenticated by the X-Gateway-Signature header instead
+	webhookRoute := app.Group("/webhooks")
+	webhookRoute.Post("/payment", func(c *fiber.Ctx) error {
+		return repaymentController.GatewayWebhook(c, repaymentSvc)
+	})
+
+	// Debit provider callbacks are authenticated by the X-Debit-Signature header
+	webhookRoute.Post("/debit", func(c *fiber.Ctx) error {
+		return mandateController.DebitCallback(c, mandateSvc)
+	})
 
 	// Define the user-related routes
 	userRoute := v1.Group("user")
@@ -40,11 +138,114 @@ func SetupRoutesV1(app *fiber.App) {
 		return userController.Login(c, userSvc)
 	})
 
+	// Routes for customers signing up, the verification code is exchanged for a password setup token
+	userRoute.Post("/register", func(c *fiber.Ctx) error {
+		return userController.Register(c, userSvc)
+	})
+	userRoute.Post("/register/verify", func(c *fiber.Ctx) error {
+		return userController.VerifyRegistration(c, userSvc)
+	})
+
+	// Route for replacing a temporary password, authenticated by the setup token handed out at sign in
+	userRoute.Post("/password/setup", func(c *fiber.Ctx) error {
+		return userController.SetPassword(c, userSvc)
+	})
+
+	// Routes for resetting a forgotten password with a code sent to the user's email or mobile number
+	userRoute.Post("/password/forgot", func(c *fiber.Ctx) error {
+		return userController.ForgotPassword(c, userSvc)
+	})
+	userRoute.Post("/password/reset", func(c *fiber.Ctx) error {
+		return userController.ResetPassword(c, userSvc)
+	})
+
+	// Routes for the second factor: answering the challenge of a sign in, and enrolling with it when the sign in
+	// requires a second factor the user has not set up yet
+	userRoute.Post("/mfa/verify", func(c *fiber.Ctx) error {
+		return userController.VerifyMfa(c, userSvc)
+	})
+	userRoute.Post("/mfa/challenge/enroll", func(c *fiber.Ctx) error {
+		return userController.EnrollMfaChallenge(c, userSvc)
+	})
+
+	// Routes for managing the second factor of the signed in user
+	userRoute.Post("/mfa/enroll", middlewares.RequireLoggedIn(), func(c *fiber.Ctx) error {
+		return userController.EnrollMfa(c, userSvc)
+	})
+	userRoute.Post("/mfa/confirm", middlewares.RequireLoggedIn(), func(c *fiber.Ctx) error {
+		return userController.ConfirmMfa(c, userSvc)
+	})
+	userRoute.Post("/mfa/backup-codes", middlewares.RequireLoggedIn(), func(c *fiber.Ctx) error {
+		return userController.RegenerateBackupCodes(c, userSvc)
+	})
+	userRoute.Post("/mfa/disable", middlewares.RequireLoggedIn(), func(c *fiber.Ctx) error {
+		return userController.DisableMfa(c, userSvc)
+	})
+
+	// Route for lifting the lockout of an identifier or IP address after too many failed sign ins
+	userRoute.Post("/login/unlock", middlewares.RequireLoggedIn(),
+		middlewares.RequirePermission(models.PermissionLoginUnlock),
+		func(c *fiber.Ctx) error {
+			return userController.UnlockLogin(c, userSvc)
+		})
+
+	// Route for exchanging a refresh token, it is authenticated by the refresh token itself
+	userRoute.Post("/token/refresh", func(c *fiber.Ctx) error {
+		return userController.RefreshToken(c, userSvc)
+	})
+
+	// Route for signing out of the current session, or of every session
+	userRoute.Post("/logout", middlewares.RequireLoggedIn(), func(c *fiber.Ctx) error {
+		return userController.Logout(c, userSvc)
+	})
+
+	// Define the user management routes, restricted to employees who may manage users
+	adminUserRoute := v1.Group("/users", middlewares.RequireLoggedIn(),
+		middlewares.RequirePermission(models.PermissionUserManage))
+
+	// Route for creating a customer or an employee, who is sent a code to set their password
+	adminUserRoute.Post("/", func(c *fiber.Ctx) error {
+		return userController.CreateUser(c, userSvc)
+	})
+
+	// Route for listing the users, "?type=", "?status=" and "?search=" narrow the list, "?page=" and "?limit=" page it
+	adminUserRoute.Get("/", func(c *fiber.Ctx) error {
+		return userController.GetUsers(c, userSvc)
+	})
+
+	// Route for the details of a user and their roles
+	adminUserRoute.Get("/:userId", func(c *fiber.Ctx) error {
+		return userController.GetUser(c, userSvc)
+	})
+
+	// Route for changing the name, email or mobile number of a user
+	adminUserRoute.Put("/:userId", func(c *fiber.Ctx) error {
+		return userController.UpdateUser(c, userSvc)
+	})
+
+	// Routes for deactivating a user, who loses access at once, and reactivating them
+	adminUserRoute.Post("/:userId/deactivate", func(c *fiber.Ctx) error {
+		return userController.DeactivateUser(c, userSvc)
+	})
+	adminUserRoute.Post("/:userId/reactivate", func(c *fiber.Ctx) error {
+		return userController.ReactivateUser(c, userSvc)
+	})
+
+	// Route for resetting the password of a user, who is sent a code to choose a new one
+	adminUserRoute.Post("/:userId/password/reset", func(c *fiber.Ctx) error {
+		return userController.ResetUserPassword(c, userSvc)
+	})
+
+	// Route for replacing the roles of an employee
+	adminUserRoute.Put("/:userId/roles", func(c *fiber.Ctx) error {
+		return userController.AssignRoles(c, userSvc)
+	})
+
 	// Define the application-related routes
 	applicationRoute := v1.Group("/application")
 
-	// Route for creating a new loan application
-	applicationRoute.Post("/", func(c *fiber.Ctx) error {
+	// Route for a signed in customer applying for a loan
+	applicationRoute.Post("/", middlewares.RequireLoggedIn(), func(c *fiber.Ctx) error {
 		return loanController.CreateLoanApplication(c, loanSvc, userSvc, repaymentSvc)
 	})
 
@@ -60,16 +261,252 @@ func SetupRoutesV1(app *fiber.App) {
 		return loanController.GetLoanApplication(c, loanSvc, userSvc)
 	})
 
-	// Route for making a repayment
-	restrictedApplicationRoute.Post("/:applicationId/repayment", func(c *fiber.Ctx) error {
+	// Route for the statement of account of a loan as JSON, CSV or PDF
+	restrictedApplicationRoute.Get("/:applicationId/statement", func(c *fiber.Ctx) error {
+		return statementController.GetAccountStatement(c, statementSvc, userSvc)
+	})
+
+	// Route for the no-objection letter of a closed loan
+	restrictedApplicationRoute.Get("/:applicationId/closure-letter", func(c *fiber.Ctx) error {
+		return closureController.GetClosureLetter(c, closureSvc, userSvc)
+	})
+
+	// Route for making a repayment, retries carrying the same Idempotency-Key replay the first response
+	restrictedApplicationRoute.Post("/:applicationId/repayment", middlewares.Idempotency(), func(c *fiber.Ctx) error {
 		return repaymentController.PayRepayment(c, repaymentSvc, userSvc)
 	})
 
+	// Route for registering a recurring debit mandate, granted by the customer
+	restrictedApplicationRoute.Post("/:applicationId/mandate", func(c *fiber.Ctx) error {
+		return mandateController.RegisterMandate(c, mandateSvc, userSvc)
+	})
+
+	// Route for the debit mandate of an application and its collection attempts
+	restrictedApplicationRoute.Get("/:applicationId/mandate", func(c *fiber.Ctx) error {
+		return mandateController.GetMandate(c, mandateSvc, userSvc)
+	})
+
+	// Route for cancelling the active debit mandate of an application
+	restrictedApplicationRoute.Post("/:applicationId/mandate/cancel", func(c *fiber.Ctx) error {
+		return mandateController.CancelMandate(c, mandateSvc, userSvc)
+	})
+
 	adminApplicationRoute := restrictedApplicationRoute.Group("/:applicationId/approve",
-		middlewares.RequireAdmin)
+		middlewares.RequirePermission(models.PermissionApplicationApprove))
 
 	// Route for approving a loan application
 	adminApplicationRoute.Post("/", func(c *fiber.Ctx) error {
 		return loanController.ApproveLoanApplication(c, loanSvc, userSvc, repaymentSvc)
 	})
+
+	// Route for handing the pending applications of an employee over to another employee
+	restrictedApplicationRoute.Post("/reassign",
+		middlewares.RequirePermission(models.PermissionApplicationReassign),
+		func(c *fiber.Ctx) error {
+			return assignmentController.BulkReassign(c, assignmentSvc, userSvc)
+		})
+
+	// Route for handing a pending application over to another employee
+	restrictedApplicationRoute.Post("/:applicationId/reassign",
+		middlewares.RequirePermission(models.PermissionApplicationReassign),
+		func(c *fiber.Ctx) error {
+			return assignmentController.ReassignApplication(c, assignmentSvc, userSvc)
+		})
+
+	// Route for who held an application when
+	restrictedApplicationRoute.Get("/:applicationId/assignments",
+		middlewares.RequirePermission(models.PermissionApplicationReassign, models.PermissionApplicationReadAll),
+		func(c *fiber.Ctx) error {
+			return assignmentController.GetAssignmentHistory(c, assignmentSvc)
+		})
+
+	// Route for reversing a received payment, e.g. a bounced cheque or a failed debit
+	restrictedApplicationRoute.Post("/:applicationId/payment/:paymentId/reverse",
+		middlewares.RequirePermission(models.PermissionPaymentReverse),
+		func(c *fiber.Ctx) error {
+			return repaymentController.ReversePayment(c, repaymentSvc, userSvc)
+		})
+
+	// Route for polling the gateway when a payment webhook was not received
+	restrictedApplicationRoute.Post("/:applicationId/payment/:paymentId/sync",
+		middlewares.RequirePermission(models.PermissionPaymentSync),
+		func(c *fiber.Ctx) error {
+			return repaymentController.SyncPaymentStatus(c, repaymentSvc, userSvc)
+		})
+
+	// Route for refunding excess credit left on an application
+	restrictedApplicationRoute.Post("/:applicationId/refund",
+		middlewares.RequirePermission(models.PermissionPaymentRefund),
+		func(c *fiber.Ctx) error {
+			return repaymentController.RefundExcessCredit(c, repaymentSvc, userSvc)
+		})
+
+	// Route for closing a repaid loan once nothing is owed on it
+	restrictedApplicationRoute.Post("/:applicationId/close",
+		middlewares.RequirePermission(models.PermissionLoanClose),
+		func(c *fiber.Ctx) error {
+			return closureController.CloseLoan(c, closureSvc, userSvc)
+		})
+
+	// Route for writing off an unrecoverable loan, limited by the employee's write-off authority
+	restrictedApplicationRoute.Post("/:applicationId/write-off",
+		middlewares.RequirePermission(models.PermissionLoanWriteOff),
+		func(c *fiber.Ctx) error {
+			return writeOffController.WriteOffLoan(c, writeOffSvc, userSvc)
+		})
+
+	// Route for flagging a loan as restructured, which holds it in provisioning stage 2 at least
+	restrictedApplicationRoute.Put("/:applicationId/restructured",
+		middlewares.RequirePermission(models.PermissionLoanRestructure),
+		func(c *fiber.Ctx) error {
+			return provisionController.SetRestructured(c, provisionSvc, userSvc)
+		})
+
+	// Define the bank reconciliation routes, each restricted to employees with its permission
+	reconciliationRoute := v1.Group("/reconciliation", middlewares.RequireLoggedIn())
+
+	// Route for importing a bank statement (CSV or camt.053) and posting the matched credits
+	reconciliationRoute.Post("/statement",
+		middlewares.RequirePermission(models.PermissionReconciliation),
+		func(c *fiber.Ctx) error {
+			return reconciliationController.ImportStatement(c, reconciliationSvc, userSvc)
+		})
+
+	// Route for the exceptions report of an imported statement, "?format=csv" downloads it as CSV
+	reconciliationRoute.Get("/statement/:statementId/exceptions",
+		middlewares.RequirePermission(models.PermissionReconciliation, models.PermissionReportView),
+		func(c *fiber.Ctx) error {
+			return reconciliationController.GetStatementExceptions(c, reconciliationSvc)
+		})
+
+	// Define the debit mandate routes, restricted to employees who may run collections
+	mandateRoute := v1.Group("/mandate", middlewares.RequireLoggedIn(),
+		middlewares.RequirePermission(models.PermissionCollectionsRun))
+
+	// Route for running a debit collection cycle without waiting for the scheduler
+	mandateRoute.Post("/collections/run", func(c *fiber.Ctx) error {
+		return mandateController.RunCollections(c, mandateSvc)
+	})
+
+	// Define the write-off report routes, restricted to employees who may view reports
+	writeOffRoute := v1.Group("/write-offs", middlewares.RequireLoggedIn(),
+		middlewares.RequirePermission(models.PermissionReportView))
+
+	// Route for the loans written off in a period with what was recovered on them
+	writeOffRoute.Get("/", func(c *fiber.Ctx) error {
+		return writeOffController.GetWriteOffReport(c, writeOffSvc)
+	})
+
+	// Define the expected credit loss provisioning routes, each restricted to employees with its permission
+	provisionRoute := v1.Group("/provisions", middlewares.RequireLoggedIn())
+
+	// Route for running the provisioning for a month end without waiting for the scheduler
+	provisionRoute.Post("/runs",
+		middlewares.RequirePermission(models.PermissionProvisionManage),
+		func(c *fiber.Ctx) error {
+			return provisionController.RunProvisioning(c, provisionSvc, userSvc)
+		})
+
+	// Route for the history of provisioning runs
+	provisionRoute.Get("/runs",
+		middlewares.RequirePermission(models.PermissionReportView),
+		func(c *fiber.Ctx) error {
+			return provisionController.GetProvisionRuns(c, provisionSvc)
+		})
+
+	// Route for a provisioning run with the provision of every loan
+	provisionRoute.Get("/runs/:runId",
+		middlewares.RequirePermission(models.PermissionReportView),
+		func(c *fiber.Ctx) error {
+			return provisionController.GetProvisionRun(c, provisionSvc)
+		})
+
+	// Route for the PD/LGD parameters per country, product and stage
+	provisionRoute.Get("/parameters",
+		middlewares.RequirePermission(models.PermissionReportView),
+		func(c *fiber.Ctx) error {
+			return provisionController.GetParameters(c, provisionSvc)
+		})
+
+	// Route for creating or replacing the PD/LGD parameters of a country, product and stage
+	provisionRoute.Put("/parameters",
+		middlewares.RequirePermission(models.PermissionProvisionManage),
+		func(c *fiber.Ctx) error {
+			return provisionController.SetParameter(c, provisionSvc, userSvc)
+		})
+
+	// Define the general ledger routes, each restricted to employees with its permission
+	ledgerRoute := v1.Group("/ledger", middlewares.RequireLoggedIn())
+
+	// Route for the trial balance, per currency, as of a date
+	ledgerRoute.Get("/trial-balance",
+		middlewares.RequirePermission(models.PermissionReportView),
+		func(c *fiber.Ctx) error {
+			return ledgerController.GetTrialBalance(c, ledgerSvc)
+		})
+
+	// Route for the journal entries, optionally of one application and an effective date range
+	ledgerRoute.Get("/entries",
+		middlewares.RequirePermission(models.PermissionReportView),
+		func(c *fiber.Ctx) error {
+			return ledgerController.GetJournal(c, ledgerSvc)
+		})
+
+	// Route for running the interest accrual up to a day without waiting for the scheduler
+	ledgerRoute.Post("/accruals/run",
+		middlewares.RequirePermission(models.PermissionLedgerManage),
+		func(c *fiber.Ctx) error {
+			return ledgerController.RunAccruals(c, accrualSvc)
+		})
+
+	// Define the maker-checker routes, the checker needs the permission stored with each action
+	actionRoute := v1.Group("/actions", middlewares.RequireLoggedIn(),
+		middlewares.RequirePermission(models.PermissionApplicationOverride, models.PermissionConfigManage))
+
+	// Route for the actions proposed for a checker, "?status=" and "?type=" narrow the list
+	actionRoute.Get("/", func(c *fiber.Ctx) error {
+		return actionController.GetActions(c, actionSvc)
+	})
+
+	// Route for approving a pending action, which applies it
+	actionRoute.Post("/:actionId/approve", func(c *fiber.Ctx) error {
+		return actionController.ApproveAction(c, actionSvc, userSvc)
+	})
+
+	// Route for rejecting a pending action
+	actionRoute.Post("/:actionId/reject", func(c *fiber.Ctx) error {
+		return actionController.RejectAction(c, actionSvc, userSvc)
+	})
+
+	// Define the out-of-office delegation routes, employees manage their own and reassigners those of anyone
+	delegationRoute := v1.Group("/delegations", middlewares.RequireLoggedIn())
+
+	// Route for forwarding new allocations and approval permissions to a delegate for a date range
+	delegationRoute.Post("/", func(c *fiber.Ctx) error {
+		return assignmentController.CreateDelegation(c, assignmentSvc, userSvc)
+	})
+
+	// Route for the delegations an employee gave or received, "?user_id=" lists those of another employee
+	delegationRoute.Get("/", func(c *fiber.Ctx) error {
+		return assignmentController.GetDelegations(c, assignmentSvc, userSvc)
+	})
+
+	// Route for ending a delegation before its end date
+	delegationRoute.Post("/:delegationId/revoke", func(c *fiber.Ctx) error {
+		return assignmentController.RevokeDelegation(c, assignmentSvc, userSvc)
+	})
+
+	// Define the eligibility config routes, restricted to employees who may manage configuration
+	eligibilityConfigRoute := v1.Group("/eligibility-configs", middlewares.RequireLoggedIn(),
+		middlewares.RequirePermission(models.PermissionConfigManage))
+
+	// Route for the eligibility configs of every country
+	eligibilityConfigRoute.Get("/", func(c *fiber.Ctx) error {
+		return loanController.GetEligibilityConfigs(c, loanSvc)
+	})
+
+	// Route for proposing an edit of an eligibility config, applied once a checker approves it
+	eligibilityConfigRoute.Put("/:configId", func(c *fiber.Ctx) error {
+		return loanController.ProposeEligibilityConfig(c, loanSvc, userSvc)
+	})
 }
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	accrualService "github.com/nishanthrk/aspire-lms/app/services/accrual"
+	mandateService "github.com/nishanthrk/aspire-lms/app/services/mandate"
+	provisionService "github.com/nishanthrk/aspire-lms/app/services/provision"
+	signingService "github.com/nishanthrk/aspire-lms/app/services/signing"
+)
+
+// StartDebitCollections runs the debit collection cycle in the background, once at start up and then once
+// per interval. A cycle is safe to overlap with one running on another instance of the service.
+// Parameters:
+// - mandateSvc: mandateService.MandateService running the cycle
+// - interval: time.Duration between two cycles
+func StartDebitCollections(mandateSvc mandateService.MandateService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runDebitCollections(mandateSvc)
+			<-ticker.C
+		}
+	}()
+}
+
+// runDebitCollections runs a single cycle, a failing cycle is logged and never stops the scheduler
+func runDebitCollections(mandateSvc mandateService.MandateService) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Sugar.Error("debit collection cycle panicked: ", r)
+		}
+	}()
+
+	response, handle := mandateSvc.RunCollections(time.Now())
+	if handle.Status < 0 {
+		logger.Sugar.Error("debit collection cycle failed: ", handle.Errors)
+		return
+	}
+	logger.Sugar.Infof("debit collection cycle: %+v", response.Data)
+}
+
+// StartInterestAccruals runs the interest accrual in the background, once at start up and then once per
+// interval. Each run accrues up to the last completed day, days already accrued are skipped.
+// Parameters:
+// - accrualSvc: accrualService.AccrualService running the accrual
+// - interval: time.Duration between two runs
+func StartInterestAccruals(accrualSvc accrualService.AccrualService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runInterestAccruals(accrualSvc)
+			<-ticker.C
+		}
+	}()
+}
+
+// runInterestAccruals runs a single accrual, a failing run is logged and never stops the scheduler
+func runInterestAccruals(accrualSvc accrualService.AccrualService) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Sugar.Error("interest accrual run panicked: ", r)
+		}
+	}()
+
+	response, handle := accrualSvc.RunAccruals(time.Now().AddDate(0, 0, -1))
+	if handle.Status < 0 {
+		logger.Sugar.Error("interest accrual run failed: ", handle.Errors)
+		return
+	}
+	logger.Sugar.Infof("interest accrual run: %+v", response.Data)
+}
+
+// StartMonthEndProvisioning runs the expected credit loss provisioning in the background, checking once at start
+// up and then once per interval whether the last month end has been provisioned yet
+// Parameters:
+// - provisionSvc: provisionService.ProvisionService running the provisioning
+// - interval: time.Duration between two checks
+func StartMonthEndProvisioning(provisionSvc provisionService.ProvisionService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runMonthEndProvisioning(provisionSvc)
+			<-ticker.C
+		}
+	}()
+}
+
+// runMonthEndProvisioning runs a single check, a failing run is logged and never stops the scheduler
+func runMonthEndProvisioning(provisionSvc provisionService.ProvisionService) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Sugar.Error("provisioning run panicked: ", r)
+		}
+	}()
+
+	response, handle := provisionSvc.RunMonthEnd(time.Now())
+	if handle.Status < 0 {
+		logger.Sugar.Error("provisioning run failed: ", handle.Errors)
+		return
+	}
+	if response.Status > 0 {
+		logger.Sugar.Infof("provisioning run: %+v", response.Data)
+	}
+}
+
+// StartKeyRotation rotates the access token signing keys in the background, checking once at start up and then
+// once per interval whether a key is due for rotation or retirement
+// Parameters:
+// - signingSvc: signingService.SigningService rotating the keys
+// - interval: time.Duration between two checks
+func StartKeyRotation(signingSvc signingService.SigningService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runKeyRotation(signingSvc)
+			<-ticker.C
+		}
+	}()
+}
+
+// runKeyRotation runs a single check, a failing run is logged and never stops the scheduler
+func runKeyRotation(signingSvc signingService.SigningService) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Sugar.Error("signing key rotation panicked: ", r)
+		}
+	}()
+
+	response, handle := signingSvc.RotateKeys(time.Now())
+	if handle.Status < 0 {
+		logger.Sugar.Error("signing key rotation failed: ", handle.Errors)
+		return
+	}
+	if response.Data.Created != "" || len(response.Data.Retired) > 0 {
+		logger.Sugar.Info("signing key rotation: ", response.Message)
+	}
+}
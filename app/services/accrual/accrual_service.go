@@ -0,0 +1,327 @@
+package accrual_service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// accrualDateLayout is the layout accrual dates are compared and referenced in
+const accrualDateLayout = "2006-01-02"
+
+// loanAccrual is what a run did for a single loan
+type loanAccrual struct {
+	daysAccrued       int
+	movedToNonAccrual bool
+	resumedAccrual    bool
+}
+
+// RunAccruals accrues interest for every approved loan. Each loan catches up from the day after its last
+// accrual, so a missed run is made good by the next one and running again for the same day does nothing.
+// Loans past due for the non-accrual threshold stop accruing and have their uncollected accrued interest
+// reversed, they resume from the day they are brought back under the threshold. Accruing loans are charged the
+// late fee once for every installment overdue past its grace period.
+// Parameters:
+// - asOf: the last day to accrue
+// Returns:
+// - dto.AccrualRunResponse with what the run did
+// - dto.HandleError with any error that occurred during the process
+func (s *accrualService) RunAccruals(asOf time.Time) (response dto.AccrualRunResponse, handle dto.HandleError) {
+	asOf = startOfDay(asOf)
+
+	var applicationCondition []db.WhereCondition
+	applicationCondition = append(applicationCondition, db.WhereCondition{
+		Key:       models.LoanApplicationColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusApproved,
+	})
+
+	application := models.LoanApplication{}
+	applications, err := application.FindAllByCondition(applicationCondition)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data.AsOf = asOf.Format(accrualDateLayout)
+	for _, application := range applications {
+		result, err := s.accrueLoan(application.ApplicationID, asOf)
+		if err != nil {
+			logger.Sugar.Error("could not accrue interest for application ", application.ApplicationID, ": ", err)
+			response.Data.LoansFailed++
+			continue
+		}
+		if result.daysAccrued > 0 {
+			response.Data.LoansAccrued++
+			response.Data.DaysAccrued += result.daysAccrued
+		}
+		if result.movedToNonAccrual {
+			response.Data.MovedToNonAccrual++
+		}
+		if result.resumedAccrual {
+			response.Data.ResumedAccrual++
+		}
+	}
+	return
+}
+
+// accrueLoan brings the accruals of a single loan up to date in one transaction, holding the loan's row lock so
+// that settlements and other runs wait for it
+// Parameters:
+// - applicationId: the loan to accrue
+// - asOf: the last day to accrue
+// Returns:
+// - loanAccrual with what was done for the loan
+// - error when the loan could not be accrued, nothing is posted then
+func (s *accrualService) accrueLoan(applicationId string, asOf time.Time) (result loanAccrual, err error) {
+	tx := db.MysqlDB.Begin()
+
+	application := models.LoanApplication{}
+	application, err = application.FindByPrimaryKeyForUpdate(tx, applicationId)
+	if err != nil || application.Status != models.LoanApplicationStatusApproved {
+		tx.Rollback()
+		return
+	}
+
+	statusChanged := false
+	if daysPastDue(tx, applicationId, asOf) >= s.nonAccrualDays {
+		if application.AccrualStatus == models.AccrualStatusNonAccrual {
+			tx.Rollback()
+			return
+		}
+
+		// Interest of a loan this far past due is not expected to be collected, stop recognising it
+		_, err = s.ledger.PostAccrualReversal(tx, application, asOf.Format(accrualDateLayout),
+			fmt.Sprintf("Accrued interest reversed, loan %v is %v or more days past due",
+				application.ApplicationID, s.nonAccrualDays))
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		application.AccrualStatus = models.AccrualStatusNonAccrual
+		application.AccrualStatusDate = null.TimeFrom(asOf)
+		result.movedToNonAccrual = true
+		statusChanged = true
+	} else {
+		if application.AccrualStatus == models.AccrualStatusNonAccrual {
+			application.AccrualStatus = models.AccrualStatusAccruing
+			application.AccrualStatusDate = null.TimeFrom(asOf)
+			result.resumedAccrual = true
+			statusChanged = true
+		}
+
+		result.daysAccrued, err = s.accrueDays(tx, application, asOf)
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+
+		if err = s.chargeLateFees(tx, application, asOf); err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+
+	if statusChanged {
+		if err = tx.Omit(clause.Associations).Save(&application).Error; err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		tx.Rollback()
+	}
+	return
+}
+
+// accrueDays posts the interest of every day not accrued yet up to asOf on the principal outstanding in the
+// ledger. A loan starts accruing on the day it was disbursed in the ledger and, after non-accrual, on the day
+// it resumed; loans disbursed before the ledger existed are not accrued.
+// Parameters:
+// - tx: the transaction holding the loan's lock
+// - application: the loan to accrue
+// - asOf: the last day to accrue
+// Returns:
+// - int with the number of days accrued
+// - error when an accrual could not be posted
+func (s *accrualService) accrueDays(tx *gorm.DB, application models.LoanApplication, asOf time.Time) (
+	days int, err error) {
+	latest := models.InterestAccrual{}
+	latest, err = latest.FindLatestTx(tx, application.ApplicationID)
+	if err != nil {
+		return
+	}
+
+	var start time.Time
+	if latest.AccrualID != "" {
+		start = startOfDay(latest.AccrualDate).AddDate(0, 0, 1)
+	} else {
+		var entryCondition []db.WhereCondition
+		entryCondition = append(entryCondition, db.WhereCondition{
+			Key:       models.JournalEntryColumns.ApplicationID,
+			Condition: "=",
+			Value:     application.ApplicationID,
+		})
+		entryCondition = append(entryCondition, db.WhereCondition{
+			Key:       models.JournalEntryColumns.EntryType,
+			Condition: "=",
+			Value:     models.JournalEntryDisbursement,
+		})
+
+		disbursement := models.JournalEntry{}
+		disbursement, err = disbursement.FindOneByConditionTx(tx, entryCondition)
+		if err != nil || disbursement.EntryID == "" {
+			return
+		}
+		start = startOfDay(disbursement.EffectiveDate)
+	}
+	if application.AccrualStatusDate.Valid && startOfDay(application.AccrualStatusDate.Time).After(start) {
+		start = startOfDay(application.AccrualStatusDate.Time)
+	}
+
+	journalLine := models.JournalLine{}
+	principal, err := journalLine.ApplicationBalance(tx, models.LedgerAccountLoanPrincipal, application.ApplicationID)
+	if err != nil {
+		return
+	}
+
+	for day := start; !day.After(asOf); day = day.AddDate(0, 0, 1) {
+		accrual := models.InterestAccrual{
+			AccrualID:        uuid.New().String(),
+			ApplicationID:    application.ApplicationID,
+			AccrualDate:      day,
+			PrincipalBalance: math.Max(principal, 0),
+			InterestRate:     application.InterestRate,
+			Amount:           dailyInterest(principal, application.InterestRate),
+		}
+
+		entry, postErr := s.ledger.PostInterestAccrual(tx, application, accrual.Amount, day)
+		if postErr != nil {
+			err = postErr
+			return
+		}
+		if entry.EntryID != "" {
+			accrual.EntryID = null.StringFrom(entry.EntryID)
+		}
+
+		if err = tx.Create(&accrual).Error; err != nil {
+			return
+		}
+		days++
+	}
+	return
+}
+
+// chargeLateFees charges the late fee of every unpaid installment overdue past its grace period on asOf. The fee
+// is posted on the day after the grace period and referenced by the installment, so an installment is charged once
+// however many runs see it overdue.
+// Parameters:
+// - tx: the transaction holding the loan's lock
+// - application: the loan to charge
+// - asOf: the day the installments are checked on
+// Returns:
+// - error when a fee could not be posted
+func (s *accrualService) chargeLateFees(tx *gorm.DB, application models.LoanApplication, asOf time.Time) error {
+	if s.lateFee <= 0 {
+		return nil
+	}
+
+	for _, repayment := range lateInstallments(pendingRepayments(tx, application.ApplicationID), asOf,
+		s.lateFeeGrace) {
+		err := s.ledger.PostFeeCharge(tx, application, lateFeeReference(repayment.RepaymentID), s.lateFee,
+			startOfDay(repayment.InstallmentDate).AddDate(0, 0, s.lateFeeGrace+1),
+			fmt.Sprintf("Late payment fee, installment %d", repayment.InstallmentNumber))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lateInstallments returns the unpaid installments overdue past the grace period on a day
+// Parameters:
+// - repayments: the unpaid installments in installment order
+// - day: the day they are checked on
+// - grace: the days an installment may be overdue without a late fee
+// Returns:
+// - []models.Repayment with the installments to charge
+func lateInstallments(repayments []models.Repayment, day time.Time, grace int) (late []models.Repayment) {
+	for _, repayment := range repayments {
+		if overdueDays(repayment.InstallmentDate, day) <= grace {
+			// Installments fall due in order, the later ones are not overdue for longer
+			break
+		}
+		late = append(late, repayment)
+	}
+	return
+}
+
+// lateFeeReference identifies the late fee of an installment
+func lateFeeReference(repaymentId string) string {
+	return "late:" + repaymentId
+}
+
+// daysPastDue returns how many days the oldest unpaid installment of a loan is overdue on a day, zero when
+// nothing is overdue
+func daysPastDue(tx *gorm.DB, applicationId string, day time.Time) int {
+	repayments := pendingRepayments(tx, applicationId)
+	if len(repayments) == 0 {
+		return 0
+	}
+	return overdueDays(repayments[0].InstallmentDate, day)
+}
+
+// pendingRepayments returns the unpaid installments of a loan in installment order, locking them
+func pendingRepayments(tx *gorm.DB, applicationId string) []models.Repayment {
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     applicationId,
+	})
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusPending,
+	})
+
+	repayment := models.Repayment{}
+	repayments, _ := repayment.FindAllByConditionForUpdate(tx, repaymentCondition)
+	return repayments
+}
+
+// overdueDays returns the whole days from a due date to a day, zero when the due date has not passed
+func overdueDays(dueDate time.Time, day time.Time) int {
+	days := int(math.Round(startOfDay(day).Sub(startOfDay(dueDate)).Hours() / 24))
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// dailyInterest returns a day's simple interest on a principal at an annual percentage rate, on an actual/365
+// basis, rounded to the cent
+func dailyInterest(principal float64, annualRate float64) float64 {
+	if principal <= 0 || annualRate <= 0 {
+		return 0
+	}
+	return math.Round(principal*annualRate/100/365*100) / 100
+}
+
+// startOfDay truncates a time to midnight in its own location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,69 @@
+package accrual_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyInterest_Actual365(t *testing.T) {
+	// 100,000 at 12% a year earns 32.876... a day
+	assert.Equal(t, 32.88, dailyInterest(100000, 12))
+	assert.Equal(t, 0.03, dailyInterest(100, 10))
+}
+
+func TestDailyInterest_NothingOutstanding(t *testing.T) {
+	assert.Equal(t, float64(0), dailyInterest(0, 12))
+	assert.Equal(t, float64(0), dailyInterest(-50, 12))
+	assert.Equal(t, float64(0), dailyInterest(1000, 0))
+}
+
+func TestOverdueDays(t *testing.T) {
+	dueDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, overdueDays(dueDate, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, overdueDays(dueDate, time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1, overdueDays(dueDate, time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 90, overdueDays(dueDate, time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestStartOfDay_KeepsLocation(t *testing.T) {
+	location := time.FixedZone("IST", 5*60*60+30*60)
+	day := startOfDay(time.Date(2024, 3, 5, 23, 59, 0, 0, location))
+
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, location), day)
+}
+
+func TestLateInstallments_PastGracePeriod(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", InstallmentDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{RepaymentID: "r2", InstallmentDate: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{RepaymentID: "r3", InstallmentDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// On 5 March the first installment is 34 days overdue and the second 5, which is still within the grace period
+	late := lateInstallments(repayments, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 5)
+	assert.Len(t, late, 1)
+	assert.Equal(t, "r1", late[0].RepaymentID)
+
+	late = lateInstallments(repayments, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), 5)
+	assert.Len(t, late, 2)
+
+	assert.Empty(t, lateInstallments(repayments, time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC), 5))
+}
+
+func TestChargeLateFees_NoFeeConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Without a late fee nothing is looked up nor posted
+	ledger := ledgerService.NewMockLedgerService(ctrl)
+	service := &accrualService{ledger: ledger, nonAccrualDays: 90, lateFeeGrace: 5}
+
+	assert.NoError(t, service.chargeLateFees(nil, models.LoanApplication{ApplicationID: "application_id"},
+		time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)))
+}
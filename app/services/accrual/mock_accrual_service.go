@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/accrual/service.go
+
+// Package accrual_service is a generated GoMock package.
+package accrual_service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+// MockAccrualService is a mock of AccrualService interface.
+type MockAccrualService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccrualServiceMockRecorder
+}
+
+// MockAccrualServiceMockRecorder is the mock recorder for MockAccrualService.
+type MockAccrualServiceMockRecorder struct {
+	mock *MockAccrualService
+}
+
+// NewMockAccrualService creates a new mock instance.
+func NewMockAccrualService(ctrl *gomock.Controller) *MockAccrualService {
+	mock := &MockAccrualService{ctrl: ctrl}
+	mock.recorder = &MockAccrualServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccrualService) EXPECT() *MockAccrualServiceMockRecorder {
+	return m.recorder
+}
+
+// RunAccruals mocks base method.
+func (m *MockAccrualService) RunAccruals(asOf time.Time) (dto.AccrualRunResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunAccruals", asOf)
+	ret0, _ := ret[0].(dto.AccrualRunResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RunAccruals indicates an expected call of RunAccruals.
+func (mr *MockAccrualServiceMockRecorder) RunAccruals(asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunAccruals", reflect.TypeOf((*MockAccrualService)(nil).RunAccruals), asOf)
+}
@@ -0,0 +1,39 @@
+package accrual_service
+
+import (
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+)
+
+// AccrualService defines the interface for the daily interest accrual engine
+type AccrualService interface {
+	// RunAccruals Accrues the interest of every approved loan for each day up to and including asOf, and charges
+	// the late fee of installments overdue past their grace period
+	RunAccruals(asOf time.Time) (dto.AccrualRunResponse, dto.HandleError)
+}
+
+// accrualService is an implementation of AccrualService
+type accrualService struct {
+	ledger         ledgerService.LedgerService
+	nonAccrualDays int
+	lateFee        float64
+	lateFeeGrace   int
+}
+
+// NewAccrualService returns a new instance of AccrualService
+// Parameters:
+// - ledger: ledgerService.LedgerService posting the accruals
+// - nonAccrualDays: days past due from which a loan stops accruing
+// - lateFee: fee charged on an installment overdue past its grace period, zero charges none
+// - lateFeeGrace: days an installment may be overdue before it is charged the late fee
+func NewAccrualService(ledger ledgerService.LedgerService, nonAccrualDays int, lateFee float64,
+	lateFeeGrace int) AccrualService {
+	return &accrualService{
+		ledger:         ledger,
+		nonAccrualDays: nonAccrualDays,
+		lateFee:        lateFee,
+		lateFeeGrace:   lateFeeGrace,
+	}
+}
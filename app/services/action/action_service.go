@@ -0,0 +1,270 @@
+package action_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Propose stores a sensitive operation as a pending action instead of performing it. It is applied once a different
+// user holding the permission approves it, by the Handler of its type.
+// Parameters:
+// - tx: the transaction of the operation proposing the action
+// - actionType: what the action does, one Handler applies each type
+// - resourceId: what the action changes, a resource has a single pending action of a type at a time
+// - payload: everything the Handler needs to apply the action, stored as JSON
+// - permission: the permission the checker needs
+// - maker: the user proposing the action
+// Returns:
+// - models.PendingAction that was stored
+// - error when another action on the resource is pending or the action could not be stored
+func Propose(tx *gorm.DB, actionType string, resourceId string, payload interface{}, permission string,
+	maker models.User) (action models.PendingAction, err error) {
+	existing, err := action.FindPendingByResource(tx, actionType, resourceId)
+	if err != nil {
+		return
+	}
+	if existing.ActionID != "" {
+		err = fmt.Errorf("action %v on %v is already awaiting a checker", existing.ActionID, resourceId)
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	action = models.PendingAction{
+		ActionID:       uuid.New().String(),
+		ActionType:     actionType,
+		ResourceID:     resourceId,
+		Payload:        string(body),
+		PermissionCode: permission,
+		Status:         models.PendingActionStatusPending,
+		MakerID:        maker.UserID,
+		CreatedAt:      time.Now(),
+	}
+	err = tx.Create(&action).Error
+	return
+}
+
+// GetActions lists the proposed actions, the oldest first
+// Parameters:
+// - request: dto.PendingActionListRequest with the optional status and type
+// Returns:
+// - dto.PendingActionListResponse with the actions
+// - dto.HandleError with any error that occurred during the process
+func (s *actionService) GetActions(request dto.PendingActionListRequest) (
+	response dto.PendingActionListResponse, handle dto.HandleError) {
+	var actionCondition []db.WhereCondition
+	if request.Status != "" {
+		actionCondition = append(actionCondition, db.WhereCondition{
+			Key:       models.PendingActionColumns.Status,
+			Condition: "=",
+			Value:     request.Status,
+		})
+	}
+	if request.ActionType != "" {
+		actionCondition = append(actionCondition, db.WhereCondition{
+			Key:       models.PendingActionColumns.ActionType,
+			Condition: "=",
+			Value:     request.ActionType,
+		})
+	}
+
+	action := models.PendingAction{}
+	actions, err := action.FindAllByCondition(actionCondition)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	response.Data.Actions = []dto.PendingActionObject{}
+	for _, action := range actions {
+		response.Data.Actions = append(response.Data.Actions, ToActionObject(action))
+	}
+	response.Status = 1
+	response.Message = fmt.Sprintf("Total %v action(s) found", len(actions))
+	return
+}
+
+// ApproveAction applies a pending action. The checker has to be a different user than the maker and hold the
+// permission of the action. The action is applied and marked APPLIED in one transaction, so it is either fully done
+// or still pending.
+// Parameters:
+// - request: dto.ActionDecisionRequest with the action ID and an optional reason
+// - checker: the user approving the action
+// Returns:
+// - dto.PendingActionResponse with the applied action
+// - dto.HandleError with any error that occurred during the process
+func (s *actionService) ApproveAction(request dto.ActionDecisionRequest, checker models.User) (
+	response dto.PendingActionResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	action, handler, handle := s.checkAction(tx, request.ActionID, checker)
+	if handle.Status < 0 {
+		tx.Rollback()
+		return
+	}
+
+	result, err := handler.Apply(tx, action, checker)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	action.Result = null.NewString(result, result != "")
+	if err = decideAction(tx, &action, models.PendingActionStatusApplied, request.Reason, checker); err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Action approved and applied"
+	response.Data = ToActionObject(action)
+	return
+}
+
+// RejectAction discards a pending action without applying it. Like an approval it is decided by a different user
+// than the maker holding the permission of the action.
+// Parameters:
+// - request: dto.ActionDecisionRequest with the action ID and the reason
+// - checker: the user rejecting the action
+// Returns:
+// - dto.PendingActionResponse with the rejected action
+// - dto.HandleError with any error that occurred during the process
+func (s *actionService) RejectAction(request dto.ActionDecisionRequest, checker models.User) (
+	response dto.PendingActionResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	action, handler, handle := s.checkAction(tx, request.ActionID, checker)
+	if handle.Status < 0 {
+		tx.Rollback()
+		return
+	}
+
+	if err := handler.Reject(tx, action, checker); err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	if err := decideAction(tx, &action, models.PendingActionStatusRejected, request.Reason, checker); err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Action rejected"
+	response.Data = ToActionObject(action)
+	return
+}
+
+// checkAction locks a pending action and verifies that the checker may decide it
+// Parameters:
+// - tx: the transaction the action is decided in
+// - actionId: the action to decide
+// - checker: the user deciding it
+// Returns:
+// - models.PendingAction that was locked
+// - Handler of its type
+// - dto.HandleError with a negative status when the action cannot be decided by the checker
+func (s *actionService) checkAction(tx *gorm.DB, actionId string, checker models.User) (
+	action models.PendingAction, handler Handler, handle dto.HandleError) {
+	action, err := action.FindByPrimaryKeyForUpdate(tx, actionId)
+	if err != nil || action.ActionID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("action %v not found", actionId)
+		return
+	}
+	if action.Status != models.PendingActionStatusPending {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("action has already been %v", action.Status)
+		return
+	}
+	if err = checkChecker(action, checker); err != nil {
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	handler, ok := s.handlers[action.ActionType]
+	if !ok {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("actions of type %v cannot be checked", action.ActionType)
+	}
+	return
+}
+
+// checkChecker verifies that a user other than the maker, holding the permission of the action, decides it
+func checkChecker(action models.PendingAction, checker models.User) error {
+	if checker.UserID == action.MakerID {
+		return fmt.Errorf("an action has to be checked by someone other than who proposed it")
+	}
+	if !authorizationService.UserHasPermission(checker, action.PermissionCode) {
+		return fmt.Errorf("missing permission %v", action.PermissionCode)
+	}
+	return nil
+}
+
+// decideAction records the decision on a pending action
+func decideAction(tx *gorm.DB, action *models.PendingAction, status string, reason string,
+	checker models.User) error {
+	action.Status = status
+	action.CheckerID = null.StringFrom(checker.UserID)
+	action.DecisionReason = null.NewString(reason, reason != "")
+	action.DecidedAt = null.TimeFrom(time.Now())
+	return tx.Omit(clause.Associations).Save(action).Error
+}
+
+// ToActionObject converts a pending action to the DTO it is reported as
+func ToActionObject(action models.PendingAction) dto.PendingActionObject {
+	object := dto.PendingActionObject{
+		ActionID:       action.ActionID,
+		ActionType:     action.ActionType,
+		ResourceID:     action.ResourceID,
+		Payload:        json.RawMessage(action.Payload),
+		PermissionCode: action.PermissionCode,
+		Status:         action.Status,
+		MakerID:        action.MakerID,
+		CheckerID:      action.CheckerID.String,
+		DecisionReason: action.DecisionReason.String,
+		Result:         action.Result.String,
+		CreatedAt:      action.CreatedAt.Format(time.RFC3339),
+	}
+	if action.DecidedAt.Valid {
+		object.DecidedAt = action.DecidedAt.Time.Format(time.RFC3339)
+	}
+	return object
+}
@@ -0,0 +1,52 @@
+package action_service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckChecker_MakerCannotCheck(t *testing.T) {
+	action := models.PendingAction{
+		ActionID:       "action_id",
+		MakerID:        "maker_id",
+		PermissionCode: models.PermissionConfigManage,
+	}
+
+	err := checkChecker(action, models.User{UserID: "maker_id"})
+	assert.EqualError(t, err, "an action has to be checked by someone other than who proposed it")
+}
+
+func TestToActionObject(t *testing.T) {
+	createdAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	action := models.PendingAction{
+		ActionID:       "action_id",
+		ActionType:     models.ActionEligibilityConfig,
+		ResourceID:     "1",
+		Payload:        `{"max_foir":0.5}`,
+		PermissionCode: models.PermissionConfigManage,
+		Status:         models.PendingActionStatusPending,
+		MakerID:        "maker_id",
+		CreatedAt:      createdAt,
+	}
+
+	object := ToActionObject(action)
+	assert.Equal(t, "2024-06-01T10:00:00Z", object.CreatedAt)
+	assert.Empty(t, object.DecidedAt)
+	assert.Empty(t, object.CheckerID)
+
+	// The payload is reported as the JSON it was proposed with
+	body, _ := json.Marshal(object)
+	assert.Contains(t, string(body), `"payload":{"max_foir":0.5}`)
+
+	action.Status = models.PendingActionStatusApplied
+	action.CheckerID = null.StringFrom("checker_id")
+	action.DecidedAt = null.TimeFrom(createdAt.Add(time.Hour))
+	object = ToActionObject(action)
+	assert.Equal(t, "checker_id", object.CheckerID)
+	assert.Equal(t, "2024-06-01T11:00:00Z", object.DecidedAt)
+}
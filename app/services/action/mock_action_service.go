@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/action/service.go
+
+// Package action_service is a generated GoMock package.
+package action_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+	gorm "gorm.io/gorm"
+)
+
+// MockActionService is a mock of ActionService interface.
+type MockActionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockActionServiceMockRecorder
+}
+
+// MockActionServiceMockRecorder is the mock recorder for MockActionService.
+type MockActionServiceMockRecorder struct {
+	mock *MockActionService
+}
+
+// NewMockActionService creates a new mock instance.
+func NewMockActionService(ctrl *gomock.Controller) *MockActionService {
+	mock := &MockActionService{ctrl: ctrl}
+	mock.recorder = &MockActionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActionService) EXPECT() *MockActionServiceMockRecorder {
+	return m.recorder
+}
+
+// ApproveAction mocks base method.
+func (m *MockActionService) ApproveAction(request dto.ActionDecisionRequest, checker models.User) (dto.PendingActionResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveAction", request, checker)
+	ret0, _ := ret[0].(dto.PendingActionResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ApproveAction indicates an expected call of ApproveAction.
+func (mr *MockActionServiceMockRecorder) ApproveAction(request, checker interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveAction", reflect.TypeOf((*MockActionService)(nil).ApproveAction), request, checker)
+}
+
+// GetActions mocks base method.
+func (m *MockActionService) GetActions(request dto.PendingActionListRequest) (dto.PendingActionListResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActions", request)
+	ret0, _ := ret[0].(dto.PendingActionListResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetActions indicates an expected call of GetActions.
+func (mr *MockActionServiceMockRecorder) GetActions(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActions", reflect.TypeOf((*MockActionService)(nil).GetActions), request)
+}
+
+// RejectAction mocks base method.
+func (m *MockActionService) RejectAction(request dto.ActionDecisionRequest, checker models.User) (dto.PendingActionResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectAction", request, checker)
+	ret0, _ := ret[0].(dto.PendingActionResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RejectAction indicates an expected call of RejectAction.
+func (mr *MockActionServiceMockRecorder) RejectAction(request, checker interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectAction", reflect.TypeOf((*MockActionService)(nil).RejectAction), request, checker)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockHandler) Apply(tx *gorm.DB, action models.PendingAction, checker models.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", tx, action, checker)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockHandlerMockRecorder) Apply(tx, action, checker interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockHandler)(nil).Apply), tx, action, checker)
+}
+
+// Reject mocks base method.
+func (m *MockHandler) Reject(tx *gorm.DB, action models.PendingAction, checker models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", tx, action, checker)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockHandlerMockRecorder) Reject(tx, action, checker interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockHandler)(nil).Reject), tx, action, checker)
+}
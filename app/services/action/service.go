@@ -0,0 +1,42 @@
+package action_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+)
+
+// ActionService defines the interface for the maker-checker of sensitive operations
+type ActionService interface {
+	// GetActions Lists the proposed actions, optionally of one status and type
+	GetActions(request dto.PendingActionListRequest) (dto.PendingActionListResponse, dto.HandleError)
+
+	// ApproveAction Applies a proposed action once a second user checked it
+	ApproveAction(request dto.ActionDecisionRequest, checker models.User) (dto.PendingActionResponse, dto.HandleError)
+
+	// RejectAction Discards a proposed action without applying it
+	RejectAction(request dto.ActionDecisionRequest, checker models.User) (dto.PendingActionResponse, dto.HandleError)
+}
+
+// Handler carries out the actions of one type once they are checked
+type Handler interface {
+	// Apply performs the action in the transaction it is approved in and describes the outcome
+	Apply(tx *gorm.DB, action models.PendingAction, checker models.User) (string, error)
+
+	// Reject undoes whatever the proposal put on hold, in the transaction it is rejected in
+	Reject(tx *gorm.DB, action models.PendingAction, checker models.User) error
+}
+
+// actionService is an implementation of ActionService
+type actionService struct {
+	handlers map[string]Handler
+}
+
+// NewActionService returns a new instance of ActionService
+// Parameters:
+// - handlers: the Handler of each action type, actions of other types cannot be checked
+func NewActionService(handlers map[string]Handler) ActionService {
+	return &actionService{
+		handlers: handlers,
+	}
+}
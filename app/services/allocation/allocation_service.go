@@ -0,0 +1,217 @@
+package allocation_service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// OpenWorkloadStrategy allocates to the available employee with the fewest pending applications
+type OpenWorkloadStrategy struct{}
+
+// NewOpenWorkloadStrategy returns a new instance of OpenWorkloadStrategy
+func NewOpenWorkloadStrategy() *OpenWorkloadStrategy {
+	return &OpenWorkloadStrategy{}
+}
+
+// Name returns the strategy identifier
+func (s *OpenWorkloadStrategy) Name() string {
+	return StrategyOpenWorkload
+}
+
+// SelectEmployee picks the available employee with the fewest pending applications
+func (s *OpenWorkloadStrategy) SelectEmployee(application models.LoanApplication) (string, error) {
+	workloads, err := findAvailableWorkloads()
+	if err != nil {
+		return "", err
+	}
+	return leastLoaded(workloads)
+}
+
+// RoundRobinStrategy allocates to the available employees in turn
+type RoundRobinStrategy struct{}
+
+// NewRoundRobinStrategy returns a new instance of RoundRobinStrategy
+func NewRoundRobinStrategy() *RoundRobinStrategy {
+	return &RoundRobinStrategy{}
+}
+
+// Name returns the strategy identifier
+func (s *RoundRobinStrategy) Name() string {
+	return StrategyRoundRobin
+}
+
+// SelectEmployee picks the available employee who was allocated an application the longest time ago, employees who
+// were never allocated one go first
+func (s *RoundRobinStrategy) SelectEmployee(application models.LoanApplication) (string, error) {
+	workloads, err := findAvailableWorkloads()
+	if err != nil {
+		return "", err
+	}
+	return nextInTurn(workloads)
+}
+
+// CountrySkillStrategy allocates to the available employees skilled in the country and product of the application
+type CountrySkillStrategy struct{}
+
+// NewCountrySkillStrategy returns a new instance of CountrySkillStrategy
+func NewCountrySkillStrategy() *CountrySkillStrategy {
+	return &CountrySkillStrategy{}
+}
+
+// Name returns the strategy identifier
+func (s *CountrySkillStrategy) Name() string {
+	return StrategyCountrySkill
+}
+
+// SelectEmployee picks, among the available employees with a skill matching the application, the one with the fewest
+// pending applications. Employees without any skill take the applications nobody is skilled in.
+func (s *CountrySkillStrategy) SelectEmployee(application models.LoanApplication) (string, error) {
+	workloads, err := findAvailableWorkloads()
+	if err != nil {
+		return "", err
+	}
+
+	var userIds []string
+	for _, workload := range workloads {
+		userIds = append(userIds, workload.UserID)
+	}
+	skill := models.EmployeeSkill{}
+	skills, err := skill.FindByUsers(userIds)
+	if err != nil {
+		return "", err
+	}
+	return leastLoaded(filterSkilled(workloads, skills, application))
+}
+
+// AmountTierStrategy allocates to the available employees of the role handling the amount of the application
+type AmountTierStrategy struct{}
+
+// NewAmountTierStrategy returns a new instance of AmountTierStrategy
+func NewAmountTierStrategy() *AmountTierStrategy {
+	return &AmountTierStrategy{}
+}
+
+// Name returns the strategy identifier
+func (s *AmountTierStrategy) Name() string {
+	return StrategyAmountTier
+}
+
+// SelectEmployee picks, among the available employees holding the role of a tier covering the loan amount, the one
+// with the fewest pending applications. Amounts no tier covers go to any available employee.
+func (s *AmountTierStrategy) SelectEmployee(application models.LoanApplication) (string, error) {
+	workloads, err := findAvailableWorkloads()
+	if err != nil {
+		return "", err
+	}
+
+	tier := models.AllocationTier{}
+	tiers, err := tier.FindForAmount(application.CurrencyCode, application.LoanAmount)
+	if err != nil {
+		return "", err
+	}
+	if len(tiers) == 0 {
+		return leastLoaded(workloads)
+	}
+
+	var roles []string
+	for _, tier := range tiers {
+		roles = append(roles, tier.RoleCode)
+	}
+	userRole := models.UserRole{}
+	userIds, err := userRole.FindUserIDsByRoles(roles)
+	if err != nil {
+		return "", err
+	}
+	return leastLoaded(filterUsers(workloads, userIds))
+}
+
+// findAvailableWorkloads returns the available employees who may approve applications
+func findAvailableWorkloads() ([]models.EmployeeWorkload, error) {
+	user := models.User{}
+	return user.FindAvailableWorkloads(models.PermissionApplicationApprove)
+}
+
+// leastLoaded picks the employee with the fewest pending applications, ties go to whoever waited longest for one
+func leastLoaded(workloads []models.EmployeeWorkload) (string, error) {
+	if len(workloads) == 0 {
+		return "", fmt.Errorf("no available employee to allocate the application to")
+	}
+	sorted := append([]models.EmployeeWorkload{}, workloads...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].OpenApplications != sorted[j].OpenApplications {
+			return sorted[i].OpenApplications < sorted[j].OpenApplications
+		}
+		return allocatedBefore(sorted[i], sorted[j])
+	})
+	return sorted[0].UserID, nil
+}
+
+// nextInTurn picks the employee who was allocated an application the longest time ago
+func nextInTurn(workloads []models.EmployeeWorkload) (string, error) {
+	if len(workloads) == 0 {
+		return "", fmt.Errorf("no available employee to allocate the application to")
+	}
+	sorted := append([]models.EmployeeWorkload{}, workloads...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return allocatedBefore(sorted[i], sorted[j])
+	})
+	return sorted[0].UserID, nil
+}
+
+// allocatedBefore reports whether an employee was last allocated an application before another one, never counts as
+// the earliest
+func allocatedBefore(a, b models.EmployeeWorkload) bool {
+	if !a.LastAllocatedAt.Valid || !b.LastAllocatedAt.Valid {
+		return !a.LastAllocatedAt.Valid && b.LastAllocatedAt.Valid
+	}
+	return a.LastAllocatedAt.Time.Before(b.LastAllocatedAt.Time)
+}
+
+// filterSkilled keeps the employees with a skill matching the country and product of an application. A skill without
+// a country or product matches any. When nobody matches, the employees without any skill are kept.
+func filterSkilled(workloads []models.EmployeeWorkload, skills []models.EmployeeSkill,
+	application models.LoanApplication) []models.EmployeeWorkload {
+	product := application.ProductCode
+	if product == "" {
+		product = models.ProductTermLoan
+	}
+
+	skilled := map[string]bool{}
+	matching := map[string]bool{}
+	for _, skill := range skills {
+		skilled[skill.UserID] = true
+		if (!skill.CountryCode.Valid || skill.CountryCode.String == application.CountryCode) &&
+			(!skill.ProductCode.Valid || skill.ProductCode.String == product) {
+			matching[skill.UserID] = true
+		}
+	}
+
+	var specialists, generalists []models.EmployeeWorkload
+	for _, workload := range workloads {
+		if matching[workload.UserID] {
+			specialists = append(specialists, workload)
+		} else if !skilled[workload.UserID] {
+			generalists = append(generalists, workload)
+		}
+	}
+	if len(specialists) > 0 {
+		return specialists
+	}
+	return generalists
+}
+
+// filterUsers keeps the employees among a group of users
+func filterUsers(workloads []models.EmployeeWorkload, userIds []string) (results []models.EmployeeWorkload) {
+	keep := map[string]bool{}
+	for _, userId := range userIds {
+		keep[userId] = true
+	}
+	for _, workload := range workloads {
+		if keep[workload.UserID] {
+			results = append(results, workload)
+		}
+	}
+	return
+}
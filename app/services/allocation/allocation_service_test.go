@@ -0,0 +1,84 @@
+package allocation_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAllocationStrategy(t *testing.T) {
+	for name, expected := range map[string]string{
+		"":              StrategyOpenWorkload,
+		"round_robin":   StrategyRoundRobin,
+		"COUNTRY_SKILL": StrategyCountrySkill,
+		"AMOUNT_TIER":   StrategyAmountTier,
+	} {
+		strategy, err := NewAllocationStrategy(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, strategy.Name())
+	}
+
+	// A misspelt strategy is refused rather than replaced by the default
+	_, err := NewAllocationStrategy("UNKNOWN")
+	assert.EqualError(t, err, "unknown allocation strategy UNKNOWN")
+}
+
+func TestLeastLoaded(t *testing.T) {
+	now := time.Now()
+	workloads := []models.EmployeeWorkload{
+		{UserID: "busy", OpenApplications: 5, LastAllocatedAt: null.TimeFrom(now.Add(-48 * time.Hour))},
+		{UserID: "recent", OpenApplications: 1, LastAllocatedAt: null.TimeFrom(now)},
+		{UserID: "earlier", OpenApplications: 1, LastAllocatedAt: null.TimeFrom(now.Add(-time.Hour))},
+	}
+
+	// Ties on pending applications go to whoever waited longest for one
+	employee, err := leastLoaded(workloads)
+	assert.NoError(t, err)
+	assert.Equal(t, "earlier", employee)
+
+	_, err = leastLoaded(nil)
+	assert.EqualError(t, err, "no available employee to allocate the application to")
+}
+
+func TestNextInTurn(t *testing.T) {
+	now := time.Now()
+	workloads := []models.EmployeeWorkload{
+		{UserID: "recent", LastAllocatedAt: null.TimeFrom(now)},
+		{UserID: "earlier", OpenApplications: 3, LastAllocatedAt: null.TimeFrom(now.Add(-time.Hour))},
+	}
+
+	employee, err := nextInTurn(workloads)
+	assert.NoError(t, err)
+	assert.Equal(t, "earlier", employee)
+
+	// Employees who were never allocated an application go first
+	workloads = append(workloads, models.EmployeeWorkload{UserID: "new"})
+	employee, err = nextInTurn(workloads)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", employee)
+}
+
+func TestFilterSkilled(t *testing.T) {
+	workloads := []models.EmployeeWorkload{{UserID: "india"}, {UserID: "singapore"}, {UserID: "generalist"}}
+	skills := []models.EmployeeSkill{
+		{UserID: "india", CountryCode: null.StringFrom("IN")},
+		{UserID: "singapore", CountryCode: null.StringFrom("SG"), ProductCode: null.StringFrom(models.ProductTermLoan)},
+	}
+
+	filtered := filterSkilled(workloads, skills, models.LoanApplication{CountryCode: "SG"})
+	assert.Equal(t, []models.EmployeeWorkload{{UserID: "singapore"}}, filtered)
+
+	// Applications nobody is skilled in go to the employees without any skill
+	filtered = filterSkilled(workloads, skills, models.LoanApplication{CountryCode: "US"})
+	assert.Equal(t, []models.EmployeeWorkload{{UserID: "generalist"}}, filtered)
+}
+
+func TestFilterUsers(t *testing.T) {
+	workloads := []models.EmployeeWorkload{{UserID: "underwriter"}, {UserID: "approver"}}
+
+	assert.Equal(t, []models.EmployeeWorkload{{UserID: "approver"}}, filterUsers(workloads, []string{"approver"}))
+	assert.Empty(t, filterUsers(workloads, nil))
+}
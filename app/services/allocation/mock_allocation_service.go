@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/allocation/service.go
+
+// Package allocation_service is a generated GoMock package.
+package allocation_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockAllocationStrategy is a mock of AllocationStrategy interface.
+type MockAllocationStrategy struct {
+	ctrl     *gomock.Controller
+	recorder *MockAllocationStrategyMockRecorder
+}
+
+// MockAllocationStrategyMockRecorder is the mock recorder for MockAllocationStrategy.
+type MockAllocationStrategyMockRecorder struct {
+	mock *MockAllocationStrategy
+}
+
+// NewMockAllocationStrategy creates a new mock instance.
+func NewMockAllocationStrategy(ctrl *gomock.Controller) *MockAllocationStrategy {
+	mock := &MockAllocationStrategy{ctrl: ctrl}
+	mock.recorder = &MockAllocationStrategyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAllocationStrategy) EXPECT() *MockAllocationStrategyMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockAllocationStrategy) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockAllocationStrategyMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockAllocationStrategy)(nil).Name))
+}
+
+// SelectEmployee mocks base method.
+func (m *MockAllocationStrategy) SelectEmployee(application models.LoanApplication) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectEmployee", application)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectEmployee indicates an expected call of SelectEmployee.
+func (mr *MockAllocationStrategyMockRecorder) SelectEmployee(application interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectEmployee", reflect.TypeOf((*MockAllocationStrategy)(nil).SelectEmployee), application)
+}
@@ -0,0 +1,48 @@
+package allocation_service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+const (
+	// StrategyOpenWorkload allocates to the employee with the fewest pending applications
+	StrategyOpenWorkload = "OPEN_WORKLOAD"
+
+	// StrategyRoundRobin allocates to the employee who was allocated an application the longest time ago
+	StrategyRoundRobin = "ROUND_ROBIN"
+
+	// StrategyCountrySkill allocates to the employees skilled in the country and product of the application
+	StrategyCountrySkill = "COUNTRY_SKILL"
+
+	// StrategyAmountTier allocates to the employees of the role handling the amount of the application
+	StrategyAmountTier = "AMOUNT_TIER"
+)
+
+// AllocationStrategy defines how a new loan application is routed to the employee processing it. Only employees who
+// may approve applications and are not flagged unavailable are allocated.
+type AllocationStrategy interface {
+	// Name returns the identifier of the strategy
+	Name() string
+
+	// SelectEmployee returns the employee the application is allocated to
+	SelectEmployee(application models.LoanApplication) (string, error)
+}
+
+// NewAllocationStrategy returns the allocation strategy configured by name, the open workload one when none is. An
+// unknown name is an error rather than a silent fallback, so a misspelt strategy stops the service from starting.
+func NewAllocationStrategy(name string) (AllocationStrategy, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case StrategyRoundRobin:
+		return NewRoundRobinStrategy(), nil
+	case StrategyCountrySkill:
+		return NewCountrySkillStrategy(), nil
+	case StrategyAmountTier:
+		return NewAmountTierStrategy(), nil
+	case StrategyOpenWorkload, "":
+		return NewOpenWorkloadStrategy(), nil
+	}
+	return nil, fmt.Errorf("unknown allocation strategy %v", name)
+}
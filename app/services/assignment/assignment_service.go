@@ -0,0 +1,181 @@
+package assignment_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reasons recorded when an application changes hands without anyone reassigning it
+const (
+	ReasonAllocated = "allocated"
+	ReasonEscalated = "routed to the next-level approver"
+)
+
+// RecordHandover records an application changing hands. The period the previous holder held it ends and the new
+// holder's starts.
+// Parameters:
+// - tx: the transaction the application changes hands in
+// - applicationId: the application
+// - fromUserId: the employee handing it over, empty when it is allocated for the first time
+// - toUserId: the employee taking it over
+// - assignedBy: who handed it over, empty when the system did
+// - reason: why it changed hands
+// Returns:
+// - error when the handover could not be recorded
+func RecordHandover(tx *gorm.DB, applicationId string, fromUserId string, toUserId string, assignedBy string,
+	reason string) error {
+	now := time.Now()
+	if fromUserId != "" {
+		err := tx.Model(&models.ApplicationAssignment{}).
+			Where("application_id = ? AND user_id = ? AND ended_at IS NULL", applicationId, fromUserId).
+			Update(models.ApplicationAssignmentColumns.EndedAt, now).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	assignment := models.ApplicationAssignment{
+		AssignmentID:  uuid.New().String(),
+		ApplicationID: applicationId,
+		UserID:        toUserId,
+		AssignedBy:    null.NewString(assignedBy, assignedBy != ""),
+		Reason:        null.NewString(reason, reason != ""),
+		StartedAt:     now,
+	}
+	return tx.Create(&assignment).Error
+}
+
+// ActingFor returns the holder of an application an employee acts as: themselves when they hold it, or the
+// out-of-office holder they are the delegate of. Applications without any holder recorded return an empty holder.
+// Parameters:
+// - tx: the transaction the application is locked in
+// - applicationId: the application
+// - userId: the employee acting on it
+// Returns:
+// - string with the holder the employee acts as
+// - error when someone else holds the application
+func ActingFor(tx *gorm.DB, applicationId string, userId string) (string, error) {
+	assignment := models.ApplicationAssignment{}
+	holders, err := assignment.FindOpenByApplicationForUpdate(tx, applicationId)
+	if err != nil || len(holders) == 0 {
+		return "", err
+	}
+	for _, holder := range holders {
+		if holder.UserID == userId {
+			return userId, nil
+		}
+	}
+
+	delegation := models.EmployeeDelegation{}
+	delegations, err := delegation.FindActiveByDelegate(userId, time.Now())
+	if err != nil {
+		return "", err
+	}
+	for _, delegation := range delegations {
+		for _, holder := range holders {
+			if holder.UserID == delegation.UserID {
+				return holder.UserID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("application is held by another employee")
+}
+
+// GetAssignmentHistory lists who held an application when, the earliest first
+// Parameters:
+// - applicationId: the application
+// Returns:
+// - dto.AssignmentHistoryResponse with the periods each employee held the application
+// - dto.HandleError with any error that occurred during the process
+func (s *assignmentService) GetAssignmentHistory(applicationId string) (
+	response dto.AssignmentHistoryResponse, handle dto.HandleError) {
+	application := models.LoanApplication{}
+	application, _ = application.FindByPrimaryKey(applicationId)
+	if application.ApplicationID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("application details not found")
+		return
+	}
+
+	assignment := models.ApplicationAssignment{}
+	assignments, err := assignment.FindByApplication(applicationId)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Data.ApplicationID = applicationId
+	response.Data.Assignments = []dto.AssignmentObject{}
+	for _, assignment := range assignments {
+		object := dto.AssignmentObject{
+			AssignmentID: assignment.AssignmentID,
+			UserID:       assignment.UserID,
+			AssignedBy:   assignment.AssignedBy.String,
+			Reason:       assignment.Reason.String,
+			StartedAt:    assignment.StartedAt.Format(time.RFC3339),
+		}
+		if assignment.EndedAt.Valid {
+			object.EndedAt = assignment.EndedAt.Time.Format(time.RFC3339)
+		}
+		response.Data.Assignments = append(response.Data.Assignments, object)
+	}
+	response.Status = 1
+	response.Message = fmt.Sprintf("Total %v assignment(s) found", len(assignments))
+	return
+}
+
+// lockApplication locks a pending application until the transaction ends
+func lockApplication(tx *gorm.DB, applicationId string) (application models.LoanApplication, err error) {
+	application, err = application.FindByPrimaryKeyForUpdate(tx, applicationId)
+	if err != nil {
+		return
+	}
+	if application.ApplicationID == "" {
+		err = fmt.Errorf("application %v not found", applicationId)
+		return
+	}
+	if application.Status != models.LoanApplicationStatusPending {
+		err = fmt.Errorf("application %v is %v, only pending applications are reassigned", applicationId,
+			application.Status)
+	}
+	return
+}
+
+// moveParticipant replaces an employee taking part in an application by another one
+func moveParticipant(tx *gorm.DB, applicationId string, fromUserId string, toUserId string) error {
+	participant := models.LoanApplicationParticipant{}
+	from, err := participant.FindByUserForUpdate(tx, applicationId, fromUserId, constants.UserTypeEmployee)
+	if err != nil {
+		return err
+	}
+	to, err := participant.FindByUserForUpdate(tx, applicationId, toUserId, constants.UserTypeEmployee)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case to.ParticipantID != "" && from.ParticipantID != "":
+		return tx.Delete(&from).Error
+	case to.ParticipantID != "":
+		return nil
+	case from.ParticipantID != "":
+		from.UserID = toUserId
+		return tx.Omit(clause.Associations).Save(&from).Error
+	}
+	to = models.LoanApplicationParticipant{
+		ParticipantID:   uuid.New().String(),
+		ApplicationID:   applicationId,
+		ParticipantType: constants.UserTypeEmployee,
+		UserID:          toUserId,
+	}
+	return tx.Omit(clause.Associations).Create(&to).Error
+}
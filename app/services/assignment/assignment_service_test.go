@@ -0,0 +1,52 @@
+package assignment_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHolds(t *testing.T) {
+	holders := []models.ApplicationAssignment{{UserID: "employee_id"}, {UserID: "approver_id"}}
+
+	assert.True(t, holds(holders, "approver_id"))
+	assert.False(t, holds(holders, "other_id"))
+	assert.False(t, holds(nil, "employee_id"))
+}
+
+func TestCheckDelegationOwner_Self(t *testing.T) {
+	// Employees always manage their own delegations
+	assert.NoError(t, checkDelegationOwner("employee_id", models.User{UserID: "employee_id"}))
+}
+
+func TestToDelegationObject(t *testing.T) {
+	revokedAt := time.Date(2024, 1, 5, 10, 30, 0, 0, time.UTC)
+	delegation := models.EmployeeDelegation{
+		DelegationID: "delegation_id",
+		UserID:       "employee_id",
+		DelegateID:   "other_id",
+		StartDate:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		Reason:       null.StringFrom("Vacation"),
+		CreatedBy:    "employee_id",
+		RevokedAt:    null.TimeFrom(revokedAt),
+		RevokedBy:    null.StringFrom("admin_id"),
+	}
+
+	object := toDelegationObject(delegation)
+	assert.Equal(t, "2024-01-02", object.StartDate)
+	assert.Equal(t, "2024-01-10", object.EndDate)
+	assert.Equal(t, "Vacation", object.Reason)
+	assert.Equal(t, "2024-01-05T10:30:00Z", object.RevokedAt)
+	assert.Equal(t, "admin_id", object.RevokedBy)
+
+	// A delegation still in force has no revocation
+	delegation.RevokedAt = null.Time{}
+	delegation.RevokedBy = null.String{}
+	object = toDelegationObject(delegation)
+	assert.Empty(t, object.RevokedAt)
+	assert.Empty(t, object.RevokedBy)
+}
@@ -0,0 +1,281 @@
+package assignment_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+)
+
+// maxDelegationHops is how many delegations in a row a new allocation is forwarded along
+const maxDelegationHops = 5
+
+// ResolveDelegate returns who works in place of an employee on a day: their delegate when they are out of office,
+// followed along the delegate's own delegation, or the employee themselves. A chain looping back, or reaching a
+// deactivated delegate, stops at the last employee before it.
+// Parameters:
+// - userId: the employee
+// - day: the day the work is forwarded on
+// Returns:
+// - string with the employee doing the work
+// - error when the delegations could not be read
+func ResolveDelegate(userId string, day time.Time) (string, error) {
+	seen := map[string]bool{userId: true}
+	for hop := 0; hop < maxDelegationHops; hop++ {
+		delegation := models.EmployeeDelegation{}
+		delegation, err := delegation.FindActiveByUser(userId, day)
+		if err != nil {
+			return "", err
+		}
+		if delegation.DelegationID == "" || seen[delegation.DelegateID] {
+			break
+		}
+		delegate := models.User{}
+		if delegate, err = delegate.FindByPrimaryKey(delegation.DelegateID); err != nil {
+			return "", err
+		}
+		if delegate.Status == models.UserStatusInactive {
+			break
+		}
+		userId = delegation.DelegateID
+		seen[userId] = true
+	}
+	return userId, nil
+}
+
+// CreateDelegation forwards the work of an out-of-office employee to a delegate for a date range: new applications
+// allocated to the employee go to the delegate, who acts on the employee's applications with their approval
+// permissions. Employees set their own delegation, holders of APPLICATION_REASSIGN set anyone's.
+// Parameters:
+// - request: dto.DelegationRequest with the employee, their delegate and the date range
+// - user: the user setting the delegation
+// Returns:
+// - dto.DelegationResponse with the delegation
+// - dto.HandleError with any error that occurred during the process
+func (s *assignmentService) CreateDelegation(request dto.DelegationRequest, user models.User) (
+	response dto.DelegationResponse, handle dto.HandleError) {
+	if request.UserID == "" {
+		request.UserID = user.UserID
+	}
+	if err := checkDelegationOwner(request.UserID, user); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	// Both ends of a delegation are employees
+	if err := checkEmployee(request.UserID); err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if err := checkEmployee(request.DelegateID); err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if request.DelegateID == request.UserID {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("an employee cannot delegate to themselves")
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	if endDate.Before(startDate) {
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("end date is before the start date")
+		return
+	}
+	if endDate.Before(today) {
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("delegation has already ended")
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	// An employee is forwarded to a single delegate on any day
+	delegation := models.EmployeeDelegation{}
+	overlapping, err := delegation.FindOverlapping(tx, request.UserID, startDate, endDate)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+	if len(overlapping) > 0 {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("overlaps delegation %v from %v to %v", overlapping[0].DelegationID,
+			overlapping[0].StartDate.Format("2006-01-02"), overlapping[0].EndDate.Format("2006-01-02"))
+		return
+	}
+
+	delegation = models.EmployeeDelegation{
+		DelegationID: uuid.New().String(),
+		UserID:       request.UserID,
+		DelegateID:   request.DelegateID,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Reason:       null.NewString(request.Reason, request.Reason != ""),
+		CreatedBy:    user.UserID,
+	}
+	if err = tx.Create(&delegation).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Delegation created"
+	response.Data = toDelegationObject(delegation)
+	return
+}
+
+// GetDelegations lists the delegations an employee gave or received, the latest first
+// Parameters:
+// - request: dto.DelegationListRequest with the employee, the signed in user when empty
+// - user: the signed in user, listing another employee's delegations needs APPLICATION_REASSIGN
+// Returns:
+// - dto.DelegationListResponse with the delegations
+// - dto.HandleError with any error that occurred during the process
+func (s *assignmentService) GetDelegations(request dto.DelegationListRequest, user models.User) (
+	response dto.DelegationListResponse, handle dto.HandleError) {
+	if request.UserID == "" {
+		request.UserID = user.UserID
+	}
+	if err := checkDelegationOwner(request.UserID, user); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	delegation := models.EmployeeDelegation{}
+	delegations, err := delegation.FindByParty(request.UserID)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Data.Delegations = []dto.DelegationObject{}
+	for _, delegation := range delegations {
+		response.Data.Delegations = append(response.Data.Delegations, toDelegationObject(delegation))
+	}
+	response.Status = 1
+	response.Message = fmt.Sprintf("Total %v delegation(s) found", len(delegations))
+	return
+}
+
+// RevokeDelegation ends a delegation, work is no longer forwarded from the next request on
+// Parameters:
+// - request: dto.DelegationRevokeRequest with the delegation
+// - user: the user revoking it, the delegating employee or a holder of APPLICATION_REASSIGN
+// Returns:
+// - dto.DelegationResponse with the revoked delegation
+// - dto.HandleError with any error that occurred during the process
+func (s *assignmentService) RevokeDelegation(request dto.DelegationRevokeRequest, user models.User) (
+	response dto.DelegationResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	delegation := models.EmployeeDelegation{}
+	delegation, err := delegation.FindByPrimaryKeyForUpdate(tx, request.DelegationID)
+	if err != nil || delegation.DelegationID == "" {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("delegation %v not found", request.DelegationID)
+		return
+	}
+	if err = checkDelegationOwner(delegation.UserID, user); err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if delegation.RevokedAt.Valid {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("delegation has already been revoked")
+		return
+	}
+
+	delegation.RevokedAt = null.TimeFrom(time.Now())
+	delegation.RevokedBy = null.StringFrom(user.UserID)
+	if err = tx.Save(&delegation).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Delegation revoked"
+	response.Data = toDelegationObject(delegation)
+	return
+}
+
+// checkDelegationOwner verifies that a user manages the delegations of an employee, their own or anyone's with
+// APPLICATION_REASSIGN
+func checkDelegationOwner(userId string, user models.User) error {
+	if userId == user.UserID {
+		return nil
+	}
+	if !authorizationService.UserHasPermission(user, models.PermissionApplicationReassign) {
+		return fmt.Errorf("missing permission %v", models.PermissionApplicationReassign)
+	}
+	return nil
+}
+
+// checkEmployee verifies that a user is an active employee
+func checkEmployee(userId string) error {
+	user := models.User{}
+	user, _ = user.FindByPrimaryKey(userId)
+	if user.UserID == "" || user.UserType != constants.UserTypeEmployee {
+		return fmt.Errorf("employee %v not found", userId)
+	}
+	if user.Status == models.UserStatusInactive {
+		return fmt.Errorf("employee %v has been deactivated", userId)
+	}
+	return nil
+}
+
+// toDelegationObject converts a delegation to its DTO
+func toDelegationObject(delegation models.EmployeeDelegation) dto.DelegationObject {
+	object := dto.DelegationObject{
+		DelegationID: delegation.DelegationID,
+		UserID:       delegation.UserID,
+		DelegateID:   delegation.DelegateID,
+		StartDate:    delegation.StartDate.Format("2006-01-02"),
+		EndDate:      delegation.EndDate.Format("2006-01-02"),
+		Reason:       delegation.Reason.String,
+		CreatedBy:    delegation.CreatedBy,
+		RevokedBy:    delegation.RevokedBy.String,
+	}
+	if delegation.RevokedAt.Valid {
+		object.RevokedAt = delegation.RevokedAt.Time.Format(time.RFC3339)
+	}
+	return object
+}
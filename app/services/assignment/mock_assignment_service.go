@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/assignment/service.go
+
+// Package assignment_service is a generated GoMock package.
+package assignment_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockAssignmentService is a mock of AssignmentService interface.
+type MockAssignmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssignmentServiceMockRecorder
+}
+
+// MockAssignmentServiceMockRecorder is the mock recorder for MockAssignmentService.
+type MockAssignmentServiceMockRecorder struct {
+	mock *MockAssignmentService
+}
+
+// NewMockAssignmentService creates a new mock instance.
+func NewMockAssignmentService(ctrl *gomock.Controller) *MockAssignmentService {
+	mock := &MockAssignmentService{ctrl: ctrl}
+	mock.recorder = &MockAssignmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssignmentService) EXPECT() *MockAssignmentServiceMockRecorder {
+	return m.recorder
+}
+
+// BulkReassign mocks base method.
+func (m *MockAssignmentService) BulkReassign(request dto.BulkReassignRequest, admin models.User) (dto.BulkReassignResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkReassign", request, admin)
+	ret0, _ := ret[0].(dto.BulkReassignResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// BulkReassign indicates an expected call of BulkReassign.
+func (mr *MockAssignmentServiceMockRecorder) BulkReassign(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkReassign", reflect.TypeOf((*MockAssignmentService)(nil).BulkReassign), request, admin)
+}
+
+// CreateDelegation mocks base method.
+func (m *MockAssignmentService) CreateDelegation(request dto.DelegationRequest, user models.User) (dto.DelegationResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelegation", request, user)
+	ret0, _ := ret[0].(dto.DelegationResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// CreateDelegation indicates an expected call of CreateDelegation.
+func (mr *MockAssignmentServiceMockRecorder) CreateDelegation(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelegation", reflect.TypeOf((*MockAssignmentService)(nil).CreateDelegation), request, user)
+}
+
+// GetAssignmentHistory mocks base method.
+func (m *MockAssignmentService) GetAssignmentHistory(applicationId string) (dto.AssignmentHistoryResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentHistory", applicationId)
+	ret0, _ := ret[0].(dto.AssignmentHistoryResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetAssignmentHistory indicates an expected call of GetAssignmentHistory.
+func (mr *MockAssignmentServiceMockRecorder) GetAssignmentHistory(applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentHistory", reflect.TypeOf((*MockAssignmentService)(nil).GetAssignmentHistory), applicationId)
+}
+
+// GetDelegations mocks base method.
+func (m *MockAssignmentService) GetDelegations(request dto.DelegationListRequest, user models.User) (dto.DelegationListResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelegations", request, user)
+	ret0, _ := ret[0].(dto.DelegationListResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetDelegations indicates an expected call of GetDelegations.
+func (mr *MockAssignmentServiceMockRecorder) GetDelegations(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegations", reflect.TypeOf((*MockAssignmentService)(nil).GetDelegations), request, user)
+}
+
+// ReassignApplication mocks base method.
+func (m *MockAssignmentService) ReassignApplication(request dto.ReassignRequest, admin models.User) (dto.ReassignResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignApplication", request, admin)
+	ret0, _ := ret[0].(dto.ReassignResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ReassignApplication indicates an expected call of ReassignApplication.
+func (mr *MockAssignmentServiceMockRecorder) ReassignApplication(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignApplication", reflect.TypeOf((*MockAssignmentService)(nil).ReassignApplication), request, admin)
+}
+
+// RevokeDelegation mocks base method.
+func (m *MockAssignmentService) RevokeDelegation(request dto.DelegationRevokeRequest, user models.User) (dto.DelegationResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDelegation", request, user)
+	ret0, _ := ret[0].(dto.DelegationResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RevokeDelegation indicates an expected call of RevokeDelegation.
+func (mr *MockAssignmentServiceMockRecorder) RevokeDelegation(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDelegation", reflect.TypeOf((*MockAssignmentService)(nil).RevokeDelegation), request, user)
+}
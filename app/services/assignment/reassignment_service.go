@@ -0,0 +1,173 @@
+package assignment_service
+
+import (
+	"fmt"
+
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+)
+
+// ReassignApplication hands a pending application over from the employee currently holding it to another employee.
+// The new holder replaces the previous one among the participants of the application.
+// Parameters:
+// - request: dto.ReassignRequest with the application, the employee taking it over and the reason
+// - admin: the user reassigning the application
+// Returns:
+// - dto.ReassignResponse with the previous and the new holder
+// - dto.HandleError with any error that occurred during the process
+func (s *assignmentService) ReassignApplication(request dto.ReassignRequest, admin models.User) (
+	response dto.ReassignResponse, handle dto.HandleError) {
+	if err := checkAssignee(request.ToUserID); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	object, err := reassign(request.ApplicationID, "", request.ToUserID, request.Reason, admin)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Loan application reassigned"
+	response.Data = object
+	return
+}
+
+// BulkReassign hands the pending applications an employee holds over to another employee, e.g. when they leave.
+// Every application is reassigned in its own transaction, the ones that could not be are reported as skipped.
+// Parameters:
+// - request: dto.BulkReassignRequest with both employees, optionally the applications to reassign, and the reason
+// - admin: the user reassigning the applications
+// Returns:
+// - dto.BulkReassignResponse with the reassigned and the skipped applications
+// - dto.HandleError with any error that occurred during the process
+func (s *assignmentService) BulkReassign(request dto.BulkReassignRequest, admin models.User) (
+	response dto.BulkReassignResponse, handle dto.HandleError) {
+	if err := checkAssignee(request.ToUserID); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	applicationIds := request.ApplicationIDs
+	if len(applicationIds) == 0 {
+		assignment := models.ApplicationAssignment{}
+		var err error
+		if applicationIds, err = assignment.FindPendingApplicationIDsByUser(request.FromUserID); err != nil {
+			handle.Status = -2
+			handle.Errors = err
+			return
+		}
+	}
+
+	response.Data.Reassigned = []dto.ReassignObject{}
+	response.Data.Skipped = []dto.ReassignSkipObject{}
+	for _, applicationId := range applicationIds {
+		object, err := reassign(applicationId, request.FromUserID, request.ToUserID, request.Reason, admin)
+		if err != nil {
+			response.Data.Skipped = append(response.Data.Skipped, dto.ReassignSkipObject{
+				ApplicationID: applicationId,
+				Error:         err.Error(),
+			})
+			continue
+		}
+		response.Data.Reassigned = append(response.Data.Reassigned, object)
+	}
+
+	response.Status = 1
+	response.Message = fmt.Sprintf("%v application(s) reassigned, %v skipped", len(response.Data.Reassigned),
+		len(response.Data.Skipped))
+	return
+}
+
+// reassign hands a pending application over to another employee in its own transaction
+// Parameters:
+// - applicationId: the application
+// - fromUserId: the holder handing it over, the latest holder when empty
+// - toUserId: the employee taking it over
+// - reason: why it is reassigned
+// - admin: the user reassigning it
+// Returns:
+// - dto.ReassignObject with the previous and the new holder
+// - error when the application could not be reassigned
+func reassign(applicationId string, fromUserId string, toUserId string, reason string, admin models.User) (
+	object dto.ReassignObject, err error) {
+	tx := db.MysqlDB.Begin()
+
+	// Lock the application so that it cannot be signed off while it changes hands
+	if _, err = lockApplication(tx, applicationId); err != nil {
+		tx.Rollback()
+		return
+	}
+
+	assignment := models.ApplicationAssignment{}
+	holders, err := assignment.FindOpenByApplicationForUpdate(tx, applicationId)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	if fromUserId == "" && len(holders) > 0 {
+		fromUserId = holders[0].UserID
+	}
+	if fromUserId == "" || !holds(holders, fromUserId) {
+		tx.Rollback()
+		err = fmt.Errorf("application %v is not held by %v", applicationId, fromUserId)
+		if fromUserId == "" {
+			err = fmt.Errorf("application %v is not held by anyone", applicationId)
+		}
+		return
+	}
+	if holds(holders, toUserId) {
+		tx.Rollback()
+		err = fmt.Errorf("application %v is already held by %v", applicationId, toUserId)
+		return
+	}
+
+	// The new holder replaces the previous one among the participants
+	if err = moveParticipant(tx, applicationId, fromUserId, toUserId); err != nil {
+		tx.Rollback()
+		return
+	}
+	if err = RecordHandover(tx, applicationId, fromUserId, toUserId, admin.UserID, reason); err != nil {
+		tx.Rollback()
+		return
+	}
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return
+	}
+
+	object = dto.ReassignObject{
+		ApplicationID: applicationId,
+		FromUserID:    fromUserId,
+		ToUserID:      toUserId,
+	}
+	return
+}
+
+// holds reports whether an employee is among the current holders of an application
+func holds(holders []models.ApplicationAssignment, userId string) bool {
+	for _, holder := range holders {
+		if holder.UserID == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAssignee verifies that applications can be handed over to a user, they have to be an employee who may
+// approve applications
+func checkAssignee(userId string) error {
+	if err := checkEmployee(userId); err != nil {
+		return err
+	}
+	if !authorizationService.UserHasPermission(models.User{UserID: userId}, models.PermissionApplicationApprove) {
+		return fmt.Errorf("employee %v may not approve applications", userId)
+	}
+	return nil
+}
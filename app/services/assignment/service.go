@@ -0,0 +1,35 @@
+package assignment_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// AssignmentService defines the interface for who holds loan applications and the out-of-office delegations
+type AssignmentService interface {
+	// ReassignApplication Hands a pending application over from its current holder to another employee
+	ReassignApplication(request dto.ReassignRequest, admin models.User) (dto.ReassignResponse, dto.HandleError)
+
+	// BulkReassign Hands the pending applications of an employee over to another employee
+	BulkReassign(request dto.BulkReassignRequest, admin models.User) (dto.BulkReassignResponse, dto.HandleError)
+
+	// GetAssignmentHistory Lists who held an application when
+	GetAssignmentHistory(applicationId string) (dto.AssignmentHistoryResponse, dto.HandleError)
+
+	// CreateDelegation Forwards the work of an out-of-office employee to a delegate for a date range
+	CreateDelegation(request dto.DelegationRequest, user models.User) (dto.DelegationResponse, dto.HandleError)
+
+	// GetDelegations Lists the delegations an employee gave or received
+	GetDelegations(request dto.DelegationListRequest, user models.User) (dto.DelegationListResponse, dto.HandleError)
+
+	// RevokeDelegation Ends a delegation before its end date
+	RevokeDelegation(request dto.DelegationRevokeRequest, user models.User) (dto.DelegationResponse, dto.HandleError)
+}
+
+// assignmentService is an implementation of AssignmentService
+type assignmentService struct{}
+
+// NewAssignmentService returns a new instance of AssignmentService
+func NewAssignmentService() AssignmentService {
+	return &assignmentService{}
+}
@@ -0,0 +1,238 @@
+package authorization_service
+
+import (
+	"fmt"
+	"time"
+
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// Rule describes who may act on a loan application
+type Rule struct {
+	// ParticipantType is what the user has to take part in the application as, any participant when empty
+	ParticipantType string
+
+	// Permission is what one of the user's roles has to grant, none when empty
+	Permission string
+
+	// Oversight is a permission that lets its holders act on any application without taking part in it, none when
+	// empty. Permission is still required.
+	Oversight string
+}
+
+// DelegablePermissions are the permissions an out-of-office employee forwards to their delegate, on the
+// applications they take part in
+var DelegablePermissions = []string{models.PermissionApplicationApprove, models.PermissionApplicationOverride}
+
+// Access holds the roles of a user and the permissions they grant
+type Access struct {
+	Roles       []string
+	Permissions []string
+}
+
+// accessStore reads what application checks are decided on: the roles of users, the delegations to them and who
+// takes part in an application
+type accessStore interface {
+	// UserAccess loads the roles of a user and the permissions they grant
+	UserAccess(userId string) (Access, error)
+
+	// ActiveDelegations finds the delegations to a delegate in force on a day
+	ActiveDelegations(delegateId string, on time.Time) ([]models.EmployeeDelegation, error)
+
+	// IsParticipant reports whether a user takes part in an application, as the participant type unless it is empty
+	IsParticipant(applicationId string, userId string, participantType string) bool
+}
+
+// mysqlAccessStore reads roles, delegations and participants from the database
+type mysqlAccessStore struct{}
+
+func (mysqlAccessStore) UserAccess(userId string) (access Access, err error) {
+	role := models.UserRole{}
+	if access.Roles, err = role.FindRoleCodesByUser(userId); err != nil {
+		return
+	}
+	permission := models.Permission{}
+	access.Permissions, err = permission.FindCodesByUser(userId)
+	return
+}
+
+func (mysqlAccessStore) ActiveDelegations(delegateId string, on time.Time) ([]models.EmployeeDelegation, error) {
+	delegation := models.EmployeeDelegation{}
+	return delegation.FindActiveByDelegate(delegateId, on)
+}
+
+func (mysqlAccessStore) IsParticipant(applicationId string, userId string, participantType string) bool {
+	var participantCondition []db.WhereCondition
+	participantCondition = append(participantCondition, db.WhereCondition{
+		Key:       models.LoanApplicationParticipantColumns.UserID,
+		Condition: "=",
+		Value:     userId,
+	})
+	participantCondition = append(participantCondition, db.WhereCondition{
+		Key:       models.LoanApplicationParticipantColumns.ApplicationID,
+		Condition: "=",
+		Value:     applicationId,
+	})
+	if participantType != "" {
+		participantCondition = append(participantCondition, db.WhereCondition{
+			Key:       models.LoanApplicationParticipantColumns.ParticipantType,
+			Condition: "=",
+			Value:     participantType,
+		})
+	}
+
+	participant := models.LoanApplicationParticipant{}
+	participant, _ = participant.FindOneByCondition(participantCondition)
+	return participant.ParticipantID != ""
+}
+
+// authorizer decides the application checks against the access kept in its store
+type authorizer struct {
+	store accessStore
+}
+
+// defaultAuthorizer decides the checks of the package functions against the database
+var defaultAuthorizer = authorizer{store: mysqlAccessStore{}}
+
+// GetUserAccess loads the roles assigned to a user and the permissions they grant
+// Parameters:
+// - userId: the user to load the roles of
+// Returns:
+// - Access with the roles and the permissions, both empty for users without a role
+// - error when they could not be read
+func GetUserAccess(userId string) (Access, error) {
+	return defaultAuthorizer.store.UserAccess(userId)
+}
+
+// HasPermission reports whether any of the wanted permissions is among the ones held
+func HasPermission(held []string, wanted ...string) bool {
+	for _, w := range wanted {
+		for _, h := range held {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UserHasPermission reports whether the roles of a user grant any of the wanted permissions
+func UserHasPermission(user models.User, wanted ...string) bool {
+	return defaultAuthorizer.userHasPermission(user, wanted...)
+}
+
+// userHasPermission is UserHasPermission against the authorizer's store
+func (a authorizer) userHasPermission(user models.User, wanted ...string) bool {
+	access, err := a.store.UserAccess(user.UserID)
+	if err != nil {
+		logger.Sugar.Error("could not read permissions of user ", user.UserID, ": ", err)
+		return false
+	}
+	return HasPermission(access.Permissions, wanted...)
+}
+
+// AuthorizeApplication checks that a user may act on a loan application: their roles grant the permission of the
+// rule, and they take part in the application as the participant type of the rule or hold its oversight permission.
+// The delegate of an out-of-office employee acts on the applications of that employee with their delegable
+// permissions as well.
+// Parameters:
+// - user: the user acting on the application
+// - applicationId: the application acted on
+// - rule: Rule the user has to satisfy
+// Returns:
+// - error describing why the user may not act on the application, nil when they may
+func AuthorizeApplication(user models.User, applicationId string, rule Rule) error {
+	return defaultAuthorizer.authorizeApplication(user, applicationId, rule)
+}
+
+// authorizeApplication is AuthorizeApplication against the authorizer's store
+func (a authorizer) authorizeApplication(user models.User, applicationId string, rule Rule) error {
+	var access Access
+	if rule.Permission != "" || rule.Oversight != "" {
+		var err error
+		if access, err = a.store.UserAccess(user.UserID); err != nil {
+			return err
+		}
+	}
+
+	if rule.Permission != "" && !HasPermission(access.Permissions, rule.Permission) {
+		if a.delegatorOf(user.UserID, applicationId, rule) != "" {
+			return nil
+		}
+		return fmt.Errorf("missing permission %v", rule.Permission)
+	}
+
+	if a.store.IsParticipant(applicationId, user.UserID, rule.ParticipantType) {
+		return nil
+	}
+	if rule.Oversight != "" && HasPermission(access.Permissions, rule.Oversight) {
+		return nil
+	}
+	if a.delegatorOf(user.UserID, applicationId, rule) != "" {
+		return nil
+	}
+	return fmt.Errorf("don't have permission to this application: %v", applicationId)
+}
+
+// delegatorOf returns the out-of-office employee a delegate acts for on an application, empty when there is none.
+// The employee takes part in the application as the participant type of the rule and holds its permission, which
+// has to be delegable.
+func (a authorizer) delegatorOf(delegateId string, applicationId string, rule Rule) string {
+	if rule.Permission != "" && !HasPermission(DelegablePermissions, rule.Permission) {
+		return ""
+	}
+
+	delegations, err := a.store.ActiveDelegations(delegateId, time.Now())
+	if err != nil {
+		logger.Sugar.Error("could not read delegations to user ", delegateId, ": ", err)
+		return ""
+	}
+	for _, delegation := range delegations {
+		if !a.store.IsParticipant(applicationId, delegation.UserID, rule.ParticipantType) {
+			continue
+		}
+		if rule.Permission == "" || a.userHasPermission(models.User{UserID: delegation.UserID}, rule.Permission) {
+			return delegation.UserID
+		}
+	}
+	return ""
+}
+
+// HasDelegatedPermission reports whether an employee out of office today forwarded any of the wanted permissions to
+// the user, only DelegablePermissions are forwarded
+func HasDelegatedPermission(userId string, wanted ...string) bool {
+	return defaultAuthorizer.hasDelegatedPermission(userId, wanted...)
+}
+
+// hasDelegatedPermission is HasDelegatedPermission against the authorizer's store
+func (a authorizer) hasDelegatedPermission(userId string, wanted ...string) bool {
+	var delegable []string
+	for _, permission := range wanted {
+		if HasPermission(DelegablePermissions, permission) {
+			delegable = append(delegable, permission)
+		}
+	}
+	if len(delegable) == 0 {
+		return false
+	}
+
+	delegations, err := a.store.ActiveDelegations(userId, time.Now())
+	if err != nil {
+		logger.Sugar.Error("could not read delegations to user ", userId, ": ", err)
+		return false
+	}
+	for _, delegation := range delegations {
+		if a.userHasPermission(models.User{UserID: delegation.UserID}, delegable...) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsApplicationParticipant checks whether the user takes part in the loan application, as the given participant type
+// unless it is empty
+func IsApplicationParticipant(applicationId string, userId string, participantType string) bool {
+	return defaultAuthorizer.store.IsParticipant(applicationId, userId, participantType)
+}
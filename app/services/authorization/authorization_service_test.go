@@ -0,0 +1,154 @@
+package authorization_service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	held := []string{models.PermissionPaymentSync, models.PermissionReportView}
+
+	assert.True(t, HasPermission(held, models.PermissionReportView))
+
+	// Any of the wanted permissions is enough
+	assert.True(t, HasPermission(held, models.PermissionReconciliation, models.PermissionReportView))
+
+	assert.False(t, HasPermission(held, models.PermissionPaymentReverse))
+	assert.False(t, HasPermission(nil, models.PermissionReportView))
+
+	// Nothing wanted grants nothing, a route always names what it needs
+	assert.False(t, HasPermission(held))
+}
+
+// memoryAccessStore holds roles, delegations and participants in memory
+type memoryAccessStore struct {
+	permissions  map[string][]string
+	delegations  map[string][]models.EmployeeDelegation
+	participants map[string]string
+}
+
+func (s memoryAccessStore) UserAccess(userId string) (Access, error) {
+	return Access{Permissions: s.permissions[userId]}, nil
+}
+
+func (s memoryAccessStore) ActiveDelegations(delegateId string, on time.Time) ([]models.EmployeeDelegation, error) {
+	return s.delegations[delegateId], nil
+}
+
+func (s memoryAccessStore) IsParticipant(applicationId string, userId string, participantType string) bool {
+	taken, ok := s.participants[applicationId+"|"+userId]
+	return ok && (participantType == "" || taken == participantType)
+}
+
+func TestAuthorizeApplication(t *testing.T) {
+	store := memoryAccessStore{
+		permissions: map[string][]string{
+			"employee_id":  {models.PermissionLoanClose, models.PermissionApplicationApprove},
+			"auditor_id":   {models.PermissionApplicationReadAll},
+			"delegate_id":  {models.PermissionReportView},
+			"colleague_id": {models.PermissionReportView},
+		},
+		delegations: map[string][]models.EmployeeDelegation{
+			"delegate_id": {{UserID: "employee_id", DelegateID: "delegate_id"}},
+		},
+		participants: map[string]string{
+			"application_id|employee_id":  constants.UserTypeEmployee,
+			"application_id|customer_id":  constants.UserTypeCustomer,
+			"application_id|colleague_id": constants.UserTypeEmployee,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		userId string
+		rule   Rule
+		status int
+		error  string
+	}{
+		{
+			name:   "participant of the rule's type",
+			userId: "employee_id",
+			rule:   Rule{ParticipantType: constants.UserTypeEmployee, Permission: models.PermissionLoanClose},
+			status: http.StatusOK,
+		},
+		{
+			name:   "participant of another type",
+			userId: "customer_id",
+			rule:   Rule{ParticipantType: constants.UserTypeEmployee},
+			status: http.StatusForbidden,
+			error:  "don't have permission to this application: application_id",
+		},
+		{
+			name:   "oversight permission without taking part",
+			userId: "auditor_id",
+			rule:   Rule{Oversight: models.PermissionApplicationReadAll},
+			status: http.StatusOK,
+		},
+		{
+			name:   "neither taking part nor oversight",
+			userId: "colleague_id",
+			rule:   Rule{ParticipantType: constants.UserTypeCustomer, Oversight: models.PermissionApplicationReadAll},
+			status: http.StatusForbidden,
+			error:  "don't have permission to this application: application_id",
+		},
+		{
+			name:   "participant missing the permission",
+			userId: "colleague_id",
+			rule:   Rule{ParticipantType: constants.UserTypeEmployee, Permission: models.PermissionLoanClose},
+			status: http.StatusForbidden,
+			error:  "missing permission LOAN_CLOSE",
+		},
+		{
+			name:   "delegate acting with a delegable permission",
+			userId: "delegate_id",
+			rule:   Rule{ParticipantType: constants.UserTypeEmployee, Permission: models.PermissionApplicationApprove},
+			status: http.StatusOK,
+		},
+		{
+			name:   "delegate refused a permission that is not delegable",
+			userId: "delegate_id",
+			rule:   Rule{ParticipantType: constants.UserTypeEmployee, Permission: models.PermissionLoanClose},
+			status: http.StatusForbidden,
+			error:  "missing permission LOAN_CLOSE",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := models.User{UserID: test.userId}
+			rule := test.rule
+			authorizer := authorizer{store: store}
+
+			app := fiber.New()
+			app.Get("/v1/application/:id", func(c *fiber.Ctx) error {
+				if err := authorizer.authorizeApplication(user, c.Params("id"), rule); err != nil {
+					return c.Status(http.StatusForbidden).JSON(&fiber.Map{
+						"status": -1,
+						"error":  err.Error(),
+					})
+				}
+				return c.JSON(&fiber.Map{"status": 1})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/application/application_id", nil)
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, test.status, resp.StatusCode)
+
+			var response map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&response)
+			assert.NoError(t, err)
+			if test.error != "" {
+				assert.Equal(t, test.error, response["error"])
+			}
+		})
+	}
+}
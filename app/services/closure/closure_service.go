@@ -0,0 +1,244 @@
+package closure_service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const closureDateLayout = "2006-01-02"
+
+// outstanding is everything still owed on a loan, or owed back to its borrower
+type outstanding struct {
+	installments    []models.Repayment
+	fees            float64
+	accruedInterest float64
+	excessCredit    float64
+	pendingPayments []string
+}
+
+// CloseLoan closes a repaid loan once nothing is owed on it any more. Loans are closed when the payment paying them
+// off settles, this closes the ones that still had something open then, e.g. a payment pending at the time.
+// Parameters:
+// - request: dto.LoanClosureRequest with the application ID
+// - user: models.User representing the employee assigned to the loan
+// Returns:
+// - dto.LoanClosureResponse with the closure date
+// - dto.HandleError with any error that occurred during the process
+func (s *closureService) CloseLoan(request dto.LoanClosureRequest, user models.User) (
+	response dto.LoanClosureResponse, handle dto.HandleError) {
+	// Only the employee allocated to the loan, with the permission to close loans, closes it
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionLoanClose,
+	}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	// Lock the loan so that no payment, reversal or refund changes it while it is verified
+	application := models.LoanApplication{}
+	application, err := application.FindByPrimaryKeyForUpdate(tx, request.ApplicationID)
+	if err != nil || application.ApplicationID == "" {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	if application.Status == models.LoanApplicationStatusClosed {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("loan was already closed on %v", application.ClosedDate.Time.Format(closureDateLayout))
+		return
+	}
+
+	owed, err := loadOutstanding(tx, application)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if blockers := closureBlockers(application, owed); len(blockers) > 0 {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("loan cannot be closed: %v", strings.Join(blockers, "; "))
+		return
+	}
+
+	if err = closeApplication(tx, &application, user.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Loan closed successfully"
+	response.Data.ApplicationId = application.ApplicationID
+	response.Data.Status = application.Status
+	response.Data.ClosedDate = application.ClosedDate.Time.Format(closureDateLayout)
+	response.Data.ClosedBy = application.ClosedBy.String
+	return
+}
+
+// CloseIfSettled closes a repaid loan when nothing is owed on it any more. It is called by the settlement that
+// pays a loan off and by refunds of excess credit, inside their transaction and with the loan locked.
+// Parameters:
+// - tx: the caller's transaction
+// - application: the locked loan, updated when it is closed
+// - closedBy: the employee closing the loan, empty when it is closed by a payment
+// Returns:
+// - bool reporting whether the loan was closed
+// - error when the loan could not be verified or saved
+func (s *closureService) CloseIfSettled(tx *gorm.DB, application *models.LoanApplication, closedBy string) (bool, error) {
+	if application.Status != models.LoanApplicationStatusPaid {
+		return false, nil
+	}
+
+	owed, err := loadOutstanding(tx, *application)
+	if err != nil {
+		return false, err
+	}
+
+	if len(closureBlockers(*application, owed)) > 0 {
+		return false, nil
+	}
+
+	if err = closeApplication(tx, application, closedBy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// loadOutstanding reads what is still owed on a loan inside a transaction, so that changes the caller has not
+// committed yet are taken into account
+func loadOutstanding(tx *gorm.DB, application models.LoanApplication) (owed outstanding, err error) {
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+
+	repayment := models.Repayment{}
+	if owed.installments, err = repayment.FindAllByConditionForUpdate(tx, repaymentCondition); err != nil {
+		return
+	}
+
+	journalLine := models.JournalLine{}
+	if owed.fees, err = journalLine.ApplicationBalance(tx, models.LedgerAccountFeesReceivable,
+		application.ApplicationID); err != nil {
+		return
+	}
+	if owed.accruedInterest, err = journalLine.ApplicationBalance(tx, models.LedgerAccountInterestReceivable,
+		application.ApplicationID); err != nil {
+		return
+	}
+	owed.excessCredit = application.ExcessCredit
+
+	// A pending payment would settle on a closed loan, it has to be settled or failed first
+	var paymentCondition []db.WhereCondition
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.Status,
+		Condition: "=",
+		Value:     models.PaymentStatusPending,
+	})
+
+	payment := models.Payment{}
+	payments, err := payment.FindAllByConditionTx(tx, paymentCondition)
+	if err != nil {
+		return
+	}
+	for _, payment := range payments {
+		owed.pendingPayments = append(owed.pendingPayments, payment.PaymentID)
+	}
+	return
+}
+
+// closureBlockers lists why a loan cannot be closed yet, nothing when it can
+// Parameters:
+// - application: the loan to close
+// - owed: what is still owed on it
+// Returns:
+// - []string with a reason for every installment, charge, credit or payment still open
+func closureBlockers(application models.LoanApplication, owed outstanding) (blockers []string) {
+	currency := application.CurrencyCode
+	if application.Status != models.LoanApplicationStatusPaid {
+		return []string{fmt.Sprintf("loan is %v, only a fully repaid loan can be closed", application.Status)}
+	}
+
+	for _, installment := range owed.installments {
+		if due := roundCents(installment.AmountDue - installment.AmountPaid); due > 0 {
+			blockers = append(blockers, fmt.Sprintf("installment %d has %v outstanding",
+				installment.InstallmentNumber, display(due, currency)))
+		} else if installment.Status != models.LoanApplicationStatusPaid {
+			blockers = append(blockers, fmt.Sprintf("installment %d is %v", installment.InstallmentNumber,
+				installment.Status))
+		}
+	}
+	if fees := roundCents(owed.fees); fees > 0 {
+		blockers = append(blockers, fmt.Sprintf("charges of %v are outstanding", display(fees, currency)))
+	}
+	if interest := roundCents(owed.accruedInterest); interest > 0 {
+		blockers = append(blockers, fmt.Sprintf("accrued interest of %v is outstanding", display(interest, currency)))
+	}
+	if excess := roundCents(owed.excessCredit); excess > 0 {
+		blockers = append(blockers, fmt.Sprintf("excess credit of %v has to be refunded", display(excess, currency)))
+	}
+	for _, paymentId := range owed.pendingPayments {
+		blockers = append(blockers, fmt.Sprintf("payment %v is still pending", paymentId))
+	}
+	return
+}
+
+// closeApplication records the closure of a loan
+func closeApplication(tx *gorm.DB, application *models.LoanApplication, closedBy string) error {
+	now := time.Now()
+	application.Status = models.LoanApplicationStatusClosed
+	application.ClosedDate = null.TimeFrom(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	application.ClosedBy = null.String{}
+	if closedBy != "" {
+		application.ClosedBy = null.StringFrom(closedBy)
+	}
+	return tx.Omit(clause.Associations).Save(application).Error
+}
+
+// roundCents rounds an amount to cents, so that floating point leftovers do not count as owed
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// display formats an amount in the currency of the loan
+func display(amount float64, currency string) string {
+	return money.NewFromFloat(amount, currency).Display()
+}
@@ -0,0 +1,101 @@
+package closure_service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func paidApplication() models.LoanApplication {
+	return models.LoanApplication{
+		ApplicationID:  "app_id",
+		CurrencyCode:   "INR",
+		Status:         models.LoanApplicationStatusPaid,
+		ApprovedAmount: null.FloatFrom(1000),
+		ApprovedDate:   null.TimeFrom(time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)),
+	}
+}
+
+func paidInstallments() []models.Repayment {
+	return []models.Repayment{
+		{InstallmentNumber: 1, AmountDue: 510.0, AmountPaid: 510.0, Status: models.LoanApplicationStatusPaid},
+		{InstallmentNumber: 2, AmountDue: 505.1, AmountPaid: 505.1000001, Status: models.LoanApplicationStatusPaid},
+	}
+}
+
+func TestClosureBlockers_NothingOwed(t *testing.T) {
+	blockers := closureBlockers(paidApplication(), outstanding{
+		installments:    paidInstallments(),
+		accruedInterest: 0.001,
+	})
+
+	assert.Empty(t, blockers)
+}
+
+func TestClosureBlockers_ListsEverythingOpen(t *testing.T) {
+	installments := paidInstallments()
+	installments[1].AmountPaid = 400
+	installments[1].Status = models.LoanApplicationStatusPending
+
+	blockers := closureBlockers(paidApplication(), outstanding{
+		installments:    installments,
+		fees:            25,
+		accruedInterest: 3.5,
+		excessCredit:    10,
+		pendingPayments: []string{"p1"},
+	})
+
+	assert.Equal(t, []string{
+		"installment 2 has ₹105.10 outstanding",
+		"charges of ₹25.00 are outstanding",
+		"accrued interest of ₹3.50 is outstanding",
+		"excess credit of ₹10.00 has to be refunded",
+		"payment p1 is still pending",
+	}, blockers)
+}
+
+func TestClosureBlockers_OnlyRepaidLoans(t *testing.T) {
+	application := paidApplication()
+	application.Status = models.LoanApplicationStatusApproved
+
+	assert.Equal(t, []string{"loan is APPROVED, only a fully repaid loan can be closed"},
+		closureBlockers(application, outstanding{}))
+}
+
+func TestBuildClosureLetter(t *testing.T) {
+	application := paidApplication()
+	application.Status = models.LoanApplicationStatusClosed
+	application.ClosedDate = null.TimeFrom(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+
+	letter := buildClosureLetter(application, paidInstallments(), time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "NOC/2024/APP_ID", letter.ReferenceNumber)
+	assert.Equal(t, "₹1,000.00", letter.LoanAmount)
+	assert.Equal(t, "₹1,015.10", letter.TotalRepaid)
+	assert.Equal(t, "2024-01-05", letter.DisbursedDate)
+	assert.Equal(t, "2024-03-02", letter.ClosedDate)
+	assert.Equal(t, "2024-03-04", letter.IssuedDate)
+}
+
+func TestWriteClosureLetterPDF(t *testing.T) {
+	application := paidApplication()
+	application.ClosedDate = null.TimeFrom(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	letter := buildClosureLetter(application, paidInstallments(), time.Now())
+	letter.Tenant = "ASPIRE"
+	letter.CustomerName = "Jane Doe"
+
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteClosureLetterPDF(&buffer, letter))
+
+	output := buffer.String()
+	assert.True(t, strings.HasPrefix(output, "%PDF-1.4"))
+	assert.True(t, strings.Contains(output, "(NO OBJECTION CERTIFICATE)"))
+	assert.True(t, strings.Contains(output, "(Dear Jane Doe,)"))
+	assert.True(t, strings.Contains(output, "(For ASPIRE)"))
+	assert.True(t, strings.Contains(output, "Rs.1,000.00"))
+}
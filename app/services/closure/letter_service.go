@@ -0,0 +1,180 @@
+package closure_service
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	"github.com/nishanthrk/aspire-lms/app/common/pdf"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+)
+
+// GetClosureLetter builds the no-objection letter confirming a loan was repaid in full and closed, for its customer
+// or assigned employee
+// Parameters:
+// - request: dto.ClosureLetterRequest with the application ID
+// - user: models.User representing the customer or employee of the application
+// Returns:
+// - dto.ClosureLetterResponse with the contents of the letter
+// - dto.HandleError with any error that occurred during the process
+func (s *closureService) GetClosureLetter(request dto.ClosureLetterRequest, user models.User) (
+	response dto.ClosureLetterResponse, handle dto.HandleError) {
+	application := models.LoanApplication{}
+	application, _ = application.FindByPrimaryKey(request.ApplicationID)
+	if application.ApplicationID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	var participantCondition []db.WhereCondition
+	participantCondition = append(participantCondition, db.WhereCondition{
+		Key:       models.LoanApplicationParticipantColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+
+	participant := models.LoanApplicationParticipant{}
+	participants, err := participant.FindAllByCondition(participantCondition)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	customerId := ""
+	for _, participant := range participants {
+		if participant.ParticipantType == constants.UserTypeCustomer {
+			customerId = participant.UserID
+		}
+	}
+
+	// Participants read the documents of their applications, auditors of any
+	rule := authorizationService.Rule{Oversight: models.PermissionApplicationReadAll}
+	if err := authorizationService.AuthorizeApplication(user, application.ApplicationID, rule); err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	if application.Status != models.LoanApplicationStatusClosed {
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("loan is %v, a closure letter is only issued once it is closed", application.Status)
+		return
+	}
+
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+
+	repayment := models.Repayment{}
+	repayments, err := repayment.FindAllByCondition(repaymentCondition)
+	if err != nil {
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	customer := models.User{}
+	customer, _ = customer.FindByPrimaryKey(customerId)
+
+	response.Status = 1
+	response.Data = buildClosureLetter(application, repayments, time.Now())
+	response.Data.Tenant = s.tenant
+	response.Data.CustomerName = customer.UserName
+	return
+}
+
+// buildClosureLetter fills the letter of a closed loan
+// Parameters:
+// - application: the closed loan
+// - repayments: its installments
+// - issued: when the letter is issued
+// Returns:
+// - dto.ClosureLetter without the tenant and customer
+func buildClosureLetter(application models.LoanApplication, repayments []models.Repayment, issued time.Time) (
+	letter dto.ClosureLetter) {
+	var repaid float64
+	for _, repayment := range repayments {
+		repaid += repayment.AmountPaid
+	}
+
+	letter.ReferenceNumber = fmt.Sprintf("NOC/%v/%v", application.ClosedDate.Time.Format("2006"),
+		strings.ToUpper(application.ApplicationID))
+	letter.ApplicationId = application.ApplicationID
+	letter.LoanAmount = display(application.ApprovedAmount.Float64, application.CurrencyCode)
+	letter.TotalRepaid = display(roundCents(repaid), application.CurrencyCode)
+	if application.ApprovedDate.Valid {
+		letter.DisbursedDate = application.ApprovedDate.Time.Format(closureDateLayout)
+	}
+	letter.ClosedDate = application.ClosedDate.Time.Format(closureDateLayout)
+	letter.IssuedDate = issued.Format(closureDateLayout)
+	return
+}
+
+// letterBrandColor fills the header band carrying the tenant name
+var letterBrandColor = pdf.Color{R: 0.07, G: 0.22, B: 0.42}
+
+// WriteClosureLetterPDF writes the no-objection letter of a closed loan as an A4 PDF in the tenant's name
+// Parameters:
+// - writer: where the letter is written to
+// - letter: the contents of the letter
+// Returns:
+// - error when the letter could not be written
+func WriteClosureLetterPDF(writer io.Writer, letter dto.ClosureLetter) error {
+	const left, right = 60.0, pdf.PageWidth - 60
+	document := pdf.NewDocument(fmt.Sprintf("No Objection Certificate %v", letter.ApplicationId), letter.Tenant)
+
+	page := document.AddPage()
+	page.FillRect(0, 0, pdf.PageWidth, 72, letterBrandColor)
+	page.Text(left, 45, pdf.HelveticaBold, 22, pdf.White, letter.Tenant)
+
+	page.Text(left, 112, pdf.Helvetica, 10, pdf.Black, fmt.Sprintf("Ref: %v", letter.ReferenceNumber))
+	page.TextRight(right, 112, pdf.Helvetica, 10, pdf.Black, fmt.Sprintf("Date: %v", letter.IssuedDate))
+	page.Text(left, 146, pdf.Helvetica, 10, pdf.Black, "To")
+	page.Text(left, 160, pdf.HelveticaBold, 10, pdf.Black, letter.CustomerName)
+
+	title := "NO OBJECTION CERTIFICATE"
+	page.Text((pdf.PageWidth-pdf.TextWidth(pdf.HelveticaBold, 14, title))/2, 206, pdf.HelveticaBold, 14, pdf.Black, title)
+	page.Text(left, 240, pdf.HelveticaBold, 10, pdf.Black,
+		fmt.Sprintf("Subject: Closure of loan account %v", letter.ApplicationId))
+
+	paragraphs := []string{
+		fmt.Sprintf("Dear %v,", letter.CustomerName),
+		fmt.Sprintf("This is to certify that the loan of %v disbursed to you on %v under loan account %v has been "+
+			"repaid in full, with total repayments of %v, and that the loan account was closed on %v.",
+			letter.LoanAmount, letter.DisbursedDate, letter.ApplicationId, letter.TotalRepaid, letter.ClosedDate),
+		"No principal, interest, charges or any other amount is outstanding against this loan account as on the " +
+			"date of this letter.",
+		fmt.Sprintf("%v has no objection to the release of any security, charge or lien created in its favour in "+
+			"respect of this loan.", letter.Tenant),
+	}
+
+	y := 272.0
+	for _, paragraph := range paragraphs {
+		for _, line := range pdf.Wrap(pdf.Helvetica, 10.5, paragraph, right-left) {
+			page.Text(left, y, pdf.Helvetica, 10.5, pdf.Black, line)
+			y += 15
+		}
+		y += 9
+	}
+
+	y += 20
+	page.Text(left, y, pdf.Helvetica, 10.5, pdf.Black, "Yours sincerely,")
+	page.Text(left, y+16, pdf.HelveticaBold, 10.5, pdf.Black, fmt.Sprintf("For %v", letter.Tenant))
+	page.Text(left, y+50, pdf.Helvetica, 10.5, pdf.Black, "Authorised Signatory")
+
+	page.Line(left, pdf.PageHeight-50, right, pdf.PageHeight-50, 0.5, pdf.Gray)
+	page.Text(left, pdf.PageHeight-36, pdf.Helvetica, 8, pdf.Gray,
+		"This is a system generated letter and does not require a signature.")
+
+	return document.Write(writer)
+}
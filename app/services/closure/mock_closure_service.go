@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/closure/service.go
+
+// Package closure_service is a generated GoMock package.
+package closure_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+	gorm "gorm.io/gorm"
+)
+
+// MockClosureService is a mock of ClosureService interface.
+type MockClosureService struct {
+	ctrl     *gomock.Controller
+	recorder *MockClosureServiceMockRecorder
+}
+
+// MockClosureServiceMockRecorder is the mock recorder for MockClosureService.
+type MockClosureServiceMockRecorder struct {
+	mock *MockClosureService
+}
+
+// NewMockClosureService creates a new mock instance.
+func NewMockClosureService(ctrl *gomock.Controller) *MockClosureService {
+	mock := &MockClosureService{ctrl: ctrl}
+	mock.recorder = &MockClosureServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClosureService) EXPECT() *MockClosureServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseIfSettled mocks base method.
+func (m *MockClosureService) CloseIfSettled(tx *gorm.DB, application *models.LoanApplication, closedBy string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseIfSettled", tx, application, closedBy)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseIfSettled indicates an expected call of CloseIfSettled.
+func (mr *MockClosureServiceMockRecorder) CloseIfSettled(tx, application, closedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseIfSettled", reflect.TypeOf((*MockClosureService)(nil).CloseIfSettled), tx, application, closedBy)
+}
+
+// CloseLoan mocks base method.
+func (m *MockClosureService) CloseLoan(request dto.LoanClosureRequest, user models.User) (dto.LoanClosureResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseLoan", request, user)
+	ret0, _ := ret[0].(dto.LoanClosureResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// CloseLoan indicates an expected call of CloseLoan.
+func (mr *MockClosureServiceMockRecorder) CloseLoan(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseLoan", reflect.TypeOf((*MockClosureService)(nil).CloseLoan), request, user)
+}
+
+// GetClosureLetter mocks base method.
+func (m *MockClosureService) GetClosureLetter(request dto.ClosureLetterRequest, user models.User) (dto.ClosureLetterResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosureLetter", request, user)
+	ret0, _ := ret[0].(dto.ClosureLetterResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetClosureLetter indicates an expected call of GetClosureLetter.
+func (mr *MockClosureServiceMockRecorder) GetClosureLetter(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosureLetter", reflect.TypeOf((*MockClosureService)(nil).GetClosureLetter), request, user)
+}
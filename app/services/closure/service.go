@@ -0,0 +1,31 @@
+package closure_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+)
+
+// ClosureService defines the interface for closing repaid loans
+type ClosureService interface {
+	// CloseLoan Verifies nothing is owed on a repaid loan any more and closes it
+	CloseLoan(request dto.LoanClosureRequest, user models.User) (dto.LoanClosureResponse, dto.HandleError)
+
+	// CloseIfSettled Closes a repaid loan inside the caller's transaction when nothing is owed on it any more
+	CloseIfSettled(tx *gorm.DB, application *models.LoanApplication, closedBy string) (bool, error)
+
+	// GetClosureLetter Builds the no-objection letter of a closed loan
+	GetClosureLetter(request dto.ClosureLetterRequest, user models.User) (dto.ClosureLetterResponse, dto.HandleError)
+}
+
+// closureService is an implementation of ClosureService
+type closureService struct {
+	tenant string
+}
+
+// NewClosureService returns a new instance of ClosureService, closure letters are issued in the tenant's name
+func NewClosureService(tenant string) ClosureService {
+	return &closureService{
+		tenant: tenant,
+	}
+}
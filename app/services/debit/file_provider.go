@@ -0,0 +1,218 @@
+package debit_service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+const (
+	mandatesDirectory  = "mandates"
+	requestsDirectory  = "requests"
+	resultsDirectory   = "results"
+	processedDirectory = "processed"
+)
+
+// FileDebitProvider is a DebitProvider exchanging JSON files in a directory, the way batch based debit
+// schemes exchange files with the bank. Mandates and collection requests are written to the mandates and
+// requests folders; outcomes are read from the results folder, one file per collection named after its
+// reference, and moved to the processed folder once acknowledged.
+type FileDebitProvider struct {
+	directory string
+	secret    []byte
+}
+
+// fileMandate is the document written for a registered mandate
+type fileMandate struct {
+	Reference string                  `json:"reference"`
+	Status    string                  `json:"status"`
+	Mandate   dto.MandateRegistration `json:"mandate"`
+}
+
+// fileCollection is the document written for a collection request
+type fileCollection struct {
+	Reference string                     `json:"reference"`
+	Request   dto.DebitCollectionRequest `json:"request"`
+}
+
+// NewFileDebitProvider returns a file based provider working in the given directory and signing callbacks
+// with the given secret
+func NewFileDebitProvider(directory string, webhookSecret string) *FileDebitProvider {
+	return &FileDebitProvider{
+		directory: directory,
+		secret:    []byte(webhookSecret),
+	}
+}
+
+// Name returns the provider identifier
+func (p *FileDebitProvider) Name() string {
+	return ProviderFile
+}
+
+// RegisterMandate writes the mandate to the mandates folder and returns a generated reference
+func (p *FileDebitProvider) RegisterMandate(request dto.MandateRegistration) (string, error) {
+	reference := fmt.Sprintf("mdt_%s", uuid.New().String())
+	err := p.writeDocument(mandatesDirectory, reference, fileMandate{
+		Reference: reference,
+		Status:    models.MandateStatusActive,
+		Mandate:   request,
+	})
+	if err != nil {
+		return "", err
+	}
+	return reference, nil
+}
+
+// CancelMandate rewrites the mandate document as cancelled
+func (p *FileDebitProvider) CancelMandate(reference string) error {
+	var mandate fileMandate
+	if err := p.readDocument(mandatesDirectory, reference, &mandate); err != nil {
+		return fmt.Errorf("mandate %v not found at provider", reference)
+	}
+	mandate.Status = models.MandateStatusCancelled
+	return p.writeDocument(mandatesDirectory, reference, mandate)
+}
+
+// CollectDebit writes the collection request to the requests folder and returns a generated reference
+func (p *FileDebitProvider) CollectDebit(request dto.DebitCollectionRequest) (string, error) {
+	if request.Amount <= 0 {
+		return "", fmt.Errorf("debit amount must be positive")
+	}
+
+	var mandate fileMandate
+	if err := p.readDocument(mandatesDirectory, request.MandateReference, &mandate); err != nil {
+		return "", fmt.Errorf("mandate %v not found at provider", request.MandateReference)
+	}
+	if mandate.Status != models.MandateStatusActive {
+		return "", fmt.Errorf("mandate %v is %v at provider", request.MandateReference, mandate.Status)
+	}
+
+	reference := fmt.Sprintf("dd_%s", uuid.New().String())
+	err := p.writeDocument(requestsDirectory, reference, fileCollection{
+		Reference: reference,
+		Request:   request,
+	})
+	if err != nil {
+		return "", err
+	}
+	return reference, nil
+}
+
+// FetchResults reads every outcome waiting in the results folder, oldest file name first. The file name
+// is the collection reference and takes precedence over any reference inside the document.
+func (p *FileDebitProvider) FetchResults() (results []dto.DebitCallbackRequest, err error) {
+	files, err := filepath.Glob(filepath.Join(p.directory, resultsDirectory, "*.json"))
+	if err != nil {
+		return
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		reference := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		var result dto.DebitCallbackRequest
+		if err = p.readDocument(resultsDirectory, reference, &result); err != nil {
+			err = fmt.Errorf("invalid debit result %v: %v", filepath.Base(file), err)
+			return
+		}
+		result.Reference = reference
+		results = append(results, result)
+	}
+	return
+}
+
+// AcknowledgeResult moves the outcome file to the processed folder
+func (p *FileDebitProvider) AcknowledgeResult(reference string) error {
+	source, err := p.documentPath(resultsDirectory, reference)
+	if err != nil {
+		return err
+	}
+	target, err := p.documentPath(processedDirectory, reference)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(source, target)
+}
+
+// PublishResult drops an outcome into the results folder, as the bank would once the debit is processed
+func (p *FileDebitProvider) PublishResult(result dto.DebitCallbackRequest) error {
+	return p.writeDocument(resultsDirectory, result.Reference, result)
+}
+
+// VerifySignature checks the hex encoded HMAC-SHA256 of the payload, an empty secret never verifies
+func (p *FileDebitProvider) VerifySignature(payload []byte, signature string) bool {
+	if len(p.secret) == 0 || signature == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, p.sign(payload))
+}
+
+// Sign returns the signature the provider would send for the payload
+func (p *FileDebitProvider) Sign(payload []byte) string {
+	return hex.EncodeToString(p.sign(payload))
+}
+
+func (p *FileDebitProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// documentPath returns where the document for a reference lives, rejecting references that would escape
+// the provider directory
+func (p *FileDebitProvider) documentPath(folder string, reference string) (string, error) {
+	if reference == "" || reference != filepath.Base(reference) || strings.HasPrefix(reference, ".") {
+		return "", fmt.Errorf("invalid provider reference: %q", reference)
+	}
+	return filepath.Join(p.directory, folder, reference+".json"), nil
+}
+
+func (p *FileDebitProvider) writeDocument(folder string, reference string, document interface{}) error {
+	path, err := p.documentPath(folder, reference)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a reader never sees a half written document
+	temporary := path + ".tmp"
+	if err = os.WriteFile(temporary, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temporary, path)
+}
+
+func (p *FileDebitProvider) readDocument(folder string, reference string, document interface{}) error {
+	path, err := p.documentPath(folder, reference)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, document)
+}
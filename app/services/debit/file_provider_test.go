@@ -0,0 +1,89 @@
+package debit_service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDebitProvider_Collection(t *testing.T) {
+	directory := t.TempDir()
+	provider := NewFileDebitProvider(directory, "secret")
+
+	mandateReference, err := provider.RegisterMandate(dto.MandateRegistration{MandateID: "mandate_id", MaxAmount: 500})
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(directory, mandatesDirectory, mandateReference+".json"))
+
+	reference, err := provider.CollectDebit(dto.DebitCollectionRequest{
+		CollectionID:     "collection_id",
+		MandateReference: mandateReference,
+		CurrencyCode:     "INR",
+		Amount:           250,
+	})
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(directory, requestsDirectory, reference+".json"))
+
+	assert.NoError(t, provider.PublishResult(dto.DebitCallbackRequest{
+		Reference:   reference,
+		Status:      models.PaymentStatusFailed,
+		FailureCode: FailureCodeInsufficientFunds,
+	}))
+
+	results, err := provider.FetchResults()
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.DebitCallbackRequest{{
+		Reference:   reference,
+		Status:      models.PaymentStatusFailed,
+		FailureCode: FailureCodeInsufficientFunds,
+	}}, results)
+
+	assert.NoError(t, provider.AcknowledgeResult(reference))
+	assert.FileExists(t, filepath.Join(directory, processedDirectory, reference+".json"))
+
+	results, err = provider.FetchResults()
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestFileDebitProvider_CancelledMandate(t *testing.T) {
+	provider := NewFileDebitProvider(t.TempDir(), "secret")
+
+	mandateReference, err := provider.RegisterMandate(dto.MandateRegistration{MandateID: "mandate_id"})
+	assert.NoError(t, err)
+	assert.NoError(t, provider.CancelMandate(mandateReference))
+
+	_, err = provider.CollectDebit(dto.DebitCollectionRequest{MandateReference: mandateReference, Amount: 10})
+	assert.EqualError(t, err, "mandate "+mandateReference+" is CANCELLED at provider")
+
+	_, err = provider.CollectDebit(dto.DebitCollectionRequest{MandateReference: "mdt_unknown", Amount: 10})
+	assert.EqualError(t, err, "mandate mdt_unknown not found at provider")
+}
+
+func TestFileDebitProvider_ResultFileNameIsReference(t *testing.T) {
+	directory := t.TempDir()
+	provider := NewFileDebitProvider(directory, "secret")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(directory, resultsDirectory), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, resultsDirectory, "dd_1.json"),
+		[]byte(`{"reference":"dd_other","status":"SETTLED"}`), 0o644))
+
+	results, err := provider.FetchResults()
+	assert.NoError(t, err)
+	assert.Equal(t, "dd_1", results[0].Reference)
+
+	assert.Error(t, provider.AcknowledgeResult("../dd_1"))
+}
+
+func TestFileDebitProvider_VerifySignature(t *testing.T) {
+	provider := NewFileDebitProvider(t.TempDir(), "secret")
+	payload := []byte(`{"reference":"dd_1","status":"SETTLED"}`)
+
+	assert.True(t, provider.VerifySignature(payload, provider.Sign(payload)))
+	assert.False(t, provider.VerifySignature(payload, "00"))
+	assert.False(t, provider.VerifySignature(payload, "not-hex"))
+	assert.False(t, NewFileDebitProvider(t.TempDir(), "").VerifySignature(payload, provider.Sign(payload)))
+}
@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/debit/service.go
+
+// Package debit_service is a generated GoMock package.
+package debit_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+// MockDebitProvider is a mock of DebitProvider interface.
+type MockDebitProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockDebitProviderMockRecorder
+}
+
+// MockDebitProviderMockRecorder is the mock recorder for MockDebitProvider.
+type MockDebitProviderMockRecorder struct {
+	mock *MockDebitProvider
+}
+
+// NewMockDebitProvider creates a new mock instance.
+func NewMockDebitProvider(ctrl *gomock.Controller) *MockDebitProvider {
+	mock := &MockDebitProvider{ctrl: ctrl}
+	mock.recorder = &MockDebitProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDebitProvider) EXPECT() *MockDebitProviderMockRecorder {
+	return m.recorder
+}
+
+// AcknowledgeResult mocks base method.
+func (m *MockDebitProvider) AcknowledgeResult(reference string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeResult", reference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcknowledgeResult indicates an expected call of AcknowledgeResult.
+func (mr *MockDebitProviderMockRecorder) AcknowledgeResult(reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeResult", reflect.TypeOf((*MockDebitProvider)(nil).AcknowledgeResult), reference)
+}
+
+// CancelMandate mocks base method.
+func (m *MockDebitProvider) CancelMandate(reference string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelMandate", reference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelMandate indicates an expected call of CancelMandate.
+func (mr *MockDebitProviderMockRecorder) CancelMandate(reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelMandate", reflect.TypeOf((*MockDebitProvider)(nil).CancelMandate), reference)
+}
+
+// CollectDebit mocks base method.
+func (m *MockDebitProvider) CollectDebit(request dto.DebitCollectionRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectDebit", request)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectDebit indicates an expected call of CollectDebit.
+func (mr *MockDebitProviderMockRecorder) CollectDebit(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectDebit", reflect.TypeOf((*MockDebitProvider)(nil).CollectDebit), request)
+}
+
+// FetchResults mocks base method.
+func (m *MockDebitProvider) FetchResults() ([]dto.DebitCallbackRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchResults")
+	ret0, _ := ret[0].([]dto.DebitCallbackRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchResults indicates an expected call of FetchResults.
+func (mr *MockDebitProviderMockRecorder) FetchResults() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchResults", reflect.TypeOf((*MockDebitProvider)(nil).FetchResults))
+}
+
+// Name mocks base method.
+func (m *MockDebitProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockDebitProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockDebitProvider)(nil).Name))
+}
+
+// RegisterMandate mocks base method.
+func (m *MockDebitProvider) RegisterMandate(request dto.MandateRegistration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterMandate", request)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterMandate indicates an expected call of RegisterMandate.
+func (mr *MockDebitProviderMockRecorder) RegisterMandate(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMandate", reflect.TypeOf((*MockDebitProvider)(nil).RegisterMandate), request)
+}
+
+// VerifySignature mocks base method.
+func (m *MockDebitProvider) VerifySignature(payload []byte, signature string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySignature", payload, signature)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VerifySignature indicates an expected call of VerifySignature.
+func (mr *MockDebitProviderMockRecorder) VerifySignature(payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySignature", reflect.TypeOf((*MockDebitProvider)(nil).VerifySignature), payload, signature)
+}
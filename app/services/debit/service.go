@@ -0,0 +1,59 @@
+package debit_service
+
+import (
+	"strings"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+const (
+	// ProviderFile is the file based stand-in provider used for local development and tests
+	ProviderFile = "FILE"
+
+	// SignatureHeader is the callback header carrying the hex encoded HMAC-SHA256 of the raw request body
+	SignatureHeader = "X-Debit-Signature"
+)
+
+// Failure codes reported by debit providers for a failed collection
+const (
+	FailureCodeInsufficientFunds = "INSUFFICIENT_FUNDS"
+	FailureCodeMaxAmountExceeded = "MAX_AMOUNT_EXCEEDED"
+	FailureCodeMandateRevoked    = "MANDATE_REVOKED"
+	FailureCodeAccountClosed     = "ACCOUNT_CLOSED"
+	FailureCodeInvalidAccount    = "INVALID_ACCOUNT"
+	FailureCodeProviderError     = "PROVIDER_ERROR"
+)
+
+// DebitProvider defines the interface every direct debit scheme integration implements
+type DebitProvider interface {
+	// Name returns the identifier stored against mandates registered with the provider
+	Name() string
+
+	// RegisterMandate registers a debit mandate with the provider and returns the provider reference
+	RegisterMandate(request dto.MandateRegistration) (string, error)
+
+	// CancelMandate revokes a registered mandate so no further debits are accepted against it
+	CancelMandate(reference string) error
+
+	// CollectDebit submits a collection request against a mandate and returns the provider reference
+	CollectDebit(request dto.DebitCollectionRequest) (string, error)
+
+	// FetchResults returns collection outcomes the provider has published but not yet had acknowledged
+	FetchResults() ([]dto.DebitCallbackRequest, error)
+
+	// AcknowledgeResult marks a published outcome as processed so it is not returned again
+	AcknowledgeResult(reference string) error
+
+	// VerifySignature checks that a callback payload was signed by the provider
+	VerifySignature(payload []byte, signature string) bool
+}
+
+// NewDebitProvider returns the provider configured by name. Real schemes are selected here as they are
+// integrated; until then every name resolves to the file based provider.
+func NewDebitProvider(name string, directory string, webhookSecret string) DebitProvider {
+	switch strings.ToUpper(name) {
+	case ProviderFile, "":
+		return NewFileDebitProvider(directory, webhookSecret)
+	}
+	return NewFileDebitProvider(directory, webhookSecret)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/gateway/service.go
+
+// Package gateway_service is a generated GoMock package.
+package gateway_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+// MockPaymentGateway is a mock of PaymentGateway interface.
+type MockPaymentGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentGatewayMockRecorder
+}
+
+// MockPaymentGatewayMockRecorder is the mock recorder for MockPaymentGateway.
+type MockPaymentGatewayMockRecorder struct {
+	mock *MockPaymentGateway
+}
+
+// NewMockPaymentGateway creates a new mock instance.
+func NewMockPaymentGateway(ctrl *gomock.Controller) *MockPaymentGateway {
+	mock := &MockPaymentGateway{ctrl: ctrl}
+	mock.recorder = &MockPaymentGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentGateway) EXPECT() *MockPaymentGatewayMockRecorder {
+	return m.recorder
+}
+
+// CreatePaymentIntent mocks base method.
+func (m *MockPaymentGateway) CreatePaymentIntent(request dto.PaymentIntentRequest) (dto.PaymentIntent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentIntent", request)
+	ret0, _ := ret[0].(dto.PaymentIntent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentIntent indicates an expected call of CreatePaymentIntent.
+func (mr *MockPaymentGatewayMockRecorder) CreatePaymentIntent(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentIntent", reflect.TypeOf((*MockPaymentGateway)(nil).CreatePaymentIntent), request)
+}
+
+// FetchStatus mocks base method.
+func (m *MockPaymentGateway) FetchStatus(reference string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchStatus", reference)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchStatus indicates an expected call of FetchStatus.
+func (mr *MockPaymentGatewayMockRecorder) FetchStatus(reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchStatus", reflect.TypeOf((*MockPaymentGateway)(nil).FetchStatus), reference)
+}
+
+// Name mocks base method.
+func (m *MockPaymentGateway) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockPaymentGatewayMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockPaymentGateway)(nil).Name))
+}
+
+// VerifySignature mocks base method.
+func (m *MockPaymentGateway) VerifySignature(payload []byte, signature string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySignature", payload, signature)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VerifySignature indicates an expected call of VerifySignature.
+func (mr *MockPaymentGatewayMockRecorder) VerifySignature(payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySignature", reflect.TypeOf((*MockPaymentGateway)(nil).VerifySignature), payload, signature)
+}
@@ -0,0 +1,44 @@
+package gateway_service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+const (
+	// GatewayFake is the in-process stand-in gateway used for local development and tests
+	GatewayFake = "FAKE"
+
+	// SignatureHeader is the webhook header carrying the hex encoded HMAC-SHA256 of the raw request body
+	SignatureHeader = "X-Gateway-Signature"
+)
+
+// PaymentGateway defines the interface every payment gateway integration implements
+type PaymentGateway interface {
+	// Name returns the identifier stored against payments created through the gateway
+	Name() string
+
+	// CreatePaymentIntent registers a payment with the gateway and returns the gateway reference
+	CreatePaymentIntent(request dto.PaymentIntentRequest) (dto.PaymentIntent, error)
+
+	// VerifySignature checks that a webhook payload was signed by the gateway
+	VerifySignature(payload []byte, signature string) bool
+
+	// FetchStatus asks the gateway for the current status of a payment
+	FetchStatus(reference string) (string, error)
+}
+
+// NewPaymentGateway returns the gateway configured by name. Real providers are selected here as they are
+// integrated. The fake gateway keeps payments in memory, so it is only used when configured explicitly and an
+// unknown name is an error rather than a silent fallback to it.
+func NewPaymentGateway(name string, webhookSecret string) (PaymentGateway, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case GatewayFake:
+		return NewFakePaymentGateway(webhookSecret), nil
+	case "":
+		return nil, fmt.Errorf("no payment gateway is configured")
+	}
+	return nil, fmt.Errorf("unknown payment gateway %v", name)
+}
@@ -0,0 +1,19 @@
+package gateway_service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaymentGateway(t *testing.T) {
+	gateway, err := NewPaymentGateway("fake", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, GatewayFake, gateway.Name())
+
+	// The in-memory gateway is never a fallback for a missing or misspelt name
+	_, err = NewPaymentGateway("", "secret")
+	assert.Error(t, err)
+	_, err = NewPaymentGateway("STRIPE", "secret")
+	assert.EqualError(t, err, "unknown payment gateway STRIPE")
+}
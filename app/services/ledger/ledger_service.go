@@ -0,0 +1,189 @@
+package ledger_service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Posting describes a journal entry to post
+type Posting struct {
+	EntryType       string
+	SourceReference string
+	ApplicationID   string
+	PaymentID       string
+	CurrencyCode    string
+	EffectiveDate   time.Time
+	Description     string
+	CreatedBy       string
+	Lines           []PostingLine
+}
+
+// PostingLine is a single debit or credit of a posting
+type PostingLine struct {
+	AccountCode string
+	Debit       float64
+	Credit      float64
+}
+
+// Post validates a posting and writes it as a journal entry. Zero amount lines are dropped and an entry
+// without any line left is not written. The source reference identifies the business event, so an event
+// posted again, e.g. by a retried transaction, returns the entry already recorded for it.
+// Parameters:
+// - tx: the transaction recording the money movement
+// - posting: the entry to post
+// Returns:
+// - models.JournalEntry with the posted entry, empty when there was nothing to post
+// - error when the posting is invalid or could not be written
+func (s *ledgerService) Post(tx *gorm.DB, posting Posting) (entry models.JournalEntry, err error) {
+	entry, err = buildEntry(posting)
+	if err != nil || len(entry.Lines) == 0 {
+		return
+	}
+	return writeEntry(tx, entry)
+}
+
+// ReverseEntry posts an entry debiting every account the original credited and the other way around
+// Parameters:
+// - tx: the transaction recording the reversal
+// - original: the entry to reverse, with its lines
+// - posting: the reversing entry's type, source reference, date, description and author, its lines are ignored
+// Returns:
+// - models.JournalEntry with the reversing entry
+// - error when the reversal could not be written
+func (s *ledgerService) ReverseEntry(tx *gorm.DB, original models.JournalEntry, posting Posting) (
+	models.JournalEntry, error) {
+	posting.ApplicationID = original.ApplicationID.String
+	posting.PaymentID = original.PaymentID.String
+	posting.CurrencyCode = original.CurrencyCode
+	posting.Lines = mirrorLines(original.Lines)
+
+	entry, err := buildEntry(posting)
+	if err != nil || len(entry.Lines) == 0 {
+		return entry, err
+	}
+	entry.ReversesEntryID = null.StringFrom(original.EntryID)
+	return writeEntry(tx, entry)
+}
+
+// writeEntry stores an entry and its lines unless an entry was already posted for its source reference
+// Parameters:
+// - tx: the transaction to write in
+// - entry: the entry to store
+// Returns:
+// - models.JournalEntry with the stored entry, or the one posted earlier for the same source reference
+// - error when the entry could not be written
+func writeEntry(tx *gorm.DB, entry models.JournalEntry) (models.JournalEntry, error) {
+	var entryCondition []db.WhereCondition
+	entryCondition = append(entryCondition, db.WhereCondition{
+		Key:       models.JournalEntryColumns.SourceReference,
+		Condition: "=",
+		Value:     entry.SourceReference,
+	})
+
+	existing := models.JournalEntry{}
+	existing, err := existing.FindOneByConditionTx(tx, entryCondition)
+	if err != nil || existing.EntryID != "" {
+		return existing, err
+	}
+
+	if err = tx.Omit(clause.Associations).Create(&entry).Error; err != nil {
+		return entry, err
+	}
+	return entry, tx.Create(&entry.Lines).Error
+}
+
+// buildEntry turns a posting into a journal entry after checking that it is complete and balanced
+// Parameters:
+// - posting: the entry to build
+// Returns:
+// - models.JournalEntry with its lines, without lines when every amount is zero
+// - error when the posting is incomplete, has a negative or two sided line or does not balance
+func buildEntry(posting Posting) (entry models.JournalEntry, err error) {
+	if posting.EntryType == "" || posting.SourceReference == "" || posting.CurrencyCode == "" {
+		err = fmt.Errorf("journal entry needs a type, a source reference and a currency")
+		return
+	}
+
+	effectiveDate := posting.EffectiveDate
+	if effectiveDate.IsZero() {
+		effectiveDate = time.Now()
+	}
+
+	entry = models.JournalEntry{
+		EntryID:         uuid.New().String(),
+		EntryType:       posting.EntryType,
+		SourceReference: posting.SourceReference,
+		CurrencyCode:    posting.CurrencyCode,
+		EffectiveDate:   time.Date(effectiveDate.Year(), effectiveDate.Month(), effectiveDate.Day(), 0, 0, 0, 0, effectiveDate.Location()),
+		Description:     posting.Description,
+	}
+	if posting.ApplicationID != "" {
+		entry.ApplicationID = null.StringFrom(posting.ApplicationID)
+	}
+	if posting.PaymentID != "" {
+		entry.PaymentID = null.StringFrom(posting.PaymentID)
+	}
+	if posting.CreatedBy != "" {
+		entry.CreatedBy = null.StringFrom(posting.CreatedBy)
+	}
+
+	var totalDebit, totalCredit int64
+	for _, line := range posting.Lines {
+		debit, credit := toCents(line.Debit), toCents(line.Credit)
+		if line.AccountCode == "" || debit < 0 || credit < 0 || (debit > 0 && credit > 0) {
+			err = fmt.Errorf("invalid journal line for account %q: debit %v, credit %v", line.AccountCode, line.Debit, line.Credit)
+			return
+		}
+		if debit == 0 && credit == 0 {
+			continue
+		}
+
+		totalDebit += debit
+		totalCredit += credit
+		entry.Lines = append(entry.Lines, models.JournalLine{
+			LineID:        uuid.New().String(),
+			EntryID:       entry.EntryID,
+			AccountCode:   line.AccountCode,
+			ApplicationID: entry.ApplicationID,
+			Debit:         fromCents(debit),
+			Credit:        fromCents(credit),
+		})
+	}
+
+	if totalDebit != totalCredit {
+		err = fmt.Errorf("journal entry %v does not balance: debits %v, credits %v",
+			posting.SourceReference, fromCents(totalDebit), fromCents(totalCredit))
+		return
+	}
+	return
+}
+
+// mirrorLines swaps the debit and credit of every line
+func mirrorLines(lines []models.JournalLine) (mirrored []PostingLine) {
+	for _, line := range lines {
+		mirrored = append(mirrored, PostingLine{
+			AccountCode: line.AccountCode,
+			Debit:       line.Credit,
+			Credit:      line.Debit,
+		})
+	}
+	return
+}
+
+// toCents converts an amount to minor units, rounding half away from zero
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// fromCents converts minor units back to an amount
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
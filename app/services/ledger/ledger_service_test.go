@@ -0,0 +1,138 @@
+package ledger_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildEntry_Balanced(t *testing.T) {
+	entry, err := buildEntry(Posting{
+		EntryType:       models.JournalEntryRepayment,
+		SourceReference: "REPAYMENT:payment_id",
+		ApplicationID:   "application_id",
+		PaymentID:       "payment_id",
+		CurrencyCode:    "INR",
+		EffectiveDate:   time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC),
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountCash, Debit: 100.10},
+			{AccountCode: models.LedgerAccountLoanPrincipal, Credit: 90.05},
+			{AccountCode: models.LedgerAccountInterestIncome, Credit: 10.05},
+			{AccountCode: models.LedgerAccountExcessCredit, Credit: 0},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, entry.Lines, 3)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), entry.EffectiveDate)
+	assert.Equal(t, "application_id", entry.ApplicationID.String)
+	assert.Equal(t, "application_id", entry.Lines[1].ApplicationID.String)
+	assert.Equal(t, entry.EntryID, entry.Lines[2].EntryID)
+}
+
+func TestBuildEntry_RejectsUnbalanced(t *testing.T) {
+	_, err := buildEntry(Posting{
+		EntryType:       models.JournalEntryDisbursement,
+		SourceReference: "DISBURSEMENT:application_id",
+		CurrencyCode:    "INR",
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountLoanPrincipal, Debit: 1000},
+			{AccountCode: models.LedgerAccountCash, Credit: 999.99},
+		},
+	})
+
+	assert.EqualError(t, err, "journal entry DISBURSEMENT:application_id does not balance: debits 1000, credits 999.99")
+}
+
+func TestBuildEntry_RejectsInvalidLines(t *testing.T) {
+	for _, line := range []PostingLine{
+		{AccountCode: models.LedgerAccountCash, Debit: -10},
+		{AccountCode: models.LedgerAccountCash, Debit: 10, Credit: 10},
+		{Debit: 10},
+	} {
+		_, err := buildEntry(Posting{
+			EntryType:       models.JournalEntryFeeCharge,
+			SourceReference: "FEE_CHARGE:reference",
+			CurrencyCode:    "INR",
+			Lines:           []PostingLine{line},
+		})
+		assert.Error(t, err)
+	}
+
+	_, err := buildEntry(Posting{EntryType: models.JournalEntryFeeCharge, CurrencyCode: "INR"})
+	assert.EqualError(t, err, "journal entry needs a type, a source reference and a currency")
+}
+
+func TestBuildEntry_NothingToPost(t *testing.T) {
+	entry, err := buildEntry(Posting{
+		EntryType:       models.JournalEntryWriteOff,
+		SourceReference: "WRITE_OFF:application_id",
+		CurrencyCode:    "INR",
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountWriteOffExpense},
+			{AccountCode: models.LedgerAccountLoanPrincipal},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, entry.Lines)
+}
+
+func TestMirrorLines_SwapsSides(t *testing.T) {
+	mirrored := mirrorLines([]models.JournalLine{
+		{AccountCode: models.LedgerAccountCash, Debit: 50},
+		{AccountCode: models.LedgerAccountLoanPrincipal, Credit: 50},
+	})
+
+	assert.Equal(t, []PostingLine{
+		{AccountCode: models.LedgerAccountCash, Credit: 50},
+		{AccountCode: models.LedgerAccountLoanPrincipal, Debit: 50},
+	}, mirrored)
+}
+
+func TestBuildTrialBalance_PerCurrency(t *testing.T) {
+	totals := []models.AccountTotal{
+		{AccountCode: models.LedgerAccountCash, CurrencyCode: "INR", Debit: 500, Credit: 1000},
+		{AccountCode: models.LedgerAccountLoanPrincipal, CurrencyCode: "INR", Debit: 1000, Credit: 450},
+		{AccountCode: models.LedgerAccountInterestIncome, CurrencyCode: "INR", Credit: 50},
+		{AccountCode: models.LedgerAccountCash, CurrencyCode: "USD", Credit: 20},
+		{AccountCode: models.LedgerAccountLoanPrincipal, CurrencyCode: "USD", Debit: 20},
+	}
+	accounts := []models.LedgerAccount{
+		{AccountCode: models.LedgerAccountCash, Name: "Cash and bank", AccountType: models.LedgerAccountTypeAsset},
+	}
+
+	currencies := buildTrialBalance(totals, accounts)
+
+	assert.Len(t, currencies, 2)
+	assert.Equal(t, "INR", currencies[0].CurrencyCode)
+	assert.Equal(t, "Cash and bank", currencies[0].Accounts[0].Name)
+	assert.Empty(t, currencies[0].Accounts[0].Debit)
+	assert.Equal(t, "₹500.00", currencies[0].Accounts[0].Credit)
+	assert.Equal(t, "₹550.00", currencies[0].Accounts[1].Debit)
+	assert.Equal(t, "₹550.00", currencies[0].TotalDebit)
+	assert.Equal(t, "₹550.00", currencies[0].TotalCredit)
+	assert.True(t, currencies[0].Balanced)
+	assert.Equal(t, "USD", currencies[1].CurrencyCode)
+	assert.True(t, currencies[1].Balanced)
+}
+
+func TestWriteOff_Total(t *testing.T) {
+	assert.Equal(t, 100.3, WriteOff{Principal: 90.1, Interest: 10.1, Fees: 0.1}.Total())
+}
+
+func TestProvisionLines_ChargeAndRelease(t *testing.T) {
+	charge := provisionLines(120.5)
+	assert.Equal(t, models.LedgerAccountProvisionExpense, charge[0].AccountCode)
+	assert.Equal(t, 120.5, charge[0].Debit)
+	assert.Equal(t, models.LedgerAccountLoanLossAllowance, charge[1].AccountCode)
+	assert.Equal(t, 120.5, charge[1].Credit)
+
+	release := provisionLines(-40)
+	assert.Equal(t, models.LedgerAccountLoanLossAllowance, release[0].AccountCode)
+	assert.Equal(t, 40.0, release[0].Debit)
+	assert.Equal(t, models.LedgerAccountProvisionExpense, release[1].AccountCode)
+	assert.Equal(t, 40.0, release[1].Credit)
+}
@@ -0,0 +1,257 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/ledger/service.go
+
+// Package ledger_service is a generated GoMock package.
+package ledger_service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+	gorm "gorm.io/gorm"
+)
+
+// MockLedgerService is a mock of LedgerService interface.
+type MockLedgerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerServiceMockRecorder
+}
+
+// MockLedgerServiceMockRecorder is the mock recorder for MockLedgerService.
+type MockLedgerServiceMockRecorder struct {
+	mock *MockLedgerService
+}
+
+// NewMockLedgerService creates a new mock instance.
+func NewMockLedgerService(ctrl *gomock.Controller) *MockLedgerService {
+	mock := &MockLedgerService{ctrl: ctrl}
+	mock.recorder = &MockLedgerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerService) EXPECT() *MockLedgerServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAccruedInterest mocks base method.
+func (m *MockLedgerService) GetAccruedInterest(applicationId string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccruedInterest", applicationId)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccruedInterest indicates an expected call of GetAccruedInterest.
+func (mr *MockLedgerServiceMockRecorder) GetAccruedInterest(applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccruedInterest", reflect.TypeOf((*MockLedgerService)(nil).GetAccruedInterest), applicationId)
+}
+
+// GetJournal mocks base method.
+func (m *MockLedgerService) GetJournal(request dto.JournalRequest) (dto.JournalResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournal", request)
+	ret0, _ := ret[0].(dto.JournalResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetJournal indicates an expected call of GetJournal.
+func (mr *MockLedgerServiceMockRecorder) GetJournal(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournal", reflect.TypeOf((*MockLedgerService)(nil).GetJournal), request)
+}
+
+// GetTrialBalance mocks base method.
+func (m *MockLedgerService) GetTrialBalance(request dto.TrialBalanceRequest) (dto.TrialBalanceResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrialBalance", request)
+	ret0, _ := ret[0].(dto.TrialBalanceResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetTrialBalance indicates an expected call of GetTrialBalance.
+func (mr *MockLedgerServiceMockRecorder) GetTrialBalance(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrialBalance", reflect.TypeOf((*MockLedgerService)(nil).GetTrialBalance), request)
+}
+
+// Post mocks base method.
+func (m *MockLedgerService) Post(tx *gorm.DB, posting Posting) (models.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", tx, posting)
+	ret0, _ := ret[0].(models.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockLedgerServiceMockRecorder) Post(tx, posting interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockLedgerService)(nil).Post), tx, posting)
+}
+
+// PostAccrualReversal mocks base method.
+func (m *MockLedgerService) PostAccrualReversal(tx *gorm.DB, application models.LoanApplication, reference, description string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostAccrualReversal", tx, application, reference, description)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostAccrualReversal indicates an expected call of PostAccrualReversal.
+func (mr *MockLedgerServiceMockRecorder) PostAccrualReversal(tx, application, reference, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostAccrualReversal", reflect.TypeOf((*MockLedgerService)(nil).PostAccrualReversal), tx, application, reference, description)
+}
+
+// PostDisbursement mocks base method.
+func (m *MockLedgerService) PostDisbursement(tx *gorm.DB, application models.LoanApplication, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostDisbursement", tx, application, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostDisbursement indicates an expected call of PostDisbursement.
+func (mr *MockLedgerServiceMockRecorder) PostDisbursement(tx, application, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostDisbursement", reflect.TypeOf((*MockLedgerService)(nil).PostDisbursement), tx, application, userId)
+}
+
+// PostFeeCharge mocks base method.
+func (m *MockLedgerService) PostFeeCharge(tx *gorm.DB, application models.LoanApplication, reference string, amount float64, chargedOn time.Time, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostFeeCharge", tx, application, reference, amount, chargedOn, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostFeeCharge indicates an expected call of PostFeeCharge.
+func (mr *MockLedgerServiceMockRecorder) PostFeeCharge(tx, application, reference, amount, chargedOn, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostFeeCharge", reflect.TypeOf((*MockLedgerService)(nil).PostFeeCharge), tx, application, reference, amount, chargedOn, description)
+}
+
+// PostInterestAccrual mocks base method.
+func (m *MockLedgerService) PostInterestAccrual(tx *gorm.DB, application models.LoanApplication, amount float64, accrualDate time.Time) (models.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostInterestAccrual", tx, application, amount, accrualDate)
+	ret0, _ := ret[0].(models.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostInterestAccrual indicates an expected call of PostInterestAccrual.
+func (mr *MockLedgerServiceMockRecorder) PostInterestAccrual(tx, application, amount, accrualDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInterestAccrual", reflect.TypeOf((*MockLedgerService)(nil).PostInterestAccrual), tx, application, amount, accrualDate)
+}
+
+// PostPaymentReversal mocks base method.
+func (m *MockLedgerService) PostPaymentReversal(tx *gorm.DB, payment models.Payment, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostPaymentReversal", tx, payment, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostPaymentReversal indicates an expected call of PostPaymentReversal.
+func (mr *MockLedgerServiceMockRecorder) PostPaymentReversal(tx, payment, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostPaymentReversal", reflect.TypeOf((*MockLedgerService)(nil).PostPaymentReversal), tx, payment, userId)
+}
+
+// PostProvision mocks base method.
+func (m *MockLedgerService) PostProvision(tx *gorm.DB, application models.LoanApplication, runId string, asOf time.Time, movement float64, userId string) (models.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostProvision", tx, application, runId, asOf, movement, userId)
+	ret0, _ := ret[0].(models.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostProvision indicates an expected call of PostProvision.
+func (mr *MockLedgerServiceMockRecorder) PostProvision(tx, application, runId, asOf, movement, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostProvision", reflect.TypeOf((*MockLedgerService)(nil).PostProvision), tx, application, runId, asOf, movement, userId)
+}
+
+// PostRecovery mocks base method.
+func (m *MockLedgerService) PostRecovery(tx *gorm.DB, payment models.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostRecovery", tx, payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostRecovery indicates an expected call of PostRecovery.
+func (mr *MockLedgerServiceMockRecorder) PostRecovery(tx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostRecovery", reflect.TypeOf((*MockLedgerService)(nil).PostRecovery), tx, payment)
+}
+
+// PostRefund mocks base method.
+func (m *MockLedgerService) PostRefund(tx *gorm.DB, refund models.Refund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostRefund", tx, refund)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostRefund indicates an expected call of PostRefund.
+func (mr *MockLedgerServiceMockRecorder) PostRefund(tx, refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostRefund", reflect.TypeOf((*MockLedgerService)(nil).PostRefund), tx, refund)
+}
+
+// PostRepayment mocks base method.
+func (m *MockLedgerService) PostRepayment(tx *gorm.DB, payment models.Payment, allocation Allocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostRepayment", tx, payment, allocation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostRepayment indicates an expected call of PostRepayment.
+func (mr *MockLedgerServiceMockRecorder) PostRepayment(tx, payment, allocation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostRepayment", reflect.TypeOf((*MockLedgerService)(nil).PostRepayment), tx, payment, allocation)
+}
+
+// PostWriteOff mocks base method.
+func (m *MockLedgerService) PostWriteOff(tx *gorm.DB, application models.LoanApplication, userId string) (WriteOff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostWriteOff", tx, application, userId)
+	ret0, _ := ret[0].(WriteOff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostWriteOff indicates an expected call of PostWriteOff.
+func (mr *MockLedgerServiceMockRecorder) PostWriteOff(tx, application, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostWriteOff", reflect.TypeOf((*MockLedgerService)(nil).PostWriteOff), tx, application, userId)
+}
+
+// ReverseEntry mocks base method.
+func (m *MockLedgerService) ReverseEntry(tx *gorm.DB, original models.JournalEntry, posting Posting) (models.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseEntry", tx, original, posting)
+	ret0, _ := ret[0].(models.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseEntry indicates an expected call of ReverseEntry.
+func (mr *MockLedgerServiceMockRecorder) ReverseEntry(tx, original, posting interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseEntry", reflect.TypeOf((*MockLedgerService)(nil).ReverseEntry), tx, original, posting)
+}
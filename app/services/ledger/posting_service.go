@@ -0,0 +1,408 @@
+package ledger_service
+
+import (
+	"fmt"
+	"time"
+
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+)
+
+// Allocation is how much of a settled payment went to the fees charged and to the principal and interest of the
+// installments, whatever is left of the payment amount is excess credit
+type Allocation struct {
+	Principal float64
+	Interest  float64
+	Fees      float64
+}
+
+// WriteOff is what a write-off took off book and the entry recording it
+type WriteOff struct {
+	Principal float64
+	Interest  float64
+	Fees      float64
+	EntryID   string
+}
+
+// Total returns the amount written off
+func (w WriteOff) Total() float64 {
+	return fromCents(toCents(w.Principal) + toCents(w.Interest) + toCents(w.Fees))
+}
+
+// sourceReference builds the reference identifying the business event an entry records
+func sourceReference(entryType string, keys ...string) string {
+	reference := entryType
+	for _, key := range keys {
+		reference += ":" + key
+	}
+	return reference
+}
+
+// PostDisbursement records the approved principal paid out to the borrower
+// Parameters:
+// - tx: the transaction approving the loan
+// - application: the approved loan application
+// - userId: the employee approving the loan
+// Returns:
+// - error when the entry could not be posted
+func (s *ledgerService) PostDisbursement(tx *gorm.DB, application models.LoanApplication, userId string) error {
+	amount := application.ApprovedAmount.Float64
+	_, err := s.Post(tx, Posting{
+		EntryType:       models.JournalEntryDisbursement,
+		SourceReference: sourceReference(models.JournalEntryDisbursement, application.ApplicationID),
+		ApplicationID:   application.ApplicationID,
+		CurrencyCode:    application.CurrencyCode,
+		EffectiveDate:   application.ApprovedDate.Time,
+		Description:     fmt.Sprintf("Disbursement of loan %v", application.ApplicationID),
+		CreatedBy:       userId,
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountLoanPrincipal, Debit: amount},
+			{AccountCode: models.LedgerAccountCash, Credit: amount},
+		},
+	})
+	return err
+}
+
+// PostInterestAccrual records interest earned on a loan for a day, a day is accrued once
+// Parameters:
+// - tx: the transaction recording the accrual
+// - application: the loan earning the interest
+// - amount: the interest earned
+// - accrualDate: the day the interest was earned
+// Returns:
+// - models.JournalEntry with the accrual entry, empty when the amount is zero
+// - error when the entry could not be posted
+func (s *ledgerService) PostInterestAccrual(tx *gorm.DB, application models.LoanApplication, amount float64,
+	accrualDate time.Time) (models.JournalEntry, error) {
+	return s.Post(tx, Posting{
+		EntryType: models.JournalEntryInterestAccrual,
+		SourceReference: sourceReference(models.JournalEntryInterestAccrual, application.ApplicationID,
+			accrualDate.Format("2006-01-02")),
+		ApplicationID: application.ApplicationID,
+		CurrencyCode:  application.CurrencyCode,
+		EffectiveDate: accrualDate,
+		Description:   fmt.Sprintf("Interest accrued on loan %v", application.ApplicationID),
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountInterestReceivable, Debit: amount},
+			{AccountCode: models.LedgerAccountInterestIncome, Credit: amount},
+		},
+	})
+}
+
+// PostAccrualReversal takes the interest accrued on a loan and not collected yet back out of income, e.g. once
+// the loan is paid off or stops accruing. Interest collected later is recognised as income when it is received.
+// Parameters:
+// - tx: the transaction changing the loan
+// - application: the loan whose accrued interest is reversed
+// - reference: identifies the event causing the reversal, an event reverses once
+// - description: why the accrued interest is reversed
+// Returns:
+// - float64 with the interest reversed
+// - error when the entry could not be posted
+func (s *ledgerService) PostAccrualReversal(tx *gorm.DB, application models.LoanApplication, reference string,
+	description string) (float64, error) {
+	journalLine := models.JournalLine{}
+	accrued, err := journalLine.ApplicationBalance(tx, models.LedgerAccountInterestReceivable, application.ApplicationID)
+	if err != nil || accrued <= 0 {
+		return 0, err
+	}
+
+	_, err = s.Post(tx, Posting{
+		EntryType:       models.JournalEntryAccrualReversal,
+		SourceReference: sourceReference(models.JournalEntryAccrualReversal, application.ApplicationID, reference),
+		ApplicationID:   application.ApplicationID,
+		CurrencyCode:    application.CurrencyCode,
+		EffectiveDate:   time.Now(),
+		Description:     description,
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountInterestIncome, Debit: accrued},
+			{AccountCode: models.LedgerAccountInterestReceivable, Credit: accrued},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return accrued, nil
+}
+
+// PostFeeCharge records a fee charged to a loan
+// Parameters:
+// - tx: the transaction charging the fee
+// - application: the loan charged
+// - reference: identifies the charge, a charge is posted once
+// - amount: the fee
+// - chargedOn: the day the fee is charged
+// - description: what the fee is for
+// Returns:
+// - error when the entry could not be posted
+func (s *ledgerService) PostFeeCharge(tx *gorm.DB, application models.LoanApplication, reference string, amount float64,
+	chargedOn time.Time, description string) error {
+	_, err := s.Post(tx, Posting{
+		EntryType:       models.JournalEntryFeeCharge,
+		SourceReference: sourceReference(models.JournalEntryFeeCharge, reference),
+		ApplicationID:   application.ApplicationID,
+		CurrencyCode:    application.CurrencyCode,
+		EffectiveDate:   chargedOn,
+		Description:     description,
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountFeesReceivable, Debit: amount},
+			{AccountCode: models.LedgerAccountFeeIncome, Credit: amount},
+		},
+	})
+	return err
+}
+
+// PostRepayment records a settled payment. The principal allocated reduces the loan receivable; interest is
+// collected from the interest receivable as far as it was accrued and recognised as income straight away
+// beyond that; fees collected clear the fees receivable and anything left over is owed back to the borrower as
+// excess credit.
+// Parameters:
+// - tx: the transaction settling the payment
+// - payment: the settled payment
+// - allocation: how much of the payment went to principal, interest and fees
+// Returns:
+// - error when the entry could not be posted
+func (s *ledgerService) PostRepayment(tx *gorm.DB, payment models.Payment, allocation Allocation) error {
+	amount := toCents(payment.Amount)
+	principal, interest, fees := toCents(allocation.Principal), toCents(allocation.Interest), toCents(allocation.Fees)
+	excess := amount - principal - interest - fees
+	if excess < 0 {
+		// Rounding of the allocated amounts never adds up to more than the payment
+		principal += excess
+		excess = 0
+	}
+
+	journalLine := models.JournalLine{}
+	accrued, err := journalLine.ApplicationBalance(tx, models.LedgerAccountInterestReceivable, payment.ApplicationID)
+	if err != nil {
+		return err
+	}
+	collected := interest
+	if accruedCents := toCents(accrued); collected > accruedCents {
+		collected = accruedCents
+	}
+	if collected < 0 {
+		collected = 0
+	}
+
+	_, err = s.Post(tx, Posting{
+		EntryType:       models.JournalEntryRepayment,
+		SourceReference: sourceReference(models.JournalEntryRepayment, payment.PaymentID),
+		ApplicationID:   payment.ApplicationID,
+		PaymentID:       payment.PaymentID,
+		CurrencyCode:    payment.CurrencyCode,
+		EffectiveDate:   payment.SettledAt.Time,
+		Description:     fmt.Sprintf("Repayment %v", payment.PaymentID),
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountCash, Debit: fromCents(amount)},
+			{AccountCode: models.LedgerAccountLoanPrincipal, Credit: fromCents(principal)},
+			{AccountCode: models.LedgerAccountInterestReceivable, Credit: fromCents(collected)},
+			{AccountCode: models.LedgerAccountInterestIncome, Credit: fromCents(interest - collected)},
+			{AccountCode: models.LedgerAccountFeesReceivable, Credit: fromCents(fees)},
+			{AccountCode: models.LedgerAccountExcessCredit, Credit: fromCents(excess)},
+		},
+	})
+	return err
+}
+
+// PostRecovery records money received on a written-off loan. Nothing is left on book for it to settle, so it is
+// income in full.
+// Parameters:
+// - tx: the transaction settling the payment
+// - payment: the settled payment
+// Returns:
+// - error when the entry could not be posted
+func (s *ledgerService) PostRecovery(tx *gorm.DB, payment models.Payment) error {
+	_, err := s.Post(tx, Posting{
+		EntryType:       models.JournalEntryRecovery,
+		SourceReference: sourceReference(models.JournalEntryRecovery, payment.PaymentID),
+		ApplicationID:   payment.ApplicationID,
+		PaymentID:       payment.PaymentID,
+		CurrencyCode:    payment.CurrencyCode,
+		EffectiveDate:   payment.SettledAt.Time,
+		Description:     fmt.Sprintf("Recovery %v on written-off loan", payment.PaymentID),
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountCash, Debit: payment.Amount},
+			{AccountCode: models.LedgerAccountRecoveryIncome, Credit: payment.Amount},
+		},
+	})
+	return err
+}
+
+// PostPaymentReversal reverses the repayment or recovery entry of a payment, and the accrual reversal posted when the
+// payment paid the loan off: the loan reopens, so the interest accrued on it is owed again and accruing resumes from
+// the last accrual. Payments settled before the ledger was introduced have no entry and nothing is posted for them.
+// Parameters:
+// - tx: the transaction reversing the payment
+// - payment: the reversed payment
+// - userId: the employee reversing the payment
+// Returns:
+// - error when the entry could not be posted
+func (s *ledgerService) PostPaymentReversal(tx *gorm.DB, payment models.Payment, userId string) error {
+	var entryCondition []db.WhereCondition
+	entryCondition = append(entryCondition, db.WhereCondition{
+		Key:       models.JournalEntryColumns.PaymentID,
+		Condition: "=",
+		Value:     payment.PaymentID,
+	})
+	entryCondition = append(entryCondition, db.WhereCondition{
+		Key:       models.JournalEntryColumns.EntryType,
+		Condition: "IN",
+		Value:     []string{models.JournalEntryRepayment, models.JournalEntryRecovery},
+	})
+
+	original := models.JournalEntry{}
+	original, err := original.FindOneByConditionTx(tx, entryCondition)
+	if err != nil || original.EntryID == "" {
+		return err
+	}
+
+	_, err = s.ReverseEntry(tx, original, Posting{
+		EntryType:       models.JournalEntryReversal,
+		SourceReference: sourceReference(models.JournalEntryReversal, payment.PaymentID),
+		EffectiveDate:   time.Now(),
+		Description:     fmt.Sprintf("Reversal of repayment %v: %v", payment.PaymentID, payment.ReversalReason.String),
+		CreatedBy:       userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	var accrualCondition []db.WhereCondition
+	accrualCondition = append(accrualCondition, db.WhereCondition{
+		Key:       models.JournalEntryColumns.SourceReference,
+		Condition: "=",
+		Value:     sourceReference(models.JournalEntryAccrualReversal, payment.ApplicationID, payment.PaymentID),
+	})
+
+	accrualReversal := models.JournalEntry{}
+	accrualReversal, err = accrualReversal.FindOneByConditionTx(tx, accrualCondition)
+	if err != nil || accrualReversal.EntryID == "" {
+		return err
+	}
+
+	_, err = s.ReverseEntry(tx, accrualReversal, Posting{
+		EntryType:       models.JournalEntryReversal,
+		SourceReference: sourceReference(models.JournalEntryReversal, accrualReversal.SourceReference),
+		EffectiveDate:   time.Now(),
+		Description: fmt.Sprintf("Accrued interest restored, payoff by repayment %v reversed: %v", payment.PaymentID,
+			payment.ReversalReason.String),
+		CreatedBy: userId,
+	})
+	return err
+}
+
+// PostRefund records excess credit paid back to the borrower
+// Parameters:
+// - tx: the transaction processing the refund
+// - refund: the processed refund
+// Returns:
+// - error when the entry could not be posted
+func (s *ledgerService) PostRefund(tx *gorm.DB, refund models.Refund) error {
+	_, err := s.Post(tx, Posting{
+		EntryType:       models.JournalEntryRefund,
+		SourceReference: sourceReference(models.JournalEntryRefund, refund.RefundID),
+		ApplicationID:   refund.ApplicationID,
+		CurrencyCode:    refund.CurrencyCode,
+		EffectiveDate:   time.Now(),
+		Description:     fmt.Sprintf("Refund of excess credit %v", refund.RefundID),
+		CreatedBy:       refund.ProcessedBy,
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountExcessCredit, Debit: refund.Amount},
+			{AccountCode: models.LedgerAccountCash, Credit: refund.Amount},
+		},
+	})
+	return err
+}
+
+// PostWriteOff takes the outstanding principal, interest and fees of a loan off book, as recorded in the
+// ledger, and charges them to the write-off expense
+// Parameters:
+// - tx: the transaction writing the loan off
+// - application: the loan written off
+// - userId: the employee writing the loan off
+// Returns:
+// - WriteOff with the amounts taken off book
+// - error when the entry could not be posted
+func (s *ledgerService) PostWriteOff(tx *gorm.DB, application models.LoanApplication, userId string) (
+	writeOff WriteOff, err error) {
+	journalLine := models.JournalLine{}
+	balances := make(map[string]float64)
+	for _, accountCode := range []string{models.LedgerAccountLoanPrincipal, models.LedgerAccountInterestReceivable,
+		models.LedgerAccountFeesReceivable} {
+		balance, balanceErr := journalLine.ApplicationBalance(tx, accountCode, application.ApplicationID)
+		if balanceErr != nil {
+			err = balanceErr
+			return
+		}
+		if balance > 0 {
+			balances[accountCode] = balance
+		}
+	}
+
+	writeOff = WriteOff{
+		Principal: balances[models.LedgerAccountLoanPrincipal],
+		Interest:  balances[models.LedgerAccountInterestReceivable],
+		Fees:      balances[models.LedgerAccountFeesReceivable],
+	}
+
+	entry, err := s.Post(tx, Posting{
+		EntryType:       models.JournalEntryWriteOff,
+		SourceReference: sourceReference(models.JournalEntryWriteOff, application.ApplicationID),
+		ApplicationID:   application.ApplicationID,
+		CurrencyCode:    application.CurrencyCode,
+		EffectiveDate:   time.Now(),
+		Description:     fmt.Sprintf("Write-off of loan %v", application.ApplicationID),
+		CreatedBy:       userId,
+		Lines: []PostingLine{
+			{AccountCode: models.LedgerAccountWriteOffExpense, Debit: writeOff.Total()},
+			{AccountCode: models.LedgerAccountLoanPrincipal, Credit: writeOff.Principal},
+			{AccountCode: models.LedgerAccountInterestReceivable, Credit: writeOff.Interest},
+			{AccountCode: models.LedgerAccountFeesReceivable, Credit: writeOff.Fees},
+		},
+	})
+	writeOff.EntryID = entry.EntryID
+	return
+}
+
+// PostProvision records the change in the loss allowance held for a loan. An increase is charged to the provision
+// expense, a decrease, e.g. once the loan is repaid, cured or written off, is released back from it.
+// Parameters:
+// - tx: the transaction of the provisioning run
+// - application: the loan provisioned for
+// - runId: the provisioning run, a run posts a loan's movement once
+// - asOf: the month end the provision is measured at
+// - movement: the new allowance less the allowance held so far
+// - userId: the employee running the provisioning, empty for the scheduler
+// Returns:
+// - models.JournalEntry with the provision entry, empty when the allowance did not change
+// - error when the entry could not be posted
+func (s *ledgerService) PostProvision(tx *gorm.DB, application models.LoanApplication, runId string, asOf time.Time,
+	movement float64, userId string) (models.JournalEntry, error) {
+	return s.Post(tx, Posting{
+		EntryType:       models.JournalEntryProvision,
+		SourceReference: sourceReference(models.JournalEntryProvision, runId, application.ApplicationID),
+		ApplicationID:   application.ApplicationID,
+		CurrencyCode:    application.CurrencyCode,
+		EffectiveDate:   asOf,
+		Description: fmt.Sprintf("Expected credit loss provision of loan %v as of %v", application.ApplicationID,
+			asOf.Format("2006-01-02")),
+		CreatedBy: userId,
+		Lines:     provisionLines(movement),
+	})
+}
+
+// provisionLines charges an increase of the allowance to the provision expense and releases a decrease from it
+func provisionLines(movement float64) []PostingLine {
+	if movement < 0 {
+		return []PostingLine{
+			{AccountCode: models.LedgerAccountLoanLossAllowance, Debit: -movement},
+			{AccountCode: models.LedgerAccountProvisionExpense, Credit: -movement},
+		}
+	}
+	return []PostingLine{
+		{AccountCode: models.LedgerAccountProvisionExpense, Debit: movement},
+		{AccountCode: models.LedgerAccountLoanLossAllowance, Credit: movement},
+	}
+}
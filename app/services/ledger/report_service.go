@@ -0,0 +1,196 @@
+package ledger_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// GetTrialBalance lists the net balance of every account with postings, per currency, as of a date
+// Parameters:
+// - request: dto.TrialBalanceRequest with the date, today when empty, and an optional currency
+// Returns:
+// - dto.TrialBalanceResponse with the accounts and the debit and credit totals of every currency
+// - dto.HandleError with any error that occurred during the process
+func (s *ledgerService) GetTrialBalance(request dto.TrialBalanceRequest) (
+	response dto.TrialBalanceResponse, handle dto.HandleError) {
+	asOf := time.Now()
+	if request.AsOf != "" {
+		asOf, _ = time.Parse("2006-01-02", request.AsOf)
+	}
+
+	journalLine := models.JournalLine{}
+	totals, err := journalLine.SumByAccount(db.MysqlDB, asOf, request.CurrencyCode)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	account := models.LedgerAccount{}
+	accounts, err := account.FindAllByCondition(nil)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data.AsOf = asOf.Format("2006-01-02")
+	response.Data.Currencies = buildTrialBalance(totals, accounts)
+	return
+}
+
+// buildTrialBalance puts the net balance of every account in its debit or credit column and totals the columns
+// per currency
+// Parameters:
+// - totals: the debits and credits of every account and currency, ordered by currency
+// - accounts: the chart of accounts
+// Returns:
+// - []dto.TrialBalanceCurrency with a trial balance per currency
+func buildTrialBalance(totals []models.AccountTotal, accounts []models.LedgerAccount) []dto.TrialBalanceCurrency {
+	chart := make(map[string]models.LedgerAccount)
+	for _, account := range accounts {
+		chart[account.AccountCode] = account
+	}
+
+	currencies := []dto.TrialBalanceCurrency{}
+	var totalDebit, totalCredit int64
+	closeCurrency := func() {
+		last := &currencies[len(currencies)-1]
+		last.TotalDebit = money.New(totalDebit, last.CurrencyCode).Display()
+		last.TotalCredit = money.New(totalCredit, last.CurrencyCode).Display()
+		last.Balanced = totalDebit == totalCredit
+	}
+
+	for _, total := range totals {
+		if len(currencies) == 0 || currencies[len(currencies)-1].CurrencyCode != total.CurrencyCode {
+			if len(currencies) > 0 {
+				closeCurrency()
+			}
+			currencies = append(currencies, dto.TrialBalanceCurrency{
+				CurrencyCode: total.CurrencyCode,
+				Accounts:     []dto.TrialBalanceAccount{},
+			})
+			totalDebit, totalCredit = 0, 0
+		}
+
+		line := dto.TrialBalanceAccount{
+			AccountCode: total.AccountCode,
+			Name:        chart[total.AccountCode].Name,
+			AccountType: chart[total.AccountCode].AccountType,
+		}
+		net := toCents(total.Debit) - toCents(total.Credit)
+		if net >= 0 {
+			totalDebit += net
+			line.Debit = money.New(net, total.CurrencyCode).Display()
+		} else {
+			totalCredit -= net
+			line.Credit = money.New(-net, total.CurrencyCode).Display()
+		}
+		current := &currencies[len(currencies)-1]
+		current.Accounts = append(current.Accounts, line)
+	}
+	if len(currencies) > 0 {
+		closeCurrency()
+	}
+	return currencies
+}
+
+// GetAccruedInterest returns the interest accrued on a loan and not collected yet
+// Parameters:
+// - applicationId: the loan application
+// Returns:
+// - float64 with the balance of the loan's interest receivable
+// - error when the balance could not be read
+func (s *ledgerService) GetAccruedInterest(applicationId string) (float64, error) {
+	journalLine := models.JournalLine{}
+	accrued, err := journalLine.ApplicationBalance(db.MysqlDB, models.LedgerAccountInterestReceivable, applicationId)
+	if err != nil || accrued < 0 {
+		return 0, err
+	}
+	return accrued, nil
+}
+
+// GetJournal lists the journal entries with their lines, optionally for one loan and an effective date range
+// Parameters:
+// - request: dto.JournalRequest with the optional application ID and date range
+// Returns:
+// - dto.JournalResponse with the entries in posting order
+// - dto.HandleError with any error that occurred during the process
+func (s *ledgerService) GetJournal(request dto.JournalRequest) (response dto.JournalResponse, handle dto.HandleError) {
+	if request.From != "" && request.To != "" && request.From > request.To {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("from date %v is after to date %v", request.From, request.To)
+		return
+	}
+
+	var entryCondition []db.WhereCondition
+	if request.ApplicationID != "" {
+		entryCondition = append(entryCondition, db.WhereCondition{
+			Key:       models.JournalEntryColumns.ApplicationID,
+			Condition: "=",
+			Value:     request.ApplicationID,
+		})
+	}
+	if request.From != "" {
+		entryCondition = append(entryCondition, db.WhereCondition{
+			Key:       models.JournalEntryColumns.EffectiveDate,
+			Condition: ">=",
+			Value:     request.From,
+		})
+	}
+	if request.To != "" {
+		entryCondition = append(entryCondition, db.WhereCondition{
+			Key:       models.JournalEntryColumns.EffectiveDate,
+			Condition: "<=",
+			Value:     request.To,
+		})
+	}
+
+	entry := models.JournalEntry{}
+	entries, err := entry.FindAllByCondition(entryCondition)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data = []dto.JournalEntryObject{}
+	for _, entry := range entries {
+		response.Data = append(response.Data, toJournalEntryObject(entry))
+	}
+	return
+}
+
+// toJournalEntryObject maps a journal entry and its lines to the response object
+func toJournalEntryObject(entry models.JournalEntry) (object dto.JournalEntryObject) {
+	object = dto.JournalEntryObject{
+		EntryId:         entry.EntryID,
+		EntryType:       entry.EntryType,
+		SourceReference: entry.SourceReference,
+		ApplicationId:   entry.ApplicationID.String,
+		PaymentId:       entry.PaymentID.String,
+		CurrencyCode:    entry.CurrencyCode,
+		EffectiveDate:   entry.EffectiveDate.Format("2006-01-02"),
+		Description:     entry.Description,
+		ReversesEntryId: entry.ReversesEntryID.String,
+		Lines:           []dto.JournalLineObject{},
+	}
+	for _, line := range entry.Lines {
+		lineObject := dto.JournalLineObject{AccountCode: line.AccountCode}
+		if line.Debit > 0 {
+			lineObject.Debit = money.NewFromFloat(line.Debit, entry.CurrencyCode).Display()
+		}
+		if line.Credit > 0 {
+			lineObject.Credit = money.NewFromFloat(line.Credit, entry.CurrencyCode).Display()
+		}
+		object.Lines = append(object.Lines, lineObject)
+	}
+	return
+}
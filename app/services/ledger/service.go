@@ -0,0 +1,71 @@
+package ledger_service
+
+import (
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+)
+
+// LedgerService defines the interface for the double-entry general ledger. Posting methods run inside the
+// caller's transaction so the journal entry commits or rolls back together with the money movement it records.
+type LedgerService interface {
+	// Post Posts a balanced journal entry, posting the same source reference again returns the original entry
+	Post(tx *gorm.DB, posting Posting) (models.JournalEntry, error)
+
+	// ReverseEntry Posts the mirror image of an entry, cancelling its effect on every account
+	ReverseEntry(tx *gorm.DB, original models.JournalEntry, posting Posting) (models.JournalEntry, error)
+
+	// PostDisbursement Records the approved principal paid out to the borrower
+	PostDisbursement(tx *gorm.DB, application models.LoanApplication, userId string) error
+
+	// PostInterestAccrual Records interest earned on a loan for a day
+	PostInterestAccrual(tx *gorm.DB, application models.LoanApplication, amount float64, accrualDate time.Time) (
+		models.JournalEntry, error)
+
+	// PostAccrualReversal Reverses the interest accrued on a loan and not collected yet
+	PostAccrualReversal(tx *gorm.DB, application models.LoanApplication, reference string, description string) (
+		float64, error)
+
+	// PostFeeCharge Records a fee charged to a loan
+	PostFeeCharge(tx *gorm.DB, application models.LoanApplication, reference string, amount float64,
+		chargedOn time.Time, description string) error
+
+	// PostRepayment Records a settled payment and how it was allocated
+	PostRepayment(tx *gorm.DB, payment models.Payment, allocation Allocation) error
+
+	// PostRecovery Records money recovered on a written-off loan as income
+	PostRecovery(tx *gorm.DB, payment models.Payment) error
+
+	// PostPaymentReversal Reverses the repayment or recovery entry of a reversed payment, and the accrual reversal
+	// of the payoff it made
+	PostPaymentReversal(tx *gorm.DB, payment models.Payment, userId string) error
+
+	// PostRefund Records excess credit paid back to the borrower
+	PostRefund(tx *gorm.DB, refund models.Refund) error
+
+	// PostWriteOff Moves every receivable of a loan off book to the write-off expense
+	PostWriteOff(tx *gorm.DB, application models.LoanApplication, userId string) (WriteOff, error)
+
+	// PostProvision Records the change in the expected credit loss allowance of a loan
+	PostProvision(tx *gorm.DB, application models.LoanApplication, runId string, asOf time.Time, movement float64,
+		userId string) (models.JournalEntry, error)
+
+	// GetAccruedInterest Returns the interest accrued on a loan and not collected yet
+	GetAccruedInterest(applicationId string) (float64, error)
+
+	// GetTrialBalance Returns the balance of every account per currency
+	GetTrialBalance(request dto.TrialBalanceRequest) (dto.TrialBalanceResponse, dto.HandleError)
+
+	// GetJournal Lists journal entries with their lines
+	GetJournal(request dto.JournalRequest) (dto.JournalResponse, dto.HandleError)
+}
+
+// ledgerService is an implementation of LedgerService
+type ledgerService struct{}
+
+// NewLedgerService returns a new instance of LedgerService
+func NewLedgerService() LedgerService {
+	return &ledgerService{}
+}
@@ -0,0 +1,201 @@
+package loan_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	actionService "github.com/nishanthrk/aspire-lms/app/services/action"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// overridePayload is what an override awaiting a checker applies
+type overridePayload struct {
+	ApplicationID  string  `json:"application_id"`
+	ApprovalID     string  `json:"approval_id"`
+	ApprovedAmount float64 `json:"approved_amount"`
+}
+
+// overrideApprovalHandler applies the overrides of ApproveLoanApplication once they are checked
+type overrideApprovalHandler struct {
+	loan         *loanService
+	repaymentSvc repaymentService.RepaymentService
+}
+
+// NewOverrideApprovalHandler returns the actionService.Handler of loan application overrides
+// Parameters:
+// - ledger: ledgerService.LedgerService the disbursement is posted with
+// - repaymentSvc: repaymentService.RepaymentService for generating the repayment schedule
+func NewOverrideApprovalHandler(ledger ledgerService.LedgerService,
+	repaymentSvc repaymentService.RepaymentService) actionService.Handler {
+	return &overrideApprovalHandler{
+		loan:         &loanService{ledger: ledger},
+		repaymentSvc: repaymentSvc,
+	}
+}
+
+// Apply approves the application for the overridden amount. The override must still be the last sign-off of the
+// pending application, and the amount within the approval limit of the checker, who completes the approval chain.
+// Parameters:
+// - tx: the transaction the action is approved in
+// - action: the pending action with the overridePayload
+// - checker: the employee approving the override
+// Returns:
+// - string describing the approval
+// - error when the application cannot be approved anymore or by the checker
+func (h *overrideApprovalHandler) Apply(tx *gorm.DB, action models.PendingAction, checker models.User) (
+	string, error) {
+	application, approvals, payload, err := lockOverride(tx, action)
+	if err != nil {
+		return "", err
+	}
+
+	access, err := authorizationService.GetUserAccess(checker.UserID)
+	if err != nil {
+		return "", err
+	}
+	limit, err := findApprovalLimit(checker.UserID, access.Roles, application.CurrencyCode)
+	if err != nil {
+		return "", err
+	}
+	amount := money.NewFromFloat(payload.ApprovedAmount, application.CurrencyCode).Display()
+	if !limit.covers(payload.ApprovedAmount) {
+		return "", fmt.Errorf("%v is above the approval limit of the checker", amount)
+	}
+
+	approval := models.LoanApproval{
+		ApprovalID:     uuid.New().String(),
+		ApplicationID:  application.ApplicationID,
+		Step:           len(approvals) + 1,
+		ApproverID:     checker.UserID,
+		ApprovedAmount: payload.ApprovedAmount,
+		Override:       true,
+		Decision:       models.LoanApprovalApproved,
+	}
+	if err = tx.Create(&approval).Error; err != nil {
+		return "", err
+	}
+
+	err = h.loan.approveApplication(tx, &application, payload.ApprovedAmount, checker.UserID, h.repaymentSvc)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("application %v approved for %v", application.ApplicationID, amount), nil
+}
+
+// Reject records the checker turning the override down, the application stays pending and can be signed off again
+// Parameters:
+// - tx: the transaction the action is rejected in
+// - action: the pending action with the overridePayload
+// - checker: the employee rejecting the override
+// Returns:
+// - error when the rejection could not be recorded
+func (h *overrideApprovalHandler) Reject(tx *gorm.DB, action models.PendingAction, checker models.User) error {
+	application, approvals, payload, err := lockOverride(tx, action)
+	if err != nil {
+		return err
+	}
+
+	approval := models.LoanApproval{
+		ApprovalID:     uuid.New().String(),
+		ApplicationID:  application.ApplicationID,
+		Step:           len(approvals) + 1,
+		ApproverID:     checker.UserID,
+		ApprovedAmount: payload.ApprovedAmount,
+		Override:       true,
+		Decision:       models.LoanApprovalRejected,
+		Reason:         null.StringFrom("override rejected by the checker"),
+	}
+	return tx.Create(&approval).Error
+}
+
+// lockOverride locks the application of an override and verifies that the override still awaits a checker
+func lockOverride(tx *gorm.DB, action models.PendingAction) (application models.LoanApplication,
+	approvals []models.LoanApproval, payload overridePayload, err error) {
+	if err = json.Unmarshal([]byte(action.Payload), &payload); err != nil {
+		return
+	}
+
+	application, err = application.FindByPrimaryKeyForUpdate(tx, payload.ApplicationID)
+	if err != nil {
+		return
+	}
+	if application.Status != models.LoanApplicationStatusPending {
+		err = fmt.Errorf("application %v is no longer pending", payload.ApplicationID)
+		return
+	}
+
+	approval := models.LoanApproval{}
+	if approvals, err = approval.FindByApplication(tx, application.ApplicationID); err != nil {
+		return
+	}
+	if !awaitingCheck(approvals) || approvals[len(approvals)-1].ApprovalID != payload.ApprovalID {
+		err = fmt.Errorf("override is no longer awaiting a checker")
+	}
+	return
+}
+
+// eligibilityConfigHandler applies the eligibility config edits once they are checked
+type eligibilityConfigHandler struct{}
+
+// NewEligibilityConfigHandler returns the actionService.Handler of eligibility config edits
+func NewEligibilityConfigHandler() actionService.Handler {
+	return &eligibilityConfigHandler{}
+}
+
+// Apply replaces the thresholds and factors of an eligibility config with the proposed ones
+// Parameters:
+// - tx: the transaction the action is approved in
+// - action: the pending action, its resource is the config ID and its payload a dto.EligibilityConfigRequest
+// - checker: the employee approving the edit
+// Returns:
+// - string describing the edit
+// - error when the config is not found or could not be saved
+func (h *eligibilityConfigHandler) Apply(tx *gorm.DB, action models.PendingAction, checker models.User) (
+	string, error) {
+	configId, err := strconv.ParseInt(action.ResourceID, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	request := dto.EligibilityConfigRequest{}
+	if err = json.Unmarshal([]byte(action.Payload), &request); err != nil {
+		return "", err
+	}
+
+	config := models.LoanEligibilityConfig{}
+	config, err = config.FindByPrimaryKeyForUpdate(tx, configId)
+	if err != nil {
+		return "", err
+	}
+	if config.ID == 0 {
+		return "", fmt.Errorf("eligibility config %v not found", configId)
+	}
+
+	config.MinCreditScore = request.MinCreditScore
+	config.MaxCreditScore = request.MaxCreditScore
+	config.MaxFoir = request.MaxFoir
+	config.BaseLoanAmount = request.BaseLoanAmount
+	config.CreditScoreFactorHigh = request.CreditScoreFactorHigh
+	config.CreditScoreFactorMedium = request.CreditScoreFactorMedium
+	config.CreditScoreFactorLow = request.CreditScoreFactorLow
+	config.UpdatedAt = time.Now()
+	if err = tx.Omit(clause.Associations).Save(&config).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("eligibility config %v of %v updated", config.ID, config.CountryCode), nil
+}
+
+// Reject leaves the eligibility config as it is, nothing was held for the edit
+func (h *eligibilityConfigHandler) Reject(tx *gorm.DB, action models.PendingAction, checker models.User) error {
+	return nil
+}
@@ -0,0 +1,292 @@
+package loan_service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Rhymond/go-money"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	assignmentService "github.com/nishanthrk/aspire-lms/app/services/assignment"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm"
+)
+
+// approvalLimit is how much an employee may approve on their own in a currency
+type approvalLimit struct {
+	amount    float64
+	unlimited bool
+}
+
+// covers reports whether an amount is within the limit
+func (l approvalLimit) covers(amount float64) bool {
+	return l.unlimited || math.Round(amount*100) <= math.Round(l.amount*100)
+}
+
+// above reports whether the limit is higher than another one
+func (l approvalLimit) above(other approvalLimit) bool {
+	if other.unlimited {
+		return false
+	}
+	return l.unlimited || math.Round(l.amount*100) > math.Round(other.amount*100)
+}
+
+// approverCandidate is an employee an application can be routed to for the next sign-off
+type approverCandidate struct {
+	userId   string
+	limit    approvalLimit
+	override bool
+	pending  int64
+}
+
+// signOffApplication records the sign-off of an employee on a pending application. The sign-off approves the
+// application when the amount is within the employee's limit and is not above the eligible one. An override the
+// employee may give awaits a checker instead, a different employee who may override too. Otherwise the application
+// is routed to a next-level approver, who is added to its participants and is the only one who can sign it off next.
+// Parameters:
+// - tx: the transaction the application is locked in
+// - application: the pending application
+// - request: dto.ApplicationApproveRequest with the amount signed off
+// - user: the employee signing off
+// Returns:
+// - models.LoanApproval that was recorded, APPROVED when the chain is complete, AWAITING_CHECK for an override and
+// ESCALATED otherwise
+// - error when the employee cannot sign off the application or nobody can approve it
+func signOffApplication(tx *gorm.DB, application models.LoanApplication, request dto.ApplicationApproveRequest,
+	user models.User) (approval models.LoanApproval, err error) {
+	approvals, err := approval.FindByApplication(tx, application.ApplicationID)
+	if err != nil {
+		return
+	}
+
+	// Only the holder of the application signs it off, or their delegate while they are out of office. Applications
+	// allocated before holders were recorded are signed off by the next-level approver they were routed to.
+	holder, err := assignmentService.ActingFor(tx, application.ApplicationID, user.UserID)
+	if err != nil {
+		return
+	}
+	signer := user.UserID
+	if holder != "" {
+		signer = holder
+	} else if pending := pendingApprover(approvals); pending != "" && pending != user.UserID {
+		err = fmt.Errorf("application is awaiting the sign-off of the next-level approver")
+		return
+	}
+
+	// An override awaiting a checker is approved or rejected through its pending action
+	if awaitingCheck(approvals) {
+		err = fmt.Errorf("application override is awaiting a checker")
+		return
+	}
+
+	// A delegate signs off within the approval limit and permissions of the holder they act for
+	access, err := authorizationService.GetUserAccess(signer)
+	if err != nil {
+		return
+	}
+	limit, err := findApprovalLimit(signer, access.Roles, application.CurrencyCode)
+	if err != nil {
+		return
+	}
+
+	override := request.ApprovedAmount > application.EligibleLoanAmount
+	approval = models.LoanApproval{
+		ApprovalID:     uuid.New().String(),
+		ApplicationID:  application.ApplicationID,
+		Step:           len(approvals) + 1,
+		ApproverID:     user.UserID,
+		OnBehalfOf:     null.NewString(signer, signer != user.UserID),
+		ApprovedAmount: request.ApprovedAmount,
+		Override:       override,
+		Decision:       models.LoanApprovalApproved,
+	}
+
+	canOverride := authorizationService.HasPermission(access.Permissions, models.PermissionApplicationOverride)
+	if !limit.covers(request.ApprovedAmount) || (override && !canOverride) {
+		exclude := map[string]bool{user.UserID: true, signer: true}
+		for _, previous := range approvals {
+			exclude[previous.ApproverID] = true
+		}
+
+		var candidates []approverCandidate
+		if candidates, err = findApproverCandidates(application.CurrencyCode, exclude); err != nil {
+			return
+		}
+		next, found := pickNextApprover(candidates, limit, request.ApprovedAmount, override)
+		if !found {
+			err = fmt.Errorf("no approver has the authority to approve %v",
+				money.NewFromFloat(request.ApprovedAmount, application.CurrencyCode).Display())
+			return
+		}
+
+		approval.Decision = models.LoanApprovalEscalated
+		approval.EscalatedTo = null.StringFrom(next.userId)
+		approval.Reason = null.StringFrom("above the approval limit")
+		if limit.covers(request.ApprovedAmount) {
+			approval.Reason = null.StringFrom("above the eligible amount")
+		}
+
+		// The next-level approver takes part in the application so that they can read and sign it off
+		if !authorizationService.IsApplicationParticipant(application.ApplicationID, next.userId,
+			constants.UserTypeEmployee) {
+			participant := models.LoanApplicationParticipant{
+				ParticipantID:   uuid.New().String(),
+				ApplicationID:   application.ApplicationID,
+				ParticipantType: constants.UserTypeEmployee,
+				UserID:          next.userId,
+			}
+			if err = tx.Create(&participant).Error; err != nil {
+				return
+			}
+		}
+
+		// The next-level approver holds the application from now on
+		if holder != "" {
+			err = assignmentService.RecordHandover(tx, application.ApplicationID, holder, next.userId, user.UserID,
+				assignmentService.ReasonEscalated)
+			if err != nil {
+				return
+			}
+		}
+	} else if override {
+		approval.Decision = models.LoanApprovalChecking
+		approval.Reason = null.StringFrom("override awaiting a checker")
+	}
+
+	err = tx.Create(&approval).Error
+	return
+}
+
+// pendingApprover returns who an application was routed to by its last sign-off, empty when it was not routed
+func pendingApprover(approvals []models.LoanApproval) string {
+	if len(approvals) == 0 {
+		return ""
+	}
+	last := approvals[len(approvals)-1]
+	if last.Decision != models.LoanApprovalEscalated {
+		return ""
+	}
+	return last.EscalatedTo.String
+}
+
+// awaitingCheck reports whether the last sign-off of an application is an override awaiting a checker
+func awaitingCheck(approvals []models.LoanApproval) bool {
+	return len(approvals) > 0 && approvals[len(approvals)-1].Decision == models.LoanApprovalChecking
+}
+
+// findApprovalLimit returns the approval limit of a user in a currency. A limit given to the user replaces the ones of
+// their roles, otherwise the highest of their roles applies. Users without any cannot approve on their own.
+func findApprovalLimit(userId string, roles []string, currencyCode string) (approvalLimit, error) {
+	authority := models.ApprovalAuthority{}
+	authorities, err := authority.FindForUser(userId, roles, currencyCode)
+	if err != nil {
+		return approvalLimit{}, err
+	}
+	return resolveApprovalLimit(authorities), nil
+}
+
+// resolveApprovalLimit picks the limit of a user among the authorities given to them and to their roles
+func resolveApprovalLimit(authorities []models.ApprovalAuthority) (limit approvalLimit) {
+	var own, roles []models.ApprovalAuthority
+	for _, authority := range authorities {
+		if authority.UserID.Valid {
+			own = append(own, authority)
+		} else {
+			roles = append(roles, authority)
+		}
+	}
+
+	applicable := roles
+	if len(own) > 0 {
+		applicable = own
+	}
+	for _, authority := range applicable {
+		candidate := approvalLimit{amount: authority.MaxAmount.Float64, unlimited: !authority.MaxAmount.Valid}
+		if candidate.above(limit) {
+			limit = candidate
+		}
+	}
+	return
+}
+
+// findApproverCandidates returns the employees who may approve applications, with their limit in a currency
+// Parameters:
+// - currencyCode: the currency of the application
+// - exclude: the employees who already signed the application off
+// Returns:
+// - []approverCandidate with the limit, the override permission and the pending applications of each employee
+// - error when they could not be read
+func findApproverCandidates(currencyCode string, exclude map[string]bool) (candidates []approverCandidate, err error) {
+	user := models.User{}
+	employees, err := user.FindEmployeesWithPermission(models.PermissionApplicationApprove)
+	if err != nil {
+		return
+	}
+
+	for _, employee := range employees {
+		if exclude[employee.UserID] {
+			continue
+		}
+		access, err := authorizationService.GetUserAccess(employee.UserID)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := findApprovalLimit(employee.UserID, access.Roles, currencyCode)
+		if err != nil {
+			return nil, err
+		}
+		participant := models.LoanApplicationParticipant{}
+		pending, err := participant.CountPendingByUser(employee.UserID)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, approverCandidate{
+			userId:   employee.UserID,
+			limit:    limit,
+			override: authorizationService.HasPermission(access.Permissions, models.PermissionApplicationOverride),
+			pending:  pending,
+		})
+	}
+	return
+}
+
+// pickNextApprover chooses who an application is routed to after a sign-off that did not approve it. An amount above
+// the signer's limit goes one level up, to the lowest limit above the signer's. An amount within it that needs an
+// override goes to the lowest limit covering it among the employees who may override. Ties go to the employee with
+// the fewest pending applications.
+// Parameters:
+// - candidates: the employees who may approve applications and have not signed this one off yet
+// - signer: the limit of the employee who signed off
+// - amount: the amount signed off
+// - override: whether the amount is above the eligible amount
+// Returns:
+// - approverCandidate the application is routed to
+// - bool reporting whether anyone was found
+func pickNextApprover(candidates []approverCandidate, signer approvalLimit, amount float64, override bool) (
+	next approverCandidate, found bool) {
+	var eligible []approverCandidate
+	for _, candidate := range candidates {
+		if !signer.covers(amount) {
+			if candidate.limit.above(signer) {
+				eligible = append(eligible, candidate)
+			}
+		} else if override && candidate.override && candidate.limit.covers(amount) {
+			eligible = append(eligible, candidate)
+		}
+	}
+	if len(eligible) == 0 {
+		return
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		if eligible[i].limit.above(eligible[j].limit) || eligible[j].limit.above(eligible[i].limit) {
+			return eligible[j].limit.above(eligible[i].limit)
+		}
+		return eligible[i].pending < eligible[j].pending
+	})
+	return eligible[0], true
+}
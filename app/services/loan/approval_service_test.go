@@ -0,0 +1,103 @@
+package loan_service
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveApprovalLimit(t *testing.T) {
+	// Users without any authority cannot approve on their own
+	limit := resolveApprovalLimit(nil)
+	assert.False(t, limit.covers(0.01))
+
+	// The highest limit of the roles applies
+	roles := []models.ApprovalAuthority{
+		{RoleCode: null.StringFrom(models.RoleUnderwriter), MaxAmount: null.FloatFrom(10000)},
+		{RoleCode: null.StringFrom(models.RoleApprover), MaxAmount: null.FloatFrom(50000)},
+	}
+	limit = resolveApprovalLimit(roles)
+	assert.True(t, limit.covers(50000))
+	assert.False(t, limit.covers(50000.01))
+
+	// A limit given to the user replaces the ones of their roles, even a lower one
+	own := append(roles, models.ApprovalAuthority{UserID: null.StringFrom("user"), MaxAmount: null.FloatFrom(5000)})
+	limit = resolveApprovalLimit(own)
+	assert.True(t, limit.covers(5000))
+	assert.False(t, limit.covers(10000))
+
+	// A missing amount is no limit
+	limit = resolveApprovalLimit(append(roles, models.ApprovalAuthority{RoleCode: null.StringFrom(models.RoleSuperAdmin)}))
+	assert.True(t, limit.unlimited)
+	assert.True(t, limit.covers(1e12))
+}
+
+func TestPickNextApprover_AboveLimitGoesOneLevelUp(t *testing.T) {
+	candidates := []approverCandidate{
+		{userId: "peer", limit: approvalLimit{amount: 10000}},
+		{userId: "manager-busy", limit: approvalLimit{amount: 50000}, pending: 7},
+		{userId: "manager", limit: approvalLimit{amount: 50000}, pending: 2},
+		{userId: "director", limit: approvalLimit{unlimited: true}},
+	}
+
+	// The lowest level above the signer's, even when it does not cover the amount yet
+	next, found := pickNextApprover(candidates, approvalLimit{amount: 10000}, 80000, false)
+	assert.True(t, found)
+	assert.Equal(t, "manager", next.userId)
+
+	// From there the chain goes on to the director
+	next, found = pickNextApprover(candidates, approvalLimit{amount: 50000}, 80000, false)
+	assert.True(t, found)
+	assert.Equal(t, "director", next.userId)
+
+	// Nobody is above an unlimited signer
+	_, found = pickNextApprover(candidates, approvalLimit{unlimited: true}, 80000, true)
+	assert.False(t, found)
+}
+
+func TestPickNextApprover_OverrideGoesToWhoMayOverride(t *testing.T) {
+	candidates := []approverCandidate{
+		{userId: "peer", limit: approvalLimit{amount: 50000}},
+		{userId: "approver-small", limit: approvalLimit{amount: 5000}, override: true},
+		{userId: "approver", limit: approvalLimit{amount: 20000}, override: true},
+		{userId: "director", limit: approvalLimit{unlimited: true}, override: true},
+	}
+
+	// The lowest limit covering the amount among the employees who may override
+	next, found := pickNextApprover(candidates, approvalLimit{amount: 50000}, 15000, true)
+	assert.True(t, found)
+	assert.Equal(t, "approver", next.userId)
+
+	_, found = pickNextApprover(candidates[:2], approvalLimit{amount: 50000}, 15000, true)
+	assert.False(t, found)
+}
+
+func TestPendingApprover(t *testing.T) {
+	assert.Equal(t, "", pendingApprover(nil))
+
+	approvals := []models.LoanApproval{
+		{Step: 1, ApproverID: "underwriter", Decision: models.LoanApprovalEscalated,
+			EscalatedTo: null.StringFrom("manager")},
+	}
+	assert.Equal(t, "manager", pendingApprover(approvals))
+
+	approvals = append(approvals, models.LoanApproval{Step: 2, ApproverID: "manager",
+		Decision: models.LoanApprovalApproved})
+	assert.Equal(t, "", pendingApprover(approvals))
+}
+
+func TestAwaitingCheck(t *testing.T) {
+	assert.False(t, awaitingCheck(nil))
+
+	approvals := []models.LoanApproval{
+		{Step: 1, ApproverID: "approver", Decision: models.LoanApprovalChecking, Override: true},
+	}
+	assert.True(t, awaitingCheck(approvals))
+
+	// A rejected override lets the application be signed off again
+	approvals = append(approvals, models.LoanApproval{Step: 2, ApproverID: "checker",
+		Decision: models.LoanApprovalRejected, Override: true})
+	assert.False(t, awaitingCheck(approvals))
+}
@@ -0,0 +1,86 @@
+package loan_service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	actionService "github.com/nishanthrk/aspire-lms/app/services/action"
+)
+
+// GetEligibilityConfigs retrieves the eligibility configs the eligible loan amount is calculated with
+// Returns:
+// - dto.EligibilityConfigListResponse with the configs of every country
+// - dto.HandleError with any error that occurred during the process
+func (s *loanService) GetEligibilityConfigs() (response dto.EligibilityConfigListResponse, handle dto.HandleError) {
+	config := models.LoanEligibilityConfig{}
+	configs, err := config.FindAll()
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	response.Data.Configs = []dto.EligibilityConfigObject{}
+	for _, config := range configs {
+		response.Data.Configs = append(response.Data.Configs, dto.EligibilityConfigObject{
+			ConfigID:                config.ID,
+			CountryCode:             config.CountryCode,
+			MinCreditScore:          config.MinCreditScore,
+			MaxCreditScore:          config.MaxCreditScore,
+			MaxFoir:                 config.MaxFoir,
+			BaseLoanAmount:          config.BaseLoanAmount,
+			CreditScoreFactorHigh:   config.CreditScoreFactorHigh,
+			CreditScoreFactorMedium: config.CreditScoreFactorMedium,
+			CreditScoreFactorLow:    config.CreditScoreFactorLow,
+			UpdatedAt:               config.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	response.Status = 1
+	response.Message = fmt.Sprintf("Total %v config(s) found", len(configs))
+	return
+}
+
+// ProposeEligibilityConfig proposes an edit of an eligibility config. The config is only changed once a different
+// employee who may manage configuration approves the pending action.
+// Parameters:
+// - request: dto.EligibilityConfigRequest with the config ID and its new thresholds and factors
+// - user: the employee proposing the edit
+// Returns:
+// - dto.PendingActionResponse with the pending action
+// - dto.HandleError with any error that occurred during the process
+func (s *loanService) ProposeEligibilityConfig(request dto.EligibilityConfigRequest, user models.User) (
+	response dto.PendingActionResponse, handle dto.HandleError) {
+	config := models.LoanEligibilityConfig{}
+	config, _ = config.FindByPrimaryKey(request.ConfigID)
+	if config.ID == 0 {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("eligibility config %v not found", request.ConfigID)
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+	action, err := actionService.Propose(tx, models.ActionEligibilityConfig, strconv.FormatInt(config.ID, 10),
+		request, models.PermissionConfigManage, user)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Eligibility config change sent to a checker"
+	response.Data = actionService.ToActionObject(action)
+	return
+}
@@ -1,7 +1,9 @@
 package loan_service
 
 import (
+	"crypto/rand"
 	"fmt"
+	"github.com/Rhymond/go-money"
 	"github.com/google/uuid"
 	"github.com/guregu/null"
 	"github.com/nishanthrk/aspire-lms/app/common/constants"
@@ -9,18 +11,31 @@ import (
 	db "github.com/nishanthrk/aspire-lms/app/database"
 	"github.com/nishanthrk/aspire-lms/app/dto"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	actionService "github.com/nishanthrk/aspire-lms/app/services/action"
+	assignmentService "github.com/nishanthrk/aspire-lms/app/services/assignment"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
 	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
 	userService "github.com/nishanthrk/aspire-lms/app/services/user"
+	"gorm.io/gorm"
+	"math/big"
 	"time"
 )
 
-// ApproveLoanApplication approves a loan application and generates the repayment schedule if needed
+// maxVirtualAccountAttempts is how many random virtual account numbers are tried before an approval gives up, a
+// number already assigned to another loan is drawn again
+const maxVirtualAccountAttempts = 5
+
+// ApproveLoanApplication signs off a loan application. It is approved, and its repayment schedule generated if
+// needed, when the amount is within the approval limit of the employee and is not above the eligible amount. An
+// override the employee may give is proposed as a pending action, applied once a checker approves it. Otherwise it is
+// routed to a next-level approver and stays pending until the chain of sign-offs completes.
 // Parameters:
 // - request: dto.ApplicationApproveRequest containing the application ID, approved amount, and override flag
 // - user: models.User representing the user performing the approval
 // - repaymentSvc: repaymentService.RepaymentService for generating the repayment schedule
 // Returns:
-// - dto.ApplicationApproveResponse with the approval result
+// - dto.ApplicationApproveResponse with the approval result, status 2 when it was routed to the next-level approver
+// and 3 when the override awaits a checker
 // - dto.HandleError with any error that occurred during the process
 func (s *loanService) ApproveLoanApplication(request dto.ApplicationApproveRequest, user models.User, repaymentSvc repaymentService.RepaymentService) (
 	response dto.ApplicationApproveResponse, handle dto.HandleError) {
@@ -53,89 +68,99 @@ func (s *loanService) ApproveLoanApplication(request dto.ApplicationApproveReque
 		return
 	}
 
-	// Check if the user has permission to approve the application
-	var participantCondition []db.WhereCondition
-
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.UserID,
-		Condition: "=",
-		Value:     user.UserID,
-	})
-
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.ApplicationID,
-		Condition: "=",
-		Value:     application.ApplicationID,
-	})
-
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.ParticipantType,
-		Condition: "=",
-		Value:     constants.UserTypeEmployee,
-	})
-
-	participant := models.LoanApplicationParticipant{}
-	participant, _ = participant.FindOneByCondition(participantCondition)
-
-	if participant.ParticipantID == "" {
+	// Check if the user is allocated to the application and may approve it
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionApplicationApprove,
+	}
+	if err := authorizationService.AuthorizeApplication(user, application.ApplicationID, rule); err != nil {
 		handle.Status = -4
-		handle.Errors = fmt.Errorf("dont have permission to approve the application")
+		handle.Errors = err
 		return
 	}
 
 	tx := db.MysqlDB.Begin()
 
-	// Update the application status and approval details
-	application.Status = models.LoanApplicationStatusApproved
-	application.ApprovedAmount = null.FloatFrom(request.ApprovedAmount)
-	application.ApprovedDate = null.TimeFrom(time.Now())
-
-	// Check if the application needs a new repayment schedule
-	difference := application.ApprovedDate.Time.Sub(application.ApplicationDate)
-
-	// If the approved amount and loan amount not same or loan is approved beyond application date regenerate repayment
-	if ((difference.Hours() / 24) > 1) || request.ApprovedAmount != application.LoanAmount {
-
-		var repaymentCondition []db.WhereCondition
-		repaymentCondition = append(repaymentCondition, db.WhereCondition{
-			Key:       models.RepaymentColumns.ApplicationID,
-			Condition: "=",
-			Value:     application.ApplicationID,
-		})
-
-		repayment := models.Repayment{}
-		oldRepayment, _ := repayment.FindAllByCondition(repaymentCondition)
-
-		// Delete all the old repayment of the application
-		if len(oldRepayment) > 0 {
-			err := tx.Delete(&oldRepayment).Error
-			if err != nil {
-				tx.Rollback()
-				handle.Status = -5
-				handle.Errors = err
-				return
-			}
+	// Lock the application so that two sign-offs cannot both approve it
+	application, err := application.FindByPrimaryKeyForUpdate(tx, application.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+	if application.Status != models.LoanApplicationStatusPending {
+		tx.Rollback()
+		response = dto.ApplicationApproveResponse{
+			Status:  1,
+			Message: "Loan application already processed",
+			Data: dto.ApproveObject{
+				ApplicationID: application.ApplicationID,
+			},
 		}
+		return
+	}
 
-		// Generate new repayment for application for approved date
-		repayments, err := repaymentSvc.CalculateRepaymentSchedule(&application)
-		if err != nil {
+	// Record the sign-off, the application is only approved once someone whose authority covers it signs it off
+	approval, err := signOffApplication(tx, application, request, user)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+	if approval.Decision == models.LoanApprovalEscalated {
+		if err = tx.Commit().Error; err != nil {
 			tx.Rollback()
-			handle.Status = -5
+			handle.Status = -6
 			handle.Errors = err
 			return
 		}
-
-		err = tx.Save(&repayments).Error
+		response = dto.ApplicationApproveResponse{
+			Status:  2,
+			Message: "Loan application sent to the next-level approver",
+			Data: dto.ApproveObject{
+				ApplicationID:   application.ApplicationID,
+				PendingApprover: approval.EscalatedTo.String,
+				ApprovalStep:    approval.Step,
+			},
+		}
+		return
+	}
+	if approval.Decision == models.LoanApprovalChecking {
+		// The override is only applied once a checker approves it
+		action, err := actionService.Propose(tx, models.ActionLoanOverride, application.ApplicationID,
+			overridePayload{
+				ApplicationID:  application.ApplicationID,
+				ApprovalID:     approval.ApprovalID,
+				ApprovedAmount: approval.ApprovedAmount,
+			}, models.PermissionApplicationOverride, user)
 		if err != nil {
 			tx.Rollback()
-			handle.Status = -5
+			handle.Status = -7
+			handle.Errors = err
+			return
+		}
+		if err = tx.Commit().Error; err != nil {
+			tx.Rollback()
+			handle.Status = -6
 			handle.Errors = err
 			return
 		}
+		response = dto.ApplicationApproveResponse{
+			Status:  3,
+			Message: "Loan application override sent to a checker",
+			Data: dto.ApproveObject{
+				ApplicationID: application.ApplicationID,
+				ApprovalStep:  approval.Step,
+				ActionID:      action.ActionID,
+			},
+		}
+		return
 	}
 
-	err := tx.Save(&application).Error
+	// Approve the application for the amount signed off
+	err = s.approveApplication(tx, &application, request.ApprovedAmount, user.UserID, repaymentSvc)
 	if err != nil {
 		tx.Rollback()
 		handle.Status = -5
@@ -156,12 +181,80 @@ func (s *loanService) ApproveLoanApplication(request dto.ApplicationApproveReque
 		Message: "Loan application approved successfully",
 		Data: dto.ApproveObject{
 			ApplicationID: application.ApplicationID,
+			ApprovalStep:  approval.Step,
 		},
 	}
 
 	return
 }
 
+// approveApplication approves a pending application in the transaction it is locked in. It assigns the virtual
+// account, regenerates the repayment schedule when the amount or the date differs from the application, and posts the
+// disbursement to the general ledger.
+// Parameters:
+// - tx: the transaction the application is locked in
+// - application: the application to approve, updated in place
+// - amount: the approved amount
+// - approverId: the employee completing the approval
+// - repaymentSvc: repaymentService.RepaymentService for generating the repayment schedule
+// Returns:
+// - error when any of the changes could not be saved
+func (s *loanService) approveApplication(tx *gorm.DB, application *models.LoanApplication, amount float64,
+	approverId string, repaymentSvc repaymentService.RepaymentService) error {
+	// Update the application status and approval details
+	application.Status = models.LoanApplicationStatusApproved
+	application.ApprovedAmount = null.FloatFrom(amount)
+	application.ApprovedDate = null.TimeFrom(time.Now())
+
+	// Assign the virtual account the customer transfers repayments to, bank credits to it are matched to this loan
+	if !application.VirtualAccount.Valid {
+		if err := assignVirtualAccount(tx, application); err != nil {
+			return err
+		}
+	}
+
+	// Check if the application needs a new repayment schedule
+	difference := application.ApprovedDate.Time.Sub(application.ApplicationDate)
+
+	// If the approved amount and loan amount not same or loan is approved beyond application date regenerate repayment
+	if ((difference.Hours() / 24) > 1) || amount != application.LoanAmount {
+
+		var repaymentCondition []db.WhereCondition
+		repaymentCondition = append(repaymentCondition, db.WhereCondition{
+			Key:       models.RepaymentColumns.ApplicationID,
+			Condition: "=",
+			Value:     application.ApplicationID,
+		})
+
+		repayment := models.Repayment{}
+		oldRepayment, _ := repayment.FindAllByCondition(repaymentCondition)
+
+		// Delete all the old repayment of the application
+		if len(oldRepayment) > 0 {
+			if err := tx.Delete(&oldRepayment).Error; err != nil {
+				return err
+			}
+		}
+
+		// Generate new repayment for application for approved date
+		repayments, err := repaymentSvc.CalculateRepaymentSchedule(application)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Save(&repayments).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Save(application).Error; err != nil {
+		return err
+	}
+
+	// Record the disbursement of the approved amount in the general ledger
+	return s.ledger.PostDisbursement(tx, *application, approverId)
+}
+
 // GetParticipantApplications retrieves the list of loan applications in which the user is a participant.
 // Parameters:
 // - user: models.User representing the user for whom to retrieve the applications.
@@ -217,29 +310,16 @@ func (s *loanService) GetLoanApplication(applicationId string, user models.User)
 		return
 	}
 
-	var participantCondition, repaymentCondition []db.WhereCondition
-
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.UserID,
-		Condition: "=",
-		Value:     user.UserID,
-	})
-
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.ApplicationID,
-		Condition: "=",
-		Value:     application.ApplicationID,
-	})
-
-	participant := models.LoanApplicationParticipant{}
-	participant, _ = participant.FindOneByCondition(participantCondition)
-
-	if participant.ParticipantID == "" {
+	// Participants read their applications, auditors read any
+	rule := authorizationService.Rule{Oversight: models.PermissionApplicationReadAll}
+	if err := authorizationService.AuthorizeApplication(user, application.ApplicationID, rule); err != nil {
 		handle.Status = -3
-		handle.Errors = fmt.Errorf("dont have permission to this application: %v", application.ApplicationID)
+		handle.Errors = err
 		return
 	}
 
+	var repaymentCondition []db.WhereCondition
+
 	repaymentCondition = append(repaymentCondition, db.WhereCondition{
 		Key:       models.RepaymentColumns.ApplicationID,
 		Condition: "=",
@@ -258,22 +338,43 @@ func (s *loanService) GetLoanApplication(applicationId string, user models.User)
 		Status: 1,
 	}
 	response.Data.LoanApplicationObject = application.GetLoanApplicationDTO()
+	if application.ExcessCredit > 0 {
+		response.Data.ExcessCredit = money.NewFromFloat(application.ExcessCredit, application.CurrencyCode).Display()
+	}
+	if application.Status == models.LoanApplicationStatusApproved {
+		response.Data.AccrualStatus = application.AccrualStatus
+	}
+	if accrued, _ := s.ledger.GetAccruedInterest(application.ApplicationID); accrued > 0 {
+		response.Data.AccruedInterest = money.NewFromFloat(accrued, application.CurrencyCode).Display()
+	}
+	if application.ClosedDate.Valid {
+		response.Data.ClosedDate = application.ClosedDate.Time.Format("2006-01-02")
+	}
+	response.Data.VirtualAccount = application.VirtualAccount.String
 	response.Data.Repayment = repaymentDTOs
 
 	return
 }
 
-// CreateLoanApplication creates a new loan application and its initial repayment schedule
+// CreateLoanApplication creates a new loan application of the signed in customer and its initial repayment schedule
 // Parameters:
-// - request: dto.ApplicationCreateRequest containing the user and loan application details
-// - userSvc: userService.UserService for handling user-related operations
+// - request: dto.ApplicationCreateRequest containing the customer identification and loan application details
+// - user: models.User representing the signed in customer
+// - userSvc: userService.UserService for allocating the employee processing the application
 // - repaymentSvc: repaymentService.RepaymentService for generating the repayment schedule
 // Returns:
 // - dto.ApplicationCreateResponse with the creation result
 // - dto.HandleError with any error that occurred during the process
-func (s *loanService) CreateLoanApplication(request dto.ApplicationCreateRequest,
+func (s *loanService) CreateLoanApplication(request dto.ApplicationCreateRequest, user models.User,
 	userSvc userService.UserService, repaymentSvc repaymentService.RepaymentService) (
 	response dto.ApplicationCreateResponse, handle dto.HandleError) {
+	// Only customers apply for loans, employees process them
+	if user.UserID == "" || user.UserType != constants.UserTypeCustomer {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("only a signed in customer can apply for a loan")
+		return
+	}
+
 	tx := db.MysqlDB.Begin()
 
 	defer func() {
@@ -284,24 +385,6 @@ func (s *loanService) CreateLoanApplication(request dto.ApplicationCreateRequest
 		}
 	}()
 
-	// Check if the user exist
-	user := userSvc.GetApplicationUser(request.User)
-	if user.UserID == "" {
-		user = models.User{
-			UserID:       uuid.New().String(),
-			UserType:     constants.UserTypeCustomer,
-			UserName:     request.User.UserName,
-			UserEmail:    request.User.UserEmail,
-			MobileNumber: request.User.MobileNumber,
-		}
-		if err := tx.Create(&user).Error; err != nil {
-			tx.Rollback()
-			handle.Status = -2
-			handle.Errors = err
-			return
-		}
-	}
-
 	// Insert user identification
 	identification := models.UserKyc{
 		KycID:       uuid.New().String(),
@@ -387,6 +470,16 @@ func (s *loanService) CreateLoanApplication(request dto.ApplicationCreateRequest
 		return
 	}
 
+	// Record who holds the application from now on
+	err = assignmentService.RecordHandover(tx, loanApplication.ApplicationID, "", approveParticipant.UserID, "",
+		assignmentService.ReasonAllocated)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -8
+		handle.Errors = err
+		return
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
 		handle.Status = -9
@@ -476,3 +569,41 @@ func minEligible(a, b float64) float64 {
 	}
 	return b
 }
+
+// assignVirtualAccount stores a new virtual account number on an application. The unique index on the column
+// refuses a number another loan holds, a fresh number is then drawn; a refused statement leaves the transaction
+// usable.
+// Parameters:
+// - tx: the transaction the application is locked in
+// - application: the application to assign the number to, updated in place
+// Returns:
+// - error when no unique number could be stored
+func assignVirtualAccount(tx *gorm.DB, application *models.LoanApplication) error {
+	for attempt := 1; attempt <= maxVirtualAccountAttempts; attempt++ {
+		number, err := virtualAccountNumber()
+		if err != nil {
+			return err
+		}
+		err = tx.Model(application).Update(models.LoanApplicationColumns.VirtualAccount, number).Error
+		if err == nil {
+			application.VirtualAccount = null.StringFrom(number)
+			return nil
+		}
+		if !db.IsDuplicateKey(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("could not assign a unique virtual account after %d attempts", maxVirtualAccountAttempts)
+}
+
+// virtualAccountNumber draws a random virtual account number
+// Returns:
+// - string: the virtual account number, "VA" followed by 12 digits
+// - error when no random number could be read
+func virtualAccountNumber() (string, error) {
+	number, err := rand.Int(rand.Reader, big.NewInt(1000000000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("VA%012d", number), nil
+}
@@ -0,0 +1,21 @@
+package loan_service
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualAccountNumber(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		number, err := virtualAccountNumber()
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^VA[0-9]{12}$`), number)
+		seen[number] = true
+	}
+
+	// Numbers are drawn at random rather than derived from the application, so a clash is not repeated on retry
+	assert.Greater(t, len(seen), 1)
+}
@@ -53,18 +53,33 @@ func (mr *MockLoanServiceMockRecorder) ApproveLoanApplication(params, user, repa
 }
 
 // CreateLoanApplication mocks base method.
-func (m *MockLoanService) CreateLoanApplication(params dto.ApplicationCreateRequest, userSvc user_service.UserService, repaymentSvc repayment_service.RepaymentService) (dto.ApplicationCreateResponse, dto.HandleError) {
+func (m *MockLoanService) CreateLoanApplication(params dto.ApplicationCreateRequest, user models.User, userSvc user_service.UserService, repaymentSvc repayment_service.RepaymentService) (dto.ApplicationCreateResponse, dto.HandleError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLoanApplication", params, userSvc, repaymentSvc)
+	ret := m.ctrl.Call(m, "CreateLoanApplication", params, user, userSvc, repaymentSvc)
 	ret0, _ := ret[0].(dto.ApplicationCreateResponse)
 	ret1, _ := ret[1].(dto.HandleError)
 	return ret0, ret1
 }
 
 // CreateLoanApplication indicates an expected call of CreateLoanApplication.
-func (mr *MockLoanServiceMockRecorder) CreateLoanApplication(params, userSvc, repaymentSvc interface{}) *gomock.Call {
+func (mr *MockLoanServiceMockRecorder) CreateLoanApplication(params, user, userSvc, repaymentSvc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanApplication", reflect.TypeOf((*MockLoanService)(nil).CreateLoanApplication), params, userSvc, repaymentSvc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanApplication", reflect.TypeOf((*MockLoanService)(nil).CreateLoanApplication), params, user, userSvc, repaymentSvc)
+}
+
+// GetEligibilityConfigs mocks base method.
+func (m *MockLoanService) GetEligibilityConfigs() (dto.EligibilityConfigListResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEligibilityConfigs")
+	ret0, _ := ret[0].(dto.EligibilityConfigListResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetEligibilityConfigs indicates an expected call of GetEligibilityConfigs.
+func (mr *MockLoanServiceMockRecorder) GetEligibilityConfigs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEligibilityConfigs", reflect.TypeOf((*MockLoanService)(nil).GetEligibilityConfigs))
 }
 
 // GetLoanApplication mocks base method.
@@ -95,3 +110,18 @@ func (mr *MockLoanServiceMockRecorder) GetParticipantApplications(user interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParticipantApplications", reflect.TypeOf((*MockLoanService)(nil).GetParticipantApplications), user)
 }
+
+// ProposeEligibilityConfig mocks base method.
+func (m *MockLoanService) ProposeEligibilityConfig(request dto.EligibilityConfigRequest, user models.User) (dto.PendingActionResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProposeEligibilityConfig", request, user)
+	ret0, _ := ret[0].(dto.PendingActionResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ProposeEligibilityConfig indicates an expected call of ProposeEligibilityConfig.
+func (mr *MockLoanServiceMockRecorder) ProposeEligibilityConfig(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProposeEligibilityConfig", reflect.TypeOf((*MockLoanService)(nil).ProposeEligibilityConfig), request, user)
+}
@@ -3,16 +3,17 @@ package loan_service
 import (
 	"github.com/nishanthrk/aspire-lms/app/dto"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
 	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
 	userService "github.com/nishanthrk/aspire-lms/app/services/user"
 )
 
 // LoanService defines the interface for loan-related operations
 type LoanService interface {
-	// CreateLoanApplication creates a new loan application
-	CreateLoanApplication(params dto.ApplicationCreateRequest, userSvc userService.UserService,
+	// CreateLoanApplication creates a new loan application of the signed in customer
+	CreateLoanApplication(params dto.ApplicationCreateRequest, user models.User, userSvc userService.UserService,
 		repaymentSvc repaymentService.RepaymentService) (dto.ApplicationCreateResponse, dto.HandleError)
-	// ApproveLoanApplication approves an existing loan application
+	// ApproveLoanApplication signs off an existing loan application, approving it once the approval chain completes
 	ApproveLoanApplication(params dto.ApplicationApproveRequest, user models.User,
 		repaymentSvc repaymentService.RepaymentService) (dto.ApplicationApproveResponse, dto.HandleError)
 	// GetLoanApplication retrieves the details of an existing loan application
@@ -20,12 +21,22 @@ type LoanService interface {
 
 	// GetParticipantApplications retrieves the applications of participant
 	GetParticipantApplications(user models.User) dto.ApplicationListResponse
+
+	// GetEligibilityConfigs retrieves the eligibility configs of every country
+	GetEligibilityConfigs() (dto.EligibilityConfigListResponse, dto.HandleError)
+	// ProposeEligibilityConfig proposes an edit of an eligibility config, applied once a checker approves it
+	ProposeEligibilityConfig(request dto.EligibilityConfigRequest, user models.User) (dto.PendingActionResponse,
+		dto.HandleError)
 }
 
 // loanService is the implementation of the LoanService interface
-type loanService struct{}
+type loanService struct {
+	ledger ledgerService.LedgerService
+}
 
 // NewLoanService returns a new instance of LoanService
-func NewLoanService() LoanService {
-	return &loanService{}
+func NewLoanService(ledger ledgerService.LedgerService) LoanService {
+	return &loanService{
+		ledger: ledger,
+	}
 }
@@ -0,0 +1,487 @@
+package mandate_service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	debitService "github.com/nishanthrk/aspire-lms/app/services/debit"
+	"gorm.io/gorm/clause"
+)
+
+// RetryPolicy decides whether and when a failed debit is presented again. The first retry waits Interval
+// and every further retry waits twice as long as the one before; at most MaxAttempts debits, the first
+// one included, are raised for an installment.
+type RetryPolicy struct {
+	MaxAttempts int
+	Interval    time.Duration
+}
+
+// NextRetry returns when a failed attempt is presented again, an invalid time when it is not retried
+// Parameters:
+// - attempt: the number of the attempt that failed, starting at 1
+// - failureCode: the failure code reported for the attempt
+// - failedAt: when the failure was recorded
+// Returns:
+// - null.Time with the retry time, invalid when the attempts are exhausted or the failure is permanent
+func (p RetryPolicy) NextRetry(attempt int, failureCode string, failedAt time.Time) null.Time {
+	if attempt >= p.MaxAttempts || !isRetryableFailure(failureCode) {
+		return null.Time{}
+	}
+	return null.TimeFrom(failedAt.Add(p.Interval * time.Duration(int64(1)<<uint(attempt-1))))
+}
+
+// isRetryableFailure reports whether presenting the debit again can succeed. Funds may arrive later, but a
+// revoked mandate or a closed account will not recover on their own.
+func isRetryableFailure(failureCode string) bool {
+	switch failureCode {
+	case debitService.FailureCodeMaxAmountExceeded, debitService.FailureCodeMandateRevoked,
+		debitService.FailureCodeAccountClosed, debitService.FailureCodeInvalidAccount:
+		return false
+	}
+	return true
+}
+
+// suspendsMandate reports whether a failure means the mandate itself can no longer be debited
+func suspendsMandate(failureCode string) bool {
+	switch failureCode {
+	case debitService.FailureCodeMandateRevoked, debitService.FailureCodeAccountClosed,
+		debitService.FailureCodeInvalidAccount:
+		return true
+	}
+	return false
+}
+
+// HandleDebitCallback applies a collection outcome pushed by the debit provider
+// Parameters:
+// - request: dto.DebitCallbackRequest containing the collection reference, outcome and failure details
+// - payload: the raw request body the signature was computed over
+// - signature: the signature sent by the provider
+// Returns:
+// - dto.DebitCallbackResponse with the result
+// - dto.HandleError with any error that occurred during the process
+func (s *mandateService) HandleDebitCallback(request dto.DebitCallbackRequest, payload []byte, signature string) (
+	response dto.DebitCallbackResponse, handle dto.HandleError) {
+	// Reject anything not signed by the provider
+	if !s.provider.VerifySignature(payload, signature) {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("invalid callback signature")
+		return
+	}
+
+	applied, handle := s.recordDebitResult(request, time.Now())
+	if handle.Status < 0 {
+		return
+	}
+
+	response.Status = 1
+	if applied {
+		response.Message = fmt.Sprintf("Debit marked as %v", request.Status)
+	} else {
+		response.Message = "Debit already processed"
+	}
+	return
+}
+
+// RunCollections is one scheduler cycle. It applies the outcomes the provider published, expires mandates
+// past their end date, raises a debit for every installment that fell due under an active mandate and
+// presents failed debits again once their retry time has come. Every step is safe to run concurrently
+// and again, so a missed or overlapping cycle only delays collections.
+// Parameters:
+// - now: the time the cycle runs at
+// Returns:
+// - dto.CollectionRunResponse with what the cycle did
+// - dto.HandleError with any error that occurred during the process
+func (s *mandateService) RunCollections(now time.Time) (response dto.CollectionRunResponse, handle dto.HandleError) {
+	// Outcomes the provider published since the last cycle
+	results, err := s.provider.FetchResults()
+	if err != nil {
+		logger.Sugar.Error("could not fetch debit results: ", err)
+	}
+	for _, result := range results {
+		if _, resultHandle := s.recordDebitResult(result, now); resultHandle.Status < 0 {
+			logger.Sugar.Error("could not apply debit result ", result.Reference, ": ", resultHandle.Errors)
+			continue
+		}
+		if err = s.provider.AcknowledgeResult(result.Reference); err != nil {
+			logger.Sugar.Error("could not acknowledge debit result ", result.Reference, ": ", err)
+		}
+		response.Data.ResultsApplied++
+	}
+
+	var mandateCondition []db.WhereCondition
+	mandateCondition = append(mandateCondition, db.WhereCondition{
+		Key:       models.DebitMandateColumns.Status,
+		Condition: "=",
+		Value:     models.MandateStatusActive,
+	})
+
+	mandate := models.DebitMandate{}
+	mandates, err := mandate.FindAllByCondition(mandateCondition)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	for _, mandate := range mandates {
+		if mandate.EndDate.Format(mandateDateLayout) < now.Format(mandateDateLayout) {
+			mandate.Status = models.MandateStatusExpired
+			if err = db.MysqlDB.Omit(clause.Associations).Save(&mandate).Error; err != nil {
+				logger.Sugar.Error("could not expire mandate ", mandate.MandateID, ": ", err)
+				continue
+			}
+			response.Data.MandatesExpired++
+			continue
+		}
+
+		application := models.LoanApplication{}
+		application, _ = application.FindByPrimaryKey(mandate.ApplicationID)
+		if application.Status != models.LoanApplicationStatusApproved {
+			continue
+		}
+
+		for _, repayment := range pendingInstallments(mandate.ApplicationID, now) {
+			if !mandate.IsValidOn(repayment.InstallmentDate) || hasCollection(repayment.RepaymentID) {
+				continue
+			}
+			collection, raiseErr := s.raiseCollection(mandate, repayment, 1, now)
+			if raiseErr != nil {
+				logger.Sugar.Error("could not raise debit for repayment ", repayment.RepaymentID, ": ", raiseErr)
+				continue
+			}
+			countCollection(&response, collection)
+		}
+	}
+
+	// Failed debits whose retry time has come
+	var retryCondition []db.WhereCondition
+	retryCondition = append(retryCondition, db.WhereCondition{
+		Key:       models.DebitCollectionColumns.Status,
+		Condition: "=",
+		Value:     models.CollectionStatusFailed,
+	})
+	retryCondition = append(retryCondition, db.WhereCondition{
+		Key:       models.DebitCollectionColumns.NextRetryAt,
+		Condition: "<=",
+		Value:     now,
+	})
+
+	failed := models.DebitCollection{}
+	retries, _ := failed.FindAllByCondition(retryCondition)
+	for _, failed := range retries {
+		if !claimRetry(failed.CollectionID, now) {
+			continue
+		}
+
+		mandate, _ = mandate.FindByPrimaryKey(failed.MandateID)
+		repayment := models.Repayment{}
+		repayment, _ = repayment.FindByPrimaryKey(failed.RepaymentID)
+		if !mandate.IsValidOn(now) || repayment.Status != models.LoanApplicationStatusPending {
+			continue
+		}
+
+		collection, raiseErr := s.raiseCollection(mandate, repayment, failed.Attempt+1, now)
+		if raiseErr != nil {
+			logger.Sugar.Error("could not retry debit ", failed.CollectionID, ": ", raiseErr)
+			continue
+		}
+		countCollection(&response, collection)
+	}
+
+	response.Status = 1
+	response.Message = "Debit collections processed"
+	return
+}
+
+// raiseCollection presents an installment for debit against the mandate. The collection row is inserted
+// before the provider is called, so the unique index on repayment and attempt lets only one cycle raise an
+// attempt. Once the provider accepted the debit its reference is stored on the collection, then a pending payment
+// is recorded for it, settled or failed when the outcome is reported.
+// Parameters:
+// - mandate: the mandate to debit
+// - repayment: the installment to collect
+// - attempt: the attempt number, 1 for the first presentation
+// - now: the time the debit is raised at
+// Returns:
+// - models.DebitCollection with the raised collection
+// - error when the collection could not be recorded
+func (s *mandateService) raiseCollection(mandate models.DebitMandate, repayment models.Repayment, attempt int,
+	now time.Time) (collection models.DebitCollection, err error) {
+	collection = models.DebitCollection{
+		CollectionID:  uuid.New().String(),
+		MandateID:     mandate.MandateID,
+		ApplicationID: mandate.ApplicationID,
+		RepaymentID:   repayment.RepaymentID,
+		Attempt:       attempt,
+		Amount:        remainingAmount(repayment),
+		CurrencyCode:  mandate.CurrencyCode,
+		Status:        models.CollectionStatusPending,
+	}
+
+	// The customer did not authorise debits this large, record the failure without retrying
+	if collection.Amount > mandate.MaxAmount {
+		collection.Status = models.CollectionStatusFailed
+		collection.FailureCode = null.StringFrom(debitService.FailureCodeMaxAmountExceeded)
+		collection.FailureReason = null.StringFrom(fmt.Sprintf("installment amount %v exceeds the mandate limit of %v",
+			collection.Amount, mandate.MaxAmount))
+		err = db.MysqlDB.Omit(clause.Associations).Create(&collection).Error
+		return
+	}
+
+	if err = db.MysqlDB.Omit(clause.Associations).Create(&collection).Error; err != nil {
+		return
+	}
+
+	reference, providerErr := s.provider.CollectDebit(dto.DebitCollectionRequest{
+		CollectionID:     collection.CollectionID,
+		MandateReference: mandate.ProviderReference,
+		CurrencyCode:     collection.CurrencyCode,
+		Amount:           collection.Amount,
+		DueDate:          repayment.InstallmentDate.Format(mandateDateLayout),
+		Attempt:          attempt,
+	})
+	if providerErr != nil {
+		collection.Status = models.CollectionStatusFailed
+		collection.FailureCode = null.StringFrom(debitService.FailureCodeProviderError)
+		collection.FailureReason = null.StringFrom(providerErr.Error())
+		collection.NextRetryAt = s.retryPolicy.NextRetry(attempt, debitService.FailureCodeProviderError, now)
+		err = db.MysqlDB.Omit(clause.Associations).Save(&collection).Error
+		return
+	}
+
+	// The reference is stored on its own first, so that the outcome of the accepted debit can still be matched to
+	// the collection when recording its payment fails
+	collection.ProviderReference = null.StringFrom(reference)
+	if err = db.MysqlDB.Omit(clause.Associations).Save(&collection).Error; err != nil {
+		err = fmt.Errorf("debit %v was submitted but could not be recorded: %v", reference, err)
+		return
+	}
+
+	submitted, err := submitCollection(collection.CollectionID)
+	if err != nil {
+		err = fmt.Errorf("debit %v was submitted but its payment could not be recorded, it is recorded when the "+
+			"outcome arrives: %v", reference, err)
+		return
+	}
+	return submitted, nil
+}
+
+// submitCollection records the pending payment of a debit the provider accepted and marks its collection submitted.
+// The collection is locked and left alone once submitted, so the payment is recorded once whether by the cycle that
+// raised the debit or by the outcome arriving after the cycle failed to record it.
+// Parameters:
+// - collectionId: the collection the provider accepted, with its provider reference stored
+// Returns:
+// - models.DebitCollection with the submitted collection
+// - error when the payment or the collection could not be saved
+func submitCollection(collectionId string) (collection models.DebitCollection, err error) {
+	tx := db.MysqlDB.Begin()
+
+	collection, err = collection.FindByPrimaryKeyForUpdate(tx, collectionId)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	if collection.Status != models.CollectionStatusPending || !collection.ProviderReference.Valid {
+		tx.Rollback()
+		return
+	}
+
+	payment := models.Payment{
+		PaymentID:        uuid.New().String(),
+		Amount:           collection.Amount,
+		ApplicationID:    collection.ApplicationID,
+		CurrencyCode:     collection.CurrencyCode,
+		Status:           models.PaymentStatusPending,
+		Gateway:          null.StringFrom(models.PaymentChannelDirectDebit),
+		GatewayReference: collection.ProviderReference,
+	}
+
+	collection.Status = models.CollectionStatusSubmitted
+	collection.PaymentID = null.StringFrom(payment.PaymentID)
+
+	if err = tx.Omit(clause.Associations).Create(&payment).Error; err != nil {
+		tx.Rollback()
+		return
+	}
+	if err = tx.Omit(clause.Associations).Save(&collection).Error; err != nil {
+		tx.Rollback()
+		return
+	}
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+	}
+	return
+}
+
+// recordDebitResult applies a collection outcome. The payment is settled or failed first, which is
+// idempotent, so an outcome delivered again after a crash completes the collection update that was missed.
+// Parameters:
+// - result: dto.DebitCallbackRequest with the outcome reported by the provider
+// - now: the time the outcome is recorded at, the base for the next retry
+// Returns:
+// - bool reporting whether the collection changed, false when it had already been processed
+// - dto.HandleError with any error that occurred during the process
+func (s *mandateService) recordDebitResult(result dto.DebitCallbackRequest, now time.Time) (
+	applied bool, handle dto.HandleError) {
+	var collectionCondition []db.WhereCondition
+	collectionCondition = append(collectionCondition, db.WhereCondition{
+		Key:       models.DebitCollectionColumns.ProviderReference,
+		Condition: "=",
+		Value:     result.Reference,
+	})
+
+	collection := models.DebitCollection{}
+	collection, _ = collection.FindOneByCondition(collectionCondition)
+	if collection.CollectionID == "" {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("debit %v not found", result.Reference)
+		return
+	}
+
+	// The debit was accepted but its payment could not be recorded when it was raised, record it before the outcome
+	if collection.Status == models.CollectionStatusPending {
+		submitted, err := submitCollection(collection.CollectionID)
+		if err != nil {
+			handle.Status = -7
+			handle.Errors = err
+			return
+		}
+		collection = submitted
+	}
+
+	if collection.Status != models.CollectionStatusSubmitted {
+		return
+	}
+
+	failureCode := result.FailureCode
+	if result.Status == models.PaymentStatusFailed && failureCode == "" {
+		failureCode = debitService.FailureCodeProviderError
+	}
+	failureReason := result.FailureReason
+	if failureReason == "" {
+		failureReason = failureCode
+	}
+
+	_, handle = s.repaymentSvc.ApplyPaymentStatus(dto.PaymentStatusRequest{
+		PaymentID:     collection.PaymentID.String,
+		Status:        result.Status,
+		FailureReason: failureReason,
+	})
+	if handle.Status < 0 {
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	collection, err := collection.FindByPrimaryKeyForUpdate(tx, collection.CollectionID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	// A concurrent delivery of the same outcome got here first
+	if collection.Status != models.CollectionStatusSubmitted {
+		tx.Rollback()
+		return
+	}
+
+	if result.Status == models.PaymentStatusSettled {
+		collection.Status = models.CollectionStatusSucceeded
+	} else {
+		collection.Status = models.CollectionStatusFailed
+		collection.FailureCode = null.StringFrom(failureCode)
+		collection.FailureReason = null.StringFrom(failureReason)
+		collection.NextRetryAt = s.retryPolicy.NextRetry(collection.Attempt, failureCode, now)
+	}
+	if err = tx.Omit(clause.Associations).Save(&collection).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	// Stop debiting an account the bank reports as unusable
+	if collection.Status == models.CollectionStatusFailed && suspendsMandate(failureCode) {
+		mandate := models.DebitMandate{}
+		mandate, _ = mandate.FindByPrimaryKey(collection.MandateID)
+		if mandate.Status == models.MandateStatusActive {
+			mandate.Status = models.MandateStatusSuspended
+			if err = tx.Omit(clause.Associations).Save(&mandate).Error; err != nil {
+				tx.Rollback()
+				handle.Status = -5
+				handle.Errors = err
+				return
+			}
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	applied = true
+	return
+}
+
+// claimRetry clears the retry time of a failed collection under a row lock, so only one cycle presents it again
+func claimRetry(collectionId string, now time.Time) bool {
+	tx := db.MysqlDB.Begin()
+
+	collection := models.DebitCollection{}
+	collection, err := collection.FindByPrimaryKeyForUpdate(tx, collectionId)
+	if err != nil || !collection.NextRetryAt.Valid || collection.NextRetryAt.Time.After(now) {
+		tx.Rollback()
+		return false
+	}
+
+	collection.NextRetryAt = null.Time{}
+	if err = tx.Omit(clause.Associations).Save(&collection).Error; err != nil {
+		tx.Rollback()
+		return false
+	}
+	return tx.Commit().Error == nil
+}
+
+// hasCollection reports whether a debit was already raised for the installment
+func hasCollection(repaymentId string) bool {
+	var collectionCondition []db.WhereCondition
+	collectionCondition = append(collectionCondition, db.WhereCondition{
+		Key:       models.DebitCollectionColumns.RepaymentID,
+		Condition: "=",
+		Value:     repaymentId,
+	})
+
+	collection := models.DebitCollection{}
+	collection, _ = collection.FindOneByCondition(collectionCondition)
+	return collection.CollectionID != ""
+}
+
+// countCollection adds a raised collection to the cycle summary
+func countCollection(response *dto.CollectionRunResponse, collection models.DebitCollection) {
+	switch {
+	case collection.Status == models.CollectionStatusFailed:
+		response.Data.CollectionsFailed++
+	case collection.Attempt > 1:
+		response.Data.RetriesRaised++
+	default:
+		response.Data.CollectionsRaised++
+	}
+}
+
+// roundAmount rounds an amount to two decimal places
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,336 @@
+package mandate_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm/clause"
+)
+
+// mandateDateLayout is the layout of the mandate validity dates
+const mandateDateLayout = "2006-01-02"
+
+// RegisterMandate registers a debit mandate for a loan application. The mandate is registered with the debit
+// provider first and only stored once the provider accepted it. A loan can have a single active mandate.
+// Parameters:
+// - request: dto.MandateCreateRequest containing the bank account, max amount, frequency and validity
+// - user: models.User representing the customer granting the mandate
+// Returns:
+// - dto.MandateResponse with the registered mandate
+// - dto.HandleError with any error that occurred during the process
+func (s *mandateService) RegisterMandate(request dto.MandateCreateRequest, user models.User) (
+	response dto.MandateResponse, handle dto.HandleError) {
+	application := models.LoanApplication{}
+	application, _ = application.FindByPrimaryKey(request.ApplicationID)
+	if application.ApplicationID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	// Only the borrower can authorise debits from their account
+	rule := authorizationService.Rule{ParticipantType: constants.UserTypeCustomer}
+	if err := authorizationService.AuthorizeApplication(user, application.ApplicationID, rule); err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	if application.Status == models.LoanApplicationStatusPaid ||
+		application.Status == models.LoanApplicationStatusClosed ||
+		application.Status == models.LoanApplicationStatusWrittenOff {
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("application is %v and has nothing left to collect", application.Status)
+		return
+	}
+
+	if !frequencyMatchesTerm(request.Frequency, application.LoanTermUnit) {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("mandate frequency %v does not match the %v repayment schedule",
+			request.Frequency, application.LoanTermUnit)
+		return
+	}
+
+	startDate, _ := time.Parse(mandateDateLayout, request.StartDate)
+	endDate, _ := time.Parse(mandateDateLayout, request.EndDate)
+	if endDate.Before(startDate) {
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("mandate end date %v is before its start date %v", request.EndDate, request.StartDate)
+		return
+	}
+	if endDate.Format(mandateDateLayout) < time.Now().Format(mandateDateLayout) {
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("mandate end date %v is in the past", request.EndDate)
+		return
+	}
+
+	// The mandate must allow the largest installment to be debited in one go
+	largest := largestInstallment(pendingInstallments(application.ApplicationID, time.Time{}))
+	if request.MaxAmount < largest {
+		handle.Status = -6
+		handle.Errors = fmt.Errorf("max amount must cover the installment amount of %v",
+			money.NewFromFloat(largest, application.CurrencyCode).Display())
+		return
+	}
+
+	existing := findActiveMandate(application.ApplicationID)
+	if existing.MandateID != "" {
+		handle.Status = -7
+		handle.Errors = fmt.Errorf("application already has the active mandate %v, cancel it first", existing.MandateID)
+		return
+	}
+
+	mandate := models.DebitMandate{
+		MandateID:         uuid.New().String(),
+		ApplicationID:     application.ApplicationID,
+		UserID:            user.UserID,
+		AccountHolderName: request.AccountHolderName,
+		BankAccountNumber: strings.ToUpper(request.BankAccountNumber),
+		BankCode:          strings.ToUpper(request.BankCode),
+		CurrencyCode:      application.CurrencyCode,
+		MaxAmount:         request.MaxAmount,
+		Frequency:         request.Frequency,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Status:            models.MandateStatusActive,
+		Provider:          s.provider.Name(),
+	}
+
+	reference, err := s.provider.RegisterMandate(dto.MandateRegistration{
+		MandateID:         mandate.MandateID,
+		ApplicationID:     mandate.ApplicationID,
+		AccountHolderName: mandate.AccountHolderName,
+		BankAccountNumber: mandate.BankAccountNumber,
+		BankCode:          mandate.BankCode,
+		CurrencyCode:      mandate.CurrencyCode,
+		MaxAmount:         mandate.MaxAmount,
+		Frequency:         mandate.Frequency,
+		StartDate:         request.StartDate,
+		EndDate:           request.EndDate,
+	})
+	if err != nil {
+		handle.Status = -8
+		handle.Errors = fmt.Errorf("debit provider rejected the mandate: %v", err)
+		return
+	}
+	mandate.ProviderReference = reference
+
+	if err = db.MysqlDB.Omit(clause.Associations).Create(&mandate).Error; err != nil {
+		handle.Status = -9
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Debit mandate registered successfully"
+	response.Data = toMandateObject(mandate, nil)
+	return
+}
+
+// GetMandate returns the most recent mandate of a loan application with all its collection attempts
+// Parameters:
+// - request: dto.MandateDetailsRequest containing the application ID
+// - user: models.User representing a participant of the application
+// Returns:
+// - dto.MandateResponse with the mandate and its collections
+// - dto.HandleError with any error that occurred during the process
+func (s *mandateService) GetMandate(request dto.MandateDetailsRequest, user models.User) (
+	response dto.MandateResponse, handle dto.HandleError) {
+	// Participants read the mandate of their applications, auditors of any
+	rule := authorizationService.Rule{Oversight: models.PermissionApplicationReadAll}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	var mandateCondition []db.WhereCondition
+	mandateCondition = append(mandateCondition, db.WhereCondition{
+		Key:       models.DebitMandateColumns.ApplicationID,
+		Condition: "=",
+		Value:     request.ApplicationID,
+	})
+
+	mandate := models.DebitMandate{}
+	mandates, _ := mandate.FindAllByCondition(mandateCondition)
+	if len(mandates) == 0 {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application %v has no debit mandate", request.ApplicationID)
+		return
+	}
+	mandate = mandates[len(mandates)-1]
+
+	var collectionCondition []db.WhereCondition
+	collectionCondition = append(collectionCondition, db.WhereCondition{
+		Key:       models.DebitCollectionColumns.MandateID,
+		Condition: "=",
+		Value:     mandate.MandateID,
+	})
+
+	collection := models.DebitCollection{}
+	collections, _ := collection.FindAllByCondition(collectionCondition)
+
+	response.Status = 1
+	response.Message = "Debit mandate retrieved successfully"
+	response.Data = toMandateObject(mandate, collections)
+	return
+}
+
+// CancelMandate revokes the active mandate of a loan application at the provider and locally. Debits
+// already submitted still settle or fail as reported, but no new debit or retry is raised.
+// Parameters:
+// - request: dto.MandateCancelRequest containing the application ID
+// - user: models.User representing a participant of the application
+// Returns:
+// - dto.MandateResponse with the cancelled mandate
+// - dto.HandleError with any error that occurred during the process
+func (s *mandateService) CancelMandate(request dto.MandateCancelRequest, user models.User) (
+	response dto.MandateResponse, handle dto.HandleError) {
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID,
+		authorizationService.Rule{}); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	mandate := findActiveMandate(request.ApplicationID)
+	if mandate.MandateID == "" {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application %v has no active debit mandate", request.ApplicationID)
+		return
+	}
+
+	if err := s.provider.CancelMandate(mandate.ProviderReference); err != nil {
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("debit provider could not cancel the mandate: %v", err)
+		return
+	}
+
+	mandate.Status = models.MandateStatusCancelled
+	mandate.CancelledBy = null.StringFrom(user.UserID)
+	mandate.CancelledAt = null.TimeFrom(time.Now())
+	if err := db.MysqlDB.Omit(clause.Associations).Save(&mandate).Error; err != nil {
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Debit mandate cancelled successfully"
+	response.Data = toMandateObject(mandate, nil)
+	return
+}
+
+// findActiveMandate returns the active mandate of a loan application, empty when there is none
+func findActiveMandate(applicationId string) models.DebitMandate {
+	var mandateCondition []db.WhereCondition
+	mandateCondition = append(mandateCondition, db.WhereCondition{
+		Key:       models.DebitMandateColumns.ApplicationID,
+		Condition: "=",
+		Value:     applicationId,
+	})
+	mandateCondition = append(mandateCondition, db.WhereCondition{
+		Key:       models.DebitMandateColumns.Status,
+		Condition: "=",
+		Value:     models.MandateStatusActive,
+	})
+
+	mandate := models.DebitMandate{}
+	mandate, _ = mandate.FindOneByCondition(mandateCondition)
+	return mandate
+}
+
+// pendingInstallments returns the unpaid installments of a loan application in due date order, limited to
+// those due by the given time unless it is zero
+func pendingInstallments(applicationId string, dueBy time.Time) []models.Repayment {
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     applicationId,
+	})
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusPending,
+	})
+	if !dueBy.IsZero() {
+		repaymentCondition = append(repaymentCondition, db.WhereCondition{
+			Key:       models.RepaymentColumns.InstallmentDate,
+			Condition: "<=",
+			Value:     dueBy,
+		})
+	}
+
+	repayment := models.Repayment{}
+	repayments, _ := repayment.FindAllByCondition(repaymentCondition)
+	return repayments
+}
+
+// frequencyMatchesTerm reports whether a mandate frequency fits the repayment schedule of the loan, a
+// mandate allowing debits as presented fits any schedule
+func frequencyMatchesTerm(frequency string, loanTermUnit string) bool {
+	return frequency == models.MandateFrequencyAsPresented || strings.EqualFold(frequency, loanTermUnit)
+}
+
+// largestInstallment returns the largest amount still owed on a single installment
+func largestInstallment(repayments []models.Repayment) (largest float64) {
+	for _, repayment := range repayments {
+		if owed := remainingAmount(repayment); owed > largest {
+			largest = owed
+		}
+	}
+	return
+}
+
+// remainingAmount returns what is still owed on an installment, rounded to the minor unit
+func remainingAmount(repayment models.Repayment) float64 {
+	return roundAmount(repayment.AmountDue - repayment.AmountPaid)
+}
+
+// toMandateObject converts a mandate and its collections into the response object, masking the account number
+func toMandateObject(mandate models.DebitMandate, collections []models.DebitCollection) dto.MandateObject {
+	object := dto.MandateObject{
+		MandateId:         mandate.MandateID,
+		ApplicationId:     mandate.ApplicationID,
+		AccountHolderName: mandate.AccountHolderName,
+		BankAccountNumber: mandate.MaskedAccountNumber(),
+		BankCode:          mandate.BankCode,
+		CurrencyCode:      mandate.CurrencyCode,
+		MaxAmount:         money.NewFromFloat(mandate.MaxAmount, mandate.CurrencyCode).Display(),
+		Frequency:         mandate.Frequency,
+		StartDate:         mandate.StartDate.Format(mandateDateLayout),
+		EndDate:           mandate.EndDate.Format(mandateDateLayout),
+		Status:            mandate.Status,
+		Provider:          mandate.Provider,
+		ProviderReference: mandate.ProviderReference,
+	}
+
+	for _, collection := range collections {
+		item := dto.DebitCollectionObject{
+			CollectionId:  collection.CollectionID,
+			RepaymentId:   collection.RepaymentID,
+			PaymentId:     collection.PaymentID.String,
+			Attempt:       collection.Attempt,
+			Amount:        money.NewFromFloat(collection.Amount, collection.CurrencyCode).Display(),
+			Status:        collection.Status,
+			FailureCode:   collection.FailureCode.String,
+			FailureReason: collection.FailureReason.String,
+			CreatedAt:     collection.CreatedAt.Format(time.RFC3339),
+		}
+		if collection.NextRetryAt.Valid {
+			item.NextRetryAt = collection.NextRetryAt.Time.Format(time.RFC3339)
+		}
+		object.Collections = append(object.Collections, item)
+	}
+	return object
+}
@@ -0,0 +1,93 @@
+package mandate_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	debitService "github.com/nishanthrk/aspire-lms/app/services/debit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_NextRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Interval: 24 * time.Hour}
+	failedAt := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, null.TimeFrom(failedAt.Add(24*time.Hour)),
+		policy.NextRetry(1, debitService.FailureCodeInsufficientFunds, failedAt))
+	assert.Equal(t, null.TimeFrom(failedAt.Add(48*time.Hour)),
+		policy.NextRetry(2, debitService.FailureCodeProviderError, failedAt))
+
+	// Attempts exhausted
+	assert.False(t, policy.NextRetry(3, debitService.FailureCodeInsufficientFunds, failedAt).Valid)
+
+	// Permanent failures are never retried
+	assert.False(t, policy.NextRetry(1, debitService.FailureCodeMandateRevoked, failedAt).Valid)
+	assert.False(t, policy.NextRetry(1, debitService.FailureCodeMaxAmountExceeded, failedAt).Valid)
+}
+
+func TestSuspendsMandate(t *testing.T) {
+	assert.True(t, suspendsMandate(debitService.FailureCodeAccountClosed))
+	assert.True(t, suspendsMandate(debitService.FailureCodeMandateRevoked))
+	assert.False(t, suspendsMandate(debitService.FailureCodeInsufficientFunds))
+	assert.False(t, suspendsMandate(debitService.FailureCodeMaxAmountExceeded))
+}
+
+func TestFrequencyMatchesTerm(t *testing.T) {
+	assert.True(t, frequencyMatchesTerm(models.MandateFrequencyMonthly, "MONTHLY"))
+	assert.True(t, frequencyMatchesTerm(models.MandateFrequencyWeekly, "weekly"))
+	assert.True(t, frequencyMatchesTerm(models.MandateFrequencyAsPresented, "WEEKLY"))
+	assert.False(t, frequencyMatchesTerm(models.MandateFrequencyWeekly, "MONTHLY"))
+}
+
+func TestLargestInstallment(t *testing.T) {
+	assert.Equal(t, 750.5, largestInstallment([]models.Repayment{
+		{AmountDue: 1000, AmountPaid: 400},
+		{AmountDue: 750.5},
+		{AmountDue: 500},
+	}))
+	assert.Equal(t, float64(0), largestInstallment(nil))
+}
+
+func TestCountCollection(t *testing.T) {
+	response := dto.CollectionRunResponse{}
+	countCollection(&response, models.DebitCollection{Attempt: 1, Status: models.CollectionStatusSubmitted})
+	countCollection(&response, models.DebitCollection{Attempt: 2, Status: models.CollectionStatusSubmitted})
+	countCollection(&response, models.DebitCollection{Attempt: 1, Status: models.CollectionStatusFailed})
+
+	assert.Equal(t, 1, response.Data.CollectionsRaised)
+	assert.Equal(t, 1, response.Data.RetriesRaised)
+	assert.Equal(t, 1, response.Data.CollectionsFailed)
+}
+
+func TestToMandateObject(t *testing.T) {
+	mandate := models.DebitMandate{
+		MandateID:         "mandate_id",
+		BankAccountNumber: "123456789012",
+		CurrencyCode:      "INR",
+		MaxAmount:         5000,
+		StartDate:         time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:           time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		Status:            models.MandateStatusActive,
+	}
+	retryAt := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+
+	object := toMandateObject(mandate, []models.DebitCollection{{
+		CollectionID: "collection_id",
+		Attempt:      1,
+		Amount:       1250.5,
+		CurrencyCode: "INR",
+		Status:       models.CollectionStatusFailed,
+		FailureCode:  null.StringFrom(debitService.FailureCodeInsufficientFunds),
+		NextRetryAt:  null.TimeFrom(retryAt),
+	}})
+
+	assert.Equal(t, "XXXX9012", object.BankAccountNumber)
+	assert.Equal(t, "2024-05-01", object.StartDate)
+	assert.Equal(t, "2025-05-01", object.EndDate)
+	assert.Len(t, object.Collections, 1)
+	assert.Equal(t, debitService.FailureCodeInsufficientFunds, object.Collections[0].FailureCode)
+	assert.Equal(t, "2024-05-03T09:00:00Z", object.Collections[0].NextRetryAt)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/mandate/service.go
+
+// Package mandate_service is a generated GoMock package.
+package mandate_service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockMandateService is a mock of MandateService interface.
+type MockMandateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMandateServiceMockRecorder
+}
+
+// MockMandateServiceMockRecorder is the mock recorder for MockMandateService.
+type MockMandateServiceMockRecorder struct {
+	mock *MockMandateService
+}
+
+// NewMockMandateService creates a new mock instance.
+func NewMockMandateService(ctrl *gomock.Controller) *MockMandateService {
+	mock := &MockMandateService{ctrl: ctrl}
+	mock.recorder = &MockMandateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMandateService) EXPECT() *MockMandateServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelMandate mocks base method.
+func (m *MockMandateService) CancelMandate(request dto.MandateCancelRequest, user models.User) (dto.MandateResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelMandate", request, user)
+	ret0, _ := ret[0].(dto.MandateResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// CancelMandate indicates an expected call of CancelMandate.
+func (mr *MockMandateServiceMockRecorder) CancelMandate(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelMandate", reflect.TypeOf((*MockMandateService)(nil).CancelMandate), request, user)
+}
+
+// GetMandate mocks base method.
+func (m *MockMandateService) GetMandate(request dto.MandateDetailsRequest, user models.User) (dto.MandateResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMandate", request, user)
+	ret0, _ := ret[0].(dto.MandateResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetMandate indicates an expected call of GetMandate.
+func (mr *MockMandateServiceMockRecorder) GetMandate(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMandate", reflect.TypeOf((*MockMandateService)(nil).GetMandate), request, user)
+}
+
+// HandleDebitCallback mocks base method.
+func (m *MockMandateService) HandleDebitCallback(request dto.DebitCallbackRequest, payload []byte, signature string) (dto.DebitCallbackResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleDebitCallback", request, payload, signature)
+	ret0, _ := ret[0].(dto.DebitCallbackResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// HandleDebitCallback indicates an expected call of HandleDebitCallback.
+func (mr *MockMandateServiceMockRecorder) HandleDebitCallback(request, payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleDebitCallback", reflect.TypeOf((*MockMandateService)(nil).HandleDebitCallback), request, payload, signature)
+}
+
+// RegisterMandate mocks base method.
+func (m *MockMandateService) RegisterMandate(request dto.MandateCreateRequest, user models.User) (dto.MandateResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterMandate", request, user)
+	ret0, _ := ret[0].(dto.MandateResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RegisterMandate indicates an expected call of RegisterMandate.
+func (mr *MockMandateServiceMockRecorder) RegisterMandate(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMandate", reflect.TypeOf((*MockMandateService)(nil).RegisterMandate), request, user)
+}
+
+// RunCollections mocks base method.
+func (m *MockMandateService) RunCollections(now time.Time) (dto.CollectionRunResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunCollections", now)
+	ret0, _ := ret[0].(dto.CollectionRunResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RunCollections indicates an expected call of RunCollections.
+func (mr *MockMandateServiceMockRecorder) RunCollections(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCollections", reflect.TypeOf((*MockMandateService)(nil).RunCollections), now)
+}
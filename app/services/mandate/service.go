@@ -0,0 +1,45 @@
+package mandate_service
+
+import (
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	debitService "github.com/nishanthrk/aspire-lms/app/services/debit"
+	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
+)
+
+// MandateService defines the interface for recurring debit mandates and their collections
+type MandateService interface {
+	// RegisterMandate Registers a debit mandate authorising the lender to collect installments from the customer's account
+	RegisterMandate(request dto.MandateCreateRequest, user models.User) (dto.MandateResponse, dto.HandleError)
+
+	// GetMandate Returns the latest mandate of a loan application with its collection attempts
+	GetMandate(request dto.MandateDetailsRequest, user models.User) (dto.MandateResponse, dto.HandleError)
+
+	// CancelMandate Cancels the active mandate of a loan application
+	CancelMandate(request dto.MandateCancelRequest, user models.User) (dto.MandateResponse, dto.HandleError)
+
+	// HandleDebitCallback Applies a signed collection outcome pushed by the debit provider
+	HandleDebitCallback(request dto.DebitCallbackRequest, payload []byte, signature string) (dto.DebitCallbackResponse, dto.HandleError)
+
+	// RunCollections Raises the debits due at the given time, retries failed ones and applies polled outcomes
+	RunCollections(now time.Time) (dto.CollectionRunResponse, dto.HandleError)
+}
+
+// mandateService is an implementation of MandateService
+type mandateService struct {
+	repaymentSvc repaymentService.RepaymentService
+	provider     debitService.DebitProvider
+	retryPolicy  RetryPolicy
+}
+
+// NewMandateService returns a new instance of MandateService
+func NewMandateService(repaymentSvc repaymentService.RepaymentService, provider debitService.DebitProvider,
+	retryPolicy RetryPolicy) MandateService {
+	return &mandateService{
+		repaymentSvc: repaymentSvc,
+		provider:     provider,
+		retryPolicy:  retryPolicy,
+	}
+}
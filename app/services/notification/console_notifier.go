@@ -0,0 +1,26 @@
+package notification_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+)
+
+// ConsoleNotifier is a Notifier writing messages to the application log instead of delivering them
+type ConsoleNotifier struct{}
+
+// NewConsoleNotifier returns a notifier writing to the application log
+func NewConsoleNotifier() *ConsoleNotifier {
+	return &ConsoleNotifier{}
+}
+
+// Name returns the notifier identifier
+func (n *ConsoleNotifier) Name() string {
+	return ProviderConsole
+}
+
+// Send logs the message with its recipient
+func (n *ConsoleNotifier) Send(message dto.Notification) error {
+	logger.Sugar.Infow("notification", "channel", message.Channel, "recipient", message.Recipient,
+		"subject", message.Subject, "body", message.Body)
+	return nil
+}
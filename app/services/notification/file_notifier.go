@@ -0,0 +1,57 @@
+package notification_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+// FileNotifier is a Notifier writing every message as a JSON file named after its ID, in a folder per channel, so
+// that messages such as one-time passwords can be read back during development and tests
+type FileNotifier struct {
+	directory string
+}
+
+// NewFileNotifier returns a notifier writing to the given directory
+func NewFileNotifier(directory string) *FileNotifier {
+	return &FileNotifier{
+		directory: directory,
+	}
+}
+
+// Name returns the notifier identifier
+func (n *FileNotifier) Name() string {
+	return ProviderFile
+}
+
+// Send writes the message to <directory>/<channel>/<notification id>.json
+func (n *FileNotifier) Send(message dto.Notification) error {
+	if message.NotificationID == "" {
+		message.NotificationID = uuid.New().String()
+	}
+	if message.CreatedAt == "" {
+		message.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+
+	// The ID becomes a file name, it must not leave the channel folder
+	if strings.ContainsAny(message.NotificationID, `/\`) || strings.Contains(message.NotificationID, "..") {
+		return fmt.Errorf("invalid notification id %v", message.NotificationID)
+	}
+
+	folder := filepath.Join(n.directory, strings.ToLower(message.Channel))
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(message, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(folder, message.NotificationID+".json"), content, 0o600)
+}
@@ -0,0 +1,46 @@
+package notification_service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNotifier_Send(t *testing.T) {
+	directory := t.TempDir()
+	notifier := NewFileNotifier(directory)
+
+	err := notifier.Send(dto.Notification{
+		NotificationID: "notification_id",
+		Channel:        ChannelSMS,
+		Recipient:      "9790970381",
+		Body:           "Your verification code is 123456",
+		Purpose:        "REGISTRATION",
+	})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(directory, "sms", "notification_id.json"))
+	assert.NoError(t, err)
+
+	var message dto.Notification
+	assert.NoError(t, json.Unmarshal(content, &message))
+	assert.Equal(t, "9790970381", message.Recipient)
+	assert.Equal(t, "Your verification code is 123456", message.Body)
+	assert.NotEmpty(t, message.CreatedAt)
+}
+
+func TestFileNotifier_InvalidID(t *testing.T) {
+	notifier := NewFileNotifier(t.TempDir())
+	err := notifier.Send(dto.Notification{NotificationID: "../escape", Channel: ChannelEmail})
+	assert.Error(t, err)
+}
+
+func TestNewNotifier(t *testing.T) {
+	assert.Equal(t, ProviderFile, NewNotifier("file", t.TempDir()).Name())
+	assert.Equal(t, ProviderConsole, NewNotifier("", "").Name())
+	assert.Equal(t, ProviderConsole, NewNotifier("unknown", "").Name())
+}
@@ -0,0 +1,42 @@
+package notification_service
+
+import (
+	"strings"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+const (
+	// ProviderConsole writes messages to the application log, for local development
+	ProviderConsole = "CONSOLE"
+
+	// ProviderFile writes every message as a JSON file, for local development and tests
+	ProviderFile = "FILE"
+)
+
+// Channels a notification is delivered through
+const (
+	ChannelEmail = "EMAIL"
+	ChannelSMS   = "SMS"
+)
+
+// Notifier defines the interface every email or SMS delivery integration implements
+type Notifier interface {
+	// Name returns the identifier of the notifier
+	Name() string
+
+	// Send delivers a message to its recipient on its channel
+	Send(message dto.Notification) error
+}
+
+// NewNotifier returns the notifier configured by name. Email and SMS gateways are selected here as they are
+// integrated; until then every other name resolves to the console notifier.
+func NewNotifier(name string, directory string) Notifier {
+	switch strings.ToUpper(name) {
+	case ProviderFile:
+		return NewFileNotifier(directory)
+	case ProviderConsole, "":
+		return NewConsoleNotifier()
+	}
+	return NewConsoleNotifier()
+}
@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/provision/service.go
+
+// Package provision_service is a generated GoMock package.
+package provision_service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockProvisionService is a mock of ProvisionService interface.
+type MockProvisionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProvisionServiceMockRecorder
+}
+
+// MockProvisionServiceMockRecorder is the mock recorder for MockProvisionService.
+type MockProvisionServiceMockRecorder struct {
+	mock *MockProvisionService
+}
+
+// NewMockProvisionService creates a new mock instance.
+func NewMockProvisionService(ctrl *gomock.Controller) *MockProvisionService {
+	mock := &MockProvisionService{ctrl: ctrl}
+	mock.recorder = &MockProvisionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProvisionService) EXPECT() *MockProvisionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetParameters mocks base method.
+func (m *MockProvisionService) GetParameters(request dto.ProvisionParameterListRequest) (dto.ProvisionParameterResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParameters", request)
+	ret0, _ := ret[0].(dto.ProvisionParameterResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetParameters indicates an expected call of GetParameters.
+func (mr *MockProvisionServiceMockRecorder) GetParameters(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameters", reflect.TypeOf((*MockProvisionService)(nil).GetParameters), request)
+}
+
+// GetProvisionRun mocks base method.
+func (m *MockProvisionService) GetProvisionRun(request dto.ProvisionRunDetailRequest) (dto.ProvisionRunDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionRun", request)
+	ret0, _ := ret[0].(dto.ProvisionRunDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetProvisionRun indicates an expected call of GetProvisionRun.
+func (mr *MockProvisionServiceMockRecorder) GetProvisionRun(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionRun", reflect.TypeOf((*MockProvisionService)(nil).GetProvisionRun), request)
+}
+
+// GetProvisionRuns mocks base method.
+func (m *MockProvisionService) GetProvisionRuns() (dto.ProvisionRunListResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionRuns")
+	ret0, _ := ret[0].(dto.ProvisionRunListResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetProvisionRuns indicates an expected call of GetProvisionRuns.
+func (mr *MockProvisionServiceMockRecorder) GetProvisionRuns() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionRuns", reflect.TypeOf((*MockProvisionService)(nil).GetProvisionRuns))
+}
+
+// RunMonthEnd mocks base method.
+func (m *MockProvisionService) RunMonthEnd(now time.Time) (dto.ProvisionRunResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunMonthEnd", now)
+	ret0, _ := ret[0].(dto.ProvisionRunResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RunMonthEnd indicates an expected call of RunMonthEnd.
+func (mr *MockProvisionServiceMockRecorder) RunMonthEnd(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMonthEnd", reflect.TypeOf((*MockProvisionService)(nil).RunMonthEnd), now)
+}
+
+// RunProvisioning mocks base method.
+func (m *MockProvisionService) RunProvisioning(asOf time.Time, userId string) (dto.ProvisionRunResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunProvisioning", asOf, userId)
+	ret0, _ := ret[0].(dto.ProvisionRunResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RunProvisioning indicates an expected call of RunProvisioning.
+func (mr *MockProvisionServiceMockRecorder) RunProvisioning(asOf, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunProvisioning", reflect.TypeOf((*MockProvisionService)(nil).RunProvisioning), asOf, userId)
+}
+
+// SetParameter mocks base method.
+func (m *MockProvisionService) SetParameter(request dto.ProvisionParameterRequest, user models.User) (dto.ProvisionParameterResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParameter", request, user)
+	ret0, _ := ret[0].(dto.ProvisionParameterResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// SetParameter indicates an expected call of SetParameter.
+func (mr *MockProvisionServiceMockRecorder) SetParameter(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParameter", reflect.TypeOf((*MockProvisionService)(nil).SetParameter), request, user)
+}
+
+// SetRestructured mocks base method.
+func (m *MockProvisionService) SetRestructured(request dto.RestructureRequest, user models.User) (dto.RestructureResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRestructured", request, user)
+	ret0, _ := ret[0].(dto.RestructureResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// SetRestructured indicates an expected call of SetRestructured.
+func (mr *MockProvisionServiceMockRecorder) SetRestructured(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRestructured", reflect.TypeOf((*MockProvisionService)(nil).SetRestructured), request, user)
+}
@@ -0,0 +1,184 @@
+package provision_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm/clause"
+)
+
+// GetParameters lists the PD/LGD parameters, optionally of one country and product
+// Parameters:
+// - request: dto.ProvisionParameterListRequest with the optional country and product
+// Returns:
+// - dto.ProvisionParameterResponse with the parameters
+// - dto.HandleError with any error that occurred during the process
+func (s *provisionService) GetParameters(request dto.ProvisionParameterListRequest) (
+	response dto.ProvisionParameterResponse, handle dto.HandleError) {
+	var parameterCondition []db.WhereCondition
+	if request.CountryCode != "" {
+		parameterCondition = append(parameterCondition, db.WhereCondition{
+			Key:       models.ProvisionParameterColumns.CountryCode,
+			Condition: "=",
+			Value:     request.CountryCode,
+		})
+	}
+	if request.ProductCode != "" {
+		parameterCondition = append(parameterCondition, db.WhereCondition{
+			Key:       models.ProvisionParameterColumns.ProductCode,
+			Condition: "=",
+			Value:     request.ProductCode,
+		})
+	}
+
+	parameter := models.ProvisionParameter{}
+	parameters, err := parameter.FindAllByCondition(parameterCondition)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data.Parameters = []dto.ProvisionParameterObject{}
+	for _, parameter := range parameters {
+		response.Data.Parameters = append(response.Data.Parameters, buildParameterObject(parameter))
+	}
+	return
+}
+
+// SetParameter creates or replaces the PD/LGD parameters of a country, product and stage. They apply from the
+// next provisioning run, runs already stored keep the parameters they used.
+// Parameters:
+// - request: dto.ProvisionParameterRequest with the country, product, stage, PD and LGD
+// - user: models.User representing the employee changing the parameters
+// Returns:
+// - dto.ProvisionParameterResponse with the stored parameters
+// - dto.HandleError with any error that occurred during the process
+func (s *provisionService) SetParameter(request dto.ProvisionParameterRequest, user models.User) (
+	response dto.ProvisionParameterResponse, handle dto.HandleError) {
+	country := models.Country{}
+	if _, err := country.FindByPrimaryKey(request.CountryCode); err != nil {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("country %v not found", request.CountryCode)
+		return
+	}
+
+	parameter := models.ProvisionParameter{
+		CountryCode:          request.CountryCode,
+		ProductCode:          request.ProductCode,
+		Stage:                request.Stage,
+		ProbabilityOfDefault: request.ProbabilityOfDefault,
+		LossGivenDefault:     request.LossGivenDefault,
+		UpdatedBy:            null.StringFrom(user.UserID),
+		UpdatedAt:            time.Now(),
+	}
+
+	err := db.MysqlDB.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{
+			models.ProvisionParameterColumns.ProbabilityOfDefault,
+			models.ProvisionParameterColumns.LossGivenDefault,
+			models.ProvisionParameterColumns.UpdatedBy,
+		}),
+	}).Create(&parameter).Error
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Provision parameters saved successfully"
+	response.Data.Parameters = []dto.ProvisionParameterObject{buildParameterObject(parameter)}
+	return
+}
+
+// SetRestructured flags a loan as restructured, e.g. after its terms were eased for a borrower in difficulty, or
+// clears the flag once it is cured. A restructured loan is held in stage 2 at least.
+// Parameters:
+// - request: dto.RestructureRequest with the application ID and the flag
+// - user: models.User representing the employee assigned to the loan
+// Returns:
+// - dto.RestructureResponse with the flag of the loan
+// - dto.HandleError with any error that occurred during the process
+func (s *provisionService) SetRestructured(request dto.RestructureRequest, user models.User) (
+	response dto.RestructureResponse, handle dto.HandleError) {
+	// Only the employee allocated to the loan, with the permission to restructure loans, flags it
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionLoanRestructure,
+	}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	application := models.LoanApplication{}
+	application, err := application.FindByPrimaryKeyForUpdate(tx, request.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	if application.Status != models.LoanApplicationStatusApproved {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("application is %v and cannot be restructured", application.Status)
+		return
+	}
+
+	if application.Restructured != *request.Restructured {
+		application.Restructured = *request.Restructured
+		application.RestructuredDate = null.Time{}
+		if application.Restructured {
+			application.RestructuredDate = null.TimeFrom(startOfDay(time.Now()))
+		}
+
+		if err = tx.Omit(clause.Associations).Save(&application).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -4
+			handle.Errors = err
+			return
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Restructuring flag updated successfully"
+	response.Data.ApplicationId = application.ApplicationID
+	response.Data.Restructured = application.Restructured
+	if application.RestructuredDate.Valid {
+		response.Data.RestructuredDate = application.RestructuredDate.Time.Format(provisionDateLayout)
+	}
+	return
+}
+
+// buildParameterObject formats PD/LGD parameters for the response
+func buildParameterObject(parameter models.ProvisionParameter) dto.ProvisionParameterObject {
+	return dto.ProvisionParameterObject{
+		CountryCode:          parameter.CountryCode,
+		ProductCode:          parameter.ProductCode,
+		Stage:                parameter.Stage,
+		ProbabilityOfDefault: parameter.ProbabilityOfDefault,
+		LossGivenDefault:     parameter.LossGivenDefault,
+		UpdatedBy:            parameter.UpdatedBy.String,
+		UpdatedAt:            parameter.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,378 @@
+package provision_service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+)
+
+// provisionDateLayout is the layout month ends are compared and referenced in
+const provisionDateLayout = "2006-01-02"
+
+// RunProvisioning measures the expected credit loss (exposure × PD × LGD) of every approved loan at a month end.
+// Loans are staged from their days past due and restructuring flag, and the PD/LGD of their country, product and
+// stage apply. The allowance of each loan is brought to its new expected credit loss by a provision entry, loans
+// that left the book since the last run have their allowance released. A month end can be run again, e.g. after
+// the parameters were corrected, the new run supersedes the earlier one and only the difference is posted.
+// Parameters:
+// - asOf: the month end to measure at
+// - userId: the employee running the provisioning, empty for the scheduler
+// Returns:
+// - dto.ProvisionRunResponse with the run and its totals per currency and stage
+// - dto.HandleError with any error that occurred during the process, nothing is stored or posted then
+func (s *provisionService) RunProvisioning(asOf time.Time, userId string) (
+	response dto.ProvisionRunResponse, handle dto.HandleError) {
+	asOf = startOfDay(asOf)
+	if !isMonthEnd(asOf) {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("%v is not a month end", asOf.Format(provisionDateLayout))
+		return
+	}
+
+	parameter := models.ProvisionParameter{}
+	parameterList, err := parameter.FindAllByCondition(nil)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	parameters := make(map[string]models.ProvisionParameter)
+	for _, parameter := range parameterList {
+		parameters[parameterKey(parameter.CountryCode, parameter.ProductCode, parameter.Stage)] = parameter
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	// Hold the allowance account so that runs do not interleave and each sees the allowance the previous one left
+	account := models.LedgerAccount{}
+	if _, err = account.FindByPrimaryKeyForUpdate(tx, models.LedgerAccountLoanLossAllowance); err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	latest := models.ProvisionRun{}
+	latest, err = latest.FindLatestTx(tx)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+	if latest.RunID != "" && startOfDay(latest.AsOfDate).After(asOf) {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("provisions have already been run for %v, an earlier month end cannot be run again",
+			latest.AsOfDate.Format(provisionDateLayout))
+		return
+	}
+
+	applications, allowances, err := provisionedApplications(tx)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	run := models.ProvisionRun{
+		RunID:    uuid.New().String(),
+		AsOfDate: asOf,
+	}
+	if userId != "" {
+		run.RunBy = null.StringFrom(userId)
+	}
+
+	var provisions []models.LoanProvision
+	for _, application := range applications {
+		// The allowance is a credit balance, it is held as a positive amount
+		held := -allowances[application.ApplicationID]
+
+		provision, err := s.measureLoan(tx, application, held, parameters, asOf)
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -7
+			handle.Errors = fmt.Errorf("could not provision application %v: %v", application.ApplicationID, err)
+			return
+		}
+		if provision.Exposure == 0 && provision.PreviousAmount == 0 {
+			continue
+		}
+
+		provision.ProvisionID = uuid.New().String()
+		provision.RunID = run.RunID
+		if provision.Movement != 0 {
+			entry, err := s.ledger.PostProvision(tx, application, run.RunID, asOf, provision.Movement, userId)
+			if err != nil {
+				tx.Rollback()
+				handle.Status = -8
+				handle.Errors = err
+				return
+			}
+			provision.EntryID = null.StringFrom(entry.EntryID)
+		}
+		provisions = append(provisions, provision)
+	}
+
+	run.LoanCount = len(provisions)
+	if err = tx.Create(&run).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -9
+		handle.Errors = err
+		return
+	}
+	if len(provisions) > 0 {
+		if err = tx.CreateInBatches(&provisions, 500).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -10
+			handle.Errors = err
+			return
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -11
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Provisioning completed successfully"
+	response.Data = buildRunObject(run, buildProvisionTotals(provisions))
+	return
+}
+
+// RunMonthEnd runs the provisioning for the month end before now, once. Later calls in the same month find the
+// run and do nothing.
+// Parameters:
+// - now: the current time
+// Returns:
+// - dto.ProvisionRunResponse with the new run, status 0 when the month end had been provisioned already
+// - dto.HandleError with any error that occurred during the process
+func (s *provisionService) RunMonthEnd(now time.Time) (response dto.ProvisionRunResponse, handle dto.HandleError) {
+	asOf := previousMonthEnd(now)
+
+	var runCondition []db.WhereCondition
+	runCondition = append(runCondition, db.WhereCondition{
+		Key:       models.ProvisionRunColumns.AsOfDate,
+		Condition: "=",
+		Value:     asOf.Format(provisionDateLayout),
+	})
+
+	run := models.ProvisionRun{}
+	runs, err := run.FindAllByCondition(runCondition)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+	if len(runs) > 0 {
+		response.Message = fmt.Sprintf("Provisions already run for %v", asOf.Format(provisionDateLayout))
+		return
+	}
+	return s.RunProvisioning(asOf, "")
+}
+
+// provisionedApplications returns the loans a run covers: every approved loan and every other loan still holding
+// an allowance, e.g. one paid off or written off since the last run
+// Parameters:
+// - tx: the transaction of the run
+// Returns:
+// - []models.LoanApplication with the loans to provision
+// - map[string]float64 with the balance of the allowance account per loan, negative for an allowance held
+// - error when the loans could not be loaded
+func provisionedApplications(tx *gorm.DB) (applications []models.LoanApplication, allowances map[string]float64,
+	err error) {
+	journalLine := models.JournalLine{}
+	allowances, err = journalLine.ApplicationBalances(tx, models.LedgerAccountLoanLossAllowance)
+	if err != nil {
+		return
+	}
+
+	var applicationCondition []db.WhereCondition
+	applicationCondition = append(applicationCondition, db.WhereCondition{
+		Key:       models.LoanApplicationColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusApproved,
+	})
+
+	application := models.LoanApplication{}
+	applications, err = application.FindAllByCondition(applicationCondition)
+	if err != nil {
+		return
+	}
+
+	approved := make(map[string]bool)
+	for _, application := range applications {
+		approved[application.ApplicationID] = true
+	}
+	var released []string
+	for applicationId := range allowances {
+		if !approved[applicationId] {
+			released = append(released, applicationId)
+		}
+	}
+	if len(released) == 0 {
+		return
+	}
+
+	var releasedCondition []db.WhereCondition
+	releasedCondition = append(releasedCondition, db.WhereCondition{
+		Key:       models.LoanApplicationColumns.ApplicationID,
+		Condition: "IN",
+		Value:     released,
+	})
+	others, err := application.FindAllByCondition(releasedCondition)
+	applications = append(applications, others...)
+	return
+}
+
+// measureLoan stages a loan and measures its expected credit loss at a month end. Loans no longer approved have
+// nothing left on book, their allowance is released in full.
+// Parameters:
+// - tx: the transaction of the run
+// - application: the loan to measure
+// - held: the allowance held for the loan before the run
+// - parameters: the PD/LGD per country, product and stage
+// - asOf: the month end to measure at
+// Returns:
+// - models.LoanProvision with the stage, exposure, expected credit loss and allowance movement of the loan
+// - error when the exposure could not be read or no parameters apply to the loan
+func (s *provisionService) measureLoan(tx *gorm.DB, application models.LoanApplication, held float64,
+	parameters map[string]models.ProvisionParameter, asOf time.Time) (provision models.LoanProvision, err error) {
+	provision = models.LoanProvision{
+		ApplicationID:  application.ApplicationID,
+		CurrencyCode:   application.CurrencyCode,
+		Restructured:   application.Restructured,
+		PreviousAmount: roundCents(held),
+	}
+
+	if application.Status == models.LoanApplicationStatusApproved {
+		provision.Exposure, err = exposureAt(tx, application.ApplicationID, asOf)
+		if err != nil {
+			return
+		}
+	}
+
+	if provision.Exposure > 0 {
+		var repaymentCondition []db.WhereCondition
+		repaymentCondition = append(repaymentCondition, db.WhereCondition{
+			Key:       models.RepaymentColumns.ApplicationID,
+			Condition: "=",
+			Value:     application.ApplicationID,
+		})
+
+		repayment := models.Repayment{}
+		repayments, findErr := repayment.FindAllByCondition(repaymentCondition)
+		if findErr != nil {
+			err = findErr
+			return
+		}
+
+		provision.DaysPastDue = daysPastDue(repayments, asOf)
+		provision.Stage = stageOf(provision.DaysPastDue, application.Restructured, s.policy)
+
+		parameter, ok := parameters[parameterKey(application.CountryCode, application.ProductCode, provision.Stage)]
+		if !ok {
+			err = fmt.Errorf("no PD/LGD parameters for country %v, product %v, stage %v", application.CountryCode,
+				application.ProductCode, provision.Stage)
+			return
+		}
+		provision.ProbabilityOfDefault = parameter.ProbabilityOfDefault
+		provision.LossGivenDefault = parameter.LossGivenDefault
+		provision.EclAmount = expectedCreditLoss(provision.Exposure, parameter.ProbabilityOfDefault,
+			parameter.LossGivenDefault)
+	}
+
+	provision.Movement = roundCents(provision.EclAmount - provision.PreviousAmount)
+	return
+}
+
+// exposureAt returns the principal, accrued interest and fees a loan owes in the ledger at the end of a day
+func exposureAt(tx *gorm.DB, applicationId string, asOf time.Time) (exposure float64, err error) {
+	journalLine := models.JournalLine{}
+	for _, accountCode := range []string{models.LedgerAccountLoanPrincipal, models.LedgerAccountInterestReceivable,
+		models.LedgerAccountFeesReceivable} {
+		balance, balanceErr := journalLine.ApplicationBalanceAsOf(tx, accountCode, applicationId, asOf)
+		if balanceErr != nil {
+			err = balanceErr
+			return
+		}
+		exposure += balance
+	}
+	return math.Max(roundCents(exposure), 0), nil
+}
+
+// daysPastDue returns how many days the oldest installment unpaid at the end of a day was overdue on it. An
+// installment paid in full after the day still counts as unpaid on it.
+func daysPastDue(repayments []models.Repayment, day time.Time) int {
+	for _, repayment := range repayments {
+		if repayment.Status == models.LoanApplicationStatusWrittenOff {
+			continue
+		}
+		paid := repayment.Status == models.LoanApplicationStatusPaid &&
+			(!repayment.PaymentDate.Valid || !startOfDay(repayment.PaymentDate.Time).After(day))
+		if paid {
+			continue
+		}
+
+		days := int(math.Round(startOfDay(day).Sub(startOfDay(repayment.InstallmentDate)).Hours() / 24))
+		if days < 0 {
+			return 0
+		}
+		return days
+	}
+	return 0
+}
+
+// stageOf returns the IFRS 9 stage of a loan: stage 3 once it is credit impaired by days past due, stage 2 on a
+// significant increase in credit risk, i.e. it is past due for the stage 2 threshold or was restructured, and
+// stage 1 otherwise
+func stageOf(daysPastDue int, restructured bool, policy StagingPolicy) int {
+	if daysPastDue >= policy.Stage3Days {
+		return models.ProvisionStage3
+	}
+	if daysPastDue >= policy.Stage2Days || restructured {
+		return models.ProvisionStage2
+	}
+	return models.ProvisionStage1
+}
+
+// expectedCreditLoss returns exposure × PD × LGD rounded to the cent
+func expectedCreditLoss(exposure float64, probabilityOfDefault float64, lossGivenDefault float64) float64 {
+	return roundCents(exposure * probabilityOfDefault * lossGivenDefault)
+}
+
+// parameterKey identifies the PD/LGD parameters of a country, product and stage
+func parameterKey(countryCode string, productCode string, stage int) string {
+	return fmt.Sprintf("%v:%v:%v", countryCode, productCode, stage)
+}
+
+// isMonthEnd reports whether a day is the last of its month
+func isMonthEnd(day time.Time) bool {
+	return day.AddDate(0, 0, 1).Day() == 1
+}
+
+// previousMonthEnd returns the last day of the month before the one a time falls in
+func previousMonthEnd(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, 0, -1)
+}
+
+// roundCents rounds an amount to the cent, half away from zero
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// startOfDay truncates a time to midnight in its own location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
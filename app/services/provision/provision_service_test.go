@@ -0,0 +1,89 @@
+package provision_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStageOf(t *testing.T) {
+	policy := StagingPolicy{Stage2Days: 30, Stage3Days: 90}
+
+	assert.Equal(t, models.ProvisionStage1, stageOf(0, false, policy))
+	assert.Equal(t, models.ProvisionStage1, stageOf(29, false, policy))
+	assert.Equal(t, models.ProvisionStage2, stageOf(30, false, policy))
+	assert.Equal(t, models.ProvisionStage2, stageOf(0, true, policy))
+	assert.Equal(t, models.ProvisionStage3, stageOf(90, false, policy))
+	assert.Equal(t, models.ProvisionStage3, stageOf(120, true, policy))
+}
+
+func TestDaysPastDue_AsOfMonthEnd(t *testing.T) {
+	monthEnd := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	repayments := []models.Repayment{
+		{InstallmentDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Status: models.LoanApplicationStatusPaid,
+			PaymentDate: null.TimeFrom(time.Date(2024, 1, 14, 10, 0, 0, 0, time.UTC))},
+		// Paid after the month end, it was still overdue on it
+		{InstallmentDate: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), Status: models.LoanApplicationStatusPaid,
+			PaymentDate: null.TimeFrom(time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC))},
+		{InstallmentDate: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), Status: models.LoanApplicationStatusPending},
+	}
+
+	assert.Equal(t, 45, daysPastDue(repayments, monthEnd))
+	assert.Equal(t, 16, daysPastDue(repayments[2:], monthEnd))
+}
+
+func TestDaysPastDue_NothingDue(t *testing.T) {
+	monthEnd := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	repayments := []models.Repayment{
+		{InstallmentDate: time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC), Status: models.LoanApplicationStatusPending},
+	}
+
+	assert.Equal(t, 0, daysPastDue(repayments, monthEnd))
+	assert.Equal(t, 0, daysPastDue(nil, monthEnd))
+}
+
+func TestExpectedCreditLoss(t *testing.T) {
+	assert.Equal(t, 45.0, expectedCreditLoss(10000, 0.01, 0.45))
+	assert.Equal(t, 333.33, expectedCreditLoss(1234.56, 0.6, 0.45))
+	assert.Equal(t, 0.0, expectedCreditLoss(0, 1, 0.45))
+}
+
+func TestMonthEnds(t *testing.T) {
+	assert.True(t, isMonthEnd(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, isMonthEnd(time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, isMonthEnd(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		previousMonthEnd(time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+		previousMonthEnd(time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestBuildProvisionTotals_PerCurrencyAndStage(t *testing.T) {
+	provisions := []models.LoanProvision{
+		{CurrencyCode: "USD", Stage: 1, Exposure: 1000, EclAmount: 4.5, Movement: 4.5},
+		{CurrencyCode: "INR", Stage: 2, Exposure: 5000, EclAmount: 450, Movement: 400},
+		{CurrencyCode: "INR", Stage: 1, Exposure: 10000, EclAmount: 45, Movement: -5},
+		{CurrencyCode: "INR", Stage: 2, Exposure: 2000.10, EclAmount: 180.01, Movement: 180.01},
+	}
+
+	totals := buildProvisionTotals(provisions)
+
+	assert.Len(t, totals, 3)
+	assert.Equal(t, "INR", totals[0].CurrencyCode)
+	assert.Equal(t, 1, totals[0].Stage)
+	assert.Equal(t, 2, totals[1].Stage)
+	assert.Equal(t, 2, totals[1].Count)
+	assert.Equal(t, 7000.10, totals[1].Exposure)
+	assert.Equal(t, 630.01, totals[1].EclAmount)
+	assert.Equal(t, 580.01, totals[1].Movement)
+	assert.Equal(t, "USD", totals[2].CurrencyCode)
+
+	object := buildRunObject(models.ProvisionRun{RunID: "run_id", AsOfDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)}, totals)
+	assert.Equal(t, "2024-03-31", object.AsOf)
+	assert.Equal(t, "₹630.01", object.Totals[1].Ecl)
+	assert.Equal(t, "-₹5.00", object.Totals[0].Movement)
+}
@@ -0,0 +1,161 @@
+package provision_service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// GetProvisionRuns lists every provisioning run, latest month end first, with its totals per currency and stage
+// Returns:
+// - dto.ProvisionRunListResponse with the runs
+// - dto.HandleError with any error that occurred during the process
+func (s *provisionService) GetProvisionRuns() (response dto.ProvisionRunListResponse, handle dto.HandleError) {
+	run := models.ProvisionRun{}
+	runs, err := run.FindAllByCondition(nil)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data.Runs = []dto.ProvisionRunObject{}
+	if len(runs) == 0 {
+		return
+	}
+
+	var runIds []string
+	for _, run := range runs {
+		runIds = append(runIds, run.RunID)
+	}
+
+	provision := models.LoanProvision{}
+	totals, err := provision.TotalsByRun(runIds)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	totalsByRun := make(map[string][]models.LoanProvisionTotal)
+	for _, total := range totals {
+		totalsByRun[total.RunID] = append(totalsByRun[total.RunID], total)
+	}
+
+	for _, run := range runs {
+		response.Data.Runs = append(response.Data.Runs, buildRunObject(run, totalsByRun[run.RunID]))
+	}
+	return
+}
+
+// GetProvisionRun returns a provisioning run with the stage, exposure and expected credit loss of every loan in it
+// Parameters:
+// - request: dto.ProvisionRunDetailRequest with the run ID
+// Returns:
+// - dto.ProvisionRunDetailResponse with the run and its loans
+// - dto.HandleError with any error that occurred during the process
+func (s *provisionService) GetProvisionRun(request dto.ProvisionRunDetailRequest) (
+	response dto.ProvisionRunDetailResponse, handle dto.HandleError) {
+	run := models.ProvisionRun{}
+	run, err := run.FindByPrimaryKey(request.RunID)
+	if err != nil || run.RunID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("provision run %v not found", request.RunID)
+		return
+	}
+
+	var provisionCondition []db.WhereCondition
+	provisionCondition = append(provisionCondition, db.WhereCondition{
+		Key:       models.LoanProvisionColumns.RunID,
+		Condition: "=",
+		Value:     run.RunID,
+	})
+
+	provision := models.LoanProvision{}
+	provisions, err := provision.FindAllByCondition(provisionCondition)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data.ProvisionRunObject = buildRunObject(run, buildProvisionTotals(provisions))
+	response.Data.Loans = []dto.LoanProvisionObject{}
+	for _, provision := range provisions {
+		response.Data.Loans = append(response.Data.Loans, dto.LoanProvisionObject{
+			ApplicationId:        provision.ApplicationID,
+			CurrencyCode:         provision.CurrencyCode,
+			Stage:                provision.Stage,
+			DaysPastDue:          provision.DaysPastDue,
+			Restructured:         provision.Restructured,
+			Exposure:             money.NewFromFloat(provision.Exposure, provision.CurrencyCode).Display(),
+			ProbabilityOfDefault: provision.ProbabilityOfDefault,
+			LossGivenDefault:     provision.LossGivenDefault,
+			Ecl:                  money.NewFromFloat(provision.EclAmount, provision.CurrencyCode).Display(),
+			PreviousEcl:          money.NewFromFloat(provision.PreviousAmount, provision.CurrencyCode).Display(),
+			Movement:             money.NewFromFloat(provision.Movement, provision.CurrencyCode).Display(),
+			EntryId:              provision.EntryID.String,
+		})
+	}
+	return
+}
+
+// buildProvisionTotals totals provisions per currency and stage, ordered by currency then stage
+func buildProvisionTotals(provisions []models.LoanProvision) (totals []models.LoanProvisionTotal) {
+	index := make(map[string]int)
+	for _, provision := range provisions {
+		key := fmt.Sprintf("%v:%v", provision.CurrencyCode, provision.Stage)
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, models.LoanProvisionTotal{
+				RunID:        provision.RunID,
+				CurrencyCode: provision.CurrencyCode,
+				Stage:        provision.Stage,
+			})
+		}
+		totals[i].Count++
+		totals[i].Exposure = roundCents(totals[i].Exposure + provision.Exposure)
+		totals[i].EclAmount = roundCents(totals[i].EclAmount + provision.EclAmount)
+		totals[i].Movement = roundCents(totals[i].Movement + provision.Movement)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].CurrencyCode != totals[j].CurrencyCode {
+			return totals[i].CurrencyCode < totals[j].CurrencyCode
+		}
+		return totals[i].Stage < totals[j].Stage
+	})
+	return
+}
+
+// buildRunObject formats a run and its totals for the response
+func buildRunObject(run models.ProvisionRun, totals []models.LoanProvisionTotal) (object dto.ProvisionRunObject) {
+	object = dto.ProvisionRunObject{
+		RunId:     run.RunID,
+		AsOf:      run.AsOfDate.Format(provisionDateLayout),
+		LoanCount: run.LoanCount,
+		RunBy:     run.RunBy.String,
+		CreatedAt: run.CreatedAt.Format(time.RFC3339),
+		Totals:    []dto.ProvisionTotal{},
+	}
+
+	for _, total := range totals {
+		object.Totals = append(object.Totals, dto.ProvisionTotal{
+			CurrencyCode: total.CurrencyCode,
+			Stage:        total.Stage,
+			Count:        total.Count,
+			Exposure:     money.NewFromFloat(total.Exposure, total.CurrencyCode).Display(),
+			Ecl:          money.NewFromFloat(total.EclAmount, total.CurrencyCode).Display(),
+			Movement:     money.NewFromFloat(total.Movement, total.CurrencyCode).Display(),
+		})
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package provision_service
+
+import (
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+)
+
+// ProvisionService defines the interface for expected credit loss provisioning
+type ProvisionService interface {
+	// RunProvisioning Measures the expected credit loss of every loan at a month end and posts the allowance movements
+	RunProvisioning(asOf time.Time, userId string) (dto.ProvisionRunResponse, dto.HandleError)
+
+	// RunMonthEnd Runs the provisioning for the last month end unless it has been run already
+	RunMonthEnd(now time.Time) (dto.ProvisionRunResponse, dto.HandleError)
+
+	// GetProvisionRuns Lists the provisioning runs with their totals per currency and stage
+	GetProvisionRuns() (dto.ProvisionRunListResponse, dto.HandleError)
+
+	// GetProvisionRun Returns a provisioning run with the provision of every loan in it
+	GetProvisionRun(request dto.ProvisionRunDetailRequest) (dto.ProvisionRunDetailResponse, dto.HandleError)
+
+	// GetParameters Lists the PD/LGD parameters per country, product and stage
+	GetParameters(request dto.ProvisionParameterListRequest) (dto.ProvisionParameterResponse, dto.HandleError)
+
+	// SetParameter Creates or replaces the PD/LGD parameters of a country, product and stage
+	SetParameter(request dto.ProvisionParameterRequest, user models.User) (dto.ProvisionParameterResponse, dto.HandleError)
+
+	// SetRestructured Flags a loan as restructured, or clears the flag
+	SetRestructured(request dto.RestructureRequest, user models.User) (dto.RestructureResponse, dto.HandleError)
+}
+
+// StagingPolicy gives the days past due moving a loan to stage 2 and to stage 3
+type StagingPolicy struct {
+	Stage2Days int
+	Stage3Days int
+}
+
+// provisionService is an implementation of ProvisionService
+type provisionService struct {
+	ledger ledgerService.LedgerService
+	policy StagingPolicy
+}
+
+// NewProvisionService returns a new instance of ProvisionService
+// Parameters:
+// - ledger: ledgerService.LedgerService posting the allowance movements
+// - policy: StagingPolicy staging the loans
+func NewProvisionService(ledger ledgerService.LedgerService, policy StagingPolicy) ProvisionService {
+	return &provisionService{
+		ledger: ledger,
+		policy: policy,
+	}
+}
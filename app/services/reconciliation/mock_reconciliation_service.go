@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/reconciliation/service.go
+
+// Package reconciliation_service is a generated GoMock package.
+package reconciliation_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockReconciliationService is a mock of ReconciliationService interface.
+type MockReconciliationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationServiceMockRecorder
+}
+
+// MockReconciliationServiceMockRecorder is the mock recorder for MockReconciliationService.
+type MockReconciliationServiceMockRecorder struct {
+	mock *MockReconciliationService
+}
+
+// NewMockReconciliationService creates a new mock instance.
+func NewMockReconciliationService(ctrl *gomock.Controller) *MockReconciliationService {
+	mock := &MockReconciliationService{ctrl: ctrl}
+	mock.recorder = &MockReconciliationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationService) EXPECT() *MockReconciliationServiceMockRecorder {
+	return m.recorder
+}
+
+// GetStatementExceptions mocks base method.
+func (m *MockReconciliationService) GetStatementExceptions(request dto.StatementExceptionRequest) (dto.StatementExceptionResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementExceptions", request)
+	ret0, _ := ret[0].(dto.StatementExceptionResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetStatementExceptions indicates an expected call of GetStatementExceptions.
+func (mr *MockReconciliationServiceMockRecorder) GetStatementExceptions(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementExceptions", reflect.TypeOf((*MockReconciliationService)(nil).GetStatementExceptions), request)
+}
+
+// ImportStatement mocks base method.
+func (m *MockReconciliationService) ImportStatement(request dto.StatementImportRequest, content []byte, user models.User) (dto.StatementImportResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStatement", request, content, user)
+	ret0, _ := ret[0].(dto.StatementImportResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ImportStatement indicates an expected call of ImportStatement.
+func (mr *MockReconciliationServiceMockRecorder) ImportStatement(request, content, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStatement", reflect.TypeOf((*MockReconciliationService)(nil).ImportStatement), request, content, user)
+}
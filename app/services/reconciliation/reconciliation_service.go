@@ -0,0 +1,502 @@
+package reconciliation_service
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm/clause"
+)
+
+// amountMatchWindow is how far the due date of an installment may be from the booking date for a credit
+// to be matched to it on the amount alone
+const amountMatchWindow = 15 * 24 * time.Hour
+
+var (
+	// installmentReferencePattern matches the payment references printed on the repayment schedule
+	installmentReferencePattern = regexp.MustCompile(`(?i)\bLN[0-9A-F]{10}-\d{2,}\b`)
+
+	// applicationIdPattern matches a loan application ID quoted in the remittance information
+	applicationIdPattern = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+
+	// exceptionReportHeader is the header row of the CSV exceptions report
+	exceptionReportHeader = []string{"line_number", "booking_date", "amount", "currency", "bank_reference",
+		"payment_reference", "virtual_account", "counterparty", "description", "application_id", "reason"}
+)
+
+// ImportStatement reads a bank statement, matches every credit to a loan by virtual account, payment reference
+// or amount and posts the matched credits through the repayment service. Credits that cannot be matched or
+// posted are kept as exceptions for operations to handle by hand. Lines whose bank reference was already
+// posted are skipped, so a statement can safely be imported again.
+// Parameters:
+// - request: dto.StatementImportRequest containing the file name and optional format
+// - content: the raw statement file
+// - user: models.User representing the employee importing the statement
+// Returns:
+// - dto.StatementImportResponse with the import summary and the exceptions
+// - dto.HandleError with any error that occurred during the process
+func (s *reconciliationService) ImportStatement(request dto.StatementImportRequest, content []byte, user models.User) (
+	response dto.StatementImportResponse, handle dto.HandleError) {
+	format := request.Format
+	if format == "" {
+		format = detectStatementFormat(request.FileName, content)
+	}
+
+	statementLines, err := parseStatement(format, content)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	if len(statementLines) == 0 {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("statement contains no entries")
+		return
+	}
+
+	fileHash := sha256.Sum256(content)
+	statement := models.BankStatement{
+		StatementID: uuid.New().String(),
+		FileName:    request.FileName,
+		Format:      format,
+		FileHash:    hex.EncodeToString(fileHash[:]),
+		TotalLines:  len(statementLines),
+		ImportedBy:  user.UserID,
+	}
+
+	if err := db.MysqlDB.Create(&statement).Error; err != nil {
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	// Identical lines without a bank reference are told apart by their position among the duplicates
+	occurrences := make(map[string]int)
+
+	var lines []models.BankStatementLine
+	for _, statementLine := range statementLines {
+		fingerprint := lineFingerprint(statementLine)
+		occurrences[fingerprint]++
+
+		line := s.reconcileLine(statementLine, statementBankReference(statementLine, fingerprint, occurrences[fingerprint]))
+		line.StatementID = statement.StatementID
+
+		switch line.Status {
+		case models.StatementLineStatusPosted:
+			statement.PostedLines++
+		case models.StatementLineStatusDuplicate:
+			statement.DuplicateLines++
+		case models.StatementLineStatusIgnored:
+			statement.IgnoredLines++
+		default:
+			statement.ExceptionLines++
+			response.Data.Exceptions = append(response.Data.Exceptions, toStatementException(line))
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := db.MysqlDB.Omit(clause.Associations).CreateInBatches(&lines, 100).Error; err != nil {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("statement %v was posted but its lines could not be stored: %v", statement.StatementID, err)
+		return
+	}
+
+	if err := db.MysqlDB.Save(&statement).Error; err != nil {
+		logger.Sugar.Error("failed to update bank statement totals: ", err)
+	}
+
+	response.Status = 1
+	response.Message = "Bank statement imported successfully"
+	response.Data.StatementId = statement.StatementID
+	response.Data.Format = statement.Format
+	response.Data.TotalLines = statement.TotalLines
+	response.Data.PostedLines = statement.PostedLines
+	response.Data.DuplicateLines = statement.DuplicateLines
+	response.Data.IgnoredLines = statement.IgnoredLines
+	response.Data.ExceptionLines = statement.ExceptionLines
+	return
+}
+
+// GetStatementExceptions lists the credits of an imported statement that were not posted
+// Parameters:
+// - request: dto.StatementExceptionRequest containing the statement ID
+// Returns:
+// - dto.StatementExceptionResponse with the exceptions
+// - dto.HandleError with any error that occurred during the process
+func (s *reconciliationService) GetStatementExceptions(request dto.StatementExceptionRequest) (
+	response dto.StatementExceptionResponse, handle dto.HandleError) {
+	statement := models.BankStatement{}
+	statement, _ = statement.FindByPrimaryKey(request.StatementID)
+	if statement.StatementID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("statement %v not found", request.StatementID)
+		return
+	}
+
+	var lineCondition []db.WhereCondition
+	lineCondition = append(lineCondition, db.WhereCondition{
+		Key:       models.BankStatementLineColumns.StatementID,
+		Condition: "=",
+		Value:     statement.StatementID,
+	})
+	lineCondition = append(lineCondition, db.WhereCondition{
+		Key:       models.BankStatementLineColumns.Status,
+		Condition: "=",
+		Value:     models.StatementLineStatusException,
+	})
+
+	line := models.BankStatementLine{}
+	lines, err := line.FindAllByCondition(lineCondition)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Data.StatementId = statement.StatementID
+	response.Data.FileName = statement.FileName
+	response.Data.Exceptions = []dto.StatementException{}
+	for _, line := range lines {
+		response.Data.Exceptions = append(response.Data.Exceptions, toStatementException(line))
+	}
+	return
+}
+
+// WriteExceptionReport writes the exceptions of a statement as CSV for operations to work through
+// Parameters:
+// - writer: where the report is written to
+// - exceptions: the statement lines that were not posted
+// Returns:
+// - error when the report could not be written
+func WriteExceptionReport(writer io.Writer, exceptions []dto.StatementException) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(exceptionReportHeader); err != nil {
+		return err
+	}
+
+	for _, exception := range exceptions {
+		record := []string{
+			strconv.Itoa(exception.LineNumber),
+			exception.BookingDate,
+			exception.Amount,
+			exception.CurrencyCode,
+			exception.BankReference,
+			exception.PaymentReference,
+			exception.VirtualAccount,
+			exception.Counterparty,
+			exception.Description,
+			exception.ApplicationId,
+			exception.Reason,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// reconcileLine matches a single statement line to a loan and posts it
+// Parameters:
+// - statementLine: the parsed statement entry
+// - bankReference: the reference the credit is booked under, used to detect credits posted before
+// Returns:
+// - models.BankStatementLine recording the outcome
+func (s *reconciliationService) reconcileLine(statementLine statementLine, bankReference string) (line models.BankStatementLine) {
+	line = models.BankStatementLine{
+		LineID:        uuid.New().String(),
+		LineNumber:    statementLine.LineNumber,
+		Amount:        statementLine.Amount,
+		CurrencyCode:  statementLine.CurrencyCode,
+		CreditDebit:   statementLine.CreditDebit,
+		BankReference: null.StringFrom(bankReference),
+	}
+	if !statementLine.BookingDate.IsZero() {
+		line.BookingDate = null.TimeFrom(statementLine.BookingDate)
+	}
+	line.PaymentReference = optionalString(statementLine.PaymentReference)
+	line.VirtualAccount = optionalString(statementLine.VirtualAccount)
+	line.Counterparty = optionalString(statementLine.Counterparty)
+	line.Description = optionalString(statementLine.Description)
+
+	// Only incoming money is reconciled against loans
+	if statementLine.CreditDebit != models.StatementLineCredit {
+		line.Status = models.StatementLineStatusIgnored
+		return
+	}
+
+	if statementLine.Amount <= 0 {
+		return exceptionLine(line, "credit amount must be greater than zero")
+	}
+
+	// Skip credits booked by an earlier import of the same or an overlapping statement
+	var paymentCondition []db.WhereCondition
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.Gateway,
+		Condition: "=",
+		Value:     models.PaymentChannelBankTransfer,
+	})
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.GatewayReference,
+		Condition: "=",
+		Value:     bankReference,
+	})
+
+	payment := models.Payment{}
+	payment, _ = payment.FindOneByCondition(paymentCondition)
+	if payment.PaymentID != "" {
+		line.Status = models.StatementLineStatusDuplicate
+		line.ApplicationID = null.StringFrom(payment.ApplicationID)
+		line.PaymentID = null.StringFrom(payment.PaymentID)
+		return
+	}
+
+	applicationId, method, reason := matchLine(statementLine)
+	if applicationId == "" {
+		return exceptionLine(line, reason)
+	}
+
+	line.ApplicationID = null.StringFrom(applicationId)
+	line.MatchMethod = null.StringFrom(method)
+
+	response, handle := s.repaymentSvc.PostBankCredit(dto.BankCreditRequest{
+		ApplicationID: applicationId,
+		CurrencyCode:  statementLine.CurrencyCode,
+		Amount:        statementLine.Amount,
+		BankReference: bankReference,
+	})
+	if handle.Status < 0 {
+		return exceptionLine(line, handle.Errors.Error())
+	}
+
+	line.Status = models.StatementLineStatusPosted
+	line.PaymentID = null.StringFrom(response.Data.PaymentId)
+	return
+}
+
+// matchLine finds the loan a credit belongs to. The virtual account the money was paid into is the
+// strongest signal, then a payment reference or application ID quoted by the customer, and finally an
+// installment falling due around the booking date for exactly the credited amount.
+// Parameters:
+// - line: the parsed statement entry
+// Returns:
+// - string: the matched application ID, empty when nothing matched
+// - string: the match method
+// - string: why the line could not be matched
+func matchLine(line statementLine) (applicationId string, method string, reason string) {
+	if line.VirtualAccount != "" {
+		var applicationCondition []db.WhereCondition
+		applicationCondition = append(applicationCondition, db.WhereCondition{
+			Key:       models.LoanApplicationColumns.VirtualAccount,
+			Condition: "=",
+			Value:     strings.ToUpper(line.VirtualAccount),
+		})
+
+		application := models.LoanApplication{}
+		application, _ = application.FindOneByCondition(applicationCondition)
+		if application.ApplicationID != "" {
+			return application.ApplicationID, models.MatchMethodVirtualAccount, ""
+		}
+	}
+
+	paymentReferences, applicationIds := extractReferences(line.PaymentReference, line.Description)
+	candidates := make(map[string]bool)
+
+	if len(paymentReferences) > 0 {
+		var repaymentCondition []db.WhereCondition
+		repaymentCondition = append(repaymentCondition, db.WhereCondition{
+			Key:       models.RepaymentColumns.PaymentReference,
+			Condition: "IN",
+			Value:     paymentReferences,
+		})
+
+		repayment := models.Repayment{}
+		repayments, _ := repayment.FindAllByCondition(repaymentCondition)
+		for _, repayment := range repayments {
+			candidates[repayment.ApplicationID] = true
+		}
+	}
+
+	for _, id := range applicationIds {
+		application := models.LoanApplication{}
+		application, _ = application.FindByPrimaryKey(id)
+		if application.ApplicationID != "" {
+			candidates[application.ApplicationID] = true
+		}
+	}
+
+	if len(candidates) > 1 {
+		return "", "", fmt.Sprintf("references point to %d different loans", len(candidates))
+	}
+	for id := range candidates {
+		return id, models.MatchMethodPaymentReference, ""
+	}
+
+	// Fall back to the amount of an installment due around the booking date
+	bookingDate := line.BookingDate
+	if bookingDate.IsZero() {
+		bookingDate = time.Now()
+	}
+
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusPending,
+	})
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.InstallmentDate,
+		Condition: ">=",
+		Value:     bookingDate.Add(-amountMatchWindow),
+	})
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.InstallmentDate,
+		Condition: "<=",
+		Value:     bookingDate.Add(amountMatchWindow),
+	})
+
+	repayment := models.Repayment{}
+	repayments, _ := repayment.FindAllByCondition(repaymentCondition)
+	for _, applicationId := range matchInstallmentAmount(repayments, line.Amount, line.CurrencyCode) {
+		candidates[applicationId] = true
+	}
+
+	if len(candidates) > 1 {
+		return "", "", fmt.Sprintf("amount matches installments of %d different loans", len(candidates))
+	}
+	for id := range candidates {
+		return id, models.MatchMethodAmount, ""
+	}
+
+	return "", "", "no loan matches the virtual account, payment reference or amount"
+}
+
+// matchInstallmentAmount returns the approved loans with a pending installment whose remaining amount
+// equals the credited amount
+// Parameters:
+// - repayments: pending installments with their loan application preloaded
+// - amount: the credited amount
+// - currencyCode: the currency of the credit
+// Returns:
+// - []string with the distinct matching application IDs
+func matchInstallmentAmount(repayments []models.Repayment, amount float64, currencyCode string) (applicationIds []string) {
+	seen := make(map[string]bool)
+	for _, repayment := range repayments {
+		if repayment.LoanApplication.Status != models.LoanApplicationStatusApproved ||
+			repayment.LoanApplication.CurrencyCode != currencyCode {
+			continue
+		}
+		if math.Abs(repayment.AmountDue-repayment.AmountPaid-amount) >= 0.005 {
+			continue
+		}
+		if !seen[repayment.ApplicationID] {
+			seen[repayment.ApplicationID] = true
+			applicationIds = append(applicationIds, repayment.ApplicationID)
+		}
+	}
+	return
+}
+
+// extractReferences collects the installment payment references and application IDs quoted in free text
+// Parameters:
+// - texts: the reference and remittance information of a statement line
+// Returns:
+// - []string with the distinct payment references, upper cased
+// - []string with the distinct application IDs, lower cased
+func extractReferences(texts ...string) (paymentReferences []string, applicationIds []string) {
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range installmentReferencePattern.FindAllString(text, -1) {
+			reference := strings.ToUpper(match)
+			if !seen[reference] {
+				seen[reference] = true
+				paymentReferences = append(paymentReferences, reference)
+			}
+		}
+		for _, match := range applicationIdPattern.FindAllString(text, -1) {
+			id := strings.ToLower(match)
+			if !seen[id] {
+				seen[id] = true
+				applicationIds = append(applicationIds, id)
+			}
+		}
+	}
+	return
+}
+
+// lineFingerprint identifies a statement line by its content, for banks that do not send a reference
+func lineFingerprint(line statementLine) string {
+	hasher := sha256.New()
+	for _, value := range []string{
+		line.BookingDate.Format("2006-01-02"),
+		strconv.FormatFloat(line.Amount, 'f', 2, 64),
+		line.CurrencyCode,
+		line.CreditDebit,
+		line.PaymentReference,
+		line.VirtualAccount,
+		line.Counterparty,
+		line.Description,
+	} {
+		hasher.Write([]byte(value))
+		hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// statementBankReference returns the bank's reference for a line, or a reference derived from its content
+func statementBankReference(line statementLine, fingerprint string, occurrence int) string {
+	if line.BankReference != "" {
+		return line.BankReference
+	}
+	return fmt.Sprintf("STMT-%v-%d", fingerprint[:32], occurrence)
+}
+
+// exceptionLine marks a statement line as an exception with the given reason
+func exceptionLine(line models.BankStatementLine, reason string) models.BankStatementLine {
+	line.Status = models.StatementLineStatusException
+	line.ExceptionReason = null.StringFrom(reason)
+	return line
+}
+
+// toStatementException converts an unposted statement line into its report entry
+func toStatementException(line models.BankStatementLine) (exception dto.StatementException) {
+	exception.LineNumber = line.LineNumber
+	if line.BookingDate.Valid {
+		exception.BookingDate = line.BookingDate.Time.Format("2006-01-02")
+	}
+	exception.Amount = strconv.FormatFloat(line.Amount, 'f', 2, 64)
+	exception.CurrencyCode = line.CurrencyCode
+	exception.BankReference = line.BankReference.String
+	exception.PaymentReference = line.PaymentReference.String
+	exception.VirtualAccount = line.VirtualAccount.String
+	exception.Counterparty = line.Counterparty.String
+	exception.Description = line.Description.String
+	exception.ApplicationId = line.ApplicationID.String
+	exception.Reason = line.ExceptionReason.String
+	return
+}
+
+// optionalString converts an empty string into a NULL column value
+func optionalString(value string) null.String {
+	if value == "" {
+		return null.String{}
+	}
+	return null.StringFrom(value)
+}
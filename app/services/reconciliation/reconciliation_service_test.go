@@ -0,0 +1,65 @@
+package reconciliation_service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractReferences(t *testing.T) {
+	references, applicationIds := extractReferences(
+		"ln3f2a9c1b7e-01 and LN3F2A9C1B7E-01",
+		"for loan 3F2A9C1B-7E11-4A5B-9C3D-123456789ABC, LN00000000AA-12 LNXYZ-01")
+
+	assert.Equal(t, []string{"LN3F2A9C1B7E-01", "LN00000000AA-12"}, references)
+	assert.Equal(t, []string{"3f2a9c1b-7e11-4a5b-9c3d-123456789abc"}, applicationIds)
+}
+
+func TestMatchInstallmentAmount(t *testing.T) {
+	approved := models.LoanApplication{Status: models.LoanApplicationStatusApproved, CurrencyCode: "INR"}
+	repayments := []models.Repayment{
+		{ApplicationID: "a1", AmountDue: 1000, AmountPaid: 250, LoanApplication: approved},
+		{ApplicationID: "a1", AmountDue: 750, LoanApplication: approved},
+		{ApplicationID: "a2", AmountDue: 800, LoanApplication: approved},
+		{ApplicationID: "a3", AmountDue: 750, LoanApplication: models.LoanApplication{
+			Status: models.LoanApplicationStatusApproved, CurrencyCode: "USD"}},
+		{ApplicationID: "a4", AmountDue: 750, LoanApplication: models.LoanApplication{
+			Status: models.LoanApplicationStatusPaid, CurrencyCode: "INR"}},
+	}
+
+	assert.Equal(t, []string{"a1"}, matchInstallmentAmount(repayments, 750, "INR"))
+	assert.Empty(t, matchInstallmentAmount(repayments, 700, "INR"))
+}
+
+func TestStatementBankReference(t *testing.T) {
+	line := statementLine{Amount: 100, CurrencyCode: "INR", BookingDate: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)}
+	fingerprint := lineFingerprint(line)
+
+	assert.Equal(t, fingerprint, lineFingerprint(line))
+	assert.NotEqual(t, statementBankReference(line, fingerprint, 1), statementBankReference(line, fingerprint, 2))
+
+	line.BankReference = "TRX-1"
+	assert.Equal(t, "TRX-1", statementBankReference(line, fingerprint, 1))
+}
+
+func TestWriteExceptionReport(t *testing.T) {
+	var report bytes.Buffer
+	err := WriteExceptionReport(&report, []dto.StatementException{{
+		LineNumber:   3,
+		BookingDate:  "2024-05-02",
+		Amount:       "120.00",
+		CurrencyCode: "INR",
+		Description:  "EMI, May",
+		Reason:       "no loan matches the virtual account, payment reference or amount",
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "line_number,booking_date,amount,currency,bank_reference,payment_reference,virtual_account,"+
+		"counterparty,description,application_id,reason\n"+
+		"3,2024-05-02,120.00,INR,,,,,\"EMI, May\",,\"no loan matches the virtual account, payment reference or amount\"\n",
+		report.String())
+}
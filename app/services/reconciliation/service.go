@@ -0,0 +1,28 @@
+package reconciliation_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
+)
+
+// ReconciliationService defines the interface for bank statement reconciliation
+type ReconciliationService interface {
+	// ImportStatement Parses a bank statement, matches its credits to loans and posts the matched ones
+	ImportStatement(request dto.StatementImportRequest, content []byte, user models.User) (dto.StatementImportResponse, dto.HandleError)
+
+	// GetStatementExceptions Lists the credits of an imported statement that could not be posted
+	GetStatementExceptions(request dto.StatementExceptionRequest) (dto.StatementExceptionResponse, dto.HandleError)
+}
+
+// reconciliationService is an implementation of ReconciliationService
+type reconciliationService struct {
+	repaymentSvc repaymentService.RepaymentService
+}
+
+// NewReconciliationService returns a new instance of ReconciliationService
+func NewReconciliationService(repaymentSvc repaymentService.RepaymentService) ReconciliationService {
+	return &reconciliationService{
+		repaymentSvc: repaymentSvc,
+	}
+}
@@ -0,0 +1,399 @@
+package reconciliation_service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// statementLine is a single booked entry read from a bank statement file
+type statementLine struct {
+	LineNumber       int
+	BookingDate      time.Time
+	Amount           float64
+	CurrencyCode     string
+	CreditDebit      string
+	BankReference    string
+	PaymentReference string
+	VirtualAccount   string
+	Counterparty     string
+	Description      string
+}
+
+// utf8BOM is stripped from the start of statement files exported by spreadsheet tools
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvColumnAliases maps the normalised CSV header names banks commonly use to the statement line fields
+var csvColumnAliases = map[string]string{
+	"bookingdate":           "date",
+	"date":                  "date",
+	"valuedate":             "date",
+	"transactiondate":       "date",
+	"amount":                "amount",
+	"creditdebit":           "credit_debit",
+	"cdtdbtind":             "credit_debit",
+	"drcr":                  "credit_debit",
+	"type":                  "credit_debit",
+	"currency":              "currency",
+	"currencycode":          "currency",
+	"ccy":                   "currency",
+	"bankreference":         "bank_reference",
+	"transactionid":         "bank_reference",
+	"transactionreference":  "bank_reference",
+	"acctsvcrref":           "bank_reference",
+	"paymentreference":      "payment_reference",
+	"reference":             "payment_reference",
+	"virtualaccount":        "virtual_account",
+	"account":               "virtual_account",
+	"creditoraccount":       "virtual_account",
+	"counterparty":          "counterparty",
+	"payer":                 "counterparty",
+	"debtor":                "counterparty",
+	"name":                  "counterparty",
+	"description":           "description",
+	"narrative":             "description",
+	"details":               "description",
+	"remittanceinformation": "description",
+}
+
+// statementDateLayouts are the booking date formats accepted in CSV statements
+var statementDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"02/01/2006",
+	"02-01-2006",
+	"02.01.2006",
+}
+
+// detectStatementFormat works out the format of a statement file when the caller did not name one
+// Parameters:
+// - fileName: the name of the uploaded file
+// - content: the raw file content
+// Returns:
+// - string: models.StatementFormatCamt053 for XML files, models.StatementFormatCSV otherwise
+func detectStatementFormat(fileName string, content []byte) string {
+	if strings.EqualFold(filepath.Ext(fileName), ".xml") {
+		return models.StatementFormatCamt053
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(content, utf8BOM)), []byte("<")) {
+		return models.StatementFormatCamt053
+	}
+	return models.StatementFormatCSV
+}
+
+// parseStatement reads every entry of a bank statement file
+// Parameters:
+// - format: models.StatementFormatCSV or models.StatementFormatCamt053
+// - content: the raw file content
+// Returns:
+// - []statementLine with the entries in file order
+// - error when the file cannot be read in the given format
+func parseStatement(format string, content []byte) ([]statementLine, error) {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	switch format {
+	case models.StatementFormatCSV:
+		return parseCSVStatement(content)
+	case models.StatementFormatCamt053:
+		return parseCamt053Statement(content)
+	default:
+		return nil, fmt.Errorf("unsupported statement format: %v", format)
+	}
+}
+
+// parseCSVStatement reads a CSV statement with a header row. Columns are recognised by name, amount and
+// currency are required. Debits are either negative amounts or flagged in a credit/debit column.
+func parseCSVStatement(content []byte) (lines []statementLine, err error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("statement file is empty")
+		}
+		return nil, fmt.Errorf("invalid CSV statement: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for index, name := range header {
+		normalised := strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(name)))
+		if field, ok := csvColumnAliases[normalised]; ok {
+			if _, exists := columns[field]; !exists {
+				columns[field] = index
+			}
+		}
+	}
+
+	for _, required := range []string{"amount", "currency"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV statement has no %v column", required)
+		}
+	}
+
+	value := func(record []string, field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("invalid CSV statement: %v", readErr)
+		}
+
+		// Report the line of the file so operations can find the entry, blank lines are skipped by the reader
+		rowNumber, _ := reader.FieldPos(0)
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		amount, amountErr := parseStatementAmount(value(record, "amount"))
+		if amountErr != nil {
+			return nil, fmt.Errorf("invalid amount at line %v: %v", rowNumber, amountErr)
+		}
+
+		line := statementLine{
+			LineNumber:       rowNumber,
+			Amount:           math.Abs(amount),
+			CurrencyCode:     strings.ToUpper(value(record, "currency")),
+			CreditDebit:      models.StatementLineCredit,
+			BankReference:    value(record, "bank_reference"),
+			PaymentReference: value(record, "payment_reference"),
+			VirtualAccount:   value(record, "virtual_account"),
+			Counterparty:     value(record, "counterparty"),
+			Description:      value(record, "description"),
+		}
+
+		if amount < 0 {
+			line.CreditDebit = models.StatementLineDebit
+		}
+		if indicator := value(record, "credit_debit"); indicator != "" {
+			if line.CreditDebit, err = parseCreditDebit(indicator); err != nil {
+				return nil, fmt.Errorf("invalid credit/debit indicator at line %v: %v", rowNumber, err)
+			}
+		}
+
+		if date := value(record, "date"); date != "" {
+			if line.BookingDate, err = parseStatementDate(date); err != nil {
+				return nil, fmt.Errorf("invalid booking date at line %v: %v", rowNumber, err)
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// parseStatementAmount parses an amount written with optional thousands separators
+func parseStatementAmount(value string) (float64, error) {
+	cleaned := strings.NewReplacer(",", "", " ", "").Replace(value)
+	if cleaned == "" {
+		return 0, fmt.Errorf("amount is empty")
+	}
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	return math.Round(amount*100) / 100, nil
+}
+
+// parseCreditDebit normalises the credit/debit indicators used by banks to CRDT or DBIT
+func parseCreditDebit(value string) (string, error) {
+	switch strings.ToUpper(value) {
+	case "C", "CR", "CRDT", "CREDIT":
+		return models.StatementLineCredit, nil
+	case "D", "DR", "DBIT", "DEBIT":
+		return models.StatementLineDebit, nil
+	default:
+		return "", fmt.Errorf("%q is neither a credit nor a debit", value)
+	}
+}
+
+// parseStatementDate parses a booking date in one of the accepted layouts
+func parseStatementDate(value string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a supported date", value)
+}
+
+// camtDocument is the subset of an ISO 20022 camt.053 bank to customer statement needed for reconciliation.
+// Elements are matched by local name so every camt.053 version is accepted.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtAccount struct {
+	IBAN  string `xml:"IBAN"`
+	Other string `xml:"Othr>Id"`
+}
+
+type camtEntry struct {
+	Amount             camtAmount        `xml:"Amt"`
+	CreditDebit        string            `xml:"CdtDbtInd"`
+	BookingDate        camtDate          `xml:"BookgDt"`
+	AccountServicerRef string            `xml:"AcctSvcrRef"`
+	AdditionalInfo     string            `xml:"AddtlNtryInf"`
+	Transactions       []camtTransaction `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTransaction struct {
+	AccountServicerRef string      `xml:"Refs>AcctSvcrRef"`
+	TransactionID      string      `xml:"Refs>TxId"`
+	EndToEndID         string      `xml:"Refs>EndToEndId"`
+	Amount             *camtAmount `xml:"Amt"`
+	DetailAmount       *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	CreditDebit        string      `xml:"CdtDbtInd"`
+	DebtorName         string      `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPartyName    string      `xml:"RltdPties>Dbtr>Pty>Nm"`
+	CreditorAccount    camtAccount `xml:"RltdPties>CdtrAcct>Id"`
+	Unstructured       []string    `xml:"RmtInf>Ustrd"`
+	CreditorReferences []string    `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	AdditionalInfo     string      `xml:"AddtlTxInf"`
+}
+
+// parseCamt053Statement reads the entries of a camt.053 statement. Batched entries produce one line per
+// transaction detail so that every credit can be matched on its own.
+func parseCamt053Statement(content []byte) (lines []statementLine, err error) {
+	document := camtDocument{}
+	if err = xml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 statement: %v", err)
+	}
+
+	if len(document.Statements) == 0 {
+		return nil, fmt.Errorf("camt.053 statement has no Stmt element")
+	}
+
+	lineNumber := 0
+	for _, statement := range document.Statements {
+		for _, entry := range statement.Entries {
+			bookingDate, dateErr := parseCamtDate(entry.BookingDate)
+			if dateErr != nil {
+				return nil, fmt.Errorf("invalid booking date in entry %v: %v", entry.AccountServicerRef, dateErr)
+			}
+
+			transactions := entry.Transactions
+			if len(transactions) == 0 {
+				transactions = []camtTransaction{{}}
+			}
+
+			for index, transaction := range transactions {
+				lineNumber++
+
+				amount := entry.Amount
+				if transaction.Amount != nil {
+					amount = *transaction.Amount
+				} else if transaction.DetailAmount != nil {
+					amount = *transaction.DetailAmount
+				} else if len(transactions) > 1 {
+					return nil, fmt.Errorf("batched entry %v has a transaction without amount", entry.AccountServicerRef)
+				}
+
+				value, amountErr := parseStatementAmount(strings.TrimSpace(amount.Value))
+				if amountErr != nil {
+					return nil, fmt.Errorf("invalid amount in entry %v: %v", entry.AccountServicerRef, amountErr)
+				}
+
+				indicator := transaction.CreditDebit
+				if indicator == "" {
+					indicator = entry.CreditDebit
+				}
+				creditDebit, indicatorErr := parseCreditDebit(strings.TrimSpace(indicator))
+				if indicatorErr != nil {
+					return nil, fmt.Errorf("invalid CdtDbtInd in entry %v: %v", entry.AccountServicerRef, indicatorErr)
+				}
+
+				line := statementLine{
+					LineNumber:     lineNumber,
+					BookingDate:    bookingDate,
+					Amount:         math.Abs(value),
+					CurrencyCode:   strings.ToUpper(strings.TrimSpace(amount.Currency)),
+					CreditDebit:    creditDebit,
+					BankReference:  camtBankReference(entry, transaction, index, len(transactions)),
+					VirtualAccount: firstNonEmpty(transaction.CreditorAccount.Other, transaction.CreditorAccount.IBAN),
+					Counterparty:   firstNonEmpty(transaction.DebtorName, transaction.DebtorPartyName),
+					Description: strings.TrimSpace(strings.Join(append(transaction.Unstructured,
+						transaction.AdditionalInfo, entry.AdditionalInfo), " ")),
+				}
+
+				if len(transaction.CreditorReferences) > 0 {
+					line.PaymentReference = transaction.CreditorReferences[0]
+				} else if transaction.EndToEndID != "NOTPROVIDED" {
+					line.PaymentReference = transaction.EndToEndID
+				}
+
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// camtBankReference picks the bank's own reference for a transaction, falling back to the entry reference
+func camtBankReference(entry camtEntry, transaction camtTransaction, index int, total int) string {
+	if reference := firstNonEmpty(transaction.AccountServicerRef, transaction.TransactionID); reference != "" {
+		return reference
+	}
+	if entry.AccountServicerRef == "" || total == 1 {
+		return entry.AccountServicerRef
+	}
+	return fmt.Sprintf("%v/%d", entry.AccountServicerRef, index+1)
+}
+
+// parseCamtDate reads a camt.053 date or date time element
+func parseCamtDate(date camtDate) (time.Time, error) {
+	if date.Date != "" {
+		return time.Parse("2006-01-02", strings.TrimSpace(date.Date))
+	}
+	if date.DateTime != "" {
+		return parseStatementDate(strings.TrimSpace(date.DateTime))
+	}
+	return time.Time{}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package reconciliation_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleCSVStatement = "\xEF\xBB\xBFBooking Date,Amount,Currency,Bank Reference,Reference,Virtual Account,Payer,Narrative\n" +
+	"2024-05-02,\"1,250.50\",inr,TRX-1,LN3F2A9C1B7E-01,,Jane Doe,May EMI\n" +
+	"03/05/2024,-99.00,INR,TRX-2,,,Bank,Account fee\n" +
+	"\n" +
+	"2024-05-04,500,INR,,,VA000000000042,John Doe,\n"
+
+const sampleCamt053Statement = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-1</Id>
+      <Ntry>
+        <Amt Ccy="INR">1000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2024-05-02</Dt></BookgDt>
+        <AcctSvcrRef>ENTRY-1</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+            <RltdPties>
+              <Dbtr><Nm>Jane Doe</Nm></Dbtr>
+              <CdtrAcct><Id><Othr><Id>VA000000000042</Id></Othr></Id></CdtrAcct>
+            </RltdPties>
+            <RmtInf><Ustrd>Loan repayment</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="INR">700.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><DtTm>2024-05-03T10:15:00+05:30</DtTm></BookgDt>
+        <AcctSvcrRef>BATCH-9</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Amt Ccy="INR">300.00</Amt>
+            <RmtInf><Strd><CdtrRefInf><Ref>LN3F2A9C1B7E-02</Ref></CdtrRefInf></Strd></RmtInf>
+          </TxDtls>
+          <TxDtls>
+            <Refs><AcctSvcrRef>TX-77</AcctSvcrRef></Refs>
+            <AmtDtls><TxAmt><Amt Ccy="INR">400.00</Amt></TxAmt></AmtDtls>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="INR">20.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2024-05-03</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func TestDetectStatementFormat(t *testing.T) {
+	assert.Equal(t, models.StatementFormatCamt053, detectStatementFormat("statement.XML", []byte("a,b")))
+	assert.Equal(t, models.StatementFormatCamt053, detectStatementFormat("upload", []byte("\n  <Document/>")))
+	assert.Equal(t, models.StatementFormatCSV, detectStatementFormat("statement.csv", []byte("Amount,Currency")))
+}
+
+func TestParseCSVStatement(t *testing.T) {
+	lines, err := parseStatement(models.StatementFormatCSV, []byte(sampleCSVStatement))
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 3)
+
+	assert.Equal(t, 2, lines[0].LineNumber)
+	assert.Equal(t, 1250.50, lines[0].Amount)
+	assert.Equal(t, "INR", lines[0].CurrencyCode)
+	assert.Equal(t, models.StatementLineCredit, lines[0].CreditDebit)
+	assert.Equal(t, "TRX-1", lines[0].BankReference)
+	assert.Equal(t, "LN3F2A9C1B7E-01", lines[0].PaymentReference)
+	assert.Equal(t, "Jane Doe", lines[0].Counterparty)
+	assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), lines[0].BookingDate)
+
+	assert.Equal(t, float64(99), lines[1].Amount)
+	assert.Equal(t, models.StatementLineDebit, lines[1].CreditDebit)
+	assert.Equal(t, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), lines[1].BookingDate)
+
+	assert.Equal(t, 5, lines[2].LineNumber)
+	assert.Equal(t, "VA000000000042", lines[2].VirtualAccount)
+	assert.Empty(t, lines[2].BankReference)
+}
+
+func TestParseCSVStatement_CreditDebitColumn(t *testing.T) {
+	lines, err := parseStatement(models.StatementFormatCSV, []byte("amount,currency,type\n10,EUR,DR\n20,EUR,Credit\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatementLineDebit, lines[0].CreditDebit)
+	assert.Equal(t, models.StatementLineCredit, lines[1].CreditDebit)
+}
+
+func TestParseCSVStatement_Invalid(t *testing.T) {
+	_, err := parseStatement(models.StatementFormatCSV, []byte("date,amount\n2024-05-01,10\n"))
+	assert.EqualError(t, err, "CSV statement has no currency column")
+
+	_, err = parseStatement(models.StatementFormatCSV, []byte("amount,currency\nten,INR\n"))
+	assert.EqualError(t, err, `invalid amount at line 2: "ten" is not a number`)
+
+	_, err = parseStatement(models.StatementFormatCSV, []byte(""))
+	assert.EqualError(t, err, "statement file is empty")
+}
+
+func TestParseCamt053Statement(t *testing.T) {
+	lines, err := parseStatement(models.StatementFormatCamt053, []byte(sampleCamt053Statement))
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 4)
+
+	assert.Equal(t, float64(1000), lines[0].Amount)
+	assert.Equal(t, "INR", lines[0].CurrencyCode)
+	assert.Equal(t, "ENTRY-1", lines[0].BankReference)
+	assert.Equal(t, "VA000000000042", lines[0].VirtualAccount)
+	assert.Equal(t, "Jane Doe", lines[0].Counterparty)
+	assert.Equal(t, "Loan repayment", lines[0].Description)
+	assert.Empty(t, lines[0].PaymentReference)
+
+	assert.Equal(t, float64(300), lines[1].Amount)
+	assert.Equal(t, "BATCH-9/1", lines[1].BankReference)
+	assert.Equal(t, "LN3F2A9C1B7E-02", lines[1].PaymentReference)
+	assert.Equal(t, 2024, lines[1].BookingDate.Year())
+
+	assert.Equal(t, float64(400), lines[2].Amount)
+	assert.Equal(t, "TX-77", lines[2].BankReference)
+
+	assert.Equal(t, models.StatementLineDebit, lines[3].CreditDebit)
+}
+
+func TestParseCamt053Statement_Invalid(t *testing.T) {
+	_, err := parseStatement(models.StatementFormatCamt053, []byte("<Document><Other/></Document>"))
+	assert.EqualError(t, err, "camt.053 statement has no Stmt element")
+
+	_, err = parseStatement(models.StatementFormatCamt053, []byte("<Document>"))
+	assert.Error(t, err)
+}
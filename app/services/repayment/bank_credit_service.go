@@ -0,0 +1,103 @@
+package repayment_service
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm/clause"
+)
+
+// PostBankCredit books a credit received directly on the bank account, e.g. a transfer matched during
+// statement reconciliation. The funds have already arrived, so the payment is settled and allocated to
+// the pending installments straight away.
+// Parameters:
+// - request: dto.BankCreditRequest containing the application ID, currency, amount and bank reference
+// Returns:
+// - dto.RepaymentResponse with the settled payment
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) PostBankCredit(request dto.BankCreditRequest) (
+	response dto.RepaymentResponse, handle dto.HandleError) {
+	application := models.LoanApplication{}
+	application, _ = application.FindByPrimaryKey(request.ApplicationID)
+	if application.ApplicationID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	if err := repaymentsClosed(application); err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	// Written-off loans still take money in, it is booked as a recovery
+	if application.Status != models.LoanApplicationStatusApproved &&
+		application.Status != models.LoanApplicationStatusWrittenOff {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application is %v and does not accept repayments", application.Status)
+		return
+	}
+
+	if application.CurrencyCode != request.CurrencyCode {
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("credit currency %v does not match loan currency %v",
+			request.CurrencyCode, application.CurrencyCode)
+		return
+	}
+
+	// A bank reference can only ever be booked once
+	var paymentCondition []db.WhereCondition
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.Gateway,
+		Condition: "=",
+		Value:     models.PaymentChannelBankTransfer,
+	})
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.GatewayReference,
+		Condition: "=",
+		Value:     request.BankReference,
+	})
+
+	existing := models.Payment{}
+	existing, _ = existing.FindOneByCondition(paymentCondition)
+	if existing.PaymentID != "" {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("bank reference %v already posted as payment %v", request.BankReference, existing.PaymentID)
+		return
+	}
+
+	payment := models.Payment{
+		PaymentID:        uuid.New().String(),
+		Amount:           request.Amount,
+		ApplicationID:    application.ApplicationID,
+		CurrencyCode:     application.CurrencyCode,
+		Status:           models.PaymentStatusPending,
+		Gateway:          null.StringFrom(models.PaymentChannelBankTransfer),
+		GatewayReference: null.StringFrom(request.BankReference),
+	}
+
+	if err := db.MysqlDB.Omit(clause.Associations).Create(&payment).Error; err != nil {
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	// Allocate the credit, drop the payment again if that fails so the line can be posted on a later import
+	if _, handle = s.applyGatewayStatus(payment.PaymentID, models.PaymentStatusSettled, ""); handle.Status < 0 {
+		if err := db.MysqlDB.Delete(&payment).Error; err != nil {
+			logger.Sugar.Error("failed to remove unallocated bank credit: ", err)
+		}
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Bank credit posted successfully"
+	response.Data.PaymentId = payment.PaymentID
+	response.Data.PaymentStatus = models.PaymentStatusSettled
+	return
+}
@@ -35,6 +35,21 @@ func (m *MockRepaymentService) EXPECT() *MockRepaymentServiceMockRecorder {
 	return m.recorder
 }
 
+// ApplyPaymentStatus mocks base method.
+func (m *MockRepaymentService) ApplyPaymentStatus(request dto.PaymentStatusRequest) (dto.PaymentSyncResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPaymentStatus", request)
+	ret0, _ := ret[0].(dto.PaymentSyncResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ApplyPaymentStatus indicates an expected call of ApplyPaymentStatus.
+func (mr *MockRepaymentServiceMockRecorder) ApplyPaymentStatus(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPaymentStatus", reflect.TypeOf((*MockRepaymentService)(nil).ApplyPaymentStatus), request)
+}
+
 // CalculateRepaymentSchedule mocks base method.
 func (m *MockRepaymentService) CalculateRepaymentSchedule(application *models.LoanApplication) ([]models.Repayment, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,81 @@ func (mr *MockRepaymentServiceMockRecorder) CalculateRepaymentSchedule(applicati
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateRepaymentSchedule", reflect.TypeOf((*MockRepaymentService)(nil).CalculateRepaymentSchedule), application)
 }
 
+// HandleGatewayWebhook mocks base method.
+func (m *MockRepaymentService) HandleGatewayWebhook(request dto.GatewayWebhookRequest, payload []byte, signature string) (dto.GatewayWebhookResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleGatewayWebhook", request, payload, signature)
+	ret0, _ := ret[0].(dto.GatewayWebhookResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// HandleGatewayWebhook indicates an expected call of HandleGatewayWebhook.
+func (mr *MockRepaymentServiceMockRecorder) HandleGatewayWebhook(request, payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleGatewayWebhook", reflect.TypeOf((*MockRepaymentService)(nil).HandleGatewayWebhook), request, payload, signature)
+}
+
+// PostBankCredit mocks base method.
+func (m *MockRepaymentService) PostBankCredit(request dto.BankCreditRequest) (dto.RepaymentResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBankCredit", request)
+	ret0, _ := ret[0].(dto.RepaymentResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// PostBankCredit indicates an expected call of PostBankCredit.
+func (mr *MockRepaymentServiceMockRecorder) PostBankCredit(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBankCredit", reflect.TypeOf((*MockRepaymentService)(nil).PostBankCredit), request)
+}
+
+// RefundExcessCredit mocks base method.
+func (m *MockRepaymentService) RefundExcessCredit(request dto.RefundRequest, user models.User) (dto.RefundResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundExcessCredit", request, user)
+	ret0, _ := ret[0].(dto.RefundResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RefundExcessCredit indicates an expected call of RefundExcessCredit.
+func (mr *MockRepaymentServiceMockRecorder) RefundExcessCredit(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundExcessCredit", reflect.TypeOf((*MockRepaymentService)(nil).RefundExcessCredit), request, user)
+}
+
+// ReversePayment mocks base method.
+func (m *MockRepaymentService) ReversePayment(request dto.PaymentReversalRequest, user models.User) (dto.PaymentReversalResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReversePayment", request, user)
+	ret0, _ := ret[0].(dto.PaymentReversalResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ReversePayment indicates an expected call of ReversePayment.
+func (mr *MockRepaymentServiceMockRecorder) ReversePayment(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReversePayment", reflect.TypeOf((*MockRepaymentService)(nil).ReversePayment), request, user)
+}
+
+// SyncPaymentStatus mocks base method.
+func (m *MockRepaymentService) SyncPaymentStatus(request dto.PaymentSyncRequest, user models.User) (dto.PaymentSyncResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPaymentStatus", request, user)
+	ret0, _ := ret[0].(dto.PaymentSyncResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// SyncPaymentStatus indicates an expected call of SyncPaymentStatus.
+func (mr *MockRepaymentServiceMockRecorder) SyncPaymentStatus(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPaymentStatus", reflect.TypeOf((*MockRepaymentService)(nil).SyncPaymentStatus), request, user)
+}
+
 // UpdateRepayment mocks base method.
 func (m *MockRepaymentService) UpdateRepayment(request dto.RepaymentRequest, user models.User) (dto.RepaymentResponse, dto.HandleError) {
 	m.ctrl.T.Helper()
@@ -1,18 +1,34 @@
 package repayment_service
 
 import (
+	"errors"
 	"fmt"
 	"github.com/Rhymond/go-money"
+	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
 	db "github.com/nishanthrk/aspire-lms/app/database"
 	"github.com/nishanthrk/aspire-lms/app/dto"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"math"
 	"strings"
 	"time"
 )
 
+const (
+	// maxRepaymentAttempts is how many times a payment is tried when its transaction hits a lock conflict
+	maxRepaymentAttempts = 3
+
+	// MySQL error numbers for a deadlock and a lock wait timeout
+	mysqlErrDeadlock        = 1213
+	mysqlErrLockWaitTimeout = 1205
+)
+
 // CalculateRepaymentSchedule generates a repayment schedule for a given loan application.
 // Parameters:
 // - application: pointer to the loan application model containing loan details
@@ -78,12 +94,27 @@ func (s *repaymentService) CalculateRepaymentSchedule(application *models.LoanAp
 			PrincipleAmount:   principle.AsMajorUnits(),
 			InterestAmount:    interest.AsMajorUnits(),
 			Status:            models.LoanApplicationStatusPending,
+			PaymentReference:  installmentReference(application.ApplicationID, i+1),
 		})
 	}
 
 	return
 }
 
+// installmentReference builds the reference a customer quotes when paying an installment by bank transfer
+// Parameters:
+// - applicationId: the ID of the loan application
+// - installmentNumber: the number of the installment
+// Returns:
+// - string: the payment reference, e.g. "LN3F2A9C1B7E-01"
+func installmentReference(applicationId string, installmentNumber int) string {
+	prefix := strings.ToUpper(strings.ReplaceAll(applicationId, "-", ""))
+	if len(prefix) > 10 {
+		prefix = prefix[:10]
+	}
+	return fmt.Sprintf("LN%s-%02d", prefix, installmentNumber)
+}
+
 // addPaymentInterval calculates the next payment date based on the term unit and iteration.
 // Parameters:
 // - startDate: the initial payment start date
@@ -178,72 +209,317 @@ func (s *repaymentService) UpdateRepayment(request dto.RepaymentRequest, user mo
 		return
 	}
 
-	// Check if the application has already been paid
-	if application.Status == models.LoanApplicationStatusPaid {
+	// Check if the application has already been paid off or closed
+	if err := repaymentsClosed(application); err != nil {
 		handle.Status = -2
-		handle.Errors = fmt.Errorf("all the installments are cleared")
+		handle.Errors = err
 		return
 	}
 
-	// Create a new payment entry
+	// Check if the user takes part in this application
+	if err := authorizationService.AuthorizeApplication(user, application.ApplicationID,
+		authorizationService.Rule{}); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	// Create a new payment entry, funds are only allocated once the gateway settles it
 	payment := models.Payment{
 		PaymentID:     uuid.New().String(),
 		Amount:        request.PaymentAmount,
 		ApplicationID: application.ApplicationID,
 		CurrencyCode:  application.CurrencyCode,
-		Status:        "RECEIVED",
+		Status:        models.PaymentStatusPending,
 	}
 
-	var participantCondition, repaymentCondition []db.WhereCondition
-
-	// Set conditions to find the participant
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.UserID,
-		Condition: "=",
-		Value:     user.UserID,
+	// Register the payment with the gateway
+	intent, err := s.gateway.CreatePaymentIntent(dto.PaymentIntentRequest{
+		PaymentID:     payment.PaymentID,
+		ApplicationID: payment.ApplicationID,
+		CurrencyCode:  payment.CurrencyCode,
+		Amount:        payment.Amount,
 	})
+	if err != nil {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("failed to initiate payment: %v", err)
+		return
+	}
 
-	participantCondition = append(participantCondition, db.WhereCondition{
-		Key:       models.LoanApplicationParticipantColumns.ApplicationID,
-		Condition: "=",
-		Value:     application.ApplicationID,
-	})
+	payment.Gateway = null.StringFrom(intent.Gateway)
+	payment.GatewayReference = null.StringFrom(intent.Reference)
+
+	// Save the payment
+	if err := db.MysqlDB.Omit(clause.Associations).Create(&payment).Error; err != nil {
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
 
-	// Find the participant
-	participant := models.LoanApplicationParticipant{}
-	participant, _ = participant.FindOneByCondition(participantCondition)
+	response.Status = 1
+	response.Message = "Payment initiated, it will be allocated once settled by the gateway"
+	response.Data.PaymentId = payment.PaymentID
+	response.Data.PaymentStatus = payment.Status
+	response.Data.GatewayReference = intent.Reference
+	response.Data.CheckoutURL = intent.CheckoutURL
+	return
+}
+
+// repaymentsClosed explains why a loan that has been paid off takes no further repayments
+// Parameters:
+// - application: the loan a repayment is made on
+// Returns:
+// - error when the loan is paid off or closed, nil otherwise
+func repaymentsClosed(application models.LoanApplication) error {
+	switch application.Status {
+	case models.LoanApplicationStatusPaid:
+		return fmt.Errorf("loan %v has been repaid in full and is awaiting closure, no further repayments "+
+			"are accepted", application.ApplicationID)
+	case models.LoanApplicationStatusClosed:
+		return fmt.Errorf("loan %v was closed on %v, no further repayments are accepted", application.ApplicationID,
+			application.ClosedDate.Time.Format("2006-01-02"))
+	}
+	return nil
+}
 
-	// Check if the user has permission to update this application
-	if participant.ParticipantID == "" {
+// HandleGatewayWebhook applies a payment status update pushed by the gateway
+// Parameters:
+// - request: dto.GatewayWebhookRequest containing the gateway reference and new status
+// - payload: the raw request body the signature was computed over
+// - signature: the signature sent by the gateway
+// Returns:
+// - dto.GatewayWebhookResponse with the result
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) HandleGatewayWebhook(request dto.GatewayWebhookRequest, payload []byte, signature string) (
+	response dto.GatewayWebhookResponse, handle dto.HandleError) {
+	// Reject anything not signed by the gateway
+	if !s.gateway.VerifySignature(payload, signature) {
 		handle.Status = -1
-		handle.Errors = fmt.Errorf("don't have permission to this application: %v", application.ApplicationID)
+		handle.Errors = fmt.Errorf("invalid webhook signature")
 		return
 	}
 
-	// Set conditions to find pending repayments
-	repaymentCondition = append(repaymentCondition, db.WhereCondition{
-		Key:       models.RepaymentColumns.ApplicationID,
+	// Bank transfers and direct debits keep their own references in the same column, only the payments collected
+	// through this gateway can be updated by it
+	var paymentCondition []db.WhereCondition
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.GatewayReference,
 		Condition: "=",
-		Value:     application.ApplicationID,
+		Value:     request.Reference,
 	})
-
-	repaymentCondition = append(repaymentCondition, db.WhereCondition{
-		Key:       models.RepaymentColumns.Status,
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.Gateway,
 		Condition: "=",
-		Value:     models.LoanApplicationStatusPending,
+		Value:     s.gateway.Name(),
 	})
 
-	// Find all pending repayments
-	repayment := models.Repayment{}
-	repayments, _ := repayment.FindAllByCondition(repaymentCondition)
+	payment := models.Payment{}
+	payment, _ = payment.FindOneByCondition(paymentCondition)
+	if payment.PaymentID == "" {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("payment %v not found", request.Reference)
+		return
+	}
 
-	// Check if any repayments are found
-	if len(repayments) == 0 {
+	// The gateway must settle exactly the amount that was requested, compared in cents
+	if request.Status == models.PaymentStatusSettled &&
+		(request.Amount <= 0 || math.Round(request.Amount*100) != math.Round(payment.Amount*100)) {
 		handle.Status = -3
-		handle.Errors = fmt.Errorf("failed to find repayments")
+		handle.Errors = fmt.Errorf("settled amount %v does not match payment amount %v", request.Amount, payment.Amount)
+		return
+	}
+
+	applied, handle := s.applyGatewayStatus(payment.PaymentID, request.Status, request.FailureReason)
+	if handle.Status < 0 {
+		return
+	}
+
+	response.Status = 1
+	if applied {
+		response.Message = fmt.Sprintf("Payment marked as %v", request.Status)
+	} else {
+		response.Message = "Payment already processed"
+	}
+	return
+}
+
+// SyncPaymentStatus polls the gateway for a payment whose webhook may have been lost and applies the result
+// Parameters:
+// - request: dto.PaymentSyncRequest containing the application ID and payment ID
+// - user: models.User representing the employee requesting the sync
+// Returns:
+// - dto.PaymentSyncResponse with the resulting payment status
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) SyncPaymentStatus(request dto.PaymentSyncRequest, user models.User) (
+	response dto.PaymentSyncResponse, handle dto.HandleError) {
+	// Check if the employee is allocated to the application and may sync its payments
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionPaymentSync,
+	}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	payment := models.Payment{}
+	payment, _ = payment.FindByPrimaryKey(request.PaymentID)
+	if payment.PaymentID == "" || payment.ApplicationID != request.ApplicationID {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("payment %v not found for this application", request.PaymentID)
+		return
+	}
+
+	// Only the gateway can be polled, other channels report their outcome through their own callbacks
+	if payment.Status == models.PaymentStatusPending && payment.Gateway.String != s.gateway.Name() {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("payment %v was not collected through the gateway", request.PaymentID)
+		return
+	}
+
+	if payment.Status == models.PaymentStatusPending {
+		status, err := s.gateway.FetchStatus(payment.GatewayReference.String)
+		if err != nil {
+			handle.Status = -3
+			handle.Errors = err
+			return
+		}
+
+		if status == models.PaymentStatusSettled || status == models.PaymentStatusFailed {
+			if _, handle = s.applyGatewayStatus(payment.PaymentID, status, ""); handle.Status < 0 {
+				return
+			}
+		}
+
+		payment, _ = payment.FindByPrimaryKey(request.PaymentID)
+	}
+
+	response.Status = 1
+	response.Message = "Payment status synchronised"
+	response.Data.PaymentId = payment.PaymentID
+	response.Data.PaymentStatus = payment.Status
+	return
+}
+
+// ApplyPaymentStatus settles or fails a pending payment raised through another collection channel, such
+// as a direct debit, whose provider reported the outcome
+// Parameters:
+// - request: dto.PaymentStatusRequest containing the payment ID, the new status and any failure reason
+// Returns:
+// - dto.PaymentSyncResponse with the resulting payment status
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) ApplyPaymentStatus(request dto.PaymentStatusRequest) (
+	response dto.PaymentSyncResponse, handle dto.HandleError) {
+	payment := models.Payment{}
+	payment, _ = payment.FindByPrimaryKey(request.PaymentID)
+	if payment.PaymentID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("payment %v not found", request.PaymentID)
+		return
+	}
+
+	applied, handle := s.applyGatewayStatus(payment.PaymentID, request.Status, request.FailureReason)
+	if handle.Status < 0 {
+		return
+	}
+
+	payment, _ = payment.FindByPrimaryKey(request.PaymentID)
+	response.Status = 1
+	if applied {
+		response.Message = fmt.Sprintf("Payment marked as %v", request.Status)
+	} else {
+		response.Message = "Payment already processed"
+	}
+	response.Data.PaymentId = payment.PaymentID
+	response.Data.PaymentStatus = payment.Status
+	return
+}
+
+// applyGatewayStatus settles or fails a pending payment, retrying when the database aborts the
+// transaction because of a lock conflict
+// Parameters:
+// - paymentId: the payment to update
+// - status: the gateway status, SETTLED or FAILED
+// - failureReason: the reason reported by the gateway for a failed payment
+// Returns:
+// - bool reporting whether the payment changed, false when it had already been processed
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) applyGatewayStatus(paymentId string, status string, failureReason string) (
+	applied bool, handle dto.HandleError) {
+	for attempt := 1; attempt <= maxRepaymentAttempts; attempt++ {
+		switch status {
+		case models.PaymentStatusSettled:
+			applied, handle = s.settlePayment(paymentId)
+		case models.PaymentStatusFailed:
+			applied, handle = s.failPayment(paymentId, failureReason)
+		default:
+			handle.Status = -4
+			handle.Errors = fmt.Errorf("unsupported payment status: %v", status)
+		}
+		if handle.Status >= 0 || !isRetryableError(handle.Errors) {
+			break
+		}
+	}
+	return
+}
+
+// failPayment marks a pending payment as failed, nothing was allocated for it so nothing is unwound
+// Parameters:
+// - paymentId: the payment to fail
+// - failureReason: the reason reported by the gateway
+// Returns:
+// - bool reporting whether the payment changed
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) failPayment(paymentId string, failureReason string) (applied bool, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	payment := models.Payment{}
+	payment, err := payment.FindByPrimaryKeyForUpdate(tx, paymentId)
+	if err != nil || payment.PaymentID == "" {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("payment %v not found", paymentId)
+		return
+	}
+
+	if payment.Status != models.PaymentStatusPending {
+		tx.Rollback()
+		return
+	}
+
+	payment.Status = models.PaymentStatusFailed
+	if failureReason != "" {
+		payment.FailureReason = null.StringFrom(failureReason)
+	}
+	if err := tx.Omit(clause.Associations).Save(&payment).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
 		return
 	}
 
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	applied = true
+	return
+}
+
+// settlePayment marks a pending payment as settled and allocates it to the fees owed and then to the pending
+// installments in a single transaction. The loan application and its pending repayments are locked with SELECT ... FOR UPDATE
+// so that two settlements on the same loan are allocated one after the other instead of against the
+// same installment. Money settling after the loan was cleared is kept as excess credit.
+// Parameters:
+// - paymentId: the payment to settle
+// Returns:
+// - bool reporting whether the payment changed, false when it had already been processed
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) settlePayment(paymentId string) (applied bool, handle dto.HandleError) {
 	// Begin a new transaction
 	tx := db.MysqlDB.Begin()
 
@@ -256,92 +532,143 @@ func (s *repaymentService) UpdateRepayment(request dto.RepaymentRequest, user mo
 		}
 	}()
 
-	// Save the payment
-	if err := tx.Save(&payment).Error; err != nil {
+	payment := models.Payment{}
+	payment, err := payment.FindByPrimaryKey(paymentId)
+	if err != nil || payment.PaymentID == "" {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("payment %v not found", paymentId)
+		return
+	}
+
+	// Lock the loan application first, concurrent settlements on the same loan wait here
+	application := models.LoanApplication{}
+	application, err = application.FindByPrimaryKeyForUpdate(tx, payment.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	// Re-read the payment under lock, a duplicate webhook might have settled it meanwhile
+	payment, err = payment.FindByPrimaryKeyForUpdate(tx, paymentId)
+	if err != nil {
 		tx.Rollback()
 		handle.Status = -5
 		handle.Errors = err
 		return
 	}
 
-	var repaymentPaymentLogs []models.RepaymentPaymentLog
-	var updateRepayments []models.Repayment
-	repaymentAmount := request.PaymentAmount
+	if payment.Status != models.PaymentStatusPending {
+		tx.Rollback()
+		return
+	}
 
-	// Iterate over each repayment
-	for i := range repayments {
-		repayment = repayments[i]
+	// Nothing is left on book for a written-off loan, money recovered on it is income and not allocated
+	if application.Status == models.LoanApplicationStatusWrittenOff {
+		handle = s.settleRecovery(tx, payment)
+		applied = handle.Status >= 0
+		return
+	}
 
-		// Process only pending repayments
-		if repayment.Status != models.LoanApplicationStatusPaid {
-			amountDueRemaining := repayment.AmountDue - repayment.AmountPaid
-
-			// Create a new repayment payment log entry
-			repaymentPaymentLog := models.RepaymentPaymentLog{
-				LogID:       uuid.New().String(),
-				RepaymentID: repayment.RepaymentID,
-				PaymentID:   payment.PaymentID,
-				Amount:      0,
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			}
+	var repaymentCondition []db.WhereCondition
 
-			// If repayment amount is greater than or equal to the remaining amount due
-			if repaymentAmount >= amountDueRemaining {
-				repaymentPaymentLog.Amount = amountDueRemaining
-				repayment.AmountPaid += amountDueRemaining
-				repayment.Status = models.LoanApplicationStatusPaid
-				repayment.PaymentDate = null.TimeFrom(time.Now())
-				repayment.OutstandingBalance = null.FloatFrom(0)
-				repaymentAmount -= amountDueRemaining
-			} else {
-				// If repayment amount is less than the remaining amount due
-				repaymentPaymentLog.Amount = repaymentAmount
-				repayment.AmountPaid += repaymentAmount
-				repayment.OutstandingBalance = null.FloatFrom(repayment.AmountDue - repayment.AmountPaid)
-				repaymentAmount = 0
-			}
+	// Set conditions to find pending repayments
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
 
-			// Add the updated repayment and log to the respective slices
-			updateRepayments = append(updateRepayments, repayment)
-			repaymentPaymentLogs = append(repaymentPaymentLogs, repaymentPaymentLog)
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusPending,
+	})
 
-			// Break the loop if the repayment amount is exhausted
-			if repaymentAmount == 0 {
-				break
-			}
-		}
+	// Find all pending repayments now that no other payment can change them
+	repayment := models.Repayment{}
+	repayments, err := repayment.FindAllByConditionForUpdate(tx, repaymentCondition)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
 	}
 
-	// Save the repayment payment logs
-	if err := tx.Save(&repaymentPaymentLogs).Error; err != nil {
+	// Fees charged to the loan are collected before any installment
+	journalLine := models.JournalLine{}
+	feesOwed, err := journalLine.ApplicationBalance(tx, models.LedgerAccountFeesReceivable, application.ApplicationID)
+	if err != nil {
 		tx.Rollback()
-		handle.Status = -6
+		handle.Status = -3
 		handle.Errors = err
 		return
 	}
+	feesPaid := collectFees(payment.Amount, feesOwed)
+
+	updateRepayments, repaymentPaymentLogs, excessAmount := allocatePayment(repayments, payment.PaymentID,
+		(math.Round(payment.Amount*100)-math.Round(feesPaid*100))/100)
 
-	// Save the updated repayments
-	if err := tx.Save(&updateRepayments).Error; err != nil {
+	// Mark the payment as settled, anything left after clearing every installment is kept as excess credit
+	payment.Status = models.PaymentStatusSettled
+	payment.ExcessAmount = excessAmount
+	payment.SettledAt = null.TimeFrom(time.Now())
+	if err := tx.Omit(clause.Associations).Save(&payment).Error; err != nil {
 		tx.Rollback()
-		handle.Status = -7
+		handle.Status = -5
 		handle.Errors = err
 		return
 	}
 
-	// Check if all repayments are paid
-	allPaid := true
-	for _, _repayment := range updateRepayments {
-		if _repayment.Status != models.LoanApplicationStatusPaid {
-			allPaid = false
-			break
+	if len(repaymentPaymentLogs) > 0 {
+		// Save the repayment payment logs
+		if err := tx.Save(&repaymentPaymentLogs).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -6
+			handle.Errors = err
+			return
+		}
+
+		// Save the updated repayments
+		if err := tx.Omit(clause.Associations).Save(&updateRepayments).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -7
+			handle.Errors = err
+			return
 		}
 	}
 
-	// Update the application status if all repayments are paid
-	if allPaid {
-		application.Status = models.LoanApplicationStatusPaid
-		if err := tx.Save(&application).Error; err != nil {
+	// Record the settled money in the general ledger
+	allocation := splitAllocation(updateRepayments, repaymentPaymentLogs)
+	allocation.Fees = feesPaid
+	if err := s.ledger.PostRepayment(tx, payment, allocation); err != nil {
+		tx.Rollback()
+		handle.Status = -10
+		handle.Errors = err
+		return
+	}
+
+	// Update the application status once every pending installment has been paid
+	allPaid := len(updateRepayments) > 0 && len(updateRepayments) == len(repayments) &&
+		updateRepayments[len(updateRepayments)-1].Status == models.LoanApplicationStatusPaid
+	if allPaid || excessAmount > 0 {
+		if allPaid {
+			application.Status = models.LoanApplicationStatusPaid
+
+			// Interest accrued beyond what the schedule charged was never earned, take it back out of income
+			_, err := s.ledger.PostAccrualReversal(tx, application, payment.PaymentID,
+				fmt.Sprintf("Accrued interest reversed, loan %v paid off", application.ApplicationID))
+			if err != nil {
+				tx.Rollback()
+				handle.Status = -10
+				handle.Errors = err
+				return
+			}
+		}
+		application.ExcessCredit += excessAmount
+		if err := tx.Omit(clause.Associations).Save(&application).Error; err != nil {
 			tx.Rollback()
 			handle.Status = -8
 			handle.Errors = err
@@ -349,6 +676,16 @@ func (s *repaymentService) UpdateRepayment(request dto.RepaymentRequest, user mo
 		}
 	}
 
+	// Close the loan straight away when paying it off left nothing owed on it, excess credit waits for its refund
+	if allPaid {
+		if _, err := s.closure.CloseIfSettled(tx, &application, ""); err != nil {
+			tx.Rollback()
+			handle.Status = -11
+			handle.Errors = err
+			return
+		}
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
@@ -357,8 +694,150 @@ func (s *repaymentService) UpdateRepayment(request dto.RepaymentRequest, user mo
 		return
 	}
 
-	response.Status = 1
-	response.Message = "Payment processed successfully"
-	response.Data.PaymentId = payment.PaymentID
+	applied = true
+	return
+}
+
+// settleRecovery settles a payment received on a written-off loan and books it as recovery income, it commits
+// or rolls back the transaction
+// Parameters:
+// - tx: the transaction holding the loan and payment locks
+// - payment: the pending payment
+// Returns:
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) settleRecovery(tx *gorm.DB, payment models.Payment) (handle dto.HandleError) {
+	payment.Status = models.PaymentStatusSettled
+	payment.SettledAt = null.TimeFrom(time.Now())
+	if err := tx.Omit(clause.Associations).Save(&payment).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	if err := s.ledger.PostRecovery(tx, payment); err != nil {
+		tx.Rollback()
+		handle.Status = -10
+		handle.Errors = err
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -9
+		handle.Errors = err
+	}
 	return
 }
+
+// allocatePayment distributes a payment over the pending repayments in installment order.
+// Parameters:
+// - repayments: the pending repayments ordered by installment date
+// - paymentId: the ID of the payment being allocated
+// - paymentAmount: the amount to allocate
+// Returns:
+// - []models.Repayment with the repayments that received part of the payment
+// - []models.RepaymentPaymentLog with one allocation log per updated repayment
+// - float64 with the part of the payment left over once every installment is cleared
+func allocatePayment(repayments []models.Repayment, paymentId string, paymentAmount float64) (
+	updateRepayments []models.Repayment, repaymentPaymentLogs []models.RepaymentPaymentLog, excessAmount float64) {
+	repaymentAmount := paymentAmount
+
+	// Iterate over each repayment
+	for _, repayment := range repayments {
+		// Process only pending repayments
+		if repayment.Status == models.LoanApplicationStatusPaid {
+			continue
+		}
+
+		amountDueRemaining := repayment.AmountDue - repayment.AmountPaid
+
+		// Create a new repayment payment log entry
+		repaymentPaymentLog := models.RepaymentPaymentLog{
+			LogID:       uuid.New().String(),
+			RepaymentID: repayment.RepaymentID,
+			PaymentID:   paymentId,
+			Amount:      0,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		// If repayment amount is greater than or equal to the remaining amount due
+		if repaymentAmount >= amountDueRemaining {
+			repaymentPaymentLog.Amount = amountDueRemaining
+			repayment.AmountPaid += amountDueRemaining
+			repayment.Status = models.LoanApplicationStatusPaid
+			repayment.PaymentDate = null.TimeFrom(time.Now())
+			repayment.OutstandingBalance = null.FloatFrom(0)
+			repaymentAmount -= amountDueRemaining
+		} else {
+			// If repayment amount is less than the remaining amount due
+			repaymentPaymentLog.Amount = repaymentAmount
+			repayment.AmountPaid += repaymentAmount
+			repayment.OutstandingBalance = null.FloatFrom(repayment.AmountDue - repayment.AmountPaid)
+			repaymentAmount = 0
+		}
+
+		// Add the updated repayment and log to the respective slices
+		updateRepayments = append(updateRepayments, repayment)
+		repaymentPaymentLogs = append(repaymentPaymentLogs, repaymentPaymentLog)
+
+		// Break the loop if the repayment amount is exhausted
+		if repaymentAmount == 0 {
+			break
+		}
+	}
+
+	excessAmount = repaymentAmount
+	return
+}
+
+// collectFees returns how much of a payment goes to the fees owed on a loan, rounded to the cent
+// Parameters:
+// - paymentAmount: the settled payment
+// - feesOwed: the fees charged and not collected yet
+// Returns:
+// - float64 with the fees the payment collects
+func collectFees(paymentAmount float64, feesOwed float64) float64 {
+	collected := math.Min(math.Round(paymentAmount*100), math.Round(feesOwed*100))
+	if collected < 0 {
+		return 0
+	}
+	return collected / 100
+}
+
+// splitAllocation splits the amounts allocated to installments into principal and interest. Every installment
+// collects its interest before its principal.
+// Parameters:
+// - updateRepayments: the repayments after allocation
+// - repaymentPaymentLogs: the allocation log of each updated repayment, in the same order
+// Returns:
+// - ledgerService.Allocation with the principal and interest allocated
+func splitAllocation(updateRepayments []models.Repayment, repaymentPaymentLogs []models.RepaymentPaymentLog) (
+	allocation ledgerService.Allocation) {
+	for i, repaymentPaymentLog := range repaymentPaymentLogs {
+		repayment := updateRepayments[i]
+		paidBefore := repayment.AmountPaid - repaymentPaymentLog.Amount
+
+		interest := repayment.InterestAmount - paidBefore
+		if interest < 0 {
+			interest = 0
+		}
+		if interest > repaymentPaymentLog.Amount {
+			interest = repaymentPaymentLog.Amount
+		}
+
+		allocation.Interest += interest
+		allocation.Principal += repaymentPaymentLog.Amount - interest
+	}
+	return
+}
+
+// isRetryableError reports whether the transaction was aborted by a deadlock or a lock wait timeout
+func isRetryableError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package repayment_service
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	closureService "github.com/nishanthrk/aspire-lms/app/services/closure"
+	gatewayService "github.com/nishanthrk/aspire-lms/app/services/gateway"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocatePayment_SpreadsAcrossInstallments(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", AmountDue: 100, Status: models.LoanApplicationStatusPending},
+		{RepaymentID: "r2", AmountDue: 100, AmountPaid: 20, Status: models.LoanApplicationStatusPending},
+		{RepaymentID: "r3", AmountDue: 100, Status: models.LoanApplicationStatusPending},
+	}
+
+	updated, logs, excess := allocatePayment(repayments, "payment_id", 150)
+
+	assert.Len(t, updated, 2)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, models.LoanApplicationStatusPaid, updated[0].Status)
+	assert.Equal(t, float64(100), updated[0].AmountPaid)
+	assert.Equal(t, models.LoanApplicationStatusPending, updated[1].Status)
+	assert.Equal(t, float64(70), updated[1].AmountPaid)
+	assert.Equal(t, float64(30), updated[1].OutstandingBalance.Float64)
+	assert.Equal(t, float64(100), logs[0].Amount)
+	assert.Equal(t, float64(50), logs[1].Amount)
+	assert.Equal(t, "payment_id", logs[1].PaymentID)
+	assert.Equal(t, float64(0), excess)
+}
+
+func TestAllocatePayment_SkipsPaidInstallments(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", AmountDue: 100, AmountPaid: 100, Status: models.LoanApplicationStatusPaid},
+		{RepaymentID: "r2", AmountDue: 100, Status: models.LoanApplicationStatusPending},
+	}
+
+	updated, logs, excess := allocatePayment(repayments, "payment_id", 100)
+
+	assert.Len(t, updated, 1)
+	assert.Equal(t, "r2", updated[0].RepaymentID)
+	assert.Equal(t, "r2", logs[0].RepaymentID)
+	assert.Equal(t, models.LoanApplicationStatusPaid, updated[0].Status)
+	assert.Equal(t, float64(0), excess)
+}
+
+func TestAllocatePayment_KeepsExcessOverLastInstallment(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", AmountDue: 100, AmountPaid: 40, Status: models.LoanApplicationStatusPending},
+	}
+
+	updated, logs, excess := allocatePayment(repayments, "payment_id", 100)
+
+	assert.Len(t, updated, 1)
+	assert.Equal(t, float64(60), logs[0].Amount)
+	assert.Equal(t, models.LoanApplicationStatusPaid, updated[0].Status)
+	assert.Equal(t, float64(40), excess)
+}
+
+func TestSplitAllocation_CollectsInterestFirst(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", AmountDue: 100, InterestAmount: 10, AmountPaid: 5, Status: models.LoanApplicationStatusPending},
+		{RepaymentID: "r2", AmountDue: 100, InterestAmount: 8, Status: models.LoanApplicationStatusPending},
+	}
+
+	updated, logs, _ := allocatePayment(repayments, "payment_id", 150)
+	allocation := splitAllocation(updated, logs)
+
+	// r1 still owed 5 of interest and 90 of principal, r2 takes 8 of interest before its principal
+	assert.InDelta(t, 13, allocation.Interest, 0.001)
+	assert.InDelta(t, 137, allocation.Principal, 0.001)
+}
+
+func TestSplitAllocation_PartialPaymentStaysOnInterest(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", AmountDue: 100, InterestAmount: 10, Status: models.LoanApplicationStatusPending},
+	}
+
+	updated, logs, _ := allocatePayment(repayments, "payment_id", 6)
+	allocation := splitAllocation(updated, logs)
+
+	assert.InDelta(t, 6, allocation.Interest, 0.001)
+	assert.InDelta(t, 0, allocation.Principal, 0.001)
+}
+
+func TestCollectFees(t *testing.T) {
+	// A payment covering the fees leaves the rest for the installments
+	assert.Equal(t, 25.5, collectFees(100, 25.5))
+	// A payment smaller than the fees goes to them in full
+	assert.Equal(t, 10.0, collectFees(10, 25.5))
+	// Nothing is collected when no fee is owed
+	assert.Equal(t, 0.0, collectFees(100, 0))
+	assert.Equal(t, 0.0, collectFees(100, -0.001))
+}
+
+// TestUpdateRepayment_ConcurrentPaymentsStayConsistent settles simultaneous payments on one loan and checks that
+// every unit of money is allocated exactly once. It needs a migrated MySQL database configured through the
+// usual DB_* environment variables and is skipped otherwise, it is run by hand as described in the README.
+func TestUpdateRepayment_ConcurrentPaymentsStayConsistent(t *testing.T) {
+	if os.Getenv("DB_HOST") == "" {
+		t.Skip("DB_HOST not set: this concurrency test is a manual check against a migrated MySQL database, " +
+			"see Database Tests in the README")
+	}
+
+	logger.InitLogger()
+	db.ConnectMysql()
+
+	gateway := gatewayService.NewFakePaymentGateway("secret")
+	svc := NewRepaymentService(gateway, ledgerService.NewLedgerService(), closureService.NewClosureService("")).(*repaymentService)
+
+	customer := models.User{
+		UserID:       uuid.New().String(),
+		UserName:     "Concurrent Customer",
+		UserType:     constants.UserTypeCustomer,
+		MobileNumber: uuid.New().String()[:15],
+	}
+	assert.NoError(t, db.MysqlDB.Create(&customer).Error)
+
+	application := models.LoanApplication{
+		ApplicationID:   uuid.New().String(),
+		LoanAmount:      12000,
+		CurrencyCode:    "INR",
+		InterestRate:    12,
+		LoanTerm:        12,
+		LoanTermUnit:    "MONTHLY",
+		ApplicationDate: time.Now(),
+		Status:          models.LoanApplicationStatusApproved,
+		ApprovedAmount:  null.FloatFrom(12000),
+		ApprovedDate:    null.TimeFrom(time.Now()),
+		CountryCode:     "IND",
+	}
+	repayments, err := svc.CalculateRepaymentSchedule(&application)
+	assert.NoError(t, err)
+	assert.NoError(t, db.MysqlDB.Create(&application).Error)
+	assert.NoError(t, db.MysqlDB.Create(&repayments).Error)
+	assert.NoError(t, db.MysqlDB.Create(&models.LoanApplicationParticipant{
+		ParticipantID:   uuid.New().String(),
+		ApplicationID:   application.ApplicationID,
+		ParticipantType: constants.UserTypeCustomer,
+		UserID:          customer.UserID,
+	}).Error)
+
+	const workers = 8
+	const paymentAmount = 750.0
+
+	// Initiate the payments, nothing is allocated until the gateway settles them
+	paymentIds := make(chan string, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			response, handle := svc.UpdateRepayment(dto.RepaymentRequest{
+				ApplicationID: application.ApplicationID,
+				PaymentAmount: paymentAmount,
+			}, customer)
+			assert.GreaterOrEqual(t, handle.Status, 0, "payment failed: %v", handle.Errors)
+			paymentIds <- response.Data.PaymentId
+		}()
+	}
+	wg.Wait()
+	close(paymentIds)
+
+	// Settle every payment at the same time
+	wg.Add(workers)
+	for paymentId := range paymentIds {
+		go func(paymentId string) {
+			defer wg.Done()
+			applied, handle := svc.applyGatewayStatus(paymentId, models.PaymentStatusSettled, "")
+			assert.GreaterOrEqual(t, handle.Status, 0, "settlement failed: %v", handle.Errors)
+			assert.True(t, applied)
+		}(paymentId)
+	}
+	wg.Wait()
+
+	var totalPaid, totalAllocated float64
+	stored, _ := (&models.Repayment{}).FindAllByCondition([]db.WhereCondition{{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	}})
+	for _, repayment := range stored {
+		assert.LessOrEqual(t, repayment.AmountPaid, repayment.AmountDue+0.005)
+		totalPaid += repayment.AmountPaid
+	}
+
+	db.MysqlDB.Model(&models.RepaymentPaymentLog{}).
+		Joins("JOIN payment ON payment.payment_id = repayment_payment_log.payment_id").
+		Where("payment.application_id = ?", application.ApplicationID).
+		Select("COALESCE(SUM(repayment_payment_log.amount), 0)").Scan(&totalAllocated)
+
+	assert.InDelta(t, workers*paymentAmount, totalPaid, 0.01)
+	assert.InDelta(t, workers*paymentAmount, totalAllocated, 0.01)
+}
+
+func TestUnwindAllocations_RestoresRepayments(t *testing.T) {
+	repayments := []models.Repayment{
+		{RepaymentID: "r1", AmountDue: 100, AmountPaid: 100, Status: models.LoanApplicationStatusPaid,
+			PaymentDate: null.TimeFrom(time.Now()), OutstandingBalance: null.FloatFrom(0)},
+		{RepaymentID: "r2", AmountDue: 100, AmountPaid: 50, Status: models.LoanApplicationStatusPending,
+			OutstandingBalance: null.FloatFrom(50)},
+	}
+
+	updated, logs := unwindAllocations(repayments, map[string]float64{"r1": 60, "r2": 50}, "payment_id")
+
+	assert.Len(t, updated, 2)
+	assert.Equal(t, float64(40), updated[0].AmountPaid)
+	assert.Equal(t, models.LoanApplicationStatusPending, updated[0].Status)
+	assert.False(t, updated[0].PaymentDate.Valid)
+	assert.Equal(t, float64(60), updated[0].OutstandingBalance.Float64)
+	assert.Equal(t, float64(0), updated[1].AmountPaid)
+	assert.False(t, updated[1].OutstandingBalance.Valid)
+	assert.Equal(t, float64(-60), logs[0].Amount)
+	assert.Equal(t, float64(-50), logs[1].Amount)
+	assert.Equal(t, "payment_id", logs[1].PaymentID)
+}
+
+func TestRepaymentsClosed(t *testing.T) {
+	application := models.LoanApplication{ApplicationID: "app_id", Status: models.LoanApplicationStatusApproved}
+	assert.NoError(t, repaymentsClosed(application))
+
+	application.Status = models.LoanApplicationStatusPaid
+	assert.EqualError(t, repaymentsClosed(application),
+		"loan app_id has been repaid in full and is awaiting closure, no further repayments are accepted")
+
+	application.Status = models.LoanApplicationStatusClosed
+	application.ClosedDate = null.TimeFrom(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	assert.EqualError(t, repaymentsClosed(application),
+		"loan app_id was closed on 2024-03-02, no further repayments are accepted")
+}
@@ -0,0 +1,378 @@
+package repayment_service
+
+import (
+	"fmt"
+	"github.com/Rhymond/go-money"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ReversePayment reverses a received payment, for example a bounced cheque or a failed debit.
+// Every allocation the payment made is unwound from its repayment, a compensating log entry is
+// written for each of them and a PAID loan is reopened.
+// Parameters:
+// - request: dto.PaymentReversalRequest containing the application ID, payment ID and reason
+// - user: models.User representing the employee reversing the payment
+// Returns:
+// - dto.PaymentReversalResponse with the reversal result
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) ReversePayment(request dto.PaymentReversalRequest, user models.User) (
+	response dto.PaymentReversalResponse, handle dto.HandleError) {
+	// Check if the employee is allocated to the application and may act on its payments
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionPaymentReverse,
+	}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	// Reverse the payment, retrying when the database aborts the transaction because of a lock conflict
+	for attempt := 1; attempt <= maxRepaymentAttempts; attempt++ {
+		handle = s.processReversal(request, user)
+		if handle.Status >= 0 || !isRetryableError(handle.Errors) {
+			break
+		}
+	}
+
+	if handle.Status < 0 {
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Payment reversed successfully"
+	response.Data.PaymentId = request.PaymentID
+	response.Data.Status = models.PaymentStatusReversed
+	return
+}
+
+// processReversal unwinds a payment inside a single transaction holding locks on the loan, the payment
+// and every repayment the payment was allocated to
+// Parameters:
+// - request: dto.PaymentReversalRequest containing the application ID, payment ID and reason
+// - user: models.User representing the employee reversing the payment
+// Returns:
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) processReversal(request dto.PaymentReversalRequest, user models.User) (handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = fmt.Errorf("%v", r)
+			return
+		}
+	}()
+
+	// Lock the loan application so that no payment is allocated while we unwind
+	application := models.LoanApplication{}
+	application, err := application.FindByPrimaryKeyForUpdate(tx, request.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("application details not found")
+		return
+	}
+
+	payment := models.Payment{}
+	payment, _ = payment.FindByPrimaryKeyForUpdate(tx, request.PaymentID)
+	if payment.PaymentID == "" || payment.ApplicationID != application.ApplicationID {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("payment %v not found for this application", request.PaymentID)
+		return
+	}
+
+	// Only payments whose funds were allocated can be reversed
+	if !payment.IsAllocated() {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("payment is %v and cannot be reversed", payment.Status)
+		return
+	}
+
+	// A closed loan has been confirmed as settled to the borrower, its payments stay as they are
+	if application.Status == models.LoanApplicationStatusClosed {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("loan was closed on %v and its payments can no longer be reversed",
+			application.ClosedDate.Time.Format("2006-01-02"))
+		return
+	}
+
+	// The excess credit created by this payment can only be taken back if it was not refunded meanwhile
+	if payment.ExcessAmount > application.ExcessCredit {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = fmt.Errorf("excess credit of this payment has already been refunded")
+		return
+	}
+
+	var logCondition []db.WhereCondition
+	logCondition = append(logCondition, db.WhereCondition{
+		Key:       models.RepaymentPaymentLogColumns.PaymentID,
+		Condition: "=",
+		Value:     payment.PaymentID,
+	})
+
+	paymentLog := models.RepaymentPaymentLog{}
+	paymentLogs, _ := paymentLog.FindAllByCondition(logCondition)
+
+	// The write-off closed the installments, payments allocated to them before it stay as they are
+	if application.Status == models.LoanApplicationStatusWrittenOff && len(paymentLogs) > 0 {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("payment was allocated before the loan was written off and cannot be reversed")
+		return
+	}
+
+	// Lock every repayment the payment was allocated to
+	allocations := make(map[string]float64)
+	var repaymentIds []string
+	for _, log := range paymentLogs {
+		if _, ok := allocations[log.RepaymentID]; !ok {
+			repaymentIds = append(repaymentIds, log.RepaymentID)
+		}
+		allocations[log.RepaymentID] += log.Amount
+	}
+
+	var updateRepayments []models.Repayment
+	var reversalLogs []models.RepaymentPaymentLog
+
+	if len(repaymentIds) > 0 {
+		var repaymentCondition []db.WhereCondition
+		repaymentCondition = append(repaymentCondition, db.WhereCondition{
+			Key:       models.RepaymentColumns.RepaymentID,
+			Condition: "IN",
+			Value:     repaymentIds,
+		})
+
+		repayment := models.Repayment{}
+		repayments, err := repayment.FindAllByConditionForUpdate(tx, repaymentCondition)
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -7
+			handle.Errors = err
+			return
+		}
+
+		updateRepayments, reversalLogs = unwindAllocations(repayments, allocations, payment.PaymentID)
+	}
+
+	// Write a compensating log entry for every allocation so the original history stays untouched
+	if len(reversalLogs) > 0 {
+		if err := tx.Save(&reversalLogs).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -8
+			handle.Errors = err
+			return
+		}
+	}
+
+	if len(updateRepayments) > 0 {
+		if err := tx.Omit(clause.Associations).Save(&updateRepayments).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -9
+			handle.Errors = err
+			return
+		}
+	}
+
+	// Mark the payment as reversed
+	payment.Status = models.PaymentStatusReversed
+	payment.ReversalReason = null.StringFrom(request.Reason)
+	payment.ReversedBy = null.StringFrom(user.UserID)
+	payment.ReversedAt = null.TimeFrom(time.Now())
+	if err := tx.Omit(clause.Associations).Save(&payment).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -10
+		handle.Errors = err
+		return
+	}
+
+	// Take back the excess credit and reopen the loan if the payment had cleared it
+	application.ExcessCredit -= payment.ExcessAmount
+	if application.Status == models.LoanApplicationStatusPaid && len(updateRepayments) > 0 {
+		application.Status = models.LoanApplicationStatusApproved
+	}
+	if err := tx.Omit(clause.Associations).Save(&application).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -11
+		handle.Errors = err
+		return
+	}
+
+	// Reverse the repayment entry in the general ledger
+	if err := s.ledger.PostPaymentReversal(tx, payment, user.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -12
+		handle.Errors = err
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -13
+		handle.Errors = err
+		return
+	}
+
+	return
+}
+
+// unwindAllocations restores the repayments a payment was allocated to.
+// Parameters:
+// - repayments: the repayments the payment was allocated to
+// - allocations: the amount allocated to each repayment keyed by repayment ID
+// - paymentId: the ID of the payment being reversed
+// Returns:
+// - []models.Repayment with the restored repayments
+// - []models.RepaymentPaymentLog with a negative log entry per restored repayment
+func unwindAllocations(repayments []models.Repayment, allocations map[string]float64, paymentId string) (
+	updateRepayments []models.Repayment, reversalLogs []models.RepaymentPaymentLog) {
+	for _, repayment := range repayments {
+		amount := allocations[repayment.RepaymentID]
+		if amount == 0 {
+			continue
+		}
+
+		repayment.AmountPaid -= amount
+		repayment.Status = models.LoanApplicationStatusPending
+		repayment.PaymentDate = null.Time{}
+		if repayment.AmountPaid > 0 {
+			repayment.OutstandingBalance = null.FloatFrom(repayment.AmountDue - repayment.AmountPaid)
+		} else {
+			repayment.AmountPaid = 0
+			repayment.OutstandingBalance = null.Float{}
+		}
+
+		updateRepayments = append(updateRepayments, repayment)
+		reversalLogs = append(reversalLogs, models.RepaymentPaymentLog{
+			LogID:       uuid.New().String(),
+			RepaymentID: repayment.RepaymentID,
+			PaymentID:   paymentId,
+			Amount:      -amount,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		})
+	}
+
+	return
+}
+
+// RefundExcessCredit pays back credit left on a loan by payments exceeding the outstanding installments
+// Parameters:
+// - request: dto.RefundRequest containing the application ID, amount and reason
+// - user: models.User representing the employee processing the refund
+// Returns:
+// - dto.RefundResponse with the refund result
+// - dto.HandleError with any error that occurred during the process
+func (s *repaymentService) RefundExcessCredit(request dto.RefundRequest, user models.User) (
+	response dto.RefundResponse, handle dto.HandleError) {
+	// Check if the employee is allocated to the application and may act on its payments
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionPaymentRefund,
+	}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = fmt.Errorf("%v", r)
+			return
+		}
+	}()
+
+	// Lock the loan application so the excess credit cannot be spent twice
+	application := models.LoanApplication{}
+	application, err := application.FindByPrimaryKeyForUpdate(tx, request.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("application details not found")
+		return
+	}
+
+	if request.Amount > application.ExcessCredit {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("refund exceeds the available excess credit of %v",
+			money.NewFromFloat(application.ExcessCredit, application.CurrencyCode).Display())
+		return
+	}
+
+	refund := models.Refund{
+		RefundID:      uuid.New().String(),
+		ApplicationID: application.ApplicationID,
+		CurrencyCode:  application.CurrencyCode,
+		Amount:        request.Amount,
+		Status:        models.RefundStatusProcessed,
+		ProcessedBy:   user.UserID,
+	}
+	if request.Reason != "" {
+		refund.Reason = null.StringFrom(request.Reason)
+	}
+
+	if err := tx.Create(&refund).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	application.ExcessCredit -= request.Amount
+	if err := tx.Omit(clause.Associations).Save(&application).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	// Record the refund in the general ledger
+	if err := s.ledger.PostRefund(tx, refund); err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	// Refunding the last excess credit of a repaid loan leaves nothing open on it
+	if _, err := s.closure.CloseIfSettled(tx, &application, user.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -9
+		handle.Errors = err
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -8
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Refund processed successfully"
+	response.Data.RefundId = refund.RefundID
+	response.Data.ExcessCredit = money.NewFromFloat(application.ExcessCredit, application.CurrencyCode).Display()
+	response.Data.LoanStatus = application.Status
+	return
+}
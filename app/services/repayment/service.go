@@ -3,21 +3,52 @@ package repayment_service
 import (
 	"github.com/nishanthrk/aspire-lms/app/dto"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	closureService "github.com/nishanthrk/aspire-lms/app/services/closure"
+	gatewayService "github.com/nishanthrk/aspire-lms/app/services/gateway"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
 )
 
 // RepaymentService defines the interface for repayment-related operations
 type RepaymentService interface {
-	// UpdateRepayment Processes a repayment request and updates the repayment schedule
+	// UpdateRepayment Initiates a repayment through the payment gateway
 	UpdateRepayment(request dto.RepaymentRequest, user models.User) (dto.RepaymentResponse, dto.HandleError)
 
 	// CalculateRepaymentSchedule Calculates the repayment schedule for a given loan application
 	CalculateRepaymentSchedule(application *models.LoanApplication) ([]models.Repayment, error)
+
+	// ReversePayment Reverses a received payment and unwinds its allocations
+	ReversePayment(request dto.PaymentReversalRequest, user models.User) (dto.PaymentReversalResponse, dto.HandleError)
+
+	// RefundExcessCredit Refunds excess credit left on a loan application
+	RefundExcessCredit(request dto.RefundRequest, user models.User) (dto.RefundResponse, dto.HandleError)
+
+	// HandleGatewayWebhook Settles or fails a payment from a signed gateway callback and allocates settled funds
+	HandleGatewayWebhook(request dto.GatewayWebhookRequest, payload []byte, signature string) (dto.GatewayWebhookResponse, dto.HandleError)
+
+	// PostBankCredit Settles and allocates a credit received directly on the bank account
+	PostBankCredit(request dto.BankCreditRequest) (dto.RepaymentResponse, dto.HandleError)
+
+	// ApplyPaymentStatus Settles or fails a pending payment reported by a collection channel other than the gateway
+	ApplyPaymentStatus(request dto.PaymentStatusRequest) (dto.PaymentSyncResponse, dto.HandleError)
+
+	// SyncPaymentStatus Polls the gateway for the status of a pending payment
+	SyncPaymentStatus(request dto.PaymentSyncRequest, user models.User) (dto.PaymentSyncResponse, dto.HandleError)
 }
 
 // repaymentService is an implementation of RepaymentService
-type repaymentService struct{}
+type repaymentService struct {
+	gateway gatewayService.PaymentGateway
+	ledger  ledgerService.LedgerService
+	closure closureService.ClosureService
+}
 
-// NewRepaymentService returns a new instance of RepaymentService
-func NewRepaymentService() RepaymentService {
-	return &repaymentService{}
+// NewRepaymentService returns a new instance of RepaymentService. The gateway may be nil for callers that only post
+// bank credits, such as the statement import command.
+func NewRepaymentService(gateway gatewayService.PaymentGateway, ledger ledgerService.LedgerService,
+	closure closureService.ClosureService) RepaymentService {
+	return &repaymentService{
+		gateway: gateway,
+		ledger:  ledger,
+		closure: closure,
+	}
 }
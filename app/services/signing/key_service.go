@@ -0,0 +1,242 @@
+package signing_service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	cfg "github.com/nishanthrk/aspire-lms/app/configs"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm/clause"
+)
+
+// rsaKeyBits is the size of the RSA keys generated for RS256
+const rsaKeyBits = 2048
+
+// GetKeySet returns the keys that are not retired: the one signing, a successor published ahead of signing and
+// predecessors whose tokens have not all expired yet
+// Returns:
+// - dto.JsonWebKeySet with the public keys
+// - dto.HandleError with any error that occurred during the process
+func (s *signingService) GetKeySet() (response dto.JsonWebKeySet, handle dto.HandleError) {
+	keys, err := ring.current(time.Now(), false)
+	if err != nil {
+		// The keys read last are served while the database cannot be read
+		if len(keys) == 0 {
+			handle.Status = -1
+			handle.Errors = err
+			return
+		}
+		logger.Sugar.Error("could not read signing keys, serving the cached ones: ", err)
+	}
+
+	response.Keys = make([]dto.JsonWebKey, 0, len(keys))
+	for _, key := range keys {
+		response.Keys = append(response.Keys, toJsonWebKey(key))
+	}
+	return
+}
+
+// RotateKeys generates the successor of the key signing once its rotation is near, and retires the keys that were
+// replaced long enough ago for every token they signed to have expired. Without any key one is generated that
+// signs at once.
+// Parameters:
+// - now: time.Time the rotation is run at
+// Returns:
+// - dto.KeyRotationResponse with the key generated and the keys retired
+// - dto.HandleError with any error that occurred during the process
+func (s *signingService) RotateKeys(now time.Time) (response dto.KeyRotationResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	// The keys are locked so that two instances rotating at once do not both generate a successor
+	signingKeyModel := models.SigningKey{}
+	keys, err := signingKeyModel.FindActiveForUpdate(tx)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	for _, key := range retiredKeys(keys, now) {
+		key.Status = models.SigningKeyStatusRetired
+		key.RetiredAt = null.TimeFrom(now)
+		if err = tx.Omit(clause.Associations).Save(&key).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = err
+			return
+		}
+		response.Data.Retired = append(response.Data.Retired, key.Kid)
+	}
+
+	if activatesAt, due := successorActivation(keys, now); due {
+		key, err := generateSigningKey(s.policy, activatesAt)
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -3
+			handle.Errors = err
+			return
+		}
+		if err = tx.Create(&key).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -4
+			handle.Errors = err
+			return
+		}
+		response.Data.Created = key.Kid
+		response.Data.ActivatesAt = key.ActivatesAt.Format(time.RFC3339)
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	// This instance picks the changes up at once, the others when their cached keys expire
+	ring.invalidate()
+
+	response.Status = 1
+	response.Message = fmt.Sprintf("%d signing key(s) retired", len(response.Data.Retired))
+	if response.Data.Created != "" {
+		response.Message = fmt.Sprintf("Signing key %v signs from %v, %d signing key(s) retired",
+			response.Data.Created, response.Data.ActivatesAt, len(response.Data.Retired))
+	}
+	return
+}
+
+// successorActivation returns when the successor of the latest key starts signing, and whether it is due to be
+// generated. It is generated once the latest key's rotation is less than an overlap away and signs an overlap later,
+// so it is published for the whole overlap even when the scheduler missed the rotation. Without any key the first
+// signs at once.
+func successorActivation(keys []models.SigningKey, now time.Time) (time.Time, bool) {
+	if len(keys) == 0 {
+		return now, true
+	}
+
+	latest := keys[0]
+	for _, key := range keys[1:] {
+		if key.ActivatesAt.After(latest.ActivatesAt) {
+			latest = key
+		}
+	}
+	if now.Before(latest.RotatesAt.Add(-keyOverlap)) {
+		return time.Time{}, false
+	}
+	return now.Add(keyOverlap), true
+}
+
+// retiredKeys returns the keys replaced by a key that has signed for longer than the overlap, every token the
+// replaced keys signed has expired by then
+func retiredKeys(keys []models.SigningKey, now time.Time) (retired []models.SigningKey) {
+	var settled null.Time
+	for _, key := range keys {
+		if !key.ActivatesAt.Add(keyOverlap).After(now) &&
+			(!settled.Valid || key.ActivatesAt.After(settled.Time)) {
+			settled = null.TimeFrom(key.ActivatesAt)
+		}
+	}
+	if !settled.Valid {
+		return
+	}
+
+	for _, key := range keys {
+		if key.ActivatesAt.Before(settled.Time) {
+			retired = append(retired, key)
+		}
+	}
+	return
+}
+
+// generateSigningKey generates a key pair for the algorithm of the policy, the private key is stored encrypted
+// with JWT_KEY_ENCRYPTION_KEY
+// Parameters:
+// - policy: RotationPolicy with the algorithm and how long the key signs
+// - activatesAt: time.Time the key starts signing
+// Returns:
+// - models.SigningKey to store
+// - error when the key could not be generated or encrypted
+func generateSigningKey(policy RotationPolicy, activatesAt time.Time) (key models.SigningKey, err error) {
+	var privateKey interface{}
+	var publicKey interface{}
+	switch policy.Algorithm {
+	case AlgorithmES256:
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return key, err
+		}
+		privateKey, publicKey = ecdsaKey, &ecdsaKey.PublicKey
+	case AlgorithmRS256:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return key, err
+		}
+		privateKey, publicKey = rsaKey, &rsaKey.PublicKey
+	default:
+		return key, fmt.Errorf("unsupported signing algorithm %v", policy.Algorithm)
+	}
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return
+	}
+	privatePem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer})
+	encrypted, err := utility.EncryptSecret(cfg.GetConfig().JWTKeySecret, string(privatePem))
+	if err != nil {
+		return
+	}
+
+	key = models.SigningKey{
+		Kid:         uuid.New().String(),
+		Algorithm:   policy.Algorithm,
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})),
+		PrivateKey:  encrypted,
+		Status:      models.SigningKeyStatusActive,
+		ActivatesAt: activatesAt,
+		RotatesAt:   activatesAt.Add(policy.Interval),
+	}
+	return
+}
+
+// toJsonWebKey converts the public key of a key to its JSON Web Key (RFC 7518)
+func toJsonWebKey(key signingKey) dto.JsonWebKey {
+	jwk := dto.JsonWebKey{
+		Use: "sig",
+		Alg: key.method.Alg(),
+		Kid: key.kid,
+	}
+
+	switch publicKey := key.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		// The coordinates are padded to the size of the curve
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = publicKey.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+	}
+	return jwk
+}
@@ -0,0 +1,243 @@
+package signing_service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	cfg "github.com/nishanthrk/aspire-lms/app/configs"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// keyCacheTTL is how long the keys read from the database are used before they are read again
+const keyCacheTTL = time.Minute
+
+// keyRefreshInterval is how often an unknown key ID, or no key to sign with, reads the keys again early
+const keyRefreshInterval = 10 * time.Second
+
+// signingKey is a key of the key set ready to sign and verify with
+type signingKey struct {
+	kid         string
+	method      jwt.SigningMethod
+	publicKey   interface{}
+	privateKey  interface{}
+	activatesAt time.Time
+}
+
+// keyring caches the keys that are not retired, every instance of the service reads them from the database
+type keyring struct {
+	mu       sync.RWMutex
+	keys     []signingKey
+	loadedAt time.Time
+}
+
+var ring = &keyring{}
+
+// current returns the cached keys, read again once they are older than the cache TTL. A refresh reads them again
+// sooner, at most once per refresh interval. The cached keys are kept when they cannot be read.
+func (r *keyring) current(now time.Time, refresh bool) ([]signingKey, error) {
+	r.mu.RLock()
+	keys, loadedAt := r.keys, r.loadedAt
+	r.mu.RUnlock()
+
+	age := now.Sub(loadedAt)
+	if !loadedAt.IsZero() && age < keyCacheTTL && (!refresh || age < keyRefreshInterval) {
+		return keys, nil
+	}
+
+	loaded, err := loadSigningKeys()
+	if err != nil {
+		return keys, err
+	}
+	r.mu.Lock()
+	r.keys, r.loadedAt = loaded, now
+	r.mu.Unlock()
+	return loaded, nil
+}
+
+// invalidate makes the next use of the keys read them again
+func (r *keyring) invalidate() {
+	r.mu.Lock()
+	r.loadedAt = time.Time{}
+	r.mu.Unlock()
+}
+
+// SignToken signs the claims of an access token with the key currently signing, its ID is the kid header
+// Parameters:
+// - claims: jwt.Claims of the token
+// Returns:
+// - string with the signed token
+// - error when no key is signing yet or the token could not be signed
+func SignToken(claims jwt.Claims) (string, error) {
+	now := time.Now()
+	keys, err := ring.current(now, false)
+	key, ok := signerAt(keys, now)
+	if !ok {
+		// The key may have started signing, or been created by another instance, since the keys were read
+		keys, err = ring.current(now, true)
+		key, ok = signerAt(keys, now)
+	}
+	if !ok {
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("no access token signing key is active yet")
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.privateKey)
+}
+
+// VerificationKey returns the key an access token is verified with, the published key its kid header names.
+// The algorithm of the token has to be the one of the key, so a public key is never used as an HMAC secret.
+// Tokens signed with the shared JWT_ACCESS_SIGN_KEY before the key set was introduced carry no kid and are
+// accepted while the secret is still configured.
+// Parameters:
+// - token: *jwt.Token being parsed
+// Returns:
+// - interface{} with the key to verify the signature with
+// - error when the token is not signed by a key of the key set
+func VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		secret := cfg.GetConfig().JWTAccessSecret
+		if secret == "" || kid != "" || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+	default:
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+
+	now := time.Now()
+	keys, _ := ring.current(now, false)
+	key, ok := findKey(keys, kid)
+	if !ok {
+		// The key may have been published by another instance since the keys were read
+		keys, _ = ring.current(now, true)
+		key, ok = findKey(keys, kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %v", token.Header["kid"])
+	}
+	if key.method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for key %v", token.Header["alg"], kid)
+	}
+	return key.publicKey, nil
+}
+
+// signerAt returns the key signing at the given time, the latest to have been activated
+func signerAt(keys []signingKey, now time.Time) (signer signingKey, ok bool) {
+	for _, key := range keys {
+		if key.privateKey == nil || key.activatesAt.After(now) {
+			continue
+		}
+		if !ok || !key.activatesAt.Before(signer.activatesAt) {
+			signer, ok = key, true
+		}
+	}
+	return
+}
+
+// findKey returns the key with the given ID
+func findKey(keys []signingKey, kid string) (signingKey, bool) {
+	if kid == "" {
+		return signingKey{}, false
+	}
+	for _, key := range keys {
+		if key.kid == kid {
+			return key, true
+		}
+	}
+	return signingKey{}, false
+}
+
+// loadSigningKeys reads the keys that are not retired. A key whose private key cannot be decrypted, e.g. after
+// JWT_KEY_ENCRYPTION_KEY changed, still verifies but no longer signs.
+func loadSigningKeys() (keys []signingKey, err error) {
+	signingKeyModel := models.SigningKey{}
+	records, err := signingKeyModel.FindActive()
+	if err != nil {
+		return
+	}
+
+	for _, record := range records {
+		key, err := parsePublicKey(record)
+		if err != nil {
+			logger.Sugar.Error("could not read signing key ", record.Kid, ": ", err)
+			continue
+		}
+		if key.privateKey, err = parsePrivateKey(record); err != nil {
+			logger.Sugar.Error("could not decrypt signing key ", record.Kid, ": ", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parsePublicKey reads the public key of a stored key, it has to be of the key's algorithm
+func parsePublicKey(record models.SigningKey) (key signingKey, err error) {
+	block, _ := pem.Decode([]byte(record.PublicKey))
+	if block == nil {
+		return key, fmt.Errorf("public key is not PEM encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return
+	}
+
+	switch typed := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if record.Algorithm != AlgorithmES256 || typed.Curve != elliptic.P256() {
+			return key, fmt.Errorf("an ECDSA key on %v cannot sign %v", typed.Curve.Params().Name, record.Algorithm)
+		}
+	case *rsa.PublicKey:
+		if record.Algorithm != AlgorithmRS256 {
+			return key, fmt.Errorf("an RSA key cannot sign %v", record.Algorithm)
+		}
+	default:
+		return key, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	key = signingKey{
+		kid:         record.Kid,
+		method:      jwt.GetSigningMethod(record.Algorithm),
+		publicKey:   publicKey,
+		activatesAt: record.ActivatesAt,
+	}
+	return
+}
+
+// parsePrivateKey decrypts the private key of a stored key
+func parsePrivateKey(record models.SigningKey) (interface{}, error) {
+	decrypted, err := utility.DecryptSecret(cfg.GetConfig().JWTKeySecret, record.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(decrypted))
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch privateKey.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey:
+		return privateKey, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/signing/service.go
+
+// Package signing_service is a generated GoMock package.
+package signing_service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+// MockSigningService is a mock of SigningService interface.
+type MockSigningService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningServiceMockRecorder
+}
+
+// MockSigningServiceMockRecorder is the mock recorder for MockSigningService.
+type MockSigningServiceMockRecorder struct {
+	mock *MockSigningService
+}
+
+// NewMockSigningService creates a new mock instance.
+func NewMockSigningService(ctrl *gomock.Controller) *MockSigningService {
+	mock := &MockSigningService{ctrl: ctrl}
+	mock.recorder = &MockSigningServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningService) EXPECT() *MockSigningServiceMockRecorder {
+	return m.recorder
+}
+
+// GetKeySet mocks base method.
+func (m *MockSigningService) GetKeySet() (dto.JsonWebKeySet, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeySet")
+	ret0, _ := ret[0].(dto.JsonWebKeySet)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetKeySet indicates an expected call of GetKeySet.
+func (mr *MockSigningServiceMockRecorder) GetKeySet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeySet", reflect.TypeOf((*MockSigningService)(nil).GetKeySet))
+}
+
+// RotateKeys mocks base method.
+func (m *MockSigningService) RotateKeys(now time.Time) (dto.KeyRotationResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKeys", now)
+	ret0, _ := ret[0].(dto.KeyRotationResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RotateKeys indicates an expected call of RotateKeys.
+func (mr *MockSigningServiceMockRecorder) RotateKeys(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKeys", reflect.TypeOf((*MockSigningService)(nil).RotateKeys), now)
+}
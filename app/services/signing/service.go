@@ -0,0 +1,54 @@
+package signing_service
+
+import (
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+const (
+	// AlgorithmES256 signs with ECDSA on the P-256 curve and SHA-256
+	AlgorithmES256 = "ES256"
+
+	// AlgorithmRS256 signs with RSASSA-PKCS1-v1_5 and SHA-256
+	AlgorithmRS256 = "RS256"
+)
+
+// keyOverlap is how long a new key is published before it signs, and how long a replaced key is still published
+// after it stopped signing. It outlasts the 14 hour access tokens and the caches of the services reading the keys.
+const keyOverlap = 24 * time.Hour
+
+// RotationPolicy decides the keys access tokens are signed with. A key signs for Interval, then a successor
+// generated for Algorithm takes over; changing the algorithm applies from the next rotation.
+type RotationPolicy struct {
+	Algorithm string
+	Interval  time.Duration
+}
+
+// SigningService defines the interface for the keys access tokens are signed with
+type SigningService interface {
+	// GetKeySet Returns the public keys access tokens are verified with, as a JSON Web Key Set
+	GetKeySet() (dto.JsonWebKeySet, dto.HandleError)
+
+	// RotateKeys Publishes the successor of a key due for rotation and retires keys no token needs any more
+	RotateKeys(now time.Time) (dto.KeyRotationResponse, dto.HandleError)
+}
+
+// signingService is an implementation of SigningService
+type signingService struct {
+	policy RotationPolicy
+}
+
+// NewSigningService returns a new instance of SigningService. A key signs for at least twice the overlap, so that
+// a successor is never published before its predecessor started signing.
+func NewSigningService(policy RotationPolicy) SigningService {
+	if policy.Algorithm != AlgorithmRS256 {
+		policy.Algorithm = AlgorithmES256
+	}
+	if policy.Interval < 2*keyOverlap {
+		policy.Interval = 2 * keyOverlap
+	}
+	return &signingService{
+		policy: policy,
+	}
+}
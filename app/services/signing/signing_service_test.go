@@ -0,0 +1,289 @@
+package signing_service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// setSigningConfig sets the configuration the keys need, GetConfig refuses a non numeric DB_PORT
+func setSigningConfig(t *testing.T, legacySecret string) {
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("JWT_KEY_ENCRYPTION_KEY", "signing-key-secret")
+	t.Setenv("JWT_ACCESS_SIGN_KEY", legacySecret)
+}
+
+// setKeyring caches the given keys as if they had just been read, so that nothing reads the database
+func setKeyring(t *testing.T, keys ...signingKey) {
+	ring.mu.Lock()
+	ring.keys, ring.loadedAt = keys, time.Now()
+	ring.mu.Unlock()
+	t.Cleanup(func() {
+		ring.mu.Lock()
+		ring.keys, ring.loadedAt = nil, time.Time{}
+		ring.mu.Unlock()
+	})
+}
+
+// newSigningKey generates a key of the algorithm and reads it back as it is read from the database
+func newSigningKey(t *testing.T, algorithm string, activatesAt time.Time) (models.SigningKey, signingKey) {
+	record, err := generateSigningKey(RotationPolicy{Algorithm: algorithm, Interval: 30 * 24 * time.Hour}, activatesAt)
+	assert.NoError(t, err)
+
+	key, err := parsePublicKey(record)
+	assert.NoError(t, err)
+	key.privateKey, err = parsePrivateKey(record)
+	assert.NoError(t, err)
+	return record, key
+}
+
+func TestNewSigningService_Policy(t *testing.T) {
+	service := NewSigningService(RotationPolicy{Algorithm: "HS256", Interval: time.Hour}).(*signingService)
+	assert.Equal(t, AlgorithmES256, service.policy.Algorithm)
+	assert.Equal(t, 48*time.Hour, service.policy.Interval)
+
+	service = NewSigningService(RotationPolicy{Algorithm: AlgorithmRS256, Interval: 720 * time.Hour}).(*signingService)
+	assert.Equal(t, AlgorithmRS256, service.policy.Algorithm)
+	assert.Equal(t, 720*time.Hour, service.policy.Interval)
+}
+
+func TestGenerateSigningKey(t *testing.T) {
+	setSigningConfig(t, "")
+	activatesAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, algorithm := range []string{AlgorithmES256, AlgorithmRS256} {
+		record, key := newSigningKey(t, algorithm, activatesAt)
+		assert.NotEmpty(t, record.Kid)
+		assert.Equal(t, algorithm, record.Algorithm)
+		assert.Equal(t, models.SigningKeyStatusActive, record.Status)
+		assert.Equal(t, activatesAt.Add(30*24*time.Hour), record.RotatesAt)
+		assert.Contains(t, record.PublicKey, "BEGIN PUBLIC KEY")
+		// The private key is only stored encrypted
+		assert.NotContains(t, record.PrivateKey, "PRIVATE KEY")
+
+		assert.Equal(t, record.Kid, key.kid)
+		assert.Equal(t, algorithm, key.method.Alg())
+		assert.NotNil(t, key.privateKey)
+	}
+
+	_, err := generateSigningKey(RotationPolicy{Algorithm: "HS256"}, activatesAt)
+	assert.Error(t, err)
+}
+
+func TestParsePrivateKey_WrongPassphrase(t *testing.T) {
+	setSigningConfig(t, "")
+	record, _ := newSigningKey(t, AlgorithmES256, time.Now())
+
+	t.Setenv("JWT_KEY_ENCRYPTION_KEY", "another-secret")
+	_, err := parsePrivateKey(record)
+	assert.Error(t, err)
+}
+
+func TestParsePublicKey_AlgorithmMismatch(t *testing.T) {
+	setSigningConfig(t, "")
+	record, _ := newSigningKey(t, AlgorithmES256, time.Now())
+
+	record.Algorithm = AlgorithmRS256
+	_, err := parsePublicKey(record)
+	assert.Error(t, err)
+}
+
+func TestToJsonWebKey_EC(t *testing.T) {
+	setSigningConfig(t, "")
+	_, key := newSigningKey(t, AlgorithmES256, time.Now())
+
+	jwk := toJsonWebKey(key)
+	assert.Equal(t, "EC", jwk.Kty)
+	assert.Equal(t, "sig", jwk.Use)
+	assert.Equal(t, "ES256", jwk.Alg)
+	assert.Equal(t, key.kid, jwk.Kid)
+	assert.Equal(t, "P-256", jwk.Crv)
+	assert.Empty(t, jwk.N)
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	assert.NoError(t, err)
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	assert.NoError(t, err)
+	assert.Len(t, x, 32)
+	assert.Len(t, y, 32)
+
+	publicKey := ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	assert.True(t, publicKey.Equal(key.publicKey))
+}
+
+func TestToJsonWebKey_RSA(t *testing.T) {
+	setSigningConfig(t, "")
+	_, key := newSigningKey(t, AlgorithmRS256, time.Now())
+
+	jwk := toJsonWebKey(key)
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "RS256", jwk.Alg)
+	assert.Empty(t, jwk.Crv)
+	assert.Equal(t, "AQAB", jwk.E)
+
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(key.publicKey.(*rsa.PublicKey).N))
+}
+
+func TestSuccessorActivation(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	// Without any key the first signs at once
+	activatesAt, due := successorActivation(nil, now)
+	assert.True(t, due)
+	assert.Equal(t, now, activatesAt)
+
+	// Not due while the rotation is further away than the overlap
+	keys := []models.SigningKey{
+		{Kid: "old", ActivatesAt: now.AddDate(0, 0, -40), RotatesAt: now.AddDate(0, 0, -10)},
+		{Kid: "current", ActivatesAt: now.AddDate(0, 0, -10), RotatesAt: now.Add(25 * time.Hour)},
+	}
+	_, due = successorActivation(keys, now)
+	assert.False(t, due)
+
+	// Due within the overlap, the successor is published for the overlap before it signs
+	keys[1].RotatesAt = now.Add(23 * time.Hour)
+	activatesAt, due = successorActivation(keys, now)
+	assert.True(t, due)
+	assert.Equal(t, now.Add(keyOverlap), activatesAt)
+
+	// So is the successor of a missed rotation
+	keys[1].RotatesAt = now.Add(-time.Hour)
+	activatesAt, due = successorActivation(keys, now)
+	assert.True(t, due)
+	assert.Equal(t, now.Add(keyOverlap), activatesAt)
+
+	// A successor already published is not generated again
+	keys = append(keys, models.SigningKey{Kid: "next", ActivatesAt: now.Add(23 * time.Hour),
+		RotatesAt: now.AddDate(0, 0, 30)})
+	_, due = successorActivation(keys, now)
+	assert.False(t, due)
+}
+
+func TestRetiredKeys(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	keys := []models.SigningKey{
+		{Kid: "oldest", ActivatesAt: now.AddDate(0, 0, -60)},
+		{Kid: "old", ActivatesAt: now.AddDate(0, 0, -30)},
+		{Kid: "current", ActivatesAt: now.Add(-2 * time.Hour)},
+		{Kid: "next", ActivatesAt: now.AddDate(0, 0, 1)},
+	}
+
+	// The old key signed until two hours ago, its tokens may not have expired yet
+	retired := retiredKeys(keys, now)
+	assert.Len(t, retired, 1)
+	assert.Equal(t, "oldest", retired[0].Kid)
+
+	// A day after its successor took over no token it signed is valid any more
+	retired = retiredKeys(keys, now.Add(keyOverlap))
+	assert.Len(t, retired, 2)
+	assert.Equal(t, "old", retired[1].Kid)
+
+	// The only key is never retired
+	assert.Empty(t, retiredKeys(keys[3:], now.AddDate(0, 0, 5)))
+}
+
+func TestSignerAt(t *testing.T) {
+	now := time.Now()
+	keys := []signingKey{
+		{kid: "old", privateKey: "key", activatesAt: now.AddDate(0, 0, -30)},
+		{kid: "current", privateKey: "key", activatesAt: now.Add(-time.Hour)},
+		{kid: "undecryptable", activatesAt: now.Add(-time.Minute)},
+		{kid: "next", privateKey: "key", activatesAt: now.Add(time.Hour)},
+	}
+
+	signer, ok := signerAt(keys, now)
+	assert.True(t, ok)
+	assert.Equal(t, "current", signer.kid)
+
+	_, ok = signerAt(keys[3:], now)
+	assert.False(t, ok)
+}
+
+func TestSignToken_VerifiedByKeySet(t *testing.T) {
+	setSigningConfig(t, "")
+	_, previous := newSigningKey(t, AlgorithmRS256, time.Now().AddDate(0, 0, -30))
+	_, current := newSigningKey(t, AlgorithmES256, time.Now().Add(-time.Hour))
+	setKeyring(t, previous, current)
+
+	claims := jwt.MapClaims{"user_id": "user_id", "exp": time.Now().Add(time.Hour).Unix()}
+	signed, err := SignToken(claims)
+	assert.NoError(t, err)
+
+	token, err := jwt.Parse(signed, VerificationKey)
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+	assert.Equal(t, current.kid, token.Header["kid"])
+	assert.Equal(t, "ES256", token.Header["alg"])
+
+	// Tokens of the previous key are still accepted
+	token = jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = previous.kid
+	signed, err = token.SignedString(previous.privateKey)
+	assert.NoError(t, err)
+	_, err = jwt.Parse(signed, VerificationKey)
+	assert.NoError(t, err)
+}
+
+func TestVerificationKey_Refused(t *testing.T) {
+	setSigningConfig(t, "")
+	_, current := newSigningKey(t, AlgorithmES256, time.Now().Add(-time.Hour))
+	_, unknown := newSigningKey(t, AlgorithmES256, time.Now().Add(-time.Hour))
+	setKeyring(t, current)
+	claims := jwt.MapClaims{"user_id": "user_id", "exp": time.Now().Add(time.Hour).Unix()}
+
+	// A key that is not published
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = unknown.kid
+	signed, err := token.SignedString(unknown.privateKey)
+	assert.NoError(t, err)
+	_, err = jwt.Parse(signed, VerificationKey)
+	assert.Error(t, err)
+
+	// The public key used as an HMAC secret
+	token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = current.kid
+	signed, err = token.SignedString([]byte("public key"))
+	assert.NoError(t, err)
+	_, err = jwt.Parse(signed, VerificationKey)
+	assert.Error(t, err)
+
+	// An algorithm other than the key's
+	token = jwt.NewWithClaims(jwt.SigningMethodES384, claims)
+	token.Header["kid"] = current.kid
+	_, err = VerificationKey(token)
+	assert.Error(t, err)
+
+	// A shared secret token once JWT_ACCESS_SIGN_KEY is removed
+	signed, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("legacy-secret"))
+	assert.NoError(t, err)
+	_, err = jwt.Parse(signed, VerificationKey)
+	assert.Error(t, err)
+}
+
+func TestVerificationKey_LegacySecret(t *testing.T) {
+	setSigningConfig(t, "legacy-secret")
+	setKeyring(t)
+	claims := jwt.MapClaims{"user_id": "user_id", "exp": time.Now().Add(time.Hour).Unix()}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("legacy-secret"))
+	assert.NoError(t, err)
+	token, err := jwt.Parse(signed, VerificationKey)
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	// Only HS256, as the secret was used
+	signed, err = jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte("legacy-secret"))
+	assert.NoError(t, err)
+	_, err = jwt.Parse(signed, VerificationKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/statement/service.go
+
+// Package statement_service is a generated GoMock package.
+package statement_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockStatementService is a mock of StatementService interface.
+type MockStatementService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatementServiceMockRecorder
+}
+
+// MockStatementServiceMockRecorder is the mock recorder for MockStatementService.
+type MockStatementServiceMockRecorder struct {
+	mock *MockStatementService
+}
+
+// NewMockStatementService creates a new mock instance.
+func NewMockStatementService(ctrl *gomock.Controller) *MockStatementService {
+	mock := &MockStatementService{ctrl: ctrl}
+	mock.recorder = &MockStatementServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatementService) EXPECT() *MockStatementServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAccountStatement mocks base method.
+func (m *MockStatementService) GetAccountStatement(request dto.AccountStatementRequest, user models.User) (dto.AccountStatementResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStatement", request, user)
+	ret0, _ := ret[0].(dto.AccountStatementResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetAccountStatement indicates an expected call of GetAccountStatement.
+func (mr *MockStatementServiceMockRecorder) GetAccountStatement(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStatement", reflect.TypeOf((*MockStatementService)(nil).GetAccountStatement), request, user)
+}
@@ -0,0 +1,188 @@
+package statement_service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/nishanthrk/aspire-lms/app/common/pdf"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+)
+
+// statementCSVHeader names the columns of the CSV statement
+var statementCSVHeader = []string{
+	"date", "type", "reference", "description", "debit", "credit", "balance",
+	"installment_number", "due_date", "allocated",
+}
+
+// WriteAccountStatementCSV writes a statement as CSV, framed by its opening and closing balances. The allocations
+// of a payment or reversal follow it as ALLOCATION rows.
+// Parameters:
+// - writer: where the statement is written to
+// - statement: the statement to write
+// Returns:
+// - error when the statement could not be written
+func WriteAccountStatementCSV(writer io.Writer, statement dto.AccountStatement) error {
+	csvWriter := csv.NewWriter(writer)
+	records := [][]string{
+		statementCSVHeader,
+		{statement.From, "OPENING_BALANCE", "", "", "", "", statement.OpeningBalance, "", "", ""},
+	}
+
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			line.Date, line.Type, line.Reference, line.Description, line.Debit, line.Credit, line.Balance, "", "", "",
+		})
+		for _, allocation := range line.Allocations {
+			records = append(records, []string{
+				line.Date, "ALLOCATION", line.Reference, "", "", "", "",
+				strconv.Itoa(allocation.InstallmentNumber), allocation.DueDate, allocation.Amount,
+			})
+		}
+	}
+	records = append(records, []string{
+		statement.To, "CLOSING_BALANCE", "", "", statement.TotalDebits, statement.TotalCredits,
+		statement.ClosingBalance, "", "", "",
+	})
+
+	for _, record := range records {
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// Layout of the PDF statement, in points
+const (
+	marginLeft   = 40.0
+	marginRight  = pdf.PageWidth - 40
+	bodyBottom   = pdf.PageHeight - 70
+	rowHeight    = 14.0
+	allocHeight  = 11.0
+	columnType   = 100.0
+	columnDesc   = 170.0
+	columnDebit  = 420.0
+	columnCredit = 488.0
+)
+
+// brandColor fills the header band carrying the tenant name
+var brandColor = pdf.Color{R: 0.07, G: 0.22, B: 0.42}
+
+// shadeColor fills the summary box
+var shadeColor = pdf.Color{R: 0.94, G: 0.95, B: 0.97}
+
+// WriteAccountStatementPDF writes a statement as an A4 PDF branded with the tenant name, with a summary of the
+// balances followed by the lines and the allocations of each payment
+// Parameters:
+// - writer: where the statement is written to
+// - statement: the statement to write
+// Returns:
+// - error when the statement could not be written
+func WriteAccountStatementPDF(writer io.Writer, statement dto.AccountStatement) error {
+	document := pdf.NewDocument(fmt.Sprintf("Statement of Account %v", statement.ApplicationId), statement.Tenant)
+
+	page := document.AddPage()
+	page.FillRect(0, 0, pdf.PageWidth, 72, brandColor)
+	page.Text(marginLeft, 45, pdf.HelveticaBold, 22, pdf.White, statement.Tenant)
+	page.TextRight(marginRight, 45, pdf.Helvetica, 12, pdf.White, "Statement of Account")
+
+	details := [][2]string{
+		{"Customer", statement.CustomerName},
+		{"Loan", statement.ApplicationId},
+		{"Status", statement.LoanStatus},
+		{"Currency", statement.CurrencyCode},
+	}
+	for i, detail := range details {
+		y := 102 + float64(i)*14
+		page.Text(marginLeft, y, pdf.HelveticaBold, 9, pdf.Black, detail[0])
+		page.Text(marginLeft+60, y, pdf.Helvetica, 9, pdf.Black, detail[1])
+	}
+	page.Text(340, 102, pdf.HelveticaBold, 9, pdf.Black, "Period")
+	page.Text(400, 102, pdf.Helvetica, 9, pdf.Black, fmt.Sprintf("%v to %v", statement.From, statement.To))
+	page.Text(340, 116, pdf.HelveticaBold, 9, pdf.Black, "Generated")
+	page.Text(400, 116, pdf.Helvetica, 9, pdf.Black, statement.GeneratedAt)
+
+	page.FillRect(marginLeft, 162, marginRight-marginLeft, 44, shadeColor)
+	summary := [][2]string{
+		{"Opening balance", statement.OpeningBalance},
+		{"Total debits", statement.TotalDebits},
+		{"Total credits", statement.TotalCredits},
+		{"Closing balance", statement.ClosingBalance},
+	}
+	width := (marginRight - marginLeft) / float64(len(summary))
+	for i, item := range summary {
+		x := marginLeft + 10 + float64(i)*width
+		page.Text(x, 179, pdf.Helvetica, 8, pdf.Gray, item[0])
+		page.Text(x, 196, pdf.HelveticaBold, 11, pdf.Black, item[1])
+	}
+
+	y := tableHeader(page, 232)
+	if len(statement.Lines) == 0 {
+		page.Text(marginLeft, y, pdf.Helvetica, 9, pdf.Gray, "No transactions in this period")
+	}
+
+	for _, line := range statement.Lines {
+		// Keep a line together with its allocations on one page where they fit
+		needed := rowHeight + float64(len(line.Allocations))*allocHeight
+		if y+needed > bodyBottom {
+			page = continuationPage(document, statement)
+			y = tableHeader(page, 70)
+		}
+
+		page.Text(marginLeft, y, pdf.Helvetica, 9, pdf.Black, line.Date)
+		page.Text(columnType, y, pdf.Helvetica, 9, pdf.Black, line.Type)
+		page.Text(columnDesc, y, pdf.Helvetica, 9, pdf.Black,
+			pdf.Truncate(pdf.Helvetica, 9, line.Description, columnDebit-columnDesc-60))
+		page.TextRight(columnDebit, y, pdf.Helvetica, 9, pdf.Black, line.Debit)
+		page.TextRight(columnCredit, y, pdf.Helvetica, 9, pdf.Black, line.Credit)
+		page.TextRight(marginRight, y, pdf.HelveticaBold, 9, pdf.Black, line.Balance)
+		y += rowHeight
+
+		for _, allocation := range line.Allocations {
+			if y > bodyBottom {
+				page = continuationPage(document, statement)
+				y = tableHeader(page, 70)
+			}
+			page.Text(columnDesc+10, y-3, pdf.Helvetica, 7.5, pdf.Gray, fmt.Sprintf("Installment %d due %v: %v",
+				allocation.InstallmentNumber, allocation.DueDate, allocation.Amount))
+			y += allocHeight
+		}
+	}
+
+	pages := document.Pages()
+	for i, page := range pages {
+		page.Line(marginLeft, pdf.PageHeight-40, marginRight, pdf.PageHeight-40, 0.5, pdf.Gray)
+		page.Text(marginLeft, pdf.PageHeight-26, pdf.Helvetica, 8, pdf.Gray,
+			fmt.Sprintf("%v - Statement of Account %v", statement.Tenant, statement.ApplicationId))
+		page.TextRight(marginRight, pdf.PageHeight-26, pdf.Helvetica, 8, pdf.Gray,
+			fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+	}
+
+	return document.Write(writer)
+}
+
+// continuationPage starts a page that carries the tenant name in a narrower band
+func continuationPage(document *pdf.Document, statement dto.AccountStatement) *pdf.Page {
+	page := document.AddPage()
+	page.FillRect(0, 0, pdf.PageWidth, 36, brandColor)
+	page.Text(marginLeft, 24, pdf.HelveticaBold, 12, pdf.White, statement.Tenant)
+	page.TextRight(marginRight, 24, pdf.Helvetica, 9, pdf.White,
+		fmt.Sprintf("Statement of Account %v", statement.ApplicationId))
+	return page
+}
+
+// tableHeader writes the column titles at y and returns where the first row goes
+func tableHeader(page *pdf.Page, y float64) float64 {
+	page.Text(marginLeft, y, pdf.HelveticaBold, 8.5, pdf.Black, "Date")
+	page.Text(columnType, y, pdf.HelveticaBold, 8.5, pdf.Black, "Type")
+	page.Text(columnDesc, y, pdf.HelveticaBold, 8.5, pdf.Black, "Description")
+	page.TextRight(columnDebit, y, pdf.HelveticaBold, 8.5, pdf.Black, "Debit")
+	page.TextRight(columnCredit, y, pdf.HelveticaBold, 8.5, pdf.Black, "Credit")
+	page.TextRight(marginRight, y, pdf.HelveticaBold, 8.5, pdf.Black, "Balance")
+	page.Line(marginLeft, y+5, marginRight, y+5, 0.75, pdf.Black)
+	return y + 19
+}
@@ -0,0 +1,24 @@
+package statement_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// StatementService defines the interface for customer account statements
+type StatementService interface {
+	// GetAccountStatement Lists the movements of a loan over a date range with their running balance
+	GetAccountStatement(request dto.AccountStatementRequest, user models.User) (dto.AccountStatementResponse, dto.HandleError)
+}
+
+// statementService is an implementation of StatementService
+type statementService struct {
+	tenant string
+}
+
+// NewStatementService returns a new instance of StatementService, statements are branded with the tenant name
+func NewStatementService(tenant string) StatementService {
+	return &statementService{
+		tenant: tenant,
+	}
+}
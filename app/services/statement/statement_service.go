@@ -0,0 +1,440 @@
+package statement_service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+)
+
+const statementDateLayout = "2006-01-02"
+
+const (
+	LineTypeDisbursement = "DISBURSEMENT"
+	LineTypeInterest     = "INTEREST"
+	LineTypeFee          = "FEE"
+	LineTypePayment      = "PAYMENT"
+	LineTypeReversal     = "REVERSAL"
+	LineTypeRefund       = "REFUND"
+)
+
+// lineOrder keeps movements of the same day in the order they happen to a loan
+var lineOrder = map[string]int{
+	LineTypeDisbursement: 0,
+	LineTypeFee:          1,
+	LineTypeInterest:     2,
+	LineTypePayment:      3,
+	LineTypeReversal:     4,
+	LineTypeRefund:       5,
+}
+
+// movement is a single change to the balance a borrower owes
+type movement struct {
+	date        time.Time
+	kind        string
+	reference   string
+	description string
+	debit       int64
+	credit      int64
+	allocations []allocation
+}
+
+// allocation is the part of a payment, or of its reversal, applied to one installment
+type allocation struct {
+	installmentNumber int
+	dueDate           time.Time
+	amount            int64
+}
+
+// statementSources are the records a statement is built from
+type statementSources struct {
+	application models.LoanApplication
+	repayments  []models.Repayment
+	payments    []models.Payment
+	logs        []models.RepaymentPaymentLog
+	refunds     []models.Refund
+	fees        []models.JournalEntry
+}
+
+// GetAccountStatement lists the disbursement, interest and fees charged, payments with their allocation to the
+// installments, reversals and refunds of a loan over a date range, with the balance owed after each of them.
+// The balance brought forward from before the range is the opening balance.
+// Parameters:
+// - request: dto.AccountStatementRequest with the application ID and the range
+// - user: models.User representing the customer or employee of the application
+// Returns:
+// - dto.AccountStatementResponse with the statement
+// - dto.HandleError with any error that occurred during the process
+func (s *statementService) GetAccountStatement(request dto.AccountStatementRequest, user models.User) (
+	response dto.AccountStatementResponse, handle dto.HandleError) {
+	from, _ := time.ParseInLocation(statementDateLayout, request.From, time.Local)
+	to, _ := time.ParseInLocation(statementDateLayout, request.To, time.Local)
+	if to.Before(from) {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("the statement period ends before it starts")
+		return
+	}
+
+	application := models.LoanApplication{}
+	application, _ = application.FindByPrimaryKey(request.ApplicationID)
+	if application.ApplicationID == "" {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	var participantCondition []db.WhereCondition
+	participantCondition = append(participantCondition, db.WhereCondition{
+		Key:       models.LoanApplicationParticipantColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+
+	participant := models.LoanApplicationParticipant{}
+	participants, err := participant.FindAllByCondition(participantCondition)
+	if err != nil {
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	customerId := ""
+	for _, participant := range participants {
+		if participant.ParticipantType == constants.UserTypeCustomer {
+			customerId = participant.UserID
+		}
+	}
+
+	// Participants read the documents of their applications, auditors of any
+	rule := authorizationService.Rule{Oversight: models.PermissionApplicationReadAll}
+	if err := authorizationService.AuthorizeApplication(user, application.ApplicationID, rule); err != nil {
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if !application.ApprovedDate.Valid {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("application %v has not been disbursed", application.ApplicationID)
+		return
+	}
+
+	sources, err := loadSources(application)
+	if err != nil {
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	customer := models.User{}
+	customer, _ = customer.FindByPrimaryKey(customerId)
+
+	response.Status = 1
+	response.Data = buildStatement(sources, from, to)
+	response.Data.Tenant = s.tenant
+	response.Data.CustomerName = customer.UserName
+	response.Data.GeneratedAt = time.Now().Format(time.RFC3339)
+	return
+}
+
+// loadSources reads the schedule, payments, allocations, refunds and fees of a loan
+func loadSources(application models.LoanApplication) (sources statementSources, err error) {
+	sources.application = application
+
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+
+	repayment := models.Repayment{}
+	if sources.repayments, err = repayment.FindAllByCondition(repaymentCondition); err != nil {
+		return
+	}
+
+	// Only payments that reached the schedule move the balance, a reversed payment had been received before
+	var paymentCondition []db.WhereCondition
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+	paymentCondition = append(paymentCondition, db.WhereCondition{
+		Key:       models.PaymentColumns.Status,
+		Condition: "IN",
+		Value:     []string{models.PaymentStatusReceived, models.PaymentStatusSettled, models.PaymentStatusReversed},
+	})
+
+	payment := models.Payment{}
+	if sources.payments, err = payment.FindAllByCondition(paymentCondition); err != nil {
+		return
+	}
+
+	if len(sources.payments) > 0 {
+		var paymentIds []string
+		for _, payment := range sources.payments {
+			paymentIds = append(paymentIds, payment.PaymentID)
+		}
+
+		var logCondition []db.WhereCondition
+		logCondition = append(logCondition, db.WhereCondition{
+			Key:       models.RepaymentPaymentLogColumns.PaymentID,
+			Condition: "IN",
+			Value:     paymentIds,
+		})
+
+		log := models.RepaymentPaymentLog{}
+		if sources.logs, err = log.FindAllByCondition(logCondition); err != nil {
+			return
+		}
+	}
+
+	var refundCondition []db.WhereCondition
+	refundCondition = append(refundCondition, db.WhereCondition{
+		Key:       models.RefundColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+	refundCondition = append(refundCondition, db.WhereCondition{
+		Key:       models.RefundColumns.Status,
+		Condition: "=",
+		Value:     models.RefundStatusProcessed,
+	})
+
+	refund := models.Refund{}
+	if sources.refunds, err = refund.FindAllByCondition(refundCondition); err != nil {
+		return
+	}
+
+	var feeCondition []db.WhereCondition
+	feeCondition = append(feeCondition, db.WhereCondition{
+		Key:       models.JournalEntryColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+	feeCondition = append(feeCondition, db.WhereCondition{
+		Key:       models.JournalEntryColumns.EntryType,
+		Condition: "=",
+		Value:     models.JournalEntryFeeCharge,
+	})
+
+	entry := models.JournalEntry{}
+	sources.fees, err = entry.FindAllByCondition(feeCondition)
+	return
+}
+
+// buildStatement turns the records of a loan into its statement over a date range
+// Parameters:
+// - sources: the records of the loan
+// - from: the first day of the statement
+// - to: the last day of the statement
+// Returns:
+// - dto.AccountStatement with the lines in the range and the balances
+func buildStatement(sources statementSources, from time.Time, to time.Time) (statement dto.AccountStatement) {
+	application := sources.application
+	currency := application.CurrencyCode
+
+	movements := collectMovements(sources)
+	sort.SliceStable(movements, func(i, j int) bool {
+		if !movements[i].date.Equal(movements[j].date) {
+			return movements[i].date.Before(movements[j].date)
+		}
+		return lineOrder[movements[i].kind] < lineOrder[movements[j].kind]
+	})
+
+	end := to.AddDate(0, 0, 1)
+	var opening, balance, debits, credits int64
+	statement.Lines = []dto.AccountStatementLine{}
+	for _, movement := range movements {
+		if !movement.date.Before(end) {
+			break
+		}
+		balance += movement.debit - movement.credit
+		if movement.date.Before(from) {
+			opening = balance
+			continue
+		}
+
+		debits += movement.debit
+		credits += movement.credit
+		line := dto.AccountStatementLine{
+			Date:        movement.date.Format(statementDateLayout),
+			Type:        movement.kind,
+			Reference:   movement.reference,
+			Description: movement.description,
+			Balance:     display(balance, currency),
+		}
+		if movement.debit != 0 {
+			line.Debit = display(movement.debit, currency)
+		}
+		if movement.credit != 0 {
+			line.Credit = display(movement.credit, currency)
+		}
+		for _, allocation := range movement.allocations {
+			line.Allocations = append(line.Allocations, dto.AccountStatementAllocation{
+				InstallmentNumber: allocation.installmentNumber,
+				DueDate:           allocation.dueDate.Format(statementDateLayout),
+				Amount:            display(allocation.amount, currency),
+			})
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	statement.ApplicationId = application.ApplicationID
+	statement.CurrencyCode = currency
+	statement.LoanStatus = application.Status
+	statement.From = from.Format(statementDateLayout)
+	statement.To = to.Format(statementDateLayout)
+	statement.OpeningBalance = display(opening, currency)
+	statement.TotalDebits = display(debits, currency)
+	statement.TotalCredits = display(credits, currency)
+	statement.ClosingBalance = display(opening+debits-credits, currency)
+	return
+}
+
+// collectMovements lists every change to the balance of a loan, in no particular order. The disbursement, interest,
+// fees, reversals and refunds are debits, payments are credits.
+func collectMovements(sources statementSources) (movements []movement) {
+	application := sources.application
+
+	if application.ApprovedDate.Valid {
+		movements = append(movements, movement{
+			date:        startOfDay(application.ApprovedDate.Time),
+			kind:        LineTypeDisbursement,
+			reference:   application.ApplicationID,
+			description: "Loan disbursed",
+			debit:       toCents(application.ApprovedAmount.Float64),
+		})
+	}
+
+	installments := make(map[string]models.Repayment)
+	for _, repayment := range sources.repayments {
+		installments[repayment.RepaymentID] = repayment
+		if repayment.InterestAmount == 0 {
+			continue
+		}
+
+		// Interest falls due on the installment date, or when the installment was settled early
+		charged := repayment.InstallmentDate
+		if repayment.PaymentDate.Valid && repayment.PaymentDate.Time.Before(charged) {
+			charged = repayment.PaymentDate.Time
+		}
+		movements = append(movements, movement{
+			date:        startOfDay(charged),
+			kind:        LineTypeInterest,
+			reference:   repayment.PaymentReference,
+			description: fmt.Sprintf("Interest on installment %d", repayment.InstallmentNumber),
+			debit:       toCents(repayment.InterestAmount),
+		})
+	}
+
+	for _, entry := range sources.fees {
+		var amount int64
+		for _, line := range entry.Lines {
+			if line.AccountCode == models.LedgerAccountFeesReceivable {
+				amount += toCents(line.Debit) - toCents(line.Credit)
+			}
+		}
+		movements = append(movements, movement{
+			date:        startOfDay(entry.EffectiveDate),
+			kind:        LineTypeFee,
+			reference:   entry.SourceReference,
+			description: entry.Description,
+			debit:       amount,
+		})
+	}
+
+	// A payment's allocations are logged as positive amounts, unwinding them on reversal as negative ones
+	allocated := make(map[string][]allocation)
+	unwound := make(map[string][]allocation)
+	for _, log := range sources.logs {
+		installment := installments[log.RepaymentID]
+		entry := allocation{
+			installmentNumber: installment.InstallmentNumber,
+			dueDate:           installment.InstallmentDate,
+			amount:            toCents(math.Abs(log.Amount)),
+		}
+		if log.Amount < 0 {
+			unwound[log.PaymentID] = append(unwound[log.PaymentID], entry)
+		} else {
+			allocated[log.PaymentID] = append(allocated[log.PaymentID], entry)
+		}
+	}
+
+	for _, payment := range sources.payments {
+		received := payment.CreatedAt
+		if payment.SettledAt.Valid {
+			received = payment.SettledAt.Time
+		}
+
+		description := "Payment received"
+		if payment.Gateway.Valid {
+			description = fmt.Sprintf("Payment received via %v", payment.Gateway.String)
+		}
+		if payment.ExcessAmount > 0 {
+			description = fmt.Sprintf("%v, %v held as excess credit", description,
+				display(toCents(payment.ExcessAmount), application.CurrencyCode))
+		}
+		movements = append(movements, movement{
+			date:        startOfDay(received),
+			kind:        LineTypePayment,
+			reference:   payment.PaymentID,
+			description: description,
+			credit:      toCents(payment.Amount),
+			allocations: allocated[payment.PaymentID],
+		})
+
+		if payment.Status == models.PaymentStatusReversed && payment.ReversedAt.Valid {
+			description = "Payment reversed"
+			if payment.ReversalReason.Valid {
+				description = fmt.Sprintf("Payment reversed: %v", payment.ReversalReason.String)
+			}
+			movements = append(movements, movement{
+				date:        startOfDay(payment.ReversedAt.Time),
+				kind:        LineTypeReversal,
+				reference:   payment.PaymentID,
+				description: description,
+				debit:       toCents(payment.Amount),
+				allocations: unwound[payment.PaymentID],
+			})
+		}
+	}
+
+	for _, refund := range sources.refunds {
+		description := "Excess credit refunded"
+		if refund.Reason.Valid {
+			description = fmt.Sprintf("Excess credit refunded: %v", refund.Reason.String)
+		}
+		movements = append(movements, movement{
+			date:        startOfDay(refund.CreatedAt),
+			kind:        LineTypeRefund,
+			reference:   refund.RefundID,
+			description: description,
+			debit:       toCents(refund.Amount),
+		})
+	}
+	return
+}
+
+// toCents converts an amount into hundredths so balances add up without rounding drift
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// display formats an amount in hundredths in the currency of the loan
+func display(cents int64, currency string) string {
+	return money.NewFromFloat(float64(cents)/100, currency).Display()
+}
+
+// startOfDay truncates a time to midnight in its location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,162 @@
+package statement_service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// statementFixture is a loan of 1,000 with two installments, the first one paid, a payment that bounced and a
+// refund of the excess credit left by the second installment
+func statementFixture() statementSources {
+	return statementSources{
+		application: models.LoanApplication{
+			ApplicationID:  "app_id",
+			CurrencyCode:   "INR",
+			Status:         models.LoanApplicationStatusPaid,
+			ApprovedAmount: null.FloatFrom(1000),
+			ApprovedDate:   null.TimeFrom(date(2024, 1, 1).Add(10 * time.Hour)),
+		},
+		repayments: []models.Repayment{
+			{RepaymentID: "r1", InstallmentNumber: 1, InterestAmount: 10, InstallmentDate: date(2024, 2, 1),
+				PaymentDate: null.TimeFrom(date(2024, 2, 1))},
+			{RepaymentID: "r2", InstallmentNumber: 2, InterestAmount: 5, InstallmentDate: date(2024, 3, 1),
+				PaymentDate: null.TimeFrom(date(2024, 2, 20))},
+		},
+		payments: []models.Payment{
+			{PaymentID: "p1", Amount: 510, Status: models.PaymentStatusSettled, CreatedAt: date(2024, 1, 31),
+				SettledAt: null.TimeFrom(date(2024, 2, 1).Add(9 * time.Hour))},
+			{PaymentID: "p2", Amount: 505, Status: models.PaymentStatusReversed, CreatedAt: date(2024, 2, 10),
+				ReversedAt: null.TimeFrom(date(2024, 2, 12)), ReversalReason: null.StringFrom("cheque bounced")},
+			{PaymentID: "p3", Amount: 515, ExcessAmount: 10, Status: models.PaymentStatusReceived,
+				Gateway: null.StringFrom("razorpay"), CreatedAt: date(2024, 2, 20)},
+		},
+		logs: []models.RepaymentPaymentLog{
+			{RepaymentID: "r1", PaymentID: "p1", Amount: 510},
+			{RepaymentID: "r2", PaymentID: "p2", Amount: 505},
+			{RepaymentID: "r2", PaymentID: "p2", Amount: -505},
+			{RepaymentID: "r2", PaymentID: "p3", Amount: 505},
+		},
+		refunds: []models.Refund{
+			{RefundID: "f1", Amount: 10, Reason: null.StringFrom("excess"), CreatedAt: date(2024, 2, 25)},
+		},
+		fees: []models.JournalEntry{
+			{SourceReference: "FEE_CHARGE:late", Description: "Late payment fee", EffectiveDate: date(2024, 2, 15),
+				Lines: []models.JournalLine{
+					{AccountCode: models.LedgerAccountFeesReceivable, Debit: 25},
+					{AccountCode: models.LedgerAccountFeeIncome, Credit: 25},
+				}},
+		},
+	}
+}
+
+func TestBuildStatement_RunningBalances(t *testing.T) {
+	statement := buildStatement(statementFixture(), date(2024, 1, 1), date(2024, 3, 31))
+
+	var types, balances []string
+	for _, line := range statement.Lines {
+		types = append(types, line.Type)
+		balances = append(balances, line.Balance)
+	}
+	assert.Equal(t, []string{
+		LineTypeDisbursement, LineTypeInterest, LineTypePayment, LineTypePayment, LineTypeReversal, LineTypeFee,
+		LineTypeInterest, LineTypePayment, LineTypeRefund,
+	}, types)
+	assert.Equal(t, []string{
+		"₹1,000.00", "₹1,010.00", "₹500.00", "-₹5.00", "₹500.00", "₹525.00", "₹530.00", "₹15.00", "₹25.00",
+	}, balances)
+
+	assert.Equal(t, "₹0.00", statement.OpeningBalance)
+	assert.Equal(t, "₹1,555.00", statement.TotalDebits)
+	assert.Equal(t, "₹1,530.00", statement.TotalCredits)
+	assert.Equal(t, "₹25.00", statement.ClosingBalance)
+
+	payment := statement.Lines[2]
+	assert.Equal(t, "2024-02-01", payment.Date)
+	assert.Equal(t, "₹510.00", payment.Credit)
+	assert.Equal(t, []dto.AccountStatementAllocation{{InstallmentNumber: 1, DueDate: "2024-02-01", Amount: "₹510.00"}},
+		payment.Allocations)
+
+	reversal := statement.Lines[4]
+	assert.Equal(t, "Payment reversed: cheque bounced", reversal.Description)
+	assert.Equal(t, "₹505.00", reversal.Debit)
+	assert.Len(t, reversal.Allocations, 1)
+
+	assert.Equal(t, "Payment received via razorpay, ₹10.00 held as excess credit", statement.Lines[7].Description)
+}
+
+func TestBuildStatement_OpeningBalanceBroughtForward(t *testing.T) {
+	statement := buildStatement(statementFixture(), date(2024, 2, 13), date(2024, 2, 20))
+
+	assert.Equal(t, "₹500.00", statement.OpeningBalance)
+	assert.Len(t, statement.Lines, 3)
+	assert.Equal(t, LineTypeFee, statement.Lines[0].Type)
+	assert.Equal(t, "₹15.00", statement.ClosingBalance)
+	assert.Equal(t, "2024-02-13", statement.From)
+	assert.Equal(t, "2024-02-20", statement.To)
+}
+
+func TestBuildStatement_EmptyRange(t *testing.T) {
+	statement := buildStatement(statementFixture(), date(2024, 6, 1), date(2024, 6, 30))
+
+	assert.Empty(t, statement.Lines)
+	assert.Equal(t, "₹25.00", statement.OpeningBalance)
+	assert.Equal(t, "₹25.00", statement.ClosingBalance)
+}
+
+func TestWriteAccountStatementCSV(t *testing.T) {
+	statement := buildStatement(statementFixture(), date(2024, 1, 1), date(2024, 2, 1))
+
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteAccountStatementCSV(&buffer, statement))
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, statementCSVHeader, records[0])
+	assert.Equal(t, []string{"2024-01-01", "OPENING_BALANCE", "", "", "", "", "₹0.00", "", "", ""}, records[1])
+	assert.Equal(t, "PAYMENT", records[4][1])
+	assert.Equal(t, []string{"2024-02-01", "ALLOCATION", "p1", "", "", "", "", "1", "2024-02-01", "₹510.00"}, records[5])
+	assert.Equal(t, []string{"2024-02-01", "CLOSING_BALANCE", "", "", "₹1,010.00", "₹510.00", "₹500.00", "", "", ""},
+		records[6])
+}
+
+func TestWriteAccountStatementPDF(t *testing.T) {
+	statement := buildStatement(statementFixture(), date(2024, 1, 1), date(2024, 3, 31))
+	statement.Tenant = "ASPIRE"
+	statement.CustomerName = "Jane (Customer)"
+
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteAccountStatementPDF(&buffer, statement))
+
+	output := buffer.String()
+	assert.True(t, strings.HasPrefix(output, "%PDF-1.4"))
+	assert.Contains(t, output, "(ASPIRE)")
+	assert.Contains(t, output, `(Jane \(Customer\))`)
+	assert.Contains(t, output, "(Rs.1,010.00)")
+	assert.Contains(t, output, "(Page 1 of 1)")
+}
+
+func TestWriteAccountStatementPDF_Paginates(t *testing.T) {
+	statement := dto.AccountStatement{Tenant: "ASPIRE", ApplicationId: "app_id"}
+	for i := 0; i < 100; i++ {
+		statement.Lines = append(statement.Lines, dto.AccountStatementLine{
+			Date: "2024-01-01", Type: LineTypeInterest, Description: "Interest", Debit: "$1.00", Balance: "$1.00",
+		})
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteAccountStatementPDF(&buffer, statement))
+	assert.True(t, strings.Contains(buffer.String(), "/Count 3"))
+	assert.True(t, strings.Contains(buffer.String(), "(Page 3 of 3)"))
+}
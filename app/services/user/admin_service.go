@@ -0,0 +1,694 @@
+package user_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userInviteMessage is the notification carrying the code a new user sets their password with, formatted with the
+// code and its lifetime
+const userInviteMessage = "An account was created for you at %v. Set your password with the code %%v, it expires in " +
+	"%%d minutes. Once it has, ask for a new code with forgot password."
+
+// adminResetMessage is the notification carrying the code of a password reset by an administrator, formatted with
+// the code and its lifetime
+const adminResetMessage = "Your %v password was reset by an administrator. Choose a new one with the code %%v, it " +
+	"expires in %%d minutes."
+
+// errUserDeactivated refuses signing in and issuing tokens to deactivated users
+var errUserDeactivated = fmt.Errorf("user has been deactivated")
+
+// CreateUser creates a customer or an employee with the roles given to them. The user has no password yet, a code
+// to set one through ResetPassword is sent to their email or mobile number.
+// Parameters:
+// - request: dto.UserCreateRequest with the details of the user, their roles and the channel to send the code on
+// - admin: the employee creating the user
+// Returns:
+// - dto.UserDetailResponse with the user
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) CreateUser(request dto.UserCreateRequest, admin models.User) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	roles, err := checkRoles(request.UserType, request.Roles)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+	existing := findUserByContact(request.UserType, request.UserEmail, request.MobileNumber, "")
+	if existing.UserID != "" {
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("a user with this email or mobile number already exists")
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	user := models.User{
+		UserID:       uuid.New().String(),
+		UserName:     request.UserName,
+		UserEmail:    request.UserEmail,
+		MobileNumber: request.MobileNumber,
+		UserType:     request.UserType,
+		Status:       models.UserStatusActive,
+	}
+	if err = tx.Create(&user).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+	if err = saveRoles(tx, user.UserID, roles, admin.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	// The new user chooses their own password with the code, the same way as a forgotten one
+	message := s.passwordCodeMessage(user, request.Channel, userInviteMessage)
+	if _, err = issueOTP(tx, s.notifier, message, user.UserID); err != nil {
+		tx.Rollback()
+		logger.Sugar.Error("could not send password code to user ", user.UserID, ": ", err)
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("password code could not be sent, try again later")
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	logger.Sugar.Infow("user created", "user_id", user.UserID, "user_type", user.UserType, "roles", roles,
+		"created_by", admin.UserID)
+	response.Status = 1
+	response.Message = fmt.Sprintf("User created, a code to set their password was sent by %v", message.Channel)
+	response.Data = toUserDetailObject(user, roles)
+	return
+}
+
+// GetUsers lists a page of the users, the latest created first
+// Parameters:
+// - request: dto.UserListRequest with the type, status and search narrowing the list, and the page
+// Returns:
+// - dto.UserListResponse with the users and their roles
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) GetUsers(request dto.UserListRequest) (response dto.UserListResponse, handle dto.HandleError) {
+	user := models.User{}
+	users, total, err := user.FindPage(request.UserType, request.Status, strings.TrimSpace(request.Search),
+		request.Page, request.Limit)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	userIds := make([]string, 0, len(users))
+	for _, user := range users {
+		userIds = append(userIds, user.UserID)
+	}
+	userRole := models.UserRole{}
+	assigned, err := userRole.FindByUsers(userIds)
+	if err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+	roles := map[string][]string{}
+	for _, role := range assigned {
+		roles[role.UserID] = append(roles[role.UserID], role.RoleCode)
+	}
+
+	response.Data.Users = []dto.UserDetailObject{}
+	for _, user := range users {
+		response.Data.Users = append(response.Data.Users, toUserDetailObject(user, roles[user.UserID]))
+	}
+	response.Data.Total = total
+	response.Data.Page = request.Page
+	response.Data.Limit = request.Limit
+	response.Status = 1
+	response.Message = fmt.Sprintf("Total %v user(s) found", total)
+	return
+}
+
+// GetUser returns the details of a user and their roles
+// Parameters:
+// - userId: the user
+// Returns:
+// - dto.UserDetailResponse with the user
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) GetUser(userId string) (response dto.UserDetailResponse, handle dto.HandleError) {
+	user := models.User{}
+	user, err := user.FindByPrimaryKey(userId)
+	if err != nil || user.UserID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("user %v not found", userId)
+		return
+	}
+
+	userRole := models.UserRole{}
+	roles, err := userRole.FindRoleCodesByUser(userId)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "User found"
+	response.Data = toUserDetailObject(user, roles)
+	return
+}
+
+// UpdateUser changes the name, email or mobile number of a user, which stay unique among the users of their type
+// Parameters:
+// - request: dto.UserUpdateRequest with the user and the details to change
+// - admin: the employee updating the user
+// Returns:
+// - dto.UserDetailResponse with the updated user
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) UpdateUser(request dto.UserUpdateRequest, admin models.User) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	user, err := lockUser(tx, request.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	if request.UserName != "" {
+		user.UserName = request.UserName
+	}
+	if request.UserEmail != "" {
+		user.UserEmail = request.UserEmail
+	}
+	if request.MobileNumber != "" {
+		user.MobileNumber = request.MobileNumber
+	}
+	existing := findUserByContact(user.UserType, user.UserEmail, user.MobileNumber, user.UserID)
+	if existing.UserID != "" {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("a user with this email or mobile number already exists")
+		return
+	}
+
+	if err = tx.Omit(clause.Associations).Save(&user).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	logger.Sugar.Infow("user updated", "user_id", user.UserID, "updated_by", admin.UserID)
+	return s.userDetail(user, "User updated")
+}
+
+// DeactivateUser disables a user: every session is signed out at once and the user can no longer sign in. The
+// pending applications an employee holds stay with them until they are reassigned.
+// Parameters:
+// - request: dto.UserStatusRequest with the user and the reason
+// - admin: the employee deactivating the user, who cannot deactivate themselves
+// Returns:
+// - dto.UserDetailResponse with the deactivated user
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) DeactivateUser(request dto.UserStatusRequest, admin models.User) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	if request.UserID == admin.UserID {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("you cannot deactivate yourself")
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	user, err := lockUser(tx, request.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if user.Status == models.UserStatusInactive {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("user has already been deactivated")
+		return
+	}
+
+	// A pending password setup cannot be finished once the user is deactivated
+	user.Status = models.UserStatusInactive
+	user.DeactivatedAt = null.TimeFrom(time.Now())
+	user.DeactivatedBy = null.StringFrom(admin.UserID)
+	user.DeactivationReason = null.NewString(request.Reason, request.Reason != "")
+	user.PasswordSetupToken = null.String{}
+	user.PasswordSetupExpires = null.Time{}
+	if err = tx.Omit(clause.Associations).Save(&user).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	session := models.AuthSession{}
+	if err = session.RevokeByUser(tx, user.UserID, "", models.SessionRevokedDeactivated); err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	logger.Sugar.Infow("user deactivated", "user_id", user.UserID, "reason", request.Reason,
+		"deactivated_by", admin.UserID)
+	message := "User deactivated"
+	if user.UserType == constants.UserTypeEmployee {
+		assignment := models.ApplicationAssignment{}
+		pending, err := assignment.FindPendingApplicationIDsByUser(user.UserID)
+		if err != nil {
+			logger.Sugar.Error("could not read pending applications of user ", user.UserID, ": ", err)
+		}
+		if len(pending) > 0 {
+			message = fmt.Sprintf("User deactivated, reassign the %v pending application(s) they hold", len(pending))
+		}
+	}
+	return s.userDetail(user, message)
+}
+
+// ReactivateUser enables a deactivated user again, who signs in with their password as before
+// Parameters:
+// - request: dto.UserStatusRequest with the user
+// - admin: the employee reactivating the user
+// Returns:
+// - dto.UserDetailResponse with the reactivated user
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) ReactivateUser(request dto.UserStatusRequest, admin models.User) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	user, err := lockUser(tx, request.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+	if user.Status != models.UserStatusInactive {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("user is already active")
+		return
+	}
+
+	user.Status = models.UserStatusActive
+	user.DeactivatedAt = null.Time{}
+	user.DeactivatedBy = null.String{}
+	user.DeactivationReason = null.String{}
+	if err = tx.Omit(clause.Associations).Save(&user).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	logger.Sugar.Infow("user reactivated", "user_id", user.UserID, "reason", request.Reason,
+		"reactivated_by", admin.UserID)
+	return s.userDetail(user, "User reactivated")
+}
+
+// ResetUserPassword clears the password of a user, signs out every session and sends them a code to choose a new
+// password through ResetPassword. The reset is written to the password reset audit log.
+// Parameters:
+// - request: dto.UserPasswordResetRequest with the user and the channel to send the code on
+// - admin: the employee resetting the password
+// Returns:
+// - dto.UserDetailResponse with the user
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) ResetUserPassword(request dto.UserPasswordResetRequest, admin models.User) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	user, err := lockUser(tx, request.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+	if user.Status == models.UserStatusInactive {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("user has been deactivated, reactivate them first")
+		return
+	}
+
+	user.UserPassword = ""
+	user.PasswordChangeRequired = false
+	user.PasswordSetupToken = null.String{}
+	user.PasswordSetupExpires = null.Time{}
+	if err = tx.Omit(clause.Associations).Save(&user).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+	session := models.AuthSession{}
+	if err = session.RevokeByUser(tx, user.UserID, "", models.SessionRevokedPasswordReset); err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	// A code sent moments ago stays valid and is not replaced
+	message := s.passwordCodeMessage(user, request.Channel, adminResetMessage)
+	if _, err = issueOTP(tx, s.notifier, message, user.UserID); err != nil && err != errOtpTooSoon {
+		tx.Rollback()
+		logger.Sugar.Error("could not send password code to user ", user.UserID, ": ", err)
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("password code could not be sent, try again later")
+		return
+	}
+
+	audit := newPasswordResetEvent(message.Recipient, "")
+	audit.UserID = null.StringFrom(user.UserID)
+	audit.Channel = null.StringFrom(message.Channel)
+	if err = recordPasswordReset(tx, audit, models.PasswordResetByAdmin, "by "+admin.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	return s.userDetail(user, fmt.Sprintf("Password reset, a code to choose a new one was sent by %v",
+		message.Channel))
+}
+
+// AssignRoles replaces the roles of an employee. The roles are embedded in the access token, so when they change
+// every session of the employee is signed out and the new roles apply from their next sign in.
+// Parameters:
+// - request: dto.UserRolesRequest with the employee and their roles
+// - admin: the employee assigning the roles, who cannot change their own
+// Returns:
+// - dto.UserDetailResponse with the employee and their new roles
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) AssignRoles(request dto.UserRolesRequest, admin models.User) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	if request.UserID == admin.UserID {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("you cannot change your own roles")
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	user, err := lockUser(tx, request.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	roles, err := checkRoles(user.UserType, request.Roles)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	userRole := models.UserRole{}
+	current, err := userRole.FindRoleCodesByUser(user.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+	changed := !sameRoles(current, roles)
+	if err = userRole.DeleteByUser(tx, user.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+	if err = saveRoles(tx, user.UserID, roles, admin.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	// Tokens carrying the previous roles must not outlive the change
+	if changed {
+		session := models.AuthSession{}
+		if err = session.RevokeByUser(tx, user.UserID, "", models.SessionRevokedRolesChanged); err != nil {
+			tx.Rollback()
+			handle.Status = -4
+			handle.Errors = err
+			return
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	logger.Sugar.Infow("roles assigned", "user_id", user.UserID, "roles", roles, "assigned_by", admin.UserID,
+		"changed", changed)
+	response.Status = 1
+	response.Message = "Roles unchanged"
+	if changed {
+		response.Message = "Roles assigned, the user has been signed out of every session"
+	}
+	response.Data = toUserDetailObject(user, roles)
+	return
+}
+
+// userDetail answers with a user and their current roles
+func (s *userService) userDetail(user models.User, message string) (
+	response dto.UserDetailResponse, handle dto.HandleError) {
+	userRole := models.UserRole{}
+	roles, err := userRole.FindRoleCodesByUser(user.UserID)
+	if err != nil {
+		logger.Sugar.Error("could not read roles of user ", user.UserID, ": ", err)
+	}
+	response.Status = 1
+	response.Message = message
+	response.Data = toUserDetailObject(user, roles)
+	return
+}
+
+// passwordCodeMessage returns the notification of a code to set a password: on the channel asked for, otherwise by
+// email, or by SMS to users without an email
+func (s *userService) passwordCodeMessage(user models.User, channel string, body string) dto.Notification {
+	if channel == "" {
+		channel = notificationService.ChannelEmail
+		if user.UserEmail == "" {
+			channel = notificationService.ChannelSMS
+		}
+	}
+	message := dto.Notification{
+		Channel:   channel,
+		Recipient: user.UserEmail,
+		Subject:   fmt.Sprintf("%v password", s.tenant),
+		Body:      fmt.Sprintf(body, s.tenant),
+		Purpose:   models.OtpPurposePasswordReset,
+	}
+	if channel == notificationService.ChannelSMS {
+		message.Recipient = user.MobileNumber
+	}
+	return message
+}
+
+// sameRoles reports whether two lists hold the same role codes, in any order
+func sameRoles(current []string, roles []string) bool {
+	if len(current) != len(roles) {
+		return false
+	}
+	held := map[string]bool{}
+	for _, code := range current {
+		held[code] = true
+	}
+	for _, code := range roles {
+		if !held[code] {
+			return false
+		}
+	}
+	return true
+}
+
+// lockUser finds a user and locks it until the transaction ends
+func lockUser(tx *gorm.DB, userId string) (models.User, error) {
+	user := models.User{}
+	user, err := user.FindByPrimaryKeyForUpdate(tx, userId)
+	if err != nil {
+		return user, err
+	}
+	if user.UserID == "" {
+		return user, fmt.Errorf("user %v not found", userId)
+	}
+	return user, nil
+}
+
+// findUserByContact finds another user of a type with an email or a mobile number
+// Parameters:
+// - userType: the type of user, emails and mobile numbers are unique within a type
+// - email: the email to look for
+// - mobileNumber: the mobile number to look for
+// - exceptUserId: the user being updated, empty when creating one
+// Returns:
+// - models.User that was found, empty when there is none
+func findUserByContact(userType string, email string, mobileNumber string, exceptUserId string) (user models.User) {
+	var andCondition, orCondition []db.WhereCondition
+	andCondition = append(andCondition, db.WhereCondition{
+		Key:       models.UsersColumns.UserType,
+		Condition: "=",
+		Value:     userType,
+	})
+	if exceptUserId != "" {
+		andCondition = append(andCondition, db.WhereCondition{
+			Key:       models.UsersColumns.UserID,
+			Condition: "<>",
+			Value:     exceptUserId,
+		})
+	}
+	orCondition = append(orCondition, db.WhereCondition{
+		Key:       models.UsersColumns.UserEmail,
+		Condition: "=",
+		Value:     email,
+	})
+	orCondition = append(orCondition, db.WhereCondition{
+		Key:       models.UsersColumns.MobileNumber,
+		Condition: "=",
+		Value:     mobileNumber,
+	})
+
+	user, _ = user.FindOneByCondition(&andCondition, &orCondition)
+	return
+}
+
+// checkRoles verifies that roles exist and can be given to a type of user, only employees hold roles
+// Parameters:
+// - userType: the type of the user the roles are given to
+// - roles: the role codes
+// Returns:
+// - []string with the role codes without duplicates
+// - error when a role cannot be given
+func checkRoles(userType string, roles []string) ([]string, error) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, code := range roles {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !seen[code] {
+			seen[code] = true
+			unique = append(unique, code)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+	if userType != constants.UserTypeEmployee {
+		return nil, fmt.Errorf("roles are only assigned to employees")
+	}
+
+	role := models.Role{}
+	found, err := role.FindByCodes(unique)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, role := range found {
+		known[role.RoleCode] = true
+	}
+	for _, code := range unique {
+		if !known[code] {
+			return nil, fmt.Errorf("role %v not found", code)
+		}
+	}
+	return unique, nil
+}
+
+// saveRoles assigns roles to a user
+func saveRoles(tx *gorm.DB, userId string, roles []string, assignedBy string) error {
+	for _, code := range roles {
+		userRole := models.UserRole{
+			UserID:     userId,
+			RoleCode:   code,
+			AssignedBy: null.StringFrom(assignedBy),
+		}
+		if err := tx.Create(&userRole).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toUserDetailObject converts a user and their roles to its DTO
+func toUserDetailObject(user models.User, roles []string) dto.UserDetailObject {
+	object := dto.UserDetailObject{
+		UserID:             user.UserID,
+		UserName:           user.UserName,
+		UserEmail:          user.UserEmail,
+		MobileNumber:       user.MobileNumber,
+		UserType:           user.UserType,
+		Status:             user.Status,
+		Roles:              roles,
+		DeactivatedBy:      user.DeactivatedBy.String,
+		DeactivationReason: user.DeactivationReason.String,
+	}
+	if user.DeactivatedAt.Valid {
+		object.DeactivatedAt = user.DeactivatedAt.Time.Format(time.RFC3339)
+	}
+	if !user.CreatedAt.IsZero() {
+		object.CreatedAt = user.CreatedAt.Format(time.RFC3339)
+	}
+	return object
+}
@@ -0,0 +1,80 @@
+package user_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRoles_CustomerHoldsNone(t *testing.T) {
+	roles, err := checkRoles(constants.UserTypeCustomer, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, roles)
+
+	_, err = checkRoles(constants.UserTypeCustomer, []string{"AUDITOR"})
+	assert.EqualError(t, err, "roles are only assigned to employees")
+
+	// Blank roles are dropped before anything is looked up
+	roles, err = checkRoles(constants.UserTypeEmployee, []string{" ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, roles)
+}
+
+func TestSameRoles(t *testing.T) {
+	assert.True(t, sameRoles([]string{"AUDITOR", "UNDERWRITER"}, []string{"UNDERWRITER", "AUDITOR"}))
+	assert.True(t, sameRoles(nil, []string{}))
+	assert.False(t, sameRoles([]string{"AUDITOR"}, []string{"AUDITOR", "UNDERWRITER"}))
+	assert.False(t, sameRoles([]string{"AUDITOR"}, []string{"UNDERWRITER"}))
+	assert.False(t, sameRoles([]string{"AUDITOR"}, []string{}))
+}
+
+func TestPasswordCodeMessage(t *testing.T) {
+	service := &userService{tenant: "Aspire"}
+	user := models.User{UserEmail: "jane.doe@example.com", MobileNumber: "6591230381"}
+
+	message := service.passwordCodeMessage(user, "", adminResetMessage)
+	assert.Equal(t, notificationService.ChannelEmail, message.Channel)
+	assert.Equal(t, "jane.doe@example.com", message.Recipient)
+	assert.Equal(t, models.OtpPurposePasswordReset, message.Purpose)
+	assert.Contains(t, message.Body, "Your Aspire password was reset by an administrator")
+
+	message = service.passwordCodeMessage(user, notificationService.ChannelSMS, userInviteMessage)
+	assert.Equal(t, "6591230381", message.Recipient)
+
+	// Users without an email are sent the code by SMS
+	user.UserEmail = ""
+	message = service.passwordCodeMessage(user, "", userInviteMessage)
+	assert.Equal(t, notificationService.ChannelSMS, message.Channel)
+	assert.Equal(t, "6591230381", message.Recipient)
+}
+
+func TestToUserDetailObject(t *testing.T) {
+	deactivatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	user := models.User{
+		UserID:             "user_id",
+		UserName:           "Jane Doe",
+		UserType:           constants.UserTypeEmployee,
+		Status:             models.UserStatusInactive,
+		DeactivatedAt:      null.TimeFrom(deactivatedAt),
+		DeactivatedBy:      null.StringFrom("admin_id"),
+		DeactivationReason: null.StringFrom("Left the company"),
+	}
+
+	object := toUserDetailObject(user, []string{models.RoleApprover})
+	assert.Equal(t, dto.UserDetailObject{
+		UserID:             "user_id",
+		UserName:           "Jane Doe",
+		UserType:           constants.UserTypeEmployee,
+		Status:             models.UserStatusInactive,
+		Roles:              []string{models.RoleApprover},
+		DeactivatedAt:      "2024-05-01T10:00:00Z",
+		DeactivatedBy:      "admin_id",
+		DeactivationReason: "Left the company",
+	}, object)
+}
@@ -0,0 +1,637 @@
+package user_service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	"github.com/nishanthrk/aspire-lms/app/configs"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// mfaChallengeTTL is how long the challenge of a sign in can be answered
+	mfaChallengeTTL = 5 * time.Minute
+
+	// mfaMaxAttempts is how many wrong codes are accepted for a challenge before the user has to sign in again, and
+	// how many wrong codes lock a signed in user out of the second factor settings
+	mfaMaxAttempts = 5
+
+	// mfaSkew is how many TOTP steps of clock drift are accepted before and after the current one
+	mfaSkew = 1
+
+	// mfaBackupCodeCount is how many backup codes are handed out when a second factor is enabled
+	mfaBackupCodeCount = 10
+
+	// mfaChallengeStatus is the status of a sign in answered with a challenge in place of the authentication tokens
+	mfaChallengeStatus = 3
+)
+
+// signIn finishes a sign in once the password is verified: users with a second factor, or required to have one,
+// get a challenge to answer with VerifyMfa, the others get their authentication tokens
+// Parameters:
+// - user: the user whose password was verified
+// - identifier: the identifier the user signed in with, wrong codes of the challenge count as its failed sign ins
+// Returns:
+// - dto.LoginResponse with the authentication tokens, or the challenge (status mfaChallengeStatus)
+// - error when the challenge or the session could not be stored
+func (s *userService) signIn(user models.User, identifier string) (response dto.LoginResponse, err error) {
+	mfa := models.UserMfa{}
+	mfa, err = mfa.FindByPrimaryKey(user.UserID)
+	if err != nil {
+		return
+	}
+
+	enrolled := mfa.Status == models.MfaStatusActive
+	if !enrolled && !mfaRequired(user.UserType) {
+		return s.GenerateAuth(user)
+	}
+	return issueMfaChallenge(user, identifier, !enrolled)
+}
+
+// VerifyMfa answers the challenge of a sign in with an authenticator code or an unused backup code and issues the
+// authentication tokens. When the sign in required an enrolment, the code confirms the new authenticator and the
+// response also carries the backup codes. Wrong codes are failed sign ins of the identifier and the IP address, so
+// signing in again with the password does not grant more guesses.
+// Parameters:
+// - request: dto.MfaVerifyRequest with the challenge token, a code and the IP address of the request
+// Returns:
+// - dto.LoginResponse with the authentication tokens, and the seconds to wait when throttled
+// - dto.HandleError with any error that occurred during the process, status -8 when the sign in is throttled
+func (s *userService) VerifyMfa(request dto.MfaVerifyRequest) (response dto.LoginResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	challenge, err := findMfaChallengeForUpdate(tx, request.ChallengeToken)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	// Refuse the code while the identifier or the IP address has to wait after failed sign ins
+	if wait := loginRetryAfter(challenge.Identifier, request.IPAddress); wait > 0 {
+		tx.Rollback()
+		response.RetryAfter, handle.Errors = loginThrottled(wait)
+		handle.Status = -8
+		return
+	}
+
+	mfa := models.UserMfa{}
+	mfa, err = mfa.FindByPrimaryKeyForUpdate(tx, challenge.UserID)
+	if err != nil || mfa.UserID == "" {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("two-factor authentication has to be set up first")
+		return
+	}
+	enrolling := mfa.Status != models.MfaStatusActive
+	if enrolling && request.Code == "" {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("enter a code of the new authenticator to finish setting it up")
+		return
+	}
+
+	valid, err := verifySecondFactor(tx, &mfa, request.Code, request.BackupCode)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+	if !valid {
+		// The failed attempt is kept so that codes cannot be guessed
+		challenge.Attempts++
+		if err = tx.Omit(clause.Associations).Save(&challenge).Error; err != nil {
+			tx.Rollback()
+		} else if err = tx.Commit().Error; err != nil {
+			tx.Rollback()
+		}
+		if err = recordLoginFailure(challenge.Identifier, request.IPAddress); err != nil {
+			logger.Sugar.Error("could not record failed sign in: ", err)
+		}
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("authentication code is incorrect")
+		return
+	}
+
+	var backupCodes []string
+	if enrolling {
+		backupCodes, err = activateMfa(tx, &mfa)
+	} else {
+		err = tx.Omit(clause.Associations).Save(&mfa).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	challenge.ConsumedAt = null.TimeFrom(time.Now())
+	if err = tx.Omit(clause.Associations).Save(&challenge).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+	clearLoginFailures(challenge.Identifier)
+
+	user := models.User{}
+	user, _ = user.FindByPrimaryKey(challenge.UserID)
+	response, err = s.GenerateAuth(user)
+	if err != nil {
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+	response.Data.BackupCodes = backupCodes
+	return
+}
+
+// EnrollMfa generates a new TOTP secret for the user to add to an authenticator app. The enrolment stays pending
+// until a code of the app confirms it, through ConfirmMfa when signed in or VerifyMfa when the sign in requires it.
+// Parameters:
+// - request: dto.MfaEnrollRequest with the challenge token when the user is not signed in
+// - user: the signed in user, empty when enrolling with a challenge
+// Returns:
+// - dto.MfaEnrollResponse with the secret and its otpauth:// URI
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) EnrollMfa(request dto.MfaEnrollRequest, user models.User) (
+	response dto.MfaEnrollResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	// Enrolling before the first sign in is allowed by the challenge of that sign in
+	if request.ChallengeToken != "" {
+		challenge, err := findMfaChallengeForUpdate(tx, request.ChallengeToken)
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -1
+			handle.Errors = err
+			return
+		}
+		user, _ = user.FindByPrimaryKey(challenge.UserID)
+	}
+	if user.UserID == "" {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("sign in to set up two-factor authentication")
+		return
+	}
+
+	mfa := models.UserMfa{}
+	mfa, err := mfa.FindByPrimaryKeyForUpdate(tx, user.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if mfa.Status == models.MfaStatusActive {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := utility.GenerateTOTPSecret()
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+	encrypted, err := utility.EncryptSecret(configs.GetConfig().MfaSecretKey, secret)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	// A pending enrolment is replaced, in case the previous secret never made it to the app
+	if mfa.UserID == "" {
+		mfa = models.UserMfa{UserID: user.UserID, CreatedAt: time.Now()}
+	}
+	mfa.Method = models.MfaMethodTotp
+	mfa.Secret = encrypted
+	mfa.Status = models.MfaStatusPending
+	mfa.LastUsedStep = 0
+	if err = tx.Omit(clause.Associations).Save(&mfa).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	account := user.UserEmail
+	if account == "" {
+		account = user.MobileNumber
+	}
+	response.Status = 1
+	response.Message = "Add the key to an authenticator app and confirm it with a code"
+	response.Data.Secret = secret
+	response.Data.OtpauthURI = utility.TOTPProvisioningURI(s.tenant, account, secret)
+	response.Data.Digits = utility.TOTPDigits
+	response.Data.Period = utility.TOTPPeriod
+	return
+}
+
+// ConfirmMfa enables the pending TOTP enrolment of a signed in user with a code of the authenticator app
+// Parameters:
+// - request: dto.MfaCodeRequest with the code
+// - user: the signed in user
+// Returns:
+// - dto.MfaBackupCodesResponse with the backup codes, shown only this once
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) ConfirmMfa(request dto.MfaCodeRequest, user models.User) (
+	response dto.MfaBackupCodesResponse, handle dto.HandleError) {
+	if wait := throttleRetryAfter(mfaThrottleKeys(user.UserID)); wait > 0 {
+		handle.Status = -5
+		response.RetryAfter, handle.Errors = mfaThrottled(wait)
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	mfa := models.UserMfa{}
+	mfa, err := mfa.FindByPrimaryKeyForUpdate(tx, user.UserID)
+	if err != nil || mfa.Status != models.MfaStatusPending {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("no two-factor authentication set up is pending")
+		return
+	}
+
+	valid, err := verifySecondFactor(tx, &mfa, request.Code, "")
+	if err != nil || !valid {
+		tx.Rollback()
+		if err == nil {
+			recordMfaFailure(user.UserID)
+		}
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("authentication code is incorrect")
+		return
+	}
+
+	response.Data.BackupCodes, err = activateMfa(tx, &mfa)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	clearThrottleFailures(mfaThrottleKeys(user.UserID))
+
+	response.Status = 1
+	response.Message = "Two-factor authentication enabled, keep the backup codes somewhere safe"
+	return
+}
+
+// RegenerateBackupCodes replaces the backup codes of a signed in user, confirmed with an authenticator code
+// Parameters:
+// - request: dto.MfaCodeRequest with the code
+// - user: the signed in user
+// Returns:
+// - dto.MfaBackupCodesResponse with the new backup codes, the previous ones no longer work
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) RegenerateBackupCodes(request dto.MfaCodeRequest, user models.User) (
+	response dto.MfaBackupCodesResponse, handle dto.HandleError) {
+	if wait := throttleRetryAfter(mfaThrottleKeys(user.UserID)); wait > 0 {
+		handle.Status = -5
+		response.RetryAfter, handle.Errors = mfaThrottled(wait)
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	mfa := models.UserMfa{}
+	mfa, err := mfa.FindByPrimaryKeyForUpdate(tx, user.UserID)
+	if err != nil || mfa.Status != models.MfaStatusActive {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("two-factor authentication is not enabled")
+		return
+	}
+
+	valid, err := verifySecondFactor(tx, &mfa, request.Code, "")
+	if err != nil || !valid {
+		tx.Rollback()
+		if err == nil {
+			recordMfaFailure(user.UserID)
+		}
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("authentication code is incorrect")
+		return
+	}
+
+	if err = tx.Omit(clause.Associations).Save(&mfa).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+	response.Data.BackupCodes, err = issueBackupCodes(tx, user.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	clearThrottleFailures(mfaThrottleKeys(user.UserID))
+
+	response.Status = 1
+	response.Message = "Backup codes replaced, the previous ones no longer work"
+	return
+}
+
+// DisableMfa removes the second factor of a signed in user, confirmed with an authenticator code. Users whose type
+// is required to have one by MFA_REQUIRED_USER_TYPES cannot remove it.
+// Parameters:
+// - request: dto.MfaCodeRequest with the code
+// - user: the signed in user
+// Returns:
+// - dto.MfaResponse confirming the change
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) DisableMfa(request dto.MfaCodeRequest, user models.User) (
+	response dto.MfaResponse, handle dto.HandleError) {
+	if mfaRequired(user.UserType) {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("two-factor authentication is required for your account")
+		return
+	}
+
+	if wait := throttleRetryAfter(mfaThrottleKeys(user.UserID)); wait > 0 {
+		handle.Status = -6
+		response.RetryAfter, handle.Errors = mfaThrottled(wait)
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	mfa := models.UserMfa{}
+	mfa, err := mfa.FindByPrimaryKeyForUpdate(tx, user.UserID)
+	if err != nil || mfa.Status != models.MfaStatusActive {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("two-factor authentication is not enabled")
+		return
+	}
+
+	valid, err := verifySecondFactor(tx, &mfa, request.Code, "")
+	if err != nil || !valid {
+		tx.Rollback()
+		if err == nil {
+			recordMfaFailure(user.UserID)
+		}
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("authentication code is incorrect")
+		return
+	}
+
+	backupCode := models.MfaBackupCode{}
+	if err = backupCode.DeleteByUser(tx, user.UserID); err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+	if err = tx.Delete(&mfa).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	clearThrottleFailures(mfaThrottleKeys(user.UserID))
+
+	response.Status = 1
+	response.Message = "Two-factor authentication disabled"
+	return
+}
+
+// mfaThrottleKeys returns the subject wrong codes of a signed in user are counted for. They are counted per user,
+// a stolen access token cannot spread its guesses over several sessions.
+func mfaThrottleKeys(userId string) []loginThrottleKey {
+	return []loginThrottleKey{{models.LoginThrottleMfa, userId, mfaMaxAttempts}}
+}
+
+// recordMfaFailure counts a wrong code of a signed in user, locking the user out of the second factor settings once
+// there are mfaMaxAttempts of them
+func recordMfaFailure(userId string) {
+	if err := recordThrottleFailure(mfaThrottleKeys(userId)); err != nil {
+		logger.Sugar.Error("could not record wrong authentication code: ", err)
+	}
+}
+
+// mfaThrottled describes a code check refused because of earlier wrong codes
+// Parameters:
+// - wait: how long until the next attempt
+// Returns:
+// - int64 with the wait in whole seconds, rounded up, for the Retry-After header
+// - error describing the refusal
+func mfaThrottled(wait time.Duration) (int64, error) {
+	seconds := retryAfterSeconds(wait)
+	return seconds, fmt.Errorf("too many wrong authentication codes, try again in %d seconds", seconds)
+}
+
+// mfaRequired reports whether users of a type have to sign in with a second factor
+func mfaRequired(userType string) bool {
+	for _, required := range configs.GetConfig().GetMfaRequiredUserTypes() {
+		if required == userType {
+			return true
+		}
+	}
+	return false
+}
+
+// issueMfaChallenge stores a single use challenge for a user whose password was verified
+// Parameters:
+// - user: the user signing in
+// - identifier: the identifier the user signed in with
+// - enrollment: whether the user has to set up an authenticator before answering the challenge
+// Returns:
+// - dto.LoginResponse with the challenge token in place of the authentication tokens
+// - error when the challenge could not be stored
+func issueMfaChallenge(user models.User, identifier string, enrollment bool) (response dto.LoginResponse, err error) {
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return
+	}
+	token := hex.EncodeToString(secret)
+
+	challenge := models.MfaChallenge{
+		ChallengeID: uuid.New().String(),
+		UserID:      user.UserID,
+		Identifier:  identifier,
+		TokenHash:   hashToken(token),
+		ExpiresAt:   time.Now().Add(mfaChallengeTTL),
+		CreatedAt:   time.Now(),
+	}
+	if err = db.MysqlDB.Create(&challenge).Error; err != nil {
+		return
+	}
+
+	response.Status = mfaChallengeStatus
+	response.Message = "Enter the code of your authenticator app"
+	if enrollment {
+		response.Message = "Two-factor authentication has to be set up before signing in"
+	}
+	response.Data = dto.TokenDetail{
+		MfaChallengeToken:     token,
+		MfaEnrollmentRequired: enrollment,
+		UserID:                user.UserID,
+		UserType:              user.UserType,
+		TokenExpires:          challenge.ExpiresAt.Unix(),
+	}
+	return
+}
+
+// findMfaChallengeForUpdate finds and locks the challenge of a token, refusing it once it is answered, expired or
+// spent by wrong codes
+func findMfaChallengeForUpdate(tx *gorm.DB, token string) (challenge models.MfaChallenge, err error) {
+	challenge, err = challenge.FindByTokenHashForUpdate(tx, hashToken(token))
+	if err != nil || challenge.ChallengeID == "" || challenge.ConsumedAt.Valid ||
+		time.Now().After(challenge.ExpiresAt) {
+		err = fmt.Errorf("challenge is invalid or has expired, sign in again")
+		return
+	}
+	if challenge.Attempts >= mfaMaxAttempts {
+		err = fmt.Errorf("too many wrong codes, sign in again")
+	}
+	return
+}
+
+// verifySecondFactor checks an authenticator code, or a backup code when one is given, for a user. A TOTP code is
+// accepted once: its step is kept on the enrolment for the caller to save. A backup code is spent in the caller's
+// transaction.
+// Parameters:
+// - tx: the caller's transaction
+// - mfa: the enrolment of the user, locked by the caller
+// - code: the authenticator code
+// - backupCode: a backup code, used in place of the authenticator code
+// Returns:
+// - bool reporting whether the code is valid
+// - error when the secret or the backup code could not be read or saved
+func verifySecondFactor(tx *gorm.DB, mfa *models.UserMfa, code string, backupCode string) (bool, error) {
+	if backupCode != "" {
+		if mfa.Status != models.MfaStatusActive {
+			return false, nil
+		}
+		stored := models.MfaBackupCode{}
+		stored, err := stored.FindUnusedForUpdate(tx, mfa.UserID, hashBackupCode(mfa.UserID, backupCode))
+		if err != nil || stored.BackupCodeID == "" {
+			return false, err
+		}
+		stored.UsedAt = null.TimeFrom(time.Now())
+		return true, tx.Omit(clause.Associations).Save(&stored).Error
+	}
+
+	secret, err := utility.DecryptSecret(configs.GetConfig().MfaSecretKey, mfa.Secret)
+	if err != nil {
+		return false, err
+	}
+	step, valid, err := utility.ValidateTOTP(secret, code, time.Now(), mfaSkew)
+	if err != nil || !valid || step <= mfa.LastUsedStep {
+		return false, err
+	}
+	mfa.LastUsedStep = step
+	return true, nil
+}
+
+// activateMfa enables a confirmed enrolment and hands out its first backup codes
+func activateMfa(tx *gorm.DB, mfa *models.UserMfa) (backupCodes []string, err error) {
+	mfa.Status = models.MfaStatusActive
+	mfa.EnabledAt = null.TimeFrom(time.Now())
+	if err = tx.Omit(clause.Associations).Save(mfa).Error; err != nil {
+		return
+	}
+	return issueBackupCodes(tx, mfa.UserID)
+}
+
+// issueBackupCodes replaces the backup codes of a user with mfaBackupCodeCount new ones. Only their hashes are
+// kept, the codes are returned to be shown to the user once.
+func issueBackupCodes(tx *gorm.DB, userId string) (backupCodes []string, err error) {
+	stored := models.MfaBackupCode{}
+	if err = stored.DeleteByUser(tx, userId); err != nil {
+		return
+	}
+
+	for i := 0; i < mfaBackupCodeCount; i++ {
+		random := make([]byte, 5)
+		if _, err = rand.Read(random); err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(random)
+		code = code[:5] + "-" + code[5:]
+
+		stored = models.MfaBackupCode{
+			BackupCodeID: uuid.New().String(),
+			UserID:       userId,
+			CodeHash:     hashBackupCode(userId, code),
+			CreatedAt:    time.Now(),
+		}
+		if err = tx.Create(&stored).Error; err != nil {
+			return nil, err
+		}
+		backupCodes = append(backupCodes, code)
+	}
+	return
+}
+
+// hashBackupCode returns the SHA-256 hex digest a backup code is stored as, salted with its user. Codes are
+// compared without their dash and case, as users type them.
+func hashBackupCode(userId string, code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hash := sha256.Sum256([]byte(userId + ":" + normalized))
+	return hex.EncodeToString(hash[:])
+}
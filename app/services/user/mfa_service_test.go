@@ -0,0 +1,65 @@
+package user_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// setMfaConfig sets the configuration the second factor needs, GetConfig refuses a non numeric DB_PORT
+func setMfaConfig(t *testing.T, requiredUserTypes string) {
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("MFA_SECRET_KEY", "mfa-secret-key")
+	t.Setenv("MFA_REQUIRED_USER_TYPES", requiredUserTypes)
+}
+
+func TestMfaRequired(t *testing.T) {
+	// Employees by default
+	setMfaConfig(t, "")
+	assert.True(t, mfaRequired("EMPLOYEE"))
+	assert.False(t, mfaRequired("CUSTOMER"))
+
+	setMfaConfig(t, "employee, CUSTOMER")
+	assert.True(t, mfaRequired("CUSTOMER"))
+
+	setMfaConfig(t, "NONE")
+	assert.False(t, mfaRequired("EMPLOYEE"))
+}
+
+func TestVerifySecondFactor_TOTP(t *testing.T) {
+	setMfaConfig(t, "")
+
+	secret, err := utility.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	encrypted, err := utility.EncryptSecret("mfa-secret-key", secret)
+	assert.NoError(t, err)
+	mfa := models.UserMfa{UserID: "user_id", Secret: encrypted, Status: models.MfaStatusActive}
+
+	step := utility.TOTPStep(time.Now())
+	code, err := utility.TOTPCode(secret, step)
+	assert.NoError(t, err)
+
+	valid, err := verifySecondFactor(nil, &mfa, code, "")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, step, mfa.LastUsedStep)
+
+	// A code is accepted once
+	valid, err = verifySecondFactor(nil, &mfa, code, "")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// A secret encrypted under another key is an error, not a wrong code
+	mfa.Secret, _ = utility.EncryptSecret("other-key", secret)
+	_, err = verifySecondFactor(nil, &mfa, code, "")
+	assert.Error(t, err)
+}
+
+func TestHashBackupCode(t *testing.T) {
+	// Backup codes are compared without their dash and case
+	assert.Equal(t, hashBackupCode("user_id", "ab12c-3d4e5"), hashBackupCode("user_id", " AB12C3D4E5 "))
+	assert.NotEqual(t, hashBackupCode("user_id", "ab12c-3d4e5"), hashBackupCode("other_user_id", "ab12c-3d4e5"))
+}
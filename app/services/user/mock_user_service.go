@@ -51,6 +51,111 @@ func (mr *MockUserServiceMockRecorder) AllocateEmployeeForProcess(application in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocateEmployeeForProcess", reflect.TypeOf((*MockUserService)(nil).AllocateEmployeeForProcess), application)
 }
 
+// AssignRoles mocks base method.
+func (m *MockUserService) AssignRoles(request dto.UserRolesRequest, admin models.User) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoles", request, admin)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// AssignRoles indicates an expected call of AssignRoles.
+func (mr *MockUserServiceMockRecorder) AssignRoles(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoles", reflect.TypeOf((*MockUserService)(nil).AssignRoles), request, admin)
+}
+
+// ConfirmMfa mocks base method.
+func (m *MockUserService) ConfirmMfa(request dto.MfaCodeRequest, user models.User) (dto.MfaBackupCodesResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMfa", request, user)
+	ret0, _ := ret[0].(dto.MfaBackupCodesResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ConfirmMfa indicates an expected call of ConfirmMfa.
+func (mr *MockUserServiceMockRecorder) ConfirmMfa(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMfa", reflect.TypeOf((*MockUserService)(nil).ConfirmMfa), request, user)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserService) CreateUser(request dto.UserCreateRequest, admin models.User) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", request, admin)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserServiceMockRecorder) CreateUser(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), request, admin)
+}
+
+// DeactivateUser mocks base method.
+func (m *MockUserService) DeactivateUser(request dto.UserStatusRequest, admin models.User) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUser", request, admin)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// DeactivateUser indicates an expected call of DeactivateUser.
+func (mr *MockUserServiceMockRecorder) DeactivateUser(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUser", reflect.TypeOf((*MockUserService)(nil).DeactivateUser), request, admin)
+}
+
+// DisableMfa mocks base method.
+func (m *MockUserService) DisableMfa(request dto.MfaCodeRequest, user models.User) (dto.MfaResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMfa", request, user)
+	ret0, _ := ret[0].(dto.MfaResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// DisableMfa indicates an expected call of DisableMfa.
+func (mr *MockUserServiceMockRecorder) DisableMfa(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMfa", reflect.TypeOf((*MockUserService)(nil).DisableMfa), request, user)
+}
+
+// EnrollMfa mocks base method.
+func (m *MockUserService) EnrollMfa(request dto.MfaEnrollRequest, user models.User) (dto.MfaEnrollResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollMfa", request, user)
+	ret0, _ := ret[0].(dto.MfaEnrollResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// EnrollMfa indicates an expected call of EnrollMfa.
+func (mr *MockUserServiceMockRecorder) EnrollMfa(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollMfa", reflect.TypeOf((*MockUserService)(nil).EnrollMfa), request, user)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockUserService) ForgotPassword(request dto.ForgotPasswordRequest) (dto.PasswordResetResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", request)
+	ret0, _ := ret[0].(dto.PasswordResetResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserServiceMockRecorder) ForgotPassword(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserService)(nil).ForgotPassword), request)
+}
+
 // GenerateAuth mocks base method.
 func (m *MockUserService) GenerateAuth(users models.User) (dto.LoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -66,18 +171,33 @@ func (mr *MockUserServiceMockRecorder) GenerateAuth(users interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAuth", reflect.TypeOf((*MockUserService)(nil).GenerateAuth), users)
 }
 
-// GetApplicationUser mocks base method.
-func (m *MockUserService) GetApplicationUser(object dto.UserObject) models.User {
+// GetSessionID mocks base method.
+func (m *MockUserService) GetSessionID(c *fiber.Ctx) string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApplicationUser", object)
-	ret0, _ := ret[0].(models.User)
+	ret := m.ctrl.Call(m, "GetSessionID", c)
+	ret0, _ := ret[0].(string)
 	return ret0
 }
 
-// GetApplicationUser indicates an expected call of GetApplicationUser.
-func (mr *MockUserServiceMockRecorder) GetApplicationUser(object interface{}) *gomock.Call {
+// GetSessionID indicates an expected call of GetSessionID.
+func (mr *MockUserServiceMockRecorder) GetSessionID(c interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationUser", reflect.TypeOf((*MockUserService)(nil).GetApplicationUser), object)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionID", reflect.TypeOf((*MockUserService)(nil).GetSessionID), c)
+}
+
+// GetUser mocks base method.
+func (m *MockUserService) GetUser(userId string) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", userId)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserServiceMockRecorder) GetUser(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserService)(nil).GetUser), userId)
 }
 
 // GetUserObject mocks base method.
@@ -94,6 +214,171 @@ func (mr *MockUserServiceMockRecorder) GetUserObject(c interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserObject", reflect.TypeOf((*MockUserService)(nil).GetUserObject), c)
 }
 
+// GetUsers mocks base method.
+func (m *MockUserService) GetUsers(request dto.UserListRequest) (dto.UserListResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", request)
+	ret0, _ := ret[0].(dto.UserListResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserServiceMockRecorder) GetUsers(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserService)(nil).GetUsers), request)
+}
+
+// Logout mocks base method.
+func (m *MockUserService) Logout(request dto.LogoutRequest, user models.User) (dto.LogoutResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", request, user)
+	ret0, _ := ret[0].(dto.LogoutResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserServiceMockRecorder) Logout(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserService)(nil).Logout), request, user)
+}
+
+// ReactivateUser mocks base method.
+func (m *MockUserService) ReactivateUser(request dto.UserStatusRequest, admin models.User) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReactivateUser", request, admin)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ReactivateUser indicates an expected call of ReactivateUser.
+func (mr *MockUserServiceMockRecorder) ReactivateUser(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactivateUser", reflect.TypeOf((*MockUserService)(nil).ReactivateUser), request, admin)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserService) RefreshToken(request dto.RefreshTokenRequest) (dto.LoginResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", request)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserServiceMockRecorder) RefreshToken(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserService)(nil).RefreshToken), request)
+}
+
+// RegenerateBackupCodes mocks base method.
+func (m *MockUserService) RegenerateBackupCodes(request dto.MfaCodeRequest, user models.User) (dto.MfaBackupCodesResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateBackupCodes", request, user)
+	ret0, _ := ret[0].(dto.MfaBackupCodesResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// RegenerateBackupCodes indicates an expected call of RegenerateBackupCodes.
+func (mr *MockUserServiceMockRecorder) RegenerateBackupCodes(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateBackupCodes", reflect.TypeOf((*MockUserService)(nil).RegenerateBackupCodes), request, user)
+}
+
+// Register mocks base method.
+func (m *MockUserService) Register(request dto.RegistrationRequest) (dto.RegistrationResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", request)
+	ret0, _ := ret[0].(dto.RegistrationResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockUserServiceMockRecorder) Register(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), request)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserService) ResetPassword(request dto.ResetPasswordRequest) (dto.PasswordResetResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", request)
+	ret0, _ := ret[0].(dto.PasswordResetResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserServiceMockRecorder) ResetPassword(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserService)(nil).ResetPassword), request)
+}
+
+// ResetUserPassword mocks base method.
+func (m *MockUserService) ResetUserPassword(request dto.UserPasswordResetRequest, admin models.User) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetUserPassword", request, admin)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// ResetUserPassword indicates an expected call of ResetUserPassword.
+func (mr *MockUserServiceMockRecorder) ResetUserPassword(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetUserPassword", reflect.TypeOf((*MockUserService)(nil).ResetUserPassword), request, admin)
+}
+
+// SetPassword mocks base method.
+func (m *MockUserService) SetPassword(request dto.SetPasswordRequest) (dto.LoginResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", request)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockUserServiceMockRecorder) SetPassword(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockUserService)(nil).SetPassword), request)
+}
+
+// UnlockLogin mocks base method.
+func (m *MockUserService) UnlockLogin(request dto.UnlockLoginRequest, admin models.User) (dto.UnlockLoginResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockLogin", request, admin)
+	ret0, _ := ret[0].(dto.UnlockLoginResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// UnlockLogin indicates an expected call of UnlockLogin.
+func (mr *MockUserServiceMockRecorder) UnlockLogin(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockLogin", reflect.TypeOf((*MockUserService)(nil).UnlockLogin), request, admin)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserService) UpdateUser(request dto.UserUpdateRequest, admin models.User) (dto.UserDetailResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", request, admin)
+	ret0, _ := ret[0].(dto.UserDetailResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserServiceMockRecorder) UpdateUser(request, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), request, admin)
+}
+
 // ValidateCredentials mocks base method.
 func (m *MockUserService) ValidateCredentials(request dto.LoginRequest) (dto.LoginResponse, dto.HandleError) {
 	m.ctrl.T.Helper()
@@ -108,3 +393,33 @@ func (mr *MockUserServiceMockRecorder) ValidateCredentials(request interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCredentials", reflect.TypeOf((*MockUserService)(nil).ValidateCredentials), request)
 }
+
+// VerifyMfa mocks base method.
+func (m *MockUserService) VerifyMfa(request dto.MfaVerifyRequest) (dto.LoginResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMfa", request)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// VerifyMfa indicates an expected call of VerifyMfa.
+func (mr *MockUserServiceMockRecorder) VerifyMfa(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMfa", reflect.TypeOf((*MockUserService)(nil).VerifyMfa), request)
+}
+
+// VerifyRegistration mocks base method.
+func (m *MockUserService) VerifyRegistration(request dto.RegistrationVerifyRequest) (dto.LoginResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyRegistration", request)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// VerifyRegistration indicates an expected call of VerifyRegistration.
+func (mr *MockUserServiceMockRecorder) VerifyRegistration(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyRegistration", reflect.TypeOf((*MockUserService)(nil).VerifyRegistration), request)
+}
@@ -0,0 +1,167 @@
+package user_service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// otpDigits is the length of a one-time password
+	otpDigits = 6
+
+	// otpTTL is how long a one-time password can be used
+	otpTTL = 10 * time.Minute
+
+	// otpMaxAttempts is how many wrong codes are accepted before a one-time password is spent
+	otpMaxAttempts = 5
+
+	// otpResendInterval is how long to wait before another one-time password is sent for the same reference
+	otpResendInterval = time.Minute
+)
+
+// errOtpTooSoon refuses a new one-time password while the previous one was sent less than otpResendInterval ago
+var errOtpTooSoon = fmt.Errorf("a verification code was sent less than %v ago, wait before requesting another",
+	otpResendInterval)
+
+// otpResult is the outcome of checking a one-time password
+type otpResult int
+
+const (
+	otpValid otpResult = iota
+	otpIncorrect
+	otpExpired
+	otpExhausted
+	otpMissing
+)
+
+// issueOTP stores a new one-time password for a purpose and reference and sends it to the recipient. A new code
+// replaces the previous one, but only once otpResendInterval has passed since it was sent.
+// Parameters:
+// - tx: the caller's transaction
+// - notifier: delivers the code
+// - message: the notification to send, its body is formatted with the code
+// - referenceId: what the code verifies, e.g. the registration
+// Returns:
+// - models.OneTimePassword that was stored
+// - error when a code was sent too recently or could not be stored or sent
+func issueOTP(tx *gorm.DB, notifier notificationService.Notifier, message dto.Notification, referenceId string) (
+	otp models.OneTimePassword, err error) {
+	previous := models.OneTimePassword{}
+	previous, err = previous.FindLatestForUpdate(tx, message.Purpose, referenceId)
+	if err != nil {
+		return
+	}
+	if previous.OtpID != "" && !previous.ConsumedAt.Valid && time.Since(previous.CreatedAt) < otpResendInterval {
+		err = errOtpTooSoon
+		return
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	otp = models.OneTimePassword{
+		OtpID:       uuid.New().String(),
+		Purpose:     message.Purpose,
+		ReferenceID: referenceId,
+		Channel:     message.Channel,
+		Recipient:   message.Recipient,
+		ExpiresAt:   now.Add(otpTTL),
+		CreatedAt:   now,
+	}
+	otp.CodeHash = hashOTP(otp.OtpID, code)
+	if err = tx.Create(&otp).Error; err != nil {
+		return
+	}
+
+	message.NotificationID = otp.OtpID
+	message.Body = fmt.Sprintf(message.Body, code, int(otpTTL.Minutes()))
+	message.CreatedAt = now.Format(time.RFC3339)
+	err = notifier.Send(message)
+	return
+}
+
+// checkOTP checks a code against the latest one-time password of a purpose and reference. A wrong code counts as
+// an attempt and a right one spends the password, both are saved in the caller's transaction.
+// Parameters:
+// - tx: the caller's transaction, to be committed whatever the result
+// - purpose: what the code is used for
+// - referenceId: what the code verifies
+// - code: the code entered by the user
+// Returns:
+// - otpResult of the check
+// - error when the password could not be read or saved
+func checkOTP(tx *gorm.DB, purpose string, referenceId string, code string) (result otpResult, err error) {
+	otp := models.OneTimePassword{}
+	otp, err = otp.FindLatestForUpdate(tx, purpose, referenceId)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case otp.OtpID == "" || otp.ConsumedAt.Valid:
+		return otpMissing, nil
+	case otp.Attempts >= otpMaxAttempts:
+		return otpExhausted, nil
+	case time.Now().After(otp.ExpiresAt):
+		return otpExpired, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(hashOTP(otp.OtpID, code))) != 1 {
+		otp.Attempts++
+		result = otpIncorrect
+		if otp.Attempts >= otpMaxAttempts {
+			result = otpExhausted
+		}
+		err = tx.Omit(clause.Associations).Save(&otp).Error
+		return
+	}
+
+	otp.ConsumedAt = null.TimeFrom(time.Now())
+	err = tx.Omit(clause.Associations).Save(&otp).Error
+	return otpValid, err
+}
+
+// otpError describes a failed check of a one-time password to the user
+func otpError(result otpResult) error {
+	switch result {
+	case otpIncorrect:
+		return fmt.Errorf("verification code is incorrect")
+	case otpExpired:
+		return fmt.Errorf("verification code has expired, request a new one")
+	case otpExhausted:
+		return fmt.Errorf("verification code was entered wrongly too many times, request a new one")
+	}
+	return fmt.Errorf("no verification code is pending, request a new one")
+}
+
+// generateOTP returns a random numeric code of otpDigits digits
+func generateOTP() (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(otpDigits), nil)
+	number, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpDigits, number), nil
+}
+
+// hashOTP returns the SHA-256 hex digest a code is stored as, salted with the ID of its one-time password
+func hashOTP(otpId string, code string) string {
+	hash := sha256.Sum256([]byte(otpId + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
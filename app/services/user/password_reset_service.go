@@ -0,0 +1,298 @@
+package user_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
+	"gorm.io/gorm"
+)
+
+const (
+	// passwordResetWindow is the period the reset requests and failed resets of an identifier are counted over
+	passwordResetWindow = time.Hour
+
+	// passwordResetMaxRequests is how many reset codes can be asked for an identifier within passwordResetWindow
+	passwordResetMaxRequests = 5
+
+	// passwordResetMaxFailures is how many wrong reset codes are accepted for an identifier within
+	// passwordResetWindow, on top of the attempts allowed for each code
+	passwordResetMaxFailures = 10
+)
+
+// passwordResetMessage is the notification carrying a password reset code, formatted with the code and its lifetime
+const passwordResetMessage = "Your %v password reset code is %%v. It expires in %%d minutes. If you did not ask to " +
+	"reset your password, ignore this message."
+
+// passwordResetSent is answered to every accepted reset request, whether an account matches the identifier or not
+const passwordResetSent = "If an account matches, a password reset code has been sent to it"
+
+// errPasswordResetLimited refuses a reset request or attempt once the identifier reached its limit
+var errPasswordResetLimited = fmt.Errorf("too many password reset attempts, try again later")
+
+// ForgotPassword sends a password reset code to the user with an email or mobile number. The response is the same
+// whether an account matches or not, or the code could not be delivered, so that it cannot be used to find out who
+// has one. Every request and failed delivery is written to the password reset audit log.
+// Parameters:
+// - request: dto.ForgotPasswordRequest with the identifier and the channel to send the code on
+// Returns:
+// - dto.PasswordResetResponse acknowledging the request
+// - dto.HandleError with any error that occurred during the process, status -1 when the identifier is rate limited
+func (s *userService) ForgotPassword(request dto.ForgotPasswordRequest) (
+	response dto.PasswordResetResponse, handle dto.HandleError) {
+	identifier := strings.TrimSpace(request.Identifier)
+	audit := newPasswordResetEvent(identifier, request.IPAddress)
+
+	tx := db.MysqlDB.Begin()
+
+	limited, err := passwordResetLimited(tx, audit, models.PasswordResetRequested, passwordResetMaxRequests)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if limited {
+		commitPasswordReset(tx)
+		handle.Status = -1
+		handle.Errors = errPasswordResetLimited
+		return
+	}
+
+	user := findUserByIdentifier(request.Platform, identifier)
+	if user.UserID != "" {
+		audit.UserID = null.StringFrom(user.UserID)
+	}
+	if err = recordPasswordReset(tx, audit, models.PasswordResetRequested, ""); err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	// Deactivated users are answered as usual but sent no code
+	if user.UserID != "" && user.Status != models.UserStatusInactive {
+		channel := passwordResetChannel(user, identifier, request.Channel)
+		message := dto.Notification{
+			Channel:   channel,
+			Recipient: user.UserEmail,
+			Subject:   fmt.Sprintf("%v password reset", s.tenant),
+			Body:      fmt.Sprintf(passwordResetMessage, s.tenant),
+			Purpose:   models.OtpPurposePasswordReset,
+		}
+		if channel == notificationService.ChannelSMS {
+			message.Recipient = user.MobileNumber
+		}
+		audit.Channel = null.StringFrom(channel)
+
+		otp, err := issueOTP(tx, s.notifier, message, user.UserID)
+		switch {
+		case err == errOtpTooSoon:
+			// The pending code stays valid, answering as usual keeps the cooldown from revealing the account
+			err = recordPasswordReset(tx, audit, models.PasswordResetRateLimited, err.Error())
+		case err != nil:
+			// Answering as usual keeps a failed delivery from revealing the account. The unsent code is dropped so
+			// that asking again is not held back by the resend interval.
+			logger.Sugar.Error("could not send password reset code to user ", user.UserID, ": ", err)
+			detail := err.Error()
+			err = nil
+			if otp.OtpID != "" {
+				err = tx.Delete(&otp).Error
+			}
+			if err == nil {
+				err = recordPasswordReset(tx, audit, models.PasswordResetSendFailed, detail)
+			}
+		default:
+			err = recordPasswordReset(tx, audit, models.PasswordResetCodeSent, "")
+		}
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = err
+			return
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = passwordResetSent
+	return
+}
+
+// ResetPassword sets a new password with the reset code sent by ForgotPassword and signs out every session of the
+// user. Wrong codes are refused the same way whether an account matches the identifier or not, and every attempt is
+// written to the password reset audit log.
+// Parameters:
+// - request: dto.ResetPasswordRequest with the identifier, the code and the new password
+// Returns:
+// - dto.PasswordResetResponse confirming the reset, the user then signs in with the new password
+// - dto.HandleError with any error that occurred during the process, status -1 when the identifier is rate limited
+func (s *userService) ResetPassword(request dto.ResetPasswordRequest) (
+	response dto.PasswordResetResponse, handle dto.HandleError) {
+	identifier := strings.TrimSpace(request.Identifier)
+	audit := newPasswordResetEvent(identifier, request.IPAddress)
+
+	tx := db.MysqlDB.Begin()
+
+	limited, err := passwordResetLimited(tx, audit, models.PasswordResetFailed, passwordResetMaxFailures)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if limited {
+		commitPasswordReset(tx)
+		handle.Status = -1
+		handle.Errors = errPasswordResetLimited
+		return
+	}
+
+	// Without a matching account there is no code to check, which is refused like any other missing code
+	user := findUserByIdentifier(request.Platform, identifier)
+	if user.UserID != "" {
+		audit.UserID = null.StringFrom(user.UserID)
+		user, err = user.FindOneByConditionForUpdate(tx, []db.WhereCondition{{
+			Key:       models.UsersColumns.UserID,
+			Condition: "=",
+			Value:     user.UserID,
+		}})
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = err
+			return
+		}
+	}
+
+	result, err := checkOTP(tx, models.OtpPurposePasswordReset, user.UserID, request.Code)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+	if result != otpValid {
+		// The failed attempt is kept so that codes cannot be guessed
+		if err = recordPasswordReset(tx, audit, models.PasswordResetFailed, otpError(result).Error()); err != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = err
+			return
+		}
+		commitPasswordReset(tx)
+		handle.Status = -3
+		handle.Errors = otpError(result)
+		return
+	}
+
+	password, err := utility.HashPassword(request.Password)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if err = savePassword(tx, &user, password, models.SessionRevokedPasswordReset); err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	if err = recordPasswordReset(tx, audit, models.PasswordResetCompleted, ""); err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Password reset successfully, sign in with the new password"
+	return
+}
+
+// newPasswordResetEvent starts an audit log entry for a reset request or attempt on an identifier
+func newPasswordResetEvent(identifier string, ipAddress string) models.PasswordResetEvent {
+	return models.PasswordResetEvent{
+		Identifier: strings.ToLower(identifier),
+		IPAddress:  null.NewString(ipAddress, ipAddress != ""),
+	}
+}
+
+// recordPasswordReset writes an event of a reset request or attempt to the audit log
+// Parameters:
+// - tx: the caller's transaction
+// - audit: the entry started by newPasswordResetEvent
+// - event: what happened
+// - detail: why a request or attempt was refused, empty otherwise
+// Returns:
+// - error when the entry could not be stored
+func recordPasswordReset(tx *gorm.DB, audit models.PasswordResetEvent, event string, detail string) error {
+	audit.EventID = uuid.New().String()
+	audit.Event = event
+	audit.Detail = null.NewString(detail, detail != "")
+	audit.CreatedAt = time.Now()
+	return tx.Create(&audit).Error
+}
+
+// passwordResetLimited checks whether an identifier reached the limit of an event within passwordResetWindow, and
+// records the refusal in the audit log when it did
+// Parameters:
+// - tx: the caller's transaction
+// - audit: the entry started by newPasswordResetEvent
+// - counted: the event that is limited
+// - limit: how many of them are allowed
+// Returns:
+// - bool reporting whether the identifier is rate limited
+// - error when the audit log could not be read or written
+func passwordResetLimited(tx *gorm.DB, audit models.PasswordResetEvent, counted string, limit int64) (bool, error) {
+	count, err := audit.CountSince(tx, audit.Identifier, counted, time.Now().Add(-passwordResetWindow))
+	if err != nil || count < limit {
+		return false, err
+	}
+	return true, recordPasswordReset(tx, audit, models.PasswordResetRateLimited,
+		fmt.Sprintf("%d %v events within %v", count, strings.ToLower(counted), passwordResetWindow))
+}
+
+// commitPasswordReset keeps the audit log of a refused request or attempt, the refusal is answered either way
+func commitPasswordReset(tx *gorm.DB) {
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		logger.Sugar.Error("could not record password reset event: ", err)
+	}
+}
+
+// passwordResetChannel returns the channel a reset code is sent on: the one asked for, otherwise SMS when the user
+// identified with their mobile number and email when they used their email
+func passwordResetChannel(user models.User, identifier string, channel string) string {
+	if channel != "" {
+		return channel
+	}
+	if identifier == user.MobileNumber {
+		return notificationService.ChannelSMS
+	}
+	return notificationService.ChannelEmail
+}
@@ -0,0 +1,27 @@
+package user_service
+
+import (
+	"testing"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetChannel(t *testing.T) {
+	user := models.User{UserEmail: "john.doe@example.com", MobileNumber: "1234567890"}
+
+	// The code goes back to where the user identified from, unless they ask otherwise
+	assert.Equal(t, "SMS", passwordResetChannel(user, "1234567890", ""))
+	assert.Equal(t, "EMAIL", passwordResetChannel(user, "john.doe@example.com", ""))
+	assert.Equal(t, "EMAIL", passwordResetChannel(user, "1234567890", "EMAIL"))
+}
+
+func TestNewPasswordResetEvent(t *testing.T) {
+	// Identifiers are counted case insensitively, like emails are matched
+	audit := newPasswordResetEvent("John.Doe@Example.com", "10.0.0.1")
+	assert.Equal(t, "john.doe@example.com", audit.Identifier)
+	assert.Equal(t, "10.0.0.1", audit.IPAddress.String)
+
+	audit = newPasswordResetEvent("1234567890", "")
+	assert.False(t, audit.IPAddress.Valid)
+}
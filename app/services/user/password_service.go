@@ -0,0 +1,166 @@
+package user_service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// passwordSetupTTL is how long a password setup token can be used after signing in with a temporary password, or
+// after verifying a registration
+const passwordSetupTTL = 15 * time.Minute
+
+// SetPassword replaces a temporary password with one chosen by the user and signs them in. The setup token is
+// handed out by ValidateCredentials when the account has to change its password, or by VerifyRegistration, and can
+// be used once.
+// Parameters:
+// - request: dto.SetPasswordRequest with the setup token and the new password
+// Returns:
+// - dto.LoginResponse with the authentication tokens, or the MFA challenge when a second factor is needed
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) SetPassword(request dto.SetPasswordRequest) (response dto.LoginResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	// Lock the user so that the token is used only once
+	var userCondition []db.WhereCondition
+	userCondition = append(userCondition, db.WhereCondition{
+		Key:       models.UsersColumns.PasswordSetupToken,
+		Condition: "=",
+		Value:     hashToken(request.SetupToken),
+	})
+
+	user := models.User{}
+	user, err := user.FindOneByConditionForUpdate(tx, userCondition)
+	if err != nil || user.UserID == "" || !user.PasswordSetupExpires.Valid ||
+		time.Now().After(user.PasswordSetupExpires.Time) {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("password setup token is invalid or has expired")
+		return
+	}
+
+	if match, _ := utility.VerifyPassword(user.UserPassword, request.Password); match {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("new password has to be different from the current password")
+		return
+	}
+
+	password, err := utility.HashPassword(request.Password)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = savePassword(tx, &user, password, models.SessionRevokedPasswordChange); err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	// Wrong codes of the challenge count against the account's email, or its mobile number without one
+	identifier := user.UserEmail
+	if identifier == "" {
+		identifier = user.MobileNumber
+	}
+	response, err = s.signIn(user, identifier)
+	if err != nil {
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+	response.Message = "Password set successfully"
+	return
+}
+
+// issuePasswordSetup stores a new single use setup token for a user who has to set their password
+// Parameters:
+// - tx: the database or transaction the token is stored with
+// - user: the user signing in with a temporary password, or without a password yet
+// Returns:
+// - dto.LoginResponse with the setup token in place of the authentication tokens
+// - error when the token could not be stored
+func issuePasswordSetup(tx *gorm.DB, user *models.User) (response dto.LoginResponse, err error) {
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return
+	}
+	token := hex.EncodeToString(secret)
+	expires := time.Now().Add(passwordSetupTTL)
+
+	// Only the hash of the token is kept, like a password
+	user.PasswordSetupToken = null.StringFrom(hashToken(token))
+	user.PasswordSetupExpires = null.TimeFrom(expires)
+	if err = tx.Omit(clause.Associations).Save(user).Error; err != nil {
+		return
+	}
+
+	response.Status = 2
+	response.Message = "Password has to be changed before signing in"
+	response.Data = dto.TokenDetail{
+		PasswordSetupToken: token,
+		UserID:             user.UserID,
+		UserType:           user.UserType,
+		TokenExpires:       expires.Unix(),
+	}
+	return
+}
+
+// savePassword stores a new password hash for a user, ends any pending password setup and signs out the sessions
+// started with the old password
+// Parameters:
+// - tx: the caller's transaction
+// - user: the user whose password changes
+// - hash: the new password hashed with utility.HashPassword
+// - reason: why the sessions of the user are revoked
+// Returns:
+// - error when the user or its sessions could not be saved
+func savePassword(tx *gorm.DB, user *models.User, hash string, reason string) error {
+	user.UserPassword = hash
+	user.PasswordChangeRequired = false
+	user.PasswordSetupToken = null.String{}
+	user.PasswordSetupExpires = null.Time{}
+	user.PasswordUpdatedAt = null.TimeFrom(time.Now())
+	if err := tx.Omit(clause.Associations).Save(user).Error; err != nil {
+		return err
+	}
+
+	session := models.AuthSession{}
+	return session.RevokeByUser(tx, user.UserID, "", reason)
+}
+
+// rehashPassword stores the password of a user with the current hashing algorithm and cost
+func rehashPassword(user *models.User, password string) error {
+	hash, err := utility.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.UserPassword = hash
+	return db.MysqlDB.Omit(clause.Associations).Save(user).Error
+}
+
+// hashToken returns the SHA-256 hex digest a password setup or MFA challenge token is stored as
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,283 @@
+package user_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// registrationMessage is the notification carrying a registration code, formatted with the code and its lifetime
+const registrationMessage = "Your %v verification code is %%v. It expires in %%d minutes, do not share it with anyone."
+
+// Register signs a customer up and sends a verification code to their email or mobile number. Registering again
+// with the same details sends a new code for the pending registration. Customers created with a loan application
+// before registration existed have no password, they register with the same email and mobile number to set one.
+// Parameters:
+// - request: dto.RegistrationRequest with the customer details and the channel to send the code on
+// Returns:
+// - dto.RegistrationResponse with the registration to verify
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) Register(request dto.RegistrationRequest) (response dto.RegistrationResponse, handle dto.HandleError) {
+	if request.Channel == "" {
+		request.Channel = notificationService.ChannelSMS
+	}
+
+	existing := findCustomer(request.UserEmail, request.MobileNumber)
+	if err := claimableCustomer(existing, request.UserEmail, request.MobileNumber); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	// A pending registration with the same details is continued rather than started again
+	var registrationCondition []db.WhereCondition
+	registrationCondition = append(registrationCondition, db.WhereCondition{
+		Key:       models.UserRegistrationColumns.UserEmail,
+		Condition: "=",
+		Value:     request.UserEmail,
+	})
+	registrationCondition = append(registrationCondition, db.WhereCondition{
+		Key:       models.UserRegistrationColumns.MobileNumber,
+		Condition: "=",
+		Value:     request.MobileNumber,
+	})
+	registrationCondition = append(registrationCondition, db.WhereCondition{
+		Key:       models.UserRegistrationColumns.Status,
+		Condition: "=",
+		Value:     models.RegistrationStatusPending,
+	})
+
+	registration := models.UserRegistration{}
+	registration, _ = registration.FindOneByCondition(registrationCondition)
+	if registration.RegistrationID == "" {
+		registration = models.UserRegistration{
+			RegistrationID: uuid.New().String(),
+			UserEmail:      request.UserEmail,
+			MobileNumber:   request.MobileNumber,
+			Status:         models.RegistrationStatusPending,
+		}
+	}
+	registration.UserName = request.UserName
+	registration.Channel = request.Channel
+
+	tx := db.MysqlDB.Begin()
+
+	if err := tx.Omit(clause.Associations).Save(&registration).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	message := dto.Notification{
+		Channel:   registration.Channel,
+		Recipient: registrationRecipient(registration),
+		Subject:   fmt.Sprintf("%v verification code", s.tenant),
+		Body:      fmt.Sprintf(registrationMessage, s.tenant),
+		Purpose:   models.OtpPurposeRegistration,
+	}
+	otp, err := issueOTP(tx, s.notifier, message, registration.RegistrationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Verification code sent"
+	response.Data.RegistrationID = registration.RegistrationID
+	response.Data.Channel = registration.Channel
+	response.Data.Recipient = maskRecipient(registration.Channel, otp.Recipient)
+	response.Data.CodeExpires = otp.ExpiresAt.Format(time.RFC3339)
+	return
+}
+
+// VerifyRegistration checks the code sent for a registration and creates the customer. The response carries a
+// password setup token in place of the authentication tokens, SetPassword finishes the registration with it.
+// Parameters:
+// - request: dto.RegistrationVerifyRequest with the registration and the code
+// Returns:
+// - dto.LoginResponse with the password setup token
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) VerifyRegistration(request dto.RegistrationVerifyRequest) (
+	response dto.LoginResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	registration := models.UserRegistration{}
+	registration, err := registration.FindByPrimaryKeyForUpdate(tx, request.RegistrationID)
+	if err != nil || registration.RegistrationID == "" {
+		tx.Rollback()
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("registration %v not found", request.RegistrationID)
+		return
+	}
+	if registration.Status != models.RegistrationStatusPending {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("registration has already been verified, sign in instead")
+		return
+	}
+
+	result, err := checkOTP(tx, models.OtpPurposeRegistration, registration.RegistrationID, request.Code)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = err
+		return
+	}
+	if result != otpValid {
+		// The failed attempt is kept so that codes cannot be guessed
+		if err = tx.Commit().Error; err != nil {
+			tx.Rollback()
+		}
+		handle.Status = -4
+		handle.Errors = otpError(result)
+		return
+	}
+
+	user, err := registerCustomer(tx, registration)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	registration.Status = models.RegistrationStatusVerified
+	registration.UserID = null.StringFrom(user.UserID)
+	registration.VerifiedAt = null.TimeFrom(time.Now())
+	if err = tx.Omit(clause.Associations).Save(&registration).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	response, err = issuePasswordSetup(tx, &user)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	response.Message = "Registration verified, set a password to finish"
+	return
+}
+
+// registerCustomer creates the customer of a verified registration, or takes over the customer without a password
+// created by a loan application with the same details
+func registerCustomer(tx *gorm.DB, registration models.UserRegistration) (user models.User, err error) {
+	user = findCustomer(registration.UserEmail, registration.MobileNumber)
+	if err = claimableCustomer(user, registration.UserEmail, registration.MobileNumber); err != nil {
+		return
+	}
+
+	if user.UserID == "" {
+		user = models.User{
+			UserID:       uuid.New().String(),
+			UserType:     constants.UserTypeCustomer,
+			UserName:     registration.UserName,
+			UserEmail:    registration.UserEmail,
+			MobileNumber: registration.MobileNumber,
+		}
+		err = tx.Create(&user).Error
+		return
+	}
+
+	user.UserName = registration.UserName
+	err = tx.Omit(clause.Associations).Save(&user).Error
+	return
+}
+
+// findCustomer finds the customer with an email or mobile number
+func findCustomer(email string, mobileNumber string) (user models.User) {
+	var andCondition, orCondition []db.WhereCondition
+	andCondition = append(andCondition, db.WhereCondition{
+		Key:       models.UsersColumns.UserType,
+		Condition: "=",
+		Value:     constants.UserTypeCustomer,
+	})
+	orCondition = append(orCondition, db.WhereCondition{
+		Key:       models.UsersColumns.UserEmail,
+		Condition: "=",
+		Value:     email,
+	})
+	orCondition = append(orCondition, db.WhereCondition{
+		Key:       models.UsersColumns.MobileNumber,
+		Condition: "=",
+		Value:     mobileNumber,
+	})
+
+	user, _ = user.FindOneByCondition(&andCondition, &orCondition)
+	return
+}
+
+// claimableCustomer checks that a registration may take over an existing customer: only one without a password,
+// and only with both its email and its mobile number
+// Parameters:
+// - user: the customer found for the registration, empty when there is none
+// - email: the email of the registration
+// - mobileNumber: the mobile number of the registration
+// Returns:
+// - error when the customer cannot be registered
+func claimableCustomer(user models.User, email string, mobileNumber string) error {
+	if user.UserID == "" {
+		return nil
+	}
+	if user.UserPassword != "" {
+		return fmt.Errorf("an account with this email or mobile number already exists, sign in instead")
+	}
+	if !strings.EqualFold(user.UserEmail, email) || user.MobileNumber != mobileNumber {
+		return fmt.Errorf("email and mobile number have to match the existing account")
+	}
+	return nil
+}
+
+// registrationRecipient returns where the code of a registration is sent
+func registrationRecipient(registration models.UserRegistration) string {
+	if registration.Channel == notificationService.ChannelEmail {
+		return registration.UserEmail
+	}
+	return registration.MobileNumber
+}
+
+// maskRecipient hides most of an email or mobile number, e.g. j***@example.com or ******0381
+func maskRecipient(channel string, recipient string) string {
+	if channel == notificationService.ChannelEmail {
+		name, domain, found := strings.Cut(recipient, "@")
+		if !found || name == "" {
+			return "***"
+		}
+		return name[:1] + "***@" + domain
+	}
+	if len(recipient) <= 4 {
+		return strings.Repeat("*", len(recipient))
+	}
+	return strings.Repeat("*", len(recipient)-4) + recipient[len(recipient)-4:]
+}
@@ -0,0 +1,42 @@
+package user_service
+
+import (
+	"testing"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskRecipient(t *testing.T) {
+	assert.Equal(t, "j***@example.com", maskRecipient("EMAIL", "john.doe@example.com"))
+	assert.Equal(t, "***", maskRecipient("EMAIL", "@example.com"))
+	assert.Equal(t, "******7890", maskRecipient("SMS", "1234567890"))
+	assert.Equal(t, "***", maskRecipient("SMS", "123"))
+}
+
+func TestClaimableCustomer(t *testing.T) {
+	// No existing customer, a new one is created
+	assert.NoError(t, claimableCustomer(models.User{}, "john.doe@example.com", "1234567890"))
+
+	// A customer created before registration existed, claimed with both details
+	legacy := models.User{UserID: "user_id", UserEmail: "John.Doe@example.com", MobileNumber: "1234567890"}
+	assert.NoError(t, claimableCustomer(legacy, "john.doe@example.com", "1234567890"))
+	assert.Error(t, claimableCustomer(legacy, "john.doe@example.com", "0987654321"))
+
+	// A customer with a password has to sign in instead
+	legacy.UserPassword = "bcrypt$hash"
+	assert.Error(t, claimableCustomer(legacy, "john.doe@example.com", "1234567890"))
+}
+
+func TestGenerateOTP(t *testing.T) {
+	code, err := generateOTP()
+	assert.NoError(t, err)
+	assert.Len(t, code, otpDigits)
+	for _, digit := range code {
+		assert.True(t, digit >= '0' && digit <= '9')
+	}
+
+	// The hash depends on both the code and the one-time password it belongs to
+	assert.Equal(t, hashOTP("otp_id", code), hashOTP("otp_id", code))
+	assert.NotEqual(t, hashOTP("otp_id", code), hashOTP("other_otp_id", code))
+}
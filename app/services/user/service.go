@@ -4,6 +4,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/nishanthrk/aspire-lms/app/dto"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	allocationService "github.com/nishanthrk/aspire-lms/app/services/allocation"
+	notificationService "github.com/nishanthrk/aspire-lms/app/services/notification"
 )
 
 // UserService defines the interface for user-related operations
@@ -11,23 +13,100 @@ type UserService interface {
 	// ValidateCredentials validates the user's login credentials
 	ValidateCredentials(request dto.LoginRequest) (response dto.LoginResponse, handle dto.HandleError)
 
+	// UnlockLogin clears the failed sign ins and lockout of an identifier or an IP address
+	UnlockLogin(request dto.UnlockLoginRequest, admin models.User) (response dto.UnlockLoginResponse, handle dto.HandleError)
+
+	// CreateUser creates a customer or an employee, who sets their password with a code sent to them
+	CreateUser(request dto.UserCreateRequest, admin models.User) (response dto.UserDetailResponse, handle dto.HandleError)
+
+	// GetUsers lists a page of the users
+	GetUsers(request dto.UserListRequest) (response dto.UserListResponse, handle dto.HandleError)
+
+	// GetUser returns the details of a user and their roles
+	GetUser(userId string) (response dto.UserDetailResponse, handle dto.HandleError)
+
+	// UpdateUser changes the name, email or mobile number of a user
+	UpdateUser(request dto.UserUpdateRequest, admin models.User) (response dto.UserDetailResponse, handle dto.HandleError)
+
+	// DeactivateUser disables a user and signs out every session of theirs
+	DeactivateUser(request dto.UserStatusRequest, admin models.User) (
+		response dto.UserDetailResponse, handle dto.HandleError)
+
+	// ReactivateUser enables a deactivated user again
+	ReactivateUser(request dto.UserStatusRequest, admin models.User) (
+		response dto.UserDetailResponse, handle dto.HandleError)
+
+	// ResetUserPassword clears the password of a user and sends them a code to choose a new one
+	ResetUserPassword(request dto.UserPasswordResetRequest, admin models.User) (
+		response dto.UserDetailResponse, handle dto.HandleError)
+
+	// AssignRoles replaces the roles of an employee
+	AssignRoles(request dto.UserRolesRequest, admin models.User) (response dto.UserDetailResponse, handle dto.HandleError)
+
+	// SetPassword replaces a temporary password using the setup token handed out at sign in
+	SetPassword(request dto.SetPasswordRequest) (response dto.LoginResponse, handle dto.HandleError)
+
+	// ForgotPassword sends a password reset code to the user with an email or mobile number, if there is one
+	ForgotPassword(request dto.ForgotPasswordRequest) (response dto.PasswordResetResponse, handle dto.HandleError)
+
+	// ResetPassword sets a new password with a reset code and signs out every session of the user
+	ResetPassword(request dto.ResetPasswordRequest) (response dto.PasswordResetResponse, handle dto.HandleError)
+
+	// VerifyMfa answers the MFA challenge of a sign in and issues the authentication tokens
+	VerifyMfa(request dto.MfaVerifyRequest) (response dto.LoginResponse, handle dto.HandleError)
+
+	// EnrollMfa generates a TOTP secret for the user to add to an authenticator app
+	EnrollMfa(request dto.MfaEnrollRequest, user models.User) (response dto.MfaEnrollResponse, handle dto.HandleError)
+
+	// ConfirmMfa enables the pending TOTP enrolment of the user with a code of the authenticator app
+	ConfirmMfa(request dto.MfaCodeRequest, user models.User) (response dto.MfaBackupCodesResponse, handle dto.HandleError)
+
+	// RegenerateBackupCodes replaces the backup codes of the user
+	RegenerateBackupCodes(request dto.MfaCodeRequest, user models.User) (
+		response dto.MfaBackupCodesResponse, handle dto.HandleError)
+
+	// DisableMfa removes the second factor of the user, unless their user type requires one
+	DisableMfa(request dto.MfaCodeRequest, user models.User) (response dto.MfaResponse, handle dto.HandleError)
+
+	// Register signs a customer up and sends them a verification code
+	Register(request dto.RegistrationRequest) (response dto.RegistrationResponse, handle dto.HandleError)
+
+	// VerifyRegistration checks the verification code and creates the customer, who then sets a password
+	VerifyRegistration(request dto.RegistrationVerifyRequest) (response dto.LoginResponse, handle dto.HandleError)
+
+	// RefreshToken exchanges a refresh token for new tokens, revoking its session when it is used twice
+	RefreshToken(request dto.RefreshTokenRequest) (response dto.LoginResponse, handle dto.HandleError)
+
+	// Logout revokes the session of the access token, or every session of the user
+	Logout(request dto.LogoutRequest, user models.User) (response dto.LogoutResponse, handle dto.HandleError)
+
+	// GetSessionID retrieves the session of the JWT token present in the request context
+	GetSessionID(c *fiber.Ctx) string
+
 	// GetUserObject retrieves the user object from the JWT token present in the request context
 	GetUserObject(c *fiber.Ctx) models.User
 
-	// GenerateAuth generates authentication tokens for the user
+	// GenerateAuth starts a session for the user and generates its authentication tokens
 	GenerateAuth(users models.User) (dto.LoginResponse, error)
 
 	// AllocateEmployeeForProcess allocates an employee for processing the loan application
 	AllocateEmployeeForProcess(application models.LoanApplication) (models.LoanApplicationParticipant, error)
-
-	// GetApplicationUser retrieves the user object based on the provided details
-	GetApplicationUser(object dto.UserObject) models.User
 }
 
 // userService is an implementation of UserService
-type userService struct{}
+type userService struct {
+	notifier  notificationService.Notifier
+	tenant    string
+	allocator allocationService.AllocationStrategy
+}
 
-// NewUserService returns a new instance of UserService
-func NewUserService() UserService {
-	return &userService{}
+// NewUserService returns a new instance of UserService, verification and password reset codes are sent through the
+// notifier in the tenant's name and new loan applications are allocated to employees by the allocator
+func NewUserService(notifier notificationService.Notifier, tenant string,
+	allocator allocationService.AllocationStrategy) UserService {
+	return &userService{
+		notifier:  notifier,
+		tenant:    tenant,
+		allocator: allocator,
+	}
 }
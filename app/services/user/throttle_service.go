@@ -0,0 +1,264 @@
+package user_service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	"github.com/nishanthrk/aspire-lms/app/configs"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// loginFreeFailures is how many failed sign ins are allowed without waiting before the next attempt
+	loginFreeFailures = 3
+
+	// loginBaseDelay is the wait after the first failure past loginFreeFailures, doubling with every further failure
+	loginBaseDelay = time.Second
+
+	// loginMaxDelay caps the wait between two attempts
+	loginMaxDelay = time.Minute
+
+	// loginFailureWindow is how long failures are remembered after the last one
+	loginFailureWindow = 15 * time.Minute
+
+	// loginMaxLockout caps the lockout, which doubles with every lockout since the last successful sign in
+	loginMaxLockout = 24 * time.Hour
+)
+
+var (
+	// dummyPasswordHash is checked against the password when the identifier matches no account, so that a sign in
+	// takes as long whether it exists or not
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
+// loginThrottleKey is a subject failed sign ins are counted for, with the failures that lock it out
+type loginThrottleKey struct {
+	scope       string
+	subject     string
+	maxFailures int
+}
+
+// UnlockLogin clears the failed sign ins and lockout of an identifier, an IP address or both
+// Parameters:
+// - request: dto.UnlockLoginRequest with the identifier and the IP address to unlock
+// - admin: the employee unlocking them
+// Returns:
+// - dto.UnlockLoginResponse confirming the unlock
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) UnlockLogin(request dto.UnlockLoginRequest, admin models.User) (
+	response dto.UnlockLoginResponse, handle dto.HandleError) {
+	tx := db.MysqlDB.Begin()
+
+	var unlocked int64
+	for _, key := range loginThrottleKeys(request.Identifier, request.IPAddress) {
+		throttle := models.LoginThrottle{}
+		deleted, err := throttle.DeleteByPrimaryKey(tx, key.scope, key.subject)
+		if err != nil {
+			tx.Rollback()
+			handle.Status = -1
+			handle.Errors = err
+			return
+		}
+		unlocked += deleted
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	logger.Sugar.Infow("sign in unlocked", "identifier", request.Identifier, "ip_address", request.IPAddress,
+		"unlocked_by", admin.UserID)
+	response.Status = 1
+	response.Message = "No failed sign ins were recorded"
+	if unlocked > 0 {
+		response.Message = "Sign in unlocked"
+	}
+	return
+}
+
+// loginThrottleKeys returns the subjects failed sign ins are counted for: the identifier, whether it matches an
+// account or not, and the IP address
+func loginThrottleKeys(identifier string, ipAddress string) (keys []loginThrottleKey) {
+	config := configs.GetConfig()
+	if identifier = strings.ToLower(strings.TrimSpace(identifier)); identifier != "" {
+		keys = append(keys, loginThrottleKey{models.LoginThrottleIdentifier, identifier, config.GetLoginMaxFailures()})
+	}
+	if ipAddress != "" {
+		keys = append(keys, loginThrottleKey{models.LoginThrottleIP, ipAddress, config.GetLoginIPMaxFailures()})
+	}
+	return
+}
+
+// loginRetryAfter returns how long the identifier and the IP address of a sign in have to wait before it is
+// attempted, zero when it can be attempted now
+func loginRetryAfter(identifier string, ipAddress string) time.Duration {
+	return throttleRetryAfter(loginThrottleKeys(identifier, ipAddress))
+}
+
+// throttleRetryAfter returns how long the longest waiting of the subjects has to wait before the next attempt
+func throttleRetryAfter(keys []loginThrottleKey) time.Duration {
+	var wait time.Duration
+	now := time.Now()
+	for _, key := range keys {
+		throttle := models.LoginThrottle{}
+		throttle, err := throttle.FindByPrimaryKey(key.scope, key.subject)
+		if err != nil {
+			logger.Sugar.Error("could not read failed sign ins of ", key.scope, ": ", err)
+			continue
+		}
+		if keyWait := loginWait(throttle, now); keyWait > wait {
+			wait = keyWait
+		}
+	}
+	return wait
+}
+
+// recordLoginFailure counts a failed sign in against the identifier and the IP address, delaying or locking them
+// out once they have too many
+func recordLoginFailure(identifier string, ipAddress string) error {
+	return recordThrottleFailure(loginThrottleKeys(identifier, ipAddress))
+}
+
+// recordThrottleFailure counts a failed attempt against each of the subjects, delaying or locking them out once
+// they have too many
+func recordThrottleFailure(keys []loginThrottleKey) error {
+	tx := db.MysqlDB.Begin()
+
+	now := time.Now()
+	lockout := configs.GetConfig().GetLoginLockoutDuration()
+	for _, key := range keys {
+		throttle := models.LoginThrottle{}
+		throttle, err := throttle.FindByPrimaryKeyForUpdate(tx, key.scope, key.subject)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if throttle.Scope == "" {
+			throttle = models.LoginThrottle{Scope: key.scope, Subject: key.subject, CreatedAt: now}
+		}
+
+		if registerLoginFailure(&throttle, now, key.maxFailures, lockout) {
+			logger.Sugar.Infow("sign in locked out", "scope", key.scope, "subject", key.subject,
+				"locked_until", throttle.LockedUntil.Time)
+		}
+		if err = tx.Omit(clause.Associations).Save(&throttle).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// clearLoginFailures forgets the failed sign ins of an identifier after a successful one. The failures of the IP
+// address are kept, signing in to one account does not vouch for the others tried from it.
+func clearLoginFailures(identifier string) {
+	clearThrottleFailures(loginThrottleKeys(identifier, ""))
+}
+
+// clearThrottleFailures forgets the failed attempts and lockout of the subjects
+func clearThrottleFailures(keys []loginThrottleKey) {
+	for _, key := range keys {
+		throttle := models.LoginThrottle{}
+		if _, err := throttle.DeleteByPrimaryKey(db.MysqlDB, key.scope, key.subject); err != nil {
+			logger.Sugar.Error("could not clear failed attempts of ", key.scope, ": ", err)
+		}
+	}
+}
+
+// loginWait returns how long a subject has to wait before its next sign in attempt
+func loginWait(throttle models.LoginThrottle, now time.Time) time.Duration {
+	var wait time.Duration
+	if throttle.LockedUntil.Valid && throttle.LockedUntil.Time.After(now) {
+		wait = throttle.LockedUntil.Time.Sub(now)
+	}
+	if throttle.NextAttemptAt.Valid && throttle.NextAttemptAt.Time.Sub(now) > wait {
+		wait = throttle.NextAttemptAt.Time.Sub(now)
+	}
+	return wait
+}
+
+// registerLoginFailure adds a failed sign in to a subject. Failures older than loginFailureWindow are forgotten.
+// Past loginFreeFailures every failure doubles the wait before the next attempt, and at maxFailures the subject is
+// locked out for the lockout duration, doubled for every lockout since its last successful sign in.
+// Parameters:
+// - throttle: the failures of the subject so far
+// - now: when the sign in failed
+// - maxFailures: the failures that lock the subject out
+// - lockout: how long the first lockout lasts
+// Returns:
+// - bool reporting whether the subject was locked out by this failure
+func registerLoginFailure(throttle *models.LoginThrottle, now time.Time, maxFailures int,
+	lockout time.Duration) bool {
+	if throttle.LastFailedAt.Valid && now.Sub(throttle.LastFailedAt.Time) > loginFailureWindow {
+		throttle.FailedCount = 0
+	}
+	throttle.FailedCount++
+	throttle.LastFailedAt = null.TimeFrom(now)
+	throttle.NextAttemptAt = null.Time{}
+
+	if throttle.FailedCount >= maxFailures {
+		for i := 0; i < throttle.LockoutCount && lockout < loginMaxLockout; i++ {
+			lockout *= 2
+		}
+		if lockout > loginMaxLockout {
+			lockout = loginMaxLockout
+		}
+		throttle.FailedCount = 0
+		throttle.LockoutCount++
+		throttle.LockedUntil = null.TimeFrom(now.Add(lockout))
+		return true
+	}
+
+	if throttle.FailedCount > loginFreeFailures {
+		delay := loginBaseDelay
+		for i := loginFreeFailures + 1; i < throttle.FailedCount && delay < loginMaxDelay; i++ {
+			delay *= 2
+		}
+		if delay > loginMaxDelay {
+			delay = loginMaxDelay
+		}
+		throttle.NextAttemptAt = null.TimeFrom(now.Add(delay))
+	}
+	return false
+}
+
+// loginThrottled describes a refused sign in, without telling whether the identifier or the IP address is limited
+// Parameters:
+// - wait: how long until the next attempt
+// Returns:
+// - int64 with the wait in whole seconds, rounded up, for the Retry-After header
+// - error describing the refusal
+func loginThrottled(wait time.Duration) (int64, error) {
+	seconds := retryAfterSeconds(wait)
+	return seconds, fmt.Errorf("too many failed sign ins, try again in %d seconds", seconds)
+}
+
+// retryAfterSeconds returns a wait in whole seconds, rounded up, for the Retry-After header
+func retryAfterSeconds(wait time.Duration) int64 {
+	return int64((wait + time.Second - 1) / time.Second)
+}
+
+// checkDummyPassword spends the time of a password check when the identifier matches no account
+func checkDummyPassword(password string) {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = utility.HashPassword("dummy password for unknown identifiers")
+	})
+	utility.VerifyPassword(dummyPasswordHash, password)
+}
@@ -0,0 +1,94 @@
+package user_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterLoginFailure_ProgressiveDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	throttle := models.LoginThrottle{}
+
+	// The first failures can be retried straight away
+	for i := 0; i < loginFreeFailures; i++ {
+		assert.False(t, registerLoginFailure(&throttle, now, 10, 15*time.Minute))
+		assert.Equal(t, time.Duration(0), loginWait(throttle, now))
+	}
+
+	// Every further failure doubles the wait
+	registerLoginFailure(&throttle, now, 10, 15*time.Minute)
+	assert.Equal(t, time.Second, loginWait(throttle, now))
+	registerLoginFailure(&throttle, now, 10, 15*time.Minute)
+	assert.Equal(t, 2*time.Second, loginWait(throttle, now))
+	registerLoginFailure(&throttle, now, 10, 15*time.Minute)
+	assert.Equal(t, 4*time.Second, loginWait(throttle, now))
+
+	// Failures are forgotten once the window has passed
+	later := now.Add(loginFailureWindow + time.Minute)
+	registerLoginFailure(&throttle, later, 10, 15*time.Minute)
+	assert.Equal(t, 1, throttle.FailedCount)
+	assert.Equal(t, time.Duration(0), loginWait(throttle, later))
+}
+
+func TestRegisterLoginFailure_Lockout(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	throttle := models.LoginThrottle{}
+
+	for i := 1; i < 5; i++ {
+		assert.False(t, registerLoginFailure(&throttle, now, 5, 15*time.Minute))
+	}
+	assert.True(t, registerLoginFailure(&throttle, now, 5, 15*time.Minute))
+	assert.Equal(t, 15*time.Minute, loginWait(throttle, now))
+	assert.Equal(t, 0, throttle.FailedCount)
+
+	// The next lockout lasts twice as long, up to loginMaxLockout
+	for i := 0; i < 5; i++ {
+		registerLoginFailure(&throttle, now, 5, 15*time.Minute)
+	}
+	assert.Equal(t, 30*time.Minute, loginWait(throttle, now))
+
+	throttle.LockoutCount = 20
+	throttle.FailedCount = 4
+	registerLoginFailure(&throttle, now, 5, 15*time.Minute)
+	assert.Equal(t, loginMaxLockout, loginWait(throttle, now))
+}
+
+func TestLoginWait_Expired(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	throttle := models.LoginThrottle{
+		LockedUntil:   null.TimeFrom(now.Add(-time.Minute)),
+		NextAttemptAt: null.TimeFrom(now.Add(-time.Second)),
+	}
+	assert.Equal(t, time.Duration(0), loginWait(throttle, now))
+}
+
+func TestLoginThrottled(t *testing.T) {
+	// The wait is rounded up, so that a retry after it is never refused
+	seconds, err := loginThrottled(1500 * time.Millisecond)
+	assert.Equal(t, int64(2), seconds)
+	assert.EqualError(t, err, "too many failed sign ins, try again in 2 seconds")
+}
+
+func TestMfaThrottleKeys_PerUser(t *testing.T) {
+	keys := mfaThrottleKeys("user_id")
+	assert.Equal(t, []loginThrottleKey{{models.LoginThrottleMfa, "user_id", mfaMaxAttempts}}, keys)
+
+	// Wrong codes of a signed in user lock its second factor settings at mfaMaxAttempts
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	throttle := models.LoginThrottle{}
+	for i := 1; i < mfaMaxAttempts; i++ {
+		assert.False(t, registerLoginFailure(&throttle, now, keys[0].maxFailures, 15*time.Minute))
+	}
+	assert.True(t, registerLoginFailure(&throttle, now, keys[0].maxFailures, 15*time.Minute))
+	assert.Equal(t, 15*time.Minute, loginWait(throttle, now))
+}
+
+func TestMfaThrottled(t *testing.T) {
+	seconds, err := mfaThrottled(899500 * time.Millisecond)
+	assert.Equal(t, int64(900), seconds)
+	assert.EqualError(t, err, "too many wrong authentication codes, try again in 900 seconds")
+}
@@ -0,0 +1,318 @@
+package user_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/utility"
+	cfg "github.com/nishanthrk/aspire-lms/app/configs"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	signingService "github.com/nishanthrk/aspire-lms/app/services/signing"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// accessTokenTTL is how long an access token is accepted
+const accessTokenTTL = 14 * time.Hour
+
+// refreshTokenTTL is how long a refresh token can be exchanged, every exchange issues a new one
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// RefreshToken exchanges a refresh token for new access and refresh tokens. A refresh token can be used once, using
+// it again means it was stolen or replayed, and revokes the session it belongs to with every token issued from it.
+// Parameters:
+// - request: dto.RefreshTokenRequest with the refresh token
+// Returns:
+// - dto.LoginResponse with the new authentication tokens
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) RefreshToken(request dto.RefreshTokenRequest) (response dto.LoginResponse, handle dto.HandleError) {
+	claims, err := parseRefreshToken(request.RefreshToken)
+	if err != nil || claims.ID == "" || claims.SessionId == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("refresh token is invalid or has expired")
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	// Lock the token so that two exchanges of the same token cannot both succeed
+	token := models.RefreshToken{}
+	token, err = token.FindByPrimaryKeyForUpdate(tx, claims.ID)
+	if err != nil || token.TokenID == "" || token.SessionID != claims.SessionId {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("refresh token is invalid or has expired")
+		return
+	}
+
+	session := models.AuthSession{}
+	session, err = session.FindByPrimaryKeyForUpdate(tx, token.SessionID)
+	if err != nil || session.Status != models.AuthSessionStatusActive {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("session has been revoked, sign in again")
+		return
+	}
+
+	// A used token coming back is a replay, the whole session is revoked
+	if token.Status != models.RefreshTokenStatusActive {
+		revokeSession(&session, models.SessionRevokedTokenReuse)
+		if err = tx.Omit(clause.Associations).Save(&session).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -4
+			handle.Errors = err
+			return
+		}
+		if err = tx.Commit().Error; err != nil {
+			tx.Rollback()
+			handle.Status = -4
+			handle.Errors = err
+			return
+		}
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("refresh token has already been used, the session has been revoked")
+		return
+	}
+
+	user := models.User{}
+	user, _ = user.FindByPrimaryKey(session.UserID)
+	if user.UserID == "" {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = fmt.Errorf("user details not found")
+		return
+	}
+
+	response, next, err := issueTokens(tx, user, session.SessionID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	now := time.Now()
+	token.Status = models.RefreshTokenStatusUsed
+	token.UsedAt = null.TimeFrom(now)
+	token.ReplacedBy = null.StringFrom(next.TokenID)
+	if err = tx.Omit(clause.Associations).Save(&token).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	session.LastRefreshedAt = null.TimeFrom(now)
+	if err = tx.Omit(clause.Associations).Save(&session).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -8
+		handle.Errors = err
+		return
+	}
+	return
+}
+
+// Logout revokes the session of the access token used, or every session of the user, so that neither their access
+// nor their refresh tokens are accepted any more
+// Parameters:
+// - request: dto.LogoutRequest with the session of the access token and whether to sign out everywhere
+// - user: models.User representing the signed in user
+// Returns:
+// - dto.LogoutResponse with the result
+// - dto.HandleError with any error that occurred during the process
+func (s *userService) Logout(request dto.LogoutRequest, user models.User) (response dto.LogoutResponse, handle dto.HandleError) {
+	if !request.AllSessions && request.SessionID == "" {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("access token does not belong to a session")
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	if request.AllSessions {
+		session := models.AuthSession{}
+		if err := session.RevokeByUser(tx, user.UserID, "", models.SessionRevokedLogout); err != nil {
+			tx.Rollback()
+			handle.Status = -2
+			handle.Errors = err
+			return
+		}
+	} else {
+		session := models.AuthSession{}
+		session, err := session.FindByPrimaryKeyForUpdate(tx, request.SessionID)
+		if err != nil || session.SessionID == "" || session.UserID != user.UserID {
+			tx.Rollback()
+			handle.Status = -3
+			handle.Errors = fmt.Errorf("session %v not found", request.SessionID)
+			return
+		}
+
+		if session.Status == models.AuthSessionStatusActive {
+			revokeSession(&session, models.SessionRevokedLogout)
+			if err = tx.Omit(clause.Associations).Save(&session).Error; err != nil {
+				tx.Rollback()
+				handle.Status = -2
+				handle.Errors = err
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Signed out successfully"
+	return
+}
+
+// GetSessionID returns the session of the access token in the request context, empty for tokens issued before
+// sessions were introduced
+// Parameters:
+// - c: *fiber.Ctx representing the Fiber request context
+// Returns:
+// - string with the session ID
+func (s *userService) GetSessionID(c *fiber.Ctx) string {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	sessionId, _ := claims["sid"].(string)
+	return sessionId
+}
+
+// issueTokens signs an access token and a new refresh token for a session and stores the refresh token
+// Parameters:
+// - tx: the transaction the refresh token is stored in
+// - user: the user the tokens are issued to
+// - sessionId: the session the tokens belong to
+// Returns:
+// - dto.LoginResponse with the tokens
+// - models.RefreshToken that was stored
+// - error when the tokens could not be signed or stored
+func issueTokens(tx *gorm.DB, user models.User, sessionId string) (
+	response dto.LoginResponse, refresh models.RefreshToken, err error) {
+	// Deactivated users keep no access, whatever signed them in
+	if user.Status == models.UserStatusInactive {
+		err = errUserDeactivated
+		return
+	}
+
+	now := time.Now()
+	expireTime := now.Add(accessTokenTTL)
+
+	// Embed the roles of the user and the permissions they grant, they are checked again when the token is refreshed
+	access, err := authorizationService.GetUserAccess(user.UserID)
+	if err != nil {
+		return
+	}
+
+	// Create access claims with user information and token metadata
+	accessClaims := AccessClaims{
+		user.UserName,
+		user.UserType,
+		utility.ToString(user.UserID),
+		sessionId,
+		access.Roles,
+		access.Permissions,
+		jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expireTime),
+			Issuer:    cfg.GetConfig().Tenant,
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	// Sign the access token with the key currently signing, services verify it against the published key set
+	accessToken, err := signingService.SignToken(accessClaims)
+	if err != nil {
+		return
+	}
+
+	// Store the refresh token, its ID is the jti claim
+	refresh = models.RefreshToken{
+		TokenID:   uuid.New().String(),
+		SessionID: sessionId,
+		Status:    models.RefreshTokenStatusActive,
+		ExpiresAt: now.Add(refreshTokenTTL),
+	}
+	if err = tx.Create(&refresh).Error; err != nil {
+		return
+	}
+
+	// Create refresh claims with user information and token metadata
+	refreshClaims := AccessClaims{
+		user.UserName,
+		user.UserType,
+		utility.ToString(user.UserID),
+		sessionId,
+		nil,
+		nil,
+		jwt.RegisteredClaims{
+			ID:        refresh.TokenID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    cfg.GetConfig().Tenant,
+			ExpiresAt: jwt.NewNumericDate(refresh.ExpiresAt),
+		},
+	}
+
+	// Generate the refresh token with the specified signing method and secret key
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).
+		SignedString([]byte(cfg.GetConfig().JWTRefreshSecret))
+	if err != nil {
+		return
+	}
+
+	// Return the generated tokens and user details in the response
+	response = dto.LoginResponse{
+		Status: 1,
+		Data: dto.TokenDetail{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			UserID:       utility.ToString(user.UserID),
+			UserType:     user.UserType,
+			Roles:        access.Roles,
+			TokenExpires: expireTime.Unix(),
+		},
+	}
+	return
+}
+
+// parseRefreshToken verifies the signature and expiry of a refresh token and returns its claims
+func parseRefreshToken(refreshToken string) (claims AccessClaims, err error) {
+	_, err = jwt.ParseWithClaims(refreshToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return []byte(cfg.GetConfig().JWTRefreshSecret), nil
+	})
+	return
+}
+
+// revokeSession marks a session as revoked
+func revokeSession(session *models.AuthSession, reason string) {
+	session.Status = models.AuthSessionStatusRevoked
+	session.RevokedReason = null.StringFrom(reason)
+	session.RevokedAt = null.TimeFrom(time.Now())
+}
@@ -0,0 +1,80 @@
+package user_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func signRefreshToken(t *testing.T, claims AccessClaims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return token
+}
+
+// setTokenConfig sets the configuration the tokens are signed with, the config needs a numeric DB_PORT
+func setTokenConfig(t *testing.T) {
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("JWT_REFRESH_SIGN_KEY", "refresh-secret")
+}
+
+func TestParseRefreshToken(t *testing.T) {
+	setTokenConfig(t)
+
+	claims := AccessClaims{
+		UserId:    "user_id",
+		SessionId: "session_id",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "token_id",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	parsed, err := parseRefreshToken(signRefreshToken(t, claims, "refresh-secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, "token_id", parsed.ID)
+	assert.Equal(t, "session_id", parsed.SessionId)
+	assert.Equal(t, "user_id", parsed.UserId)
+}
+
+func TestParseRefreshToken_Refused(t *testing.T) {
+	setTokenConfig(t)
+
+	claims := AccessClaims{
+		SessionId: "session_id",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "token_id",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	// An access token is signed with the access secret and cannot be used to refresh
+	_, err := parseRefreshToken(signRefreshToken(t, claims, "access-secret"))
+	assert.Error(t, err)
+
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	_, err = parseRefreshToken(signRefreshToken(t, claims, "refresh-secret"))
+	assert.Error(t, err)
+
+	_, err = parseRefreshToken("not a token")
+	assert.Error(t, err)
+}
+
+func TestGetSessionID(t *testing.T) {
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	service := NewUserService(nil, "", nil)
+	assert.Equal(t, "", service.GetSessionID(c))
+
+	c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": "user_id"}})
+	assert.Equal(t, "", service.GetSessionID(c))
+
+	c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": "user_id", "sid": "session_id"}})
+	assert.Equal(t, "session_id", service.GetSessionID(c))
+}
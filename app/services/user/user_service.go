@@ -7,138 +7,169 @@ import (
 	"github.com/google/uuid"
 	"github.com/nishanthrk/aspire-lms/app/common/constants"
 	"github.com/nishanthrk/aspire-lms/app/common/utility"
-	cfg "github.com/nishanthrk/aspire-lms/app/configs"
 	db "github.com/nishanthrk/aspire-lms/app/database"
 	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
 	"github.com/nishanthrk/aspire-lms/app/models"
+	assignmentService "github.com/nishanthrk/aspire-lms/app/services/assignment"
 	"time"
 )
 
 // AccessClaims represents access token JWT claims
 type AccessClaims struct {
-	Username string `json:"username"`
-	UserType string `json:"user_type"`
-	UserId   string `json:"user_id"`
+	Username    string   `json:"username"`
+	UserType    string   `json:"user_type"`
+	UserId      string   `json:"user_id"`
+	SessionId   string   `json:"sid,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// ValidateCredentials validates the user's credentials for login
+// ValidateCredentials validates the user's credentials for login. Failed sign ins are counted per identifier and IP
+// address, which are delayed and then locked out once they have too many.
 // Parameters:
 // - request: dto.LoginRequest containing the login details (platform and identifier)
 // Returns:
-// - dto.LoginResponse with the authentication tokens
-// - dto.HandleError with any error that occurred during the process
+// - dto.LoginResponse with the authentication tokens, a password setup token when the password has to be changed,
+// or an MFA challenge token when the user has to enter a second factor, and the seconds to wait when throttled
+// - dto.HandleError with any error that occurred during the process, status -6 when the sign in is throttled and -7
+// when the user has been deactivated
 func (s *userService) ValidateCredentials(request dto.LoginRequest) (response dto.LoginResponse, handle dto.HandleError) {
-	userModel := models.User{}
+	// Refuse the attempt while the identifier or the IP address has to wait after failed sign ins
+	if wait := loginRetryAfter(request.Identifier, request.IPAddress); wait > 0 {
+		response.RetryAfter, handle.Errors = loginThrottled(wait)
+		handle.Status = -6
+		return
+	}
+
+	// Find the user of the platform by email or mobile number
+	userModel := findUserByIdentifier(request.Platform, request.Identifier)
+
+	// Check if the password matches, accounts without a password cannot sign in. Unknown identifiers are checked
+	// against a dummy hash and refused like a wrong password, so that neither tells whether an account exists.
+	match, rehash := false, false
+	if userModel.UserID == "" {
+		checkDummyPassword(request.Password)
+	} else {
+		match, rehash = utility.VerifyPassword(userModel.UserPassword, request.Password)
+	}
+	if !match {
+		if err := recordLoginFailure(request.Identifier, request.IPAddress); err != nil {
+			logger.Sugar.Error("could not record failed sign in: ", err)
+		}
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("user details not found")
+		return
+	}
+
+	// Deactivated users cannot sign in until they are reactivated
+	if userModel.Status == models.UserStatusInactive {
+		handle.Status = -7
+		handle.Errors = errUserDeactivated
+		return
+	}
+
+	// Replace a legacy SHA-512 or weaker hash now that the password is known, signing in does not depend on it
+	if rehash {
+		if err := rehashPassword(&userModel, request.Password); err != nil {
+			logger.Sugar.Error("could not rehash password of user ", userModel.UserID, ": ", err)
+		}
+	}
+
+	// A temporary password only allows choosing a new one
+	if userModel.PasswordChangeRequired {
+		setup, err := issuePasswordSetup(db.MysqlDB, &userModel)
+		if err != nil {
+			handle.Status = -5
+			handle.Errors = fmt.Errorf("user details not found")
+			return
+		}
+		clearLoginFailures(request.Identifier)
+		return setup, handle
+	}
+
+	// Generate authentication tokens, or the challenge of the second factor
+	response, err := s.signIn(userModel, request.Identifier)
+	if err != nil {
+		handle.Status = -4
+		handle.Errors = fmt.Errorf("user details not found")
+		return
+	}
+
+	// The failures are cleared once the user is signed in, a challenge has to be answered first
+	if response.Status != mfaChallengeStatus {
+		clearLoginFailures(request.Identifier)
+	}
+
+	return
+}
+
+// findUserByIdentifier finds the user of a platform whose email or mobile number is the identifier
+// Parameters:
+// - platform: the X-Platform the request was made from, selecting customers or employees
+// - identifier: the email or mobile number entered by the user
+// Returns:
+// - models.User that was found, empty when there is none
+func findUserByIdentifier(platform string, identifier string) (user models.User) {
 	var andCondition, orCondition []db.WhereCondition
 
 	// Add platform validation condition
 	andCondition = append(andCondition, db.WhereCondition{
 		Key:       models.UsersColumns.UserType,
 		Condition: "=",
-		Value:     utility.ValidatePlatform(request.Platform),
+		Value:     utility.ValidatePlatform(platform),
 	})
 
 	// Add email identifier condition
 	orCondition = append(orCondition, db.WhereCondition{
 		Key:       models.UsersColumns.UserEmail,
 		Condition: "=",
-		Value:     request.Identifier,
+		Value:     identifier,
 	})
 
 	// Add mobile number identifier condition
 	orCondition = append(orCondition, db.WhereCondition{
 		Key:       models.UsersColumns.MobileNumber,
 		Condition: "=",
-		Value:     request.Identifier,
+		Value:     identifier,
 	})
 
-	// Find user by conditions
-	userModel, _ = userModel.FindOneByCondition(&andCondition, &orCondition)
-	if userModel.UserID == "" {
-		handle.Status = -2
-		handle.Errors = fmt.Errorf("user details not found")
-		return
-	}
-
-	// Check if the password matches
-	if userModel.UserPassword != utility.HashPassword(request.Password) {
-		handle.Status = -3
-		handle.Errors = fmt.Errorf("user details not found")
-		return
-	}
-
-	// Generate authentication tokens
-	response, err := s.GenerateAuth(userModel)
-	if err != nil {
-		handle.Status = -4
-		handle.Errors = fmt.Errorf("user details not found")
-		return
-	}
-
+	user, _ = user.FindOneByCondition(&andCondition, &orCondition)
 	return
 }
 
-// GenerateAuth generates JWT access and refresh tokens for the authenticated user
+// GenerateAuth starts a new session for the authenticated user and issues its first access and refresh tokens
 // Parameters:
 // - users: models.User representing the authenticated user
 // Returns:
 // - dto.LoginResponse with the authentication tokens and user details
 // - error if any error occurred during token generation
 func (s *userService) GenerateAuth(users models.User) (detail dto.LoginResponse, err error) {
-	// Set the access token expiration time to 14 hours
-	expireTime := time.Now().Add(time.Hour * 14)
-
-	// Create access claims with user information and token metadata
-	accessClaims := AccessClaims{
-		users.UserName,
-		users.UserType,
-		utility.ToString(users.UserID),
-		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expireTime),
-			Issuer:    cfg.GetConfig().Tenant,
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	// Generate the access token with the specified signing method and secret key
-	accessClaimWithSecret := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessToken, err := accessClaimWithSecret.SignedString([]byte(cfg.GetConfig().JWTAccessSecret))
-	if err != nil {
-		return
-	}
+	tx := db.MysqlDB.Begin()
 
-	// Create refresh claims with user information and token metadata
-	refreshClaims := AccessClaims{
-		users.UserName,
-		users.UserType,
-		utility.ToString(users.UserID),
-		jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    cfg.GetConfig().Tenant,
-			NotBefore: jwt.NewNumericDate(expireTime),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 30)),
-		},
+	// Every sign in starts a session, the refresh tokens rotated from it share the session ID
+	session := models.AuthSession{
+		SessionID: uuid.New().String(),
+		UserID:    users.UserID,
+		Status:    models.AuthSessionStatusActive,
+	}
+	if err = tx.Create(&session).Error; err != nil {
+		tx.Rollback()
+		return
 	}
 
-	// Generate the refresh token with the specified signing method and secret key
-	refreshClaimWithSecret := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshToken, err := refreshClaimWithSecret.SignedString([]byte(cfg.GetConfig().JWTRefreshSecret))
+	detail, _, err = issueTokens(tx, users, session.SessionID)
 	if err != nil {
+		tx.Rollback()
 		return
 	}
 
-	// Return the generated tokens and user details in the response
-	return dto.LoginResponse{
-		Status: 1,
-		Data: dto.TokenDetail{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-			UserID:       utility.ToString(users.UserID),
-			UserType:     users.UserType,
-			TokenExpires: expireTime.Unix(),
-		},
-	}, nil
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return
+	}
+	return
 }
 
 // GetUserObject retrieves the user object from the JWT token in the request context
@@ -180,7 +211,7 @@ func (s *userService) GetUserObject(c *fiber.Ctx) (userModel models.User) {
 	return
 }
 
-// AllocateEmployeeForProcess assigns the least loaded employee to the loan application
+// AllocateEmployeeForProcess assigns the employee selected by the configured allocation strategy to the loan application
 // Parameters:
 // - application: models.LoanApplication representing the loan application
 // Returns:
@@ -189,8 +220,16 @@ func (s *userService) GetUserObject(c *fiber.Ctx) (userModel models.User) {
 func (s *userService) AllocateEmployeeForProcess(application models.LoanApplication) (participant models.LoanApplicationParticipant, err error) {
 	user := models.User{}
 
-	// Find the least loaded employee
-	employeeId, _ := user.FindLeastLoadedEmployee()
+	// Select the employee by the configured allocation strategy
+	employeeId, err := s.allocator.SelectEmployee(application)
+	if err != nil {
+		return
+	}
+
+	// Forward the application to the delegate of an employee who is out of office
+	if employeeId, err = assignmentService.ResolveDelegate(employeeId, time.Now()); err != nil {
+		return
+	}
 
 	// Retrieve the employee details by primary key
 	user, _ = user.FindByPrimaryKey(employeeId)
@@ -209,36 +248,3 @@ func (s *userService) AllocateEmployeeForProcess(application models.LoanApplicat
 
 	return
 }
-
-// GetApplicationUser retrieves or creates a user based on the provided UserObject
-// Parameters:
-// - object: dto.UserObject containing user details
-// Returns:
-// - models.User representing the user found or created
-func (s *userService) GetApplicationUser(object dto.UserObject) (userModel models.User) {
-	var andCondition, orCondition []db.WhereCondition
-
-	// Add condition to match the user type as 'Customer'
-	andCondition = append(andCondition, db.WhereCondition{
-		Key:       models.UsersColumns.UserType,
-		Condition: "=",
-		Value:     constants.UserTypeCustomer,
-	})
-
-	// Add conditions to match either user email or mobile number
-	orCondition = append(orCondition, db.WhereCondition{
-		Key:       models.UsersColumns.UserEmail,
-		Condition: "=",
-		Value:     object.UserEmail,
-	})
-
-	orCondition = append(orCondition, db.WhereCondition{
-		Key:       models.UsersColumns.MobileNumber,
-		Condition: "=",
-		Value:     object.MobileNumber,
-	})
-
-	// Find the user by the specified conditions
-	userModel, _ = userModel.FindOneByCondition(&andCondition, &orCondition)
-	return
-}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/services/writeoff/service.go
+
+// Package writeoff_service is a generated GoMock package.
+package writeoff_service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/nishanthrk/aspire-lms/app/dto"
+	models "github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// MockWriteOffService is a mock of WriteOffService interface.
+type MockWriteOffService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriteOffServiceMockRecorder
+}
+
+// MockWriteOffServiceMockRecorder is the mock recorder for MockWriteOffService.
+type MockWriteOffServiceMockRecorder struct {
+	mock *MockWriteOffService
+}
+
+// NewMockWriteOffService creates a new mock instance.
+func NewMockWriteOffService(ctrl *gomock.Controller) *MockWriteOffService {
+	mock := &MockWriteOffService{ctrl: ctrl}
+	mock.recorder = &MockWriteOffServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriteOffService) EXPECT() *MockWriteOffServiceMockRecorder {
+	return m.recorder
+}
+
+// GetWriteOffReport mocks base method.
+func (m *MockWriteOffService) GetWriteOffReport(request dto.WriteOffReportRequest) (dto.WriteOffReportResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWriteOffReport", request)
+	ret0, _ := ret[0].(dto.WriteOffReportResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// GetWriteOffReport indicates an expected call of GetWriteOffReport.
+func (mr *MockWriteOffServiceMockRecorder) GetWriteOffReport(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWriteOffReport", reflect.TypeOf((*MockWriteOffService)(nil).GetWriteOffReport), request)
+}
+
+// WriteOffLoan mocks base method.
+func (m *MockWriteOffService) WriteOffLoan(request dto.WriteOffRequest, user models.User) (dto.WriteOffResponse, dto.HandleError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteOffLoan", request, user)
+	ret0, _ := ret[0].(dto.WriteOffResponse)
+	ret1, _ := ret[1].(dto.HandleError)
+	return ret0, ret1
+}
+
+// WriteOffLoan indicates an expected call of WriteOffLoan.
+func (mr *MockWriteOffServiceMockRecorder) WriteOffLoan(request, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteOffLoan", reflect.TypeOf((*MockWriteOffService)(nil).WriteOffLoan), request, user)
+}
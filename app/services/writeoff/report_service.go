@@ -0,0 +1,144 @@
+package writeoff_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+)
+
+// GetWriteOffReport lists the loans written off in a period, both days included, with the amounts taken off book
+// and recovered since, and totals them per currency
+// Parameters:
+// - request: dto.WriteOffReportRequest with the period and an optional currency
+// Returns:
+// - dto.WriteOffReportResponse with the write-offs and their totals
+// - dto.HandleError with any error that occurred during the process
+func (s *writeOffService) GetWriteOffReport(request dto.WriteOffReportRequest) (
+	response dto.WriteOffReportResponse, handle dto.HandleError) {
+	from, _ := time.ParseInLocation("2006-01-02", request.From, time.Local)
+	to, _ := time.ParseInLocation("2006-01-02", request.To, time.Local)
+	if from.After(to) {
+		handle.Status = -1
+		handle.Errors = fmt.Errorf("from date %v is after to date %v", request.From, request.To)
+		return
+	}
+
+	var writeOffCondition []db.WhereCondition
+	writeOffCondition = append(writeOffCondition, db.WhereCondition{
+		Key:       models.LoanWriteOffColumns.WrittenOffAt,
+		Condition: ">=",
+		Value:     from,
+	})
+	writeOffCondition = append(writeOffCondition, db.WhereCondition{
+		Key:       models.LoanWriteOffColumns.WrittenOffAt,
+		Condition: "<",
+		Value:     to.AddDate(0, 0, 1),
+	})
+	if request.CurrencyCode != "" {
+		writeOffCondition = append(writeOffCondition, db.WhereCondition{
+			Key:       models.LoanWriteOffColumns.CurrencyCode,
+			Condition: "=",
+			Value:     request.CurrencyCode,
+		})
+	}
+
+	writeOff := models.LoanWriteOff{}
+	writeOffs, err := writeOff.FindAllByCondition(writeOffCondition)
+	if err != nil {
+		handle.Status = -2
+		handle.Errors = err
+		return
+	}
+
+	// Recoveries are credited to recovery income, net of reversed ones
+	recovered := make(map[string]float64)
+	journalLine := models.JournalLine{}
+	for _, writeOff := range writeOffs {
+		balance, err := journalLine.ApplicationBalance(db.MysqlDB, models.LedgerAccountRecoveryIncome, writeOff.ApplicationID)
+		if err != nil {
+			handle.Status = -3
+			handle.Errors = err
+			return
+		}
+		recovered[writeOff.ApplicationID] = -balance
+	}
+
+	response.Status = 1
+	response.Data.From = request.From
+	response.Data.To = request.To
+	response.Data.WriteOffs, response.Data.Totals = buildWriteOffReport(writeOffs, recovered)
+	return
+}
+
+// writeOffTotal accumulates the write-offs of a currency in minor units
+type writeOffTotal struct {
+	count       int
+	outstanding int64
+	principal   int64
+	interest    int64
+	fees        int64
+	recovered   int64
+}
+
+// buildWriteOffReport maps the write-offs to the report and totals them per currency, in order of first appearance
+// Parameters:
+// - writeOffs: the write-offs of the period
+// - recovered: the amount recovered per application
+// Returns:
+// - []dto.WriteOffObject with a line per write-off
+// - []dto.WriteOffTotal with the totals per currency
+func buildWriteOffReport(writeOffs []models.LoanWriteOff, recovered map[string]float64) (
+	objects []dto.WriteOffObject, totals []dto.WriteOffTotal) {
+	objects = []dto.WriteOffObject{}
+	totals = []dto.WriteOffTotal{}
+
+	var currencies []string
+	sums := make(map[string]*writeOffTotal)
+	for _, writeOff := range writeOffs {
+		currency := writeOff.CurrencyCode
+		objects = append(objects, dto.WriteOffObject{
+			WriteOffId:    writeOff.WriteOffID,
+			ApplicationId: writeOff.ApplicationID,
+			CurrencyCode:  currency,
+			WrittenOffAt:  writeOff.WrittenOffAt.Format(time.RFC3339),
+			WrittenOffBy:  writeOff.WrittenOffBy,
+			Reason:        writeOff.Reason,
+			Outstanding:   money.NewFromFloat(writeOff.OutstandingAmount, currency).Display(),
+			Principal:     money.NewFromFloat(writeOff.PrincipalAmount, currency).Display(),
+			Interest:      money.NewFromFloat(writeOff.InterestAmount, currency).Display(),
+			Fees:          money.NewFromFloat(writeOff.FeeAmount, currency).Display(),
+			Recovered:     money.NewFromFloat(recovered[writeOff.ApplicationID], currency).Display(),
+		})
+
+		sum, ok := sums[currency]
+		if !ok {
+			sum = &writeOffTotal{}
+			sums[currency] = sum
+			currencies = append(currencies, currency)
+		}
+		sum.count++
+		sum.outstanding += money.NewFromFloat(writeOff.OutstandingAmount, currency).Amount()
+		sum.principal += money.NewFromFloat(writeOff.PrincipalAmount, currency).Amount()
+		sum.interest += money.NewFromFloat(writeOff.InterestAmount, currency).Amount()
+		sum.fees += money.NewFromFloat(writeOff.FeeAmount, currency).Amount()
+		sum.recovered += money.NewFromFloat(recovered[writeOff.ApplicationID], currency).Amount()
+	}
+
+	for _, currency := range currencies {
+		sum := sums[currency]
+		totals = append(totals, dto.WriteOffTotal{
+			CurrencyCode: currency,
+			Count:        sum.count,
+			Outstanding:  money.New(sum.outstanding, currency).Display(),
+			Principal:    money.New(sum.principal, currency).Display(),
+			Interest:     money.New(sum.interest, currency).Display(),
+			Fees:         money.New(sum.fees, currency).Display(),
+			Recovered:    money.New(sum.recovered, currency).Display(),
+		})
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package writeoff_service
+
+import (
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+)
+
+// WriteOffService defines the interface for writing off unrecoverable loans
+type WriteOffService interface {
+	// WriteOffLoan Takes an approved loan off book and closes it as WRITTEN_OFF
+	WriteOffLoan(request dto.WriteOffRequest, user models.User) (dto.WriteOffResponse, dto.HandleError)
+
+	// GetWriteOffReport Lists the write-offs of a period with what was recovered on them
+	GetWriteOffReport(request dto.WriteOffReportRequest) (dto.WriteOffReportResponse, dto.HandleError)
+}
+
+// writeOffService is an implementation of WriteOffService
+type writeOffService struct {
+	ledger ledgerService.LedgerService
+}
+
+// NewWriteOffService returns a new instance of WriteOffService
+func NewWriteOffService(ledger ledgerService.LedgerService) WriteOffService {
+	return &writeOffService{
+		ledger: ledger,
+	}
+}
@@ -0,0 +1,217 @@
+package writeoff_service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/google/uuid"
+	"github.com/guregu/null"
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	db "github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	authorizationService "github.com/nishanthrk/aspire-lms/app/services/authorization"
+	"gorm.io/gorm/clause"
+)
+
+// WriteOffLoan writes off an approved loan. The employee must be assigned to the loan and hold a write-off
+// authority in its currency covering the amount the borrower still owes on the schedule. The receivables of the
+// loan are moved to the write-off expense, its unpaid installments are closed and the loan becomes WRITTEN_OFF.
+// Money received afterwards is booked as recovery income.
+// Parameters:
+// - request: dto.WriteOffRequest containing the application ID and the reason
+// - user: models.User representing the employee writing the loan off
+// Returns:
+// - dto.WriteOffResponse with the amounts written off
+// - dto.HandleError with any error that occurred during the process
+func (s *writeOffService) WriteOffLoan(request dto.WriteOffRequest, user models.User) (
+	response dto.WriteOffResponse, handle dto.HandleError) {
+	// Only the employee allocated to the loan, with the permission to write loans off, writes it off
+	rule := authorizationService.Rule{
+		ParticipantType: constants.UserTypeEmployee,
+		Permission:      models.PermissionLoanWriteOff,
+	}
+	if err := authorizationService.AuthorizeApplication(user, request.ApplicationID, rule); err != nil {
+		handle.Status = -1
+		handle.Errors = err
+		return
+	}
+
+	tx := db.MysqlDB.Begin()
+
+	// Lock the loan so that no payment is allocated while it is written off
+	application := models.LoanApplication{}
+	application, err := application.FindByPrimaryKeyForUpdate(tx, request.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -2
+		handle.Errors = fmt.Errorf("application %v not found", request.ApplicationID)
+		return
+	}
+
+	if application.Status != models.LoanApplicationStatusApproved {
+		tx.Rollback()
+		handle.Status = -3
+		handle.Errors = fmt.Errorf("application is %v and cannot be written off", application.Status)
+		return
+	}
+
+	var repaymentCondition []db.WhereCondition
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.ApplicationID,
+		Condition: "=",
+		Value:     application.ApplicationID,
+	})
+	repaymentCondition = append(repaymentCondition, db.WhereCondition{
+		Key:       models.RepaymentColumns.Status,
+		Condition: "=",
+		Value:     models.LoanApplicationStatusPending,
+	})
+
+	repayment := models.Repayment{}
+	repayments, err := repayment.FindAllByConditionForUpdate(tx, repaymentCondition)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -4
+		handle.Errors = err
+		return
+	}
+
+	outstanding := outstandingAmount(repayments)
+	if err = checkAuthority(user.UserID, application.CurrencyCode, outstanding); err != nil {
+		tx.Rollback()
+		handle.Status = -5
+		handle.Errors = err
+		return
+	}
+
+	// Move whatever the ledger holds for the loan off book
+	writeOff, err := s.ledger.PostWriteOff(tx, application, user.UserID)
+	if err != nil {
+		tx.Rollback()
+		handle.Status = -6
+		handle.Errors = err
+		return
+	}
+
+	now := time.Now()
+	loanWriteOff := models.LoanWriteOff{
+		WriteOffID:        uuid.New().String(),
+		ApplicationID:     application.ApplicationID,
+		CurrencyCode:      application.CurrencyCode,
+		OutstandingAmount: outstanding,
+		PrincipalAmount:   writeOff.Principal,
+		InterestAmount:    writeOff.Interest,
+		FeeAmount:         writeOff.Fees,
+		Reason:            request.Reason,
+		WrittenOffBy:      user.UserID,
+		WrittenOffAt:      now,
+	}
+	if writeOff.EntryID != "" {
+		loanWriteOff.EntryID = null.StringFrom(writeOff.EntryID)
+	}
+	if err = tx.Create(&loanWriteOff).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -7
+		handle.Errors = err
+		return
+	}
+
+	// Close the unpaid installments, nothing is collected against them any more
+	for i := range repayments {
+		repayments[i].Status = models.LoanApplicationStatusWrittenOff
+	}
+	if len(repayments) > 0 {
+		if err = tx.Omit(clause.Associations).Save(&repayments).Error; err != nil {
+			tx.Rollback()
+			handle.Status = -8
+			handle.Errors = err
+			return
+		}
+	}
+
+	application.Status = models.LoanApplicationStatusWrittenOff
+	if err = tx.Omit(clause.Associations).Save(&application).Error; err != nil {
+		tx.Rollback()
+		handle.Status = -9
+		handle.Errors = err
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		handle.Status = -10
+		handle.Errors = err
+		return
+	}
+
+	response.Status = 1
+	response.Message = "Loan written off successfully"
+	response.Data.WriteOffId = loanWriteOff.WriteOffID
+	response.Data.ApplicationId = application.ApplicationID
+	response.Data.Status = application.Status
+	response.Data.Outstanding = money.NewFromFloat(outstanding, application.CurrencyCode).Display()
+	response.Data.Principal = money.NewFromFloat(writeOff.Principal, application.CurrencyCode).Display()
+	response.Data.Interest = money.NewFromFloat(writeOff.Interest, application.CurrencyCode).Display()
+	response.Data.Fees = money.NewFromFloat(writeOff.Fees, application.CurrencyCode).Display()
+	response.Data.WrittenOffAt = now.Format(time.RFC3339)
+	return
+}
+
+// checkAuthority verifies that an employee may write off an amount in a currency
+// Parameters:
+// - userId: the employee writing off
+// - currencyCode: the currency of the loan
+// - amount: the amount written off
+// Returns:
+// - error when the employee has no authority in the currency or a lower one than the amount
+func checkAuthority(userId string, currencyCode string, amount float64) error {
+	var authorityCondition []db.WhereCondition
+	authorityCondition = append(authorityCondition, db.WhereCondition{
+		Key:       models.WriteOffAuthorityColumns.UserID,
+		Condition: "=",
+		Value:     userId,
+	})
+	authorityCondition = append(authorityCondition, db.WhereCondition{
+		Key:       models.WriteOffAuthorityColumns.CurrencyCode,
+		Condition: "=",
+		Value:     currencyCode,
+	})
+
+	authority := models.WriteOffAuthority{}
+	authority, err := authority.FindOneByCondition(authorityCondition)
+	if err != nil {
+		return err
+	}
+	return withinAuthority(authority, currencyCode, amount)
+}
+
+// withinAuthority compares an amount with a write-off authority
+func withinAuthority(authority models.WriteOffAuthority, currencyCode string, amount float64) error {
+	if authority.UserID == "" {
+		return fmt.Errorf("you don't have authority to write off loans in %v", currencyCode)
+	}
+	if math.Round(amount*100) > math.Round(authority.MaxAmount*100) {
+		return fmt.Errorf("write-off of %v exceeds your authority of %v",
+			money.NewFromFloat(amount, currencyCode).Display(),
+			money.NewFromFloat(authority.MaxAmount, currencyCode).Display())
+	}
+	return nil
+}
+
+// outstandingAmount returns what the borrower still owes on the unpaid installments
+func outstandingAmount(repayments []models.Repayment) float64 {
+	var cents int64
+	for _, repayment := range repayments {
+		if repayment.Status == models.LoanApplicationStatusPaid {
+			continue
+		}
+		remaining := math.Round((repayment.AmountDue - repayment.AmountPaid) * 100)
+		if remaining > 0 {
+			cents += int64(remaining)
+		}
+	}
+	return float64(cents) / 100
+}
@@ -0,0 +1,56 @@
+package writeoff_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nishanthrk/aspire-lms/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutstandingAmount_UnpaidInstallments(t *testing.T) {
+	repayments := []models.Repayment{
+		{AmountDue: 100.10, AmountPaid: 40.05, Status: models.LoanApplicationStatusPending},
+		{AmountDue: 100.10, Status: models.LoanApplicationStatusPending},
+		{AmountDue: 100.10, AmountPaid: 100.10, Status: models.LoanApplicationStatusPaid},
+	}
+
+	assert.Equal(t, 160.15, outstandingAmount(repayments))
+}
+
+func TestWithinAuthority(t *testing.T) {
+	authority := models.WriteOffAuthority{UserID: "user_id", CurrencyCode: "INR", MaxAmount: 50000}
+
+	assert.NoError(t, withinAuthority(authority, "INR", 50000))
+	assert.EqualError(t, withinAuthority(authority, "INR", 50000.01),
+		"write-off of ₹50,000.01 exceeds your authority of ₹50,000.00")
+	assert.EqualError(t, withinAuthority(models.WriteOffAuthority{}, "USD", 10),
+		"you don't have authority to write off loans in USD")
+}
+
+func TestBuildWriteOffReport_TotalsPerCurrency(t *testing.T) {
+	writtenOffAt := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	writeOffs := []models.LoanWriteOff{
+		{WriteOffID: "w1", ApplicationID: "a1", CurrencyCode: "INR", OutstandingAmount: 1200, PrincipalAmount: 1000,
+			InterestAmount: 150.5, FeeAmount: 49.5, WrittenOffAt: writtenOffAt},
+		{WriteOffID: "w2", ApplicationID: "a2", CurrencyCode: "USD", OutstandingAmount: 80, PrincipalAmount: 80,
+			WrittenOffAt: writtenOffAt},
+		{WriteOffID: "w3", ApplicationID: "a3", CurrencyCode: "INR", OutstandingAmount: 300, PrincipalAmount: 300,
+			WrittenOffAt: writtenOffAt},
+	}
+
+	objects, totals := buildWriteOffReport(writeOffs, map[string]float64{"a1": 200, "a3": 25.25})
+
+	assert.Len(t, objects, 3)
+	assert.Equal(t, "₹200.00", objects[0].Recovered)
+	assert.Equal(t, "$0.00", objects[1].Recovered)
+	assert.Len(t, totals, 2)
+	assert.Equal(t, "INR", totals[0].CurrencyCode)
+	assert.Equal(t, 2, totals[0].Count)
+	assert.Equal(t, "₹1,500.00", totals[0].Outstanding)
+	assert.Equal(t, "₹1,300.00", totals[0].Principal)
+	assert.Equal(t, "₹150.50", totals[0].Interest)
+	assert.Equal(t, "₹225.25", totals[0].Recovered)
+	assert.Equal(t, "USD", totals[1].CurrencyCode)
+	assert.Equal(t, "$80.00", totals[1].Principal)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nishanthrk/aspire-lms/app/common/constants"
+	"github.com/nishanthrk/aspire-lms/app/configs"
+	"github.com/nishanthrk/aspire-lms/app/database"
+	"github.com/nishanthrk/aspire-lms/app/dto"
+	"github.com/nishanthrk/aspire-lms/app/logger"
+	"github.com/nishanthrk/aspire-lms/app/models"
+	closureService "github.com/nishanthrk/aspire-lms/app/services/closure"
+	ledgerService "github.com/nishanthrk/aspire-lms/app/services/ledger"
+	reconciliationService "github.com/nishanthrk/aspire-lms/app/services/reconciliation"
+	repaymentService "github.com/nishanthrk/aspire-lms/app/services/repayment"
+)
+
+// reconcile imports a bank statement file from the command line, posts the matched credits and writes the
+// exceptions report as CSV, e.g.
+//
+//	go run ./cmd/reconcile -file statement.xml -user <employee user id> -report exceptions.csv
+func main() {
+	filePath := flag.String("file", "", "bank statement file to import (CSV or camt.053 XML)")
+	format := flag.String("format", "", "statement format, CSV or CAMT053 (detected from the file when empty)")
+	userId := flag.String("user", "", "user ID of the employee the import is recorded against")
+	reportPath := flag.String("report", "", "where to write the CSV exceptions report (stdout when empty)")
+	flag.Parse()
+
+	if *filePath == "" || *userId == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration from the environment file and connect to the database
+	configs.LoadLocalConfig()
+	logger.InitLogger()
+	database.ConnectMysql()
+
+	user := models.User{}
+	user, _ = user.FindByPrimaryKey(*userId)
+	if user.UserID == "" || user.UserType != constants.UserTypeEmployee {
+		logger.Sugar.Fatal("user ", *userId, " is not an employee")
+	}
+
+	content, err := os.ReadFile(*filePath)
+	if err != nil {
+		logger.Sugar.Fatal("could not read statement file: ", err)
+	}
+
+	// Bank credits settle without the payment gateway, the import does not need one configured
+	repaymentSvc := repaymentService.NewRepaymentService(nil, ledgerService.NewLedgerService(),
+		closureService.NewClosureService(configs.GetConfig().Tenant))
+	reconciliationSvc := reconciliationService.NewReconciliationService(repaymentSvc)
+
+	response, handle := reconciliationSvc.ImportStatement(dto.StatementImportRequest{
+		FileName: filepath.Base(*filePath),
+		Format:   strings.ToUpper(*format),
+	}, content, user)
+	if handle.Status < 0 {
+		logger.Sugar.Fatal("statement import failed: ", handle.Errors)
+	}
+
+	summary, _ := json.MarshalIndent(response.Data, "", "  ")
+	fmt.Fprintln(os.Stderr, string(summary))
+
+	report := os.Stdout
+	if *reportPath != "" {
+		report, err = os.Create(*reportPath)
+		if err != nil {
+			logger.Sugar.Fatal("could not create exceptions report: ", err)
+		}
+		defer report.Close()
+	}
+
+	if err := reconciliationService.WriteExceptionReport(report, response.Data.Exceptions); err != nil {
+		logger.Sugar.Fatal("could not write exceptions report: ", err)
+	}
+}
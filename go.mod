@@ -17,7 +17,9 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/newrelic/go-agent/v3 v3.33.0
 	github.com/stretchr/testify v1.9.0
+	github.com/valyala/fasthttp v1.51.0
 	go.uber.org/zap v1.27.0
+	golang.org/x/crypto v0.22.0
 	gorm.io/driver/mysql v1.5.7
 	gorm.io/gorm v1.25.10
 )
@@ -42,11 +44,9 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/valyala/fasthttp v1.51.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
-	golang.org/x/crypto v0.22.0 // indirect
 	golang.org/x/net v0.23.0 // indirect
 	golang.org/x/sys v0.19.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
@@ -40,7 +40,7 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowHeaders: "Origin, Content-Type, Accept, " +
-			"Authorization, Access-Control-Allow-Headers, X-Platform, X-IP, X-Forwarded-For",
+			"Authorization, Access-Control-Allow-Headers, X-Platform, X-IP, X-Forwarded-For, Idempotency-Key",
 		AllowMethods: "*",
 	}))
 
@@ -0,0 +1,37 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `idempotency_key`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `idempotency_key` (
+  `idempotency_key_id` VARCHAR(50) NOT NULL,
+  `idempotency_key` VARCHAR(255) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `request_method` VARCHAR(10) NOT NULL,
+  `request_path` VARCHAR(255) NOT NULL,
+  `request_hash` VARCHAR(64) NOT NULL,
+  `response_status` INT NULL DEFAULT NULL,
+  `response_body` TEXT NULL DEFAULT NULL,
+  `expires_at` TIMESTAMP NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`idempotency_key_id`),
+  UNIQUE INDEX `idx_idempotency_key_user` (`idempotency_key` ASC, `user_id` ASC) VISIBLE,
+  INDEX `idx_expires_at` (`expires_at` ASC) VISIBLE,
+  INDEX `fk_idempotency_key_user1_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_idempotency_key_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
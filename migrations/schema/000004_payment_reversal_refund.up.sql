@@ -0,0 +1,54 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `payment`
+  ADD COLUMN `excess_amount` DECIMAL(15,2) NOT NULL DEFAULT '0.00' AFTER `amount`,
+  ADD COLUMN `reversal_reason` TEXT NULL DEFAULT NULL AFTER `status`,
+  ADD COLUMN `reversed_by` VARCHAR(50) NULL DEFAULT NULL AFTER `reversal_reason`,
+  ADD COLUMN `reversed_at` TIMESTAMP NULL DEFAULT NULL AFTER `reversed_by`;
+
+ALTER TABLE `loan_application`
+  ADD COLUMN `excess_credit` DECIMAL(15,2) NOT NULL DEFAULT '0.00' AFTER `eligible_loan_amount`;
+
+
+-- -----------------------------------------------------
+-- Table `refund`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `refund` (
+  `refund_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `amount` DECIMAL(15,2) NOT NULL,
+  `status` VARCHAR(50) NOT NULL,
+  `reason` TEXT NULL DEFAULT NULL,
+  `processed_by` VARCHAR(50) NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`refund_id`),
+  INDEX `fk_refund_loan_application1_idx` (`application_id` ASC) VISIBLE,
+  INDEX `fk_refund_currency1_idx` (`currency_code` ASC) VISIBLE,
+  INDEX `fk_refund_user1_idx` (`processed_by` ASC) VISIBLE,
+  CONSTRAINT `fk_refund_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_refund_currency1`
+    FOREIGN KEY (`currency_code`)
+    REFERENCES `currency` (`currency_code`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_refund_user1`
+    FOREIGN KEY (`processed_by`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
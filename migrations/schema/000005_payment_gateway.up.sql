@@ -0,0 +1,16 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `payment`
+  ADD COLUMN `gateway` VARCHAR(50) NULL DEFAULT NULL AFTER `status`,
+  ADD COLUMN `gateway_reference` VARCHAR(255) NULL DEFAULT NULL AFTER `gateway`,
+  ADD COLUMN `failure_reason` TEXT NULL DEFAULT NULL AFTER `gateway_reference`,
+  ADD COLUMN `settled_at` TIMESTAMP NULL DEFAULT NULL AFTER `failure_reason`,
+  ADD UNIQUE INDEX `idx_gateway_reference` (`gateway_reference` ASC) VISIBLE;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,87 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `loan_application`
+  ADD COLUMN `virtual_account` VARCHAR(34) NULL DEFAULT NULL AFTER `excess_credit`,
+  ADD UNIQUE INDEX `idx_virtual_account` (`virtual_account` ASC) VISIBLE;
+
+
+-- -----------------------------------------------------
+-- Table `bank_statement`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `bank_statement` (
+  `statement_id` VARCHAR(50) NOT NULL,
+  `file_name` VARCHAR(255) NOT NULL,
+  `format` VARCHAR(20) NOT NULL,
+  `file_hash` VARCHAR(64) NOT NULL,
+  `total_lines` INT NOT NULL DEFAULT 0,
+  `posted_lines` INT NOT NULL DEFAULT 0,
+  `duplicate_lines` INT NOT NULL DEFAULT 0,
+  `ignored_lines` INT NOT NULL DEFAULT 0,
+  `exception_lines` INT NOT NULL DEFAULT 0,
+  `imported_by` VARCHAR(50) NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`statement_id`),
+  INDEX `idx_file_hash` (`file_hash` ASC) VISIBLE,
+  INDEX `fk_bank_statement_user1_idx` (`imported_by` ASC) VISIBLE,
+  CONSTRAINT `fk_bank_statement_user1`
+    FOREIGN KEY (`imported_by`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `bank_statement_line`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `bank_statement_line` (
+  `line_id` VARCHAR(50) NOT NULL,
+  `statement_id` VARCHAR(50) NOT NULL,
+  `line_number` INT NOT NULL,
+  `booking_date` DATE NULL DEFAULT NULL,
+  `amount` DECIMAL(15,2) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `credit_debit` VARCHAR(4) NOT NULL,
+  `bank_reference` VARCHAR(255) NULL DEFAULT NULL,
+  `payment_reference` VARCHAR(255) NULL DEFAULT NULL,
+  `virtual_account` VARCHAR(34) NULL DEFAULT NULL,
+  `counterparty` VARCHAR(255) NULL DEFAULT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `status` VARCHAR(50) NOT NULL,
+  `match_method` VARCHAR(50) NULL DEFAULT NULL,
+  `application_id` VARCHAR(50) NULL DEFAULT NULL,
+  `payment_id` VARCHAR(50) NULL DEFAULT NULL,
+  `exception_reason` TEXT NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`line_id`),
+  INDEX `fk_bank_statement_line_bank_statement1_idx` (`statement_id` ASC) VISIBLE,
+  INDEX `fk_bank_statement_line_loan_application1_idx` (`application_id` ASC) VISIBLE,
+  INDEX `fk_bank_statement_line_payment1_idx` (`payment_id` ASC) VISIBLE,
+  INDEX `idx_status` (`status` ASC) VISIBLE,
+  CONSTRAINT `fk_bank_statement_line_bank_statement1`
+    FOREIGN KEY (`statement_id`)
+    REFERENCES `bank_statement` (`statement_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_statement_line_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_statement_line_payment1`
+    FOREIGN KEY (`payment_id`)
+    REFERENCES `payment` (`payment_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,102 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+-- -----------------------------------------------------
+-- Table `debit_mandate`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `debit_mandate` (
+  `mandate_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `account_holder_name` VARCHAR(255) NOT NULL,
+  `bank_account_number` VARCHAR(34) NOT NULL,
+  `bank_code` VARCHAR(20) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `max_amount` DECIMAL(15,2) NOT NULL,
+  `frequency` VARCHAR(20) NOT NULL,
+  `start_date` DATE NOT NULL,
+  `end_date` DATE NOT NULL,
+  `status` VARCHAR(50) NOT NULL,
+  `provider` VARCHAR(50) NOT NULL,
+  `provider_reference` VARCHAR(255) NOT NULL,
+  `cancelled_by` VARCHAR(50) NULL DEFAULT NULL,
+  `cancelled_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`mandate_id`),
+  INDEX `fk_debit_mandate_loan_application1_idx` (`application_id` ASC) VISIBLE,
+  INDEX `fk_debit_mandate_user1_idx` (`user_id` ASC) VISIBLE,
+  INDEX `idx_status` (`status` ASC) VISIBLE,
+  CONSTRAINT `fk_debit_mandate_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_mandate_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_mandate_currency1`
+    FOREIGN KEY (`currency_code`)
+    REFERENCES `currency` (`currency_code`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `debit_collection`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `debit_collection` (
+  `collection_id` VARCHAR(50) NOT NULL,
+  `mandate_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `repayment_id` VARCHAR(50) NOT NULL,
+  `payment_id` VARCHAR(50) NULL DEFAULT NULL,
+  `attempt` INT NOT NULL,
+  `amount` DECIMAL(15,2) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `status` VARCHAR(50) NOT NULL,
+  `provider_reference` VARCHAR(255) NULL DEFAULT NULL,
+  `failure_code` VARCHAR(50) NULL DEFAULT NULL,
+  `failure_reason` TEXT NULL DEFAULT NULL,
+  `next_retry_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`collection_id`),
+  UNIQUE INDEX `idx_repayment_attempt` (`repayment_id` ASC, `attempt` ASC) VISIBLE,
+  UNIQUE INDEX `idx_provider_reference` (`provider_reference` ASC) VISIBLE,
+  INDEX `fk_debit_collection_debit_mandate1_idx` (`mandate_id` ASC) VISIBLE,
+  INDEX `fk_debit_collection_loan_application1_idx` (`application_id` ASC) VISIBLE,
+  INDEX `fk_debit_collection_payment1_idx` (`payment_id` ASC) VISIBLE,
+  INDEX `idx_next_retry_at` (`next_retry_at` ASC) VISIBLE,
+  CONSTRAINT `fk_debit_collection_debit_mandate1`
+    FOREIGN KEY (`mandate_id`)
+    REFERENCES `debit_mandate` (`mandate_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_collection_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_collection_repayment1`
+    FOREIGN KEY (`repayment_id`)
+    REFERENCES `repayment` (`repayment_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_collection_payment1`
+    FOREIGN KEY (`payment_id`)
+    REFERENCES `payment` (`payment_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
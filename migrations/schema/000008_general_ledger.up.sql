@@ -0,0 +1,123 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+-- -----------------------------------------------------
+-- Table `ledger_account`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `ledger_account` (
+  `account_code` VARCHAR(10) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `account_type` VARCHAR(20) NOT NULL,
+  `normal_balance` VARCHAR(6) NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`account_code`))
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `journal_entry`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `journal_entry` (
+  `entry_id` VARCHAR(50) NOT NULL,
+  `entry_type` VARCHAR(30) NOT NULL,
+  `source_reference` VARCHAR(120) NOT NULL,
+  `application_id` VARCHAR(50) NULL DEFAULT NULL,
+  `payment_id` VARCHAR(50) NULL DEFAULT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `effective_date` DATE NOT NULL,
+  `description` VARCHAR(255) NOT NULL,
+  `reverses_entry_id` VARCHAR(50) NULL DEFAULT NULL,
+  `created_by` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`entry_id`),
+  UNIQUE INDEX `idx_source_reference` (`source_reference` ASC) VISIBLE,
+  INDEX `idx_effective_date` (`effective_date` ASC) VISIBLE,
+  INDEX `fk_journal_entry_loan_application1_idx` (`application_id` ASC) VISIBLE,
+  INDEX `fk_journal_entry_payment1_idx` (`payment_id` ASC) VISIBLE,
+  INDEX `fk_journal_entry_currency1_idx` (`currency_code` ASC) VISIBLE,
+  INDEX `fk_journal_entry_journal_entry1_idx` (`reverses_entry_id` ASC) VISIBLE,
+  CONSTRAINT `fk_journal_entry_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_journal_entry_payment1`
+    FOREIGN KEY (`payment_id`)
+    REFERENCES `payment` (`payment_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_journal_entry_currency1`
+    FOREIGN KEY (`currency_code`)
+    REFERENCES `currency` (`currency_code`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_journal_entry_journal_entry1`
+    FOREIGN KEY (`reverses_entry_id`)
+    REFERENCES `journal_entry` (`entry_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `journal_line`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `journal_line` (
+  `line_id` VARCHAR(50) NOT NULL,
+  `entry_id` VARCHAR(50) NOT NULL,
+  `account_code` VARCHAR(10) NOT NULL,
+  `application_id` VARCHAR(50) NULL DEFAULT NULL,
+  `debit` DECIMAL(15,2) NOT NULL DEFAULT '0.00',
+  `credit` DECIMAL(15,2) NOT NULL DEFAULT '0.00',
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`line_id`),
+  INDEX `fk_journal_line_journal_entry1_idx` (`entry_id` ASC) VISIBLE,
+  INDEX `idx_account_application` (`account_code` ASC, `application_id` ASC) VISIBLE,
+  CONSTRAINT `fk_journal_line_journal_entry1`
+    FOREIGN KEY (`entry_id`)
+    REFERENCES `journal_entry` (`entry_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_journal_line_ledger_account1`
+    FOREIGN KEY (`account_code`)
+    REFERENCES `ledger_account` (`account_code`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- Postings are immutable, a mistake is corrected by posting a reversing entry
+CREATE TRIGGER `trg_journal_entry_no_update` BEFORE UPDATE ON `journal_entry` FOR EACH ROW
+  SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal entries are immutable';
+
+CREATE TRIGGER `trg_journal_entry_no_delete` BEFORE DELETE ON `journal_entry` FOR EACH ROW
+  SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal entries are immutable';
+
+CREATE TRIGGER `trg_journal_line_no_update` BEFORE UPDATE ON `journal_line` FOR EACH ROW
+  SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal lines are immutable';
+
+CREATE TRIGGER `trg_journal_line_no_delete` BEFORE DELETE ON `journal_line` FOR EACH ROW
+  SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal lines are immutable';
+
+
+-- -----------------------------------------------------
+-- Chart of accounts
+-- -----------------------------------------------------
+INSERT INTO `ledger_account` (`account_code`, `name`, `account_type`, `normal_balance`) VALUES
+  ('1000', 'Cash and bank', 'ASSET', 'DEBIT'),
+  ('1100', 'Loans receivable - principal', 'ASSET', 'DEBIT'),
+  ('1110', 'Interest receivable', 'ASSET', 'DEBIT'),
+  ('1120', 'Fees receivable', 'ASSET', 'DEBIT'),
+  ('2100', 'Customer excess credit', 'LIABILITY', 'CREDIT'),
+  ('4000', 'Interest income', 'INCOME', 'CREDIT'),
+  ('4100', 'Fee income', 'INCOME', 'CREDIT'),
+  ('5000', 'Loan write-off expense', 'EXPENSE', 'DEBIT');
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,41 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `loan_application`
+  ADD COLUMN `accrual_status` VARCHAR(20) NOT NULL DEFAULT 'ACCRUING' AFTER `virtual_account`,
+  ADD COLUMN `accrual_status_date` DATE NULL DEFAULT NULL AFTER `accrual_status`;
+
+
+-- -----------------------------------------------------
+-- Table `interest_accrual`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `interest_accrual` (
+  `accrual_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `accrual_date` DATE NOT NULL,
+  `principal_balance` DECIMAL(15,2) NOT NULL,
+  `interest_rate` DECIMAL(5,2) NOT NULL,
+  `amount` DECIMAL(15,2) NOT NULL,
+  `entry_id` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`accrual_id`),
+  UNIQUE INDEX `idx_application_accrual_date` (`application_id` ASC, `accrual_date` ASC) VISIBLE,
+  INDEX `fk_interest_accrual_journal_entry1_idx` (`entry_id` ASC) VISIBLE,
+  CONSTRAINT `fk_interest_accrual_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_interest_accrual_journal_entry1`
+    FOREIGN KEY (`entry_id`)
+    REFERENCES `journal_entry` (`entry_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,63 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+-- -----------------------------------------------------
+-- Table `write_off_authority`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `write_off_authority` (
+  `user_id` VARCHAR(50) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `max_amount` DECIMAL(15,2) NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`, `currency_code`),
+  CONSTRAINT `fk_write_off_authority_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `loan_write_off`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `loan_write_off` (
+  `write_off_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `outstanding_amount` DECIMAL(15,2) NOT NULL,
+  `principal_amount` DECIMAL(15,2) NOT NULL DEFAULT 0,
+  `interest_amount` DECIMAL(15,2) NOT NULL DEFAULT 0,
+  `fee_amount` DECIMAL(15,2) NOT NULL DEFAULT 0,
+  `reason` VARCHAR(255) NOT NULL,
+  `written_off_by` VARCHAR(50) NOT NULL,
+  `written_off_at` DATETIME NOT NULL,
+  `entry_id` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`write_off_id`),
+  UNIQUE INDEX `idx_write_off_application` (`application_id` ASC) VISIBLE,
+  INDEX `idx_written_off_at` (`written_off_at` ASC) VISIBLE,
+  INDEX `fk_loan_write_off_user1_idx` (`written_off_by` ASC) VISIBLE,
+  CONSTRAINT `fk_loan_write_off_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_loan_write_off_user1`
+    FOREIGN KEY (`written_off_by`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `ledger_account` (`account_code`, `name`, `account_type`, `normal_balance`) VALUES
+  ('4200', 'Recovery income', 'INCOME', 'CREDIT');
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,20 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+DROP TABLE IF EXISTS `loan_provision`;
+DROP TABLE IF EXISTS `provision_run`;
+DROP TABLE IF EXISTS `provision_parameter`;
+
+DELETE FROM `ledger_account` WHERE `account_code` IN ('1190', '5100');
+
+ALTER TABLE `loan_application`
+  DROP COLUMN `restructured_date`,
+  DROP COLUMN `restructured`,
+  DROP COLUMN `product_code`;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
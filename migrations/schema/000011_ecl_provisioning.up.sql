@@ -0,0 +1,102 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `loan_application`
+  ADD COLUMN `product_code` VARCHAR(30) NOT NULL DEFAULT 'TERM_LOAN' AFTER `country_code`,
+  ADD COLUMN `restructured` TINYINT(1) NOT NULL DEFAULT 0 AFTER `accrual_status_date`,
+  ADD COLUMN `restructured_date` DATE NULL DEFAULT NULL AFTER `restructured`;
+
+
+-- -----------------------------------------------------
+-- Table `provision_parameter`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `provision_parameter` (
+  `country_code` VARCHAR(3) NOT NULL,
+  `product_code` VARCHAR(30) NOT NULL,
+  `stage` TINYINT NOT NULL,
+  `probability_of_default` DECIMAL(7,6) NOT NULL,
+  `loss_given_default` DECIMAL(7,6) NOT NULL,
+  `updated_by` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`country_code`, `product_code`, `stage`),
+  CONSTRAINT `fk_provision_parameter_country1`
+    FOREIGN KEY (`country_code`)
+    REFERENCES `country` (`country_code`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `provision_run`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `provision_run` (
+  `run_id` VARCHAR(50) NOT NULL,
+  `as_of_date` DATE NOT NULL,
+  `loan_count` INT NOT NULL DEFAULT 0,
+  `run_by` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`run_id`),
+  INDEX `idx_provision_run_as_of_date` (`as_of_date` ASC) VISIBLE)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `loan_provision`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `loan_provision` (
+  `provision_id` VARCHAR(50) NOT NULL,
+  `run_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `stage` TINYINT NOT NULL,
+  `days_past_due` INT NOT NULL DEFAULT 0,
+  `restructured` TINYINT(1) NOT NULL DEFAULT 0,
+  `exposure` DECIMAL(15,2) NOT NULL,
+  `probability_of_default` DECIMAL(7,6) NOT NULL,
+  `loss_given_default` DECIMAL(7,6) NOT NULL,
+  `ecl_amount` DECIMAL(15,2) NOT NULL,
+  `previous_amount` DECIMAL(15,2) NOT NULL DEFAULT 0,
+  `movement` DECIMAL(15,2) NOT NULL DEFAULT 0,
+  `entry_id` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`provision_id`),
+  UNIQUE INDEX `idx_run_application` (`run_id` ASC, `application_id` ASC) VISIBLE,
+  INDEX `fk_loan_provision_loan_application1_idx` (`application_id` ASC) VISIBLE,
+  INDEX `fk_loan_provision_journal_entry1_idx` (`entry_id` ASC) VISIBLE,
+  CONSTRAINT `fk_loan_provision_provision_run1`
+    FOREIGN KEY (`run_id`)
+    REFERENCES `provision_run` (`run_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_loan_provision_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_loan_provision_journal_entry1`
+    FOREIGN KEY (`entry_id`)
+    REFERENCES `journal_entry` (`entry_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `ledger_account` (`account_code`, `name`, `account_type`, `normal_balance`) VALUES
+  ('1190', 'Loan loss allowance', 'ASSET', 'CREDIT'),
+  ('5100', 'Loan loss provision expense', 'EXPENSE', 'DEBIT');
+
+-- Starting PD/LGD for the term loan of every country, finance replaces them with calibrated values
+INSERT INTO `provision_parameter` (`country_code`, `product_code`, `stage`, `probability_of_default`, `loss_given_default`)
+SELECT `country_code`, 'TERM_LOAN', 1, 0.010000, 0.450000 FROM `country`
+UNION ALL
+SELECT `country_code`, 'TERM_LOAN', 2, 0.200000, 0.450000 FROM `country`
+UNION ALL
+SELECT `country_code`, 'TERM_LOAN', 3, 1.000000, 0.450000 FROM `country`;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,17 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+-- bcrypt hashes cannot be turned back into SHA-512 digests, users who signed in since the upgrade need a password reset
+ALTER TABLE `user`
+  DROP INDEX `user_password_setup_token_UNIQUE`,
+  DROP COLUMN `password_updated_at`,
+  DROP COLUMN `password_setup_expires`,
+  DROP COLUMN `password_setup_token`,
+  DROP COLUMN `password_change_required`;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
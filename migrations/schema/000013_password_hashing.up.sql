@@ -0,0 +1,20 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `user`
+  ADD COLUMN `password_change_required` TINYINT(1) NOT NULL DEFAULT 0 AFTER `mobile_number`,
+  ADD COLUMN `password_setup_token` VARCHAR(64) NULL DEFAULT NULL AFTER `password_change_required`,
+  ADD COLUMN `password_setup_expires` DATETIME NULL DEFAULT NULL AFTER `password_setup_token`,
+  ADD COLUMN `password_updated_at` DATETIME NULL DEFAULT NULL AFTER `password_setup_expires`,
+  ADD UNIQUE INDEX `user_password_setup_token_UNIQUE` (`password_setup_token` ASC);
+
+-- Accounts still on the former default password 12345678 have to choose their own before signing in
+UPDATE `user` SET `password_change_required` = 1
+WHERE `user_password` = 'fa585d89c851dd338a70dcf535aa2a92fee7836dd6aff1226583e88e0996293f16bc009c652826e0fc5c706695a03cddce372f139eff4d13959da6f1f5d3eabe';
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
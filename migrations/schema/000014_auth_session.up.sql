@@ -0,0 +1,54 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `auth_session`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `auth_session` (
+  `session_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `revoked_reason` VARCHAR(20) NULL DEFAULT NULL,
+  `revoked_at` TIMESTAMP NULL DEFAULT NULL,
+  `last_refreshed_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`session_id`),
+  INDEX `idx_auth_session_user_status` (`user_id` ASC, `status` ASC) VISIBLE,
+  CONSTRAINT `fk_auth_session_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `refresh_token`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `refresh_token` (
+  `token_id` VARCHAR(50) NOT NULL,
+  `session_id` VARCHAR(50) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `expires_at` TIMESTAMP NOT NULL,
+  `used_at` TIMESTAMP NULL DEFAULT NULL,
+  `replaced_by` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`token_id`),
+  INDEX `fk_refresh_token_auth_session1_idx` (`session_id` ASC) VISIBLE,
+  CONSTRAINT `fk_refresh_token_auth_session1`
+    FOREIGN KEY (`session_id`)
+    REFERENCES `auth_session` (`session_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,56 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `user_registration`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `user_registration` (
+  `registration_id` VARCHAR(50) NOT NULL,
+  `user_name` VARCHAR(255) NOT NULL,
+  `user_email` VARCHAR(255) NOT NULL,
+  `mobile_number` VARCHAR(20) NOT NULL,
+  `channel` VARCHAR(10) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `user_id` VARCHAR(50) NULL DEFAULT NULL,
+  `verified_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`registration_id`),
+  INDEX `idx_user_registration_email` (`user_email` ASC) VISIBLE,
+  INDEX `idx_user_registration_mobile` (`mobile_number` ASC) VISIBLE,
+  INDEX `fk_user_registration_user1_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_user_registration_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `one_time_password`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `one_time_password` (
+  `otp_id` VARCHAR(50) NOT NULL,
+  `purpose` VARCHAR(30) NOT NULL,
+  `reference_id` VARCHAR(50) NOT NULL,
+  `channel` VARCHAR(10) NOT NULL,
+  `recipient` VARCHAR(255) NOT NULL,
+  `code_hash` VARCHAR(64) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `expires_at` TIMESTAMP NOT NULL,
+  `consumed_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`otp_id`),
+  INDEX `idx_one_time_password_reference` (`purpose` ASC, `reference_id` ASC, `created_at` ASC) VISIBLE)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,33 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `password_reset_event`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `password_reset_event` (
+  `event_id` VARCHAR(50) NOT NULL,
+  `identifier` VARCHAR(255) NOT NULL,
+  `user_id` VARCHAR(50) NULL DEFAULT NULL,
+  `event` VARCHAR(20) NOT NULL,
+  `channel` VARCHAR(10) NULL DEFAULT NULL,
+  `ip_address` VARCHAR(45) NULL DEFAULT NULL,
+  `detail` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`event_id`),
+  INDEX `idx_password_reset_event_identifier` (`identifier` ASC, `event` ASC, `created_at` ASC) VISIBLE,
+  INDEX `fk_password_reset_event_user1_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_password_reset_event_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
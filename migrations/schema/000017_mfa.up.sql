@@ -0,0 +1,74 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `user_mfa`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `user_mfa` (
+  `user_id` VARCHAR(50) NOT NULL,
+  `method` VARCHAR(10) NOT NULL DEFAULT 'TOTP',
+  `secret` VARCHAR(255) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `last_used_step` BIGINT NOT NULL DEFAULT 0,
+  `enabled_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`),
+  CONSTRAINT `fk_user_mfa_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mfa_backup_code`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mfa_backup_code` (
+  `backup_code_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `code_hash` VARCHAR(64) NOT NULL,
+  `used_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`backup_code_id`),
+  UNIQUE INDEX `idx_mfa_backup_code_hash` (`code_hash` ASC) VISIBLE,
+  INDEX `fk_mfa_backup_code_user1_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_mfa_backup_code_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mfa_challenge`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mfa_challenge` (
+  `challenge_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `token_hash` VARCHAR(64) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `expires_at` TIMESTAMP NOT NULL,
+  `consumed_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`challenge_id`),
+  UNIQUE INDEX `idx_mfa_challenge_token` (`token_hash` ASC) VISIBLE,
+  INDEX `fk_mfa_challenge_user1_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_mfa_challenge_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,28 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `login_throttle`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `login_throttle` (
+  `scope` VARCHAR(20) NOT NULL,
+  `subject` VARCHAR(255) NOT NULL,
+  `failed_count` INT NOT NULL DEFAULT 0,
+  `last_failed_at` TIMESTAMP NULL DEFAULT NULL,
+  `next_attempt_at` TIMESTAMP NULL DEFAULT NULL,
+  `locked_until` TIMESTAMP NULL DEFAULT NULL,
+  `lockout_count` INT NOT NULL DEFAULT 0,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`scope`, `subject`),
+  INDEX `idx_login_throttle_locked_until` (`locked_until` ASC) VISIBLE)
+ENGINE = InnoDB;
+
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,132 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `role`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `role` (
+  `role_code` VARCHAR(30) NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `description` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`role_code`))
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `permission`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `permission` (
+  `permission_code` VARCHAR(50) NOT NULL,
+  `description` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`permission_code`))
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `role_permission`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `role_permission` (
+  `role_code` VARCHAR(30) NOT NULL,
+  `permission_code` VARCHAR(50) NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`role_code`, `permission_code`),
+  INDEX `fk_role_permission_permission1_idx` (`permission_code` ASC) VISIBLE,
+  CONSTRAINT `fk_role_permission_role1`
+    FOREIGN KEY (`role_code`)
+    REFERENCES `role` (`role_code`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_role_permission_permission1`
+    FOREIGN KEY (`permission_code`)
+    REFERENCES `permission` (`permission_code`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `user_role`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `user_role` (
+  `user_id` VARCHAR(50) NOT NULL,
+  `role_code` VARCHAR(30) NOT NULL,
+  `assigned_by` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`, `role_code`),
+  INDEX `fk_user_role_role1_idx` (`role_code` ASC) VISIBLE,
+  CONSTRAINT `fk_user_role_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_user_role_role1`
+    FOREIGN KEY (`role_code`)
+    REFERENCES `role` (`role_code`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `role` (`role_code`, `name`, `description`) VALUES
+  ('SUPER_ADMIN', 'Super admin', 'Every permission'),
+  ('UNDERWRITER', 'Underwriter', 'Assesses and approves the applications allocated to them'),
+  ('APPROVER', 'Approver', 'Approves applications, including amounts above the eligible amount'),
+  ('COLLECTIONS_AGENT', 'Collections agent', 'Follows up repayments and runs debit collections'),
+  ('FINANCE', 'Finance', 'Handles payments, refunds, closures, write-offs, reconciliation and provisioning'),
+  ('AUDITOR', 'Auditor', 'Reads every application and report without changing anything');
+
+INSERT INTO `permission` (`permission_code`, `description`) VALUES
+  ('APPLICATION_READ_ALL', 'Read any loan application, not only the ones taken part in'),
+  ('APPLICATION_APPROVE', 'Approve loan applications'),
+  ('APPLICATION_OVERRIDE', 'Approve more than the eligible amount of an application'),
+  ('PAYMENT_SYNC', 'Poll the gateway for the status of a payment'),
+  ('PAYMENT_REVERSE', 'Reverse payments'),
+  ('PAYMENT_REFUND', 'Refund excess credit'),
+  ('LOAN_CLOSE', 'Close repaid loans'),
+  ('LOAN_WRITE_OFF', 'Write loans off'),
+  ('LOAN_RESTRUCTURE', 'Flag loans as restructured'),
+  ('COLLECTIONS_RUN', 'Run the debit collections'),
+  ('RECONCILIATION_MANAGE', 'Import bank statements and review their exceptions'),
+  ('PROVISION_MANAGE', 'Run provisioning and set the PD/LGD parameters'),
+  ('LEDGER_MANAGE', 'Run the interest accruals'),
+  ('REPORT_VIEW', 'Read the ledger, write-off, provisioning and reconciliation reports'),
+  ('LOGIN_UNLOCK', 'Unlock sign ins locked out after failures');
+
+INSERT INTO `role_permission` (`role_code`, `permission_code`)
+SELECT 'SUPER_ADMIN', `permission_code` FROM `permission`;
+
+INSERT INTO `role_permission` (`role_code`, `permission_code`) VALUES
+  ('UNDERWRITER', 'APPLICATION_APPROVE'),
+  ('UNDERWRITER', 'LOAN_RESTRUCTURE'),
+  ('APPROVER', 'APPLICATION_APPROVE'),
+  ('APPROVER', 'APPLICATION_OVERRIDE'),
+  ('COLLECTIONS_AGENT', 'PAYMENT_SYNC'),
+  ('COLLECTIONS_AGENT', 'LOAN_RESTRUCTURE'),
+  ('COLLECTIONS_AGENT', 'COLLECTIONS_RUN'),
+  ('FINANCE', 'PAYMENT_SYNC'),
+  ('FINANCE', 'PAYMENT_REVERSE'),
+  ('FINANCE', 'PAYMENT_REFUND'),
+  ('FINANCE', 'LOAN_CLOSE'),
+  ('FINANCE', 'LOAN_WRITE_OFF'),
+  ('FINANCE', 'COLLECTIONS_RUN'),
+  ('FINANCE', 'RECONCILIATION_MANAGE'),
+  ('FINANCE', 'PROVISION_MANAGE'),
+  ('FINANCE', 'LEDGER_MANAGE'),
+  ('FINANCE', 'REPORT_VIEW'),
+  ('AUDITOR', 'APPLICATION_READ_ALL'),
+  ('AUDITOR', 'REPORT_VIEW');
+
+-- Employees could do everything before roles existed, they keep doing so until their roles are narrowed
+INSERT INTO `user_role` (`user_id`, `role_code`)
+SELECT `user_id`, 'SUPER_ADMIN' FROM `user` WHERE `user_type` = 'EMPLOYEE';
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
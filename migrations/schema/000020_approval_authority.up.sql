@@ -0,0 +1,70 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `approval_authority`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `approval_authority` (
+  `authority_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NULL DEFAULT NULL,
+  `role_code` VARCHAR(30) NULL DEFAULT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `max_amount` DECIMAL(15,2) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`authority_id`),
+  UNIQUE INDEX `idx_approval_authority_user` (`user_id` ASC, `currency_code` ASC) VISIBLE,
+  UNIQUE INDEX `idx_approval_authority_role` (`role_code` ASC, `currency_code` ASC) VISIBLE,
+  CONSTRAINT `fk_approval_authority_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_approval_authority_role1`
+    FOREIGN KEY (`role_code`)
+    REFERENCES `role` (`role_code`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `loan_approval`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `loan_approval` (
+  `approval_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `step` INT NOT NULL,
+  `approver_id` VARCHAR(50) NOT NULL,
+  `approved_amount` DECIMAL(15,2) NOT NULL,
+  `override` TINYINT NOT NULL DEFAULT 0,
+  `decision` VARCHAR(20) NOT NULL,
+  `escalated_to` VARCHAR(50) NULL DEFAULT NULL,
+  `reason` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`approval_id`),
+  UNIQUE INDEX `idx_loan_approval_step` (`application_id` ASC, `step` ASC) VISIBLE,
+  INDEX `fk_loan_approval_user1_idx` (`approver_id` ASC) VISIBLE,
+  CONSTRAINT `fk_loan_approval_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_loan_approval_user1`
+    FOREIGN KEY (`approver_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- Super admins approve any amount, the other roles and employees get their limits configured per currency
+INSERT INTO `approval_authority` (`authority_id`, `role_code`, `currency_code`, `max_amount`)
+SELECT UUID(), 'SUPER_ADMIN', `currency_code`, NULL FROM `currency`;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
@@ -0,0 +1,44 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `pending_action`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `pending_action` (
+  `action_id` VARCHAR(50) NOT NULL,
+  `action_type` VARCHAR(50) NOT NULL,
+  `resource_id` VARCHAR(50) NOT NULL,
+  `payload` TEXT NOT NULL,
+  `permission_code` VARCHAR(50) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `maker_id` VARCHAR(50) NOT NULL,
+  `checker_id` VARCHAR(50) NULL DEFAULT NULL,
+  `decision_reason` VARCHAR(255) NULL DEFAULT NULL,
+  `result` VARCHAR(255) NULL DEFAULT NULL,
+  `decided_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`action_id`),
+  INDEX `idx_pending_action_status` (`status` ASC, `action_type` ASC) VISIBLE,
+  INDEX `idx_pending_action_resource` (`action_type` ASC, `resource_id` ASC) VISIBLE,
+  INDEX `fk_pending_action_user1_idx` (`maker_id` ASC) VISIBLE,
+  CONSTRAINT `fk_pending_action_user1`
+    FOREIGN KEY (`maker_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `permission` (`permission_code`, `description`) VALUES
+  ('CONFIG_MANAGE', 'Propose and check changes to the loan eligibility configuration');
+
+INSERT INTO `role_permission` (`role_code`, `permission_code`) VALUES
+  ('SUPER_ADMIN', 'CONFIG_MANAGE');
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
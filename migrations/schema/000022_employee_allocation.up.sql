@@ -0,0 +1,68 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `employee_availability`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `employee_availability` (
+  `user_id` VARCHAR(50) NOT NULL,
+  `available` TINYINT(1) NOT NULL DEFAULT 1,
+  `reason` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`),
+  CONSTRAINT `fk_employee_availability_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `employee_skill`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `employee_skill` (
+  `skill_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `country_code` VARCHAR(3) NULL DEFAULT NULL,
+  `product_code` VARCHAR(30) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`skill_id`),
+  INDEX `fk_employee_skill_user1_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_employee_skill_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `allocation_tier`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `allocation_tier` (
+  `tier_id` VARCHAR(50) NOT NULL,
+  `currency_code` VARCHAR(3) NOT NULL,
+  `min_amount` DECIMAL(15,2) NOT NULL DEFAULT 0,
+  `max_amount` DECIMAL(15,2) NULL DEFAULT NULL,
+  `role_code` VARCHAR(30) NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`tier_id`),
+  INDEX `idx_allocation_tier_currency` (`currency_code` ASC, `min_amount` ASC) VISIBLE,
+  CONSTRAINT `fk_allocation_tier_role1`
+    FOREIGN KEY (`role_code`)
+    REFERENCES `role` (`role_code`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
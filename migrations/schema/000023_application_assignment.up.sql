@@ -0,0 +1,88 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+
+-- -----------------------------------------------------
+-- Table `application_assignment`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `application_assignment` (
+  `assignment_id` VARCHAR(50) NOT NULL,
+  `application_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `assigned_by` VARCHAR(50) NULL DEFAULT NULL,
+  `reason` VARCHAR(255) NULL DEFAULT NULL,
+  `started_at` TIMESTAMP NOT NULL,
+  `ended_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`assignment_id`),
+  INDEX `idx_application_assignment_application` (`application_id` ASC, `started_at` ASC) VISIBLE,
+  INDEX `idx_application_assignment_user` (`user_id` ASC, `ended_at` ASC) VISIBLE,
+  CONSTRAINT `fk_application_assignment_loan_application1`
+    FOREIGN KEY (`application_id`)
+    REFERENCES `loan_application` (`application_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_application_assignment_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `employee_delegation`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `employee_delegation` (
+  `delegation_id` VARCHAR(50) NOT NULL,
+  `user_id` VARCHAR(50) NOT NULL,
+  `delegate_id` VARCHAR(50) NOT NULL,
+  `start_date` DATE NOT NULL,
+  `end_date` DATE NOT NULL,
+  `reason` VARCHAR(255) NULL DEFAULT NULL,
+  `created_by` VARCHAR(50) NOT NULL,
+  `revoked_at` TIMESTAMP NULL DEFAULT NULL,
+  `revoked_by` VARCHAR(50) NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`delegation_id`),
+  INDEX `idx_employee_delegation_user` (`user_id` ASC, `start_date` ASC, `end_date` ASC) VISIBLE,
+  INDEX `idx_employee_delegation_delegate` (`delegate_id` ASC, `start_date` ASC, `end_date` ASC) VISIBLE,
+  CONSTRAINT `fk_employee_delegation_user1`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_employee_delegation_user2`
+    FOREIGN KEY (`delegate_id`)
+    REFERENCES `user` (`user_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `loan_approval`
+  ADD COLUMN `on_behalf_of` VARCHAR(50) NULL DEFAULT NULL AFTER `approver_id`;
+
+-- Every employee taking part in an application holds it from the time they were added until they routed it to a
+-- next-level approver
+INSERT INTO `application_assignment` (`assignment_id`, `application_id`, `user_id`, `reason`, `started_at`, `ended_at`)
+SELECT UUID(), `participant`.`application_id`, `participant`.`user_id`, 'allocated',
+  COALESCE(`participant`.`created_at`, CURRENT_TIMESTAMP),
+  (SELECT MIN(`approval`.`created_at`) FROM `loan_approval` `approval`
+    WHERE `approval`.`application_id` = `participant`.`application_id`
+      AND `approval`.`approver_id` = `participant`.`user_id` AND `approval`.`decision` = 'ESCALATED')
+FROM `loan_application_participant` `participant` WHERE `participant`.`participant_type` = 'EMPLOYEE';
+
+INSERT INTO `permission` (`permission_code`, `description`) VALUES
+  ('APPLICATION_REASSIGN', 'Reassign applications and set the out-of-office delegation of other employees');
+
+INSERT INTO `role_permission` (`role_code`, `permission_code`) VALUES
+  ('SUPER_ADMIN', 'APPLICATION_REASSIGN');
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
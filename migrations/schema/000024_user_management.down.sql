@@ -0,0 +1,19 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+DELETE FROM `role_permission` WHERE `permission_code` = 'USER_MANAGE';
+DELETE FROM `permission` WHERE `permission_code` = 'USER_MANAGE';
+
+ALTER TABLE `user`
+  DROP INDEX `idx_user_type_status`,
+  DROP COLUMN `deactivation_reason`,
+  DROP COLUMN `deactivated_by`,
+  DROP COLUMN `deactivated_at`,
+  DROP COLUMN `status`;
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
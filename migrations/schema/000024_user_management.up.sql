@@ -0,0 +1,22 @@
+-- MySQL Workbench Forward Engineering
+
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE `user`
+  ADD COLUMN `status` VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' AFTER `user_type`,
+  ADD COLUMN `deactivated_at` DATETIME NULL DEFAULT NULL AFTER `password_updated_at`,
+  ADD COLUMN `deactivated_by` VARCHAR(50) NULL DEFAULT NULL AFTER `deactivated_at`,
+  ADD COLUMN `deactivation_reason` VARCHAR(255) NULL DEFAULT NULL AFTER `deactivated_by`,
+  ADD INDEX `idx_user_type_status` (`user_type` ASC, `status` ASC);
+
+INSERT INTO `permission` (`permission_code`, `description`) VALUES
+  ('USER_MANAGE', 'Create, update, deactivate and reactivate users, reset their passwords and assign roles');
+
+INSERT INTO `role_permission` (`role_code`, `permission_code`) VALUES
+  ('SUPER_ADMIN', 'USER_MANAGE');
+
+SET SQL_MODE=@OLD_SQL_MODE;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;